@@ -0,0 +1,34 @@
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// API provides a key-value cache with expiration.
+//
+// This interface is implemented by the mikros framework and is available to
+// services that enable the "cache" feature. The backend where entries are
+// kept is selected in the feature settings, so switching it does not require
+// changing the service code.
+type API interface {
+	// Get returns the value of key, and false if it is not cached or has
+	// expired.
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+
+	// Set caches value for key during ttl. A zero ttl uses the default TTL
+	// of the feature settings, when set, or keeps the entry until deleted.
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+
+	// Delete removes key from the cache.
+	Delete(ctx context.Context, key string) error
+}
+
+// Backend is the storage where cache entries are kept. Implementations must
+// be safe for concurrent use. The ttl they receive is never zero when the
+// entry must expire; zero means no expiration.
+type Backend interface {
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, key string) error
+}
@@ -0,0 +1,34 @@
+package cache
+
+import (
+	"sync"
+)
+
+// BackendFactory creates a Backend using the settings found in the
+// '[features.cache.options]' section of the 'service.toml' file.
+type BackendFactory func(options map[string]interface{}) (Backend, error)
+
+var (
+	backendsMu sync.RWMutex
+	backends   = make(map[string]BackendFactory)
+)
+
+// RegisterBackend makes a cache backend available to be selected by name in
+// the feature settings. It is the hook used by plugins providing backends
+// that the framework does not ship, like redis, and is usually called from
+// their init function. Registering a name twice replaces the first factory.
+func RegisterBackend(name string, factory BackendFactory) {
+	backendsMu.Lock()
+	defer backendsMu.Unlock()
+
+	backends[name] = factory
+}
+
+// LookupBackend returns the factory registered with name.
+func LookupBackend(name string) (BackendFactory, bool) {
+	backendsMu.RLock()
+	defer backendsMu.RUnlock()
+
+	factory, ok := backends[name]
+	return factory, ok
+}
@@ -0,0 +1,32 @@
+package clock
+
+import (
+	"time"
+
+	"github.com/mikros-dev/mikros/components/clock"
+)
+
+// API is the time source used by the framework timers, such as cache TTLs,
+// lock leases and token expirations.
+//
+// This interface is implemented by the mikros framework and is always
+// available to services. Services using it instead of the time package let
+// their tests move time forward with ServiceTesting.Clock, along with the
+// framework timers.
+type API interface {
+	// Now returns the current time.
+	Now() time.Time
+
+	// Since returns the time elapsed since t.
+	Since(t time.Time) time.Duration
+
+	// After waits for d to elapse and then sends the current time on the
+	// returned channel.
+	After(d time.Duration) <-chan time.Time
+
+	// NewTimer creates a timer that fires once d elapses.
+	NewTimer(d time.Duration) clock.Timer
+
+	// NewTicker creates a ticker that ticks every d.
+	NewTicker(d time.Duration) clock.Ticker
+}
@@ -0,0 +1,62 @@
+package lock
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	// ErrNotHeld is returned when refreshing or releasing a lock that is not
+	// held anymore by its owner.
+	ErrNotHeld = errors.New("lock is not held")
+)
+
+// API provides distributed locks and leader-only task execution.
+//
+// This interface is implemented by the mikros framework and is available to
+// services that enable the "lock" feature. Locks are leases with a TTL, so
+// a lock held by a replica that crashed is released once its TTL expires.
+type API interface {
+	// TryLock tries to acquire the lock name for ttl without waiting. When
+	// the lock is held by someone else, it returns false.
+	TryLock(ctx context.Context, name string, ttl time.Duration) (Lock, bool, error)
+
+	// RunIfLeader executes fn only while this replica is the leader of name,
+	// i.e., holds its lock. Replicas that are not the leader stand by, trying
+	// to acquire the lock periodically, and take over when the leader loses
+	// it. The leader keeps its lock refreshed and, if it fails to do so, the
+	// context given to fn is canceled.
+	//
+	// fn is expected to run until its context is done. When it returns, the
+	// lock is released and RunIfLeader returns its error. RunIfLeader also
+	// returns when ctx is done.
+	RunIfLeader(ctx context.Context, name string, fn func(ctx context.Context) error) error
+
+	// IsLeader returns true if this replica currently is the leader of name
+	// in a RunIfLeader call.
+	IsLeader(name string) bool
+}
+
+// Lock is an acquired lock.
+type Lock interface {
+	// Refresh extends the lock lease by its TTL.
+	Refresh(ctx context.Context) error
+
+	// Release releases the lock.
+	Release(ctx context.Context) error
+}
+
+// Backend is the storage where locks are kept. Implementations must be safe
+// for concurrent use and share the locks among all replicas of the service,
+// except for the in-memory one.
+type Backend interface {
+	// Acquire acquires the lock name for owner during ttl. It must succeed,
+	// extending the lease, if the lock is already held by owner, and return
+	// false if it is held by someone else.
+	Acquire(ctx context.Context, name, owner string, ttl time.Duration) (bool, error)
+
+	// Release releases the lock name if it is held by owner, returning
+	// ErrNotHeld otherwise.
+	Release(ctx context.Context, name, owner string) error
+}
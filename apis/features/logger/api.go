@@ -37,6 +37,15 @@ type API interface {
 	Level() string
 }
 
+// RequestLogs is optionally implemented by API implementations that keep the
+// messages of every request suppressed by the current log level, so runtimes
+// can output them when a request fails.
+type RequestLogs interface {
+	// EndRequest finishes the request being handled with ctx. Its suppressed
+	// messages are output when elevate is true and discarded otherwise.
+	EndRequest(ctx context.Context, elevate bool)
+}
+
 // Attribute represents a key-value pair attached to log messages.
 //
 // Attributes provide additional context to log entries and are used
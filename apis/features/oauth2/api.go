@@ -0,0 +1,38 @@
+package oauth2
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// API provides OAuth2 access tokens, obtained with the client credentials
+// grant, for outbound calls.
+//
+// This interface is implemented by the mikros framework and is available to
+// services that enable the "oauth2" feature. Tokens are cached per audience
+// and refreshed before they expire. Coupled gRPC clients configured in the
+// feature definitions receive them automatically.
+type API interface {
+	// Token returns a valid access token for the audience.
+	Token(ctx context.Context, audience string) (string, error)
+
+	// Transport wraps base, or http.DefaultTransport if nil, with a round
+	// tripper that adds an access token for the audience into the
+	// Authorization header of every request.
+	Transport(audience string, base http.RoundTripper) http.RoundTripper
+
+	// HTTPClient returns an http.Client using Transport.
+	HTTPClient(audience string) *http.Client
+
+	// Stats returns counters of the token requests made so far.
+	Stats() Stats
+}
+
+// Stats gathers counters of the token requests made by the feature.
+type Stats struct {
+	Fetches     uint64
+	Failures    uint64
+	LastFailure time.Time
+	LastError   string
+}
@@ -0,0 +1,22 @@
+package watchdog
+
+import (
+	"time"
+)
+
+// API provides access to the runtime measurements taken by the watchdog
+// feature.
+//
+// This interface is implemented by the mikros framework and is available to
+// services that enable the "watchdog" feature. Services can use it to export
+// the measurements through their own metrics system.
+type API interface {
+	// SchedulerLag returns the most recent scheduler lag measured, i.e., how
+	// late the probe timer fired compared to its interval. A growing lag is
+	// an early signal of CPU saturation.
+	SchedulerLag() time.Duration
+
+	// MaxSchedulerLag returns the highest scheduler lag measured since the
+	// service started.
+	MaxSchedulerLag() time.Duration
+}
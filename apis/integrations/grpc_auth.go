@@ -0,0 +1,17 @@
+package integrations
+
+import (
+	"context"
+)
+
+// GRPCAuthenticator defines the contract for authorization plugins used by
+// gRPC services. It is called by the runtime auth interceptor for every RPC
+// method that declares required scopes through its proto method options.
+type GRPCAuthenticator interface {
+	// Authorize authenticates the caller of method (its full name, e.g.
+	// "/package.Service/Method") and checks that it was granted all scopes.
+	// The returned context is given to the method handler, so it can carry
+	// the caller identity. Errors should be gRPC status errors, usually with
+	// codes.Unauthenticated or codes.PermissionDenied.
+	Authorize(ctx context.Context, method string, scopes []string) (context.Context, error)
+}
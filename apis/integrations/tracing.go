@@ -41,3 +41,11 @@ type Tracer interface {
 	// recording durations, or reporting collected metrics.
 	ComputeMetrics(ctx context.Context, serviceName string, data interface{}) error
 }
+
+// TraceSampler is optionally implemented by Tracer integrations to report
+// whether the request being handled is sampled. All log messages of sampled
+// requests are output, including the ones suppressed by the log level.
+type TraceSampler interface {
+	// Sampled returns true when the request of ctx is being traced.
+	Sampled(ctx context.Context) bool
+}
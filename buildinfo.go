@@ -0,0 +1,31 @@
+package mikros
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/mikros-dev/mikros/components/buildinfo"
+	"github.com/mikros-dev/mikros/components/logger"
+)
+
+// BuildInfo returns how the service binary was built, including every module
+// compiled into it.
+func (s *Service) BuildInfo() buildinfo.Info {
+	info, _ := buildinfo.Read()
+	info.ServiceName = s.definitions.ServiceName().String()
+	info.ServiceVersion = s.definitions.Version
+
+	return info
+}
+
+// BuildInfoHandler returns an HTTP handler that outputs the service BuildInfo
+// as JSON, so its dependencies can be audited. It is intended to be mounted
+// in an administrative (non-public) route.
+func (s *Service) BuildInfoHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		if err := json.NewEncoder(w).Encode(s.BuildInfo()); err != nil {
+			s.logger.Error(r.Context(), "failed to write build info", logger.Error(err))
+		}
+	})
+}
@@ -0,0 +1,39 @@
+// Command mikros-bindgen generates static binding code for request structs,
+// removing the reflection of the mikros http binding functions. It is meant
+// to be used with go:generate, for structs annotated with "//mikros:bind":
+//
+//	//go:generate go run github.com/mikros-dev/mikros/cmd/mikros-bindgen -file=$GOFILE
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/mikros-dev/mikros/components/http/bindgen"
+)
+
+func main() {
+	var (
+		file   = flag.String("file", os.Getenv("GOFILE"), "Go source file declaring the request structs")
+		names  = flag.String("type", "", "comma-separated structs to generate binders for (default: annotated structs)")
+		output = flag.String("output", "", "generated file name (default: file with a _bind.go suffix)")
+	)
+
+	flag.Parse()
+
+	var types []string
+	if *names != "" {
+		types = strings.Split(*names, ",")
+	}
+
+	if err := bindgen.Write(&bindgen.Options{
+		File:   *file,
+		Types:  types,
+		Output: *output,
+	}); err != nil {
+		fmt.Fprintf(os.Stderr, "mikros-bindgen: %v\n", err)
+		os.Exit(1)
+	}
+}
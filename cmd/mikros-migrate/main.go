@@ -0,0 +1,70 @@
+// Command mikros-migrate brings a service definitions file to the format of
+// the current framework version, applying the registered migration rules and
+// flagging deprecated or unknown keys that must be fixed by hand:
+//
+//	mikros-migrate -file service.toml -write
+//
+// It exits with code 1 when something is left to be fixed, so it can be used
+// by CI jobs.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/mikros-dev/mikros/components/definition"
+)
+
+func main() {
+	var (
+		file  = flag.String("file", "service.toml", "definitions file to migrate")
+		write = flag.Bool("write", false, "rewrite the file instead of only reporting (comments are not kept)")
+	)
+
+	flag.Parse()
+
+	content, report, err := definition.MigrateFile(*file, definition.MigrateOptions{
+		Schema: coreSchema(),
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "mikros-migrate: %v\n", err)
+		os.Exit(1)
+	}
+
+	for _, c := range report.Changes {
+		fmt.Println(c.String())
+	}
+	for _, key := range report.Unknown {
+		fmt.Printf("unknown: %s\n", key)
+	}
+
+	if *write && len(report.Changes) > 0 {
+		if err := os.WriteFile(*file, content, 0o644); err != nil {
+			fmt.Fprintf(os.Stderr, "mikros-migrate: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if report.Pending() {
+		os.Exit(1)
+	}
+}
+
+// coreSchema returns the schema of the framework definitions. Settings of
+// features are not known by this command, so their section is not checked.
+func coreSchema() definition.Schema {
+	defs, err := definition.New()
+	if err != nil {
+		return nil
+	}
+
+	schema := defs.JSONSchema(nil)
+	if properties, ok := schema["properties"].(map[string]interface{}); ok {
+		if features, ok := properties["features"].(definition.Schema); ok {
+			features["additionalProperties"] = true
+		}
+	}
+
+	return schema
+}
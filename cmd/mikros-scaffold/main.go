@@ -0,0 +1,34 @@
+// Command mikros-scaffold generates the boilerplate of a new external
+// feature or runtime. It is meant to be used with go:generate:
+//
+//	//go:generate go run github.com/mikros-dev/mikros/cmd/mikros-scaffold -kind=feature -name=audit_log
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/mikros-dev/mikros/components/scaffold"
+)
+
+func main() {
+	var (
+		kind = flag.String("kind", string(scaffold.KindFeature), "plugin kind: feature or runtime")
+		name = flag.String("name", "", "plugin name, in snake_case")
+		pkg  = flag.String("package", "", "package name of the generated files (default: name without underscores)")
+		dir  = flag.String("dir", ".", "directory where the files are generated")
+	)
+
+	flag.Parse()
+
+	if err := scaffold.Write(&scaffold.Options{
+		Kind:      scaffold.Kind(*kind),
+		Name:      *name,
+		Package:   *pkg,
+		Directory: *dir,
+	}); err != nil {
+		fmt.Fprintf(os.Stderr, "mikros-scaffold: %v\n", err)
+		os.Exit(1)
+	}
+}
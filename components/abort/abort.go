@@ -0,0 +1,122 @@
+// Package abort classifies the failures that make a service abort, so each
+// class finishes the process with its own exit code and orchestrators and
+// scripts can react differently to them, e.g. by not restarting a service
+// with an invalid configuration.
+package abort
+
+import (
+	"errors"
+	"syscall"
+)
+
+// Reason is the class of failure that made a service abort.
+type Reason int
+
+// Supported abort reasons. Their exit codes follow the sysexits.h
+// convention when there is a matching one.
+const (
+	// ReasonUnknown is used for failures that don't fit any other class.
+	// It exits with code 1.
+	ReasonUnknown Reason = iota
+
+	// ReasonConfig is used for invalid service definitions, options or
+	// environment variables. It exits with code 78 (EX_CONFIG).
+	ReasonConfig
+
+	// ReasonDependencyUnavailable is used when a dependency, such as a
+	// feature, an integration or a coupled service, cannot be reached. It
+	// exits with code 69 (EX_UNAVAILABLE).
+	ReasonDependencyUnavailable
+
+	// ReasonPortBusy is used when a server port is already in use. It exits
+	// with code 75 (EX_TEMPFAIL).
+	ReasonPortBusy
+
+	// ReasonRuntime is used when a runtime fails while the service is
+	// running. It exits with code 70 (EX_SOFTWARE).
+	ReasonRuntime
+)
+
+var (
+	exitCodes = map[Reason]int{
+		ReasonUnknown:               1,
+		ReasonConfig:                78,
+		ReasonDependencyUnavailable: 69,
+		ReasonPortBusy:              75,
+		ReasonRuntime:               70,
+	}
+
+	reasonNames = map[Reason]string{
+		ReasonUnknown:               "unknown",
+		ReasonConfig:                "config",
+		ReasonDependencyUnavailable: "dependency_unavailable",
+		ReasonPortBusy:              "port_busy",
+		ReasonRuntime:               "runtime",
+	}
+)
+
+// ExitCode returns the process exit code of the reason.
+func (r Reason) ExitCode() int {
+	if code, ok := exitCodes[r]; ok {
+		return code
+	}
+
+	return exitCodes[ReasonUnknown]
+}
+
+// String returns the reason name.
+func (r Reason) String() string {
+	if name, ok := reasonNames[r]; ok {
+		return name
+	}
+
+	return reasonNames[ReasonUnknown]
+}
+
+// Error is an error annotated with the reason of the abort it causes.
+type Error struct {
+	Reason Reason
+	Err    error
+}
+
+// Wrap annotates err with an abort reason. It returns nil if err is nil
+// and keeps the reason of errors that were already annotated.
+func Wrap(reason Reason, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var e *Error
+	if errors.As(err, &e) {
+		return err
+	}
+
+	return &Error{
+		Reason: reason,
+		Err:    err,
+	}
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// ReasonOf returns the abort reason of err. Errors caused by addresses
+// already in use are always ReasonPortBusy, while other errors that were
+// not annotated are ReasonUnknown.
+func ReasonOf(err error) Reason {
+	if errors.Is(err, syscall.EADDRINUSE) {
+		return ReasonPortBusy
+	}
+
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Reason
+	}
+
+	return ReasonUnknown
+}
@@ -0,0 +1,70 @@
+package abort
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReason(t *testing.T) {
+	t.Run("should have distinct exit codes", func(t *testing.T) {
+		codes := map[int]Reason{}
+		for _, r := range []Reason{ReasonUnknown, ReasonConfig, ReasonDependencyUnavailable, ReasonPortBusy, ReasonRuntime} {
+			_, exists := codes[r.ExitCode()]
+			assert.False(t, exists, r.String())
+			codes[r.ExitCode()] = r
+		}
+
+		assert.Equal(t, 1, ReasonUnknown.ExitCode())
+		assert.Equal(t, 78, ReasonConfig.ExitCode())
+	})
+
+	t.Run("should handle unknown reasons as ReasonUnknown", func(t *testing.T) {
+		assert.Equal(t, 1, Reason(100).ExitCode())
+		assert.Equal(t, "unknown", Reason(100).String())
+	})
+}
+
+func TestWrap(t *testing.T) {
+	t.Run("should annotate errors keeping their message", func(t *testing.T) {
+		cause := errors.New("invalid port")
+		err := Wrap(ReasonConfig, cause)
+
+		assert.Equal(t, "invalid port", err.Error())
+		assert.ErrorIs(t, err, cause)
+		assert.Equal(t, ReasonConfig, ReasonOf(err))
+		assert.Equal(t, ReasonConfig, ReasonOf(fmt.Errorf("bootstrap: %w", err)))
+	})
+
+	t.Run("should keep the reason of annotated errors", func(t *testing.T) {
+		err := Wrap(ReasonRuntime, fmt.Errorf("outer: %w", Wrap(ReasonDependencyUnavailable, errors.New("boom"))))
+		assert.Equal(t, ReasonDependencyUnavailable, ReasonOf(err))
+	})
+
+	t.Run("should return nil for nil errors", func(t *testing.T) {
+		assert.NoError(t, Wrap(ReasonConfig, nil))
+	})
+}
+
+func TestReasonOf(t *testing.T) {
+	t.Run("should return ReasonUnknown for errors without reason", func(t *testing.T) {
+		assert.Equal(t, ReasonUnknown, ReasonOf(errors.New("boom")))
+		assert.Equal(t, ReasonUnknown, ReasonOf(nil))
+	})
+
+	t.Run("should detect ports already in use", func(t *testing.T) {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		defer l.Close()
+
+		_, err = net.Listen("tcp", l.Addr().String())
+		require.Error(t, err)
+
+		err = Wrap(ReasonDependencyUnavailable, fmt.Errorf("could not listen to service port: %w", err))
+		assert.Equal(t, ReasonPortBusy, ReasonOf(err))
+	})
+}
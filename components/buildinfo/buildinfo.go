@@ -0,0 +1,90 @@
+// Package buildinfo exposes how the running binary was built, including the
+// list of modules it depends on, in a machine-readable form, so security
+// tooling can audit running services without access to build artifacts.
+package buildinfo
+
+import (
+	"runtime/debug"
+	"sort"
+)
+
+// Module is a Go module compiled into the binary.
+type Module struct {
+	Path    string  `json:"path"`
+	Version string  `json:"version"`
+	Sum     string  `json:"sum,omitempty"`
+	Replace *Module `json:"replace,omitempty"`
+}
+
+// Info describes the binary build.
+type Info struct {
+	// ServiceName and ServiceVersion identify the service, when known.
+	ServiceName    string `json:"service_name,omitempty"`
+	ServiceVersion string `json:"service_version,omitempty"`
+
+	GoVersion string `json:"go_version"`
+
+	// Path is the package path of the main package.
+	Path string `json:"path"`
+	Main Module `json:"main"`
+
+	// Settings are the build settings, such as the VCS revision
+	// (vcs.revision) and the build flags.
+	Settings map[string]string `json:"settings,omitempty"`
+
+	// Dependencies are all modules compiled into the binary, sorted by
+	// their path.
+	Dependencies []Module `json:"dependencies"`
+}
+
+// Read returns the build information embedded in the running binary. It
+// returns false when the binary was not built with module support.
+func Read() (Info, bool) {
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return Info{}, false
+	}
+
+	return FromBuildInfo(bi), true
+}
+
+// FromBuildInfo converts the build information of the runtime/debug package.
+func FromBuildInfo(bi *debug.BuildInfo) Info {
+	info := Info{
+		GoVersion:    bi.GoVersion,
+		Path:         bi.Path,
+		Main:         fromDebugModule(&bi.Main),
+		Dependencies: make([]Module, 0, len(bi.Deps)),
+	}
+
+	if len(bi.Settings) > 0 {
+		info.Settings = make(map[string]string, len(bi.Settings))
+		for _, s := range bi.Settings {
+			info.Settings[s.Key] = s.Value
+		}
+	}
+
+	for _, dep := range bi.Deps {
+		info.Dependencies = append(info.Dependencies, fromDebugModule(dep))
+	}
+	sort.Slice(info.Dependencies, func(i, j int) bool {
+		return info.Dependencies[i].Path < info.Dependencies[j].Path
+	})
+
+	return info
+}
+
+func fromDebugModule(m *debug.Module) Module {
+	module := Module{
+		Path:    m.Path,
+		Version: m.Version,
+		Sum:     m.Sum,
+	}
+
+	if m.Replace != nil {
+		replace := fromDebugModule(m.Replace)
+		module.Replace = &replace
+	}
+
+	return module
+}
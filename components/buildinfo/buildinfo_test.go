@@ -0,0 +1,45 @@
+package buildinfo
+
+import (
+	"runtime/debug"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromBuildInfo(t *testing.T) {
+	t.Run("should convert modules and settings", func(t *testing.T) {
+		info := FromBuildInfo(&debug.BuildInfo{
+			GoVersion: "go1.24.0",
+			Path:      "example.com/service/cmd",
+			Main:      debug.Module{Path: "example.com/service", Version: "(devel)"},
+			Deps: []*debug.Module{
+				{Path: "github.com/stretchr/testify", Version: "v1.9.0", Sum: "h1:abc"},
+				{
+					Path:    "github.com/BurntSushi/toml",
+					Version: "v1.4.0",
+					Replace: &debug.Module{Path: "../toml", Version: ""},
+				},
+			},
+			Settings: []debug.BuildSetting{
+				{Key: "vcs.revision", Value: "0123abc"},
+			},
+		})
+
+		assert.Equal(t, "go1.24.0", info.GoVersion)
+		assert.Equal(t, "example.com/service", info.Main.Path)
+		assert.Equal(t, "0123abc", info.Settings["vcs.revision"])
+		require.Len(t, info.Dependencies, 2)
+		assert.Equal(t, "github.com/BurntSushi/toml", info.Dependencies[0].Path)
+		assert.Equal(t, "../toml", info.Dependencies[0].Replace.Path)
+		assert.Equal(t, "h1:abc", info.Dependencies[1].Sum)
+	})
+
+	t.Run("should read the running binary information", func(t *testing.T) {
+		info, ok := Read()
+		require.True(t, ok)
+		assert.NotEmpty(t, info.GoVersion)
+		assert.NotNil(t, info.Dependencies)
+	})
+}
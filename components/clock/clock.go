@@ -0,0 +1,132 @@
+// Package clock provides the time source used by the framework timers, such
+// as cache TTLs, lock leases and token expirations, allowing tests to move
+// time forward instead of waiting for it.
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock is a source of time.
+type Clock interface {
+	Now() time.Time
+	Since(t time.Time) time.Duration
+	After(d time.Duration) <-chan time.Time
+	NewTimer(d time.Duration) Timer
+	NewTicker(d time.Duration) Ticker
+}
+
+// Timer is a single event, like time.Timer.
+type Timer interface {
+	C() <-chan time.Time
+	Stop() bool
+	Reset(d time.Duration) bool
+}
+
+// Ticker delivers ticks at intervals, like time.Ticker.
+type Ticker interface {
+	C() <-chan time.Time
+	Stop()
+}
+
+var (
+	mu      sync.RWMutex
+	current Clock = Real()
+)
+
+// Default returns the clock currently used by the framework.
+func Default() Clock {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	return current
+}
+
+// Set replaces the clock used by the framework until the returned function
+// is called, which restores the previous one. Timers and tickers keep the
+// clock that created them. Since it is global, tests setting it must not run
+// in parallel.
+func Set(c Clock) func() {
+	mu.Lock()
+	defer mu.Unlock()
+
+	previous := current
+	current = c
+
+	return func() {
+		mu.Lock()
+		defer mu.Unlock()
+
+		current = previous
+	}
+}
+
+// Now returns the current time of the default clock.
+func Now() time.Time {
+	return Default().Now()
+}
+
+// Since returns the time elapsed since t in the default clock.
+func Since(t time.Time) time.Duration {
+	return Default().Since(t)
+}
+
+// After waits for d to elapse in the default clock and then sends the
+// current time on the returned channel.
+func After(d time.Duration) <-chan time.Time {
+	return Default().After(d)
+}
+
+// NewTimer creates a new Timer of the default clock.
+func NewTimer(d time.Duration) Timer {
+	return Default().NewTimer(d)
+}
+
+// NewTicker creates a new Ticker of the default clock.
+func NewTicker(d time.Duration) Ticker {
+	return Default().NewTicker(d)
+}
+
+// Real returns the Clock of the system time.
+func Real() Clock {
+	return realClock{}
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) Since(t time.Time) time.Duration {
+	return time.Since(t)
+}
+
+func (realClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+func (realClock) NewTimer(d time.Duration) Timer {
+	return &realTimer{Timer: time.NewTimer(d)}
+}
+
+func (realClock) NewTicker(d time.Duration) Ticker {
+	return &realTicker{Ticker: time.NewTicker(d)}
+}
+
+type realTimer struct {
+	*time.Timer
+}
+
+func (t *realTimer) C() <-chan time.Time {
+	return t.Timer.C
+}
+
+type realTicker struct {
+	*time.Ticker
+}
+
+func (t *realTicker) C() <-chan time.Time {
+	return t.Ticker.C
+}
@@ -0,0 +1,39 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSet(t *testing.T) {
+	t.Run("should replace the default clock until restored", func(t *testing.T) {
+		var (
+			start   = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+			f       = NewFake(start)
+			restore = Set(f)
+		)
+
+		timer := NewTimer(time.Minute)
+		assert.Equal(t, start, Now())
+
+		f.Advance(time.Minute)
+		_, ok := received(timer.C())
+		assert.True(t, ok)
+		assert.Equal(t, time.Minute, Since(start))
+
+		restore()
+		assert.WithinDuration(t, time.Now(), Now(), time.Second)
+	})
+
+	t.Run("should use the system time by default", func(t *testing.T) {
+		timer := NewTimer(time.Millisecond)
+
+		select {
+		case <-timer.C():
+		case <-time.After(time.Second):
+			t.Fatal("timer did not fire")
+		}
+	})
+}
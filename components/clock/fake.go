@@ -0,0 +1,204 @@
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Fake is a Clock whose time only moves when told to, firing the timers and
+// tickers that become due.
+type Fake struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []*waiter
+}
+
+type waiter struct {
+	clock  *Fake
+	when   time.Time
+	period time.Duration
+	c      chan time.Time
+	active bool
+}
+
+// NewFake creates a Fake clock starting at now.
+func NewFake(now time.Time) *Fake {
+	return &Fake{
+		now: now,
+	}
+}
+
+// Now returns the clock current time.
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.now
+}
+
+// Since returns the time elapsed since t.
+func (f *Fake) Since(t time.Time) time.Duration {
+	return f.Now().Sub(t)
+}
+
+// After returns a channel that receives the clock time once d elapses.
+func (f *Fake) After(d time.Duration) <-chan time.Time {
+	return f.NewTimer(d).C()
+}
+
+// NewTimer creates a Timer that fires once d elapses.
+func (f *Fake) NewTimer(d time.Duration) Timer {
+	return f.add(d, 0)
+}
+
+// NewTicker creates a Ticker that ticks every d.
+func (f *Fake) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("clock: non-positive interval for NewTicker")
+	}
+
+	return &fakeTicker{waiter: f.add(d, d)}
+}
+
+func (f *Fake) add(d, period time.Duration) *waiter {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	w := &waiter{
+		clock:  f,
+		when:   f.now.Add(d),
+		period: period,
+		c:      make(chan time.Time, 1),
+		active: true,
+	}
+	f.waiters = append(f.waiters, w)
+
+	// Timers with no duration fire right away, like the real ones.
+	if d <= 0 && period == 0 {
+		f.fire(f.now)
+	}
+
+	return w
+}
+
+// Advance moves the clock forward by d, firing, in order, every timer and
+// tick that becomes due.
+func (f *Fake) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.fire(f.now.Add(d))
+}
+
+// SetNow moves the clock to t. Timers and ticks due until t are fired when
+// it moves forward.
+func (f *Fake) SetNow(t time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if t.Before(f.now) {
+		f.now = t
+		return
+	}
+
+	f.fire(t)
+}
+
+// fire moves the clock up to target firing the due waiters. It must be called
+// with the lock held.
+func (f *Fake) fire(target time.Time) {
+	for {
+		var next *waiter
+		for _, w := range f.waiters {
+			if w.active && !w.when.After(target) && (next == nil || w.when.Before(next.when)) {
+				next = w
+			}
+		}
+		if next == nil {
+			break
+		}
+
+		if next.when.After(f.now) {
+			f.now = next.when
+		}
+
+		// Like the real ones, ticks are dropped when nobody is receiving
+		// them.
+		select {
+		case next.c <- f.now:
+		default:
+		}
+
+		if next.period > 0 {
+			next.when = next.when.Add(next.period)
+			continue
+		}
+
+		next.active = false
+		f.remove(next)
+	}
+
+	f.now = target
+}
+
+func (f *Fake) remove(w *waiter) {
+	for i, v := range f.waiters {
+		if v == w {
+			f.waiters = append(f.waiters[:i], f.waiters[i+1:]...)
+			return
+		}
+	}
+}
+
+func (w *waiter) C() <-chan time.Time {
+	return w.c
+}
+
+// Stop prevents the timer, or ticker, from firing, returning false if it had
+// already fired or been stopped.
+func (w *waiter) Stop() bool {
+	w.clock.mu.Lock()
+	defer w.clock.mu.Unlock()
+
+	wasActive := w.active
+	w.active = false
+	w.clock.remove(w)
+
+	return wasActive
+}
+
+// Reset changes the timer to fire after d, returning false if it had
+// already fired or been stopped.
+func (w *waiter) Reset(d time.Duration) bool {
+	w.clock.mu.Lock()
+	defer w.clock.mu.Unlock()
+
+	wasActive := w.active
+	if !wasActive {
+		w.clock.waiters = append(w.clock.waiters, w)
+	}
+
+	w.active = true
+	w.when = w.clock.now.Add(d)
+	if w.period > 0 {
+		w.period = d
+	}
+
+	if d <= 0 && w.period == 0 {
+		w.clock.fire(w.clock.now)
+	}
+
+	return wasActive
+}
+
+type fakeTicker struct {
+	waiter *waiter
+}
+
+func (t *fakeTicker) C() <-chan time.Time {
+	return t.waiter.C()
+}
+
+func (t *fakeTicker) Stop() {
+	t.waiter.Stop()
+}
@@ -0,0 +1,100 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func received(c <-chan time.Time) (time.Time, bool) {
+	select {
+	case t := <-c:
+		return t, true
+	default:
+		return time.Time{}, false
+	}
+}
+
+func TestFake(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("should only move time when advanced", func(t *testing.T) {
+		f := NewFake(start)
+		assert.Equal(t, start, f.Now())
+
+		f.Advance(time.Hour)
+		assert.Equal(t, start.Add(time.Hour), f.Now())
+		assert.Equal(t, time.Hour, f.Since(start))
+
+		f.SetNow(start)
+		assert.Equal(t, start, f.Now())
+	})
+
+	t.Run("should fire timers when they become due", func(t *testing.T) {
+		var (
+			f     = NewFake(start)
+			timer = f.NewTimer(time.Minute)
+			after = f.After(2 * time.Minute)
+		)
+
+		f.Advance(59 * time.Second)
+		_, ok := received(timer.C())
+		assert.False(t, ok)
+
+		f.Advance(time.Second)
+		fired, ok := received(timer.C())
+		assert.True(t, ok)
+		assert.Equal(t, start.Add(time.Minute), fired)
+		assert.False(t, timer.Stop())
+
+		f.SetNow(start.Add(time.Hour))
+		fired, ok = received(after)
+		assert.True(t, ok)
+		assert.Equal(t, start.Add(2*time.Minute), fired)
+	})
+
+	t.Run("should not fire stopped timers", func(t *testing.T) {
+		var (
+			f     = NewFake(start)
+			timer = f.NewTimer(time.Minute)
+		)
+
+		assert.True(t, timer.Stop())
+		f.Advance(time.Hour)
+
+		_, ok := received(timer.C())
+		assert.False(t, ok)
+
+		assert.False(t, timer.Reset(time.Second))
+		f.Advance(time.Second)
+		_, ok = received(timer.C())
+		assert.True(t, ok)
+	})
+
+	t.Run("should fire timers without duration right away", func(t *testing.T) {
+		f := NewFake(start)
+
+		_, ok := received(f.NewTimer(0).C())
+		assert.True(t, ok)
+	})
+
+	t.Run("should tick until stopped", func(t *testing.T) {
+		var (
+			f      = NewFake(start)
+			ticker = f.NewTicker(time.Second)
+		)
+
+		for i := 1; i <= 3; i++ {
+			f.Advance(time.Second)
+			tick, ok := received(ticker.C())
+			assert.True(t, ok)
+			assert.Equal(t, start.Add(time.Duration(i)*time.Second), tick)
+		}
+
+		ticker.Stop()
+		f.Advance(time.Second)
+		_, ok := received(ticker.C())
+		assert.False(t, ok)
+	})
+}
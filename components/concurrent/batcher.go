@@ -0,0 +1,236 @@
+package concurrent
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	logger_api "github.com/mikros-dev/mikros/apis/features/logger"
+	"github.com/mikros-dev/mikros/components/logger"
+)
+
+const (
+	defaultBatchMaxItems = 100
+	defaultBatchMaxWait  = time.Second
+)
+
+var (
+	// ErrBatcherStopped is returned when an item is added to a batcher that
+	// was stopped.
+	ErrBatcherStopped = errors.New("batcher is stopped")
+)
+
+// BatchFunc receives a batch of accumulated items to be handled, usually
+// written into a downstream system at once.
+type BatchFunc[T any] func(ctx context.Context, items []T) error
+
+// BatcherOptions configures a Batcher.
+type BatcherOptions[T any] struct {
+	// MaxItems is the number of accumulated items that triggers a flush.
+	// Defaults to 100.
+	MaxItems int
+
+	// MaxWait is the maximum time an item waits, since the first item of the
+	// batch was added, before the batch is flushed. Defaults to 1s.
+	MaxWait time.Duration
+
+	// BufferSize is the number of items that can wait to be accumulated. Add
+	// blocks when the buffer is full. Defaults to MaxItems.
+	BufferSize int
+
+	// Logger, when set, receives an error message for every failed batch.
+	Logger logger_api.API
+
+	// OnError, when set, is called with the error and the items of every
+	// failed batch, allowing them to be retried or stored somewhere else.
+	OnError func(err error, items []T)
+}
+
+// BatcherStats gathers the batcher counters.
+type BatcherStats struct {
+	Pending      int64
+	Batches      uint64
+	Items        uint64
+	Failed       uint64
+	FailedItems  uint64
+	SizeFlushes  uint64
+	TimerFlushes uint64
+}
+
+// Batcher accumulates items and hands them, in batches, to a BatchFunc when
+// MaxItems items were added or MaxWait has elapsed, whatever comes first.
+// Remaining items are flushed when the batcher is stopped, so it can be
+// stopped along with the service without losing them.
+type Batcher[T any] struct {
+	options BatcherOptions[T]
+	flush   BatchFunc[T]
+	ctx     context.Context
+	cancel  context.CancelFunc
+	items   chan T
+	done    chan struct{}
+
+	mu      sync.RWMutex
+	stopped bool
+
+	pending      atomic.Int64
+	batches      atomic.Uint64
+	flushed      atomic.Uint64
+	failed       atomic.Uint64
+	failedItems  atomic.Uint64
+	sizeFlushes  atomic.Uint64
+	timerFlushes atomic.Uint64
+}
+
+// NewBatcher creates a new Batcher that hands its batches to flush.
+func NewBatcher[T any](flush BatchFunc[T], options ...BatcherOptions[T]) *Batcher[T] {
+	var opts BatcherOptions[T]
+	if len(options) > 0 {
+		opts = options[0]
+	}
+	if opts.MaxItems <= 0 {
+		opts.MaxItems = defaultBatchMaxItems
+	}
+	if opts.MaxWait <= 0 {
+		opts.MaxWait = defaultBatchMaxWait
+	}
+	if opts.BufferSize <= 0 {
+		opts.BufferSize = opts.MaxItems
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	b := &Batcher[T]{
+		options: opts,
+		flush:   flush,
+		ctx:     ctx,
+		cancel:  cancel,
+		items:   make(chan T, opts.BufferSize),
+		done:    make(chan struct{}),
+	}
+
+	go b.loop()
+	return b
+}
+
+func (b *Batcher[T]) loop() {
+	defer close(b.done)
+
+	var (
+		batch = make([]T, 0, b.options.MaxItems)
+		timer = time.NewTimer(b.options.MaxWait)
+	)
+	timer.Stop()
+	defer timer.Stop()
+
+	for {
+		select {
+		case item, ok := <-b.items:
+			if !ok {
+				b.handle(batch)
+				return
+			}
+
+			if len(batch) == 0 {
+				timer.Reset(b.options.MaxWait)
+			}
+
+			batch = append(batch, item)
+			if len(batch) >= b.options.MaxItems {
+				timer.Stop()
+				b.sizeFlushes.Add(1)
+				b.handle(batch)
+				batch = make([]T, 0, b.options.MaxItems)
+			}
+
+		case <-timer.C:
+			if len(batch) > 0 {
+				b.timerFlushes.Add(1)
+				b.handle(batch)
+				batch = make([]T, 0, b.options.MaxItems)
+			}
+		}
+	}
+}
+
+func (b *Batcher[T]) handle(batch []T) {
+	if len(batch) == 0 {
+		return
+	}
+
+	defer b.pending.Add(-int64(len(batch)))
+	b.batches.Add(1)
+
+	if err := b.flush(b.ctx, batch); err != nil {
+		b.failed.Add(1)
+		b.failedItems.Add(uint64(len(batch)))
+		b.report(err, batch)
+		return
+	}
+
+	b.flushed.Add(uint64(len(batch)))
+}
+
+func (b *Batcher[T]) report(err error, batch []T) {
+	if b.options.OnError != nil {
+		b.options.OnError(err, batch)
+	}
+
+	if b.options.Logger != nil {
+		b.options.Logger.Error(b.ctx, "batch flush failed", logger.Error(err), logger.Int32("items", int32(len(batch))))
+	}
+}
+
+// Add adds an item into the current batch, waiting for room in the buffer
+// while ctx is not done.
+func (b *Batcher[T]) Add(ctx context.Context, item T) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if b.stopped {
+		return ErrBatcherStopped
+	}
+
+	b.pending.Add(1)
+	select {
+	case b.items <- item:
+		return nil
+	case <-ctx.Done():
+		b.pending.Add(-1)
+		return ctx.Err()
+	}
+}
+
+// Stop stops accepting items and flushes the ones already added. If ctx is
+// done before that, the context given to the BatchFunc is canceled and ctx
+// error is returned.
+func (b *Batcher[T]) Stop(ctx context.Context) error {
+	b.mu.Lock()
+	if !b.stopped {
+		b.stopped = true
+		close(b.items)
+	}
+	b.mu.Unlock()
+
+	select {
+	case <-b.done:
+		b.cancel()
+		return nil
+	case <-ctx.Done():
+		b.cancel()
+		return ctx.Err()
+	}
+}
+
+// Stats returns the batcher counters.
+func (b *Batcher[T]) Stats() BatcherStats {
+	return BatcherStats{
+		Pending:      b.pending.Load(),
+		Batches:      b.batches.Load(),
+		Items:        b.flushed.Load(),
+		Failed:       b.failed.Load(),
+		FailedItems:  b.failedItems.Load(),
+		SizeFlushes:  b.sizeFlushes.Load(),
+		TimerFlushes: b.timerFlushes.Load(),
+	}
+}
@@ -0,0 +1,110 @@
+package concurrent
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type batchRecorder struct {
+	mu      sync.Mutex
+	batches [][]int
+}
+
+func (r *batchRecorder) flush(_ context.Context, items []int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.batches = append(r.batches, append([]int(nil), items...))
+	return nil
+}
+
+func (r *batchRecorder) get() [][]int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.batches
+}
+
+func TestBatcher(t *testing.T) {
+	t.Run("should flush when the batch is full", func(t *testing.T) {
+		var (
+			r = &batchRecorder{}
+			b = NewBatcher(r.flush, BatcherOptions[int]{MaxItems: 3, MaxWait: time.Hour})
+		)
+
+		for i := 1; i <= 7; i++ {
+			require.NoError(t, b.Add(context.Background(), i))
+		}
+
+		require.Eventually(t, func() bool { return len(r.get()) == 2 }, time.Second, time.Millisecond)
+		require.NoError(t, b.Stop(context.Background()))
+
+		assert.Equal(t, [][]int{{1, 2, 3}, {4, 5, 6}, {7}}, r.get())
+		stats := b.Stats()
+		assert.Equal(t, uint64(3), stats.Batches)
+		assert.Equal(t, uint64(7), stats.Items)
+		assert.Equal(t, uint64(2), stats.SizeFlushes)
+		assert.Equal(t, int64(0), stats.Pending)
+	})
+
+	t.Run("should flush when the wait time elapses", func(t *testing.T) {
+		var (
+			r = &batchRecorder{}
+			b = NewBatcher(r.flush, BatcherOptions[int]{MaxItems: 100, MaxWait: 10 * time.Millisecond})
+		)
+		defer func() { _ = b.Stop(context.Background()) }()
+
+		require.NoError(t, b.Add(context.Background(), 1))
+		require.NoError(t, b.Add(context.Background(), 2))
+
+		require.Eventually(t, func() bool { return len(r.get()) == 1 }, time.Second, time.Millisecond)
+		assert.Equal(t, [][]int{{1, 2}}, r.get())
+		assert.Equal(t, uint64(1), b.Stats().TimerFlushes)
+	})
+
+	t.Run("should report failed batches", func(t *testing.T) {
+		var (
+			failed []int
+			b      = NewBatcher(func(_ context.Context, _ []int) error {
+				return errors.New("unavailable")
+			}, BatcherOptions[int]{
+				OnError: func(_ error, items []int) {
+					failed = append(failed, items...)
+				},
+			})
+		)
+
+		require.NoError(t, b.Add(context.Background(), 1))
+		require.NoError(t, b.Stop(context.Background()))
+
+		assert.Equal(t, []int{1}, failed)
+		assert.Equal(t, uint64(1), b.Stats().Failed)
+	})
+
+	t.Run("should not accept items after stopped", func(t *testing.T) {
+		r := &batchRecorder{}
+		b := NewBatcher(r.flush)
+
+		require.NoError(t, b.Stop(context.Background()))
+		assert.ErrorIs(t, b.Add(context.Background(), 1), ErrBatcherStopped)
+	})
+
+	t.Run("should cancel the flush context when the stop deadline is reached", func(t *testing.T) {
+		b := NewBatcher(func(ctx context.Context, _ []int) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+		require.NoError(t, b.Add(context.Background(), 1))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		assert.ErrorIs(t, b.Stop(ctx), context.DeadlineExceeded)
+	})
+}
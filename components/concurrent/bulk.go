@@ -0,0 +1,73 @@
+package concurrent
+
+import (
+	"context"
+	"errors"
+	"runtime"
+)
+
+// ForEach calls fn for every item using a Pool, waiting until all calls
+// finish. Panics are recovered and reported as PanicError. The errors of
+// the failed calls, and of the items not processed because ctx was done,
+// are returned joined.
+//
+// The pool uses one worker per item, limited to GOMAXPROCS, unless options
+// set it. Its Logger and OnError options also receive the errors.
+func ForEach[T any](ctx context.Context, items []T, fn func(ctx context.Context, item T) error, options ...PoolOptions) error {
+	_, err := Map(ctx, items, func(ctx context.Context, item T) (struct{}, error) {
+		return struct{}{}, fn(ctx, item)
+	}, options...)
+
+	return err
+}
+
+// Map calls fn for every item using a Pool, returning its results in the
+// same order of the items. It behaves like ForEach regarding errors, the
+// result of a failed call being the one returned with the error.
+func Map[T, R any](ctx context.Context, items []T, fn func(ctx context.Context, item T) (R, error), options ...PoolOptions) ([]R, error) {
+	if len(items) == 0 {
+		return nil, nil
+	}
+
+	var (
+		results = make([]R, len(items))
+		errs    = make([]error, len(items))
+		pool    = NewPool(bulkPoolOptions(len(items), options))
+	)
+
+	for i, item := range items {
+		err := pool.Submit(ctx, func(_ context.Context) error {
+			errs[i] = protect(func() error {
+				var err error
+				results[i], err = fn(ctx, item)
+				return err
+			})
+
+			return errs[i]
+		})
+		if err != nil {
+			for j := i; j < len(items); j++ {
+				errs[j] = err
+			}
+			break
+		}
+	}
+
+	// The tasks use ctx, not the pool one, so the pool is drained without
+	// a deadline.
+	_ = pool.Stop(context.Background())
+
+	return results, errors.Join(errs...)
+}
+
+func bulkPoolOptions(items int, options []PoolOptions) PoolOptions {
+	var opts PoolOptions
+	if len(options) > 0 {
+		opts = options[0]
+	}
+	if opts.Workers <= 0 {
+		opts.Workers = min(items, runtime.GOMAXPROCS(0))
+	}
+
+	return opts
+}
@@ -0,0 +1,87 @@
+package concurrent
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestForEach(t *testing.T) {
+	t.Run("should call fn for every item", func(t *testing.T) {
+		var sum atomic.Int64
+
+		err := ForEach(context.Background(), []int{1, 2, 3, 4}, func(_ context.Context, item int) error {
+			sum.Add(int64(item))
+			return nil
+		}, PoolOptions{Workers: 2})
+		require.NoError(t, err)
+		assert.Equal(t, int64(10), sum.Load())
+	})
+
+	t.Run("should join errors and recover panics", func(t *testing.T) {
+		var reported atomic.Int32
+
+		err := ForEach(context.Background(), []string{"ok", "fail", "panic"}, func(_ context.Context, item string) error {
+			switch item {
+			case "fail":
+				return errors.New("failed")
+			case "panic":
+				panic("crash")
+			}
+			return nil
+		}, PoolOptions{
+			OnError: func(error) {
+				reported.Add(1)
+			},
+		})
+
+		assert.ErrorContains(t, err, "failed")
+
+		var panicErr *PanicError
+		require.ErrorAs(t, err, &panicErr)
+		assert.Equal(t, "crash", panicErr.Value)
+		assert.Equal(t, int32(2), reported.Load())
+	})
+
+	t.Run("should not process items after ctx is done", func(t *testing.T) {
+		var (
+			called      atomic.Int32
+			ctx, cancel = context.WithCancel(context.Background())
+		)
+
+		// The worker stays busy while the queue is full, so the items that
+		// don't fit fail because ctx is done.
+		cancel()
+		err := ForEach(ctx, []int{1, 2, 3}, func(_ context.Context, _ int) error {
+			called.Add(1)
+			time.Sleep(50 * time.Millisecond)
+			return nil
+		}, PoolOptions{Workers: 1, QueueSize: 1})
+
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Less(t, called.Load(), int32(3))
+	})
+}
+
+func TestMap(t *testing.T) {
+	t.Run("should return the results in the items order", func(t *testing.T) {
+		results, err := Map(context.Background(), []int{1, 2, 3}, func(_ context.Context, item int) (int, error) {
+			return item * item, nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []int{1, 4, 9}, results)
+	})
+
+	t.Run("should handle no items", func(t *testing.T) {
+		results, err := Map(context.Background(), nil, func(_ context.Context, item int) (int, error) {
+			return item, nil
+		})
+		require.NoError(t, err)
+		assert.Empty(t, results)
+	})
+}
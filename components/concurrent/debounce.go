@@ -0,0 +1,177 @@
+package concurrent
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrDebouncerStopped is returned when a call is made to a debouncer that
+	// was stopped.
+	ErrDebouncerStopped = errors.New("debouncer is stopped")
+)
+
+// DebounceOptions configures a Debouncer.
+type DebounceOptions struct {
+	// MaxWait limits how long a call can be postponed by subsequent calls.
+	// When reached, the function is executed even if calls keep arriving.
+	// Zero means no limit.
+	MaxWait time.Duration
+}
+
+// Debouncer postpones the execution of a function until a period without
+// calls has elapsed, executing it only once, with the value of the last call,
+// for a burst of calls. Executions never overlap.
+type Debouncer[T any] struct {
+	wait    time.Duration
+	options DebounceOptions
+	fn      func(ctx context.Context, value T)
+	ctx     context.Context
+	cancel  context.CancelFunc
+
+	mu         sync.Mutex
+	timer      *time.Timer
+	generation uint64
+	pending    bool
+	value      T
+	first      time.Time
+	stopped    bool
+
+	exec    sync.Mutex
+	running sync.WaitGroup
+}
+
+// NewDebouncer creates a new Debouncer that executes fn after wait has
+// elapsed since the last call.
+func NewDebouncer[T any](wait time.Duration, fn func(ctx context.Context, value T), options ...DebounceOptions) *Debouncer[T] {
+	var opts DebounceOptions
+	if len(options) > 0 {
+		opts = options[0]
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Debouncer[T]{
+		wait:    wait,
+		options: opts,
+		fn:      fn,
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+}
+
+// Call schedules the function execution with value, postponing any execution
+// scheduled by previous calls.
+func (d *Debouncer[T]) Call(value T) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.stopped {
+		return ErrDebouncerStopped
+	}
+
+	now := time.Now()
+	if !d.pending {
+		d.pending = true
+		d.first = now
+	}
+	d.value = value
+
+	delay := d.wait
+	if d.options.MaxWait > 0 {
+		if remaining := d.options.MaxWait - now.Sub(d.first); remaining < delay {
+			delay = max(remaining, 0)
+		}
+	}
+
+	if d.timer != nil {
+		d.timer.Stop()
+	}
+
+	d.generation++
+	generation := d.generation
+	d.timer = time.AfterFunc(delay, func() {
+		d.fire(generation)
+	})
+
+	return nil
+}
+
+func (d *Debouncer[T]) fire(generation uint64) {
+	d.mu.Lock()
+	if !d.pending || generation != d.generation {
+		d.mu.Unlock()
+		return
+	}
+
+	value, ok := d.take()
+	d.running.Add(1)
+	d.mu.Unlock()
+
+	defer d.running.Done()
+	if ok {
+		d.execute(value)
+	}
+}
+
+// take must be called with mu held.
+func (d *Debouncer[T]) take() (T, bool) {
+	var zero T
+	if !d.pending {
+		return zero, false
+	}
+
+	value := d.value
+	d.pending = false
+	d.value = zero
+
+	return value, true
+}
+
+func (d *Debouncer[T]) execute(value T) {
+	d.exec.Lock()
+	defer d.exec.Unlock()
+
+	d.fn(d.ctx, value)
+}
+
+// Flush executes a scheduled call right away, if there is one, waiting for
+// it to finish.
+func (d *Debouncer[T]) Flush() {
+	d.mu.Lock()
+	if d.timer != nil {
+		d.timer.Stop()
+	}
+	value, ok := d.take()
+	d.mu.Unlock()
+
+	if ok {
+		d.execute(value)
+	}
+}
+
+// Stop stops accepting calls, executes the scheduled one, if any, and waits
+// until running executions finish. If ctx is done before that, the context
+// given to the function is canceled and ctx error is returned.
+func (d *Debouncer[T]) Stop(ctx context.Context) error {
+	d.mu.Lock()
+	d.stopped = true
+	d.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		d.Flush()
+		d.running.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		d.cancel()
+		return nil
+	case <-ctx.Done():
+		d.cancel()
+		return ctx.Err()
+	}
+}
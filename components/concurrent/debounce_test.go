@@ -0,0 +1,74 @@
+package concurrent
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type debounceRecorder struct {
+	mu     sync.Mutex
+	values []int
+}
+
+func (r *debounceRecorder) record(_ context.Context, value int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.values = append(r.values, value)
+}
+
+func (r *debounceRecorder) get() []int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]int(nil), r.values...)
+}
+
+func TestDebouncer(t *testing.T) {
+	t.Run("should execute once with the last value of a burst", func(t *testing.T) {
+		var (
+			r = &debounceRecorder{}
+			d = NewDebouncer(20*time.Millisecond, r.record)
+		)
+
+		for i := 1; i <= 5; i++ {
+			require.NoError(t, d.Call(i))
+		}
+
+		require.Eventually(t, func() bool { return len(r.get()) == 1 }, time.Second, time.Millisecond)
+		time.Sleep(40 * time.Millisecond)
+		assert.Equal(t, []int{5}, r.get())
+	})
+
+	t.Run("should not postpone beyond MaxWait", func(t *testing.T) {
+		var (
+			r = &debounceRecorder{}
+			d = NewDebouncer(time.Hour, r.record, DebounceOptions{MaxWait: 20 * time.Millisecond})
+		)
+		defer func() { _ = d.Stop(context.Background()) }()
+
+		require.NoError(t, d.Call(1))
+		require.NoError(t, d.Call(2))
+
+		require.Eventually(t, func() bool { return len(r.get()) == 1 }, time.Second, time.Millisecond)
+		assert.Equal(t, []int{2}, r.get())
+	})
+
+	t.Run("should execute the scheduled call when stopped", func(t *testing.T) {
+		var (
+			r = &debounceRecorder{}
+			d = NewDebouncer(time.Hour, r.record)
+		)
+
+		require.NoError(t, d.Call(1))
+		require.NoError(t, d.Stop(context.Background()))
+
+		assert.Equal(t, []int{1}, r.get())
+		assert.ErrorIs(t, d.Call(2), ErrDebouncerStopped)
+	})
+}
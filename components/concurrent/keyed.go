@@ -0,0 +1,115 @@
+package concurrent
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+var (
+	// ErrSerializerStopped is returned when a function is given to a
+	// serializer that was stopped.
+	ErrSerializerStopped = errors.New("keyed serializer is stopped")
+)
+
+// KeyedSerializer executes functions one at a time for the same key, while
+// functions with different keys run concurrently. It is useful for handling
+// events of the same entity in order without serializing every event.
+type KeyedSerializer struct {
+	mu      sync.Mutex
+	keys    map[string]*keyedLock
+	stopped bool
+	wg      sync.WaitGroup
+}
+
+type keyedLock struct {
+	ch   chan struct{}
+	refs int
+}
+
+// NewKeyedSerializer creates a new KeyedSerializer.
+func NewKeyedSerializer() *KeyedSerializer {
+	return &KeyedSerializer{
+		keys: make(map[string]*keyedLock),
+	}
+}
+
+// Do executes fn once no other function is being executed for key, waiting
+// for it while ctx is not done. It returns fn error.
+func (s *KeyedSerializer) Do(ctx context.Context, key string, fn func(ctx context.Context) error) error {
+	l, err := s.acquire(key)
+	if err != nil {
+		return err
+	}
+	defer s.release(key, l)
+
+	select {
+	case l.ch <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	defer func() { <-l.ch }()
+
+	return fn(ctx)
+}
+
+func (s *KeyedSerializer) acquire(key string) (*keyedLock, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.stopped {
+		return nil, ErrSerializerStopped
+	}
+
+	l, ok := s.keys[key]
+	if !ok {
+		l = &keyedLock{
+			ch: make(chan struct{}, 1),
+		}
+		s.keys[key] = l
+	}
+	l.refs++
+	s.wg.Add(1)
+
+	return l, nil
+}
+
+func (s *KeyedSerializer) release(key string, l *keyedLock) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	l.refs--
+	if l.refs == 0 {
+		delete(s.keys, key)
+	}
+	s.wg.Done()
+}
+
+// Len returns the number of keys with functions running or waiting.
+func (s *KeyedSerializer) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.keys)
+}
+
+// Stop stops accepting functions and waits until the running and waiting
+// ones finish, or ctx is done.
+func (s *KeyedSerializer) Stop(ctx context.Context) error {
+	s.mu.Lock()
+	s.stopped = true
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,90 @@
+package concurrent
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyedSerializer(t *testing.T) {
+	t.Run("should serialize functions with the same key", func(t *testing.T) {
+		var (
+			s       = NewKeyedSerializer()
+			wg      sync.WaitGroup
+			running atomic.Int32
+			maxSeen atomic.Int32
+		)
+
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_ = s.Do(context.Background(), "user-1", func(_ context.Context) error {
+					n := running.Add(1)
+					if n > maxSeen.Load() {
+						maxSeen.Store(n)
+					}
+					time.Sleep(time.Millisecond)
+					running.Add(-1)
+					return nil
+				})
+			}()
+		}
+
+		wg.Wait()
+		assert.Equal(t, int32(1), maxSeen.Load())
+		assert.Equal(t, 0, s.Len())
+	})
+
+	t.Run("should run different keys concurrently", func(t *testing.T) {
+		var (
+			s       = NewKeyedSerializer()
+			started = make(chan struct{})
+			release = make(chan struct{})
+		)
+
+		go func() {
+			_ = s.Do(context.Background(), "a", func(_ context.Context) error {
+				close(started)
+				<-release
+				return nil
+			})
+		}()
+		<-started
+
+		err := s.Do(context.Background(), "b", func(_ context.Context) error {
+			return nil
+		})
+		require.NoError(t, err)
+		close(release)
+	})
+
+	t.Run("should wait for running functions when stopped", func(t *testing.T) {
+		var (
+			s       = NewKeyedSerializer()
+			started = make(chan struct{})
+			done    atomic.Bool
+		)
+
+		go func() {
+			_ = s.Do(context.Background(), "a", func(_ context.Context) error {
+				close(started)
+				time.Sleep(10 * time.Millisecond)
+				done.Store(true)
+				return nil
+			})
+		}()
+		<-started
+
+		require.NoError(t, s.Stop(context.Background()))
+		assert.True(t, done.Load())
+		assert.ErrorIs(t, s.Do(context.Background(), "a", func(_ context.Context) error {
+			return nil
+		}), ErrSerializerStopped)
+	})
+}
@@ -0,0 +1,274 @@
+package concurrent
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	logger_api "github.com/mikros-dev/mikros/apis/features/logger"
+	"github.com/mikros-dev/mikros/components/logger"
+)
+
+const (
+	defaultPipelineBufferSize = 16
+	pipelineSinkStageName     = "sink"
+)
+
+// Source produces the items processed by a Pipeline, sending them to out.
+// It must return when it has no more items or when ctx is done.
+type Source[T any] func(ctx context.Context, out chan<- T) error
+
+// Stage is a processing step of a Pipeline.
+type Stage[T any] struct {
+	// Name identifies the stage in stats and errors.
+	Name string
+
+	// Process handles one item, returning it, possibly changed, to the next
+	// stage.
+	Process func(ctx context.Context, item T) (T, error)
+
+	// Workers is the number of goroutines processing items in the stage.
+	// Defaults to 1.
+	Workers int
+
+	// Retries is how many times Process is called again for an item when it
+	// fails, waiting RetryBackoff between attempts.
+	Retries      int
+	RetryBackoff time.Duration
+}
+
+// PipelineOptions configures a Pipeline.
+type PipelineOptions[T any] struct {
+	// BufferSize is the capacity of the channels between stages. Defaults
+	// to 16.
+	BufferSize int
+
+	// DeadLetter, when set, receives the items that failed in a stage after
+	// all retries, with the stage name and the last error. Failed items are
+	// dropped from the pipeline.
+	DeadLetter func(ctx context.Context, item T, stage string, err error)
+
+	// Logger, when set, receives an error message for every failed item.
+	Logger logger_api.API
+}
+
+// StageStats gathers the counters of a pipeline stage.
+type StageStats struct {
+	Name      string
+	Processed uint64
+	Failed    uint64
+	Retries   uint64
+	Duration  time.Duration
+}
+
+// Pipeline processes items produced by a source through a sequence of
+// stages, ending at a sink. Stages are connected by bounded channels, so a
+// slow stage applies backpressure to the previous ones.
+type Pipeline[T any] struct {
+	source  Source[T]
+	sink    *pipelineStage[T]
+	stages  []*pipelineStage[T]
+	options PipelineOptions[T]
+}
+
+type pipelineStage[T any] struct {
+	Stage[T]
+	processed atomic.Uint64
+	failed    atomic.Uint64
+	retries   atomic.Uint64
+	duration  atomic.Int64
+}
+
+// NewPipeline creates a new Pipeline reading items from source and writing
+// them into sink after all stages. The sink is reported as the "sink" stage
+// in Stats.
+func NewPipeline[T any](
+	source Source[T],
+	sink func(ctx context.Context, item T) error,
+	options ...PipelineOptions[T],
+) *Pipeline[T] {
+	var opts PipelineOptions[T]
+	if len(options) > 0 {
+		opts = options[0]
+	}
+	if opts.BufferSize <= 0 {
+		opts.BufferSize = defaultPipelineBufferSize
+	}
+
+	return &Pipeline[T]{
+		source:  source,
+		options: opts,
+		sink: &pipelineStage[T]{
+			Stage: Stage[T]{
+				Name:    pipelineSinkStageName,
+				Workers: 1,
+				Process: func(ctx context.Context, item T) (T, error) {
+					return item, sink(ctx, item)
+				},
+			},
+		},
+	}
+}
+
+// AddStage appends a stage to the pipeline.
+func (p *Pipeline[T]) AddStage(stage Stage[T]) *Pipeline[T] {
+	if stage.Workers <= 0 {
+		stage.Workers = 1
+	}
+
+	p.stages = append(p.stages, &pipelineStage[T]{Stage: stage})
+	return p
+}
+
+// Run executes the pipeline until the source has no more items and all of
+// them went through the stages, or ctx is done. It returns the source error,
+// if any, or ctx error.
+func (p *Pipeline[T]) Run(ctx context.Context) error {
+	var (
+		stages    = p.allStages()
+		wg        sync.WaitGroup
+		sourceErr error
+		first     = make(chan T, p.options.BufferSize)
+		in        = first
+	)
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer close(first)
+		sourceErr = p.source(ctx, first)
+	}()
+
+	for i, stage := range stages {
+		var out chan T
+		if i < len(stages)-1 {
+			out = make(chan T, p.options.BufferSize)
+		}
+
+		p.startStage(ctx, &wg, stage, in, out)
+		in = out
+	}
+
+	wg.Wait()
+	if sourceErr != nil {
+		return sourceErr
+	}
+
+	return ctx.Err()
+}
+
+func (p *Pipeline[T]) startStage(ctx context.Context, wg *sync.WaitGroup, stage *pipelineStage[T], in <-chan T, out chan<- T) {
+	var workers sync.WaitGroup
+	workers.Add(stage.Workers)
+
+	for i := 0; i < stage.Workers; i++ {
+		go func() {
+			defer workers.Done()
+			for item := range in {
+				result, ok := p.process(ctx, stage, item)
+				if !ok || out == nil {
+					continue
+				}
+
+				select {
+				case out <- result:
+				case <-ctx.Done():
+				}
+			}
+		}()
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		workers.Wait()
+		if out != nil {
+			close(out)
+		}
+	}()
+}
+
+func (p *Pipeline[T]) process(ctx context.Context, stage *pipelineStage[T], item T) (T, bool) {
+	var (
+		result T
+		err    error
+		start  = time.Now()
+	)
+
+	for attempt := 0; attempt <= stage.Retries; attempt++ {
+		if ctx.Err() != nil {
+			return result, false
+		}
+
+		if attempt > 0 {
+			stage.retries.Add(1)
+			if !sleep(ctx, stage.RetryBackoff) {
+				return result, false
+			}
+		}
+
+		if result, err = stage.Process(ctx, item); err == nil {
+			break
+		}
+	}
+
+	stage.duration.Add(int64(time.Since(start)))
+	if err != nil {
+		stage.failed.Add(1)
+		p.fail(ctx, item, stage.Name, err)
+		return result, false
+	}
+
+	stage.processed.Add(1)
+	return result, true
+}
+
+func (p *Pipeline[T]) fail(ctx context.Context, item T, stage string, err error) {
+	if p.options.Logger != nil {
+		p.options.Logger.Error(ctx, "pipeline item failed", logger.String("pipeline.stage", stage), logger.Error(err))
+	}
+
+	if p.options.DeadLetter != nil {
+		p.options.DeadLetter(ctx, item, stage, err)
+	}
+}
+
+func sleep(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return true
+	}
+
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-t.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func (p *Pipeline[T]) allStages() []*pipelineStage[T] {
+	stages := make([]*pipelineStage[T], 0, len(p.stages)+1)
+	stages = append(stages, p.stages...)
+	return append(stages, p.sink)
+}
+
+// Stats returns the counters of every stage of the pipeline.
+func (p *Pipeline[T]) Stats() []StageStats {
+	stages := p.allStages()
+	stats := make([]StageStats, 0, len(stages))
+	for _, s := range stages {
+		stats = append(stats, StageStats{
+			Name:      s.Name,
+			Processed: s.processed.Load(),
+			Failed:    s.failed.Load(),
+			Retries:   s.retries.Load(),
+			Duration:  time.Duration(s.duration.Load()),
+		})
+	}
+
+	return stats
+}
@@ -0,0 +1,136 @@
+package concurrent
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func numbersSource(n int) Source[int] {
+	return func(ctx context.Context, out chan<- int) error {
+		for i := 1; i <= n; i++ {
+			select {
+			case out <- i:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		return nil
+	}
+}
+
+func TestPipeline(t *testing.T) {
+	t.Run("should process items through all stages", func(t *testing.T) {
+		var (
+			mu      sync.Mutex
+			results []int
+		)
+
+		p := NewPipeline(numbersSource(10), func(_ context.Context, item int) error {
+			mu.Lock()
+			results = append(results, item)
+			mu.Unlock()
+			return nil
+		})
+		p.AddStage(Stage[int]{
+			Name:    "double",
+			Workers: 3,
+			Process: func(_ context.Context, item int) (int, error) {
+				return item * 2, nil
+			},
+		}).AddStage(Stage[int]{
+			Name: "increment",
+			Process: func(_ context.Context, item int) (int, error) {
+				return item + 1, nil
+			},
+		})
+
+		require.NoError(t, p.Run(context.Background()))
+
+		sort.Ints(results)
+		assert.Equal(t, []int{3, 5, 7, 9, 11, 13, 15, 17, 19, 21}, results)
+
+		stats := p.Stats()
+		require.Len(t, stats, 3)
+		assert.Equal(t, "double", stats[0].Name)
+		assert.Equal(t, uint64(10), stats[0].Processed)
+		assert.Equal(t, "sink", stats[2].Name)
+		assert.Equal(t, uint64(10), stats[2].Processed)
+	})
+
+	t.Run("should retry and send failed items to the dead letter hook", func(t *testing.T) {
+		var (
+			mu       sync.Mutex
+			attempts = map[int]int{}
+			dead     []int
+			sunk     int
+		)
+
+		p := NewPipeline(numbersSource(4), func(_ context.Context, _ int) error {
+			mu.Lock()
+			sunk++
+			mu.Unlock()
+			return nil
+		}, PipelineOptions[int]{
+			DeadLetter: func(_ context.Context, item int, stage string, err error) {
+				assert.Equal(t, "validate", stage)
+				assert.EqualError(t, err, "odd")
+				dead = append(dead, item)
+			},
+		})
+		p.AddStage(Stage[int]{
+			Name:    "validate",
+			Retries: 2,
+			Process: func(_ context.Context, item int) (int, error) {
+				mu.Lock()
+				attempts[item]++
+				mu.Unlock()
+
+				if item%2 == 1 {
+					return 0, errors.New("odd")
+				}
+				return item, nil
+			},
+		})
+
+		require.NoError(t, p.Run(context.Background()))
+
+		assert.Equal(t, []int{1, 3}, dead)
+		assert.Equal(t, 2, sunk)
+		assert.Equal(t, 3, attempts[1])
+		assert.Equal(t, 1, attempts[2])
+
+		stats := p.Stats()
+		assert.Equal(t, uint64(2), stats[0].Failed)
+		assert.Equal(t, uint64(4), stats[0].Retries)
+	})
+
+	t.Run("should return the source error", func(t *testing.T) {
+		p := NewPipeline(func(_ context.Context, _ chan<- int) error {
+			return errors.New("source failed")
+		}, func(_ context.Context, _ int) error {
+			return nil
+		})
+
+		assert.EqualError(t, p.Run(context.Background()), "source failed")
+	})
+
+	t.Run("should stop when the context is canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+
+		p := NewPipeline(numbersSource(1000), func(_ context.Context, item int) error {
+			if item == 5 {
+				cancel()
+			}
+			return nil
+		})
+
+		assert.ErrorIs(t, p.Run(ctx), context.Canceled)
+	})
+}
@@ -0,0 +1,282 @@
+package concurrent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+
+	logger_api "github.com/mikros-dev/mikros/apis/features/logger"
+	"github.com/mikros-dev/mikros/components/logger"
+)
+
+var (
+	// ErrPoolStopped is returned when a task is submitted to a pool that was
+	// stopped.
+	ErrPoolStopped = errors.New("worker pool is stopped")
+
+	// ErrQueueFull is returned by TrySubmit when the pool queue has no room
+	// for another task.
+	ErrQueueFull = errors.New("worker pool queue is full")
+)
+
+// Task is a unit of work executed by a Pool. The context given to it is
+// canceled when the pool is stopped and its drain deadline is reached.
+type Task func(ctx context.Context) error
+
+// PoolOptions configures a Pool.
+type PoolOptions struct {
+	// Workers is the number of goroutines executing tasks. Defaults to
+	// GOMAXPROCS.
+	Workers int
+
+	// QueueSize is the number of tasks that can wait for a worker. Submit
+	// blocks when the queue is full. Defaults to Workers.
+	QueueSize int
+
+	// Logger, when set, receives an error message for every failed task.
+	Logger logger_api.API
+
+	// OnError, when set, is called with the error of every failed task,
+	// including panics, which are reported as PanicError.
+	OnError func(err error)
+}
+
+// PanicError is the error reported when a task panics.
+type PanicError struct {
+	Value interface{}
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("task panic: %v", e.Value)
+}
+
+// PoolStats gathers the pool counters.
+type PoolStats struct {
+	Workers   int
+	Queued    int
+	Running   int64
+	Completed uint64
+	Failed    uint64
+	Panics    uint64
+}
+
+// Pool executes tasks with a fixed number of goroutines, reading them from
+// a bounded queue. Task panics are recovered and reported as errors, so a
+// faulty task does not bring the service down.
+type Pool struct {
+	options PoolOptions
+	ctx     context.Context
+	cancel  context.CancelFunc
+	tasks   chan Task
+	quit    chan struct{}
+	wg      sync.WaitGroup
+	senders sync.WaitGroup
+
+	// mu guards stopped and the senders registration only; it is never held
+	// while waiting for room in the queue.
+	mu       sync.Mutex
+	stopped  bool
+	stopOnce sync.Once
+
+	running   atomic.Int64
+	completed atomic.Uint64
+	failed    atomic.Uint64
+	panics    atomic.Uint64
+}
+
+// NewPool creates a new Pool and starts its workers.
+func NewPool(options ...PoolOptions) *Pool {
+	var opts PoolOptions
+	if len(options) > 0 {
+		opts = options[0]
+	}
+	if opts.Workers <= 0 {
+		opts.Workers = runtime.GOMAXPROCS(0)
+	}
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = opts.Workers
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &Pool{
+		options: opts,
+		ctx:     ctx,
+		cancel:  cancel,
+		tasks:   make(chan Task, opts.QueueSize),
+		quit:    make(chan struct{}),
+	}
+
+	p.wg.Add(opts.Workers)
+	for i := 0; i < opts.Workers; i++ {
+		go p.work()
+	}
+
+	return p
+}
+
+func (p *Pool) work() {
+	defer p.wg.Done()
+
+	for task := range p.tasks {
+		p.run(task)
+	}
+}
+
+func (p *Pool) run(task Task) {
+	p.running.Add(1)
+	defer p.running.Add(-1)
+
+	if err := p.execute(task); err != nil {
+		p.failed.Add(1)
+		p.report(err)
+		return
+	}
+
+	p.completed.Add(1)
+}
+
+func (p *Pool) execute(task Task) error {
+	err := protect(func() error {
+		return task(p.ctx)
+	})
+
+	var panicErr *PanicError
+	if errors.As(err, &panicErr) {
+		p.panics.Add(1)
+	}
+
+	return err
+}
+
+// protect calls fn, returning a PanicError if it panics.
+func protect(fn func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &PanicError{
+				Value: r,
+				Stack: debug.Stack(),
+			}
+		}
+	}()
+
+	return fn()
+}
+
+func (p *Pool) report(err error) {
+	if p.options.OnError != nil {
+		p.options.OnError(err)
+	}
+
+	if p.options.Logger != nil {
+		p.options.Logger.Error(p.ctx, "worker pool task failed", logger.Error(err))
+	}
+}
+
+// Submit adds a task into the pool queue, waiting for room in it while ctx
+// is not done. Submit returns ErrPoolStopped if the pool is stopped while it
+// waits.
+func (p *Pool) Submit(ctx context.Context, task Task) error {
+	if !p.enter() {
+		return ErrPoolStopped
+	}
+	defer p.senders.Done()
+
+	// Prefers queueing the task when there is room for it, even if ctx is
+	// already done.
+	select {
+	case p.tasks <- task:
+		return nil
+	default:
+	}
+
+	select {
+	case p.tasks <- task:
+		return nil
+	case <-p.quit:
+		return ErrPoolStopped
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// TrySubmit adds a task into the pool queue without waiting, returning
+// ErrQueueFull when there is no room for it.
+func (p *Pool) TrySubmit(task Task) error {
+	if !p.enter() {
+		return ErrPoolStopped
+	}
+	defer p.senders.Done()
+
+	select {
+	case p.tasks <- task:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+// enter registers a sender, so the queue is not closed while it sends a
+// task, returning false if the pool is stopped.
+func (p *Pool) enter() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.stopped {
+		return false
+	}
+
+	p.senders.Add(1)
+	return true
+}
+
+// Stop stops accepting tasks and waits until the queued ones are executed.
+// Submit calls waiting for room in the queue return ErrPoolStopped. If ctx
+// is done before the queue is drained, the context of the running tasks is
+// canceled and ctx error is returned.
+func (p *Pool) Stop(ctx context.Context) error {
+	p.stopOnce.Do(func() {
+		p.mu.Lock()
+		p.stopped = true
+		p.mu.Unlock()
+		close(p.quit)
+
+		// The queue is closed, letting the workers finish, once no sender
+		// can write to it anymore.
+		go func() {
+			p.senders.Wait()
+			close(p.tasks)
+		}()
+	})
+
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		p.cancel()
+		return nil
+	case <-ctx.Done():
+		p.cancel()
+		return ctx.Err()
+	}
+}
+
+// Stats returns the pool counters.
+func (p *Pool) Stats() PoolStats {
+	return PoolStats{
+		Workers:   p.options.Workers,
+		Queued:    len(p.tasks),
+		Running:   p.running.Load(),
+		Completed: p.completed.Load(),
+		Failed:    p.failed.Load(),
+		Panics:    p.panics.Load(),
+	}
+}
@@ -0,0 +1,155 @@
+package concurrent
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPool(t *testing.T) {
+	t.Run("should execute all submitted tasks before stopping", func(t *testing.T) {
+		var (
+			executed atomic.Int32
+			p        = NewPool(PoolOptions{Workers: 4, QueueSize: 2})
+		)
+
+		for i := 0; i < 50; i++ {
+			require.NoError(t, p.Submit(context.Background(), func(_ context.Context) error {
+				executed.Add(1)
+				return nil
+			}))
+		}
+
+		require.NoError(t, p.Stop(context.Background()))
+		assert.Equal(t, int32(50), executed.Load())
+		assert.Equal(t, uint64(50), p.Stats().Completed)
+	})
+
+	t.Run("should report errors and recover panics", func(t *testing.T) {
+		var (
+			mu   sync.Mutex
+			errs []error
+			p    = NewPool(PoolOptions{
+				Workers: 1,
+				OnError: func(err error) {
+					mu.Lock()
+					errs = append(errs, err)
+					mu.Unlock()
+				},
+			})
+		)
+
+		require.NoError(t, p.Submit(context.Background(), func(_ context.Context) error {
+			return errors.New("boom")
+		}))
+		require.NoError(t, p.Submit(context.Background(), func(_ context.Context) error {
+			panic("crash")
+		}))
+		require.NoError(t, p.Stop(context.Background()))
+
+		require.Len(t, errs, 2)
+		assert.EqualError(t, errs[0], "boom")
+
+		var panicErr *PanicError
+		require.ErrorAs(t, errs[1], &panicErr)
+		assert.Equal(t, "crash", panicErr.Value)
+		assert.NotEmpty(t, panicErr.Stack)
+
+		stats := p.Stats()
+		assert.Equal(t, uint64(2), stats.Failed)
+		assert.Equal(t, uint64(1), stats.Panics)
+	})
+
+	t.Run("should reject tasks when full or stopped", func(t *testing.T) {
+		var (
+			release = make(chan struct{})
+			started = make(chan struct{})
+			p       = NewPool(PoolOptions{Workers: 1, QueueSize: 1})
+		)
+
+		require.NoError(t, p.TrySubmit(func(_ context.Context) error {
+			close(started)
+			<-release
+			return nil
+		}))
+		<-started
+		require.NoError(t, p.TrySubmit(func(_ context.Context) error { return nil }))
+		assert.ErrorIs(t, p.TrySubmit(func(_ context.Context) error { return nil }), ErrQueueFull)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		assert.ErrorIs(t, p.Submit(ctx, func(_ context.Context) error { return nil }), context.DeadlineExceeded)
+
+		close(release)
+		require.NoError(t, p.Stop(context.Background()))
+		assert.ErrorIs(t, p.Submit(context.Background(), func(_ context.Context) error { return nil }), ErrPoolStopped)
+	})
+
+	t.Run("should cancel running tasks when the drain deadline is reached", func(t *testing.T) {
+		var (
+			canceled = make(chan struct{})
+			started  = make(chan struct{})
+			p        = NewPool(PoolOptions{Workers: 1})
+		)
+
+		require.NoError(t, p.Submit(context.Background(), func(ctx context.Context) error {
+			close(started)
+			<-ctx.Done()
+			close(canceled)
+			return ctx.Err()
+		}))
+		<-started
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		assert.ErrorIs(t, p.Stop(ctx), context.DeadlineExceeded)
+
+		select {
+		case <-canceled:
+		case <-time.After(time.Second):
+			t.Fatal("running task was not canceled")
+		}
+	})
+
+	t.Run("should not block Stop while a submit waits for room", func(t *testing.T) {
+		var (
+			release = make(chan struct{})
+			started = make(chan struct{})
+			p       = NewPool(PoolOptions{Workers: 1, QueueSize: 1})
+		)
+
+		require.NoError(t, p.Submit(context.Background(), func(_ context.Context) error {
+			close(started)
+			<-release
+			return nil
+		}))
+		<-started
+		require.NoError(t, p.Submit(context.Background(), func(_ context.Context) error { return nil }))
+
+		submitted := make(chan error, 1)
+		go func() {
+			submitted <- p.Submit(context.Background(), func(_ context.Context) error { return nil })
+		}()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		assert.ErrorIs(t, p.Stop(ctx), context.DeadlineExceeded)
+
+		select {
+		case err := <-submitted:
+			assert.ErrorIs(t, err, ErrPoolStopped)
+		case <-time.After(time.Second):
+			t.Fatal("waiting submit was not released by Stop")
+		}
+
+		close(release)
+		require.NoError(t, p.Stop(context.Background()))
+		assert.Equal(t, uint64(2), p.Stats().Completed)
+	})
+}
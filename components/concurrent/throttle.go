@@ -0,0 +1,142 @@
+package concurrent
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrThrottlerStopped is returned when waiting on a throttler that was
+	// stopped.
+	ErrThrottlerStopped = errors.New("throttler is stopped")
+)
+
+// ThrottleOptions configures a Throttler.
+type ThrottleOptions struct {
+	// Burst is the number of events allowed at once, before they start being
+	// spaced by the throttler interval. Defaults to 1.
+	Burst int
+}
+
+// Throttler limits the rate of events to one per interval, allowing bursts of
+// up to ThrottleOptions.Burst events.
+type Throttler struct {
+	interval time.Duration
+	burst    float64
+	stopped  chan struct{}
+	stop     sync.Once
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// NewThrottler creates a new Throttler allowing one event per interval.
+func NewThrottler(interval time.Duration, options ...ThrottleOptions) *Throttler {
+	var opts ThrottleOptions
+	if len(options) > 0 {
+		opts = options[0]
+	}
+	if opts.Burst <= 0 {
+		opts.Burst = 1
+	}
+
+	return &Throttler{
+		interval: interval,
+		burst:    float64(opts.Burst),
+		stopped:  make(chan struct{}),
+		tokens:   float64(opts.Burst),
+		last:     time.Now(),
+	}
+}
+
+// refill must be called with mu held.
+func (t *Throttler) refill(now time.Time) {
+	if t.interval <= 0 {
+		t.tokens = t.burst
+		return
+	}
+
+	t.tokens = min(t.burst, t.tokens+float64(now.Sub(t.last))/float64(t.interval))
+	t.last = now
+}
+
+// Allow reports whether an event can happen now, consuming it if so. It is
+// meant for handlers that drop events exceeding the rate.
+func (t *Throttler) Allow() bool {
+	if t.isStopped() {
+		return false
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.refill(time.Now())
+	if t.tokens < 1 {
+		return false
+	}
+
+	t.tokens--
+	return true
+}
+
+// Wait blocks until an event can happen, ctx is done or the throttler is
+// stopped.
+func (t *Throttler) Wait(ctx context.Context) error {
+	if t.isStopped() {
+		return ErrThrottlerStopped
+	}
+
+	t.mu.Lock()
+	t.refill(time.Now())
+	t.tokens--
+	delay := time.Duration(0)
+	if t.tokens < 0 {
+		delay = time.Duration(-t.tokens * float64(t.interval))
+	}
+	t.mu.Unlock()
+
+	if delay == 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		t.release()
+		return ctx.Err()
+	case <-t.stopped:
+		t.release()
+		return ErrThrottlerStopped
+	}
+}
+
+func (t *Throttler) release() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.tokens = min(t.burst, t.tokens+1)
+}
+
+func (t *Throttler) isStopped() bool {
+	select {
+	case <-t.stopped:
+		return true
+	default:
+		return false
+	}
+}
+
+// Stop releases all waiting callers with ErrThrottlerStopped and makes
+// further events not allowed.
+func (t *Throttler) Stop() {
+	t.stop.Do(func() {
+		close(t.stopped)
+	})
+}
@@ -0,0 +1,59 @@
+package concurrent
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestThrottler(t *testing.T) {
+	t.Run("should allow bursts and drop exceeding events", func(t *testing.T) {
+		th := NewThrottler(time.Hour, ThrottleOptions{Burst: 2})
+
+		assert.True(t, th.Allow())
+		assert.True(t, th.Allow())
+		assert.False(t, th.Allow())
+	})
+
+	t.Run("should space waiting events by the interval", func(t *testing.T) {
+		var (
+			th    = NewThrottler(20 * time.Millisecond)
+			start = time.Now()
+		)
+
+		for i := 0; i < 3; i++ {
+			require.NoError(t, th.Wait(context.Background()))
+		}
+
+		assert.GreaterOrEqual(t, time.Since(start), 35*time.Millisecond)
+	})
+
+	t.Run("should stop waiting when the context is done", func(t *testing.T) {
+		th := NewThrottler(time.Hour)
+		require.NoError(t, th.Wait(context.Background()))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		assert.ErrorIs(t, th.Wait(ctx), context.DeadlineExceeded)
+	})
+
+	t.Run("should release waiting callers when stopped", func(t *testing.T) {
+		th := NewThrottler(time.Hour)
+		require.NoError(t, th.Wait(context.Background()))
+
+		errs := make(chan error)
+		go func() {
+			errs <- th.Wait(context.Background())
+		}()
+
+		time.Sleep(10 * time.Millisecond)
+		th.Stop()
+
+		assert.ErrorIs(t, <-errs, ErrThrottlerStopped)
+		assert.False(t, th.Allow())
+	})
+}
@@ -0,0 +1,52 @@
+package context
+
+import (
+	"context"
+)
+
+type handlerInfoKey struct{}
+
+// HandlerInfoKey is the key under which the handler information is stored
+// inside a context. Runtimes using contexts that don't support
+// context.WithValue, such as fasthttp.RequestCtx, can store the information
+// directly as a user value with it.
+var HandlerInfoKey = handlerInfoKey{}
+
+// HandlerInfo describes the handler executing the current request.
+type HandlerInfo struct {
+	// Name is the handler name, such as the RPC method name.
+	Name string
+
+	// Route is the template of the route that matched the request, for
+	// example "GET /users/{id}" or "/service.UserService/GetUser".
+	Route string
+}
+
+// WithHandlerInfo returns a copy of ctx carrying the handler information.
+func WithHandlerInfo(ctx context.Context, info HandlerInfo) context.Context {
+	return context.WithValue(ctx, HandlerInfoKey, info)
+}
+
+// WithHandlerInfoFunc returns a copy of ctx carrying a function that gives
+// the handler information. It allows runtimes to store it before the
+// request is routed, when the handler is not known yet.
+func WithHandlerInfoFunc(ctx context.Context, fn func() HandlerInfo) context.Context {
+	return context.WithValue(ctx, HandlerInfoKey, fn)
+}
+
+// HandlerInfoFromContext retrieves the handler information from ctx.
+func HandlerInfoFromContext(ctx context.Context) (HandlerInfo, bool) {
+	if ctx == nil {
+		return HandlerInfo{}, false
+	}
+
+	switch v := ctx.Value(HandlerInfoKey).(type) {
+	case HandlerInfo:
+		return v, true
+	case func() HandlerInfo:
+		info := v()
+		return info, info != HandlerInfo{}
+	}
+
+	return HandlerInfo{}, false
+}
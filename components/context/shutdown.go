@@ -0,0 +1,26 @@
+package context
+
+import (
+	"context"
+)
+
+type shutdownNoticeKey struct{}
+
+// WithShutdownNotice returns a copy of ctx carrying a channel that runtimes
+// close when their server starts shutting down.
+func WithShutdownNotice(ctx context.Context, notice <-chan struct{}) context.Context {
+	return context.WithValue(ctx, shutdownNoticeKey{}, notice)
+}
+
+// ShutdownNotice returns the channel closed when the server handling the
+// request of ctx starts shutting down. Long-lived requests, such as streams,
+// should end when it is closed, before the runtime cancels them. It returns
+// nil, which blocks forever when received from, for contexts without it.
+func ShutdownNotice(ctx context.Context) <-chan struct{} {
+	if ctx == nil {
+		return nil
+	}
+
+	notice, _ := ctx.Value(shutdownNoticeKey{}).(<-chan struct{})
+	return notice
+}
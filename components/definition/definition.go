@@ -3,10 +3,13 @@ package definition
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/BurntSushi/toml"
 	"github.com/creasty/defaults"
@@ -31,6 +34,11 @@ type Definitions struct {
 	Features Features                          `toml:"features,omitempty"`
 	Log      Log                               `toml:"log,omitempty"`
 	Tests    Tests                             `toml:"tests,omitempty"`
+	Limits   Limits                            `toml:"limits,omitempty"`
+	Budget   DownstreamBudget                  `toml:"downstream_budget,omitempty"`
+	Crash    CrashReport                       `toml:"crash_report,omitempty"`
+	Listen   Listen                            `toml:"listen,omitempty"`
+	Health   Health                            `toml:"health,omitempty"`
 	Service  map[string]interface{}            `toml:"service,omitempty"`
 	Clients  map[string]GrpcClient             `toml:"clients,omitempty"`
 	Runtime  map[string]map[string]interface{} `toml:"runtime,omitempty"`
@@ -38,6 +46,7 @@ type Definitions struct {
 	path                  string
 	supportedRuntimeTypes []string
 	externalRuntimes      map[string]ExternalRuntimeEntry
+	namingPolicies        []NamingPolicy
 }
 
 // Log represents configuration settings for logging in a service.
@@ -45,6 +54,61 @@ type Log struct {
 	ErrorStackTrace string            `toml:"error_stack_trace,omitempty" validate:"omitempty,oneof=default disabled structured" default:"disabled"`
 	Level           string            `toml:"level,omitempty" validate:"omitempty,oneof=info debug error warn internal"`
 	Attributes      map[string]string `toml:"attributes,omitempty"`
+
+	// FeatureUsageInterval enables a periodic report of enabled features
+	// that were never requested by the service, besides the one made when
+	// it stops.
+	FeatureUsageInterval time.Duration `toml:"feature_usage_interval,omitempty"`
+
+	// SlowCallThreshold enables a warning for every call to a coupled gRPC
+	// client that takes longer than it.
+	SlowCallThreshold time.Duration `toml:"slow_call_threshold,omitempty"`
+
+	// RecentRecords is the number of the last log records, of any level,
+	// kept in memory. They are flushed when the service crashes and can be
+	// inspected with the service LogTailHandler.
+	RecentRecords int `toml:"recent_records,omitempty" validate:"gte=0"`
+
+	// RequestBuffer is the number of messages suppressed by the log level
+	// kept for every request being handled, keyed by its tracker ID. They are
+	// output when the request fails or is sampled by the tracing integration.
+	RequestBuffer int `toml:"request_buffer,omitempty" validate:"gte=0"`
+
+	// OTLP enables exporting log messages to an OpenTelemetry collector, in
+	// addition to the standard output.
+	OTLP *OTLPLogs `toml:"otlp,omitempty"`
+}
+
+// OTLPLogs gathers settings to export log messages to an OpenTelemetry
+// collector using OTLP/HTTP.
+type OTLPLogs struct {
+	// Endpoint is the collector base URL, e.g. "http://collector:4318".
+	Endpoint string `toml:"endpoint,omitempty" validate:"required,url"`
+
+	// Headers are added into every export request.
+	Headers map[string]string `toml:"headers,omitempty"`
+
+	// BatchSize is the maximum number of messages sent by export request.
+	BatchSize int `toml:"batch_size,omitempty" validate:"gte=0"`
+
+	// FlushInterval is the maximum time messages wait to be exported.
+	FlushInterval time.Duration `toml:"flush_interval,omitempty" validate:"gte=0"`
+
+	// Timeout limits every export request.
+	Timeout time.Duration `toml:"timeout,omitempty" validate:"gte=0"`
+}
+
+// DownstreamBudget limits the calls that a single request handled by the
+// service can make to its coupled clients.
+type DownstreamBudget struct {
+	// MaxCalls is the maximum number of calls per request.
+	MaxCalls int `toml:"max_calls,omitempty" validate:"gte=0"`
+
+	// MaxDuration is the maximum cumulative time spent on calls per request.
+	MaxDuration time.Duration `toml:"max_duration,omitempty" validate:"gte=0"`
+
+	// Enforce makes calls above the budget fail instead of only being logged.
+	Enforce bool `toml:"enforce,omitempty"`
 }
 
 // GrpcClient defines the configuration settings for a gRPC coupled client.
@@ -82,6 +146,86 @@ type ExternalRuntimeEntry interface {
 	Validate() error
 }
 
+// Limits gathers settings to adjust the Go runtime (GOMAXPROCS and GOMEMLIMIT)
+// to the container CPU and memory limits, which are detected automatically
+// when the service starts.
+type Limits struct {
+	Disabled         bool    `toml:"disabled,omitempty"`
+	MaxProcs         int     `toml:"max_procs,omitempty" validate:"gte=0"`
+	MemoryLimit      int64   `toml:"memory_limit,omitempty" validate:"gte=0"`
+	MemoryLimitRatio float64 `toml:"memory_limit_ratio,omitempty" validate:"gte=0,lte=1" default:"0.9"`
+}
+
+// CrashReport gathers settings of the structured crash reports written when
+// the service aborts or panics. Reports are only written when Path is set.
+type CrashReport struct {
+	// Path is the directory where reports are written.
+	Path string `toml:"path,omitempty"`
+
+	// LogRecords is the number of the last log records added to reports.
+	LogRecords int `toml:"log_records,omitempty" validate:"gte=0" default:"100"`
+}
+
+// Listen gathers settings of the addresses that runtime servers listen to.
+// By default, they listen to all interfaces, over both IPv4 and IPv6.
+type Listen struct {
+	// Host is the address, or host name, of the interface servers listen
+	// to, e.g. "127.0.0.1" to accept only local connections.
+	Host string `toml:"host,omitempty" validate:"omitempty,ip|hostname_rfc1123"`
+
+	// Network restricts servers to IPv4 ("tcp4") or IPv6 ("tcp6")
+	// connections. Defaults to both ("tcp").
+	Network string `toml:"network,omitempty" validate:"omitempty,oneof=tcp tcp4 tcp6"`
+
+	// Hosts overrides Host for specific runtimes, e.g. to keep an
+	// administrative HTTP server local while gRPC is public.
+	Hosts map[string]string `toml:"hosts,omitempty" validate:"dive,ip|hostname_rfc1123"`
+
+	// ProxyProtocol enables parsing the PROXY protocol header sent by L4
+	// load balancers, so servers see the addresses of the real clients.
+	ProxyProtocol ProxyProtocol `toml:"proxy_protocol,omitempty"`
+}
+
+// ProxyProtocol gathers settings of the PROXY protocol, versions 1 and 2,
+// on runtime server listeners.
+type ProxyProtocol struct {
+	Enabled bool `toml:"enabled,omitempty"`
+
+	// TrustedProxies are the networks, in CIDR notation, of the load
+	// balancers. Connections from other peers are used as they are, so
+	// clients reaching the port directly cannot spoof their addresses. It
+	// is required when the protocol is enabled.
+	TrustedProxies []string `toml:"trusted_proxies,omitempty" validate:"dive,cidr"`
+
+	// HeaderTimeout is the maximum time to wait for the header of a
+	// connection. Defaults to 5 seconds.
+	HeaderTimeout time.Duration `toml:"header_timeout,omitempty" validate:"gte=0"`
+}
+
+// RuntimeHost returns the host that the server of a runtime listens to.
+func (l Listen) RuntimeHost(runtimeType RuntimeType) string {
+	if host, ok := l.Hosts[runtimeType.String()]; ok {
+		return host
+	}
+
+	return l.Host
+}
+
+// Health gathers settings of the health checks of the service features.
+type Health struct {
+	// CheckInterval enables periodic health checks of the features, so
+	// their state transitions are logged even when nobody asks for them.
+	CheckInterval time.Duration `toml:"check_interval,omitempty" validate:"gte=0"`
+
+	// Timeout limits how long a feature has to report its health, being
+	// unhealthy when it doesn't. Defaults to 5 seconds.
+	Timeout time.Duration `toml:"timeout,omitempty" validate:"gte=0"`
+
+	// NonCritical lists features whose unhealthy state only degrades the
+	// service instead of making it not ready, e.g. a cache.
+	NonCritical []string `toml:"non_critical,omitempty"`
+}
+
 // Tests gathers unit tests related options.
 type Tests struct {
 	ExecuteLifecycle   bool  `toml:"execute_lifecycle,omitempty"`
@@ -107,7 +251,8 @@ func New() (*Definitions, error) {
 // Validate validates if all data loaded from the service definitions is
 // correct.
 //
-// It also validates external services and external features custom definitions.
+// It also validates naming policies, external services and external features
+// custom definitions.
 func (d *Definitions) Validate() error {
 	validate := validator.New()
 
@@ -134,6 +279,16 @@ func (d *Definitions) Validate() error {
 		return err
 	}
 
+	if d.Listen.ProxyProtocol.Enabled && len(d.Listen.ProxyProtocol.TrustedProxies) == 0 {
+		return errors.New("listen.proxy_protocol.trusted_proxies must be set when the PROXY protocol is enabled")
+	}
+
+	for _, policy := range d.namingPolicies {
+		if err := policy.validate(d); err != nil {
+			return err
+		}
+	}
+
 	for _, svc := range d.externalRuntimes {
 		if err := svc.Validate(); err != nil {
 			return err
@@ -227,6 +382,18 @@ func (d *Definitions) ExternalFeatureDefinitions(name string) (ExternalFeatureEn
 	return v, nil
 }
 
+// ExternalFeatureNames returns, sorted, the names of the external features
+// whose definitions were added.
+func (d *Definitions) ExternalFeatureNames() []string {
+	names := make([]string, 0, len(d.Features.externalFeatures))
+	for name := range d.Features.externalFeatures {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
 // AddExternalRuntimeDefinitions adds definitions from external runtime into
 // the Definitions object.
 func (d *Definitions) AddExternalRuntimeDefinitions(name string, defs ExternalRuntimeEntry) {
@@ -3,6 +3,7 @@ package definition
 import (
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -180,6 +181,98 @@ port = 9192
 				a.Equal(1, len(defs.Clients))
 			},
 		},
+		{
+			Title: "succeed with listen settings",
+			TomlDefinitions: `
+name = "service_test"
+types = ["grpc", "http"]
+version = "v0.1.0"
+language = "go"
+product = "SDS"
+
+[listen]
+host = "0.0.0.0"
+network = "tcp4"
+
+[listen.hosts]
+http = "localhost"
+
+[listen.proxy_protocol]
+enabled = true
+trusted_proxies = ["10.0.0.0/8"]
+`,
+			DefsAssertion:  a.NotNil,
+			ErrorAssertion: a.NoError,
+			CustomAssertion: func(defs *Definitions) {
+				a.Equal("tcp4", defs.Listen.Network)
+				a.Equal("0.0.0.0", defs.Listen.RuntimeHost(RuntimeTypeGRPC))
+				a.Equal("localhost", defs.Listen.RuntimeHost(RuntimeTypeHTTP))
+				a.True(defs.Listen.ProxyProtocol.Enabled)
+				a.Equal([]string{"10.0.0.0/8"}, defs.Listen.ProxyProtocol.TrustedProxies)
+			},
+		},
+		{
+			Title: "succeed with health settings",
+			TomlDefinitions: `
+name = "service_test"
+types = ["grpc"]
+version = "v0.1.0"
+language = "go"
+product = "SDS"
+
+[health]
+check_interval = "30s"
+non_critical = ["cache"]
+`,
+			DefsAssertion:  a.NotNil,
+			ErrorAssertion: a.NoError,
+			CustomAssertion: func(defs *Definitions) {
+				a.Equal(30*time.Second, defs.Health.CheckInterval)
+				a.Equal([]string{"cache"}, defs.Health.NonCritical)
+			},
+		},
+		{
+			Title: "should fail with invalid listen settings",
+			TomlDefinitions: `
+name = "service_test"
+types = ["grpc"]
+version = "v0.1.0"
+language = "go"
+product = "SDS"
+
+[listen]
+host = "not a host"
+network = "udp"
+
+[listen.proxy_protocol]
+enabled = true
+trusted_proxies = ["10.0.0.1"]
+`,
+			ErrorAssertion: a.Error,
+			Expected: []string{
+				"'Definitions.Listen.ProxyProtocol.TrustedProxies[0]' Error:Field validation for 'TrustedProxies[0]' failed on the 'cidr' tag",
+				"'Definitions.Listen.Host' Error:Field validation for 'Host' failed",
+				"'Definitions.Listen.Network' Error:Field validation for 'Network' failed on the 'oneof' tag",
+			},
+		},
+		{
+			Title: "should fail with PROXY protocol without trusted proxies",
+			TomlDefinitions: `
+name = "service_test"
+types = ["grpc"]
+version = "v0.1.0"
+language = "go"
+product = "SDS"
+
+[listen.proxy_protocol]
+enabled = true
+trusted_proxies = []
+`,
+			ErrorAssertion: a.Error,
+			Expected: []string{
+				"listen.proxy_protocol.trusted_proxies must be set",
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -0,0 +1,142 @@
+package definition
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	// definitionsFileNames are the names of the definitions file searched
+	// in the service directory, by preference.
+	definitionsFileNames = []string{
+		"service.toml",
+		"service.yaml",
+		"service.yml",
+		"service.json",
+	}
+)
+
+// findDefinitionsFile returns the first definitions file found in dir, or
+// the path of 'service.toml' when there is none.
+func findDefinitionsFile(dir string) string {
+	for _, name := range definitionsFileNames {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+
+	return filepath.Join(dir, definitionsFileNames[0])
+}
+
+// decodeFile decodes a definitions file into target using its 'toml' tags,
+// whatever the file format is. YAML and JSON files are converted to TOML
+// before being decoded, so features and runtimes loading their own settings
+// support them without changes. ${VAR} references in string values are
+// replaced by environment variables.
+func decodeFile(path string, target interface{}) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var data interface{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		if err := yaml.Unmarshal(content, &data); err != nil {
+			return fmt.Errorf("could not parse YAML definitions: %w", err)
+		}
+	case ".json":
+		dec := json.NewDecoder(bytes.NewReader(content))
+		dec.UseNumber()
+		if err := dec.Decode(&data); err != nil {
+			return fmt.Errorf("could not parse JSON definitions: %w", err)
+		}
+	default:
+		if !bytes.Contains(content, []byte("${")) {
+			_, err := toml.Decode(string(content), target)
+			return err
+		}
+
+		var table map[string]interface{}
+		if _, err := toml.Decode(string(content), &table); err != nil {
+			return err
+		}
+		data = table
+	}
+
+	table, ok := normalizeValue(data).(map[string]interface{})
+	if !ok {
+		if data == nil {
+			// Empty files have no definitions.
+			table = map[string]interface{}{}
+		} else {
+			return fmt.Errorf("definitions file '%s' must contain an object", path)
+		}
+	}
+
+	if err := interpolateEnv(table, os.LookupEnv); err != nil {
+		return fmt.Errorf("definitions file '%s': %w", path, err)
+	}
+
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(table); err != nil {
+		return err
+	}
+
+	_, err = toml.Decode(buf.String(), target)
+	return err
+}
+
+// normalizeValue converts YAML and JSON values into the ones supported by
+// the TOML encoder: JSON numbers become integers when they have no fraction,
+// null values are dropped and map keys become strings.
+func normalizeValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			if item != nil {
+				out[k] = normalizeValue(item)
+			}
+		}
+
+		return out
+	case map[interface{}]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			if item != nil {
+				out[fmt.Sprint(k)] = normalizeValue(item)
+			}
+		}
+
+		return out
+	case []interface{}:
+		out := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			if item != nil {
+				out = append(out, normalizeValue(item))
+			}
+		}
+
+		return out
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return n
+		}
+		if f, err := v.Float64(); err == nil {
+			return f
+		}
+
+		return v.String()
+	default:
+		return v
+	}
+}
@@ -0,0 +1,153 @@
+package definition
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeDefinitionsFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	return path
+}
+
+func TestParseFormats(t *testing.T) {
+	files := map[string]string{
+		"service.toml": `
+name = "orders"
+types = ["grpc", "http:8080"]
+version = "v1.0.0"
+language = "go"
+product = "store"
+
+[log]
+level = "debug"
+recent_records = 50
+slow_call_threshold = "250ms"
+
+[limits]
+memory_limit_ratio = 0.75
+
+[runtime.http]
+read_timeout = "5s"
+`,
+		"service.yaml": `
+name: orders
+types: [grpc, "http:8080"]
+version: v1.0.0
+language: go
+product: store
+log:
+  level: debug
+  recent_records: 50
+  slow_call_threshold: 250ms
+  otlp: null
+limits:
+  memory_limit_ratio: 0.75
+runtime:
+  http:
+    read_timeout: 5s
+`,
+		"service.json": `{
+  "name": "orders",
+  "types": ["grpc", "http:8080"],
+  "version": "v1.0.0",
+  "language": "go",
+  "product": "store",
+  "log": {"level": "debug", "recent_records": 50, "slow_call_threshold": "250ms"},
+  "limits": {"memory_limit_ratio": 0.75},
+  "runtime": {"http": {"read_timeout": "5s"}}
+}`,
+	}
+
+	for name, content := range files {
+		t.Run("should load "+name, func(t *testing.T) {
+			path := writeDefinitionsFile(t, t.TempDir(), name, content)
+
+			defs, err := ParseFromFile(path)
+			require.NoError(t, err)
+			require.NoError(t, defs.Validate())
+
+			assert.Equal(t, "orders", defs.Name)
+			assert.Equal(t, []string{"grpc", "http:8080"}, defs.Types)
+			assert.Equal(t, "debug", defs.Log.Level)
+			assert.Equal(t, 50, defs.Log.RecentRecords)
+			assert.Equal(t, 250*time.Millisecond, defs.Log.SlowCallThreshold)
+			assert.Nil(t, defs.Log.OTLP)
+			assert.Equal(t, 0.75, defs.Limits.MemoryLimitRatio)
+			assert.Equal(t, "disabled", defs.Log.ErrorStackTrace)
+			assert.Equal(t, path, defs.Path())
+
+			settings, ok := defs.LoadRuntime(RuntimeTypeHTTP)
+			require.True(t, ok)
+			assert.Equal(t, "5s", settings["read_timeout"])
+
+			var external struct {
+				Log struct {
+					Level string `toml:"level"`
+				} `toml:"log"`
+			}
+			require.NoError(t, ParseExternalDefinitions(path, &external))
+			assert.Equal(t, "debug", external.Log.Level)
+		})
+	}
+
+	t.Run("should validate definitions of any format", func(t *testing.T) {
+		path := writeDefinitionsFile(t, t.TempDir(), "service.yaml", `
+name: orders
+types: [unsupported]
+version: v1.0.0
+language: go
+product: store
+`)
+
+		defs, err := ParseFromFile(path)
+		require.NoError(t, err)
+		assert.Error(t, defs.Validate())
+	})
+
+	t.Run("should fail with invalid files", func(t *testing.T) {
+		dir := t.TempDir()
+
+		_, err := ParseFromFile(writeDefinitionsFile(t, dir, "service.json", `["orders"]`))
+		assert.Error(t, err)
+
+		_, err = ParseFromFile(writeDefinitionsFile(t, dir, "service.yml", "name: [orders"))
+		assert.Error(t, err)
+
+		_, err = ParseFromFile(writeDefinitionsFile(t, dir, "broken.json", `{"name": 1.5}`))
+		assert.Error(t, err)
+	})
+}
+
+func TestFindDefinitionsFile(t *testing.T) {
+	t.Run("should prefer TOML files", func(t *testing.T) {
+		dir := t.TempDir()
+		writeDefinitionsFile(t, dir, "service.json", "{}")
+		writeDefinitionsFile(t, dir, "service.toml", "")
+
+		assert.Equal(t, filepath.Join(dir, "service.toml"), findDefinitionsFile(dir))
+	})
+
+	t.Run("should find YAML and JSON files", func(t *testing.T) {
+		dir := t.TempDir()
+		writeDefinitionsFile(t, dir, "service.json", "{}")
+		assert.Equal(t, filepath.Join(dir, "service.json"), findDefinitionsFile(dir))
+
+		writeDefinitionsFile(t, dir, "service.yaml", "")
+		assert.Equal(t, filepath.Join(dir, "service.yaml"), findDefinitionsFile(dir))
+	})
+
+	t.Run("should default to service.toml", func(t *testing.T) {
+		dir := t.TempDir()
+		assert.Equal(t, filepath.Join(dir, "service.toml"), findDefinitionsFile(dir))
+	})
+}
@@ -0,0 +1,108 @@
+package definition
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mikros-dev/mikros/internal/components/expand"
+)
+
+// interpolateEnv replaces ${VAR} references inside the string values of a
+// decoded definitions file with the environment variables given by lookup.
+// A ${VAR:-fallback} reference uses fallback when VAR is not set or is
+// empty, and $${ is kept as a literal ${. All variables that are not set,
+// and have no fallback, are reported in the same error.
+func interpolateEnv(table map[string]interface{}, lookup func(name string) (string, bool)) error {
+	var (
+		missing = make(map[string][]string)
+		err     = interpolateTable("", table, lookup, missing)
+	)
+	if err != nil {
+		return err
+	}
+
+	if len(missing) == 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(missing))
+	for name := range missing {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	details := make([]string, 0, len(names))
+	for _, name := range names {
+		keys := missing[name]
+		sort.Strings(keys)
+		details = append(details, fmt.Sprintf("%s (%s)", name, strings.Join(keys, ", ")))
+	}
+
+	return fmt.Errorf("definitions reference environment variables that are not set: %s", strings.Join(details, "; "))
+}
+
+func interpolateTable(
+	prefix string,
+	table map[string]interface{},
+	lookup func(name string) (string, bool),
+	missing map[string][]string,
+) error {
+	for key, value := range table {
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+
+		v, err := interpolateItem(path, value, lookup, missing)
+		if err != nil {
+			return err
+		}
+
+		table[key] = v
+	}
+
+	return nil
+}
+
+func interpolateItem(
+	path string,
+	value interface{},
+	lookup func(name string) (string, bool),
+	missing map[string][]string,
+) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		expanded, err := expand.Value(v, lookup, func(name string) {
+			missing[name] = append(missing[name], path)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("could not expand '%s': %w", path, err)
+		}
+
+		return expanded, nil
+	case map[string]interface{}:
+		return v, interpolateTable(path, v, lookup, missing)
+	case []interface{}:
+		for i, item := range v {
+			expanded, err := interpolateItem(fmt.Sprintf("%s[%d]", path, i), item, lookup, missing)
+			if err != nil {
+				return nil, err
+			}
+
+			v[i] = expanded
+		}
+
+		return v, nil
+	case []map[string]interface{}:
+		for i, item := range v {
+			if err := interpolateTable(fmt.Sprintf("%s[%d]", path, i), item, lookup, missing); err != nil {
+				return nil, err
+			}
+		}
+
+		return v, nil
+	default:
+		return v, nil
+	}
+}
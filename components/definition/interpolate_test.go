@@ -0,0 +1,84 @@
+package definition
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseInterpolation(t *testing.T) {
+	t.Run("should expand environment variables in TOML files", func(t *testing.T) {
+		t.Setenv("SERVICE_NAME", "orders")
+		t.Setenv("SERVICE_PRODUCT", "")
+
+		path := writeDefinitionsFile(t, t.TempDir(), "service.toml", `
+name = "${SERVICE_NAME}"
+types = ["grpc"]
+version = "v${SERVICE_VERSION:-1.0.0}"
+language = "go"
+product = "${SERVICE_PRODUCT:-store}"
+
+[log]
+level = "debug"
+slow_call_threshold = "250ms"
+`)
+
+		defs, err := ParseFromFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, "orders", defs.Name)
+		assert.Equal(t, "v1.0.0", defs.Version)
+		assert.Equal(t, "store", defs.Product)
+		assert.Equal(t, "debug", defs.Log.Level)
+	})
+
+	t.Run("should expand environment variables in YAML files", func(t *testing.T) {
+		t.Setenv("SERVICE_NAME", "orders")
+
+		path := writeDefinitionsFile(t, t.TempDir(), "service.yaml", `
+name: ${SERVICE_NAME}
+types: [grpc]
+version: v1.0.0
+language: go
+product: store
+`)
+
+		defs, err := ParseFromFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, "orders", defs.Name)
+	})
+
+	t.Run("should fail listing the variables not set", func(t *testing.T) {
+		path := writeDefinitionsFile(t, t.TempDir(), "service.toml", `
+name = "${MISSING_NAME}"
+types = ["grpc"]
+version = "v1.0.0"
+language = "go"
+product = "${MISSING_PRODUCT}-${MISSING_NAME}"
+`)
+
+		_, err := ParseFromFile(path)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "MISSING_NAME (name, product)")
+		assert.Contains(t, err.Error(), "MISSING_PRODUCT (product)")
+	})
+
+	t.Run("should expand external definitions", func(t *testing.T) {
+		t.Setenv("DB_HOST", "db.local")
+
+		path := writeDefinitionsFile(t, t.TempDir(), "service.toml", `
+[features.database]
+hosts = ["${DB_HOST}", "${DB_REPLICA:-replica.local}"]
+`)
+
+		var defs struct {
+			Features struct {
+				Database struct {
+					Hosts []string `toml:"hosts"`
+				} `toml:"database"`
+			} `toml:"features"`
+		}
+		require.NoError(t, ParseExternalDefinitions(path, &defs))
+		assert.Equal(t, []string{"db.local", "replica.local"}, defs.Features.Database.Hosts)
+	})
+}
@@ -0,0 +1,168 @@
+package definition
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// LintWarning is an operational best-practice issue found in the service
+// definitions. Unlike validation errors, warnings don't prevent the service
+// from starting.
+type LintWarning struct {
+	// Rule is the identifier of the rule that produced the warning.
+	Rule string
+
+	// Message describes the issue.
+	Message string
+}
+
+func (w LintWarning) String() string {
+	return fmt.Sprintf("%s: %s", w.Rule, w.Message)
+}
+
+// LintOptions configures how definitions are linted.
+type LintOptions struct {
+	// Env is the deployment environment where the service will execute.
+	// Some rules only apply to specific environments.
+	Env DeploymentEnv
+
+	// CORS is the CORS configuration of the HTTP runtime, provided by the
+	// service CORS integration. Its rule is skipped when it is nil.
+	CORS *CORSSettings
+}
+
+// CORSSettings is the part of a CORS configuration checked by Lint.
+type CORSSettings struct {
+	AllowedOrigins   []string
+	AllowCredentials bool
+}
+
+// Lint checks the service definitions against operational best practices and
+// returns all warnings found.
+func Lint(defs *Definitions, options ...LintOptions) []LintWarning {
+	var opts LintOptions
+	if len(options) > 0 {
+		opts = options[0]
+	}
+
+	var warnings []LintWarning
+	for _, rule := range []func(*Definitions, LintOptions) []LintWarning{
+		lintHTTPTimeouts,
+		lintHTTPAuth,
+		lintHTTPCors,
+		lintScriptEnvs,
+	} {
+		warnings = append(warnings, rule(defs, opts)...)
+	}
+
+	return warnings
+}
+
+func lintHTTPTimeouts(defs *Definitions, opts LintOptions) []LintWarning {
+	if !slices.Contains(defs.Types, RuntimeTypeHTTP.String()) {
+		return nil
+	}
+
+	settings, _ := defs.LoadRuntime(RuntimeTypeHTTP)
+
+	var warnings []LintWarning
+	for _, key := range []string{"read_timeout", "write_timeout", "idle_timeout"} {
+		value, ok := settings[key]
+		if !ok {
+			if opts.Env == DeploymentEnvProduction {
+				warnings = append(warnings, LintWarning{
+					Rule: "http-timeouts",
+					Message: fmt.Sprintf("runtime.http.%s is not set, production services should choose their "+
+						"own timeouts instead of relying on the framework default", key),
+				})
+			}
+
+			continue
+		}
+
+		d, valid := durationValue(value)
+		switch {
+		case !valid:
+			warnings = append(warnings, LintWarning{
+				Rule:    "http-timeouts",
+				Message: fmt.Sprintf("runtime.http.%s is not a valid duration", key),
+			})
+		case d <= 0:
+			// The runtime keeps its default for values that are not
+			// positive, so timeouts cannot be disabled.
+			warnings = append(warnings, LintWarning{
+				Rule: "http-timeouts",
+				Message: fmt.Sprintf("runtime.http.%s is not positive and is ignored, the framework "+
+					"default is used instead", key),
+			})
+		}
+	}
+
+	return warnings
+}
+
+// durationValue converts a duration setting, written either as a string,
+// e.g. "10s", or as a number of nanoseconds, the same way the runtime does.
+func durationValue(value interface{}) (time.Duration, bool) {
+	switch v := value.(type) {
+	case int64:
+		return time.Duration(v), true
+	case float64:
+		return time.Duration(v), true
+	case string:
+		d, err := time.ParseDuration(v)
+		return d, err == nil
+	}
+
+	return 0, false
+}
+
+func lintHTTPAuth(defs *Definitions, opts LintOptions) []LintWarning {
+	settings, ok := defs.LoadRuntime(RuntimeTypeHTTP)
+	if !ok || opts.Env != DeploymentEnvProduction {
+		return nil
+	}
+
+	if disabled, _ := settings["disable_auth"].(bool); disabled {
+		return []LintWarning{
+			{
+				Rule:    "http-auth",
+				Message: "runtime.http.disable_auth is enabled in production",
+			},
+		}
+	}
+
+	return nil
+}
+
+func lintHTTPCors(_ *Definitions, opts LintOptions) []LintWarning {
+	if opts.CORS == nil {
+		return nil
+	}
+
+	if slices.Contains(opts.CORS.AllowedOrigins, "*") && opts.CORS.AllowCredentials {
+		return []LintWarning{
+			{
+				Rule: "http-cors",
+				Message: "CORS allows any origin with credentials, which fails the service startup when " +
+					"runtime.http.cors_strict is enabled and silently disables CORS otherwise",
+			},
+		}
+	}
+
+	return nil
+}
+
+func lintScriptEnvs(defs *Definitions, _ LintOptions) []LintWarning {
+	if !slices.Contains(defs.Types, RuntimeTypeScript.String()) || len(defs.Envs) == 0 {
+		return nil
+	}
+
+	return []LintWarning{
+		{
+			Rule:    "script-envs",
+			Message: "script services should receive their inputs as arguments instead of required envs",
+		},
+	}
+}
@@ -0,0 +1,179 @@
+package definition
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLint(t *testing.T) {
+	tests := []struct {
+		Title           string
+		TomlDefinitions string
+		Env             DeploymentEnv
+		CORS            *CORSSettings
+		Expected        []string
+	}{
+		{
+			Title: "should not warn with default settings",
+			TomlDefinitions: `
+name = "example"
+types = ["http"]
+version = "v1.0.0"
+language = "go"
+product = "SDS"
+`,
+			Env: DeploymentEnvDevelopment,
+		},
+		{
+			Title: "should not warn with production settings",
+			TomlDefinitions: `
+name = "example"
+types = ["http"]
+version = "v1.0.0"
+language = "go"
+product = "SDS"
+
+[runtime.http]
+read_timeout = "10s"
+write_timeout = "10s"
+idle_timeout = "30s"
+`,
+			Env:  DeploymentEnvProduction,
+			CORS: &CORSSettings{AllowedOrigins: []string{"https://app.example.com"}, AllowCredentials: true},
+		},
+		{
+			Title: "should warn about missing timeouts in production",
+			TomlDefinitions: `
+name = "example"
+types = ["http"]
+version = "v1.0.0"
+language = "go"
+product = "SDS"
+
+[runtime.http]
+read_timeout = "10s"
+`,
+			Env:      DeploymentEnvProduction,
+			Expected: []string{"http-timeouts", "http-timeouts"},
+		},
+		{
+			Title: "should warn about ignored timeouts",
+			TomlDefinitions: `
+name = "example"
+types = ["http"]
+version = "v1.0.0"
+language = "go"
+product = "SDS"
+
+[runtime.http]
+read_timeout = "0s"
+write_timeout = 0
+idle_timeout = "30s"
+`,
+			Expected: []string{"http-timeouts", "http-timeouts"},
+		},
+		{
+			Title: "should warn about invalid timeouts",
+			TomlDefinitions: `
+name = "example"
+types = ["http"]
+version = "v1.0.0"
+language = "go"
+product = "SDS"
+
+[runtime.http]
+read_timeout = "5 seconds"
+`,
+			Expected: []string{"http-timeouts"},
+		},
+		{
+			Title: "should warn about auth disabled only in production",
+			TomlDefinitions: `
+name = "example"
+types = ["http"]
+version = "v1.0.0"
+language = "go"
+product = "SDS"
+
+[runtime.http]
+disable_auth = true
+read_timeout = "10s"
+write_timeout = "10s"
+idle_timeout = "30s"
+`,
+			Env:      DeploymentEnvProduction,
+			Expected: []string{"http-auth"},
+		},
+		{
+			Title: "should not warn about auth disabled outside production",
+			TomlDefinitions: `
+name = "example"
+types = ["http"]
+version = "v1.0.0"
+language = "go"
+product = "SDS"
+
+[runtime.http]
+disable_auth = true
+`,
+			Env: DeploymentEnvDevelopment,
+		},
+		{
+			Title: "should warn about wildcard cors origins with credentials",
+			TomlDefinitions: `
+name = "example"
+types = ["http"]
+version = "v1.0.0"
+language = "go"
+product = "SDS"
+`,
+			CORS:     &CORSSettings{AllowedOrigins: []string{"*"}, AllowCredentials: true},
+			Expected: []string{"http-cors"},
+		},
+		{
+			Title: "should not warn about wildcard cors origins without credentials",
+			TomlDefinitions: `
+name = "example"
+types = ["http"]
+version = "v1.0.0"
+language = "go"
+product = "SDS"
+`,
+			CORS: &CORSSettings{AllowedOrigins: []string{"*"}},
+		},
+		{
+			Title: "should warn about script services with envs",
+			TomlDefinitions: `
+name = "example"
+types = ["script"]
+version = "v1.0.0"
+language = "go"
+product = "SDS"
+envs = ["INPUT_FILE"]
+`,
+			Expected: []string{"script-envs"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Title, func(t *testing.T) {
+			tmpFile, _ := os.CreateTemp(os.TempDir(), "lint-*.toml")
+			defer func() { _ = os.Remove(tmpFile.Name()) }()
+			_, _ = tmpFile.Write([]byte(test.TomlDefinitions))
+			_ = tmpFile.Close()
+
+			defs, err := ParseFromFile(tmpFile.Name())
+			require.NoError(t, err)
+
+			var rules []string
+			for _, w := range Lint(defs, LintOptions{Env: test.Env, CORS: test.CORS}) {
+				rules = append(rules, w.Rule)
+			}
+
+			assert.Equal(t, test.Expected, rules)
+		})
+	}
+}
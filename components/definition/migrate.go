@@ -0,0 +1,342 @@
+package definition
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/BurntSushi/toml"
+)
+
+// MigrationRule describes a deprecated part of definitions files and how to
+// bring it to the current format.
+type MigrationRule struct {
+	// Key is the dotted path of the deprecated key, e.g. "log.stacktrace".
+	Key string
+
+	// NewKey, when set, is where the value of Key is moved to.
+	NewKey string
+
+	// Rewrite, when set, converts the value of Key, returning false when
+	// it has nothing to change. Rewrites happen before moves.
+	Rewrite func(value interface{}) (interface{}, bool)
+
+	// Message explains the change. Rules without NewKey and Rewrite only
+	// flag the key with it, for changes that must be done by hand.
+	Message string
+}
+
+// MigrationChange is a rule that matched a definitions file.
+type MigrationChange struct {
+	Key     string
+	NewKey  string
+	Message string
+
+	// Applied is false when the change must be done by hand.
+	Applied bool
+}
+
+func (c MigrationChange) String() string {
+	status := "flagged"
+	if c.Applied {
+		status = "migrated"
+	}
+
+	target := c.Key
+	if c.NewKey != "" {
+		target = fmt.Sprintf("%s -> %s", c.Key, c.NewKey)
+	}
+
+	if c.Message == "" {
+		return fmt.Sprintf("%s: %s", status, target)
+	}
+
+	return fmt.Sprintf("%s: %s: %s", status, target, c.Message)
+}
+
+// MigrationReport lists what Migrate found in a definitions file.
+type MigrationReport struct {
+	Changes []MigrationChange
+
+	// Unknown are keys not described by the schema, usually typos or
+	// settings removed from the framework.
+	Unknown []string
+}
+
+// Pending returns true if something must still be fixed by hand.
+func (r MigrationReport) Pending() bool {
+	if len(r.Unknown) > 0 {
+		return true
+	}
+
+	for _, c := range r.Changes {
+		if !c.Applied {
+			return true
+		}
+	}
+
+	return false
+}
+
+// MigrateOptions configures a migration.
+type MigrateOptions struct {
+	// Rules are used in addition to the registered ones.
+	Rules []MigrationRule
+
+	// Schema, when set, is used to find unknown keys, e.g. the one given by
+	// Definitions.JSONSchema.
+	Schema Schema
+}
+
+var (
+	migrationRulesMu sync.Mutex
+	migrationRules   []MigrationRule
+)
+
+// RegisterMigrationRules adds rules used by every migration. Framework
+// releases and external features renaming or moving their settings register
+// them so services can be upgraded automatically.
+func RegisterMigrationRules(rules ...MigrationRule) {
+	migrationRulesMu.Lock()
+	defer migrationRulesMu.Unlock()
+
+	migrationRules = append(migrationRules, rules...)
+}
+
+// RenameRuntimeType returns a rule replacing a runtime type in the service
+// types, keeping its port, if any.
+func RenameRuntimeType(from, to string) MigrationRule {
+	return MigrationRule{
+		Key:     "types",
+		Message: fmt.Sprintf("runtime type '%s' was renamed to '%s'", from, to),
+		Rewrite: func(value interface{}) (interface{}, bool) {
+			types, ok := value.([]interface{})
+			if !ok {
+				return nil, false
+			}
+
+			var (
+				changed bool
+				out     = make([]interface{}, len(types))
+			)
+			for i, t := range types {
+				out[i] = t
+				s, ok := t.(string)
+				if !ok {
+					continue
+				}
+
+				name, port, hasPort := strings.Cut(s, ":")
+				if name != from {
+					continue
+				}
+
+				changed = true
+				out[i] = to
+				if hasPort {
+					out[i] = to + ":" + port
+				}
+			}
+
+			return out, changed
+		},
+	}
+}
+
+// Migrate applies the migration rules to the decoded content of a
+// definitions file, changing it in place, and reports what was found.
+func Migrate(data map[string]interface{}, options ...MigrateOptions) MigrationReport {
+	var opt MigrateOptions
+	if len(options) > 0 {
+		opt = options[0]
+	}
+
+	migrationRulesMu.Lock()
+	rules := append(append([]MigrationRule(nil), migrationRules...), opt.Rules...)
+	migrationRulesMu.Unlock()
+
+	var report MigrationReport
+	for _, rule := range rules {
+		if change, ok := applyMigrationRule(data, rule); ok {
+			report.Changes = append(report.Changes, change)
+		}
+	}
+
+	if opt.Schema != nil {
+		report.Unknown = unknownKeys("", data, opt.Schema)
+		sort.Strings(report.Unknown)
+	}
+
+	return report
+}
+
+// MigrateFile migrates a TOML definitions file, returning its new content.
+// Comments and the original order of the keys are not kept.
+func MigrateFile(path string, options ...MigrateOptions) ([]byte, MigrationReport, error) {
+	var data map[string]interface{}
+	if _, err := toml.DecodeFile(path, &data); err != nil {
+		return nil, MigrationReport{}, err
+	}
+
+	report := Migrate(data, options...)
+
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(data); err != nil {
+		return nil, MigrationReport{}, err
+	}
+
+	return buf.Bytes(), report, nil
+}
+
+func applyMigrationRule(data map[string]interface{}, rule MigrationRule) (MigrationChange, bool) {
+	value, ok := lookupKey(data, rule.Key)
+	if !ok {
+		return MigrationChange{}, false
+	}
+
+	change := MigrationChange{
+		Key:     rule.Key,
+		NewKey:  rule.NewKey,
+		Message: rule.Message,
+	}
+
+	if rule.Rewrite != nil {
+		v, changed := rule.Rewrite(value)
+		if !changed {
+			return MigrationChange{}, false
+		}
+
+		value = v
+		setKey(data, rule.Key, value)
+		change.Applied = true
+	}
+
+	if rule.NewKey != "" {
+		if _, exists := lookupKey(data, rule.NewKey); exists {
+			change.Applied = false
+			change.Message = fmt.Sprintf("both '%s' and '%s' are set", rule.Key, rule.NewKey)
+			return change, true
+		}
+
+		if !setKey(data, rule.NewKey, value) {
+			change.Applied = false
+			change.Message = fmt.Sprintf("'%s' is not a table", rule.NewKey)
+			return change, true
+		}
+
+		deleteKey(data, rule.Key)
+		change.Applied = true
+	}
+
+	return change, true
+}
+
+func lookupKey(data map[string]interface{}, key string) (interface{}, bool) {
+	var (
+		parts   = strings.Split(key, ".")
+		current = data
+	)
+
+	for i, p := range parts {
+		v, ok := current[p]
+		if !ok {
+			return nil, false
+		}
+		if i == len(parts)-1 {
+			return v, true
+		}
+
+		current, ok = v.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+	}
+
+	return nil, false
+}
+
+// setKey sets the value of key, creating its missing tables. It returns
+// false when one of its parents is not a table.
+func setKey(data map[string]interface{}, key string, value interface{}) bool {
+	var (
+		parts   = strings.Split(key, ".")
+		current = data
+	)
+
+	for _, p := range parts[:len(parts)-1] {
+		v, ok := current[p]
+		if !ok {
+			next := make(map[string]interface{})
+			current[p] = next
+			current = next
+			continue
+		}
+
+		current, ok = v.(map[string]interface{})
+		if !ok {
+			return false
+		}
+	}
+
+	current[parts[len(parts)-1]] = value
+	return true
+}
+
+// deleteKey removes key, and the tables left empty by it.
+func deleteKey(data map[string]interface{}, key string) {
+	parent, name, found := strings.Cut(key, ".")
+	if !found {
+		delete(data, key)
+		return
+	}
+
+	child, ok := data[parent].(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	deleteKey(child, name)
+	if len(child) == 0 {
+		delete(data, parent)
+	}
+}
+
+// unknownKeys returns the keys of data not described by schema. Tables
+// accepting any key are not inspected.
+func unknownKeys(prefix string, data map[string]interface{}, schema Schema) []string {
+	var (
+		unknown       []string
+		properties, _ = schema["properties"].(map[string]interface{})
+		additional    = schema["additionalProperties"]
+	)
+
+	for key, value := range data {
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+
+		child, known := properties[key].(Schema)
+		if !known {
+			switch a := additional.(type) {
+			case bool:
+				if !a {
+					unknown = append(unknown, path)
+				}
+				continue
+			case Schema:
+				child = a
+			default:
+				continue
+			}
+		}
+
+		if table, ok := value.(map[string]interface{}); ok {
+			unknown = append(unknown, unknownKeys(path, table, child)...)
+		}
+	}
+
+	return unknown
+}
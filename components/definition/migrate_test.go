@@ -0,0 +1,168 @@
+package definition
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/BurntSushi/toml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func decodeDefinitions(t *testing.T, content string) map[string]interface{} {
+	t.Helper()
+
+	var data map[string]interface{}
+	_, err := toml.Decode(content, &data)
+	require.NoError(t, err)
+
+	return data
+}
+
+func TestMigrate(t *testing.T) {
+	t.Run("should move keys", func(t *testing.T) {
+		data := decodeDefinitions(t, `
+name = "orders"
+
+[log]
+stacktrace = "structured"
+`)
+
+		report := Migrate(data, MigrateOptions{
+			Rules: []MigrationRule{{Key: "log.stacktrace", NewKey: "log.error_stack_trace"}},
+		})
+
+		require.Len(t, report.Changes, 1)
+		assert.True(t, report.Changes[0].Applied)
+		assert.False(t, report.Pending())
+		assert.Equal(t, map[string]interface{}{"error_stack_trace": "structured"}, data["log"])
+	})
+
+	t.Run("should move keys between tables", func(t *testing.T) {
+		data := decodeDefinitions(t, `
+[service]
+proxy_protocol = true
+`)
+
+		Migrate(data, MigrateOptions{
+			Rules: []MigrationRule{{Key: "service.proxy_protocol", NewKey: "listen.proxy_protocol.enabled"}},
+		})
+
+		_, ok := data["service"]
+		assert.False(t, ok)
+		v, ok := lookupKey(data, "listen.proxy_protocol.enabled")
+		assert.True(t, ok)
+		assert.Equal(t, true, v)
+	})
+
+	t.Run("should not overwrite existing keys", func(t *testing.T) {
+		data := decodeDefinitions(t, `
+[log]
+stacktrace = "structured"
+error_stack_trace = "default"
+`)
+
+		report := Migrate(data, MigrateOptions{
+			Rules: []MigrationRule{{Key: "log.stacktrace", NewKey: "log.error_stack_trace"}},
+		})
+
+		require.Len(t, report.Changes, 1)
+		assert.False(t, report.Changes[0].Applied)
+		assert.True(t, report.Pending())
+		assert.Equal(t, "default", data["log"].(map[string]interface{})["error_stack_trace"])
+	})
+
+	t.Run("should rename runtime types", func(t *testing.T) {
+		data := decodeDefinitions(t, `types = ["grpc", "native:8080"]`)
+
+		report := Migrate(data, MigrateOptions{
+			Rules: []MigrationRule{RenameRuntimeType("native", "worker")},
+		})
+
+		require.Len(t, report.Changes, 1)
+		assert.Equal(t, []interface{}{"grpc", "worker:8080"}, data["types"])
+
+		report = Migrate(data, MigrateOptions{
+			Rules: []MigrationRule{RenameRuntimeType("native", "worker")},
+		})
+		assert.Empty(t, report.Changes)
+	})
+
+	t.Run("should flag keys without automatic migration", func(t *testing.T) {
+		data := decodeDefinitions(t, `
+[features.legacy]
+enabled = true
+`)
+
+		report := Migrate(data, MigrateOptions{
+			Rules: []MigrationRule{{Key: "features.legacy", Message: "use the cache feature instead"}},
+		})
+
+		require.Len(t, report.Changes, 1)
+		assert.False(t, report.Changes[0].Applied)
+		assert.Equal(t, "flagged: features.legacy: use the cache feature instead", report.Changes[0].String())
+	})
+
+	t.Run("should report unknown keys", func(t *testing.T) {
+		defs, err := New()
+		require.NoError(t, err)
+		defs.AddExternalFeatureDefinitions("cache", &schemaFeature{})
+
+		data := decodeDefinitions(t, `
+name = "orders"
+typo = 1
+
+[log]
+levl = "debug"
+
+[features.cache]
+enabled = true
+backnd = "file"
+
+[runtime.http]
+anything = true
+
+[service]
+custom = 1
+`)
+
+		report := Migrate(data, MigrateOptions{Schema: defs.JSONSchema(nil)})
+		assert.Equal(t, []string{"features.cache.backnd", "log.levl", "typo"}, report.Unknown)
+		assert.True(t, report.Pending())
+	})
+
+	t.Run("should use registered rules", func(t *testing.T) {
+		defer func() {
+			migrationRulesMu.Lock()
+			migrationRules = nil
+			migrationRulesMu.Unlock()
+		}()
+
+		RegisterMigrationRules(MigrationRule{Key: "old", NewKey: "product"})
+		data := decodeDefinitions(t, `old = "store"`)
+
+		Migrate(data)
+		assert.Equal(t, map[string]interface{}{"product": "store"}, data)
+	})
+}
+
+func TestMigrateFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "service.toml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+name = "orders"
+types = ["native"]
+`), 0o600))
+
+	content, report, err := MigrateFile(path, MigrateOptions{
+		Rules: []MigrationRule{RenameRuntimeType("native", "worker")},
+	})
+	require.NoError(t, err)
+	require.Len(t, report.Changes, 1)
+
+	data := decodeDefinitions(t, string(content))
+	assert.Equal(t, []interface{}{"worker"}, data["types"])
+
+	_, _, err = MigrateFile(filepath.Join(t.TempDir(), "missing.toml"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,54 @@
+package definition
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// NamingPolicy enforces organization naming conventions on the service
+// definitions. It is checked by Definitions.Validate, so services not
+// following the conventions fail at startup.
+type NamingPolicy struct {
+	// Name, when set, must match the service name.
+	Name *regexp.Regexp
+
+	// Product, when set, must match the service product.
+	Product *regexp.Regexp
+
+	// Version, when set, must match the service version.
+	Version *regexp.Regexp
+
+	// Check is an optional callback for conventions that can't be
+	// expressed by regular expressions.
+	Check func(defs *Definitions) error
+}
+
+func (p NamingPolicy) validate(defs *Definitions) error {
+	for _, field := range []struct {
+		name   string
+		value  string
+		policy *regexp.Regexp
+	}{
+		{name: "name", value: defs.Name, policy: p.Name},
+		{name: "product", value: defs.Product, policy: p.Product},
+		{name: "version", value: defs.Version, policy: p.Version},
+	} {
+		if field.policy != nil && !field.policy.MatchString(field.value) {
+			return fmt.Errorf("service %s '%s' does not follow the naming policy '%s'", field.name, field.value, field.policy)
+		}
+	}
+
+	if p.Check != nil {
+		if err := p.Check(defs); err != nil {
+			return fmt.Errorf("service definitions do not follow the naming policy: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// AddNamingPolicy adds a naming policy to be enforced when the definitions are
+// validated.
+func (d *Definitions) AddNamingPolicy(policy NamingPolicy) {
+	d.namingPolicies = append(d.namingPolicies, policy)
+}
@@ -0,0 +1,65 @@
+package definition
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNamingPolicy(t *testing.T) {
+	newDefinitions := func(t *testing.T) *Definitions {
+		defs, err := New()
+		require.NoError(t, err)
+
+		defs.Name = "user-service"
+		defs.Types = []string{"grpc"}
+		defs.Version = "v1.0.0"
+		defs.Language = "go"
+		defs.Product = "SDS"
+
+		return defs
+	}
+
+	t.Run("should succeed when definitions follow the policy", func(t *testing.T) {
+		defs := newDefinitions(t)
+		defs.AddNamingPolicy(NamingPolicy{
+			Name:    regexp.MustCompile(`^[a-z]+(-[a-z]+)*$`),
+			Product: regexp.MustCompile(`^[A-Z]{3}$`),
+		})
+
+		assert.NoError(t, defs.Validate())
+	})
+
+	t.Run("should fail when a field does not match the policy", func(t *testing.T) {
+		defs := newDefinitions(t)
+		defs.Name = "UserService"
+		defs.AddNamingPolicy(NamingPolicy{
+			Name: regexp.MustCompile(`^[a-z]+(-[a-z]+)*$`),
+		})
+
+		err := defs.Validate()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "service name 'UserService' does not follow the naming policy")
+	})
+
+	t.Run("should fail when the callback fails", func(t *testing.T) {
+		defs := newDefinitions(t)
+		defs.AddNamingPolicy(NamingPolicy{
+			Check: func(defs *Definitions) error {
+				if !strings.HasSuffix(defs.Name, "-api") {
+					return errors.New("name must end with -api")
+				}
+
+				return nil
+			},
+		})
+
+		err := defs.Validate()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "name must end with -api")
+	})
+}
@@ -3,13 +3,18 @@ package definition
 import (
 	"flag"
 	"os"
-	"path/filepath"
-
-	"github.com/BurntSushi/toml"
 )
 
 // Parse is responsible for loading the service definitions file (service.toml)
-// into a proper Definitions structure.
+// into a proper Definitions structure. When the service directory has no
+// 'service.toml', a 'service.yaml', 'service.yml' or 'service.json' file is
+// loaded instead, with the same keys.
+//
+// String values may reference environment variables, as in
+// 'host = "${DB_HOST}"', or 'host = "${DB_HOST:-localhost}"' to use a
+// fallback when the variable is not set or is empty. Referencing a variable
+// that is not set, without a fallback, is an error. Use '$${' to write a
+// literal '${'.
 func Parse() (*Definitions, error) {
 	path, err := getServiceTomlPath()
 	if err != nil {
@@ -20,14 +25,15 @@ func Parse() (*Definitions, error) {
 }
 
 // ParseFromFile is an alternative way of loading a service definitions file
-// for outside projects.
+// for outside projects. Its format, TOML, YAML or JSON, is given by its
+// extension.
 func ParseFromFile(path string) (*Definitions, error) {
 	defs, err := New()
 	if err != nil {
 		return nil, err
 	}
 
-	if _, err := toml.DecodeFile(path, &defs); err != nil {
+	if err := decodeFile(path, defs); err != nil {
 		return nil, err
 	}
 
@@ -37,7 +43,7 @@ func ParseFromFile(path string) (*Definitions, error) {
 }
 
 func getServiceTomlPath() (string, error) {
-	path := flag.String("config", "", "Sets the alternative path for 'service.toml' file.")
+	path := flag.String("config", "", "Sets the alternative path for 'service.toml' file (or its YAML or JSON version).")
 	flag.Parse()
 
 	if path != nil && *path != "" {
@@ -49,16 +55,12 @@ func getServiceTomlPath() (string, error) {
 		return "", err
 	}
 
-	return filepath.Join(serviceDir, "service.toml"), nil
+	return findDefinitionsFile(serviceDir), nil
 }
 
 // ParseExternalDefinitions allows loading specific service definitions from its
 // file using a custom target. This provides external features (plugins) to load
 // their definitions from the same file into their own structures.
 func ParseExternalDefinitions(path string, defs interface{}) error {
-	if _, err := toml.DecodeFile(path, defs); err != nil {
-		return err
-	}
-
-	return nil
+	return decodeFile(path, defs)
 }
@@ -0,0 +1,247 @@
+package definition
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	jsonSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+)
+
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+)
+
+// Schema is a JSON Schema document.
+type Schema map[string]interface{}
+
+// JSONSchema returns the JSON Schema of the definitions file, including the
+// sections of the external features and runtimes added to d, so editors can
+// autocomplete it and CI jobs can validate it. serviceDefinitions, when not
+// nil, is the structure of the '[service]' section.
+func (d *Definitions) JSONSchema(serviceDefinitions interface{}) Schema {
+	schema := SchemaOf(d)
+	schema["$schema"] = jsonSchemaDraft
+	schema["title"] = "service.toml"
+
+	properties := schema["properties"].(map[string]interface{})
+
+	features := properties["features"].(Schema)
+	featureProperties := features["properties"].(map[string]interface{})
+	for name, entry := range d.Features.externalFeatures {
+		featureProperties[name] = SchemaOf(entry)
+	}
+
+	runtime := properties["runtime"].(Schema)
+	runtimeProperties := make(map[string]interface{})
+	for name, entry := range d.externalRuntimes {
+		runtimeProperties[name] = SchemaOf(entry)
+	}
+	if len(runtimeProperties) > 0 {
+		runtime["properties"] = runtimeProperties
+	}
+
+	if serviceDefinitions != nil {
+		properties["service"] = SchemaOf(serviceDefinitions)
+	}
+
+	return schema
+}
+
+// SchemaOf returns the JSON Schema of a settings structure using its 'toml',
+// 'validate' and 'default' field tags.
+func SchemaOf(v interface{}) Schema {
+	if v == nil {
+		return Schema{}
+	}
+
+	return typeSchema(reflect.TypeOf(v))
+}
+
+func typeSchema(t reflect.Type) Schema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t == durationType {
+		return Schema{
+			"type":        []string{"string", "integer"},
+			"description": "duration, e.g. \"30s\" or \"5m\"",
+		}
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		return structSchema(t)
+	case reflect.Map:
+		return Schema{
+			"type":                 "object",
+			"additionalProperties": typeSchema(t.Elem()),
+		}
+	case reflect.Slice, reflect.Array:
+		return Schema{
+			"type":  "array",
+			"items": typeSchema(t.Elem()),
+		}
+	case reflect.String:
+		return Schema{"type": "string"}
+	case reflect.Bool:
+		return Schema{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return Schema{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return Schema{"type": "number"}
+	default:
+		// Interfaces accept anything.
+		return Schema{}
+	}
+}
+
+func structSchema(t reflect.Type) Schema {
+	var (
+		properties = make(map[string]interface{})
+		required   []string
+	)
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, ok := tomlFieldName(field)
+		if !ok {
+			continue
+		}
+
+		schema := typeSchema(field.Type)
+		rules := field.Tag.Get("validate")
+		if applyValidateRules(schema, field.Type, rules) {
+			required = append(required, name)
+		}
+		if def, ok := field.Tag.Lookup("default"); ok {
+			schema["default"] = defaultValue(field.Type, def)
+		}
+
+		properties[name] = schema
+	}
+
+	schema := Schema{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+
+	return schema
+}
+
+func tomlFieldName(field reflect.StructField) (string, bool) {
+	tag, ok := field.Tag.Lookup("toml")
+	if !ok {
+		return field.Name, true
+	}
+
+	name, _, _ := strings.Cut(tag, ",")
+	if name == "-" {
+		return "", false
+	}
+	if name == "" {
+		return field.Name, true
+	}
+
+	return name, true
+}
+
+// applyValidateRules adds the constraints of validate rules that JSON Schema
+// can express to schema. It returns true if the field is required.
+func applyValidateRules(schema Schema, t reflect.Type, rules string) bool {
+	if rules == "" {
+		return false
+	}
+
+	var required bool
+	for _, rule := range strings.Split(rules, ",") {
+		name, param, _ := strings.Cut(rule, "=")
+
+		switch name {
+		case "required":
+			required = true
+		case "dive":
+			// The remaining rules belong to the items.
+			if items, ok := schema["items"].(Schema); ok {
+				_, rest, _ := strings.Cut(rules, "dive")
+				applyValidateRules(items, t.Elem(), strings.TrimPrefix(rest, ","))
+			}
+			if values, ok := schema["additionalProperties"].(Schema); ok {
+				_, rest, _ := strings.Cut(rules, "dive")
+				applyValidateRules(values, t.Elem(), strings.TrimPrefix(rest, ","))
+			}
+
+			return required
+		case "oneof":
+			var enum []interface{}
+			for _, v := range strings.Fields(param) {
+				enum = append(enum, defaultValue(t, v))
+			}
+			schema["enum"] = enum
+		case "gte", "min":
+			setNumericBound(schema, "minimum", param)
+		case "lte", "max":
+			setNumericBound(schema, "maximum", param)
+		case "url":
+			schema["format"] = "uri"
+		case "cidr":
+			schema["pattern"] = `^[0-9a-fA-F.:]+/[0-9]+$`
+		case "uppercase":
+			schema["pattern"] = `^[^a-z]*$`
+		}
+	}
+
+	return required
+}
+
+func setNumericBound(schema Schema, keyword, param string) {
+	if schema["type"] != "integer" && schema["type"] != "number" {
+		return
+	}
+
+	if n, err := strconv.ParseFloat(param, 64); err == nil {
+		schema[keyword] = n
+	}
+}
+
+// defaultValue converts a tag value into the JSON type of the field.
+func defaultValue(t reflect.Type, value string) interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == durationType {
+		return value
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return n
+		}
+	case reflect.Float32, reflect.Float64:
+		if n, err := strconv.ParseFloat(value, 64); err == nil {
+			return n
+		}
+	default:
+	}
+
+	return value
+}
@@ -0,0 +1,103 @@
+package definition
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type schemaFeature struct {
+	Enable  bool          `toml:"enabled"`
+	Backend string        `toml:"backend" validate:"oneof=memory file" default:"memory"`
+	TTL     time.Duration `toml:"ttl"`
+}
+
+func (f *schemaFeature) Enabled() bool {
+	return f.Enable
+}
+
+func (f *schemaFeature) Validate() error {
+	return nil
+}
+
+type schemaRuntime struct {
+	Workers int `toml:"workers" validate:"gte=1,lte=64" default:"4"`
+}
+
+func (r *schemaRuntime) Name() string {
+	return "worker"
+}
+
+func (r *schemaRuntime) Validate() error {
+	return nil
+}
+
+func TestJSONSchema(t *testing.T) {
+	property := func(t *testing.T, schema Schema, path ...string) Schema {
+		t.Helper()
+
+		current := schema
+		for _, name := range path {
+			properties, ok := current["properties"].(map[string]interface{})
+			require.True(t, ok, name)
+
+			current, ok = properties[name].(Schema)
+			require.True(t, ok, name)
+		}
+
+		return current
+	}
+
+	t.Run("should describe the definitions file", func(t *testing.T) {
+		defs, err := New()
+		require.NoError(t, err)
+
+		schema := defs.JSONSchema(nil)
+		assert.Equal(t, jsonSchemaDraft, schema["$schema"])
+		assert.Equal(t, []string{"name", "types", "version", "language", "product"}, schema["required"])
+		assert.Equal(t, false, schema["additionalProperties"])
+
+		assert.Equal(t, []interface{}{"go", "rust"}, property(t, schema, "language")["enum"])
+		assert.Equal(t, "array", property(t, schema, "types")["type"])
+		assert.Equal(t, "disabled", property(t, schema, "log", "error_stack_trace")["default"])
+		assert.Equal(t, float64(0), property(t, schema, "log", "recent_records")["minimum"])
+		assert.Equal(t, "uri", property(t, schema, "log", "otlp", "endpoint")["format"])
+		assert.Equal(t, []string{"endpoint"}, property(t, schema, "log", "otlp")["required"])
+		assert.Equal(t, float64(1), property(t, schema, "limits", "memory_limit_ratio")["maximum"])
+		assert.Equal(t, 0.9, property(t, schema, "limits", "memory_limit_ratio")["default"])
+		assert.Equal(t, []string{"string", "integer"}, property(t, schema, "health", "timeout")["type"])
+
+		items := property(t, schema, "listen", "proxy_protocol", "trusted_proxies")["items"].(Schema)
+		assert.NotEmpty(t, items["pattern"])
+
+		_, err = json.Marshal(schema)
+		assert.NoError(t, err)
+	})
+
+	t.Run("should include external and service sections", func(t *testing.T) {
+		defs, err := New()
+		require.NoError(t, err)
+		defs.AddExternalFeatureDefinitions("cache", &schemaFeature{})
+		defs.AddExternalRuntimeDefinitions("worker", &schemaRuntime{})
+
+		var service struct {
+			Queue string `toml:"queue" validate:"required"`
+		}
+
+		schema := defs.JSONSchema(&service)
+
+		backend := property(t, schema, "features", "cache", "backend")
+		assert.Equal(t, []interface{}{"memory", "file"}, backend["enum"])
+		assert.Equal(t, "memory", backend["default"])
+		assert.Equal(t, "boolean", property(t, schema, "features", "cache", "enabled")["type"])
+
+		workers := property(t, schema, "runtime", "worker", "workers")
+		assert.Equal(t, int64(4), workers["default"])
+		assert.Equal(t, float64(64), workers["maximum"])
+
+		assert.Equal(t, []string{"queue"}, property(t, schema, "service")["required"])
+	})
+}
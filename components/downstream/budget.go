@@ -0,0 +1,164 @@
+package downstream
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	logger_api "github.com/mikros-dev/mikros/apis/features/logger"
+	"github.com/mikros-dev/mikros/components/logger"
+)
+
+// ErrBudgetExceeded is the error matched by errors.Is when a request exceeds
+// its downstream call budget.
+var ErrBudgetExceeded = errors.New("downstream call budget exceeded")
+
+// Budget limits the calls that a single request can make to its downstream
+// dependencies. It helps to catch N+1 call patterns, where a handler calls a
+// dependency once for every item it handles.
+type Budget struct {
+	// MaxCalls is the maximum number of downstream calls. Zero means no limit.
+	MaxCalls int
+
+	// MaxDuration is the maximum cumulative time spent on downstream calls.
+	// Zero means no limit.
+	MaxDuration time.Duration
+
+	// Enforce makes calls above the budget fail with a BudgetExceededError.
+	// Otherwise, exceeding the budget is only logged.
+	Enforce bool
+}
+
+// IsZero reports whether the budget has no limits.
+func (b Budget) IsZero() bool {
+	return b.MaxCalls <= 0 && b.MaxDuration <= 0
+}
+
+// BudgetUsage is how much of a budget a request has already used.
+type BudgetUsage struct {
+	Calls    int
+	Duration time.Duration
+}
+
+// BudgetExceededError is the error returned when a downstream call exceeds the
+// budget of the current request.
+type BudgetExceededError struct {
+	Budget      Budget
+	Usage       BudgetUsage
+	Destination string
+	Method      string
+}
+
+func (e *BudgetExceededError) Error() string {
+	return fmt.Sprintf(
+		"%v: %s %s (calls %d/%d, duration %s/%s)",
+		ErrBudgetExceeded,
+		e.Destination,
+		e.Method,
+		e.Usage.Calls,
+		e.Budget.MaxCalls,
+		e.Usage.Duration,
+		e.Budget.MaxDuration,
+	)
+}
+
+// Is allows the error to be matched against ErrBudgetExceeded.
+func (e *BudgetExceededError) Is(target error) bool {
+	return target == ErrBudgetExceeded
+}
+
+type budgetKey struct{}
+
+type budgetState struct {
+	mu       sync.Mutex
+	budget   Budget
+	usage    BudgetUsage
+	reported bool
+}
+
+// WithBudget returns a copy of ctx where downstream calls are limited by
+// budget. Runtimes call it once per request.
+func WithBudget(ctx context.Context, budget Budget) context.Context {
+	if budget.IsZero() {
+		return ctx
+	}
+
+	return context.WithValue(ctx, budgetKey{}, &budgetState{
+		budget: budget,
+	})
+}
+
+// BudgetUsageFromContext returns the budget usage of the request that ctx
+// belongs to.
+func BudgetUsageFromContext(ctx context.Context) (BudgetUsage, bool) {
+	state, ok := ctx.Value(budgetKey{}).(*budgetState)
+	if !ok {
+		return BudgetUsage{}, false
+	}
+
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	return state.usage, true
+}
+
+// SpendBudget registers a call to method of destination in the budget of the
+// current request and returns the function that must be called when the call
+// is finished, so that its duration is accounted.
+//
+// When the call exceeds the budget, a warning is written to log, once per
+// request, and, if the budget is enforced, a BudgetExceededError is returned
+// and the call must not be made. Contexts without a budget are not limited.
+func SpendBudget(ctx context.Context, log logger_api.API, destination, method string) (func(), error) {
+	state, ok := ctx.Value(budgetKey{}).(*budgetState)
+	if !ok {
+		return func() {}, nil
+	}
+
+	start := time.Now()
+	done := func() {
+		state.mu.Lock()
+		state.usage.Duration += time.Since(start)
+		state.mu.Unlock()
+	}
+
+	state.mu.Lock()
+	state.usage.Calls++
+
+	var (
+		b        = state.budget
+		exceeded = (b.MaxCalls > 0 && state.usage.Calls > b.MaxCalls) ||
+			(b.MaxDuration > 0 && state.usage.Duration >= b.MaxDuration)
+		report = exceeded && !state.reported
+		err    = &BudgetExceededError{
+			Budget:      b,
+			Usage:       state.usage,
+			Destination: destination,
+			Method:      method,
+		}
+	)
+
+	if report {
+		state.reported = true
+	}
+	state.mu.Unlock()
+
+	if report && log != nil {
+		log.Warn(ctx, "downstream call budget exceeded",
+			logger.String("downstream.destination", destination),
+			logger.String("downstream.method", method),
+			logger.Any("downstream.calls", err.Usage.Calls),
+			logger.String("downstream.duration", err.Usage.Duration.String()),
+			logger.Any("budget.max_calls", b.MaxCalls),
+			logger.String("budget.max_duration", b.MaxDuration.String()),
+		)
+	}
+
+	if exceeded && b.Enforce {
+		return func() {}, err
+	}
+
+	return done, nil
+}
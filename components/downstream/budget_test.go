@@ -0,0 +1,71 @@
+package downstream
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpendBudget(t *testing.T) {
+	t.Run("should not limit contexts without budget", func(t *testing.T) {
+		ctx := WithBudget(context.Background(), Budget{})
+		for i := 0; i < 10; i++ {
+			done, err := SpendBudget(ctx, nil, "users", "Get")
+			require.NoError(t, err)
+			done()
+		}
+
+		_, ok := BudgetUsageFromContext(ctx)
+		assert.False(t, ok)
+	})
+
+	t.Run("should only log when the budget is not enforced", func(t *testing.T) {
+		var (
+			l   = &fakeLogger{}
+			ctx = WithBudget(context.Background(), Budget{MaxCalls: 2})
+		)
+
+		for i := 0; i < 4; i++ {
+			done, err := SpendBudget(ctx, l, "users", "Get")
+			require.NoError(t, err)
+			done()
+		}
+
+		assert.Equal(t, []string{"downstream call budget exceeded"}, l.messages)
+		usage, ok := BudgetUsageFromContext(ctx)
+		assert.True(t, ok)
+		assert.Equal(t, 4, usage.Calls)
+	})
+
+	t.Run("should fail calls above an enforced budget", func(t *testing.T) {
+		ctx := WithBudget(context.Background(), Budget{MaxCalls: 1, Enforce: true})
+
+		done, err := SpendBudget(ctx, nil, "users", "Get")
+		require.NoError(t, err)
+		done()
+
+		_, err = SpendBudget(ctx, nil, "users", "Get")
+		assert.ErrorIs(t, err, ErrBudgetExceeded)
+
+		var bErr *BudgetExceededError
+		require.True(t, errors.As(err, &bErr))
+		assert.Equal(t, 2, bErr.Usage.Calls)
+		assert.Equal(t, "users", bErr.Destination)
+	})
+
+	t.Run("should account the cumulative call duration", func(t *testing.T) {
+		ctx := WithBudget(context.Background(), Budget{MaxDuration: time.Millisecond, Enforce: true})
+
+		done, err := SpendBudget(ctx, nil, "users", "Get")
+		require.NoError(t, err)
+		time.Sleep(2 * time.Millisecond)
+		done()
+
+		_, err = SpendBudget(ctx, nil, "users", "Get")
+		assert.ErrorIs(t, err, ErrBudgetExceeded)
+	})
+}
@@ -0,0 +1,75 @@
+package downstream
+
+import (
+	"context"
+	"time"
+
+	logger_api "github.com/mikros-dev/mikros/apis/features/logger"
+	"github.com/mikros-dev/mikros/apis/integrations"
+	"github.com/mikros-dev/mikros/components/logger"
+)
+
+// SlowCallOptions gathers the options to create a SlowCallLogger.
+type SlowCallOptions struct {
+	// Threshold is the duration above which a call is considered slow. A zero
+	// value disables the logger.
+	Threshold time.Duration
+
+	// Logger is where warnings are written.
+	Logger logger_api.API
+
+	// Tracker, if set, is used to add the request tracker ID into warnings.
+	Tracker integrations.Tracker
+}
+
+// SlowCallLogger logs a structured warning whenever a call to a downstream
+// dependency (another service, a database, an external API) takes longer
+// than a threshold.
+type SlowCallLogger struct {
+	options SlowCallOptions
+}
+
+// NewSlowCallLogger creates a new SlowCallLogger. It returns nil, which is a
+// valid logger that does nothing, when no threshold or logger is given.
+func NewSlowCallLogger(options SlowCallOptions) *SlowCallLogger {
+	if options.Threshold <= 0 || options.Logger == nil {
+		return nil
+	}
+
+	return &SlowCallLogger{
+		options: options,
+	}
+}
+
+// Track starts measuring a call to method of destination and returns the
+// function that must be called when the call is finished:
+//
+//	defer slow.Track(ctx, "postgres", "FindUser")()
+func (s *SlowCallLogger) Track(ctx context.Context, destination, method string) func() {
+	start := time.Now()
+	return func() {
+		s.Observe(ctx, destination, method, time.Since(start))
+	}
+}
+
+// Observe checks the duration of a finished call and logs it if it exceeds
+// the threshold.
+func (s *SlowCallLogger) Observe(ctx context.Context, destination, method string, elapsed time.Duration) {
+	if s == nil || elapsed <= s.options.Threshold {
+		return
+	}
+
+	fields := []logger_api.Attribute{
+		logger.String("downstream.destination", destination),
+		logger.String("downstream.method", method),
+		logger.String("downstream.duration", elapsed.String()),
+		logger.String("downstream.threshold", s.options.Threshold.String()),
+	}
+	if s.options.Tracker != nil {
+		if id, ok := s.options.Tracker.Retrieve(ctx); ok {
+			fields = append(fields, logger.String("tracker.id", id))
+		}
+	}
+
+	s.options.Logger.Warn(ctx, "slow downstream call", fields...)
+}
@@ -0,0 +1,63 @@
+package downstream
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	logger_api "github.com/mikros-dev/mikros/apis/features/logger"
+)
+
+type fakeLogger struct {
+	logger_api.API
+	messages []string
+	attrs    map[string]interface{}
+}
+
+func (f *fakeLogger) Warn(_ context.Context, msg string, attrs ...logger_api.Attribute) {
+	f.messages = append(f.messages, msg)
+	f.attrs = make(map[string]interface{})
+	for _, a := range attrs {
+		f.attrs[a.Key()] = a.Value()
+	}
+}
+
+type fakeTracker struct{}
+
+func (fakeTracker) Generate() string { return "id" }
+
+func (fakeTracker) Add(ctx context.Context, _ string) context.Context { return ctx }
+
+func (fakeTracker) Retrieve(_ context.Context) (string, bool) { return "abc", true }
+
+func TestSlowCallLogger(t *testing.T) {
+	t.Run("should be disabled without threshold", func(t *testing.T) {
+		s := NewSlowCallLogger(SlowCallOptions{Logger: &fakeLogger{}})
+		assert.Nil(t, s)
+
+		// A nil logger must be usable.
+		s.Observe(context.Background(), "users", "Get", time.Hour)
+		s.Track(context.Background(), "users", "Get")()
+	})
+
+	t.Run("should log calls above the threshold", func(t *testing.T) {
+		l := &fakeLogger{}
+		s := NewSlowCallLogger(SlowCallOptions{
+			Threshold: time.Second,
+			Logger:    l,
+			Tracker:   fakeTracker{},
+		})
+
+		s.Observe(context.Background(), "users", "/users.UserService/Get", 500*time.Millisecond)
+		assert.Empty(t, l.messages)
+
+		s.Observe(context.Background(), "users", "/users.UserService/Get", 2*time.Second)
+		assert.Equal(t, []string{"slow downstream call"}, l.messages)
+		assert.Equal(t, "users", l.attrs["downstream.destination"])
+		assert.Equal(t, "/users.UserService/Get", l.attrs["downstream.method"])
+		assert.Equal(t, "2s", l.attrs["downstream.duration"])
+		assert.Equal(t, "abc", l.attrs["tracker.id"])
+	})
+}
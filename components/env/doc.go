@@ -2,15 +2,27 @@
 //
 // Overview
 //
-//   - Tag syntax:        `env:"NAME[,default_value=VAL][,required]"`
+//   - Tag syntax:        `env:"NAME[,default_value=VAL][,required][,separator=SEP][,source=NAME]"`
 //   - Precedence:        SERVICE<sep>NAME → NAME (service-scoped overrides global)
 //   - Default separator: "__" (portable); can be changed via Options
-//   - Pointer fields:    rejected when tagged (use value types or Env[T])
+//   - Pointer fields:    rejected when tagged (use value types, Env[T] or
+//     Options.AllowPointers)
 //   - Missing values:    if `required` and not found (and no default) → error
 //     otherwise leave zero value (or zero Env[T] capturing VarName)
 //   - Supported types:   string, bool, int/int32/int64, uint/uint32/uint64,
 //     float32/float64, time.Duration,
-//     and custom types implementing encoding.TextUnmarshaler.
+//     and custom types implementing encoding.TextUnmarshaler,
+//     plus slices and maps of them.
+//
+// # Slices and maps
+//
+// Slice fields are parsed from separated values, e.g. HOSTS=a,b,c, and map
+// fields from separated key=value entries, e.g. LABELS=k1=v1,k2=v2. Items are
+// separated by "," unless Options.ListSeparator or the separator tag option
+// sets another one:
+//
+//	Hosts  []string          `env:"HOSTS"`
+//	Labels map[string]string `env:"LABELS,separator=;"` // LABELS=k1=v1;k2=v2
 //
 // # ServiceOptions-scoped precedence
 //
@@ -29,17 +41,131 @@
 // Env[T] wrappers
 //
 // Env[T] captures both the parsed value and the concrete environment variable
-// name used (via VarName). Supported instantiations are Env[string] and Env[int32].
+// name used (via VarName). T can be any of the supported types, e.g.
+// Env[bool], Env[int64], Env[float64], Env[time.Duration], Env[[]string] or
+// a TextUnmarshaler implementation.
 //
 // When a variable is not found and no default is provided, scalar fields keep
 // their zero value. For Env[T], a zero-valued wrapper is assigned and VarName
 // records the resolved key.
 //
-// # Pointers are not supported
+// # Variable expansion
+//
+// Values, including default ones, can reference other variables with
+// ${VAR}, which are resolved like tagged ones, i.e. service-scoped names
+// first. ${VAR:-fallback} uses fallback when VAR is not set or is empty,
+// and $${ gives a literal ${:
+//
+//	DB_URL=postgres://${DB_USER}:${DB_PASSWORD}@${DB_HOST:-localhost}:5432/app
+//
+// Values of referenced variables are not expanded again.
+//
+// # Dotenv files
+//
+// Options.DotEnvFiles lists dotenv files, useful for local development, whose
+// variables are used when they are not set in the process environment:
+//
+//	_ = env.Load(svc, &cfg, env.Options{DotEnvFiles: []string{".env", ".env.local"}})
+//
+// Each variable is resolved with the following precedence:
+//
+//	file__DB_HOST  // process environment
+//	DB_HOST        // process environment
+//	file__DB_HOST  // dotenv files
+//	DB_HOST        // dotenv files
+//	default_value
+//
+// Files contain KEY=VALUE lines, optionally prefixed by "export", and "#"
+// comments. Single-quoted values are taken literally, while double-quoted
+// ones support escape sequences. Variables of later files override the ones
+// of earlier files and files that don't exist are ignored.
+//
+// # Secret providers
+//
+// Fields with the source tag option are loaded from the SecretResolver
+// registered with its name in Options.SecretResolvers, instead of the process
+// environment and dotenv files:
+//
+//	type Config struct {
+//	    Password string `env:"DB_PASS,source=aws-sm,required"`
+//	}
+//
+//	_ = env.Load(svc, &cfg, env.Options{
+//	    SecretResolvers: map[string]env.SecretResolver{
+//	        "aws-sm": env.SecretResolverFunc(fetchSecret),
+//	    },
+//	})
+//
+// Resolvers receive the service-scoped name first and the global one when it
+// is not found. Resolved secrets are not expanded, and fields of unknown
+// sources fail to load.
+//
+// # Validation
 //
-// Tagged pointer fields (e.g., *int, *MyType) are rejected to avoid nil vs.
-// zero-value ambiguity and implicit allocation. Use a value field or wrap in
-// Env[T] if presence/source tracking is needed.
+// Fields may also have a validate tag, using the rules of
+// github.com/go-playground/validator, which are checked after the values are
+// coerced. Load returns every failure at once, without the invalid values:
+//
+//	type Config struct {
+//	    Port  int      `env:"PORT,default_value=8080" validate:"min=1,max=65535"`
+//	    Hosts []string `env:"HOSTS" validate:"dive,hostname"`
+//	}
+//
+// Env[T] fields are validated by their wrapped value. Options.Validator
+// allows using custom validations.
+//
+// # Load report
+//
+// Options.Report receives, once Load finishes, how every variable was
+// resolved: its source (service-scoped, global, default or missing) and
+// provider (process environment, dotenv files or secret resolver). Values
+// are not included, so it can be logged at startup:
+//
+//	_ = env.Load(svc, &cfg, env.Options{
+//	    Report: func(r env.LoadReport) {
+//	        for _, v := range r.Variables {
+//	            log.Printf("%s: %s (%s %s)", v.Name, v.Key, v.Source, v.Provider)
+//	        }
+//	    },
+//	})
+//
+// # Watching for changes
+//
+// Watch loads the struct, like Load, and keeps re-resolving its variables
+// periodically, with WatchOptions.Interval, or when the process receives
+// SIGHUP. Reloads that change any variable call a callback with their
+// differences, letting settings like the log level be tuned without a
+// restart:
+//
+//	err := env.Watch(ctx, svc, &cfg, func(d env.Diff) {
+//	    for _, c := range d.Changes {
+//	        log.Printf("%s changed from %v to %v", c.Name, c.Old, c.New)
+//	    }
+//	    apply(d.Current.(*Config))
+//	}, env.WatchOptions{Interval: time.Minute})
+//
+// The struct is only written by the initial load. Reloaded values are
+// delivered through Diff.Current, and failed reloads, reported to
+// WatchOptions.OnError, keep the previous ones.
+//
+// # Pointers
+//
+// Tagged pointer fields (e.g., *int, *MyType) are rejected by default to
+// avoid nil vs. zero-value ambiguity and implicit allocation. Use a value
+// field or wrap in Env[T] if presence/source tracking is needed.
+//
+// When presence is really needed, Options.AllowPointers accepts them: they
+// are nil when the variable is absent and point to a newly allocated value
+// when it is present or has a default value:
+//
+//	type Config struct {
+//	    MaxConns *int `env:"MAX_CONNS"`
+//	}
+//
+//	_ = env.Load(svc, &cfg, env.Options{AllowPointers: true})
+//	if cfg.MaxConns != nil {
+//	    pool.SetMaxConns(*cfg.MaxConns)
+//	}
 //
 // Examples
 //
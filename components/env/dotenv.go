@@ -0,0 +1,109 @@
+package env
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// loadDotEnvFiles reads the variables of dotenv files. Variables of later
+// files override the ones of earlier files and files that don't exist are
+// ignored, so the same options can be used where they are not deployed.
+func loadDotEnvFiles(paths []string) (map[string]string, error) {
+	vars := make(map[string]string)
+	for _, path := range paths {
+		f, err := os.Open(path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("env: could not open dotenv file: %w", err)
+		}
+
+		fileVars, err := parseDotEnv(f)
+		_ = f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("env: invalid dotenv file %q: %w", path, err)
+		}
+
+		for k, v := range fileVars {
+			vars[k] = v
+		}
+	}
+
+	return vars, nil
+}
+
+// parseDotEnv parses KEY=VALUE lines, optionally prefixed by "export".
+// Values can be single-quoted, taken literally, or double-quoted, with
+// escape sequences. Unquoted values end at a " #" comment.
+func parseDotEnv(r io.Reader) (map[string]string, error) {
+	var (
+		vars    = make(map[string]string)
+		scanner = bufio.NewScanner(r)
+		line    int
+	)
+
+	for scanner.Scan() {
+		line++
+
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		text = strings.TrimPrefix(text, "export ")
+		key, value, ok := strings.Cut(text, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" || strings.ContainsAny(key, " \t") {
+			return nil, fmt.Errorf("line %d: expected KEY=VALUE", line)
+		}
+
+		v, err := parseDotEnvValue(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		vars[key] = v
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return vars, nil
+}
+
+func parseDotEnvValue(value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+
+	switch value[0] {
+	case '\'':
+		end := strings.IndexByte(value[1:], '\'')
+		if end < 0 {
+			return "", errors.New("unterminated single-quoted value")
+		}
+
+		return value[1 : end+1], nil
+
+	case '"':
+		prefix, err := strconv.QuotedPrefix(value)
+		if err != nil {
+			return "", errors.New("invalid double-quoted value")
+		}
+
+		return strconv.Unquote(prefix)
+	}
+
+	if i := strings.Index(value, " #"); i >= 0 {
+		value = value[:i]
+	}
+
+	return strings.TrimSpace(value), nil
+}
@@ -0,0 +1,122 @@
+package env
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mikros-dev/mikros/components/service"
+)
+
+func writeDotEnv(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), ".env")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestParseDotEnv(t *testing.T) {
+	a := assert.New(t)
+
+	t.Run("parses values, quotes and comments", func(t *testing.T) {
+		vars, err := parseDotEnv(strings.NewReader(`
+# comment
+PLAIN=value
+export EXPORTED=yes
+SPACED = padded value # trailing comment
+SINGLE='literal \n $x'
+DOUBLE="line\nbreak"
+EMPTY=
+URL=http://host/#anchor
+`))
+
+		a.Nil(err)
+		a.Equal(map[string]string{
+			"PLAIN":    "value",
+			"EXPORTED": "yes",
+			"SPACED":   "padded value",
+			"SINGLE":   `literal \n $x`,
+			"DOUBLE":   "line\nbreak",
+			"EMPTY":    "",
+			"URL":      "http://host/#anchor",
+		}, vars)
+	})
+
+	t.Run("invalid lines report their number", func(t *testing.T) {
+		_, err := parseDotEnv(strings.NewReader("A=1\nINVALID\n"))
+		a.ErrorContains(err, "line 2")
+
+		_, err = parseDotEnv(strings.NewReader("A='unterminated\n"))
+		a.ErrorContains(err, "line 1")
+	})
+}
+
+func TestLoadDotEnvFiles(t *testing.T) {
+	var (
+		svc = service.FromString("example")
+		a   = assert.New(t)
+	)
+
+	type config struct {
+		Host    string `env:"DB_HOST"`
+		Port    int    `env:"DB_PORT,default_value=5432"`
+		User    string `env:"DB_USER,default_value=admin"`
+		Name    string `env:"DB_NAME,required"`
+		Timeout string `env:"DB_TIMEOUT"`
+	}
+
+	t.Run("process environment takes precedence over files", func(t *testing.T) {
+		path := writeDotEnv(t, "DB_HOST=file-host\nDB_PORT=6543\nexample__DB_NAME=file-name\nDB_NAME=global-name\n")
+		t.Setenv("DB_HOST", "process-host")
+
+		var cfg config
+		err := Load(svc, &cfg, Options{DotEnvFiles: []string{path}})
+
+		a.Nil(err)
+		a.Equal("process-host", cfg.Host)
+		a.Equal(6543, cfg.Port)
+		a.Equal("admin", cfg.User)
+		a.Equal("file-name", cfg.Name)
+	})
+
+	t.Run("global process variable precedes service-scoped file variable", func(t *testing.T) {
+		path := writeDotEnv(t, "example__DB_NAME=file-name\n")
+		t.Setenv("DB_NAME", "process-name")
+
+		var cfg config
+		err := Load(svc, &cfg, Options{DotEnvFiles: []string{path}})
+
+		a.Nil(err)
+		a.Equal("process-name", cfg.Name)
+	})
+
+	t.Run("later files override earlier ones and missing files are ignored", func(t *testing.T) {
+		first := writeDotEnv(t, "DB_NAME=first\nDB_TIMEOUT=1s\n")
+		second := writeDotEnv(t, "DB_NAME=second\n")
+
+		var cfg config
+		err := Load(svc, &cfg, Options{
+			DotEnvFiles: []string{first, filepath.Join(t.TempDir(), "missing.env"), second},
+		})
+
+		a.Nil(err)
+		a.Equal("second", cfg.Name)
+		a.Equal("1s", cfg.Timeout)
+	})
+
+	t.Run("invalid file fails", func(t *testing.T) {
+		path := writeDotEnv(t, "DB_NAME\n")
+
+		var cfg config
+		err := Load(svc, &cfg, Options{DotEnvFiles: []string{path}})
+
+		a.ErrorContains(err, "invalid dotenv file")
+	})
+}
@@ -10,11 +10,15 @@ import (
 	"strings"
 	"time"
 
+	"github.com/go-playground/validator/v10"
+
 	"github.com/mikros-dev/mikros/components/service"
+	"github.com/mikros-dev/mikros/internal/components/expand"
 )
 
 const (
-	separator = "__"
+	separator     = "__"
+	listSeparator = ","
 )
 
 var (
@@ -24,9 +28,10 @@ var (
 	errorNoTagName       = errors.New("'env' tag cannot be empty")
 	errorDefaultValue    = errors.New("default_value requires a value")
 	errorPointerField    = errors.New("env: pointer-typed fields are not supported; use value type or Env[T]")
+	errorSeparator       = errors.New("separator requires a value")
+	errorSource          = errors.New("source requires a value")
 
-	envStringType = reflect.TypeOf(Env[string]{})
-	envInt32Type  = reflect.TypeOf(Env[int32]{})
+	envWrapperType = reflect.TypeOf((*envWrapper)(nil)).Elem()
 
 	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
 	timeDurationType    = reflect.TypeOf(time.Duration(0))
@@ -36,6 +41,53 @@ var (
 // environment variables.
 type Options struct {
 	Separator string
+
+	// ListSeparator separates the items of slice and map fields, e.g.
+	// HOSTS=a,b,c. Fields can use another one with the separator tag
+	// option. Defaults to ",".
+	ListSeparator string
+
+	// DotEnvFiles are dotenv files, e.g. ".env", whose variables are used
+	// when they are not set in the process environment. Variables of later
+	// files override the ones of earlier files and files that don't exist
+	// are ignored.
+	DotEnvFiles []string
+
+	// SecretResolvers are the secret providers, by name, that fields with
+	// the source tag option are loaded from, instead of the process
+	// environment and DotEnvFiles.
+	SecretResolvers map[string]SecretResolver
+
+	// Report, if set, receives how every variable was resolved once Load
+	// finishes, even when it fails, so it can be logged at startup.
+	Report func(report LoadReport)
+
+	// AllowPointers accepts tagged pointer fields, e.g. *int, which are
+	// set to nil when their variable is absent, and to a newly allocated
+	// value when it is present or has a default value.
+	AllowPointers bool
+
+	// Validator checks the validate tags of fields, e.g.
+	// `validate:"min=1,max=65535"`. Defaults to a validator without custom
+	// validations.
+	Validator *validator.Validate
+}
+
+// lookupFunc retrieves the value of a variable from one of its sources.
+type lookupFunc func(key string) (string, bool)
+
+// lookupSource is a named source of variables.
+type lookupSource struct {
+	provider string
+	lookup   lookupFunc
+}
+
+// resolved is the result of looking up a variable.
+type resolved struct {
+	value    string
+	key      string
+	found    bool
+	provider string
 }
 
 // Env is a type that wraps an environment-backed value, exposing both its value
@@ -59,10 +111,36 @@ func (e Env[T]) VarName() string {
 	return e.varName
 }
 
+// envWrapper lets Load populate Env[T] fields of any T.
+type envWrapper interface {
+	valueType() reflect.Type
+	get() reflect.Value
+	set(value reflect.Value, varName string)
+}
+
+func (e *Env[T]) valueType() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+func (e *Env[T]) get() reflect.Value {
+	return reflect.ValueOf(&e.value).Elem()
+}
+
+// set sets the wrapped value, keeping the zero value when value is invalid.
+func (e *Env[T]) set(value reflect.Value, varName string) {
+	if value.IsValid() {
+		e.value = convertValue(value, e.valueType()).Interface().(T)
+	}
+	e.varName = varName
+}
+
 type envTag struct {
 	Required     bool
 	Name         string
 	DefaultValue string
+	Separator    string
+	Source       string
+	Validate     string
 }
 
 // Load populates a struct from environment variables.
@@ -70,23 +148,54 @@ type envTag struct {
 // Precedence:
 //  1. SERVICE<sep>KEY
 //  2. KEY
+//  3. SERVICE<sep>KEY from Options.DotEnvFiles
+//  4. KEY from Options.DotEnvFiles
 //
 // Example: if service is "file", the default separator is "__":
 //
 //	file__DB_HOST → DB_HOST
+//
+// Fields with a validate tag are checked once all of them are loaded, and
+// every failure is returned together.
 func Load(serviceName service.Name, target interface{}, options ...Options) error {
 	rv, rt, err := validateTarget(target)
 	if err != nil {
 		return err
 	}
 
-	opt := Options{
-		Separator: separator,
-	}
+	var opt Options
 	if len(options) > 0 {
 		opt = options[0]
 	}
+	if opt.Separator == "" {
+		opt.Separator = separator
+	}
+	if opt.ListSeparator == "" {
+		opt.ListSeparator = listSeparator
+	}
+
+	var report LoadReport
+	if opt.Report != nil {
+		defer func() { opt.Report(report) }()
+	}
 
+	lookups := []lookupSource{{provider: ProviderEnv, lookup: os.LookupEnv}}
+	if len(opt.DotEnvFiles) > 0 {
+		vars, err := loadDotEnvFiles(opt.DotEnvFiles)
+		if err != nil {
+			return err
+		}
+
+		lookups = append(lookups, lookupSource{
+			provider: ProviderDotEnv,
+			lookup: func(key string) (string, bool) {
+				v, ok := vars[key]
+				return v, ok
+			},
+		})
+	}
+
+	var validations []fieldValidation
 	for i := 0; i < rv.NumField(); i++ {
 		var (
 			f  = rt.Field(i)
@@ -106,32 +215,89 @@ func Load(serviceName service.Name, target interface{}, options ...Options) erro
 			continue
 		}
 
-		// Reject tagged pointer types
-		if f.Type.Kind() == reflect.Ptr {
+		// Reject tagged pointer types, unless asked not to. Pointers to
+		// pointers are always rejected.
+		if f.Type.Kind() == reflect.Ptr && (!opt.AllowPointers || f.Type.Elem().Kind() == reflect.Ptr) {
 			return fmt.Errorf("%w: %q", errorPointerField, f.Name)
 		}
 
-		if err := handleField(serviceName, opt, tag, f, fv); err != nil {
+		if err := handleField(serviceName, opt, lookups, tag, f, fv, &report); err != nil {
 			return err
 		}
+
+		if tag.Validate != "" {
+			validations = append(validations, fieldValidation{
+				name:  tag.Name,
+				rules: tag.Validate,
+				value: fv,
+			})
+		}
 	}
 
-	return nil
+	return validateFields(opt.Validator, validations)
 }
 
-func handleField(serviceName service.Name, opt Options, tag *envTag, f reflect.StructField, fv reflect.Value) error {
-	value, key, ok := resolveEnv(serviceName, tag, opt)
+func handleField(
+	serviceName service.Name,
+	opt Options,
+	lookups []lookupSource,
+	tag *envTag,
+	f reflect.StructField,
+	fv reflect.Value,
+	report *LoadReport,
+) error {
+	var res resolved
+	if tag.Source != "" {
+		var err error
+		res, err = resolveSecret(serviceName, tag, opt)
+		if err != nil {
+			return err
+		}
+	} else {
+		res = resolveEnv(serviceName, tag, opt, lookups)
+	}
+	report.add(f.Name, tag, res)
+
+	value, key, ok := res.value, res.key, res.found
+
 	if tag.Required && !ok && tag.DefaultValue == "" {
 		return fmt.Errorf("env: required env %q not set", tag.Name)
 	}
 
 	// If not found and no default, leave zero value — except Env[T], which
-	// we still populate to capture VarName.
+	// we still populate to capture VarName. Pointers are left nil.
 	if !ok && tag.DefaultValue == "" {
+		if f.Type.Kind() == reflect.Ptr {
+			fv.Set(reflect.Zero(f.Type))
+			return nil
+		}
+
 		return handleZeroValue(f, fv, key)
 	}
 
-	v, err := coerceValue(f, value, key)
+	// Secrets are used as they are, since they may contain anything.
+	if tag.Source == "" || !ok {
+		expanded, err := expand.Value(value, func(name string) (string, bool) {
+			r := resolveEnv(serviceName, &envTag{Name: name}, opt, lookups)
+			return r.value, r.found
+		}, nil)
+		if err != nil {
+			return fmt.Errorf("env: could not expand %q: %w", key, err)
+		}
+
+		value = expanded
+	}
+
+	sep := opt.ListSeparator
+	if tag.Separator != "" {
+		sep = tag.Separator
+	}
+
+	if f.Type.Kind() == reflect.Ptr {
+		return handlePointerField(f, fv, value, key, sep)
+	}
+
+	v, err := coerceValue(f, value, key, sep)
 	if err != nil {
 		return err
 	}
@@ -140,14 +306,27 @@ func handleField(serviceName service.Name, opt Options, tag *envTag, f reflect.S
 	return nil
 }
 
+// handlePointerField allocates a new value for a pointer field, coercing
+// value into the pointed type.
+func handlePointerField(f reflect.StructField, fv reflect.Value, value, key, sep string) error {
+	elem := f
+	elem.Type = f.Type.Elem()
+
+	v, err := coerceValue(elem, value, key, sep)
+	if err != nil {
+		return err
+	}
+
+	ptr := reflect.New(elem.Type)
+	assignField(ptr.Elem(), v)
+	fv.Set(ptr)
+
+	return nil
+}
+
 func handleZeroValue(f reflect.StructField, fv reflect.Value, key string) error {
 	if isEnvWrapperType(f.Type) {
-		v, err := zeroEnvWrapperValue(f.Type, key)
-		if err != nil {
-			return err
-		}
-
-		assignField(fv, v)
+		assignField(fv, newEnvWrapperValue(f.Type, reflect.Value{}, key))
 	}
 
 	return nil
@@ -185,7 +364,8 @@ func parseFieldTag(tag reflect.StructTag) (*envTag, error) {
 	}
 
 	t := &envTag{
-		Name: strings.TrimSpace(entries[0]),
+		Name:     strings.TrimSpace(entries[0]),
+		Validate: tag.Get("validate"),
 	}
 
 	for _, entry := range entries[1:] {
@@ -203,6 +383,18 @@ func parseFieldTag(tag reflect.StructTag) (*envTag, error) {
 			}
 
 			t.DefaultValue = trimQuotes(strings.TrimSpace(v))
+		case "separator":
+			if !ok || strings.TrimSpace(v) == "" {
+				return nil, errorSeparator
+			}
+
+			t.Separator = strings.TrimSpace(v)
+		case "source":
+			if !ok || strings.TrimSpace(v) == "" {
+				return nil, errorSource
+			}
+
+			t.Source = strings.TrimSpace(v)
 		}
 	}
 
@@ -221,70 +413,76 @@ func trimQuotes(s string) string {
 	return s[1 : len(s)-1]
 }
 
-func resolveEnv(serviceName service.Name, tag *envTag, options Options) (string, string, bool) {
+// resolveEnv looks up the variable of tag in every source, in order, with
+// the service-scoped name preceding the global one inside each of them.
+func resolveEnv(serviceName service.Name, tag *envTag, options Options, lookups []lookupSource) resolved {
 	key := serviceName.String() + options.Separator + tag.Name
 
-	if value, ok := os.LookupEnv(key); ok {
-		return value, key, true
+	for _, source := range lookups {
+		for _, k := range []string{key, tag.Name} {
+			if value, ok := source.lookup(k); ok {
+				return resolved{
+					value:    value,
+					key:      k,
+					found:    true,
+					provider: source.provider,
+				}
+			}
+		}
 	}
 
-	if value, ok := os.LookupEnv(tag.Name); ok {
-		return value, tag.Name, true
+	return resolved{
+		value: tag.DefaultValue,
+		key:   tag.Name,
 	}
-
-	return tag.DefaultValue, tag.Name, false
 }
 
 func isEnvWrapperType(t reflect.Type) bool {
-	if t == envStringType {
-		return true
-	}
-	if t == envInt32Type {
-		return true
-	}
-
-	return false
+	return t.Kind() == reflect.Struct && reflect.PointerTo(t).Implements(envWrapperType)
 }
 
-func zeroEnvWrapperValue(t reflect.Type, key string) (reflect.Value, error) {
-	if t == envStringType {
-		return reflect.ValueOf(Env[string]{
-			value:   "",
-			varName: key,
-		}), nil
-	}
-	if t == envInt32Type {
-		return reflect.ValueOf(Env[int32]{
-			value:   0,
-			varName: key,
-		}), nil
-	}
+// newEnvWrapperValue creates an Env[T] value of type t wrapping value.
+func newEnvWrapperValue(t reflect.Type, value reflect.Value, key string) reflect.Value {
+	ptr := reflect.New(t)
+	ptr.Interface().(envWrapper).set(value, key)
 
-	return reflect.Value{}, fmt.Errorf("unsupported Env wrapper type %v", t)
+	return ptr.Elem()
 }
 
-func coerceValue(sf reflect.StructField, value, key string) (reflect.Value, error) {
+func coerceValue(sf reflect.StructField, value, key, sep string) (reflect.Value, error) {
 	t := sf.Type
 
-	// Check for Env[T] types
-	if t == envStringType {
-		return reflect.ValueOf(Env[string]{
-			value:   value,
-			varName: key,
-		}), nil
-	}
-	if t == envInt32Type {
-		n, err := parseInt(value, 32)
+	// Env[T] wraps any of the other supported types.
+	if isEnvWrapperType(t) {
+		inner := reflect.New(t).Interface().(envWrapper).valueType()
+		v, err := coerceTypeValue(inner, value, sep)
 		if err != nil {
 			return reflect.Value{}, err
 		}
 
-		return reflect.ValueOf(Env[int32]{
-			value:   int32(n),
-			varName: key,
-		}), nil
+		return newEnvWrapperValue(t, v, key), nil
+	}
+
+	return coerceTypeValue(t, value, sep)
+}
+
+func coerceTypeValue(t reflect.Type, value, sep string) (reflect.Value, error) {
+	// Slices and maps implementing UnmarshalText, like net.IP, are handled
+	// as single values.
+	if !implementsTextUnmarshaler(t) {
+		switch t.Kind() {
+		case reflect.Slice:
+			return coerceSliceValue(t, value, sep)
+		case reflect.Map:
+			return coerceMapValue(t, value, sep)
+		default:
+		}
 	}
 
+	return coerceSingleValue(t, value)
+}
+
+func coerceSingleValue(t reflect.Type, value string) (reflect.Value, error) {
 	// time.Duration
 	if t == timeDurationType {
 		d, err := time.ParseDuration(strings.TrimSpace(value))
@@ -304,6 +502,69 @@ func coerceValue(sf reflect.StructField, value, key string) (reflect.Value, erro
 	return coerceScalarValue(t, value)
 }
 
+// coerceSliceValue parses the items of value, separated by sep, into a slice
+// of type t. Empty items are ignored.
+func coerceSliceValue(t reflect.Type, value, sep string) (reflect.Value, error) {
+	out := reflect.MakeSlice(t, 0, 0)
+	for _, item := range splitItems(value, sep) {
+		v, err := coerceSingleValue(t.Elem(), item)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		out = reflect.Append(out, convertValue(v, t.Elem()))
+	}
+
+	return out, nil
+}
+
+// coerceMapValue parses the key=value entries of value, separated by sep,
+// into a map of type t. Empty entries are ignored.
+func coerceMapValue(t reflect.Type, value, sep string) (reflect.Value, error) {
+	out := reflect.MakeMap(t)
+	for _, entry := range splitItems(value, sep) {
+		k, v, ok := strings.Cut(entry, "=")
+		if !ok {
+			return reflect.Value{}, fmt.Errorf("invalid map entry %q, expected key=value", entry)
+		}
+
+		key, err := coerceSingleValue(t.Key(), strings.TrimSpace(k))
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		elem, err := coerceSingleValue(t.Elem(), strings.TrimSpace(v))
+		if err != nil {
+			return reflect.Value{}, err
+		}
+
+		out.SetMapIndex(convertValue(key, t.Key()), convertValue(elem, t.Elem()))
+	}
+
+	return out, nil
+}
+
+func splitItems(value, sep string) []string {
+	var items []string
+	for _, item := range strings.Split(value, sep) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
+// convertValue converts v into t, which is needed for named types, like
+// definition.DeploymentEnv, coerced from their underlying kind.
+func convertValue(v reflect.Value, t reflect.Type) reflect.Value {
+	if v.Type() != t && v.Type().ConvertibleTo(t) {
+		return v.Convert(t)
+	}
+
+	return v
+}
+
 func coerceScalarValue(t reflect.Type, value string) (reflect.Value, error) {
 	switch t.Kind() {
 	case reflect.String:
@@ -1,6 +1,7 @@
 package env
 
 import (
+	"net"
 	"testing"
 	"time"
 
@@ -212,6 +213,51 @@ func TestLoad(t *testing.T) {
 		a.ErrorContains(err, "env: pointer-typed fields are not supported; use value type or Env[T]")
 	})
 
+	t.Run("target with pointers allowed", func(t *testing.T) {
+		t.Setenv("MAX_CONNS", "25")
+		t.Setenv("example__POOL", "primary")
+
+		var cfg struct {
+			MaxConns *int           `env:"MAX_CONNS"`
+			Timeout  *time.Duration `env:"TIMEOUT,default_value=5s"`
+			Region   *string        `env:"REGION"`
+			Hosts    *[]string      `env:"HOSTS"`
+			Pool     *Env[string]   `env:"POOL"`
+		}
+		cfg.Region = new(string)
+
+		err := Load(svc, &cfg, Options{AllowPointers: true})
+		a.NoError(err)
+		a.Equal(25, *cfg.MaxConns)
+		a.Equal(5*time.Second, *cfg.Timeout)
+		a.Nil(cfg.Region)
+		a.Nil(cfg.Hosts)
+		a.Equal("primary", cfg.Pool.Value())
+		a.Equal("example__POOL", cfg.Pool.VarName())
+	})
+
+	t.Run("target with pointers allowed and invalid values", func(t *testing.T) {
+		t.Setenv("MAX_CONNS", "many")
+
+		var cfg struct {
+			MaxConns *int `env:"MAX_CONNS"`
+		}
+
+		err := Load(svc, &cfg, Options{AllowPointers: true})
+		a.Error(err)
+		a.Nil(cfg.MaxConns)
+
+		var required struct {
+			Port *int `env:"PORT_NUMBER,required"`
+		}
+		a.ErrorContains(Load(svc, &required, Options{AllowPointers: true}), "required env")
+
+		var double struct {
+			Port **int `env:"PORT_NUMBER"`
+		}
+		a.ErrorContains(Load(svc, &double, Options{AllowPointers: true}), "pointer-typed fields are not supported")
+	})
+
 	t.Run("target with convertible types", func(t *testing.T) {
 		type Port int32
 		type Label string
@@ -248,4 +294,110 @@ func TestLoad(t *testing.T) {
 		a.NotNil(err)
 		a.ErrorContains(err, "default_value requires a value")
 	})
+
+	t.Run("slices and maps from separated values", func(t *testing.T) {
+		t.Setenv("HOSTS", "a, b,,c")
+		t.Setenv("PORTS", "80|443")
+		t.Setenv("LABELS", "k1=v1;k2 = v2")
+		t.Setenv("TIMEOUTS", "read=1s,write=2s")
+
+		var cfg struct {
+			Hosts    []string                   `env:"HOSTS"`
+			Ports    []int                      `env:"PORTS,separator=|"`
+			Labels   map[string]string          `env:"LABELS,separator=;"`
+			Timeouts map[string]time.Duration   `env:"TIMEOUTS"`
+			Envs     []definition.DeploymentEnv `env:"ENVS,default_value=dev"`
+		}
+
+		err := Load(svc, &cfg)
+		a.Nil(err)
+		a.Equal([]string{"a", "b", "c"}, cfg.Hosts)
+		a.Equal([]int{80, 443}, cfg.Ports)
+		a.Equal(map[string]string{"k1": "v1", "k2": "v2"}, cfg.Labels)
+		a.Equal(map[string]time.Duration{"read": time.Second, "write": 2 * time.Second}, cfg.Timeouts)
+		a.Equal([]definition.DeploymentEnv{definition.DeploymentEnvDevelopment}, cfg.Envs)
+	})
+
+	t.Run("custom list separator", func(t *testing.T) {
+		t.Setenv("HOSTS", "a;b")
+
+		var cfg struct {
+			Hosts []string `env:"HOSTS"`
+		}
+
+		err := Load(svc, &cfg, Options{Separator: separator, ListSeparator: ";"})
+		a.Nil(err)
+		a.Equal([]string{"a", "b"}, cfg.Hosts)
+	})
+
+	t.Run("invalid slice and map values", func(t *testing.T) {
+		t.Setenv("PORTS", "80,http")
+		t.Setenv("LABELS", "k1")
+
+		var ports struct {
+			Ports []int `env:"PORTS"`
+		}
+		a.NotNil(Load(svc, &ports))
+
+		var labels struct {
+			Labels map[string]string `env:"LABELS"`
+		}
+		a.ErrorContains(Load(svc, &labels), `invalid map entry "k1"`)
+
+		var sep struct {
+			Labels map[string]string `env:"LABELS,separator"`
+		}
+		a.ErrorContains(Load(svc, &sep), "separator requires a value")
+	})
+
+	t.Run("Env wrapper of any supported type", func(t *testing.T) {
+		t.Setenv("ENABLED", "true")
+		t.Setenv("example__LIMIT", "9000000000")
+		t.Setenv("RATIO", "0.75")
+		t.Setenv("TIMEOUT", "3s")
+		t.Setenv("HOSTS", "a,b")
+		t.Setenv("DEPLOY_ENV", "dev")
+		t.Setenv("BIND_IP", "10.0.0.1")
+
+		var cfg struct {
+			Enabled Env[bool]                     `env:"ENABLED"`
+			Limit   Env[int64]                    `env:"LIMIT"`
+			Ratio   Env[float64]                  `env:"RATIO"`
+			Timeout Env[time.Duration]            `env:"TIMEOUT"`
+			Hosts   Env[[]string]                 `env:"HOSTS"`
+			Deploy  Env[definition.DeploymentEnv] `env:"DEPLOY_ENV"`
+			Retries Env[uint]                     `env:"RETRIES,default_value=3"`
+			Missing Env[time.Duration]            `env:"MISSING"`
+			IP      Env[net.IP]                   `env:"BIND_IP"`
+		}
+
+		err := Load(svc, &cfg)
+		a.Nil(err)
+		a.True(cfg.Enabled.Value())
+		a.Equal(int64(9000000000), cfg.Limit.Value())
+		a.Equal("example__LIMIT", cfg.Limit.VarName())
+		a.Equal(0.75, cfg.Ratio.Value())
+		a.Equal(3*time.Second, cfg.Timeout.Value())
+		a.Equal([]string{"a", "b"}, cfg.Hosts.Value())
+		a.Equal(definition.DeploymentEnvDevelopment, cfg.Deploy.Value())
+		a.Equal(uint(3), cfg.Retries.Value())
+		a.Equal("RETRIES", cfg.Retries.VarName())
+		a.Zero(cfg.Missing.Value())
+		a.Equal("MISSING", cfg.Missing.VarName())
+		a.Equal("10.0.0.1", cfg.IP.Value().String())
+	})
+
+	t.Run("Env wrapper of invalid values", func(t *testing.T) {
+		t.Setenv("ENABLED", "maybe")
+
+		var cfg struct {
+			Enabled Env[bool] `env:"ENABLED"`
+		}
+		a.NotNil(Load(svc, &cfg))
+
+		var unsupported struct {
+			Value Env[complex64] `env:"ENABLED"`
+		}
+		a.ErrorContains(Load(svc, &unsupported), "unsupported type")
+	})
 }
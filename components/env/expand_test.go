@@ -0,0 +1,60 @@
+package env
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mikros-dev/mikros/components/service"
+)
+
+func TestLoadExpansion(t *testing.T) {
+	var (
+		svc = service.FromString("example")
+		a   = assert.New(t)
+	)
+
+	type config struct {
+		URL     string   `env:"DB_URL"`
+		Data    string   `env:"DATA_DIR,default_value=${BASE_DIR:-/var}/data"`
+		Port    int      `env:"DB_PORT"`
+		Brokers []string `env:"BROKERS"`
+	}
+
+	t.Run("expands values and defaults", func(t *testing.T) {
+		t.Setenv("DB_URL", "postgres://${DB_USER}@${DB_HOST:-localhost}:${DB_PORT}/app")
+		t.Setenv("DB_USER", "admin")
+		t.Setenv("example__DB_USER", "service")
+		t.Setenv("DB_PORT", "${DEFAULT_PORT:-5432}")
+		t.Setenv("BROKERS", "${BROKER_A},b")
+		t.Setenv("BROKER_A", "a")
+
+		var cfg config
+		err := Load(svc, &cfg)
+
+		a.Nil(err)
+		a.Equal("postgres://service@localhost:${DEFAULT_PORT:-5432}/app", cfg.URL)
+		a.Equal("/var/data", cfg.Data)
+		a.Equal(5432, cfg.Port)
+		a.Equal([]string{"a", "b"}, cfg.Brokers)
+	})
+
+	t.Run("references can come from dotenv files", func(t *testing.T) {
+		path := writeDotEnv(t, "BASE_DIR=/srv\n")
+
+		var cfg config
+		err := Load(svc, &cfg, Options{DotEnvFiles: []string{path}})
+
+		a.Nil(err)
+		a.Equal("/srv/data", cfg.Data)
+	})
+
+	t.Run("invalid reference fails", func(t *testing.T) {
+		t.Setenv("DB_URL", "${DB_USER")
+
+		var cfg config
+		err := Load(svc, &cfg)
+
+		a.ErrorContains(err, `could not expand "DB_URL"`)
+	})
+}
@@ -0,0 +1,84 @@
+package env
+
+// VariableSource tells where the value of a variable came from.
+type VariableSource string
+
+// Sources of variables values.
+const (
+	// VariableSourceService is a service-scoped variable, e.g. file__DB_HOST.
+	VariableSourceService VariableSource = "service"
+
+	// VariableSourceGlobal is a variable without the service name.
+	VariableSourceGlobal VariableSource = "global"
+
+	// VariableSourceDefault is the default_value of the field tag.
+	VariableSourceDefault VariableSource = "default"
+
+	// VariableSourceMissing is a variable not found, without default value.
+	VariableSourceMissing VariableSource = "missing"
+)
+
+// Providers of variables found by Load. Secrets use the name of their
+// SecretResolver.
+const (
+	ProviderEnv    = "env"
+	ProviderDotEnv = "dotenv"
+)
+
+// LoadReport lists how the variables of a Load call were resolved, in the
+// order of the struct fields. It doesn't hold values, so it can be logged
+// without leaking secrets.
+type LoadReport struct {
+	Variables []VariableReport
+}
+
+// VariableReport describes how a single variable was resolved.
+type VariableReport struct {
+	// Field is the name of the struct field.
+	Field string
+
+	// Name is the variable name of the field tag.
+	Name string
+
+	// Key is the variable actually used, which is the service-scoped one
+	// when it is set.
+	Key    string
+	Source VariableSource
+
+	// Provider is where found variables came from: ProviderEnv,
+	// ProviderDotEnv or the name of a SecretResolver.
+	Provider string
+}
+
+// Missing returns the names of the variables that were not found and had no
+// default value.
+func (r LoadReport) Missing() []string {
+	var names []string
+	for _, v := range r.Variables {
+		if v.Source == VariableSourceMissing {
+			names = append(names, v.Name)
+		}
+	}
+
+	return names
+}
+
+func (r *LoadReport) add(field string, tag *envTag, res resolved) {
+	source := VariableSourceMissing
+	switch {
+	case res.found && res.key != tag.Name:
+		source = VariableSourceService
+	case res.found:
+		source = VariableSourceGlobal
+	case tag.DefaultValue != "":
+		source = VariableSourceDefault
+	}
+
+	r.Variables = append(r.Variables, VariableReport{
+		Field:    field,
+		Name:     tag.Name,
+		Key:      res.key,
+		Source:   source,
+		Provider: res.provider,
+	})
+}
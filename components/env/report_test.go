@@ -0,0 +1,68 @@
+package env
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mikros-dev/mikros/components/service"
+)
+
+func TestLoadReport(t *testing.T) {
+	var (
+		svc = service.FromString("example")
+		a   = assert.New(t)
+	)
+
+	type config struct {
+		Host     string `env:"DB_HOST"`
+		Port     int    `env:"DB_PORT,default_value=5432"`
+		User     string `env:"DB_USER"`
+		Name     string `env:"DB_NAME"`
+		Timeout  string `env:"DB_TIMEOUT"`
+		Password string `env:"DB_PASS,source=vault"`
+		Ignored  string
+	}
+
+	t.Run("reports the source of every variable", func(t *testing.T) {
+		t.Setenv("example__DB_HOST", "scoped")
+		t.Setenv("DB_USER", "admin")
+		path := writeDotEnv(t, "DB_NAME=app\n")
+
+		var (
+			cfg    config
+			report LoadReport
+		)
+		err := Load(svc, &cfg, Options{
+			DotEnvFiles:     []string{path},
+			SecretResolvers: map[string]SecretResolver{"vault": mapResolver{"DB_PASS": "secret"}},
+			Report:          func(r LoadReport) { report = r },
+		})
+
+		a.Nil(err)
+		a.Equal([]VariableReport{
+			{Field: "Host", Name: "DB_HOST", Key: "example__DB_HOST", Source: VariableSourceService, Provider: ProviderEnv},
+			{Field: "Port", Name: "DB_PORT", Key: "DB_PORT", Source: VariableSourceDefault},
+			{Field: "User", Name: "DB_USER", Key: "DB_USER", Source: VariableSourceGlobal, Provider: ProviderEnv},
+			{Field: "Name", Name: "DB_NAME", Key: "DB_NAME", Source: VariableSourceGlobal, Provider: ProviderDotEnv},
+			{Field: "Timeout", Name: "DB_TIMEOUT", Key: "DB_TIMEOUT", Source: VariableSourceMissing},
+			{Field: "Password", Name: "DB_PASS", Key: "DB_PASS", Source: VariableSourceGlobal, Provider: "vault"},
+		}, report.Variables)
+		a.Equal([]string{"DB_TIMEOUT"}, report.Missing())
+	})
+
+	t.Run("reports variables resolved before a failure", func(t *testing.T) {
+		t.Setenv("DB_HOST", "localhost")
+		t.Setenv("DB_PORT", "invalid")
+
+		var (
+			cfg    config
+			report LoadReport
+		)
+		err := Load(svc, &cfg, Options{Report: func(r LoadReport) { report = r }})
+
+		a.NotNil(err)
+		a.Len(report.Variables, 2)
+		a.Equal("DB_PORT", report.Variables[1].Key)
+	})
+}
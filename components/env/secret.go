@@ -0,0 +1,57 @@
+package env
+
+import (
+	"fmt"
+
+	"github.com/mikros-dev/mikros/components/service"
+)
+
+// SecretResolver fetches values from a secret provider, e.g. AWS Secrets
+// Manager or Vault. Resolvers are registered in Options.SecretResolvers and
+// used by fields with the source tag option:
+//
+//	Password string `env:"DB_PASS,source=aws-sm"`
+type SecretResolver interface {
+	// Resolve returns the value of the secret name. It must return false,
+	// and no error, when the secret doesn't exist.
+	Resolve(name string) (string, bool, error)
+}
+
+// SecretResolverFunc is a function implementing SecretResolver.
+type SecretResolverFunc func(name string) (string, bool, error)
+
+// Resolve calls f(name).
+func (f SecretResolverFunc) Resolve(name string) (string, bool, error) {
+	return f(name)
+}
+
+// resolveSecret looks up the variable of tag in the secret resolver of its
+// source, with the service-scoped name preceding the global one.
+func resolveSecret(serviceName service.Name, tag *envTag, options Options) (resolved, error) {
+	resolver, ok := options.SecretResolvers[tag.Source]
+	if !ok || resolver == nil {
+		return resolved{}, fmt.Errorf("env: unknown source %q for %q", tag.Source, tag.Name)
+	}
+
+	var resolveErr error
+	lookup := func(key string) (string, bool) {
+		if resolveErr != nil {
+			return "", false
+		}
+
+		value, ok, err := resolver.Resolve(key)
+		if err != nil {
+			resolveErr = fmt.Errorf("env: could not resolve %q from source %q: %w", key, tag.Source, err)
+			return "", false
+		}
+
+		return value, ok
+	}
+
+	res := resolveEnv(serviceName, tag, options, []lookupSource{{provider: tag.Source, lookup: lookup}})
+	if resolveErr != nil {
+		return resolved{}, resolveErr
+	}
+
+	return res, nil
+}
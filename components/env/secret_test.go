@@ -0,0 +1,95 @@
+package env
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mikros-dev/mikros/components/service"
+)
+
+type mapResolver map[string]string
+
+func (m mapResolver) Resolve(name string) (string, bool, error) {
+	v, ok := m[name]
+	return v, ok, nil
+}
+
+func TestLoadSecrets(t *testing.T) {
+	var (
+		svc = service.FromString("example")
+		a   = assert.New(t)
+	)
+
+	type config struct {
+		Password string      `env:"DB_PASS,source=vault,required"`
+		Token    Env[string] `env:"API_TOKEN,source=vault"`
+		Port     int         `env:"DB_PORT,source=vault,default_value=5432"`
+		User     string      `env:"DB_USER"`
+	}
+
+	t.Run("loads fields from their sources", func(t *testing.T) {
+		t.Setenv("DB_PASS", "from-env")
+		t.Setenv("DB_USER", "admin")
+
+		var cfg config
+		err := Load(svc, &cfg, Options{
+			SecretResolvers: map[string]SecretResolver{
+				"vault": mapResolver{
+					"DB_PASS":            "p4ss${word}",
+					"example__API_TOKEN": "scoped",
+					"API_TOKEN":          "global",
+				},
+			},
+		})
+
+		a.Nil(err)
+		a.Equal("p4ss${word}", cfg.Password)
+		a.Equal("scoped", cfg.Token.Value())
+		a.Equal("example__API_TOKEN", cfg.Token.VarName())
+		a.Equal(5432, cfg.Port)
+		a.Equal("admin", cfg.User)
+	})
+
+	t.Run("missing required secret fails", func(t *testing.T) {
+		t.Setenv("DB_PASS", "from-env")
+
+		var cfg config
+		err := Load(svc, &cfg, Options{
+			SecretResolvers: map[string]SecretResolver{"vault": mapResolver{}},
+		})
+
+		a.ErrorContains(err, `required env "DB_PASS" not set`)
+	})
+
+	t.Run("resolver errors are returned", func(t *testing.T) {
+		var cfg config
+		err := Load(svc, &cfg, Options{
+			SecretResolvers: map[string]SecretResolver{
+				"vault": SecretResolverFunc(func(string) (string, bool, error) {
+					return "", false, errors.New("permission denied")
+				}),
+			},
+		})
+
+		a.ErrorContains(err, "permission denied")
+		a.ErrorContains(err, `source "vault"`)
+	})
+
+	t.Run("unknown source fails", func(t *testing.T) {
+		var cfg config
+		err := Load(svc, &cfg)
+
+		a.ErrorContains(err, `unknown source "vault"`)
+	})
+
+	t.Run("empty source fails", func(t *testing.T) {
+		var cfg struct {
+			Password string `env:"DB_PASS,source="`
+		}
+		err := Load(svc, &cfg)
+
+		a.ErrorIs(err, errorSource)
+	})
+}
@@ -0,0 +1,63 @@
+package env
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/go-playground/validator/v10"
+)
+
+var defaultValidator = sync.OnceValue(func() *validator.Validate {
+	return validator.New()
+})
+
+// fieldValidation is a loaded field waiting for its validate tag to be
+// checked.
+type fieldValidation struct {
+	name  string
+	rules string
+	value reflect.Value
+}
+
+// validateFields checks the validate tags of every loaded field, returning
+// all failures joined, so they are fixed at once.
+func validateFields(v *validator.Validate, fields []fieldValidation) error {
+	if v == nil {
+		v = defaultValidator()
+	}
+
+	var errs []error
+	for _, f := range fields {
+		value := f.value
+		if value.Kind() == reflect.Ptr && !value.IsNil() && isEnvWrapperType(value.Type().Elem()) {
+			value = value.Elem()
+		}
+		if isEnvWrapperType(value.Type()) {
+			value = value.Addr().Interface().(envWrapper).get()
+		}
+
+		if err := v.Var(value.Interface(), f.rules); err != nil {
+			errs = append(errs, validationError(f.name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// validationError describes why a variable is invalid without its value,
+// which may be a secret.
+func validationError(name string, err error) error {
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) || len(validationErrors) == 0 {
+		return fmt.Errorf("env: could not validate %q: %w", name, err)
+	}
+
+	fe := validationErrors[0]
+	if fe.Param() != "" {
+		return fmt.Errorf("env: invalid %q: failed on '%s=%s' validation", name, fe.Tag(), fe.Param())
+	}
+
+	return fmt.Errorf("env: invalid %q: failed on '%s' validation", name, fe.Tag())
+}
@@ -0,0 +1,84 @@
+package env
+
+import (
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/mikros-dev/mikros/components/service"
+)
+
+func TestLoadValidation(t *testing.T) {
+	var (
+		svc = service.FromString("example")
+		a   = assert.New(t)
+	)
+
+	type config struct {
+		Port    int      `env:"PORT,default_value=8080" validate:"min=1,max=65535"`
+		Mode    string   `env:"MODE" validate:"omitempty,oneof=fast safe"`
+		Workers Env[int] `env:"WORKERS,default_value=4" validate:"gte=1"`
+		Hosts   []string `env:"HOSTS" validate:"dive,hostname"`
+	}
+
+	t.Run("accepts valid values", func(t *testing.T) {
+		t.Setenv("MODE", "safe")
+		t.Setenv("HOSTS", "db1,db2")
+
+		var cfg config
+		err := Load(svc, &cfg)
+		a.NoError(err)
+		a.Equal(8080, cfg.Port)
+		a.Equal(4, cfg.Workers.Value())
+	})
+
+	t.Run("aggregates every validation failure", func(t *testing.T) {
+		t.Setenv("PORT", "70000")
+		t.Setenv("MODE", "slow")
+		t.Setenv("example__WORKERS", "0")
+
+		var cfg config
+		err := Load(svc, &cfg)
+		a.Error(err)
+		a.ErrorContains(err, `env: invalid "PORT": failed on 'max=65535' validation`)
+		a.ErrorContains(err, `env: invalid "MODE": failed on 'oneof=fast safe' validation`)
+		a.ErrorContains(err, `env: invalid "WORKERS": failed on 'gte=1' validation`)
+		a.NotContains(err.Error(), "70000")
+	})
+
+	t.Run("validates slice items", func(t *testing.T) {
+		t.Setenv("HOSTS", "db1,not a host")
+
+		var cfg config
+		err := Load(svc, &cfg)
+		a.ErrorContains(err, `env: invalid "HOSTS": failed on 'hostname' validation`)
+	})
+
+	t.Run("validates allowed pointers", func(t *testing.T) {
+		t.Setenv("LIMIT", "0")
+
+		var cfg struct {
+			Limit   *int      `env:"LIMIT" validate:"omitempty,gte=1"`
+			Retries *int      `env:"RETRIES" validate:"omitempty,gte=1"`
+			Workers *Env[int] `env:"WORKERS" validate:"omitnil,gte=1"`
+		}
+		err := Load(svc, &cfg, Options{AllowPointers: true})
+		a.ErrorContains(err, `env: invalid "LIMIT": failed on 'gte=1' validation`)
+		a.NotContains(err.Error(), "RETRIES")
+		a.NotContains(err.Error(), "WORKERS")
+	})
+
+	t.Run("uses custom validators", func(t *testing.T) {
+		v := validator.New()
+		a.NoError(v.RegisterValidation("even", func(fl validator.FieldLevel) bool {
+			return fl.Field().Int()%2 == 0
+		}))
+
+		var cfg struct {
+			Size int `env:"SIZE,default_value=3" validate:"even"`
+		}
+		err := Load(svc, &cfg, Options{Validator: v})
+		a.ErrorContains(err, `env: invalid "SIZE": failed on 'even' validation`)
+	})
+}
@@ -0,0 +1,181 @@
+package env
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"reflect"
+	"syscall"
+	"time"
+
+	"github.com/mikros-dev/mikros/components/service"
+)
+
+// WatchOptions defines how Watch reloads variables.
+type WatchOptions struct {
+	// Options are used by every load.
+	Options Options
+
+	// Interval between periodic reloads. Zero disables them, leaving only
+	// the reloads triggered by Signals.
+	Interval time.Duration
+
+	// Signals that trigger a reload. Defaults to SIGHUP.
+	Signals []os.Signal
+
+	// OnError, if set, receives errors of reloads. Variables keep their
+	// previous values when a reload fails.
+	OnError func(err error)
+}
+
+// Change is a variable whose value changed between two loads.
+type Change struct {
+	// Field is the name of the struct field.
+	Field string
+
+	// Name is the variable name of the field tag.
+	Name string
+
+	// Old and New are the field values before and after the reload.
+	Old interface{}
+	New interface{}
+}
+
+// Diff is the result of a reload that changed at least one variable.
+type Diff struct {
+	// Changes are the changed variables, in the order of the struct fields.
+	Changes []Change
+
+	// Current points to a new struct, of the same type as the Watch target,
+	// holding all reloaded values.
+	Current interface{}
+}
+
+// Watch loads target, like Load, and keeps re-resolving its variables,
+// periodically or when the process receives one of the configured signals,
+// until ctx is done. Every reload changing a variable calls onChange with
+// the differences.
+//
+// target is only written by the initial load, so it can be read without
+// synchronization. Reloaded values are delivered through Diff.Current.
+func Watch(
+	ctx context.Context,
+	serviceName service.Name,
+	target interface{},
+	onChange func(diff Diff),
+	options ...WatchOptions,
+) error {
+	var opt WatchOptions
+	if len(options) > 0 {
+		opt = options[0]
+	}
+	if len(opt.Signals) == 0 {
+		opt.Signals = []os.Signal{syscall.SIGHUP}
+	}
+
+	if err := Load(serviceName, target, opt.Options); err != nil {
+		return err
+	}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, opt.Signals...)
+
+	w := &watcher{
+		serviceName: serviceName,
+		options:     opt,
+		onChange:    onChange,
+		current:     reflect.ValueOf(target).Elem(),
+	}
+
+	go func() {
+		defer signal.Stop(signals)
+		w.run(ctx, signals)
+	}()
+
+	return nil
+}
+
+type watcher struct {
+	serviceName service.Name
+	options     WatchOptions
+	onChange    func(diff Diff)
+	current     reflect.Value
+}
+
+func (w *watcher) run(ctx context.Context, signals <-chan os.Signal) {
+	var tick <-chan time.Time
+	if w.options.Interval > 0 {
+		ticker := time.NewTicker(w.options.Interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-tick:
+		case <-signals:
+		}
+
+		w.reload()
+	}
+}
+
+// reload loads the variables into a new struct, notifying onChange if any
+// of them changed since the last successful load.
+func (w *watcher) reload() {
+	next := reflect.New(w.current.Type())
+	if err := Load(w.serviceName, next.Interface(), w.options.Options); err != nil {
+		if w.options.OnError != nil {
+			w.options.OnError(err)
+		}
+
+		return
+	}
+
+	changes := diffFields(w.current, next.Elem())
+	w.current = next.Elem()
+
+	if len(changes) > 0 && w.onChange != nil {
+		w.onChange(Diff{
+			Changes: changes,
+			Current: next.Interface(),
+		})
+	}
+}
+
+// diffFields compares the tagged fields of two structs of the same type.
+func diffFields(previous, current reflect.Value) []Change {
+	var (
+		changes []Change
+		rt      = previous.Type()
+	)
+
+	for i := 0; i < rt.NumField(); i++ {
+		f := rt.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		tag, err := parseFieldTag(f.Tag)
+		if err != nil || tag == nil {
+			continue
+		}
+
+		var (
+			old = previous.Field(i).Interface()
+			cur = current.Field(i).Interface()
+		)
+		if !reflect.DeepEqual(old, cur) {
+			changes = append(changes, Change{
+				Field: f.Name,
+				Name:  tag.Name,
+				Old:   old,
+				New:   cur,
+			})
+		}
+	}
+
+	return changes
+}
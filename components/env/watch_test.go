@@ -0,0 +1,162 @@
+package env
+
+import (
+	"context"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mikros-dev/mikros/components/service"
+)
+
+func TestWatch(t *testing.T) {
+	svc := service.FromString("example")
+
+	type config struct {
+		Level   string        `env:"LOG_LEVEL,default_value=info"`
+		Feature Env[bool]     `env:"FEATURE_ENABLED"`
+		Timeout time.Duration `env:"TIMEOUT,default_value=1s"`
+		Ignored string
+	}
+
+	t.Run("loads the target before watching", func(t *testing.T) {
+		t.Setenv("LOG_LEVEL", "debug")
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		var cfg config
+		err := Watch(ctx, svc, &cfg, nil, WatchOptions{Interval: time.Hour})
+		require.NoError(t, err)
+		assert.Equal(t, "debug", cfg.Level)
+	})
+
+	t.Run("fails when the initial load fails", func(t *testing.T) {
+		var cfg struct {
+			Port int `env:"PORT,required"`
+		}
+
+		err := Watch(context.Background(), svc, &cfg, nil, WatchOptions{Interval: time.Hour})
+		assert.Error(t, err)
+	})
+
+	t.Run("notifies changed variables", func(t *testing.T) {
+		t.Setenv("LOG_LEVEL", "info")
+
+		var (
+			cfg   config
+			diffs []Diff
+			w     = &watcher{
+				serviceName: svc,
+				onChange:    func(d Diff) { diffs = append(diffs, d) },
+			}
+		)
+		require.NoError(t, Load(svc, &cfg))
+		w.current = reflect.ValueOf(&cfg).Elem()
+
+		w.reload()
+		assert.Empty(t, diffs)
+
+		t.Setenv("LOG_LEVEL", "debug")
+		t.Setenv("example__FEATURE_ENABLED", "true")
+		w.reload()
+
+		require.Len(t, diffs, 1)
+		changes := diffs[0].Changes
+		require.Len(t, changes, 2)
+		assert.Equal(t, "Level", changes[0].Field)
+		assert.Equal(t, "LOG_LEVEL", changes[0].Name)
+		assert.Equal(t, "info", changes[0].Old)
+		assert.Equal(t, "debug", changes[0].New)
+		assert.Equal(t, "FEATURE_ENABLED", changes[1].Name)
+
+		current := diffs[0].Current.(*config)
+		assert.Equal(t, "debug", current.Level)
+		assert.True(t, current.Feature.Value())
+		assert.Equal(t, "example__FEATURE_ENABLED", current.Feature.VarName())
+		assert.Equal(t, "info", cfg.Level)
+	})
+
+	t.Run("reloads periodically", func(t *testing.T) {
+		t.Setenv("LOG_LEVEL", "info")
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		var (
+			cfg   config
+			diffs = make(chan Diff, 1)
+		)
+		err := Watch(ctx, svc, &cfg, func(d Diff) { diffs <- d }, WatchOptions{
+			Interval: 10 * time.Millisecond,
+		})
+		require.NoError(t, err)
+
+		require.NoError(t, os.Setenv("LOG_LEVEL", "debug"))
+
+		select {
+		case d := <-diffs:
+			assert.Equal(t, "debug", d.Current.(*config).Level)
+		case <-time.After(time.Second):
+			t.Fatal("change was not notified")
+		}
+	})
+
+	t.Run("reports reload errors keeping previous values", func(t *testing.T) {
+		t.Setenv("TIMEOUT", "2s")
+
+		var (
+			cfg  config
+			errs = make(chan error, 1)
+			w    = &watcher{
+				serviceName: svc,
+				options: WatchOptions{
+					OnError: func(err error) { errs <- err },
+				},
+			}
+		)
+		require.NoError(t, Load(svc, &cfg))
+		w.current = reflect.ValueOf(&cfg).Elem()
+
+		t.Setenv("TIMEOUT", "soon")
+		w.reload()
+		assert.Error(t, <-errs)
+
+		var notified bool
+		w.onChange = func(Diff) { notified = true }
+		t.Setenv("TIMEOUT", "2s")
+		w.reload()
+		assert.False(t, notified)
+	})
+
+	t.Run("reloads when signaled", func(t *testing.T) {
+		t.Setenv("LOG_LEVEL", "info")
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		var (
+			cfg     config
+			diffs   = make(chan Diff, 1)
+			signals = make(chan os.Signal, 1)
+			w       = &watcher{
+				serviceName: svc,
+				onChange:    func(d Diff) { diffs <- d },
+			}
+		)
+		require.NoError(t, Load(svc, &cfg))
+		w.current = reflect.ValueOf(&cfg).Elem()
+		go w.run(ctx, signals)
+
+		t.Setenv("LOG_LEVEL", "warn")
+		signals <- os.Interrupt
+
+		select {
+		case d := <-diffs:
+			assert.Equal(t, "warn", d.Current.(*config).Level)
+		case <-time.After(time.Second):
+			t.Fatal("signal did not trigger a reload")
+		}
+	})
+}
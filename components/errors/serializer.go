@@ -0,0 +1,236 @@
+package errors
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"sync"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// AcceptSerializersKey is the gRPC metadata key where clients list, by
+	// preference, the serializers they are able to decode.
+	AcceptSerializersKey = "mikros-error-serializers"
+
+	// ErrorInfoDomain is the domain of the errdetails.ErrorInfo detail
+	// carrying framework errors.
+	ErrorInfoDomain = "mikros.dev"
+
+	protoSerializerName = "proto/v1"
+	jsonSerializerName  = "json/v1"
+)
+
+// Payload is the representation of a framework error exchanged between
+// services. It is the contract that implementations in other languages must
+// follow.
+type Payload struct {
+	Kind        Kind   `json:"kind"`
+	Message     string `json:"message,omitempty"`
+	Cause       string `json:"cause,omitempty"`
+	Code        int32  `json:"code,omitempty"`
+	ServiceName string `json:"service_name,omitempty"`
+	Destination string `json:"destination,omitempty"`
+}
+
+// Serializer encodes framework errors into gRPC statuses and decodes them
+// back.
+type Serializer interface {
+	// Name identifies the serializer, and its version, in the negotiation,
+	// e.g. "proto/v1".
+	Name() string
+
+	// Encode creates a status with the given code carrying the payload.
+	Encode(code codes.Code, payload Payload) (*status.Status, error)
+
+	// Decode extracts the payload of a status. It returns false when the
+	// status was not encoded by the serializer.
+	Decode(st *status.Status) (Payload, bool)
+}
+
+var (
+	serializersMu sync.RWMutex
+	serializers   = []Serializer{ProtoSerializer(), JSONSerializer()}
+)
+
+// RegisterSerializer adds a serializer, with the highest preference, to the
+// ones used by services. A serializer with the same name is replaced.
+func RegisterSerializer(s Serializer) {
+	serializersMu.Lock()
+	defer serializersMu.Unlock()
+
+	registered := []Serializer{s}
+	for _, r := range serializers {
+		if r.Name() != s.Name() {
+			registered = append(registered, r)
+		}
+	}
+
+	serializers = registered
+}
+
+// Serializers returns the registered serializers, by preference.
+func Serializers() []Serializer {
+	serializersMu.RLock()
+	defer serializersMu.RUnlock()
+
+	return append([]Serializer(nil), serializers...)
+}
+
+// NegotiateSerializer returns the first serializer accepted by the client,
+// in the client preference order. Clients that don't list any, like older
+// ones, receive JSONSerializer, the original format.
+func NegotiateSerializer(accepted []string) Serializer {
+	registered := Serializers()
+	for _, name := range accepted {
+		for _, s := range registered {
+			if s.Name() == strings.TrimSpace(name) {
+				return s
+			}
+		}
+	}
+
+	return JSONSerializer()
+}
+
+// AcceptedSerializers returns the serializers listed by the client of an
+// incoming gRPC call.
+func AcceptedSerializers(ctx context.Context) []string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil
+	}
+
+	var names []string
+	for _, v := range md.Get(AcceptSerializersKey) {
+		names = append(names, strings.Split(v, ",")...)
+	}
+
+	return names
+}
+
+// AppendAcceptedSerializers lists the registered serializers in the metadata
+// of an outgoing gRPC call, so the server may answer with the preferred one.
+func AppendAcceptedSerializers(ctx context.Context) context.Context {
+	var names []string
+	for _, s := range Serializers() {
+		names = append(names, s.Name())
+	}
+
+	return metadata.AppendToOutgoingContext(ctx, AcceptSerializersKey, strings.Join(names, ","))
+}
+
+// DecodeStatus extracts the payload of a status trying every registered
+// serializer, by preference.
+func DecodeStatus(st *status.Status) (Payload, bool) {
+	for _, s := range Serializers() {
+		if p, ok := s.Decode(st); ok {
+			return p, true
+		}
+	}
+
+	return Payload{}, false
+}
+
+// ProtoSerializer returns the serializer that carries the payload as an
+// errdetails.ErrorInfo detail of the status, using the error message as the
+// status message.
+func ProtoSerializer() Serializer {
+	return protoSerializer{}
+}
+
+type protoSerializer struct{}
+
+func (protoSerializer) Name() string {
+	return protoSerializerName
+}
+
+func (protoSerializer) Encode(code codes.Code, payload Payload) (*status.Status, error) {
+	metadata := map[string]string{
+		"version": "1",
+	}
+	for k, v := range map[string]string{
+		"message":      payload.Message,
+		"cause":        payload.Cause,
+		"service_name": payload.ServiceName,
+		"destination":  payload.Destination,
+	} {
+		if v != "" {
+			metadata[k] = v
+		}
+	}
+	if payload.Code != 0 {
+		metadata["code"] = strconv.FormatInt(int64(payload.Code), 10)
+	}
+
+	return status.New(code, payload.Message).WithDetails(&errdetails.ErrorInfo{
+		Reason:   payload.Kind.String(),
+		Domain:   ErrorInfoDomain,
+		Metadata: metadata,
+	})
+}
+
+func (protoSerializer) Decode(st *status.Status) (Payload, bool) {
+	for _, d := range st.Details() {
+		info, ok := d.(*errdetails.ErrorInfo)
+		if !ok || info.GetDomain() != ErrorInfoDomain {
+			continue
+		}
+
+		md := info.GetMetadata()
+		payload := Payload{
+			Kind:        Kind(info.GetReason()),
+			Message:     md["message"],
+			Cause:       md["cause"],
+			ServiceName: md["service_name"],
+			Destination: md["destination"],
+		}
+		if code, err := strconv.ParseInt(md["code"], 10, 32); err == nil {
+			payload.Code = int32(code)
+		}
+
+		return payload, true
+	}
+
+	return Payload{}, false
+}
+
+// JSONSerializer returns the serializer that carries the payload as a JSON
+// document in the status message.
+func JSONSerializer() Serializer {
+	return jsonSerializer{}
+}
+
+type jsonSerializer struct{}
+
+func (jsonSerializer) Name() string {
+	return jsonSerializerName
+}
+
+func (jsonSerializer) Encode(code codes.Code, payload Payload) (*status.Status, error) {
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return status.New(code, string(b)), nil
+}
+
+func (jsonSerializer) Decode(st *status.Status) (Payload, bool) {
+	var payload Payload
+	if err := json.Unmarshal([]byte(st.Message()), &payload); err != nil {
+		return Payload{}, false
+	}
+
+	// Other JSON documents are not framework errors.
+	if payload.Kind == "" {
+		return Payload{}, false
+	}
+
+	return payload, true
+}
@@ -0,0 +1,124 @@
+package errors
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type textSerializer struct{}
+
+func (textSerializer) Name() string {
+	return "text/v1"
+}
+
+func (textSerializer) Encode(code codes.Code, payload Payload) (*status.Status, error) {
+	return status.New(code, "text:"+payload.Message), nil
+}
+
+func (textSerializer) Decode(st *status.Status) (Payload, bool) {
+	return Payload{}, false
+}
+
+func TestSerializers(t *testing.T) {
+	payload := Payload{
+		Kind:        KindNotFound,
+		Message:     "user not found",
+		Cause:       "no rows",
+		Code:        42,
+		ServiceName: "users",
+		Destination: "orders",
+	}
+
+	t.Run("should round trip payloads", func(t *testing.T) {
+		for _, s := range []Serializer{ProtoSerializer(), JSONSerializer()} {
+			st, err := s.Encode(codes.NotFound, payload)
+			require.NoError(t, err, s.Name())
+			assert.Equal(t, codes.NotFound, st.Code())
+
+			decoded, ok := s.Decode(st)
+			require.True(t, ok, s.Name())
+			assert.Equal(t, payload, decoded, s.Name())
+		}
+	})
+
+	t.Run("should keep the JSON message format", func(t *testing.T) {
+		st, err := JSONSerializer().Encode(codes.NotFound, Payload{Kind: KindNotFound, Message: "not found"})
+		require.NoError(t, err)
+		assert.Equal(t, `{"kind":"NotFoundError","message":"not found"}`, st.Message())
+	})
+
+	t.Run("should use the error message in proto statuses", func(t *testing.T) {
+		st, err := ProtoSerializer().Encode(codes.NotFound, payload)
+		require.NoError(t, err)
+		assert.Equal(t, "user not found", st.Message())
+
+		_, ok := JSONSerializer().Decode(st)
+		assert.False(t, ok)
+	})
+
+	t.Run("should decode statuses of any serializer", func(t *testing.T) {
+		for _, s := range []Serializer{ProtoSerializer(), JSONSerializer()} {
+			st, err := s.Encode(codes.NotFound, payload)
+			require.NoError(t, err)
+
+			decoded, ok := DecodeStatus(st)
+			require.True(t, ok, s.Name())
+			assert.Equal(t, payload, decoded)
+		}
+
+		_, ok := DecodeStatus(status.New(codes.Unavailable, "connection refused"))
+		assert.False(t, ok)
+
+		_, ok = DecodeStatus(status.New(codes.Unknown, `{"error":"other"}`))
+		assert.False(t, ok)
+	})
+}
+
+func TestNegotiateSerializer(t *testing.T) {
+	t.Run("should use the client preference", func(t *testing.T) {
+		assert.Equal(t, "proto/v1", NegotiateSerializer([]string{"proto/v1", "json/v1"}).Name())
+		assert.Equal(t, "json/v1", NegotiateSerializer([]string{" json/v1", "proto/v1"}).Name())
+		assert.Equal(t, "proto/v1", NegotiateSerializer([]string{"cbor/v2", "proto/v1"}).Name())
+	})
+
+	t.Run("should fall back to JSON", func(t *testing.T) {
+		assert.Equal(t, "json/v1", NegotiateSerializer(nil).Name())
+		assert.Equal(t, "json/v1", NegotiateSerializer([]string{"cbor/v2"}).Name())
+	})
+
+	t.Run("should negotiate through gRPC metadata", func(t *testing.T) {
+		out := AppendAcceptedSerializers(context.Background())
+		md, ok := metadata.FromOutgoingContext(out)
+		require.True(t, ok)
+
+		in := metadata.NewIncomingContext(context.Background(), md)
+		assert.Equal(t, []string{"proto/v1", "json/v1"}, AcceptedSerializers(in))
+		assert.Equal(t, "proto/v1", NegotiateSerializer(AcceptedSerializers(in)).Name())
+		assert.Empty(t, AcceptedSerializers(context.Background()))
+	})
+
+	t.Run("should prefer registered serializers", func(t *testing.T) {
+		previous := Serializers()
+		defer func() {
+			serializersMu.Lock()
+			serializers = previous
+			serializersMu.Unlock()
+		}()
+
+		RegisterSerializer(textSerializer{})
+		RegisterSerializer(textSerializer{})
+
+		names := []string{}
+		for _, s := range Serializers() {
+			names = append(names, s.Name())
+		}
+		assert.Equal(t, []string{"text/v1", "proto/v1", "json/v1"}, names)
+		assert.Equal(t, "text/v1", NegotiateSerializer([]string{"text/v1"}).Name())
+	})
+}
@@ -3,13 +3,18 @@ package grpc
 import (
 	"context"
 	"fmt"
+	"net"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/status"
 
+	logger_api "github.com/mikros-dev/mikros/apis/features/logger"
 	"github.com/mikros-dev/mikros/apis/integrations"
 	mcontext "github.com/mikros-dev/mikros/components/context"
+	"github.com/mikros-dev/mikros/components/downstream"
 	"github.com/mikros-dev/mikros/components/service"
 	merrors "github.com/mikros-dev/mikros/internal/components/errors"
 )
@@ -23,6 +28,24 @@ type ClientConnectionOptions struct {
 	Connection            ConnectionOptions
 	AlternativeConnection *ConnectionOptions
 	Tracker               integrations.Tracker
+
+	// SlowCalls, if set, logs calls to the client that take too long.
+	SlowCalls *downstream.SlowCallLogger
+
+	// Logger is used to warn about requests exceeding their downstream call
+	// budget.
+	Logger logger_api.API
+
+	// Credentials, if set, adds credentials into the metadata of every call.
+	Credentials credentials.PerRPCCredentials
+
+	// Dialer, if set, replaces the network connection with the client
+	// service, e.g. by the one of an InMemoryServer.
+	Dialer func(ctx context.Context, address string) (net.Conn, error)
+
+	// Interceptors are additional interceptors called after the default one
+	// in every call.
+	Interceptors []grpc.UnaryClientInterceptor
 }
 
 // ConnectionOptions defines the configuration details for establishing
@@ -41,18 +64,32 @@ type ConnectionOptions struct {
 func ClientConnection(options *ClientConnectionOptions) (*grpc.ClientConn, error) {
 	address := getClientConnectionAddress(options)
 
-	conn, err := grpc.NewClient(
-		address,
+	dialOptions := []grpc.DialOption{
 		grpc.WithTransportCredentials(insecure.NewCredentials()),
 		grpc.WithUnaryInterceptor(
 			gRPCClientUnaryInterceptor(
 				options.Context,
 				options.Tracker,
+				options.SlowCalls,
+				options.Logger,
 				options.ServiceName,
 				options.ClientName,
 			),
 		),
-	)
+	}
+	if options.Credentials != nil {
+		dialOptions = append(dialOptions, grpc.WithPerRPCCredentials(options.Credentials))
+	}
+	if len(options.Interceptors) > 0 {
+		dialOptions = append(dialOptions, grpc.WithChainUnaryInterceptor(options.Interceptors...))
+	}
+	if options.Dialer != nil {
+		// Skips name resolution since the address is not used to connect.
+		address = "passthrough:///" + address
+		dialOptions = append(dialOptions, grpc.WithContextDialer(options.Dialer))
+	}
+
+	conn, err := grpc.NewClient(address, dialOptions...)
 	if err != nil {
 		return nil, err
 	}
@@ -80,6 +117,8 @@ func getClientConnectionAddress(options *ClientConnectionOptions) string {
 func gRPCClientUnaryInterceptor(
 	svcCtx *mcontext.ServiceContext,
 	tracker integrations.Tracker,
+	slowCalls *downstream.SlowCallLogger,
+	log logger_api.API,
 	from, to service.Name,
 ) grpc.UnaryClientInterceptor {
 	return func(
@@ -102,6 +141,16 @@ func gRPCClientUnaryInterceptor(
 			ctx = tracker.Add(ctx, trackID)
 		}
 
+		done, err := downstream.SpendBudget(ctx, log, to.String(), method)
+		if err != nil {
+			return &budgetExceededError{err: err}
+		}
+		defer done()
+		defer slowCalls.Track(ctx, to.String(), method)()
+
+		// Lets the server answer errors with our preferred serializer.
+		ctx = merrors.AppendAcceptedSerializers(ctx)
+
 		// Calls invoker with a new context.
 		if err := invoker(mcontext.AppendServiceContext(ctx, svcCtx), method, req, reply, cc, opts...); err != nil {
 			// Return the proper inner service error for the caller.
@@ -116,3 +165,23 @@ func gRPCClientUnaryInterceptor(
 		return nil
 	}
 }
+
+// budgetExceededError is the error of calls refused by an enforced downstream
+// budget. It carries a ResourceExhausted status, like the errors of calls
+// that were made, while still unwrapping to the downstream.BudgetExceededError.
+type budgetExceededError struct {
+	err error
+}
+
+func (e *budgetExceededError) Error() string {
+	return e.err.Error()
+}
+
+func (e *budgetExceededError) Unwrap() error {
+	return e.err
+}
+
+// GRPCStatus gives the status of the error to the grpc status package.
+func (e *budgetExceededError) GRPCStatus() *status.Status {
+	return status.New(codes.ResourceExhausted, e.err.Error())
+}
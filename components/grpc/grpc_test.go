@@ -0,0 +1,58 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+
+	"github.com/mikros-dev/mikros/components/downstream"
+	"github.com/mikros-dev/mikros/components/service"
+)
+
+func TestClientConnectionBudget(t *testing.T) {
+	t.Run("should refuse calls above an enforced budget with a ResourceExhausted status", func(t *testing.T) {
+		srv := health.NewServer()
+		srv.SetServingStatus("users", healthpb.HealthCheckResponse_SERVING)
+
+		s, err := NewInMemoryServer(&healthpb.Health_ServiceDesc, srv)
+		require.NoError(t, err)
+		defer s.Stop()
+
+		conn, err := ClientConnection(&ClientConnectionOptions{
+			ServiceName: service.FromString("orders"),
+			ClientName:  service.FromString("users"),
+			Connection: ConnectionOptions{
+				Namespace: "unknown",
+				Port:      7070,
+			},
+			Dialer: s.Dialer(),
+		})
+		require.NoError(t, err)
+		defer func() {
+			_ = conn.Close()
+		}()
+
+		var (
+			client = healthpb.NewHealthClient(conn)
+			ctx    = downstream.WithBudget(context.Background(), downstream.Budget{MaxCalls: 1, Enforce: true})
+			req    = &healthpb.HealthCheckRequest{Service: "users"}
+		)
+
+		_, err = client.Check(ctx, req)
+		require.NoError(t, err)
+
+		_, err = client.Check(ctx, req)
+		require.Error(t, err)
+		assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+		var budgetErr *downstream.BudgetExceededError
+		assert.True(t, errors.As(err, &budgetErr))
+	})
+}
@@ -0,0 +1,85 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"reflect"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/test/bufconn"
+
+	merrors "github.com/mikros-dev/mikros/internal/components/errors"
+)
+
+const (
+	inMemoryBufferSize = 1 << 20
+)
+
+// InMemoryServer serves a gRPC service implementation inside the process,
+// without any network listener. Clients reach it by setting its Dialer in
+// their ClientConnectionOptions, which is how coupled clients are connected
+// to other services inside tests.
+type InMemoryServer struct {
+	server   *grpc.Server
+	listener *bufconn.Listener
+}
+
+// NewInMemoryServer registers srv as the implementation of the service
+// described by desc and starts serving it.
+func NewInMemoryServer(desc *grpc.ServiceDesc, srv interface{}) (*InMemoryServer, error) {
+	if desc == nil || srv == nil {
+		return nil, errors.New("in-memory server requires a service description and its implementation")
+	}
+
+	handlerType := reflect.TypeOf(desc.HandlerType).Elem()
+	if !reflect.TypeOf(srv).Implements(handlerType) {
+		return nil, fmt.Errorf("%T does not implement the service '%s'", srv, desc.ServiceName)
+	}
+
+	s := &InMemoryServer{
+		server:   grpc.NewServer(grpc.ChainUnaryInterceptor(inMemoryErrorInterceptor)),
+		listener: bufconn.Listen(inMemoryBufferSize),
+	}
+	s.server.RegisterService(desc, srv)
+
+	go func() {
+		_ = s.server.Serve(s.listener)
+	}()
+
+	return s, nil
+}
+
+// Dialer returns the function that clients must use to connect to the server.
+func (s *InMemoryServer) Dialer() func(ctx context.Context, address string) (net.Conn, error) {
+	return func(ctx context.Context, _ string) (net.Conn, error) {
+		return s.listener.DialContext(ctx)
+	}
+}
+
+// Stop stops the server, closing all of its connections.
+func (s *InMemoryServer) Stop() {
+	s.server.Stop()
+}
+
+// inMemoryErrorInterceptor converts service errors into gRPC statuses, like
+// the gRPC runtime does, so clients receive them as they would from a
+// deployed service.
+func inMemoryErrorInterceptor(
+	ctx context.Context,
+	req interface{},
+	_ *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (interface{}, error) {
+	resp, err := handler(ctx, req)
+	if err == nil {
+		return resp, nil
+	}
+
+	if st, ok, encodeErr := merrors.ToGRPCStatusFor(ctx, err); ok && encodeErr == nil {
+		return resp, st.Err()
+	}
+
+	return resp, err
+}
@@ -0,0 +1,101 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/mikros-dev/mikros/components/errors"
+	"github.com/mikros-dev/mikros/components/service"
+	mierrors "github.com/mikros-dev/mikros/internal/components/errors"
+)
+
+type failingHealthServer struct {
+	healthpb.UnimplementedHealthServer
+}
+
+func (f *failingHealthServer) Check(context.Context, *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	return nil, mierrors.NewBuilder(mierrors.BuilderOptions{ServiceName: "users"}).NotFound().WithCode(errorCode(7))
+}
+
+type errorCode int32
+
+func (c errorCode) ErrorCode() int32 {
+	return int32(c)
+}
+
+func TestInMemoryServer(t *testing.T) {
+	t.Run("should serve clients connected with its dialer", func(t *testing.T) {
+		srv := health.NewServer()
+		srv.SetServingStatus("users", healthpb.HealthCheckResponse_SERVING)
+
+		s, err := NewInMemoryServer(&healthpb.Health_ServiceDesc, srv)
+		require.NoError(t, err)
+		defer s.Stop()
+
+		conn, err := ClientConnection(&ClientConnectionOptions{
+			ServiceName: service.FromString("orders"),
+			ClientName:  service.FromString("users"),
+			Connection: ConnectionOptions{
+				Namespace: "unknown",
+				Port:      7070,
+			},
+			Dialer: s.Dialer(),
+		})
+		require.NoError(t, err)
+		defer func() {
+			_ = conn.Close()
+		}()
+
+		client := healthpb.NewHealthClient(conn)
+		res, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "users"})
+		require.NoError(t, err)
+		assert.Equal(t, healthpb.HealthCheckResponse_SERVING, res.GetStatus())
+
+		_, err = client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "unknown"})
+		assert.Error(t, err)
+	})
+
+	t.Run("should deliver service errors to clients", func(t *testing.T) {
+		s, err := NewInMemoryServer(&healthpb.Health_ServiceDesc, &failingHealthServer{})
+		require.NoError(t, err)
+		defer s.Stop()
+
+		conn, err := ClientConnection(&ClientConnectionOptions{
+			ServiceName: service.FromString("orders"),
+			ClientName:  service.FromString("users"),
+			Connection: ConnectionOptions{
+				Namespace: "unknown",
+				Port:      7070,
+			},
+			Dialer: s.Dialer(),
+		})
+		require.NoError(t, err)
+		defer func() {
+			_ = conn.Close()
+		}()
+
+		_, err = healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{})
+		require.Error(t, err)
+
+		e, ok := errors.From(err)
+		require.True(t, ok)
+		assert.Equal(t, errors.KindNotFound, e.Kind())
+		assert.Equal(t, int32(7), e.Code())
+		assert.Equal(t, "not found", e.Message())
+	})
+
+	t.Run("should fail when the implementation does not match the service", func(t *testing.T) {
+		_, err := NewInMemoryServer(&healthpb.Health_ServiceDesc, struct{}{})
+		assert.Error(t, err)
+	})
+
+	t.Run("should fail without service description", func(t *testing.T) {
+		_, err := NewInMemoryServer(nil, health.NewServer())
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,246 @@
+// Package handoff implements zero-downtime binary upgrades by passing the
+// listening sockets of a running process to a new one.
+//
+// When an upgrade is requested (SIGUSR2 on Unix systems), the service starts
+// a new process of its (possibly replaced) binary inheriting all listeners
+// created through Listen. Once the new process signals that it is ready to
+// receive connections, the old one gracefully drains and stops, so no
+// connection is refused during the upgrade.
+package handoff
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	listenersEnv = "MIKROS_HANDOFF_LISTENERS"
+	readyFdEnv   = "MIKROS_HANDOFF_READY_FD"
+)
+
+var (
+	// firstInheritedFd is the descriptor of the first file passed to a child
+	// process through exec.Cmd.ExtraFiles.
+	firstInheritedFd = 3
+
+	mu          sync.Mutex
+	listeners   = make(map[string]net.Listener)
+	usedFds     = make(map[int]bool)
+	readyClosed bool
+)
+
+type fileListener interface {
+	File() (*os.File, error)
+}
+
+// Listen announces on the local TCP address. If the process was started by
+// an upgrade and inherited a listener for the same address, it is used
+// instead of creating a new socket.
+func Listen(address string) (net.Listener, error) {
+	return ListenNetwork("tcp", address)
+}
+
+// ListenNetwork is like Listen, but announces on a specific network, which
+// must be "tcp", "tcp4" or "tcp6".
+func ListenNetwork(network, address string) (net.Listener, error) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	l, err := inheritedListener(address)
+	if err != nil {
+		return nil, err
+	}
+	if l == nil {
+		l, err = net.Listen(network, address)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	listeners[address] = l
+	return l, nil
+}
+
+func inheritedListener(address string) (net.Listener, error) {
+	value := os.Getenv(listenersEnv)
+	if value == "" {
+		return nil, nil
+	}
+
+	for i, addr := range strings.Split(value, ",") {
+		fd := firstInheritedFd + i
+		if addr != address || usedFds[fd] {
+			continue
+		}
+
+		file := os.NewFile(uintptr(fd), addr)
+		l, err := net.FileListener(file)
+		if err != nil {
+			return nil, fmt.Errorf("could not inherit listener for '%s': %w", address, err)
+		}
+
+		// FileListener duplicates the descriptor.
+		_ = file.Close()
+		usedFds[fd] = true
+
+		return l, nil
+	}
+
+	return nil, nil
+}
+
+// Inherited reports whether the current process was started by an upgrade.
+func Inherited() bool {
+	return os.Getenv(listenersEnv) != "" || os.Getenv(readyFdEnv) != ""
+}
+
+// Ready notifies the parent process, when started by an upgrade, that the
+// current process is ready to receive connections. It does nothing
+// otherwise.
+func Ready() error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	value := os.Getenv(readyFdEnv)
+	if value == "" || readyClosed {
+		return nil
+	}
+
+	fd, err := strconv.Atoi(value)
+	if err != nil {
+		return fmt.Errorf("invalid %s value '%s': %w", readyFdEnv, value, err)
+	}
+
+	file := os.NewFile(uintptr(fd), "handoff-ready")
+	defer func() {
+		_ = file.Close()
+	}()
+	readyClosed = true
+
+	_, err = file.Write([]byte{1})
+	return err
+}
+
+// Upgrade starts a new process of the current executable, with the same
+// arguments, passing to it all listeners created through Listen. It blocks
+// until the new process calls Ready, returning it, or ctx is done, in which
+// case the new process is killed.
+func Upgrade(ctx context.Context) (*os.Process, error) {
+	executable, err := os.Executable()
+	if err != nil {
+		return nil, err
+	}
+
+	files, addresses, err := listenerFiles()
+	defer closeFiles(files)
+	if err != nil {
+		return nil, err
+	}
+
+	readyReader, readyWriter, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = readyReader.Close()
+	}()
+
+	cmd := exec.Command(executable, os.Args[1:]...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.ExtraFiles = append(files, readyWriter)
+	cmd.Env = append(upgradeEnviron(),
+		fmt.Sprintf("%s=%s", listenersEnv, strings.Join(addresses, ",")),
+		fmt.Sprintf("%s=%d", readyFdEnv, firstInheritedFd+len(files)),
+	)
+
+	err = cmd.Start()
+	_ = readyWriter.Close()
+	if err != nil {
+		return nil, fmt.Errorf("could not start new process: %w", err)
+	}
+
+	if err := waitReady(ctx, readyReader); err != nil {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+		return nil, err
+	}
+
+	return cmd.Process, nil
+}
+
+func listenerFiles() ([]*os.File, []string, error) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	var (
+		files     []*os.File
+		addresses []string
+	)
+
+	for address, l := range listeners {
+		fl, ok := l.(fileListener)
+		if !ok {
+			return files, nil, fmt.Errorf("listener for '%s' cannot be handed off", address)
+		}
+
+		f, err := fl.File()
+		if err != nil {
+			return files, nil, fmt.Errorf("could not get listener for '%s' file: %w", address, err)
+		}
+
+		files = append(files, f)
+		addresses = append(addresses, address)
+	}
+
+	return files, addresses, nil
+}
+
+func closeFiles(files []*os.File) {
+	for _, f := range files {
+		_ = f.Close()
+	}
+}
+
+// upgradeEnviron returns the current environment without the handoff
+// variables inherited from a previous upgrade.
+func upgradeEnviron() []string {
+	var environ []string
+	for _, e := range os.Environ() {
+		if strings.HasPrefix(e, listenersEnv+"=") || strings.HasPrefix(e, readyFdEnv+"=") {
+			continue
+		}
+
+		environ = append(environ, e)
+	}
+
+	return environ
+}
+
+func waitReady(ctx context.Context, ready *os.File) error {
+	result := make(chan error, 1)
+	go func() {
+		b := make([]byte, 1)
+		if _, err := ready.Read(b); err != nil {
+			result <- errors.New("new process exited before being ready")
+			return
+		}
+
+		result <- nil
+	}()
+
+	select {
+	case err := <-result:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("new process is not ready: %w", ctx.Err())
+	}
+}
@@ -0,0 +1,86 @@
+//go:build unix
+
+package handoff
+
+import (
+	"net"
+	"os"
+	"strconv"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListen(t *testing.T) {
+	t.Run("should create a new listener", func(t *testing.T) {
+		l, err := Listen("127.0.0.1:0")
+		require.NoError(t, err)
+		defer func() { _ = l.Close() }()
+
+		assert.False(t, Inherited())
+	})
+
+	t.Run("should create a listener of a specific network", func(t *testing.T) {
+		l, err := ListenNetwork("tcp4", "127.0.0.1:0")
+		require.NoError(t, err)
+		defer func() { _ = l.Close() }()
+
+		_, err = ListenNetwork("tcp4", "[::1]:0")
+		assert.Error(t, err)
+	})
+
+	t.Run("should inherit a listener from the parent process", func(t *testing.T) {
+		parent, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		defer func() { _ = parent.Close() }()
+
+		file, err := parent.(*net.TCPListener).File()
+		require.NoError(t, err)
+
+		// Listen closes the inherited descriptor, so it receives a copy of it.
+		fd, err := syscall.Dup(int(file.Fd()))
+		require.NoError(t, err)
+		_ = file.Close()
+
+		previousFd := firstInheritedFd
+		firstInheritedFd = fd
+		defer func() { firstInheritedFd = previousFd }()
+
+		address := "127.0.0.1:9999"
+		t.Setenv(listenersEnv, address)
+
+		l, err := Listen(address)
+		require.NoError(t, err)
+		defer func() { _ = l.Close() }()
+
+		assert.True(t, Inherited())
+		assert.Equal(t, parent.Addr().String(), l.Addr().String())
+	})
+}
+
+func TestReady(t *testing.T) {
+	t.Run("should do nothing when not started by an upgrade", func(t *testing.T) {
+		assert.NoError(t, Ready())
+	})
+
+	t.Run("should notify the parent process", func(t *testing.T) {
+		r, w, err := os.Pipe()
+		require.NoError(t, err)
+		defer func() { _ = r.Close() }()
+
+		// Ready closes the descriptor, so it receives a copy of it.
+		fd, err := syscall.Dup(int(w.Fd()))
+		require.NoError(t, err)
+		_ = w.Close()
+
+		t.Setenv(readyFdEnv, strconv.Itoa(fd))
+		require.NoError(t, Ready())
+
+		b := make([]byte, 1)
+		n, err := r.Read(b)
+		require.NoError(t, err)
+		assert.Equal(t, 1, n)
+	})
+}
@@ -0,0 +1,13 @@
+//go:build !unix
+
+package handoff
+
+import (
+	"os"
+)
+
+// Signals returns the signals that request an upgrade. Upgrades are not
+// supported on this platform.
+func Signals() []os.Signal {
+	return nil
+}
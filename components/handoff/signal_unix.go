@@ -0,0 +1,13 @@
+//go:build unix
+
+package handoff
+
+import (
+	"os"
+	"syscall"
+)
+
+// Signals returns the signals that request an upgrade.
+func Signals() []os.Signal {
+	return []os.Signal{syscall.SIGUSR2}
+}
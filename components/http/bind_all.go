@@ -0,0 +1,94 @@
+package http
+
+import (
+	"errors"
+	"io"
+	"net/http"
+)
+
+// BindAll binds a whole request into target. The body is decoded first,
+// directly into target, as BindBody does, and then the fields tagged with
+// other locations are overwritten by the values found in the path, query,
+// headers and cookies, like Bind does:
+//
+//	type UpdateUserRequest struct {
+//		ID     string `json:"id" http:"loc=path"`
+//		DryRun bool   `json:"dry_run" http:"loc=query"`
+//		Name   string `json:"name"`
+//		Email  string `json:"email" http:"loc=body,required"`
+//	}
+//
+// Unlike Bind, body fields keep their decoded values instead of being
+// converted again from their text representation. The `required` and
+// `default` tag options of body fields are applied when they are not present
+// in the body. Requests without body only have their parameters bound.
+func BindAll(r *http.Request, target interface{}, opts ...*BindOptions) error {
+	o := getBindOptions(r, opts...)
+
+	b, err := newBinder(r, target, &o)
+	if err != nil {
+		return err
+	}
+
+	var bodyErr error
+	if hasBody(r) {
+		if err := BindBody(r, target, o.Body); err != nil && !errors.Is(err, io.EOF) {
+			if !o.CollectErrors {
+				return err
+			}
+
+			bodyErr = err
+		}
+	}
+
+	if errs, ok := bindGenerated(r, target, &o, ""); ok {
+		return finishBind(r, target, &o, bodyErr, errs)
+	}
+
+	var errs BindErrors
+	err = walkFields(b.rv, &o, "", "", nil, func(f *boundField) error {
+		if f.tag == nil {
+			// Only bound from the body
+			return nil
+		}
+
+		var err error
+		switch {
+		case f.tag.Location == "body":
+			err = b.bindDecodedBodyField(f)
+		case isMapField(f.sf.Type):
+			err = b.bindMap(f)
+		default:
+			err = b.bindFromExtractor(f.name, f.tag, f.fv)
+		}
+		if err != nil && !errs.collect(err) {
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return finishBind(r, target, &o, bodyErr, errs)
+}
+
+// bindDecodedBodyField applies the default value, or the required check, of
+// a body field that was not present in the decoded body.
+func (b *binder) bindDecodedBodyField(f *boundField) error {
+	if !isZeroValue(f.fv) {
+		return nil
+	}
+
+	values, err := f.tag.missing(f.tag.Location, f.name)
+	if err != nil || len(values) == 0 {
+		return err
+	}
+
+	return setBoundValues(f.fv, f.tag, f.name, f.tag.Location, values, b.opt)
+}
+
+func hasBody(r *http.Request) bool {
+	return r.Body != nil && r.Body != http.NoBody && r.ContentLength != 0
+}
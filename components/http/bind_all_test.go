@@ -0,0 +1,109 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBindAll(t *testing.T) {
+	type address struct {
+		City string `json:"city"`
+	}
+
+	type request struct {
+		ID        string            `json:"id" http:"loc=path"`
+		DryRun    bool              `json:"dry_run" http:"loc=query"`
+		Token     string            `json:"token" http:"loc=header"`
+		Name      string            `json:"name"`
+		Tags      []string          `json:"tags"`
+		Address   *address          `json:"address"`
+		Labels    map[string]string `json:"labels"`
+		CreatedAt time.Time         `json:"created_at" http:"loc=body"`
+		Role      string            `json:"role" http:"loc=body,default=member"`
+		Email     string            `json:"email" http:"loc=body,required"`
+	}
+
+	newRequest := func(body string) *http.Request {
+		r := httptest.NewRequest(http.MethodPut, "/users/42?dry_run=true&id=ignored", strings.NewReader(body))
+		r.SetPathValue("id", "42")
+		r.Header.Set("Content-Type", "application/json")
+		r.Header.Set("token", "abc")
+
+		return r
+	}
+
+	t.Run("should decode the body and overlay the parameters", func(t *testing.T) {
+		var (
+			r = newRequest(`{
+				"id": "from-body",
+				"name": "john",
+				"tags": ["a", "b"],
+				"address": {"city": "Lisbon"},
+				"labels": {"team": "core"},
+				"created_at": "2024-03-01T10:00:00Z",
+				"email": "john@example.com"
+			}`)
+			v request
+		)
+
+		err := BindAll(r, &v)
+		require.NoError(t, err)
+		assert.Equal(t, "42", v.ID)
+		assert.True(t, v.DryRun)
+		assert.Equal(t, "abc", v.Token)
+		assert.Equal(t, "john", v.Name)
+		assert.Equal(t, []string{"a", "b"}, v.Tags)
+		require.NotNil(t, v.Address)
+		assert.Equal(t, "Lisbon", v.Address.City)
+		assert.Equal(t, map[string]string{"team": "core"}, v.Labels)
+		assert.Equal(t, 2024, v.CreatedAt.Year())
+		assert.Equal(t, "member", v.Role)
+		assert.Equal(t, "john@example.com", v.Email)
+	})
+
+	t.Run("should report required body fields", func(t *testing.T) {
+		var (
+			r = newRequest(`{"name": "john"}`)
+			v request
+		)
+
+		err := BindAll(r, &v)
+
+		var errs BindErrors
+		require.True(t, errors.As(err, &errs))
+		require.Len(t, errs, 1)
+		assert.Equal(t, "email", errs[0].Field)
+		assert.Equal(t, "body", errs[0].Location)
+	})
+
+	t.Run("should bind parameters of requests without body", func(t *testing.T) {
+		var (
+			r = httptest.NewRequest(http.MethodGet, "/?dry_run=1", nil)
+			v = struct {
+				DryRun bool   `json:"dry_run" http:"loc=query"`
+				Name   string `json:"name"`
+			}{}
+		)
+
+		err := BindAll(r, &v)
+		require.NoError(t, err)
+		assert.True(t, v.DryRun)
+	})
+
+	t.Run("should fail with invalid bodies", func(t *testing.T) {
+		var (
+			r = newRequest(`{"name": 1}`)
+			v request
+		)
+
+		err := BindAll(r, &v)
+		assert.Error(t, err)
+	})
+}
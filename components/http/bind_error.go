@@ -0,0 +1,230 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrMissingParameter is the cause of a BindError for a required
+	// parameter that was not sent.
+	ErrMissingParameter = errors.New("missing required parameter")
+
+	// ErrTooManyValues is the cause of a BindError for a parameter sent with
+	// more values than BindOptions.MaxSliceLen allows.
+	ErrTooManyValues = errors.New("too many values")
+
+	// ErrValueTooLong is the cause of a BindError for a parameter value
+	// longer than BindOptions.MaxValueLen allows.
+	ErrValueTooLong = errors.New("value too long")
+
+	// ErrFileTooLarge is the cause of a BindError for a file larger than
+	// BindOptions.MaxFileBytes allows.
+	ErrFileTooLarge = errors.New("file too large")
+
+	// ErrContentTypeNotAllowed is the cause of a BindError for a file whose
+	// content type is not in BindOptions.AllowedContentTypes.
+	ErrContentTypeNotAllowed = errors.New("content type not allowed")
+)
+
+// BindError describes a parameter that could not be bound into a field.
+type BindError struct {
+	// Field is the parameter name, as resolved from the field tags.
+	Field string
+
+	// Location is where the parameter was looked for: "query", "header",
+	// "path", "cookie", "body" or "form".
+	Location string
+
+	// Value is the raw value received. Multiple values are joined by commas.
+	Value string
+
+	// Err is the underlying cause, usually a conversion error, or
+	// ErrMissingParameter.
+	Err error
+}
+
+func newBindError(field, location string, values []string, err error) *BindError {
+	return &BindError{
+		Field:    field,
+		Location: location,
+		Value:    strings.Join(values, ","),
+		Err:      err,
+	}
+}
+
+func (e *BindError) Error() string {
+	if errors.Is(e.Err, ErrMissingParameter) {
+		return fmt.Sprintf("missing required %s parameter '%s'", e.Location, e.Field)
+	}
+	if isLimitError(e.Err) {
+		return fmt.Sprintf("invalid %s parameter '%s': %v", e.Location, e.Field, e.Err)
+	}
+
+	return fmt.Sprintf("invalid %s parameter '%s' value '%s': %v", e.Location, e.Field, e.Value, e.Err)
+}
+
+func (e *BindError) Unwrap() error {
+	return e.Err
+}
+
+// isLimitError reports whether err is caused by a parameter exceeding the
+// BindOptions limits, or restrictions of uploaded files, whose values are not kept in the BindError.
+func isLimitError(err error) bool {
+	return errors.Is(err, ErrTooManyValues) ||
+		errors.Is(err, ErrValueTooLong) ||
+		errors.Is(err, ErrFileTooLarge) ||
+		errors.Is(err, ErrContentTypeNotAllowed)
+}
+
+// BindErrors gathers all parameters that could not be bound in a single
+// binding call, so handlers can report every invalid parameter at once. It
+// can be inspected with errors.As, both as BindErrors and as *BindError, and
+// rendered with ValidationProblem.
+type BindErrors []*BindError
+
+func (e BindErrors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, err := range e {
+		messages = append(messages, err.Error())
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+// Unwrap returns the individual errors.
+func (e BindErrors) Unwrap() []error {
+	errs := make([]error, 0, len(e))
+	for _, err := range e {
+		errs = append(errs, err)
+	}
+
+	return errs
+}
+
+// FieldErrors returns the errors keyed by parameter name.
+func (e BindErrors) FieldErrors() FieldErrors {
+	out := make(FieldErrors)
+	for _, err := range e {
+		if errors.Is(err.Err, ErrMissingParameter) {
+			out.Add(err.Field, "is required")
+			continue
+		}
+		if errors.Is(err.Err, ErrTooManyValues) {
+			out.Add(err.Field, "has too many values")
+			continue
+		}
+		if errors.Is(err.Err, ErrValueTooLong) {
+			out.Add(err.Field, "is too long")
+			continue
+		}
+
+		out.Add(err.Field, fmt.Sprintf("has an invalid value '%s'", err.Value))
+	}
+
+	return out
+}
+
+// collect appends err into errs if it is a *BindError, or BindErrors,
+// returning false for other errors, which must interrupt the binding.
+func (e *BindErrors) collect(err error) bool {
+	var bindErrs BindErrors
+	if errors.As(err, &bindErrs) {
+		*e = append(*e, bindErrs...)
+		return true
+	}
+
+	var bindErr *BindError
+	if !errors.As(err, &bindErr) {
+		return false
+	}
+
+	*e = append(*e, bindErr)
+	return true
+}
+
+// err returns errs as an error, or nil if there is none.
+func (e BindErrors) err() error {
+	if len(e) == 0 {
+		return nil
+	}
+
+	return e
+}
+
+// CollectedErrors gathers every problem found in a request bound with
+// BindOptions.CollectErrors, so handlers can report all of them in a single
+// response. It can be inspected with errors.As, as each of its parts, and
+// rendered with ValidationProblem.
+type CollectedErrors struct {
+	// Body is the failure to decode the request body, if any.
+	Body error
+
+	// Bind has the parameters that could not be bound.
+	Bind BindErrors
+
+	// Validation is the failure to validate the target, if any. It is
+	// usually a FieldErrors, without the fields already reported in Bind.
+	Validation error
+}
+
+func (e *CollectedErrors) Error() string {
+	messages := make([]string, 0, 3)
+	for _, err := range e.Unwrap() {
+		messages = append(messages, err.Error())
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+// Unwrap returns the errors that were found.
+func (e *CollectedErrors) Unwrap() []error {
+	var errs []error
+	if e.Body != nil {
+		errs = append(errs, e.Body)
+	}
+	if len(e.Bind) > 0 {
+		errs = append(errs, e.Bind)
+	}
+	if e.Validation != nil {
+		errs = append(errs, e.Validation)
+	}
+
+	return errs
+}
+
+// FieldErrors returns all errors keyed by field. Errors not related to a
+// single field are keyed by "body", for the body decoding failure, or by
+// "request", for a validation failure.
+func (e *CollectedErrors) FieldErrors() FieldErrors {
+	out := e.Bind.FieldErrors()
+	if e.Body != nil {
+		out.Add("body", e.Body.Error())
+	}
+
+	if e.Validation != nil {
+		fieldErrors, ok := NewFieldErrors(e.Validation)
+		if !ok {
+			out.Add("request", e.Validation.Error())
+			return out
+		}
+
+		for field, messages := range fieldErrors {
+			for _, msg := range messages {
+				out.Add(field, msg)
+			}
+		}
+	}
+
+	return out
+}
+
+// err returns e as an error, or nil if no error was found.
+func (e *CollectedErrors) err() error {
+	if e.Body == nil && len(e.Bind) == 0 && e.Validation == nil {
+		return nil
+	}
+
+	return e
+}
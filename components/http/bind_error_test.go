@@ -0,0 +1,148 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBindErrors(t *testing.T) {
+	t.Run("should report every invalid parameter", func(t *testing.T) {
+		var (
+			r = httptest.NewRequest(http.MethodGet, "/?limit=ten&active=maybe&name=john", nil)
+			v = struct {
+				Limit  int    `json:"limit" http:"loc=query"`
+				Active bool   `json:"active" http:"loc=query"`
+				Name   string `json:"name" http:"loc=query"`
+				Token  string `json:"token" http:"loc=header,required"`
+			}{}
+		)
+
+		err := Bind(r, &v)
+		require.Error(t, err)
+		assert.Equal(t, "john", v.Name)
+
+		var errs BindErrors
+		require.True(t, errors.As(err, &errs))
+		require.Len(t, errs, 3)
+
+		assert.Equal(t, "limit", errs[0].Field)
+		assert.Equal(t, "query", errs[0].Location)
+		assert.Equal(t, "ten", errs[0].Value)
+		assert.ErrorIs(t, errs[0].Err, strconv.ErrSyntax)
+		assert.ErrorIs(t, errs[2], ErrMissingParameter)
+
+		assert.Equal(t, FieldErrors{
+			"limit":  {"has an invalid value 'ten'"},
+			"active": {"has an invalid value 'maybe'"},
+			"token":  {"is required"},
+		}, errs.FieldErrors())
+	})
+
+	t.Run("should be usable as a single BindError", func(t *testing.T) {
+		var (
+			r = httptest.NewRequest(http.MethodGet, "/?ids=1,x", nil)
+			v = struct {
+				IDs []int `json:"ids"`
+			}{}
+		)
+
+		err := BindQuery(r, &v)
+
+		var bindErr *BindError
+		require.True(t, errors.As(err, &bindErr))
+		assert.Equal(t, "ids", bindErr.Field)
+		assert.Equal(t, "1,x", bindErr.Value)
+		assert.Contains(t, err.Error(), "invalid query parameter 'ids' value '1,x'")
+	})
+
+	t.Run("should be rendered as field errors", func(t *testing.T) {
+		errs := BindErrors{newBindError("limit", "query", []string{"ten"}, strconv.ErrSyntax)}
+
+		fieldErrors, ok := NewFieldErrors(errs)
+		require.True(t, ok)
+		assert.Equal(t, FieldErrors{"limit": {"has an invalid value 'ten'"}}, fieldErrors)
+	})
+}
+
+func TestCollectedErrors(t *testing.T) {
+	type request struct {
+		Limit int    `json:"limit" http:"loc=query" validate:"min=1"`
+		Name  string `json:"name" http:"loc=query" validate:"required"`
+		Email string `json:"email" http:"loc=body" validate:"required,email"`
+	}
+
+	opts := &BindOptions{CollectErrors: true, EnableValidation: true}
+
+	t.Run("should validate targets with parameters that were not bound", func(t *testing.T) {
+		var (
+			r = httptest.NewRequest(http.MethodGet, "/?limit=ten", nil)
+			v request
+		)
+
+		err := Bind(r, &v, opts)
+		require.Error(t, err)
+
+		var collected *CollectedErrors
+		require.True(t, errors.As(err, &collected))
+		assert.Len(t, collected.Bind, 1)
+
+		fieldErrors, ok := NewFieldErrors(err)
+		require.True(t, ok)
+		assert.Equal(t, FieldErrors{
+			"limit": {"has an invalid value 'ten'"},
+			"name":  {"is required"},
+			"email": {"is required"},
+		}, fieldErrors)
+	})
+
+	t.Run("should bind parameters of requests with invalid bodies", func(t *testing.T) {
+		var (
+			r = httptest.NewRequest(http.MethodPost, "/?limit=5&name=john", strings.NewReader(`{"email":`))
+			v request
+		)
+
+		r.Header.Set("Content-Type", "application/json")
+
+		err := BindAll(r, &v, opts)
+		require.Error(t, err)
+		assert.Equal(t, 5, v.Limit)
+		assert.Equal(t, "john", v.Name)
+
+		var collected *CollectedErrors
+		require.True(t, errors.As(err, &collected))
+		assert.Error(t, collected.Body)
+
+		fieldErrors, _ := NewFieldErrors(err)
+		assert.Contains(t, fieldErrors, "body")
+		assert.Contains(t, fieldErrors, "email")
+	})
+
+	t.Run("should keep unsupported media types detectable", func(t *testing.T) {
+		var (
+			r = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`x`))
+			v request
+		)
+
+		r.Header.Set("Content-Type", "text/x-unknown")
+
+		err := BindAll(r, &v, opts)
+		assert.ErrorIs(t, err, ErrUnsupportedMediaType)
+	})
+
+	t.Run("should return nil without errors", func(t *testing.T) {
+		var (
+			r = httptest.NewRequest(http.MethodGet, "/?limit=5&name=john", strings.NewReader(`{"email":"john@example.com"}`))
+			v request
+		)
+
+		require.NoError(t, Bind(r, &v, opts))
+		assert.Equal(t, "john@example.com", v.Email)
+	})
+}
@@ -0,0 +1,208 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	logger_api "github.com/mikros-dev/mikros/apis/features/logger"
+	mcontext "github.com/mikros-dev/mikros/components/context"
+	"github.com/mikros-dev/mikros/components/logger"
+)
+
+const (
+	defaultBindFailureMaxEntries = 1000
+	defaultBindFailureLogEvery   = 100
+)
+
+// BindFailureStatsOptions configures a BindFailureStats.
+type BindFailureStatsOptions struct {
+	// Logger, if set, receives a warning for the first failure of every
+	// route parameter and then for one in every LogEvery of them.
+	Logger logger_api.API
+
+	// LogEvery is the sampling interval of the warnings. Defaults to 100.
+	LogEvery int
+
+	// MaxEntries limits how many route parameters are tracked. Failures of
+	// new parameters are ignored once the limit is reached. Defaults to 1000.
+	MaxEntries int
+}
+
+// BindFailure is the number of times a parameter of a route could not be
+// bound.
+type BindFailure struct {
+	Route    string `json:"route"`
+	Field    string `json:"field"`
+	Location string `json:"location"`
+	Missing  uint64 `json:"missing"`
+	Invalid  uint64 `json:"invalid"`
+}
+
+// Count returns the total number of failures.
+func (f BindFailure) Count() uint64 {
+	return f.Missing + f.Invalid
+}
+
+// BindFailureStats counts the parameters that could not be bound, per route
+// and field, helping to detect widespread client mistakes, like after a
+// schema change. It is set with BindOptions.FailureStats and is safe for
+// concurrent use, so a single one can be shared by all routes.
+//
+// Routes are identified by the pattern that matched the request or, without
+// one, by its method and path.
+type BindFailureStats struct {
+	options  BindFailureStatsOptions
+	mu       sync.Mutex
+	failures map[bindFailureKey]*BindFailure
+}
+
+type bindFailureKey struct {
+	route    string
+	field    string
+	location string
+}
+
+// NewBindFailureStats creates a new BindFailureStats.
+func NewBindFailureStats(options ...BindFailureStatsOptions) *BindFailureStats {
+	var opts BindFailureStatsOptions
+	if len(options) > 0 {
+		opts = options[0]
+	}
+	if opts.LogEvery <= 0 {
+		opts.LogEvery = defaultBindFailureLogEvery
+	}
+	if opts.MaxEntries <= 0 {
+		opts.MaxEntries = defaultBindFailureMaxEntries
+	}
+
+	return &BindFailureStats{
+		options:  opts,
+		failures: make(map[bindFailureKey]*BindFailure),
+	}
+}
+
+// observe accounts the parameters of errs. It can be called on a nil
+// BindFailureStats.
+func (s *BindFailureStats) observe(r *http.Request, errs BindErrors) {
+	if s == nil || len(errs) == 0 {
+		return
+	}
+
+	route := bindFailureRoute(r)
+	for _, err := range errs {
+		count, ok := s.record(route, err)
+		if !ok || s.options.Logger == nil {
+			continue
+		}
+
+		if count == 1 || count%uint64(s.options.LogEvery) == 0 {
+			s.options.Logger.Warn(r.Context(), "request parameter could not be bound",
+				logger.String("http.route", route),
+				logger.String("bind.field", err.Field),
+				logger.String("bind.location", err.Location),
+				logger.Any("bind.failures", count),
+				logger.Error(err),
+			)
+		}
+	}
+}
+
+func (s *BindFailureStats) record(route string, err *BindError) (uint64, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := bindFailureKey{
+		route:    route,
+		field:    err.Field,
+		location: err.Location,
+	}
+
+	f, ok := s.failures[key]
+	if !ok {
+		if len(s.failures) >= s.options.MaxEntries {
+			return 0, false
+		}
+
+		f = &BindFailure{
+			Route:    route,
+			Field:    err.Field,
+			Location: err.Location,
+		}
+		s.failures[key] = f
+	}
+
+	if errors.Is(err.Err, ErrMissingParameter) {
+		f.Missing++
+	} else {
+		f.Invalid++
+	}
+
+	return f.Count(), true
+}
+
+// Failures returns the failures of every route parameter, the most frequent
+// first.
+func (s *BindFailureStats) Failures() []BindFailure {
+	s.mu.Lock()
+	failures := make([]BindFailure, 0, len(s.failures))
+	for _, f := range s.failures {
+		failures = append(failures, *f)
+	}
+	s.mu.Unlock()
+
+	sort.Slice(failures, func(i, j int) bool {
+		if failures[i].Count() != failures[j].Count() {
+			return failures[i].Count() > failures[j].Count()
+		}
+		if failures[i].Route != failures[j].Route {
+			return failures[i].Route < failures[j].Route
+		}
+
+		return failures[i].Field < failures[j].Field
+	})
+
+	return failures
+}
+
+// Handler returns an admin handler exposing the failures as JSON. The "route"
+// query parameter filters the failures of a single route and "top" limits how
+// many are returned.
+func (s *BindFailureStats) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var (
+			route  = r.URL.Query().Get("route")
+			top, _ = strconv.Atoi(r.URL.Query().Get("top"))
+			out    = make([]BindFailure, 0)
+		)
+
+		for _, f := range s.Failures() {
+			if route != "" && f.Route != route {
+				continue
+			}
+
+			out = append(out, f)
+		}
+		if top > 0 && len(out) > top {
+			out = out[:top]
+		}
+
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		_ = json.NewEncoder(w).Encode(out)
+	})
+}
+
+func bindFailureRoute(r *http.Request) string {
+	if r.Pattern != "" {
+		return r.Pattern
+	}
+	if info, ok := mcontext.HandlerInfoFromContext(r.Context()); ok && info.Route != "" {
+		return info.Route
+	}
+
+	return strings.TrimSpace(r.Method + " " + r.URL.Path)
+}
@@ -0,0 +1,91 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	logger_api "github.com/mikros-dev/mikros/apis/features/logger"
+)
+
+type fakeWarnLogger struct {
+	logger_api.API
+	warnings int
+}
+
+func (f *fakeWarnLogger) Warn(_ context.Context, _ string, _ ...logger_api.Attribute) {
+	f.warnings++
+}
+
+func TestBindFailureStats(t *testing.T) {
+	type request struct {
+		Limit int    `json:"limit" http:"loc=query"`
+		Token string `json:"token" http:"loc=header,required"`
+	}
+
+	newRequest := func(target string) *http.Request {
+		r := httptest.NewRequest(http.MethodGet, target, nil)
+		r.Pattern = "GET /users"
+		return r
+	}
+
+	t.Run("should count failures per route and field", func(t *testing.T) {
+		var (
+			stats = NewBindFailureStats()
+			opts  = &BindOptions{FailureStats: stats}
+		)
+
+		require.Error(t, Bind(newRequest("/users?limit=abc"), &request{}, opts))
+		require.Error(t, BindAll(newRequest("/users?limit=abc"), &request{}, opts))
+		require.Error(t, BindQuery(newRequest("/users?limit=x"), &request{}, opts))
+
+		r := newRequest("/users?limit=1")
+		r.Header.Set("token", "abc")
+		require.NoError(t, Bind(r, &request{}, opts))
+
+		assert.Equal(t, []BindFailure{
+			{Route: "GET /users", Field: "limit", Location: "query", Invalid: 3},
+			{Route: "GET /users", Field: "token", Location: "header", Missing: 2},
+		}, stats.Failures())
+	})
+
+	t.Run("should sample the warnings", func(t *testing.T) {
+		var (
+			log   = &fakeWarnLogger{}
+			stats = NewBindFailureStats(BindFailureStatsOptions{Logger: log, LogEvery: 3})
+		)
+
+		for i := 0; i < 7; i++ {
+			_ = BindQuery(newRequest("/users?limit=abc"), &request{}, &BindOptions{FailureStats: stats})
+		}
+
+		// The 1st, 3rd and 6th failures.
+		assert.Equal(t, 3, log.warnings)
+	})
+
+	t.Run("should stop tracking new fields after the limit", func(t *testing.T) {
+		stats := NewBindFailureStats(BindFailureStatsOptions{MaxEntries: 1})
+
+		_ = Bind(newRequest("/users?limit=abc"), &request{}, &BindOptions{FailureStats: stats})
+		assert.Len(t, stats.Failures(), 1)
+	})
+
+	t.Run("should expose the failures as JSON", func(t *testing.T) {
+		var (
+			stats = NewBindFailureStats()
+			rec   = httptest.NewRecorder()
+		)
+
+		_ = Bind(newRequest("/users?limit=abc"), &request{}, &BindOptions{FailureStats: stats})
+		stats.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/bind-failures?top=1", nil))
+
+		var out []BindFailure
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &out))
+		assert.Len(t, out, 1)
+	})
+}
@@ -0,0 +1,70 @@
+package http
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// BinderFunc converts a parameter value into a value of the type it was
+// registered for.
+type BinderFunc func(value string) (interface{}, error)
+
+var (
+	bindersMu sync.RWMutex
+	binders   = make(map[reflect.Type]BinderFunc)
+)
+
+// RegisterBinder registers the function used to convert parameters into
+// fields of type t, replacing the one previously registered for it, if any.
+// Registered binders take precedence over the builtin conversions and over
+// encoding.TextUnmarshaler, and are also used for pointers and slices of t:
+//
+//	RegisterBinder(reflect.TypeOf(uuid.UUID{}), func(s string) (interface{}, error) {
+//		return uuid.Parse(s)
+//	})
+//
+// Since it is global, binders should be registered while the application is
+// initializing.
+func RegisterBinder(t reflect.Type, fn BinderFunc) {
+	bindersMu.Lock()
+	defer bindersMu.Unlock()
+
+	binders[t] = fn
+
+	// Types bound with a binder are no longer nested structs.
+	resetFieldCache()
+}
+
+func lookupBinder(t reflect.Type) (BinderFunc, bool) {
+	bindersMu.RLock()
+	defer bindersMu.RUnlock()
+
+	fn, ok := binders[t]
+	return fn, ok
+}
+
+// setBinderValue sets into field the value converted by a registered binder.
+func setBinderValue(field reflect.Value, fn BinderFunc, value string) error {
+	v, err := fn(value)
+	if err != nil {
+		return err
+	}
+
+	rv := reflect.ValueOf(v)
+	if !rv.IsValid() {
+		field.Set(reflect.Zero(field.Type()))
+		return nil
+	}
+
+	switch {
+	case rv.Type().AssignableTo(field.Type()):
+		field.Set(rv)
+	case rv.Type().ConvertibleTo(field.Type()):
+		field.Set(rv.Convert(field.Type()))
+	default:
+		return fmt.Errorf("binder of type %s returned a %s value", field.Type(), rv.Type())
+	}
+
+	return nil
+}
@@ -0,0 +1,104 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testMoney struct {
+	Cents int64
+}
+
+type testLabels []string
+
+type testPriority int
+
+func TestRegisterBinder(t *testing.T) {
+	RegisterBinder(reflect.TypeOf(testMoney{}), func(s string) (interface{}, error) {
+		units, cents, _ := strings.Cut(s, ".")
+		if units == "" || len(cents) != 2 {
+			return nil, errors.New("invalid amount")
+		}
+
+		var m testMoney
+		for _, c := range units + cents {
+			if c < '0' || c > '9' {
+				return nil, errors.New("invalid amount")
+			}
+			m.Cents = m.Cents*10 + int64(c-'0')
+		}
+
+		return m, nil
+	})
+	RegisterBinder(reflect.TypeOf(testLabels{}), func(s string) (interface{}, error) {
+		return strings.Split(s, "|"), nil
+	})
+	RegisterBinder(reflect.TypeOf(testPriority(0)), func(s string) (interface{}, error) {
+		switch s {
+		case "low":
+			return 1, nil
+		case "high":
+			return 2, nil
+		}
+
+		return nil, errors.New("invalid priority")
+	})
+
+	t.Run("should use registered binders", func(t *testing.T) {
+		var (
+			r = httptest.NewRequest(http.MethodGet, "/?price=10.50&prices=1.00,2.50&max=3.00&labels=a|b&priority=high", nil)
+			v = struct {
+				Price    testMoney    `json:"price"`
+				Prices   []testMoney  `json:"prices"`
+				Max      *testMoney   `json:"max"`
+				Labels   testLabels   `json:"labels"`
+				Priority testPriority `json:"priority"`
+			}{}
+		)
+
+		err := BindQuery(r, &v)
+		require.NoError(t, err)
+		assert.Equal(t, testMoney{Cents: 1050}, v.Price)
+		assert.Equal(t, []testMoney{{Cents: 100}, {Cents: 250}}, v.Prices)
+		require.NotNil(t, v.Max)
+		assert.Equal(t, testMoney{Cents: 300}, *v.Max)
+		assert.Equal(t, testLabels{"a", "b"}, v.Labels)
+		assert.Equal(t, testPriority(2), v.Priority)
+	})
+
+	t.Run("should not handle struct types with binders as nested", func(t *testing.T) {
+		var (
+			r = httptest.NewRequest(http.MethodGet, "/?price=1.99", nil)
+			v = struct {
+				Price testMoney `json:"price" http:"loc=query"`
+			}{}
+		)
+
+		err := Bind(r, &v)
+		require.NoError(t, err)
+		assert.Equal(t, testMoney{Cents: 199}, v.Price)
+	})
+
+	t.Run("should report binder errors", func(t *testing.T) {
+		var (
+			r = httptest.NewRequest(http.MethodGet, "/?price=abc&priority=none", nil)
+			v = struct {
+				Price    testMoney    `json:"price"`
+				Priority testPriority `json:"priority"`
+			}{}
+		)
+
+		err := BindQuery(r, &v)
+
+		var errs BindErrors
+		require.True(t, errors.As(err, &errs))
+		assert.Len(t, errs, 2)
+	})
+}
@@ -0,0 +1,518 @@
+// Package bindgen generates static binding code for request structs, so
+// latency-sensitive services, like gateways, avoid the reflection done by the
+// binding functions of the mikros http package. The generated code makes the
+// structs implement http.GeneratedBinder, which the binding functions use
+// instead of reflection.
+//
+// It can be used directly or through go:generate with the mikros-bindgen
+// command, for structs annotated with a "//mikros:bind" comment:
+//
+//	//go:generate go run github.com/mikros-dev/mikros/cmd/mikros-bindgen -file=$GOFILE
+//
+//	//mikros:bind
+//	type ListUsersRequest struct {
+//		Limit int      `json:"limit" http:"loc=query,default=10"`
+//		Tags  []string `json:"tags" http:"loc=query"`
+//	}
+//
+// Only path, query, header and cookie fields of basic types (strings,
+// booleans, numbers and time.Duration), or slices of them, are supported,
+// with the loc, required, default and default_env tag options. Nested
+// structs holding parameters, which the reflection binding descends into,
+// are not supported either. Structs with other fields or options must keep
+// using reflection.
+package bindgen
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/stoewer/go-strcase"
+)
+
+const (
+	bindDirective = "//mikros:bind"
+)
+
+var (
+	basicTypes = []string{
+		"string", "bool",
+		"int", "int8", "int16", "int32", "int64",
+		"uint", "uint8", "uint16", "uint32", "uint64",
+		"float32", "float64",
+	}
+
+	parameterLocations = []string{"query", "header", "path", "cookie"}
+)
+
+// Options configures the generation.
+type Options struct {
+	// File is the Go source file declaring the request structs.
+	File string
+
+	// Source, if set, is used as the content of File instead of reading it.
+	Source []byte
+
+	// Types lists the structs to generate binders for. When empty, the
+	// structs annotated with a "//mikros:bind" comment are used.
+	Types []string
+
+	// Output is the name of the generated file. It defaults to File with a
+	// "_bind.go" suffix instead of ".go".
+	Output string
+}
+
+// File is a generated source file.
+type File struct {
+	Name    string
+	Content []byte
+}
+
+type templateData struct {
+	Package string
+	Types   []structData
+}
+
+type structData struct {
+	Name   string
+	Fields []fieldData
+}
+
+type fieldData struct {
+	Func     string
+	Field    string
+	Location string
+	Name     string
+	Rules    string
+}
+
+// Generate returns the formatted source file with the binders of the structs
+// selected by options.
+func Generate(options *Options) (*File, error) {
+	if options == nil {
+		return nil, errors.New("bindgen options cannot be nil")
+	}
+	if options.File == "" {
+		return nil, errors.New("bindgen requires a source file")
+	}
+
+	var src interface{}
+	if options.Source != nil {
+		src = options.Source
+	}
+
+	f, err := parser.ParseFile(token.NewFileSet(), options.File, src, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	data := templateData{
+		Package: f.Name.Name,
+	}
+
+	var (
+		timeName = importName(f, "time")
+		structs  = fileStructs(f)
+	)
+
+	for _, spec := range structSpecs(f, options.Types) {
+		s, err := newStructData(spec, timeName, structs)
+		if err != nil {
+			return nil, err
+		}
+
+		data.Types = append(data.Types, s)
+	}
+
+	if err := checkTypes(data.Types, options.Types); err != nil {
+		return nil, err
+	}
+
+	content, err := render(data)
+	if err != nil {
+		return nil, err
+	}
+
+	name := options.Output
+	if name == "" {
+		name = strings.TrimSuffix(options.File, ".go") + "_bind.go"
+	}
+
+	return &File{
+		Name:    name,
+		Content: content,
+	}, nil
+}
+
+// Write generates the binders and writes them into their file, replacing it
+// if it already exists.
+func Write(options *Options) error {
+	file, err := Generate(options)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(file.Name, file.Content, 0o644)
+}
+
+func checkTypes(found []structData, requested []string) error {
+	for _, name := range requested {
+		if !slices.ContainsFunc(found, func(s structData) bool { return s.Name == name }) {
+			return fmt.Errorf("struct '%s' not found", name)
+		}
+	}
+
+	if len(found) == 0 {
+		return errors.New("no structs to generate binders for")
+	}
+
+	return nil
+}
+
+// structSpecs returns the struct declarations of f that are listed in
+// names or, when it is empty, annotated with the bind directive.
+func structSpecs(f *ast.File, names []string) []*ast.TypeSpec {
+	var specs []*ast.TypeSpec
+	for _, decl := range f.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+
+		for _, s := range gen.Specs {
+			spec := s.(*ast.TypeSpec)
+			if _, ok := spec.Type.(*ast.StructType); !ok {
+				continue
+			}
+
+			selected := slices.Contains(names, spec.Name.Name)
+			if len(names) == 0 {
+				selected = hasDirective(spec.Doc) || (len(gen.Specs) == 1 && hasDirective(gen.Doc))
+			}
+			if selected {
+				specs = append(specs, spec)
+			}
+		}
+	}
+
+	return specs
+}
+
+// fileStructs returns the struct types declared in f by their names.
+func fileStructs(f *ast.File) map[string]*ast.StructType {
+	structs := make(map[string]*ast.StructType)
+	for _, decl := range f.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+
+		for _, s := range gen.Specs {
+			spec := s.(*ast.TypeSpec)
+			if st, ok := spec.Type.(*ast.StructType); ok {
+				structs[spec.Name.Name] = st
+			}
+		}
+	}
+
+	return structs
+}
+
+func hasDirective(doc *ast.CommentGroup) bool {
+	if doc == nil {
+		return false
+	}
+
+	return slices.ContainsFunc(doc.List, func(c *ast.Comment) bool {
+		return strings.TrimSpace(c.Text) == bindDirective
+	})
+}
+
+// importName returns the name under which f imports path, or an empty
+// string if it doesn't.
+func importName(f *ast.File, path string) string {
+	for _, imp := range f.Imports {
+		if p, _ := strconv.Unquote(imp.Path.Value); p != path {
+			continue
+		}
+		if imp.Name != nil {
+			return imp.Name.Name
+		}
+
+		return path
+	}
+
+	return ""
+}
+
+func newStructData(spec *ast.TypeSpec, timeName string, structs map[string]*ast.StructType) (structData, error) {
+	if spec.TypeParams != nil {
+		return structData{}, fmt.Errorf("struct '%s': generic structs are not supported", spec.Name.Name)
+	}
+
+	s := structData{
+		Name: spec.Name.Name,
+	}
+
+	for _, field := range spec.Type.(*ast.StructType).Fields.List {
+		fields, err := newFieldData(field, timeName, structs)
+		if err != nil {
+			return structData{}, fmt.Errorf("struct '%s': %w", s.Name, err)
+		}
+
+		s.Fields = append(s.Fields, fields...)
+	}
+
+	return s, nil
+}
+
+func newFieldData(field *ast.Field, timeName string, structs map[string]*ast.StructType) ([]fieldData, error) {
+	tag, err := fieldTag(field)
+	if err != nil {
+		return nil, err
+	}
+
+	jsonName, _, _ := strings.Cut(tag.Get("json"), ",")
+	rawHTTP, ok := tag.Lookup("http")
+	if !ok {
+		if jsonName == "-" {
+			return nil, nil
+		}
+
+		// Not bound from parameters, unless it is a nested struct with
+		// parameters of its own.
+		return nil, checkNestedField(field, structs)
+	}
+	if len(field.Names) == 0 {
+		return nil, fmt.Errorf("embedded field '%s' is not supported", types.ExprString(field.Type))
+	}
+	if jsonName == "-" {
+		return nil, nil
+	}
+
+	fn, err := bindFunc(field.Type, timeName)
+	if err != nil {
+		return nil, err
+	}
+
+	var out []fieldData
+	for _, name := range field.Names {
+		if !name.IsExported() {
+			continue
+		}
+
+		location, rules, err := parseTag(rawHTTP)
+		if err != nil {
+			return nil, fmt.Errorf("field '%s': %w", name.Name, err)
+		}
+
+		paramName := strconv.Quote(jsonName)
+		if jsonName == "" {
+			paramName = fmt.Sprintf("p.Name(%q, %q)", strcase.SnakeCase(name.Name), strings.ToLower(name.Name))
+		}
+
+		out = append(out, fieldData{
+			Func:     fn,
+			Field:    name.Name,
+			Location: location,
+			Name:     paramName,
+			Rules:    rules,
+		})
+	}
+
+	return out, nil
+}
+
+func fieldTag(field *ast.Field) (reflect.StructTag, error) {
+	if field.Tag == nil {
+		return "", nil
+	}
+
+	raw, err := strconv.Unquote(field.Tag.Value)
+	if err != nil {
+		return "", err
+	}
+
+	return reflect.StructTag(raw), nil
+}
+
+// checkNestedField fails for untagged fields whose parameters would be
+// bound by the reflection binding, which descends into nested structs, so
+// that the generated binder never binds less than it. Embedded structs not
+// declared in the same file can't be inspected and are rejected as well.
+func checkNestedField(field *ast.Field, structs map[string]*ast.StructType) error {
+	name := types.ExprString(field.Type)
+	if len(field.Names) > 0 {
+		name = field.Names[0].Name
+	}
+
+	if len(field.Names) == 0 {
+		if ident, ok := derefType(field.Type).(*ast.Ident); !ok || structs[ident.Name] == nil {
+			return fmt.Errorf("embedded field '%s' is not supported", name)
+		}
+	}
+	if hasNestedParameters(field.Type, structs, make(map[string]bool)) {
+		return fmt.Errorf("field '%s': nested parameters are not supported", name)
+	}
+
+	return nil
+}
+
+// hasNestedParameters tells if expr is a struct, declared inline or in the
+// same file, with fields bound from parameters.
+func hasNestedParameters(expr ast.Expr, structs map[string]*ast.StructType, seen map[string]bool) bool {
+	var st *ast.StructType
+	switch t := derefType(expr).(type) {
+	case *ast.StructType:
+		st = t
+	case *ast.Ident:
+		if seen[t.Name] {
+			return false
+		}
+		seen[t.Name] = true
+		st = structs[t.Name]
+	}
+	if st == nil {
+		return false
+	}
+
+	for _, field := range st.Fields.List {
+		tag, err := fieldTag(field)
+		if err != nil {
+			continue
+		}
+		if _, ok := tag.Lookup("http"); ok {
+			return true
+		}
+		if hasNestedParameters(field.Type, structs, seen) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func derefType(expr ast.Expr) ast.Expr {
+	if star, ok := expr.(*ast.StarExpr); ok {
+		return star.X
+	}
+
+	return expr
+}
+
+// bindFunc returns the http package function binding fields of type expr.
+func bindFunc(expr ast.Expr, timeName string) (string, error) {
+	if array, ok := expr.(*ast.ArrayType); ok && array.Len == nil && isBasicType(array.Elt, timeName) {
+		return "BindParameterSlice", nil
+	}
+	if isBasicType(expr, timeName) {
+		return "BindParameter", nil
+	}
+
+	return "", fmt.Errorf("unsupported field type '%s'", types.ExprString(expr))
+}
+
+func isBasicType(expr ast.Expr, timeName string) bool {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		return slices.Contains(basicTypes, t.Name)
+	case *ast.SelectorExpr:
+		pkg, ok := t.X.(*ast.Ident)
+		return ok && timeName != "" && pkg.Name == timeName && t.Sel.Name == "Duration"
+	}
+
+	return false
+}
+
+// parseTag parses an http tag into the field location and the source code
+// of its ParameterRules.
+func parseTag(raw string) (string, string, error) {
+	var (
+		location string
+		rules    []string
+		required bool
+		hasDef   bool
+	)
+
+	for _, entry := range strings.Split(raw, ",") {
+		k, v, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		switch strings.TrimSpace(k) {
+		case "loc":
+			location = strings.TrimSpace(v)
+			if !slices.Contains(parameterLocations, location) {
+				return "", "", fmt.Errorf("unsupported location '%s'", location)
+			}
+		case "required":
+			required = true
+			rules = append(rules, "Required: true")
+		case "default":
+			if !ok {
+				return "", "", errors.New("missing member default")
+			}
+			hasDef = true
+			rules = append(rules, fmt.Sprintf("Default: %q, HasDefault: true", strings.TrimSpace(v)))
+		case "default_env":
+			if !ok || strings.TrimSpace(v) == "" {
+				return "", "", errors.New("missing member default_env")
+			}
+			hasDef = true
+			rules = append(rules, fmt.Sprintf("DefaultEnv: %q", strings.TrimSpace(v)))
+		case "":
+		default:
+			return "", "", fmt.Errorf("unsupported tag option '%s'", strings.TrimSpace(k))
+		}
+	}
+
+	if location == "" {
+		return "", "", errors.New("missing member location")
+	}
+	if required && hasDef {
+		return "", "", errors.New("required and default cannot be used together")
+	}
+
+	return location, strings.Join(rules, ", "), nil
+}
+
+func render(data templateData) ([]byte, error) {
+	tpl, err := template.New("bindgen").Parse(bindersTemplate)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := tpl.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+
+	return format.Source(buf.Bytes())
+}
+
+const bindersTemplate = `// Code generated by mikros-bindgen. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+	mhttp "github.com/mikros-dev/mikros/components/http"
+)
+{{range .Types}}
+var _ mhttp.GeneratedBinder = (*{{.Name}})(nil)
+
+// BindParameters binds the {{.Name}} parameters without reflection.
+func (t *{{.Name}}) BindParameters(p *mhttp.Parameters) {
+{{- range .Fields}}
+	mhttp.{{.Func}}(p, &t.{{.Field}}, "{{.Location}}", {{.Name}}, mhttp.ParameterRules{ {{- .Rules -}} })
+{{- end}}
+}
+{{end}}`
@@ -0,0 +1,123 @@
+package bindgen
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const source = `package api
+
+import "time"
+
+//mikros:bind
+type ListUsersRequest struct {
+	ID      string        ` + "`json:\"id\" http:\"loc=path,required\"`" + `
+	Limit   int           ` + "`json:\"limit\" http:\"loc=query,default=10\"`" + `
+	Page    int           ` + "`json:\"page\" http:\"loc=query,default_env=PAGE,default=1\"`" + `
+	Tags    []string      ` + "`json:\"tags\" http:\"loc=query\"`" + `
+	Timeout time.Duration ` + "`json:\"timeout\" http:\"loc=header\"`" + `
+	Session string        ` + "`http:\"loc=cookie\"`" + `
+	Ignored string        ` + "`json:\"-\" http:\"loc=query\"`" + `
+	Body    string        ` + "`json:\"body\"`" + `
+	hidden  string        ` + "`http:\"loc=query\"`" + `
+}
+
+type NotAnnotated struct {
+	Name string ` + "`json:\"name\" http:\"loc=query\"`" + `
+}
+`
+
+func TestGenerate(t *testing.T) {
+	t.Run("should generate binders for annotated structs", func(t *testing.T) {
+		file, err := Generate(&Options{File: "api.go", Source: []byte(source)})
+		require.NoError(t, err)
+		assert.Equal(t, "api_bind.go", file.Name)
+
+		content := string(file.Content)
+		assert.Contains(t, content, "// Code generated by mikros-bindgen. DO NOT EDIT.")
+		assert.Contains(t, content, "package api")
+		assert.Contains(t, content, "func (t *ListUsersRequest) BindParameters(p *mhttp.Parameters) {")
+		assert.Contains(t, content, `mhttp.BindParameter(p, &t.ID, "path", "id", mhttp.ParameterRules{Required: true})`)
+		assert.Contains(t, content, `mhttp.BindParameter(p, &t.Limit, "query", "limit", mhttp.ParameterRules{Default: "10", HasDefault: true})`)
+		assert.Contains(t, content, `mhttp.BindParameter(p, &t.Page, "query", "page", mhttp.ParameterRules{DefaultEnv: "PAGE", Default: "1", HasDefault: true})`)
+		assert.Contains(t, content, `mhttp.BindParameterSlice(p, &t.Tags, "query", "tags", mhttp.ParameterRules{})`)
+		assert.Contains(t, content, `mhttp.BindParameter(p, &t.Timeout, "header", "timeout", mhttp.ParameterRules{})`)
+		assert.Contains(t, content, `mhttp.BindParameter(p, &t.Session, "cookie", p.Name("session", "session"), mhttp.ParameterRules{})`)
+		assert.NotContains(t, content, "Ignored")
+		assert.NotContains(t, content, "t.Body")
+		assert.NotContains(t, content, "hidden")
+		assert.NotContains(t, content, "NotAnnotated")
+	})
+
+	t.Run("should generate binders for the requested structs", func(t *testing.T) {
+		file, err := Generate(&Options{
+			File:   "api.go",
+			Source: []byte(source),
+			Types:  []string{"NotAnnotated"},
+			Output: "binders.go",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "binders.go", file.Name)
+		assert.Contains(t, string(file.Content), "func (t *NotAnnotated) BindParameters(p *mhttp.Parameters) {")
+		assert.NotContains(t, string(file.Content), "ListUsersRequest")
+	})
+
+	t.Run("should fail for missing structs", func(t *testing.T) {
+		_, err := Generate(&Options{File: "api.go", Source: []byte(source), Types: []string{"Unknown"}})
+		assert.ErrorContains(t, err, "struct 'Unknown' not found")
+
+		_, err = Generate(&Options{File: "api.go", Source: []byte("package api\n")})
+		assert.ErrorContains(t, err, "no structs to generate binders for")
+	})
+
+	t.Run("should accept nested structs without parameters", func(t *testing.T) {
+		src := "package api\n\ntype Payload struct {\nName string `json:\"name\"`\n}\n\n" +
+			"//mikros:bind\ntype Request struct {\nID string `http:\"loc=path\"`\nPayload\nBody *Payload `json:\"body\"`\n}\n"
+
+		file, err := Generate(&Options{File: "api.go", Source: []byte(src)})
+		require.NoError(t, err)
+		assert.NotContains(t, string(file.Content), "Payload")
+	})
+
+	t.Run("should fail for unsupported fields", func(t *testing.T) {
+		for _, tc := range []struct {
+			field string
+			err   string
+		}{
+			{field: "Filter map[string]string `http:\"loc=query\"`", err: "unsupported field type 'map[string]string'"},
+			{field: "Since time.Time `http:\"loc=query\"`", err: "unsupported field type 'time.Time'"},
+			{field: "Name string `http:\"loc=body\"`", err: "unsupported location 'body'"},
+			{field: "Name string `http:\"required\"`", err: "missing member location"},
+			{field: "Name string `http:\"loc=query,required,default=a\"`", err: "required and default cannot be used together"},
+			{field: "Name string `http:\"loc=query,required,default_env=NAME\"`", err: "required and default cannot be used together"},
+			{field: "Name string `http:\"loc=query,default_env\"`", err: "missing member default_env"},
+			{field: "Since string `http:\"loc=query,time_format=2006\"`", err: "unsupported tag option 'time_format'"},
+			{field: "Paging", err: "field 'Paging': nested parameters are not supported"},
+			{field: "Page *Paging `json:\"page\"`", err: "field 'Page': nested parameters are not supported"},
+			{field: "Filter struct { Name string `http:\"loc=query\"` }", err: "field 'Filter': nested parameters are not supported"},
+			{field: "time.Time", err: "embedded field 'time.Time' is not supported"},
+		} {
+			src := "package api\n\nimport \"time\"\n\nvar _ time.Time\n\n" +
+				"type Paging struct {\nLimit int `http:\"loc=query\"`\n}\n\n" +
+				"//mikros:bind\ntype Request struct {\n" + tc.field + "\n}\n"
+			_, err := Generate(&Options{File: "api.go", Source: []byte(src)})
+			assert.ErrorContains(t, err, tc.err, tc.field)
+		}
+	})
+}
+
+func TestWrite(t *testing.T) {
+	t.Run("should write the generated file next to the source", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "api.go")
+		require.NoError(t, os.WriteFile(file, []byte(source), 0o644))
+		require.NoError(t, Write(&Options{File: file}))
+
+		content, err := os.ReadFile(filepath.Join(filepath.Dir(file), "api_bind.go"))
+		require.NoError(t, err)
+		assert.Contains(t, string(content), "func (t *ListUsersRequest) BindParameters")
+	})
+}
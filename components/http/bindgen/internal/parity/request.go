@@ -0,0 +1,26 @@
+// Package parity holds request structs with binders generated by bindgen,
+// to check that they bind the same as the reflection binding.
+package parity
+
+import (
+	"time"
+)
+
+//go:generate go run github.com/mikros-dev/mikros/cmd/mikros-bindgen -file=$GOFILE
+
+// Payload is bound from the request body, so it has no parameters.
+type Payload struct {
+	Name string `json:"name"`
+}
+
+//mikros:bind
+type ListRequest struct {
+	ID      string        `json:"id" http:"loc=path,required"`
+	Limit   int           `json:"limit" http:"loc=query,default=10"`
+	Page    int           `json:"page" http:"loc=query,default_env=PARITY_PAGE,default=1"`
+	Tags    []string      `json:"tags" http:"loc=query"`
+	Timeout time.Duration `json:"timeout" http:"loc=header"`
+	Session string        `http:"loc=cookie"`
+	Ignored string        `json:"-" http:"loc=query"`
+	Payload Payload       `json:"payload"`
+}
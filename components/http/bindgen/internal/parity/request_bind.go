@@ -0,0 +1,19 @@
+// Code generated by mikros-bindgen. DO NOT EDIT.
+
+package parity
+
+import (
+	mhttp "github.com/mikros-dev/mikros/components/http"
+)
+
+var _ mhttp.GeneratedBinder = (*ListRequest)(nil)
+
+// BindParameters binds the ListRequest parameters without reflection.
+func (t *ListRequest) BindParameters(p *mhttp.Parameters) {
+	mhttp.BindParameter(p, &t.ID, "path", "id", mhttp.ParameterRules{Required: true})
+	mhttp.BindParameter(p, &t.Limit, "query", "limit", mhttp.ParameterRules{Default: "10", HasDefault: true})
+	mhttp.BindParameter(p, &t.Page, "query", "page", mhttp.ParameterRules{DefaultEnv: "PARITY_PAGE", Default: "1", HasDefault: true})
+	mhttp.BindParameterSlice(p, &t.Tags, "query", "tags", mhttp.ParameterRules{})
+	mhttp.BindParameter(p, &t.Timeout, "header", "timeout", mhttp.ParameterRules{})
+	mhttp.BindParameter(p, &t.Session, "cookie", p.Name("session", "session"), mhttp.ParameterRules{})
+}
@@ -0,0 +1,86 @@
+package parity
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	mhttp "github.com/mikros-dev/mikros/components/http"
+	"github.com/mikros-dev/mikros/components/http/bindgen"
+)
+
+// reflectedListRequest has the ListRequest fields but not its generated
+// binder, so it is bound through reflection.
+type reflectedListRequest ListRequest
+
+func TestGeneratedFile(t *testing.T) {
+	t.Run("should be up to date", func(t *testing.T) {
+		file, err := bindgen.Generate(&bindgen.Options{File: "request.go"})
+		require.NoError(t, err)
+
+		content, err := os.ReadFile(file.Name)
+		require.NoError(t, err)
+		assert.Equal(t, string(content), string(file.Content))
+	})
+}
+
+func TestBindParity(t *testing.T) {
+	tests := []struct {
+		name    string
+		request func() *http.Request
+	}{
+		{
+			name: "with every parameter",
+			request: func() *http.Request {
+				r := httptest.NewRequest(http.MethodGet, "/lists/7?limit=5&page=3&tags=a,b&Ignored=x", nil)
+				r.SetPathValue("id", "7")
+				r.Header.Set("timeout", "2s")
+				r.AddCookie(&http.Cookie{Name: "session", Value: "s1"})
+				return r
+			},
+		},
+		{
+			name: "with defaults",
+			request: func() *http.Request {
+				r := httptest.NewRequest(http.MethodGet, "/lists/7?tags=%20a%20", nil)
+				r.SetPathValue("id", "7")
+				return r
+			},
+		},
+		{
+			name: "with a body",
+			request: func() *http.Request {
+				r := httptest.NewRequest(http.MethodPost, "/lists/7?limit=1", strings.NewReader(`{"payload":{"name":"n"}}`))
+				r.Header.Set("Content-Type", "application/json")
+				r.SetPathValue("id", "7")
+				return r
+			},
+		},
+		{
+			name: "with invalid parameters",
+			request: func() *http.Request {
+				return httptest.NewRequest(http.MethodGet, "/lists?limit=many", nil)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run("should bind "+tt.name+" as the reflection binding", func(t *testing.T) {
+			var (
+				generated ListRequest
+				reflected reflectedListRequest
+			)
+
+			generatedErr := mhttp.Bind(tt.request(), &generated)
+			reflectedErr := mhttp.Bind(tt.request(), &reflected)
+
+			assert.Equal(t, reflectedErr == nil, generatedErr == nil, "generated: %v, reflected: %v", generatedErr, reflectedErr)
+			assert.Equal(t, ListRequest(reflected), generated)
+		})
+	}
+}
@@ -0,0 +1,206 @@
+// Package bindtest provides benchmark helpers and fuzz entry points for the
+// request binding functions of the mikros http package.
+//
+// Services can use it against their own request structs to catch panics and
+// performance regressions in binding:
+//
+//	func FuzzCreateUserRequest(f *testing.F) {
+//		bindtest.FuzzBind[CreateUserRequest](f)
+//	}
+//
+//	func BenchmarkCreateUserRequest(b *testing.B) {
+//		bindtest.BenchmarkBind[CreateUserRequest](b, func() *http.Request {
+//			return httptest.NewRequest(http.MethodGet, "/users?limit=10", nil)
+//		})
+//	}
+package bindtest
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	mhttp "github.com/mikros-dev/mikros/components/http"
+)
+
+// ValueSeeds is the corpus of tricky field values used to seed the fuzz entry
+// points. They are applied to every field of the target struct.
+var ValueSeeds = []string{
+	"",
+	"0",
+	"-1",
+	"18446744073709551616",
+	"1e309",
+	"NaN",
+	"true",
+	"a,b,,c",
+	",,,",
+	"2023-01-01T12:00:00Z",
+	"0000-00-00",
+	"1h30m",
+	"-9223372036854775808ns",
+	"%zz",
+	"\x00\xff",
+	"日本語",
+	strings.Repeat("9", 512),
+}
+
+// BodySeeds is the corpus of tricky request bodies used to seed the fuzz entry
+// points.
+var BodySeeds = [][]byte{
+	nil,
+	[]byte("{}"),
+	[]byte("null"),
+	[]byte("[]"),
+	[]byte(`{"a":1}{"b":2}`),
+	[]byte(`{"a":`),
+	[]byte(`{"a":"\ud800"}`),
+	[]byte(`{"a":1e999}`),
+	[]byte(strings.Repeat("[", 1024)),
+}
+
+// FuzzBind is a fuzz entry point for Bind using T as the target struct. Each
+// fuzzed value is used for all fields of T in the query string, headers and
+// path parameters, and the fuzzed body is sent as the request body.
+func FuzzBind[T any](f *testing.F) {
+	f.Helper()
+	addSeeds(f)
+
+	f.Fuzz(func(_ *testing.T, value string, body []byte) {
+		var target T
+		_ = mhttp.Bind(newFuzzRequest[T](value, body), &target)
+	})
+}
+
+// FuzzBindQuery is a fuzz entry point for BindQuery using T as the target
+// struct. Each fuzzed value is used for all fields of T in the query string.
+func FuzzBindQuery[T any](f *testing.F, opts ...*mhttp.BindOptions) {
+	f.Helper()
+	addSeeds(f)
+
+	f.Fuzz(func(_ *testing.T, value string, _ []byte) {
+		var target T
+		_ = mhttp.BindQuery(newFuzzRequest[T](value, nil), &target, opts...)
+	})
+}
+
+// FuzzBindBody is a fuzz entry point for BindBody using T as the target
+// struct.
+func FuzzBindBody[T any](f *testing.F, opts ...mhttp.BindBodyOptions) {
+	f.Helper()
+	addSeeds(f)
+
+	f.Fuzz(func(_ *testing.T, _ string, body []byte) {
+		var target T
+		_ = mhttp.BindBody(newFuzzRequest[T]("", body), &target, opts...)
+	})
+}
+
+func addSeeds(f *testing.F) {
+	for _, v := range ValueSeeds {
+		f.Add(v, []byte(nil))
+	}
+	for _, b := range BodySeeds {
+		f.Add("", b)
+	}
+}
+
+func newFuzzRequest[T any](value string, body []byte) *http.Request {
+	var (
+		names = fieldNames(reflect.TypeOf((*T)(nil)).Elem())
+		query = url.Values{}
+	)
+
+	for _, name := range names {
+		query.Set(name, value)
+	}
+
+	r := httptest.NewRequest(http.MethodPost, "/?"+query.Encode(), bytes.NewReader(body))
+	for _, name := range names {
+		r.Header.Set(name, value)
+		r.SetPathValue(name, value)
+	}
+
+	return r
+}
+
+// fieldNames returns the names that the binder looks up for each exported
+// field of t, using its json tag or the lower-case field name.
+func fieldNames(t reflect.Type) []string {
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var names []string
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(sf.Name)
+		}
+
+		names = append(names, name)
+	}
+
+	return names
+}
+
+// BenchmarkBind measures Bind using T as the target struct. The newRequest
+// function is called once per iteration, outside the measured time, since
+// request bodies can only be read once.
+func BenchmarkBind[T any](b *testing.B, newRequest func() *http.Request) {
+	b.Helper()
+	benchmark(b, newRequest, func(r *http.Request, target *T) error {
+		return mhttp.Bind(r, target)
+	})
+}
+
+// BenchmarkBindQuery measures BindQuery using T as the target struct.
+func BenchmarkBindQuery[T any](b *testing.B, newRequest func() *http.Request, opts ...*mhttp.BindOptions) {
+	b.Helper()
+	benchmark(b, newRequest, func(r *http.Request, target *T) error {
+		return mhttp.BindQuery(r, target, opts...)
+	})
+}
+
+// BenchmarkBindBody measures BindBody decoding body into T.
+func BenchmarkBindBody[T any](b *testing.B, body []byte, opts ...mhttp.BindBodyOptions) {
+	b.Helper()
+
+	newRequest := func() *http.Request {
+		return httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(body))
+	}
+
+	b.SetBytes(int64(len(body)))
+	benchmark(b, newRequest, func(r *http.Request, target *T) error {
+		return mhttp.BindBody(r, target, opts...)
+	})
+}
+
+func benchmark[T any](b *testing.B, newRequest func() *http.Request, bind func(*http.Request, *T) error) {
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		var (
+			r      = newRequest()
+			target T
+		)
+		b.StartTimer()
+
+		if err := bind(r, &target); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
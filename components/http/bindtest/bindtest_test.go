@@ -0,0 +1,58 @@
+package bindtest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	mhttp "github.com/mikros-dev/mikros/components/http"
+)
+
+type request struct {
+	ID       int64         `json:"id" http:"loc=path"`
+	Tags     []string      `json:"tags" http:"loc=query"`
+	Limit    *uint8        `json:"limit" http:"loc=query"`
+	Since    time.Time     `json:"since" http:"loc=query"`
+	Timeout  time.Duration `json:"timeout" http:"loc=header"`
+	Name     string        `json:"name" http:"loc=body"`
+	Score    float32       `json:"score" http:"loc=body"`
+	Language mhttp.Locale  `json:"accept-language" http:"loc=header"`
+	Ignored  string        `json:"-"`
+}
+
+func FuzzBindRequest(f *testing.F) {
+	FuzzBind[request](f)
+}
+
+func FuzzBindQueryRequest(f *testing.F) {
+	FuzzBindQuery[request](f)
+}
+
+func FuzzBindBodyRequest(f *testing.F) {
+	FuzzBindBody[request](f)
+}
+
+func BenchmarkBindRequest(b *testing.B) {
+	BenchmarkBind[request](b, func() *http.Request {
+		var (
+			body = strings.NewReader(`{"name":"John","score":4.5}`)
+			r    = httptest.NewRequest(http.MethodPost, "/?tags=a,b,c&limit=10&since=2023-01-01T12:00:00Z", body)
+		)
+
+		r.SetPathValue("id", "42")
+		r.Header.Set("timeout", "1s")
+		return r
+	})
+}
+
+func BenchmarkBindQueryRequest(b *testing.B) {
+	BenchmarkBindQuery[request](b, func() *http.Request {
+		return httptest.NewRequest(http.MethodGet, "/?tags=a,b,c&limit=10", nil)
+	})
+}
+
+func BenchmarkBindBodyRequest(b *testing.B) {
+	BenchmarkBindBody[request](b, []byte(`{"name":"John","score":4.5}`))
+}
@@ -0,0 +1,121 @@
+package http
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"strings"
+	"sync"
+
+	"google.golang.org/protobuf/proto"
+)
+
+var (
+	// ErrUnsupportedMediaType is returned by BindBody when no decoder is
+	// registered for the request Content-Type.
+	ErrUnsupportedMediaType = errors.New("unsupported media type")
+)
+
+// BodyDecoder decodes a request body into target. The body is already
+// limited to BindBodyOptions.MaxBytes.
+type BodyDecoder func(body io.Reader, target interface{}, options BindBodyOptions) error
+
+var (
+	bodyDecodersMu sync.RWMutex
+	bodyDecoders   = map[string]BodyDecoder{
+		"application/json":       decodeJSONBody,
+		"application/xml":        decodeXMLBody,
+		"text/xml":               decodeXMLBody,
+		"application/x-protobuf": decodeProtobufBody,
+		"application/protobuf":   decodeProtobufBody,
+	}
+)
+
+// RegisterBodyDecoder registers the decoder BindBody uses for requests with
+// the mediaType Content-Type, replacing the one previously registered for
+// it, if any. Media types are matched without their parameters, e.g.
+// "charset".
+func RegisterBodyDecoder(mediaType string, decoder BodyDecoder) {
+	bodyDecodersMu.Lock()
+	defer bodyDecodersMu.Unlock()
+
+	bodyDecoders[strings.ToLower(mediaType)] = decoder
+}
+
+// lookupBodyDecoder returns the decoder of a Content-Type header value.
+// Requests without it are decoded as JSON, as well as media types with a
+// "+json" suffix, e.g. "application/merge-patch+json", unless a decoder was
+// registered for them. The same goes for "+xml".
+func lookupBodyDecoder(contentType string) (BodyDecoder, error) {
+	if contentType == "" {
+		return decodeJSONBody, nil
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, fmt.Errorf("%w '%s'", ErrUnsupportedMediaType, contentType)
+	}
+
+	bodyDecodersMu.RLock()
+	defer bodyDecodersMu.RUnlock()
+
+	if d, ok := bodyDecoders[mediaType]; ok {
+		return d, nil
+	}
+
+	switch {
+	case strings.HasSuffix(mediaType, "+json"):
+		return bodyDecoders["application/json"], nil
+	case strings.HasSuffix(mediaType, "+xml"):
+		return bodyDecoders["application/xml"], nil
+	}
+
+	return nil, fmt.Errorf("%w '%s'", ErrUnsupportedMediaType, mediaType)
+}
+
+func decodeJSONBody(body io.Reader, target interface{}, options BindBodyOptions) error {
+	dec := json.NewDecoder(body)
+	if options.DisallowUnknownFields {
+		dec.DisallowUnknownFields()
+	}
+
+	if err := dec.Decode(target); err != nil {
+		return err
+	}
+
+	// Ensure we're dealing with a single JSON
+	if err := dec.Decode(&struct{}{}); err != io.EOF {
+		return errors.New("expected only one JSON object in request body")
+	}
+
+	return nil
+}
+
+func decodeXMLBody(body io.Reader, target interface{}, _ BindBodyOptions) error {
+	return xml.NewDecoder(body).Decode(target)
+}
+
+func decodeProtobufBody(body io.Reader, target interface{}, options BindBodyOptions) error {
+	msg, ok := target.(proto.Message)
+	if !ok {
+		return fmt.Errorf("target must be a proto.Message to decode protobuf bodies, got %T", target)
+	}
+
+	b, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+
+	if err := proto.Unmarshal(b, msg); err != nil {
+		return err
+	}
+
+	if options.DisallowUnknownFields && len(msg.ProtoReflect().GetUnknown()) > 0 {
+		return errors.New("request body has unknown protobuf fields")
+	}
+
+	return nil
+}
@@ -0,0 +1,124 @@
+package http
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func newBodyRequest(contentType string, body io.Reader) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, "/", body)
+	if contentType != "" {
+		r.Header.Set("Content-Type", contentType)
+	}
+
+	return r
+}
+
+func TestBindBodyContentNegotiation(t *testing.T) {
+	type user struct {
+		Name string `json:"name" xml:"name"`
+		Age  int    `json:"age" xml:"age"`
+	}
+
+	t.Run("should decode JSON bodies", func(t *testing.T) {
+		for _, contentType := range []string{"", "application/json; charset=utf-8", "application/merge-patch+json"} {
+			var (
+				r = newBodyRequest(contentType, strings.NewReader(`{"name":"john","age":42}`))
+				v user
+			)
+
+			err := BindBody(r, &v)
+			require.NoError(t, err, contentType)
+			assert.Equal(t, user{Name: "john", Age: 42}, v)
+		}
+	})
+
+	t.Run("should decode XML bodies", func(t *testing.T) {
+		var (
+			r = newBodyRequest("application/xml", strings.NewReader(`<user><name>john</name><age>42</age></user>`))
+			v user
+		)
+
+		err := BindBody(r, &v)
+		require.NoError(t, err)
+		assert.Equal(t, user{Name: "john", Age: 42}, v)
+	})
+
+	t.Run("should decode protobuf bodies", func(t *testing.T) {
+		b, err := proto.Marshal(wrapperspb.String("john"))
+		require.NoError(t, err)
+
+		var (
+			r = newBodyRequest("application/x-protobuf", bytes.NewReader(b))
+			v wrapperspb.StringValue
+		)
+
+		err = BindBody(r, &v)
+		require.NoError(t, err)
+		assert.Equal(t, "john", v.GetValue())
+	})
+
+	t.Run("should fail to decode protobuf bodies into other types", func(t *testing.T) {
+		var (
+			r = newBodyRequest("application/x-protobuf", strings.NewReader(""))
+			v user
+		)
+
+		err := BindBody(r, &v)
+		assert.ErrorContains(t, err, "proto.Message")
+	})
+
+	t.Run("should reject unsupported media types", func(t *testing.T) {
+		var (
+			r = newBodyRequest("application/yaml", strings.NewReader("name: john"))
+			v user
+		)
+
+		err := BindBody(r, &v)
+		assert.ErrorIs(t, err, ErrUnsupportedMediaType)
+	})
+
+	t.Run("should use registered decoders", func(t *testing.T) {
+		RegisterBodyDecoder("application/vnd.test.csv", func(body io.Reader, target interface{}, _ BindBodyOptions) error {
+			b, err := io.ReadAll(body)
+			if err != nil {
+				return err
+			}
+
+			target.(*user).Name = strings.Split(string(b), ",")[0]
+			return nil
+		})
+
+		var (
+			r = newBodyRequest("application/vnd.test.csv", strings.NewReader("john,42"))
+			v user
+		)
+
+		err := BindBody(r, &v)
+		require.NoError(t, err)
+		assert.Equal(t, "john", v.Name)
+	})
+
+	t.Run("should limit the body size of any decoder", func(t *testing.T) {
+		b, err := proto.Marshal(wrapperspb.String(strings.Repeat("a", 100)))
+		require.NoError(t, err)
+
+		var (
+			r = newBodyRequest("application/x-protobuf", io.NopCloser(bytes.NewReader(b)))
+			v wrapperspb.StringValue
+		)
+		r.ContentLength = -1
+
+		err = BindBody(r, &v, BindBodyOptions{MaxBytes: 10})
+		assert.ErrorContains(t, err, "exceeds 10 bytes")
+	})
+}
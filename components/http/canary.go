@@ -0,0 +1,82 @@
+package http
+
+import (
+	"math/rand/v2"
+	"net/http"
+	"sync/atomic"
+)
+
+// CanaryOptions configures a CanaryRouter.
+type CanaryOptions struct {
+	// Stable is the handler serving the current implementation.
+	Stable http.Handler
+
+	// Canary is the handler serving the new implementation.
+	Canary http.Handler
+
+	// Percentage is the share of requests, from 0 to 100, routed to the
+	// Canary handler.
+	Percentage float64
+
+	// Header, when set, routes every request carrying this header with
+	// HeaderValue to the Canary handler, regardless of Percentage. If
+	// HeaderValue is empty, any value matches.
+	Header      string
+	HeaderValue string
+}
+
+// CanaryRouter splits requests between two handler implementations of the
+// same route, allowing incremental rollouts inside a single service. It keeps
+// the number of requests served by each variant.
+type CanaryRouter struct {
+	options     CanaryOptions
+	random      func() float64
+	stableCalls atomic.Uint64
+	canaryCalls atomic.Uint64
+}
+
+// NewCanaryRouter creates a new CanaryRouter.
+func NewCanaryRouter(options CanaryOptions) *CanaryRouter {
+	return &CanaryRouter{
+		options: options,
+		random:  rand.Float64,
+	}
+}
+
+// ServeHTTP routes the request to one of the variants.
+func (c *CanaryRouter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if c.useCanary(r) {
+		c.canaryCalls.Add(1)
+		c.options.Canary.ServeHTTP(w, r)
+		return
+	}
+
+	c.stableCalls.Add(1)
+	c.options.Stable.ServeHTTP(w, r)
+}
+
+func (c *CanaryRouter) useCanary(r *http.Request) bool {
+	if c.options.Header != "" {
+		if v := r.Header.Get(c.options.Header); v != "" {
+			if c.options.HeaderValue == "" || v == c.options.HeaderValue {
+				return true
+			}
+		}
+	}
+
+	if c.options.Percentage <= 0 {
+		return false
+	}
+
+	return c.random()*100 < c.options.Percentage
+}
+
+// StableCalls returns how many requests were served by the Stable handler.
+func (c *CanaryRouter) StableCalls() uint64 {
+	return c.stableCalls.Load()
+}
+
+// CanaryCalls returns how many requests were served by the Canary handler.
+func (c *CanaryRouter) CanaryCalls() uint64 {
+	return c.canaryCalls.Load()
+}
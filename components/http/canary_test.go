@@ -0,0 +1,89 @@
+package http
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanaryRouter(t *testing.T) {
+	var (
+		stable = http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = io.WriteString(w, "stable")
+		})
+		canary = http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = io.WriteString(w, "canary")
+		})
+	)
+
+	serve := func(c *CanaryRouter, r *http.Request) string {
+		rec := httptest.NewRecorder()
+		c.ServeHTTP(rec, r)
+		return rec.Body.String()
+	}
+
+	t.Run("should split requests by percentage", func(t *testing.T) {
+		var (
+			c = NewCanaryRouter(CanaryOptions{
+				Stable:     stable,
+				Canary:     canary,
+				Percentage: 25,
+			})
+			values = []float64{0.1, 0.3, 0.24, 0.9}
+		)
+
+		c.random = func() float64 {
+			v := values[0]
+			values = values[1:]
+			return v
+		}
+
+		var out []string
+		for i := 0; i < 4; i++ {
+			out = append(out, serve(c, httptest.NewRequest(http.MethodGet, "/", nil)))
+		}
+
+		assert.Equal(t, []string{"canary", "stable", "canary", "stable"}, out)
+		assert.Equal(t, uint64(2), c.CanaryCalls())
+		assert.Equal(t, uint64(2), c.StableCalls())
+	})
+
+	t.Run("should never use canary with zero percentage", func(t *testing.T) {
+		c := NewCanaryRouter(CanaryOptions{Stable: stable, Canary: canary})
+		c.random = func() float64 { return 0 }
+
+		assert.Equal(t, "stable", serve(c, httptest.NewRequest(http.MethodGet, "/", nil)))
+	})
+
+	t.Run("should route matching headers to canary", func(t *testing.T) {
+		c := NewCanaryRouter(CanaryOptions{
+			Stable:      stable,
+			Canary:      canary,
+			Header:      "X-Canary",
+			HeaderValue: "on",
+		})
+
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("X-Canary", "on")
+		assert.Equal(t, "canary", serve(c, r))
+
+		r = httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("X-Canary", "off")
+		assert.Equal(t, "stable", serve(c, r))
+	})
+
+	t.Run("should match any header value when none is configured", func(t *testing.T) {
+		c := NewCanaryRouter(CanaryOptions{
+			Stable: stable,
+			Canary: canary,
+			Header: "X-Canary",
+		})
+
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("X-Canary", "1")
+		assert.Equal(t, "canary", serve(c, r))
+	})
+}
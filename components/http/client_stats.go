@@ -0,0 +1,378 @@
+package http
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultClientStatsMaxClients = 10000
+	defaultClientStatsAPIKey     = "X-API-Key"
+	clientKeyIDLength            = 16
+)
+
+var (
+	// labelEscaper escapes Prometheus label values.
+	labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+)
+
+// ClientStatsOptions configures a ClientStats.
+type ClientStatsOptions struct {
+	// Identify returns the identity of the client sending a request, such as
+	// an authenticated principal. When nil or when it returns an empty
+	// string, the APIKeyHeader value is used and, without it, the client IP
+	// address. API keys are never kept, only their ClientKeyID.
+	Identify func(r *http.Request) string
+
+	// APIKeyHeader is the header carrying the client API key. Defaults to
+	// X-API-Key.
+	APIKeyHeader string
+
+	// MaxClients limits how many clients are tracked at the same time. When
+	// the limit is reached, the client that was seen least recently is
+	// dropped. Defaults to 10000.
+	MaxClients int
+}
+
+// ClientStat is the aggregated traffic of a single client.
+type ClientStat struct {
+	Client      string    `json:"client"`
+	Requests    int64     `json:"requests"`
+	Errors      int64     `json:"errors"`
+	BytesIn     int64     `json:"bytes_in"`
+	BytesOut    int64     `json:"bytes_out"`
+	RequestRate float64   `json:"request_rate"`
+	ErrorRate   float64   `json:"error_rate"`
+	FirstSeen   time.Time `json:"first_seen"`
+	LastSeen    time.Time `json:"last_seen"`
+}
+
+// ClientStats aggregates request, error and byte counters per client identity
+// (API key, principal or IP address), helping to detect abusive clients
+// without an external gateway.
+//
+// RequestRate is the average number of requests per second since the client
+// was first seen, and ErrorRate is the share of requests answered with a 5xx
+// or 4xx status code.
+type ClientStats struct {
+	options ClientStatsOptions
+	now     func() time.Time
+	mu      sync.Mutex
+	clients map[string]*list.Element
+
+	// recent orders the clients, as *ClientStat, from the most to the
+	// least recently seen.
+	recent *list.List
+}
+
+// NewClientStats creates a new ClientStats.
+func NewClientStats(options ...ClientStatsOptions) *ClientStats {
+	var opts ClientStatsOptions
+	if len(options) > 0 {
+		opts = options[0]
+	}
+	if opts.APIKeyHeader == "" {
+		opts.APIKeyHeader = defaultClientStatsAPIKey
+	}
+	if opts.MaxClients <= 0 {
+		opts.MaxClients = defaultClientStatsMaxClients
+	}
+
+	return &ClientStats{
+		options: opts,
+		now:     time.Now,
+		clients: make(map[string]*list.Element),
+		recent:  list.New(),
+	}
+}
+
+// Middleware returns a middleware that accounts every request into the
+// stats.
+func (c *ClientStats) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var (
+			rw = &clientStatsResponseWriter{ResponseWriter: w, status: http.StatusOK}
+			in = &countingReader{ReadCloser: r.Body}
+		)
+
+		if r.Body != nil {
+			r.Body = in
+		}
+
+		next.ServeHTTP(rw, r)
+
+		// Handlers may not read the whole body, so the declared length is
+		// preferred when available.
+		bytesIn := in.n
+		if r.ContentLength > bytesIn {
+			bytesIn = r.ContentLength
+		}
+
+		c.record(c.identify(r), bytesIn, rw.n, rw.status >= http.StatusBadRequest)
+	})
+}
+
+func (c *ClientStats) identify(r *http.Request) string {
+	if c.options.Identify != nil {
+		if id := c.options.Identify(r); id != "" {
+			return id
+		}
+	}
+
+	if key := r.Header.Get(c.options.APIKeyHeader); key != "" {
+		return ClientKeyID(key)
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	return "ip:" + host
+}
+
+// ClientKeyID returns the identity given to clients sending an API key: a
+// truncated SHA-256 of the key, so keys are not exposed by the stats.
+func ClientKeyID(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return "key:" + hex.EncodeToString(sum[:])[:clientKeyIDLength]
+}
+
+func (c *ClientStats) record(client string, bytesIn, bytesOut int64, failed bool) {
+	now := c.now()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var stat *ClientStat
+	if e, ok := c.clients[client]; ok {
+		c.recent.MoveToFront(e)
+		stat = e.Value.(*ClientStat)
+	} else {
+		if len(c.clients) >= c.options.MaxClients {
+			c.evict()
+		}
+
+		stat = &ClientStat{
+			Client:    client,
+			FirstSeen: now,
+		}
+		c.clients[client] = c.recent.PushFront(stat)
+	}
+
+	stat.Requests++
+	stat.BytesIn += bytesIn
+	stat.BytesOut += bytesOut
+	stat.LastSeen = now
+	if failed {
+		stat.Errors++
+	}
+}
+
+// evict drops the client seen least recently. It must be called with the
+// lock held.
+func (c *ClientStats) evict() {
+	if oldest := c.recent.Back(); oldest != nil {
+		c.recent.Remove(oldest)
+		delete(c.clients, oldest.Value.(*ClientStat).Client)
+	}
+}
+
+// Client returns the stats of a single client.
+func (c *ClientStats) Client(client string) (ClientStat, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.clients[client]
+	if !ok {
+		return ClientStat{}, false
+	}
+
+	return c.snapshot(e.Value.(*ClientStat)), true
+}
+
+// Top returns the stats of the n clients with the most requests, or of all
+// clients if n is not positive.
+func (c *ClientStats) Top(n int) []ClientStat {
+	c.mu.Lock()
+	stats := make([]ClientStat, 0, len(c.clients))
+	for e := c.recent.Front(); e != nil; e = e.Next() {
+		stats = append(stats, c.snapshot(e.Value.(*ClientStat)))
+	}
+	c.mu.Unlock()
+
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Requests != stats[j].Requests {
+			return stats[i].Requests > stats[j].Requests
+		}
+
+		return stats[i].Client < stats[j].Client
+	})
+
+	if n > 0 && len(stats) > n {
+		stats = stats[:n]
+	}
+
+	return stats
+}
+
+func (c *ClientStats) snapshot(s *ClientStat) ClientStat {
+	out := *s
+	if elapsed := c.now().Sub(s.FirstSeen).Seconds(); elapsed >= 1 {
+		out.RequestRate = float64(s.Requests) / elapsed
+	} else {
+		out.RequestRate = float64(s.Requests)
+	}
+	if s.Requests > 0 {
+		out.ErrorRate = float64(s.Errors) / float64(s.Requests)
+	}
+
+	return out
+}
+
+// Handler returns an admin handler exposing the stats as JSON. The "client"
+// query parameter returns a single client and "top" limits how many clients
+// are returned, ordered by their number of requests.
+func (c *ClientStats) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+
+		if client := r.URL.Query().Get("client"); client != "" {
+			stat, ok := c.Client(client)
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte("{}\n"))
+				return
+			}
+
+			_ = json.NewEncoder(w).Encode(stat)
+			return
+		}
+
+		top, _ := strconv.Atoi(r.URL.Query().Get("top"))
+		_ = json.NewEncoder(w).Encode(c.Top(top))
+	})
+}
+
+// MetricsHandler returns a handler exposing the stats in the Prometheus text
+// format, labeled by client, so they can be scraped along with other
+// service metrics.
+func (c *ClientStats) MetricsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		_ = c.WriteMetrics(w)
+	})
+}
+
+// WriteMetrics writes the stats in the Prometheus text format.
+func (c *ClientStats) WriteMetrics(w io.Writer) error {
+	var (
+		stats   = c.Top(0)
+		metrics = []struct {
+			name  string
+			kind  string
+			help  string
+			value func(s ClientStat) string
+		}{
+			{
+				name:  "http_client_requests_total",
+				kind:  "counter",
+				help:  "Requests received per client.",
+				value: func(s ClientStat) string { return strconv.FormatInt(s.Requests, 10) },
+			},
+			{
+				name:  "http_client_errors_total",
+				kind:  "counter",
+				help:  "Requests per client answered with a 4xx or 5xx status code.",
+				value: func(s ClientStat) string { return strconv.FormatInt(s.Errors, 10) },
+			},
+			{
+				name:  "http_client_received_bytes_total",
+				kind:  "counter",
+				help:  "Request body bytes received per client.",
+				value: func(s ClientStat) string { return strconv.FormatInt(s.BytesIn, 10) },
+			},
+			{
+				name:  "http_client_sent_bytes_total",
+				kind:  "counter",
+				help:  "Response body bytes sent per client.",
+				value: func(s ClientStat) string { return strconv.FormatInt(s.BytesOut, 10) },
+			},
+			{
+				name:  "http_client_request_rate",
+				kind:  "gauge",
+				help:  "Average requests per second since the client was first seen.",
+				value: func(s ClientStat) string { return strconv.FormatFloat(s.RequestRate, 'g', -1, 64) },
+			},
+			{
+				name:  "http_client_error_rate",
+				kind:  "gauge",
+				help:  "Share of the client requests answered with an error.",
+				value: func(s ClientStat) string { return strconv.FormatFloat(s.ErrorRate, 'g', -1, 64) },
+			},
+		}
+	)
+
+	for _, m := range metrics {
+		if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", m.name, m.help, m.name, m.kind); err != nil {
+			return err
+		}
+
+		for _, s := range stats {
+			client := labelEscaper.Replace(s.Client)
+			if _, err := fmt.Fprintf(w, "%s{client=\"%s\"} %s\n", m.name, client, m.value(s)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+type clientStatsResponseWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	n           int64
+}
+
+func (w *clientStatsResponseWriter) WriteHeader(code int) {
+	if !w.wroteHeader {
+		w.status = code
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *clientStatsResponseWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	n, err := w.ResponseWriter.Write(b)
+	w.n += int64(n)
+	return n, err
+}
+
+func (w *clientStatsResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+type countingReader struct {
+	io.ReadCloser
+	n int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,158 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientStats(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+
+		_, _ = w.Write([]byte("hello"))
+	})
+
+	serve := func(h http.Handler, path, apiKey, body string) {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.RemoteAddr = "10.0.0.1:4321"
+		if apiKey != "" {
+			req.Header.Set("X-API-Key", apiKey)
+		}
+
+		h.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	t.Run("should aggregate requests per client", func(t *testing.T) {
+		var (
+			stats = NewClientStats()
+			h     = stats.Middleware(handler)
+		)
+
+		serve(h, "/", "abc", "1234")
+		serve(h, "/fail", "abc", "")
+		serve(h, "/", "", "")
+
+		stat, ok := stats.Client(ClientKeyID("abc"))
+		require.True(t, ok)
+		assert.Equal(t, int64(2), stat.Requests)
+		assert.Equal(t, int64(1), stat.Errors)
+		assert.Equal(t, int64(4), stat.BytesIn)
+		assert.Equal(t, int64(5), stat.BytesOut)
+		assert.InDelta(t, 0.5, stat.ErrorRate, 0.001)
+
+		stat, ok = stats.Client("ip:10.0.0.1")
+		require.True(t, ok)
+		assert.Equal(t, int64(1), stat.Requests)
+	})
+
+	t.Run("should use custom identities", func(t *testing.T) {
+		var (
+			stats = NewClientStats(ClientStatsOptions{
+				Identify: func(r *http.Request) string {
+					return r.Header.Get("X-User")
+				},
+			})
+			h = stats.Middleware(handler)
+		)
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("X-User", "john")
+		h.ServeHTTP(httptest.NewRecorder(), req)
+
+		_, ok := stats.Client("john")
+		assert.True(t, ok)
+	})
+
+	t.Run("should drop the least recently seen client", func(t *testing.T) {
+		var (
+			now   = time.Now()
+			stats = NewClientStats(ClientStatsOptions{MaxClients: 2})
+			h     = stats.Middleware(handler)
+		)
+		stats.now = func() time.Time {
+			now = now.Add(time.Second)
+			return now
+		}
+
+		serve(h, "/", "a", "")
+		serve(h, "/", "b", "")
+		serve(h, "/", "a", "")
+		serve(h, "/", "c", "")
+
+		_, ok := stats.Client(ClientKeyID("b"))
+		assert.False(t, ok)
+		assert.Len(t, stats.Top(0), 2)
+	})
+
+	t.Run("should expose the top clients", func(t *testing.T) {
+		var (
+			stats = NewClientStats()
+			h     = stats.Middleware(handler)
+		)
+
+		serve(h, "/", "a", "")
+		serve(h, "/", "b", "")
+		serve(h, "/", "b", "")
+
+		rec := httptest.NewRecorder()
+		stats.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/?top=1", nil))
+
+		var out []ClientStat
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &out))
+		require.Len(t, out, 1)
+		assert.Equal(t, ClientKeyID("b"), out[0].Client)
+		assert.NotContains(t, rec.Body.String(), `"key:b"`)
+
+		rec = httptest.NewRecorder()
+		stats.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/?client=unknown", nil))
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("should not keep API keys", func(t *testing.T) {
+		var (
+			stats = NewClientStats()
+			h     = stats.Middleware(handler)
+		)
+
+		serve(h, "/", "secret-api-key", "")
+
+		id := ClientKeyID("secret-api-key")
+		assert.Regexp(t, `^key:[0-9a-f]{16}$`, id)
+		_, ok := stats.Client(id)
+		assert.True(t, ok)
+		for _, s := range stats.Top(0) {
+			assert.NotContains(t, s.Client, "secret-api-key")
+		}
+	})
+
+	t.Run("should expose metrics", func(t *testing.T) {
+		var (
+			stats = NewClientStats()
+			h     = stats.Middleware(handler)
+		)
+
+		serve(h, "/", "", "1234")
+		serve(h, "/fail", "", "")
+
+		rec := httptest.NewRecorder()
+		stats.MetricsHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+		body := rec.Body.String()
+		assert.Contains(t, rec.Header().Get("Content-Type"), "text/plain")
+		assert.Contains(t, body, "# TYPE http_client_requests_total counter\n")
+		assert.Contains(t, body, `http_client_requests_total{client="ip:10.0.0.1"} 2`)
+		assert.Contains(t, body, `http_client_errors_total{client="ip:10.0.0.1"} 1`)
+		assert.Contains(t, body, `http_client_received_bytes_total{client="ip:10.0.0.1"} 4`)
+		assert.Contains(t, body, `http_client_error_rate{client="ip:10.0.0.1"} 0.5`)
+	})
+}
@@ -0,0 +1,81 @@
+package http
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// entityTag returns the ETag header value of a success response, either the
+// one set in its options or one computed from its encoded body. It returns
+// an empty string when the response has no ETag.
+func entityTag(body []byte, options SuccessOptions) string {
+	if options.ETag != "" {
+		return quoteETag(options.ETag)
+	}
+	if !options.GenerateETag {
+		return ""
+	}
+
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// quoteETag adds the quotes required by the ETag syntax to values without
+// them, keeping the weak validator prefix.
+func quoteETag(etag string) string {
+	if strings.HasSuffix(etag, `"`) {
+		return etag
+	}
+
+	if tag, ok := strings.CutPrefix(etag, "W/"); ok {
+		return `W/"` + tag + `"`
+	}
+
+	return `"` + etag + `"`
+}
+
+// notModified reports whether a request already has the current version of
+// the response, according to its If-None-Match or, when it is not sent,
+// If-Modified-Since headers. Only GET and HEAD requests are considered.
+func notModified(r *http.Request, etag string, lastModified time.Time) bool {
+	if r == nil || (r.Method != http.MethodGet && r.Method != http.MethodHead) {
+		return false
+	}
+
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		return etag != "" && etagMatches(inm, etag)
+	}
+
+	ims := r.Header.Get("If-Modified-Since")
+	if ims == "" || lastModified.IsZero() {
+		return false
+	}
+
+	t, err := http.ParseTime(ims)
+	if err != nil {
+		return false
+	}
+
+	// Header dates have second precision.
+	return !lastModified.Truncate(time.Second).After(t)
+}
+
+// etagMatches compares an If-None-Match header value with etag using the
+// weak comparison.
+func etagMatches(header, etag string) bool {
+	if strings.TrimSpace(header) == "*" {
+		return true
+	}
+
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(header, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(candidate), "W/") == etag {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,128 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newConditionalRequest(method string, headers map[string]string) *http.Request {
+	r := httptest.NewRequest(method, "/users/1", nil)
+	for k, v := range headers {
+		r.Header.Set(k, v)
+	}
+
+	return r
+}
+
+func TestSuccessConditional(t *testing.T) {
+	var (
+		data         = map[string]string{"name": "john"}
+		lastModified = time.Date(2024, 5, 10, 12, 30, 15, 500, time.UTC)
+	)
+
+	t.Run("should set the ETag and Last-Modified headers", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		Success(ctx, rec, data, SuccessOptions{
+			ETag:         "v1",
+			LastModified: lastModified,
+		})
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, `"v1"`, rec.Header().Get("ETag"))
+		assert.Equal(t, "Fri, 10 May 2024 12:30:15 GMT", rec.Header().Get("Last-Modified"))
+	})
+
+	t.Run("should generate the ETag from the body", func(t *testing.T) {
+		var (
+			first  = httptest.NewRecorder()
+			second = httptest.NewRecorder()
+			other  = httptest.NewRecorder()
+		)
+
+		Success(ctx, first, data, SuccessOptions{GenerateETag: true})
+		Success(ctx, second, data, SuccessOptions{GenerateETag: true})
+		Success(ctx, other, map[string]string{"name": "jane"}, SuccessOptions{GenerateETag: true})
+
+		assert.NotEmpty(t, first.Header().Get("ETag"))
+		assert.Equal(t, first.Header().Get("ETag"), second.Header().Get("ETag"))
+		assert.NotEqual(t, first.Header().Get("ETag"), other.Header().Get("ETag"))
+	})
+
+	t.Run("should answer matching If-None-Match with 304", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		Success(ctx, rec, data, SuccessOptions{
+			ETag:    `W/"v1"`,
+			Request: newConditionalRequest(http.MethodGet, map[string]string{"If-None-Match": `"v0", "v1"`}),
+			Headers: map[string]string{"Cache-Control": "max-age=60"},
+		})
+
+		assert.Equal(t, http.StatusNotModified, rec.Code)
+		assert.Empty(t, rec.Body.String())
+		assert.Equal(t, `W/"v1"`, rec.Header().Get("ETag"))
+		assert.Equal(t, "max-age=60", rec.Header().Get("Cache-Control"))
+		assert.Equal(t, "Accept", rec.Header().Get("Vary"))
+	})
+
+	t.Run("should answer a stale If-None-Match with the data", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		Success(ctx, rec, data, SuccessOptions{
+			ETag:    "v2",
+			Request: newConditionalRequest(http.MethodGet, map[string]string{"If-None-Match": `"v1"`}),
+		})
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.NotEmpty(t, rec.Body.String())
+	})
+
+	t.Run("should answer If-Modified-Since with 304 when not modified", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		Success(ctx, rec, data, SuccessOptions{
+			LastModified: lastModified,
+			Request: newConditionalRequest(http.MethodGet, map[string]string{
+				"If-Modified-Since": "Fri, 10 May 2024 12:30:15 GMT",
+			}),
+		})
+
+		assert.Equal(t, http.StatusNotModified, rec.Code)
+	})
+
+	t.Run("should answer If-Modified-Since with the data when modified", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		Success(ctx, rec, data, SuccessOptions{
+			LastModified: lastModified,
+			Request: newConditionalRequest(http.MethodGet, map[string]string{
+				"If-Modified-Since": "Fri, 10 May 2024 12:30:14 GMT",
+			}),
+		})
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("should ignore If-Modified-Since when If-None-Match is sent", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		Success(ctx, rec, data, SuccessOptions{
+			ETag:         "v2",
+			LastModified: lastModified,
+			Request: newConditionalRequest(http.MethodGet, map[string]string{
+				"If-None-Match":     `"v1"`,
+				"If-Modified-Since": "Fri, 10 May 2024 12:30:15 GMT",
+			}),
+		})
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("should not answer unsafe methods with 304", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		Success(ctx, rec, data, SuccessOptions{
+			ETag:    "v1",
+			Request: newConditionalRequest(http.MethodPost, map[string]string{"If-None-Match": "*"}),
+		})
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+}
@@ -0,0 +1,118 @@
+package http
+
+import (
+	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+)
+
+var (
+	// ErrUnsupportedContentEncoding is returned by BindBody when no decoder
+	// is registered for one of the request Content-Encoding values.
+	ErrUnsupportedContentEncoding = errors.New("unsupported content encoding")
+)
+
+// ContentDecoder wraps a request body compressed with a content coding to
+// give back its decompressed data.
+type ContentDecoder func(body io.Reader) (io.ReadCloser, error)
+
+var (
+	contentDecodersMu sync.RWMutex
+	contentDecoders   = map[string]ContentDecoder{
+		"gzip":    decodeGzipContent,
+		"x-gzip":  decodeGzipContent,
+		"deflate": decodeDeflateContent,
+		"br":      decodeBrotliContent,
+	}
+)
+
+// RegisterContentDecoder registers the decoder BindBody uses for requests
+// with the encoding Content-Encoding, replacing the one previously registered
+// for it, if any.
+func RegisterContentDecoder(encoding string, decoder ContentDecoder) {
+	contentDecodersMu.Lock()
+	defer contentDecodersMu.Unlock()
+
+	contentDecoders[strings.ToLower(encoding)] = decoder
+}
+
+// decodeContent returns the decompressed body of a request according to its
+// Content-Encoding header value. Codings are listed in the order they were
+// applied, so they are removed from the last to the first one.
+func decodeContent(body io.Reader, contentEncoding string) (io.Reader, func(), error) {
+	var (
+		closers []io.Closer
+		closeFn = func() {
+			for _, c := range closers {
+				_ = c.Close()
+			}
+		}
+		codings = strings.Split(contentEncoding, ",")
+	)
+
+	for i := len(codings) - 1; i >= 0; i-- {
+		coding := strings.ToLower(strings.TrimSpace(codings[i]))
+		if coding == "" || coding == "identity" {
+			continue
+		}
+
+		contentDecodersMu.RLock()
+		decoder, ok := contentDecoders[coding]
+		contentDecodersMu.RUnlock()
+
+		if !ok {
+			closeFn()
+			return nil, nil, fmt.Errorf("%w '%s'", ErrUnsupportedContentEncoding, coding)
+		}
+
+		rc, err := decoder(body)
+		if err != nil {
+			closeFn()
+			return nil, nil, fmt.Errorf("could not decode '%s' request body: %w", coding, err)
+		}
+
+		closers = append(closers, rc)
+		body = rc
+	}
+
+	return body, closeFn, nil
+}
+
+func decodeGzipContent(body io.Reader) (io.ReadCloser, error) {
+	return gzip.NewReader(body)
+}
+
+// decodeDeflateContent accepts both the zlib format, which is what HTTP
+// defines for "deflate", and the raw deflate one that some clients send
+// instead.
+func decodeDeflateContent(body io.Reader) (io.ReadCloser, error) {
+	br := bufio.NewReader(body)
+	header, err := br.Peek(2)
+	if err != nil {
+		return nil, err
+	}
+
+	if isZlibHeader(header) {
+		return zlib.NewReader(br)
+	}
+
+	return flate.NewReader(br), nil
+}
+
+// isZlibHeader reports whether b starts a zlib stream, i.e., uses the deflate
+// compression method and has a valid header checksum.
+func isZlibHeader(b []byte) bool {
+	return b[0]&0x0f == 8 && (uint16(b[0])<<8|uint16(b[1]))%31 == 0
+}
+
+func decodeBrotliContent(body io.Reader) (io.ReadCloser, error) {
+	return io.NopCloser(brotli.NewReader(body)), nil
+}
@@ -0,0 +1,141 @@
+package http
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func compressBody(t *testing.T, encoding string, data []byte) []byte {
+	t.Helper()
+
+	var (
+		buf bytes.Buffer
+		w   io.WriteCloser
+	)
+
+	switch encoding {
+	case "gzip":
+		w = gzip.NewWriter(&buf)
+	case "deflate":
+		w = zlib.NewWriter(&buf)
+	case "raw-deflate":
+		fw, err := flate.NewWriter(&buf, flate.DefaultCompression)
+		require.NoError(t, err)
+		w = fw
+	case "br":
+		w = brotli.NewWriter(&buf)
+	}
+
+	_, err := w.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	return buf.Bytes()
+}
+
+func TestBindBodyContentEncoding(t *testing.T) {
+	type user struct {
+		Name string `json:"name"`
+	}
+
+	body := []byte(`{"name":"john"}`)
+
+	t.Run("should decompress encoded bodies", func(t *testing.T) {
+		for _, tc := range []struct {
+			header   string
+			encoding string
+		}{
+			{header: "gzip", encoding: "gzip"},
+			{header: "deflate", encoding: "deflate"},
+			{header: "deflate", encoding: "raw-deflate"},
+			{header: "br", encoding: "br"},
+		} {
+			var (
+				r = newBodyRequest("application/json", bytes.NewReader(compressBody(t, tc.encoding, body)))
+				v user
+			)
+
+			r.Header.Set("Content-Encoding", tc.header)
+			require.NoError(t, BindBody(r, &v), tc.encoding)
+			assert.Equal(t, user{Name: "john"}, v)
+		}
+	})
+
+	t.Run("should remove codings in reverse order", func(t *testing.T) {
+		var (
+			data = compressBody(t, "br", compressBody(t, "gzip", body))
+			r    = newBodyRequest("application/json", bytes.NewReader(data))
+			v    user
+		)
+
+		r.Header.Set("Content-Encoding", "gzip, identity, br")
+		require.NoError(t, BindBody(r, &v))
+		assert.Equal(t, user{Name: "john"}, v)
+	})
+
+	t.Run("should fail with unsupported codings", func(t *testing.T) {
+		r := newBodyRequest("application/json", bytes.NewReader(body))
+		r.Header.Set("Content-Encoding", "compress")
+
+		err := BindBody(r, &user{})
+		assert.True(t, errors.Is(err, ErrUnsupportedContentEncoding))
+	})
+
+	t.Run("should fail with invalid compressed data", func(t *testing.T) {
+		r := newBodyRequest("application/json", bytes.NewReader(body))
+		r.Header.Set("Content-Encoding", "gzip")
+
+		assert.Error(t, BindBody(r, &user{}))
+	})
+
+	t.Run("should limit the decompressed size", func(t *testing.T) {
+		var (
+			large = []byte(`{"name":"` + strings.Repeat("a", 4096) + `"}`)
+			r     = newBodyRequest("application/json", bytes.NewReader(compressBody(t, "gzip", large)))
+		)
+
+		r.Header.Set("Content-Encoding", "gzip")
+		err := BindBody(r, &user{}, BindBodyOptions{MaxBytes: 1024, MaxDecompressedBytes: 2048})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "decompressed request body exceeds 2048 bytes")
+	})
+
+	t.Run("should use registered decoders", func(t *testing.T) {
+		RegisterContentDecoder("x-reverse", func(body io.Reader) (io.ReadCloser, error) {
+			b, err := io.ReadAll(body)
+			if err != nil {
+				return nil, err
+			}
+
+			for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+				b[i], b[j] = b[j], b[i]
+			}
+
+			return io.NopCloser(bytes.NewReader(b)), nil
+		})
+		defer func() {
+			contentDecodersMu.Lock()
+			delete(contentDecoders, "x-reverse")
+			contentDecodersMu.Unlock()
+		}()
+
+		var (
+			r = newBodyRequest("application/json", strings.NewReader(`}"nhoj":"eman"{`))
+			v user
+		)
+
+		r.Header.Set("Content-Encoding", "X-Reverse")
+		require.NoError(t, BindBody(r, &v))
+		assert.Equal(t, user{Name: "john"}, v)
+	})
+}
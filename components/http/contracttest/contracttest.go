@@ -0,0 +1,241 @@
+// Package contracttest generates baseline API contract tests from the
+// operations documented in an OpenAPI document.
+//
+// Services can use it to check that their HTTP handler enforces the
+// documented contract:
+//
+//	func TestContract(t *testing.T) {
+//		doc, err := mhttp.ParseOpenAPI(openapiJSON)
+//		require.NoError(t, err)
+//
+//		contracttest.Run(t, newRouter(), doc)
+//	}
+package contracttest
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	mhttp "github.com/mikros-dev/mikros/components/http"
+)
+
+const (
+	unknownRoutePath   = "/__contracttest__/unknown-route"
+	invalidContentType = "application/x-contracttest-invalid"
+)
+
+// Options configures which contract tests are generated and what they expect.
+type Options struct {
+	// SkipUnknownRoute disables the test checking that an undocumented
+	// route returns 404 Not Found.
+	SkipUnknownRoute bool
+
+	// SkipAuth disables the tests checking that operations requiring
+	// authentication return 401 Unauthorized when called without
+	// credentials.
+	SkipAuth bool
+
+	// SkipContentType disables the tests checking that operations with a
+	// request body reject undocumented content types.
+	SkipContentType bool
+
+	// ContentTypeStatusCodes are the status codes accepted when an
+	// undocumented content type is sent. Defaults to 400 and 415.
+	ContentTypeStatusCodes []int
+
+	// PrepareRequest, if set, is called with every generated request
+	// before it is sent, allowing tests to add required headers, for
+	// example.
+	PrepareRequest func(r *http.Request)
+}
+
+// Case is a single generated contract test.
+type Case struct {
+	// Name describes the test case.
+	Name string
+
+	// Method and Path are used to build the request.
+	Method string
+	Path   string
+
+	// Headers are set in the request.
+	Headers map[string]string
+
+	// Body is the request body.
+	Body string
+
+	// ExpectedStatusCodes are the accepted response status codes.
+	ExpectedStatusCodes []int
+}
+
+// Request builds the case HTTP request.
+func (c Case) Request() *http.Request {
+	r := httptest.NewRequest(c.Method, c.Path, strings.NewReader(c.Body))
+	for k, v := range c.Headers {
+		r.Header.Set(k, v)
+	}
+
+	return r
+}
+
+// Cases returns the contract test table generated from the document.
+func Cases(doc *mhttp.OpenAPIDocument, options ...Options) []Case {
+	var opts Options
+	if len(options) > 0 {
+		opts = options[0]
+	}
+	if len(opts.ContentTypeStatusCodes) == 0 {
+		opts.ContentTypeStatusCodes = []int{http.StatusBadRequest, http.StatusUnsupportedMediaType}
+	}
+
+	var cases []Case
+	if !opts.SkipUnknownRoute {
+		cases = append(cases, Case{
+			Name:                "unknown route returns 404",
+			Method:              http.MethodGet,
+			Path:                unknownRoutePath,
+			ExpectedStatusCodes: []int{http.StatusNotFound},
+		})
+	}
+
+	for _, route := range doc.Routes() {
+		path := samplePath(route)
+
+		if !opts.SkipAuth && route.Operation.RequiresAuth() {
+			c := Case{
+				Name:                fmt.Sprintf("%s %s without credentials returns 401", route.Method, route.Path),
+				Method:              route.Method,
+				Path:                path,
+				ExpectedStatusCodes: []int{http.StatusUnauthorized},
+			}
+
+			// Send a documented content type so that only the missing
+			// credentials make the request fail.
+			if contentType, ok := documentedContentType(route.Operation); ok {
+				c.Headers = map[string]string{
+					"Content-Type": contentType,
+				}
+			}
+
+			cases = append(cases, c)
+		}
+
+		if !opts.SkipContentType && hasDocumentedContent(route.Operation) {
+			cases = append(cases, Case{
+				Name:   fmt.Sprintf("%s %s rejects undocumented content type", route.Method, route.Path),
+				Method: route.Method,
+				Path:   path,
+				Headers: map[string]string{
+					"Content-Type": invalidContentType,
+				},
+				Body:                "contracttest",
+				ExpectedStatusCodes: opts.ContentTypeStatusCodes,
+			})
+		}
+	}
+
+	return cases
+}
+
+func hasDocumentedContent(operation *mhttp.OpenAPIOperation) bool {
+	if operation.RequestBody == nil || len(operation.RequestBody.Content) == 0 {
+		return false
+	}
+
+	// Operations accepting anything can't reject a content type.
+	_, wildcard := operation.RequestBody.Content["*/*"]
+	return !wildcard
+}
+
+func documentedContentType(operation *mhttp.OpenAPIOperation) (string, bool) {
+	if operation.RequestBody == nil || len(operation.RequestBody.Content) == 0 {
+		return "", false
+	}
+
+	var contentTypes []string
+	for contentType := range operation.RequestBody.Content {
+		contentTypes = append(contentTypes, contentType)
+	}
+	slices.Sort(contentTypes)
+
+	return contentTypes[0], true
+}
+
+// samplePath fills the route path template with values matching its path
+// parameters schemas.
+func samplePath(route mhttp.OpenAPIRoute) string {
+	segments := strings.Split(route.Path, "/")
+	for i, s := range segments {
+		name, ok := strings.CutPrefix(s, "{")
+		if !ok {
+			continue
+		}
+		name = strings.TrimSuffix(name, "}")
+
+		var schema *mhttp.OpenAPISchema
+		for _, p := range route.Operation.Parameters {
+			if p.In == "path" && p.Name == name {
+				schema = p.Schema
+			}
+		}
+
+		segments[i] = sampleValue(schema)
+	}
+
+	return strings.Join(segments, "/")
+}
+
+func sampleValue(schema *mhttp.OpenAPISchema) string {
+	if schema == nil {
+		return "contracttest"
+	}
+	if len(schema.Enum) > 0 {
+		return fmt.Sprint(schema.Enum[0])
+	}
+
+	switch {
+	case schema.Type == "integer" || schema.Type == "number":
+		return "1"
+	case schema.Type == "boolean":
+		return "true"
+	case schema.Format == "uuid":
+		return "00000000-0000-4000-8000-000000000000"
+	case schema.Format == "date":
+		return "2024-01-01"
+	case schema.Format == "date-time":
+		return "2024-01-01T00:00:00Z"
+	}
+
+	return "contracttest"
+}
+
+// Run executes the contract tests generated from the document against a
+// handler, each one as a subtest.
+func Run(t *testing.T, handler http.Handler, doc *mhttp.OpenAPIDocument, options ...Options) {
+	t.Helper()
+
+	var opts Options
+	if len(options) > 0 {
+		opts = options[0]
+	}
+
+	for _, c := range Cases(doc, options...) {
+		t.Run(c.Name, func(t *testing.T) {
+			r := c.Request()
+			if opts.PrepareRequest != nil {
+				opts.PrepareRequest(r)
+			}
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, r)
+
+			if !slices.Contains(c.ExpectedStatusCodes, rec.Code) {
+				t.Errorf("%s %s: expected status code in %v, got %d", c.Method, c.Path, c.ExpectedStatusCodes, rec.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package contracttest
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	mhttp "github.com/mikros-dev/mikros/components/http"
+)
+
+const testDocument = `{
+	"security": [{"bearer": []}],
+	"paths": {
+		"/users/{id}": {
+			"get": {
+				"parameters": [{"name": "id", "in": "path", "required": true, "schema": {"type": "integer"}}]
+			}
+		},
+		"/users": {
+			"post": {
+				"requestBody": {"content": {"application/json": {}}}
+			}
+		},
+		"/health": {
+			"get": {"security": []}
+		}
+	}
+}`
+
+func newTestRouter() http.Handler {
+	var (
+		mux  = http.NewServeMux()
+		auth = func(next http.HandlerFunc) http.HandlerFunc {
+			return func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("Authorization") == "" {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+
+				next(w, r)
+			}
+		}
+	)
+
+	mux.HandleFunc("GET /users/{id}", auth(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	mux.HandleFunc("POST /users", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Type") != "application/json" {
+			w.WriteHeader(http.StatusUnsupportedMediaType)
+			return
+		}
+
+		auth(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusCreated)
+		})(w, r)
+	})
+	mux.HandleFunc("GET /health", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	return mux
+}
+
+func TestCases(t *testing.T) {
+	doc, err := mhttp.ParseOpenAPI([]byte(testDocument))
+	require.NoError(t, err)
+
+	t.Run("should generate cases from the document", func(t *testing.T) {
+		var names []string
+		for _, c := range Cases(doc) {
+			names = append(names, c.Name)
+		}
+
+		assert.Equal(t, []string{
+			"unknown route returns 404",
+			"POST /users without credentials returns 401",
+			"POST /users rejects undocumented content type",
+			"GET /users/{id} without credentials returns 401",
+		}, names)
+	})
+
+	t.Run("should fill path parameters with sample values", func(t *testing.T) {
+		cases := Cases(doc, Options{SkipUnknownRoute: true, SkipContentType: true})
+		require.Len(t, cases, 2)
+		assert.Equal(t, "/users/1", cases[1].Path)
+	})
+}
+
+func TestRun(t *testing.T) {
+	doc, err := mhttp.ParseOpenAPI([]byte(testDocument))
+	require.NoError(t, err)
+
+	Run(t, newTestRouter(), doc)
+}
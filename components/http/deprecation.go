@@ -0,0 +1,146 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Deprecation holds the deprecation metadata of a route. It is used by the
+// Deprecate middleware to add the Deprecation, Sunset (RFC 8594) and Link
+// headers to the route responses.
+type Deprecation struct {
+	// Date is when the route was deprecated. If zero, the Deprecation header
+	// is sent as "true".
+	Date time.Time
+
+	// Sunset is when the route will stop responding. If zero, the Sunset
+	// header is not sent.
+	Sunset time.Time
+
+	// Link points to documentation about the deprecation and is sent as a
+	// Link header with rel="deprecation".
+	Link string
+
+	// Successor points to the route that replaces the deprecated one and is
+	// sent as a Link header with rel="successor-version".
+	Successor string
+
+	// Name identifies the route in the DeprecationCounter. If empty, the
+	// request pattern is used, and requests without one, e.g. of handlers
+	// not registered in a ServeMux, are counted as UnmatchedRoute.
+	Name string
+
+	// Counter, if set, counts the calls made to the deprecated route.
+	Counter *DeprecationCounter
+}
+
+// Deprecate returns a middleware that adds deprecation headers to all
+// responses of the wrapped handler and counts its calls.
+//
+//	mux.Handle("GET /v1/users", Deprecate(Deprecation{
+//		Sunset:    time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+//		Successor: "/v2/users",
+//	})(listUsers))
+func Deprecate(d Deprecation) func(http.Handler) http.Handler {
+	headers := d.headers()
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			for k, values := range headers {
+				for _, v := range values {
+					w.Header().Add(k, v)
+				}
+			}
+
+			if d.Counter != nil {
+				d.Counter.inc(d.routeName(r))
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func (d Deprecation) headers() http.Header {
+	h := http.Header{}
+
+	deprecation := "true"
+	if !d.Date.IsZero() {
+		deprecation = fmt.Sprintf("@%d", d.Date.Unix())
+	}
+	h.Set("Deprecation", deprecation)
+
+	if !d.Sunset.IsZero() {
+		h.Set("Sunset", d.Sunset.UTC().Format(http.TimeFormat))
+	}
+	if d.Link != "" {
+		h.Add("Link", fmt.Sprintf(`<%s>; rel="deprecation"`, d.Link))
+	}
+	if d.Successor != "" {
+		h.Add("Link", fmt.Sprintf(`<%s>; rel="successor-version"`, d.Successor))
+	}
+
+	return h
+}
+
+func (d Deprecation) routeName(r *http.Request) string {
+	if d.Name != "" {
+		return d.Name
+	}
+	if r.Pattern != "" {
+		return r.Pattern
+	}
+
+	// Paths are not used, since they would make the routes unbounded.
+	return UnmatchedRoute
+}
+
+// UnmatchedRoute is the DeprecationCounter route of the calls that have
+// neither a Deprecation.Name nor a request pattern.
+const UnmatchedRoute = "unmatched"
+
+// DeprecationCounter counts calls made to deprecated routes. It is safe for
+// concurrent use and can be shared by several routes.
+type DeprecationCounter struct {
+	mu     sync.Mutex
+	counts map[string]uint64
+}
+
+// NewDeprecationCounter creates a new DeprecationCounter.
+func NewDeprecationCounter() *DeprecationCounter {
+	return &DeprecationCounter{
+		counts: make(map[string]uint64),
+	}
+}
+
+func (c *DeprecationCounter) inc(route string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.counts[route]++
+}
+
+// Count returns how many times a deprecated route was called.
+func (c *DeprecationCounter) Count(route string) uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.counts[route]
+}
+
+// Routes returns the names of all deprecated routes that were called, sorted.
+func (c *DeprecationCounter) Routes() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	routes := make([]string, 0, len(c.counts))
+	for route := range c.counts {
+		routes = append(routes, route)
+	}
+	sort.Strings(routes)
+
+	return routes
+}
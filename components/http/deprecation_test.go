@@ -0,0 +1,84 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeprecate(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	t.Run("should add deprecation headers", func(t *testing.T) {
+		var (
+			rec = httptest.NewRecorder()
+			r   = httptest.NewRequest(http.MethodGet, "/v1/users", nil)
+			h   = Deprecate(Deprecation{
+				Date:      time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+				Sunset:    time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+				Link:      "https://example.com/deprecation",
+				Successor: "/v2/users",
+			})(ok)
+		)
+
+		h.ServeHTTP(rec, r)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "@1704067200", rec.Header().Get("Deprecation"))
+		assert.Equal(t, "Wed, 01 Jan 2025 00:00:00 GMT", rec.Header().Get("Sunset"))
+		assert.Equal(t, []string{
+			`<https://example.com/deprecation>; rel="deprecation"`,
+			`</v2/users>; rel="successor-version"`,
+		}, rec.Header().Values("Link"))
+	})
+
+	t.Run("should send only the Deprecation header without metadata", func(t *testing.T) {
+		var (
+			rec = httptest.NewRecorder()
+			r   = httptest.NewRequest(http.MethodGet, "/v1/users", nil)
+		)
+
+		Deprecate(Deprecation{})(ok).ServeHTTP(rec, r)
+
+		assert.Equal(t, "true", rec.Header().Get("Deprecation"))
+		assert.Empty(t, rec.Header().Get("Sunset"))
+		assert.Empty(t, rec.Header().Get("Link"))
+	})
+
+	t.Run("should count calls per route", func(t *testing.T) {
+		var (
+			counter = NewDeprecationCounter()
+			mux     = http.NewServeMux()
+		)
+
+		mux.Handle("GET /v1/users/{id}", Deprecate(Deprecation{Counter: counter})(ok))
+		mux.Handle("GET /v1/items", Deprecate(Deprecation{Counter: counter, Name: "items"})(ok))
+
+		for _, path := range []string{"/v1/users/1", "/v1/users/2", "/v1/items"} {
+			mux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+		}
+
+		assert.Equal(t, uint64(2), counter.Count("GET /v1/users/{id}"))
+		assert.Equal(t, uint64(1), counter.Count("items"))
+		assert.Equal(t, []string{"GET /v1/users/{id}", "items"}, counter.Routes())
+	})
+
+	t.Run("should count calls without a pattern as unmatched", func(t *testing.T) {
+		var (
+			counter = NewDeprecationCounter()
+			handler = Deprecate(Deprecation{Counter: counter})(ok)
+		)
+
+		for _, path := range []string{"/v1/users/1", "/v1/users/2"} {
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+		}
+
+		assert.Equal(t, uint64(2), counter.Count(UnmatchedRoute))
+		assert.Equal(t, []string{UnmatchedRoute}, counter.Routes())
+	})
+}
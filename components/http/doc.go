@@ -1,6 +1,6 @@
 // Package http provides a comprehensive set of HTTP request parameter binding utilities
 // for Go applications. It enables automatic extraction and type conversion of request
-// data (query parameters, headers, path parameters, and JSON body) into Go structs
+// data (query parameters, headers, cookies, path parameters, and JSON body) into Go structs
 // using struct field tags and reflection.
 //
 // # Overview
@@ -30,6 +30,11 @@
 //		// Use req.ID, req.Name, req.Active...
 //	}
 //
+// All binding functions accept an optional *BindOptions. Options shared by
+// the whole service can be set once with the DefaultBindOptions member of the
+// HTTP service options, which the runtime stores inside the context of every
+// request (see NewBindOptionsContext). Calls without options use them.
+//
 // # Multi-Source Binding
 //
 // The Bind function supports extracting data from different request sources based on
@@ -39,6 +44,7 @@
 //		UserID   string    `json:"user_id" http:"loc=path"`        // From URL path
 //		Filter   string    `json:"filter" http:"loc=query"`        // From query string
 //		APIKey   string    `json:"api_key" http:"loc=header"`      // From headers
+//		Session  string    `json:"session" http:"loc=cookie"`      // From cookies
 //		PageSize int       `json:"page_size" http:"loc=query"`     // From query string
 //		Created  time.Time `json:"created" http:"loc=query,time_format=2006-01-02"`
 //	}
@@ -62,6 +68,92 @@
 //
 // Fields tagged with `json:"-"` are skipped during binding.
 //
+// # Nested Structs
+//
+// Nested structs are bound field by field, with parameter names prefixed by
+// the struct name and a dot. Fields without an http tag inherit the location
+// of the struct field containing them, and a `prefix` tag option replaces the
+// default prefix. Embedded structs are bound as if their fields were declared
+// in the outer struct:
+//
+//	type Pagination struct {
+//		Page int `json:"page" http:"loc=query"`
+//	}
+//
+//	type ListRequest struct {
+//		Pagination
+//		Filter struct {
+//			Name string `json:"name"`
+//		} `json:"filter" http:"loc=query"` // ?filter.name=john
+//	}
+//
+// Nested structs located in the body are decoded from it as a whole.
+//
+// # Required and Default Values
+//
+// The `required` tag option makes binding fail when a parameter is absent,
+// while `default` assigns a value to it, using the same type conversion of
+// received values:
+//
+//	type ListRequest struct {
+//		OrgID    string `json:"org_id" http:"loc=path,required"`
+//		PageSize int    `json:"page_size" http:"loc=query,default=10"`
+//	}
+//
+// The specialized binding functions (BindQuery, BindHeader, ...) also honor
+// these options for fields whose tag location matches theirs or that have no
+// location at all, e.g. `http:"required"`.
+//
+// Defaults can also come from the environment with `default_env`, which
+// takes precedence over `default` when the variable is set, allowing them to
+// change between deployments:
+//
+//	PageSize int `json:"page_size" http:"loc=query,default_env=PAGE_SIZE,default=10"`
+//
+// The variable is read only once, the first time the struct is bound.
+//
+// # Binding Errors
+//
+// Parameters that cannot be converted into their fields, or required ones
+// that were not sent, don't interrupt the binding. They are all returned
+// together as BindErrors, with a BindError for each parameter carrying its
+// name, location, raw value and the underlying cause:
+//
+//	var errs BindErrors
+//	if errors.As(err, &errs) {
+//		ValidationProblem(ctx, w, errs)
+//	}
+//
+// Other failures, like a body that cannot be decoded, stop the binding, and
+// targets are only validated when everything was bound. With
+// BindOptions.CollectErrors, binding goes on and validation runs anyway,
+// returning every problem as a *CollectedErrors, which ValidationProblem
+// renders with all fields at once.
+//
+// A BindFailureStats set in BindOptions.FailureStats counts these errors per
+// route and field, and can log sampled warnings, so widespread client
+// mistakes show up after a schema change:
+//
+//	stats := NewBindFailureStats(BindFailureStatsOptions{Logger: log})
+//	mux.Handle("GET /admin/bind-failures", stats.Handler())
+//
+// # Validation
+//
+// When BindOptions.EnableValidation is set, targets are validated after being
+// bound. Their `validate` tags are checked with go-playground/validator and,
+// if they implement Validator, their Validate method is called:
+//
+//	type CreateRequest struct {
+//		Name  string `json:"name" http:"loc=query" validate:"required"`
+//		Email string `json:"email" http:"loc=query" validate:"email"`
+//	}
+//
+//	err := Bind(r, &req, &BindOptions{EnableValidation: true})
+//
+// Tag errors are returned as FieldErrors, keyed by the field binding names,
+// so they can be written with ValidationProblem. Since the whole target is
+// validated, enable it only in the last binding function called for a target.
+//
 // # Slice and Multiple Value Handling
 //
 // Slices are populated from multiple parameter values or CSV-formatted single values:
@@ -73,6 +165,82 @@
 //
 // CSV parsing is controlled by BindOptions.
 //
+// BindOptions.MaxSliceLen and BindOptions.MaxValueLen protect services from
+// abusive requests, rejecting parameters with too many values or too long
+// ones before they are converted, with ErrTooManyValues and ErrValueTooLong
+// as BindError causes.
+//
+// # Request Bodies
+//
+// BindBody decodes the request body according to its Content-Type. JSON,
+// used when no Content-Type is sent, XML and protobuf are supported by
+// default. Other media types can be supported by registering a decoder:
+//
+//	RegisterBodyDecoder("application/yaml", func(body io.Reader, target interface{}, _ BindBodyOptions) error {
+//		return yaml.NewDecoder(body).Decode(target)
+//	})
+//
+// Requests with media types without a decoder fail with
+// ErrUnsupportedMediaType.
+//
+// Bodies with a gzip, deflate or br Content-Encoding are decompressed before
+// being decoded. BindBodyOptions.MaxBytes limits the body as received and
+// BindBodyOptions.MaxDecompressedBytes limits it once decompressed. Other
+// codings can be supported with RegisterContentDecoder, requests using
+// unknown ones fail with ErrUnsupportedContentEncoding.
+//
+// # Binding Whole Requests
+//
+// BindAll decodes the body directly into the target and then overwrites the
+// fields tagged with other locations with the request parameters, so a
+// single struct can describe the whole request:
+//
+//	type UpdateUserRequest struct {
+//		ID    string `json:"id" http:"loc=path"`
+//		Name  string `json:"name"`
+//		Email string `json:"email" http:"loc=body,required"`
+//	}
+//
+//	err := BindAll(r, &req)
+//
+// # Typed Handlers
+//
+// Handler creates an http.HandlerFunc from a typed function. The request is
+// bound into its first argument with BindAll and its result is written with
+// Success, or with ValidationProblem when it returns an error:
+//
+//	mux.Handle("PUT /users/{id}", Handler(func(ctx context.Context, req UpdateUserRequest) (*User, error) {
+//		return users.Update(ctx, req)
+//	}))
+//
+// Requests that cannot be bound are answered with 400 Bad Request, listing
+// the failed fields, without calling the function.
+//
+// # Map Fields
+//
+// Map fields are bound from the query parameters that use the field name as
+// prefix, either as "name[key]" or as "name.key". Keys and values are
+// converted like single parameters:
+//
+//	type SearchRequest struct {
+//		Labels map[string]string `json:"labels" http:"loc=query"` // ?labels[env]=prod&labels.team=core
+//		Range  map[string][]int  `json:"range" http:"loc=query"`  // ?range[age]=18&range[age]=30
+//	}
+//
+// Header maps receive every header starting with the field name, keyed by
+// the rest of the header name in canonical form, or in lower case with
+// BindOptions.LowercaseHeaderKeys:
+//
+//	type ProxyRequest struct {
+//		Vendor map[string]string `json:"x-vendor" http:"loc=header"` // X-Vendor-Trace-Id: abc
+//	}
+//
+// BindOptions.HeaderPrefix is prepended to the names of all header fields,
+// including maps, so a `user-id` field can be bound from X-App-User-Id.
+//
+// Maps are not bound from other locations, except from the body, where they
+// are decoded with the rest of the JSON object.
+//
 // # TextUnmarshaler Support
 //
 // Types implementing encoding.TextUnmarshaler can be bound directly:
@@ -94,4 +262,176 @@
 //	type UserRequest struct {
 //		Status Status `json:"status"`
 //	}
+//
+// # Time Layouts
+//
+// time.Time fields are parsed with BindOptions.DefaultTimeLayout, or with the
+// `time_format` tag option, which accepts a comma-separated list of layouts
+// tried in order. Besides time package layouts, it accepts the name of its
+// layout constants, e.g. "RFC1123", and "unix" or "unixmilli" for seconds or
+// milliseconds since the Unix epoch:
+//
+//	type EventsRequest struct {
+//		Since time.Time `json:"since" http:"loc=query,time_format=unix"`
+//		Day   time.Time `json:"day" http:"loc=query,time_format=DateOnly,02/01/2006,required"`
+//	}
+//
+// Since the options of the tag are also separated by commas, layouts
+// containing them must be referenced by name.
+//
+// # Custom Binders
+//
+// Types that cannot implement encoding.TextUnmarshaler, e.g. the ones from
+// other packages, can have their conversion registered once for the whole
+// application:
+//
+//	RegisterBinder(reflect.TypeOf(decimal.Decimal{}), func(s string) (interface{}, error) {
+//		return decimal.NewFromString(s)
+//	})
+//
+// Registered binders take precedence over every other conversion and are
+// also used for pointers and slices of the registered type.
+//
+// # Generated Binders
+//
+// Binding relies on reflection. Latency-sensitive services can remove it by
+// generating the binding code of their request structs with the
+// mikros-bindgen command, which makes them implement GeneratedBinder:
+//
+//	//go:generate go run github.com/mikros-dev/mikros/cmd/mikros-bindgen -file=$GOFILE
+//
+//	//mikros:bind
+//	type ListUsersRequest struct {
+//		Limit int      `json:"limit" http:"loc=query,default=10"`
+//		Tags  []string `json:"tags" http:"loc=query"`
+//	}
+//
+// Generated binders support path, query, header and cookie fields of basic
+// types, or slices of them, with the loc, required, default and default_env
+// tag options. They read the default_env variable on every binding, while
+// reflection resolves it once. Targets without one keep being bound through
+// reflection.
+//
+// # Locale Negotiation
+//
+// Fields of type Locale are filled by parsing an Accept-Language value, with
+// its quality values, and matching it against BindOptions.SupportedLocales:
+//
+//	type PageRequest struct {
+//		Language Locale `json:"accept-language" http:"loc=header"`
+//	}
+//
+//	opts := &BindOptions{
+//		SupportedLocales: []string{"en-US", "pt-BR"},
+//		DefaultLocale:    "en-US",
+//	}
+//
+// Handlers that don't bind structs can use NegotiateLocale directly.
+//
+// # File Uploads
+//
+// BindMultipart binds multipart/form-data requests. Text fields are converted
+// like query parameters and file parts are bound to *multipart.FileHeader or
+// UploadedFile fields:
+//
+//	type UploadRequest struct {
+//		Title  string         `json:"title"`
+//		Avatar UploadedFile   `json:"avatar"`
+//		Photos []UploadedFile `json:"photos"`
+//	}
+//
+//	opts := &BindOptions{
+//		MaxFileBytes:        5 << 20,
+//		AllowedContentTypes: []string{"image/*"},
+//	}
+//
+// Files larger than MaxFileBytes are not read past the limit. Rejected files
+// are reported as a *BindError of the "form" location, caused by
+// ErrFileTooLarge or ErrContentTypeNotAllowed.
+//
+// # Response Encoding
+//
+// Success encodes responses as JSON by default. When the request is passed
+// in SuccessOptions.Request, its Accept header chooses the encoder, following
+// the client quality values. JSON and XML are supported by default, and
+// other media types can be supported by registering an encoder:
+//
+//	RegisterResponseEncoder("application/msgpack", func(w io.Writer, data interface{}) error {
+//		return msgpack.NewEncoder(w).Encode(data)
+//	})
+//
+//	Success(ctx, w, user, SuccessOptions{Request: r})
+//
+// Requests accepting no registered media type are answered with JSON.
+//
+// # Conditional Requests
+//
+// Success sets the ETag and Last-Modified headers from SuccessOptions.ETag,
+// or a tag computed from the body with GenerateETag, and LastModified. When
+// the request is passed in SuccessOptions.Request, GET and HEAD requests
+// that already have the current response, according to their If-None-Match
+// or If-Modified-Since headers, are answered with 304 Not Modified:
+//
+//	Success(ctx, w, user, SuccessOptions{
+//		Request:      r,
+//		GenerateETag: true,
+//		LastModified: user.UpdatedAt,
+//	})
+//
+// # Pagination
+//
+// BindPagination binds the "page", "per_page", "cursor" and "sort" query
+// parameters of list requests, and SuccessPage answers them with the page
+// items wrapped with their pagination metadata and a Link header pointing
+// to the neighbouring pages:
+//
+//	p, err := BindPagination(r, PaginationOptions{SortFields: []string{"name", "created_at"}})
+//	if err != nil {
+//		ValidationProblem(ctx, w, err)
+//		return
+//	}
+//
+//	users, total := listUsers(p.Offset(), p.Limit(), p.Sort)
+//	SuccessPage(ctx, w, r, p, Page[User]{Items: users, Total: total})
+//
+// # Filters
+//
+// BindFilter binds operator-style query parameters, like
+// "?age[gte]=18&created[lt]=2024-01-01", into a Filter holding their
+// field/operator/value conditions. The eq, ne, gt, gte, lt, lte, in, nin and
+// like operators are supported, and FilterOptions restricts the fields and
+// operators that can be used:
+//
+//	f, err := BindFilter(r, FilterOptions{
+//		Fields: map[string][]FilterOperator{
+//			"age":    {FilterGte, FilterLte},
+//			"status": {FilterEq, FilterIn},
+//		},
+//	})
+//
+// # Streaming Responses
+//
+// Long results can be streamed as a JSON array with SuccessStream, or as
+// newline-delimited JSON with SuccessNDJSON. Server-Sent Events are written
+// with an EventStream, which can keep idle connections open with heartbeats
+// and resume reconnecting clients from their last event:
+//
+//	stream, err := NewEventStream(w, EventStreamOptions{Heartbeat: 15 * time.Second})
+//	if err != nil {
+//		Problem(ctx, w, err)
+//		return
+//	}
+//	defer stream.Close()
+//
+//	for n := range notificationsAfter(LastEventID(r)) {
+//		if err := stream.Send(Event{ID: n.ID, Event: "notification", Data: n}); err != nil {
+//			return
+//		}
+//	}
+//
+// Streams with the request context in EventStreamOptions.Context end when
+// the service starts stopping, after sending a "shutdown" event telling
+// clients to reconnect. Handlers waiting on other sources can select on
+// stream.Done(). Requests still running after the HTTP runtime
+// stream_grace_period have their contexts canceled.
 package http
@@ -0,0 +1,88 @@
+package http
+
+import (
+	"reflect"
+	"sync"
+)
+
+// cachedField is the binding metadata of a struct field, which only depends
+// on its type and is computed once.
+type cachedField struct {
+	index  int
+	sf     reflect.StructField
+	name   string
+	tag    *bindTag
+	nested bool
+}
+
+// cachedFields are the bindable fields of a struct type. When one of its
+// tags is invalid, fields has the ones declared before it and err the
+// failure, so walking the type behaves as if it was parsed every time.
+type cachedFields struct {
+	fields []cachedField
+	err    error
+}
+
+type fieldCacheKey struct {
+	t         reflect.Type
+	snakeCase bool
+}
+
+// fieldCache keeps the cachedFields of every struct type already bound, so
+// field names and tags are not parsed again on every request.
+var fieldCache sync.Map
+
+// typeFields gives back the bindable fields of the struct type rt.
+func typeFields(rt reflect.Type, snakeCase bool) *cachedFields {
+	key := fieldCacheKey{
+		t:         rt,
+		snakeCase: snakeCase,
+	}
+
+	if c, ok := fieldCache.Load(key); ok {
+		return c.(*cachedFields)
+	}
+
+	c, _ := fieldCache.LoadOrStore(key, newCachedFields(rt, snakeCase))
+	return c.(*cachedFields)
+}
+
+func newCachedFields(rt reflect.Type, snakeCase bool) *cachedFields {
+	c := &cachedFields{}
+
+	for i := 0; i < rt.NumField(); i++ {
+		sf := rt.Field(i)
+
+		// Exported fields of embedded unexported structs can still be set.
+		if !sf.IsExported() && (!sf.Anonymous || sf.Type.Kind() != reflect.Struct) {
+			continue // unexported
+		}
+
+		name, ok := resolveFieldName(sf, snakeCase)
+		if !ok {
+			continue // e.g. json:"-"
+		}
+
+		tag, err := parseBindTag(sf.Tag)
+		if err != nil {
+			c.err = err
+			return c
+		}
+
+		c.fields = append(c.fields, cachedField{
+			index:  i,
+			sf:     sf,
+			name:   name,
+			tag:    tag,
+			nested: isNestedStruct(sf.Type),
+		})
+	}
+
+	return c
+}
+
+// resetFieldCache drops all cached fields, which must be computed again when
+// something they depend on changes, like the registered binders.
+func resetFieldCache() {
+	fieldCache.Clear()
+}
@@ -0,0 +1,114 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTypeFields(t *testing.T) {
+	type filter struct {
+		Name string `json:"name"`
+	}
+
+	type request struct {
+		ID       string `json:"id" http:"loc=path"`
+		Filter   filter `json:"filter" http:"loc=query"`
+		Ignored  string `json:"-" http:"loc=query"`
+		internal string `http:"loc=query"`
+	}
+
+	t.Run("should compute the fields of a type once", func(t *testing.T) {
+		var (
+			rt = reflect.TypeOf(request{})
+			a  = typeFields(rt, false)
+			b  = typeFields(rt, false)
+		)
+
+		require.NoError(t, a.err)
+		assert.Same(t, a, b)
+		require.Len(t, a.fields, 2)
+		assert.Equal(t, "id", a.fields[0].name)
+		assert.Equal(t, "path", a.fields[0].tag.Location)
+		assert.True(t, a.fields[1].nested)
+		assert.NotSame(t, a, typeFields(rt, true))
+	})
+
+	t.Run("should not share inherited locations", func(t *testing.T) {
+		type outer struct {
+			Query  filter `json:"query" http:"loc=query"`
+			Header filter `json:"header" http:"loc=header"`
+		}
+
+		var (
+			r = httptest.NewRequest(http.MethodGet, "/?query.name=john", nil)
+			v outer
+		)
+
+		r.Header.Set("header.name", "doe")
+
+		for i := 0; i < 2; i++ {
+			require.NoError(t, Bind(r, &v))
+			assert.Equal(t, "john", v.Query.Name)
+			assert.Equal(t, "doe", v.Header.Name)
+			assert.Nil(t, typeFields(reflect.TypeOf(filter{}), false).fields[0].tag)
+		}
+	})
+
+	t.Run("should keep invalid tag errors", func(t *testing.T) {
+		type invalid struct {
+			Name string `json:"name" http:"loc=somewhere"`
+		}
+
+		var v invalid
+		for i := 0; i < 2; i++ {
+			assert.Error(t, Bind(httptest.NewRequest(http.MethodGet, "/", nil), &v))
+		}
+	})
+
+	t.Run("should be reset when binders are registered", func(t *testing.T) {
+		type cachedMoney struct {
+			Cents int64
+		}
+
+		type request struct {
+			Price cachedMoney `json:"price" http:"loc=query"`
+		}
+
+		assert.True(t, typeFields(reflect.TypeOf(request{}), false).fields[0].nested)
+
+		RegisterBinder(reflect.TypeOf(cachedMoney{}), func(_ string) (interface{}, error) {
+			return cachedMoney{Cents: 100}, nil
+		})
+
+		assert.False(t, typeFields(reflect.TypeOf(request{}), false).fields[0].nested)
+	})
+}
+
+func BenchmarkBindCachedFields(b *testing.B) {
+	type request struct {
+		ID     string   `json:"id" http:"loc=path"`
+		Limit  int      `json:"limit" http:"loc=query,default=10"`
+		Tags   []string `json:"tags" http:"loc=query"`
+		Token  string   `json:"token" http:"loc=header"`
+		Filter struct {
+			Name string `json:"name"`
+		} `json:"filter" http:"loc=query"`
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/users/42?limit=5&tags=a,b&filter.name=john", nil)
+	r.SetPathValue("id", "42")
+	r.Header.Set("token", "abc")
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var v request
+		if err := Bind(r, &v); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
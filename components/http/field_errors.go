@@ -0,0 +1,183 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// FieldErrors holds validation error messages keyed by field, so that the
+// same validation result can be rendered as JSON for API clients or next to
+// the inputs of an HTML form.
+type FieldErrors map[string][]string
+
+// FieldErrorsProvider is implemented by errors that can describe themselves
+// as FieldErrors, allowing custom validation errors to be used with
+// NewFieldErrors.
+type FieldErrorsProvider interface {
+	FieldErrors() FieldErrors
+}
+
+// NewFieldErrors converts a validation error into FieldErrors. It supports
+// validator.ValidationErrors, errors implementing FieldErrorsProvider,
+// CollectedErrors and FieldErrors itself, including when they are wrapped.
+// It returns false for other errors.
+//
+// Fields of validator.ValidationErrors are keyed by their namespace without
+// the root struct name, e.g. "Address.Street". Register a tag name function
+// in the validator to use JSON names instead.
+func NewFieldErrors(err error) (FieldErrors, bool) {
+	// Collected errors are merged, instead of having only one of their
+	// parts found below.
+	var collected *CollectedErrors
+	if errors.As(err, &collected) {
+		return collected.FieldErrors(), true
+	}
+
+	var fieldErrors FieldErrors
+	if errors.As(err, &fieldErrors) {
+		return fieldErrors, true
+	}
+
+	var provider FieldErrorsProvider
+	if errors.As(err, &provider) {
+		return provider.FieldErrors(), true
+	}
+
+	var validationErrors validator.ValidationErrors
+	if errors.As(err, &validationErrors) {
+		out := make(FieldErrors)
+		for _, fe := range validationErrors {
+			out.Add(fieldErrorName(fe), fieldErrorMessage(fe))
+		}
+
+		return out, true
+	}
+
+	return nil, false
+}
+
+// Add adds an error message to a field.
+func (f FieldErrors) Add(field, message string) {
+	f[field] = append(f[field], message)
+}
+
+// Error returns all messages, ordered by field.
+func (f FieldErrors) Error() string {
+	fields := make([]string, 0, len(f))
+	for field := range f {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	var parts []string
+	for _, field := range fields {
+		for _, msg := range f[field] {
+			parts = append(parts, field+" "+msg)
+		}
+	}
+
+	return strings.Join(parts, "; ")
+}
+
+// Form returns the first message of every field, which is usually what is
+// displayed next to a form input.
+func (f FieldErrors) Form() map[string]string {
+	out := make(map[string]string, len(f))
+	for field, messages := range f {
+		if len(messages) > 0 {
+			out[field] = messages[0]
+		}
+	}
+
+	return out
+}
+
+func fieldErrorName(fe validator.FieldError) string {
+	namespace := fe.Namespace()
+	if _, after, ok := strings.Cut(namespace, "."); ok {
+		return after
+	}
+
+	return namespace
+}
+
+func fieldErrorMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required", "required_if", "required_unless", "required_with", "required_without":
+		return "is required"
+	case "email":
+		return "must be a valid email address"
+	case "url", "uri", "http_url":
+		return "must be a valid URL"
+	case "uuid", "uuid4":
+		return "must be a valid UUID"
+	case "oneof":
+		return fmt.Sprintf("must be one of: %s", fe.Param())
+	case "len":
+		return fmt.Sprintf("must have length %s", fe.Param())
+	case "min", "gte":
+		return fmt.Sprintf("must be at least %s", fe.Param())
+	case "max", "lte":
+		return fmt.Sprintf("must be at most %s", fe.Param())
+	case "gt":
+		return fmt.Sprintf("must be greater than %s", fe.Param())
+	case "lt":
+		return fmt.Sprintf("must be less than %s", fe.Param())
+	default:
+		return fmt.Sprintf("is invalid (%s)", fe.Tag())
+	}
+}
+
+// ValidationProblem outputs an HTTP error response for a validation error.
+// When the error can be converted with NewFieldErrors, the response body is a
+// JSON object with the error message and the messages of every field:
+//
+//	{"error": "name is required", "fields": {"name": ["is required"]}}
+//
+// The status code defaults to 400 Bad Request. Other errors are handled by
+// Problem.
+func ValidationProblem(ctx context.Context, w http.ResponseWriter, err error, options ...ProblemOptions) {
+	var problemOpts ProblemOptions
+	if len(options) > 0 {
+		problemOpts = options[0]
+	}
+
+	fieldErrors, ok := NewFieldErrors(err)
+	if !ok {
+		Problem(ctx, w, err, problemOpts)
+		return
+	}
+
+	if problemOpts.HTTPStatusCode == 0 {
+		problemOpts.HTTPStatusCode = http.StatusBadRequest
+	}
+	if problemOpts.Output != nil {
+		problemOpts.Output(ctx, w, err, problemOpts.HTTPStatusCode)
+		return
+	}
+
+	body, mErr := json.Marshal(map[string]interface{}{
+		"error":  fieldErrors.Error(),
+		"fields": fieldErrors,
+	})
+	if mErr != nil {
+		Problem(ctx, w, err, problemOpts)
+		return
+	}
+
+	writeProblem(ctx, w, rawError(body), problemOpts)
+}
+
+// rawError lets an already encoded body go through writeProblem.
+type rawError []byte
+
+func (r rawError) Error() string {
+	return string(r)
+}
@@ -0,0 +1,83 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type signupForm struct {
+	Name    string `validate:"required"`
+	Email   string `validate:"required,email"`
+	Plan    string `validate:"oneof=free pro"`
+	Address struct {
+		Street string `validate:"required"`
+	}
+}
+
+func TestNewFieldErrors(t *testing.T) {
+	t.Run("should convert validator errors", func(t *testing.T) {
+		err := validator.New().Struct(signupForm{Email: "x", Plan: "gold"})
+		require.Error(t, err)
+
+		fieldErrors, ok := NewFieldErrors(fmt.Errorf("invalid form: %w", err))
+		require.True(t, ok)
+		assert.Equal(t, FieldErrors{
+			"Name":           {"is required"},
+			"Email":          {"must be a valid email address"},
+			"Plan":           {"must be one of: free pro"},
+			"Address.Street": {"is required"},
+		}, fieldErrors)
+		assert.Equal(t, "is required", fieldErrors.Form()["Address.Street"])
+	})
+
+	t.Run("should convert custom errors", func(t *testing.T) {
+		fe := FieldErrors{}
+		fe.Add("name", "is taken")
+
+		got, ok := NewFieldErrors(fmt.Errorf("wrapped: %w", fe))
+		require.True(t, ok)
+		assert.Equal(t, "name is taken", got.Error())
+	})
+
+	t.Run("should not convert other errors", func(t *testing.T) {
+		_, ok := NewFieldErrors(errors.New("boom"))
+		assert.False(t, ok)
+	})
+}
+
+func TestValidationProblem(t *testing.T) {
+	t.Run("should write field errors as JSON", func(t *testing.T) {
+		fe := FieldErrors{}
+		fe.Add("name", "is required")
+
+		rec := httptest.NewRecorder()
+		ValidationProblem(context.Background(), rec, fe)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+		var body struct {
+			Error  string              `json:"error"`
+			Fields map[string][]string `json:"fields"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		assert.Equal(t, "name is required", body.Error)
+		assert.Equal(t, []string{"is required"}, body.Fields["name"])
+	})
+
+	t.Run("should use Problem for other errors", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		ValidationProblem(context.Background(), rec, errors.New("boom"))
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		assert.Equal(t, "boom", rec.Body.String())
+	})
+}
@@ -0,0 +1,195 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// FilterOperator is the comparison applied by a filter condition.
+type FilterOperator string
+
+// Supported filter operators.
+const (
+	FilterEq    FilterOperator = "eq"
+	FilterNe    FilterOperator = "ne"
+	FilterGt    FilterOperator = "gt"
+	FilterGte   FilterOperator = "gte"
+	FilterLt    FilterOperator = "lt"
+	FilterLte   FilterOperator = "lte"
+	FilterIn    FilterOperator = "in"
+	FilterNotIn FilterOperator = "nin"
+	FilterLike  FilterOperator = "like"
+)
+
+var (
+	filterOperators = []FilterOperator{
+		FilterEq,
+		FilterNe,
+		FilterGt,
+		FilterGte,
+		FilterLt,
+		FilterLte,
+		FilterIn,
+		FilterNotIn,
+		FilterLike,
+	}
+)
+
+// FilterCondition is a field/operator/value triple of a filter, e.g.,
+// "age[gte]=18" is the condition {Field: "age", Operator: "gte", Values:
+// ["18"]}.
+type FilterCondition struct {
+	Field    string
+	Operator FilterOperator
+
+	// Values has a single value, except for the "in" and "nin" operators,
+	// whose comma-separated values are split.
+	Values []string
+}
+
+// Value returns the first value of the condition.
+func (c FilterCondition) Value() string {
+	if len(c.Values) == 0 {
+		return ""
+	}
+
+	return c.Values[0]
+}
+
+// Filter is the list of conditions requested by a list request, bound from
+// its operator-style query parameters, like "?age[gte]=18&name[like]=jo".
+type Filter struct {
+	Conditions []FilterCondition
+}
+
+// Get returns the conditions of a field.
+func (f *Filter) Get(field string) []FilterCondition {
+	var conditions []FilterCondition
+	for _, c := range f.Conditions {
+		if c.Field == field {
+			conditions = append(conditions, c)
+		}
+	}
+
+	return conditions
+}
+
+// Has reports whether the filter has conditions for a field.
+func (f *Filter) Has(field string) bool {
+	return slices.ContainsFunc(f.Conditions, func(c FilterCondition) bool {
+		return c.Field == field
+	})
+}
+
+// FilterOptions configures BindFilter.
+type FilterOptions struct {
+	// Fields are the fields that can be filtered, and the operators allowed
+	// for each of them. A field without operators accepts all of them. Any
+	// field is accepted when empty.
+	//
+	// Listed fields can also be filtered with plain parameters, like
+	// "status=active", which use the "eq" operator.
+	Fields map[string][]FilterOperator
+}
+
+// BindFilter binds the filter conditions of a list request, sent as
+// "field[operator]=value" query parameters. Unknown operators, fields and
+// operators not allowed by the options are reported as BindErrors.
+func BindFilter(r *http.Request, options ...FilterOptions) (*Filter, error) {
+	var opts FilterOptions
+	if len(options) > 0 {
+		opts = options[0]
+	}
+
+	var (
+		q      = r.URL.Query()
+		errs   BindErrors
+		filter = &Filter{}
+		keys   = make([]string, 0, len(q))
+	)
+
+	for k := range q {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	for _, k := range keys {
+		field, operator, ok := parseFilterParameter(k, opts)
+		if !ok {
+			continue
+		}
+
+		if err := validateFilterCondition(field, operator, opts); err != nil {
+			errs = append(errs, newBindError(k, "query", q[k], err))
+			continue
+		}
+
+		for _, v := range q[k] {
+			values := []string{v}
+			if operator == FilterIn || operator == FilterNotIn {
+				values = splitFilterValues(v)
+			}
+
+			filter.Conditions = append(filter.Conditions, FilterCondition{
+				Field:    field,
+				Operator: operator,
+				Values:   values,
+			})
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	return filter, nil
+}
+
+// parseFilterParameter splits a query parameter name into the field and the
+// operator of a condition. Parameters without an operator are conditions
+// only for fields listed in the options.
+func parseFilterParameter(parameter string, opts FilterOptions) (string, FilterOperator, bool) {
+	open := strings.Index(parameter, "[")
+	if open <= 0 || !strings.HasSuffix(parameter, "]") {
+		if _, ok := opts.Fields[parameter]; ok {
+			return parameter, FilterEq, true
+		}
+
+		return "", "", false
+	}
+
+	return parameter[:open], FilterOperator(strings.ToLower(parameter[open+1 : len(parameter)-1])), true
+}
+
+func validateFilterCondition(field string, operator FilterOperator, opts FilterOptions) error {
+	if !slices.Contains(filterOperators, operator) {
+		return fmt.Errorf("unknown filter operator '%s'", operator)
+	}
+
+	if len(opts.Fields) == 0 {
+		return nil
+	}
+
+	allowed, ok := opts.Fields[field]
+	if !ok {
+		return fmt.Errorf("field '%s' cannot be filtered", field)
+	}
+	if len(allowed) > 0 && !slices.Contains(allowed, operator) {
+		return fmt.Errorf("operator '%s' is not supported by field '%s'", operator, field)
+	}
+
+	return nil
+}
+
+func splitFilterValues(value string) []string {
+	var values []string
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+
+	return values
+}
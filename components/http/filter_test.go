@@ -0,0 +1,64 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBindFilter(t *testing.T) {
+	t.Run("should bind operator parameters", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/users?age[gte]=18&age[lt]=65&created[LT]=2024-01-01&page=2", nil)
+
+		f, err := BindFilter(r)
+		require.NoError(t, err)
+		assert.Equal(t, []FilterCondition{
+			{Field: "age", Operator: FilterGte, Values: []string{"18"}},
+			{Field: "age", Operator: FilterLt, Values: []string{"65"}},
+			{Field: "created", Operator: FilterLt, Values: []string{"2024-01-01"}},
+		}, f.Conditions)
+		assert.Len(t, f.Get("age"), 2)
+		assert.Equal(t, "2024-01-01", f.Get("created")[0].Value())
+		assert.False(t, f.Has("page"))
+	})
+
+	t.Run("should split the values of list operators", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/users?status[in]=active,%20pending&role[nin]=admin", nil)
+
+		f, err := BindFilter(r)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"active", "pending"}, f.Get("status")[0].Values)
+		assert.Equal(t, []string{"admin"}, f.Get("role")[0].Values)
+	})
+
+	t.Run("should bind plain parameters of listed fields", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/users?status=active&page=2", nil)
+
+		f, err := BindFilter(r, FilterOptions{Fields: map[string][]FilterOperator{"status": nil}})
+		require.NoError(t, err)
+		assert.Equal(t, []FilterCondition{{Field: "status", Operator: FilterEq, Values: []string{"active"}}}, f.Conditions)
+	})
+
+	t.Run("should report every invalid condition", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/users?age[between]=1&password[eq]=x&name[gt]=a&name[like]=jo", nil)
+
+		_, err := BindFilter(r, FilterOptions{
+			Fields: map[string][]FilterOperator{
+				"age":  nil,
+				"name": {FilterEq, FilterLike},
+			},
+		})
+		require.Error(t, err)
+
+		var errs BindErrors
+		require.True(t, errors.As(err, &errs))
+		assert.Len(t, errs, 3)
+		assert.Contains(t, errs.FieldErrors(), "age[between]")
+		assert.Contains(t, errs.FieldErrors(), "password[eq]")
+		assert.Contains(t, errs.FieldErrors(), "name[gt]")
+	})
+}
@@ -0,0 +1,249 @@
+package http
+
+import (
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// GeneratedBinder is implemented by binding targets with generated binding
+// code, usually created by the mikros-bindgen command. Bind, BindAll,
+// BindQuery, BindHeader, BindCookie and BindPath use it instead of
+// reflection, falling back to it for targets that don't implement it.
+type GeneratedBinder interface {
+	// BindParameters binds every request parameter of the target through
+	// the Parameters binding functions.
+	BindParameters(p *Parameters)
+}
+
+// Parameters gives generated binders access to the request parameters,
+// which are looked up, converted and checked against the BindOptions like
+// the reflection-based binding does.
+type Parameters struct {
+	r        *http.Request
+	opts     *BindOptions
+	location string
+	query    url.Values
+	errs     BindErrors
+}
+
+func newParameters(r *http.Request, opts *BindOptions, location string) *Parameters {
+	return &Parameters{
+		r:        r,
+		opts:     opts,
+		location: location,
+	}
+}
+
+// Name gives back the name of a field without json tag, which depends on
+// BindOptions.FallbackSnakeCase.
+func (p *Parameters) Name(snakeCase, lowerCase string) string {
+	if p.opts.FallbackSnakeCase {
+		return snakeCase
+	}
+
+	return lowerCase
+}
+
+// values gives back the values of a parameter, or its default ones when it
+// was not sent.
+func (p *Parameters) values(location, name string, rules ParameterRules) ([]string, bool) {
+	if p.location != "" && p.location != location {
+		return nil, false
+	}
+
+	if values := p.lookup(location, name); len(values) > 0 {
+		return values, true
+	}
+
+	if rules.Required {
+		p.errs = append(p.errs, newBindError(name, location, nil, ErrMissingParameter))
+		return nil, false
+	}
+	if rules.DefaultEnv != "" {
+		if v, ok := os.LookupEnv(rules.DefaultEnv); ok && v != "" {
+			return []string{v}, true
+		}
+	}
+	if rules.HasDefault {
+		return []string{rules.Default}, true
+	}
+
+	return nil, false
+}
+
+func (p *Parameters) lookup(location, name string) []string {
+	switch location {
+	case "query":
+		if p.query == nil {
+			p.query = p.r.URL.Query()
+		}
+
+		values, _ := valuesLookup(p.query, name)
+		return values
+	case "header":
+		return p.r.Header.Values(p.opts.HeaderPrefix + name)
+	case "cookie":
+		cookies := p.r.CookiesNamed(name)
+		values := make([]string, 0, len(cookies))
+		for _, c := range cookies {
+			values = append(values, c.Value)
+		}
+
+		return values
+	case "path":
+		if v, ok := p.opts.PathGetter(p.r, name); ok && v != "" {
+			return []string{v}
+		}
+	}
+
+	return nil
+}
+
+// ParameterRules are the `http` tag options of a generated binder field.
+type ParameterRules struct {
+	Required   bool
+	Default    string
+	HasDefault bool
+
+	// DefaultEnv names the environment variable holding the default value,
+	// which takes precedence over Default when it is set.
+	DefaultEnv string
+}
+
+// Parameter is the set of types generated binders convert without
+// reflection.
+type Parameter interface {
+	string | bool |
+		int | int8 | int16 | int32 | int64 |
+		uint | uint8 | uint16 | uint32 | uint64 |
+		float32 | float64 | time.Duration
+}
+
+// BindParameter binds a parameter into a field of a generated binder.
+// Conversion failures are reported like Bind does.
+func BindParameter[T Parameter](p *Parameters, field *T, location, name string, rules ParameterRules) {
+	values, ok := p.values(location, name, rules)
+	if !ok {
+		return
+	}
+
+	if err := checkValueLimits(values, p.opts); err != nil {
+		p.errs = append(p.errs, newBindError(name, location, nil, err))
+		return
+	}
+
+	v, err := parseParameter[T](values[0])
+	if err != nil {
+		p.errs = append(p.errs, newBindError(name, location, values, err))
+		return
+	}
+
+	*field = v
+}
+
+// BindParameterSlice binds all values of a parameter into a slice field of
+// a generated binder, splitting CSV values like Bind does.
+func BindParameterSlice[T Parameter](p *Parameters, field *[]T, location, name string, rules ParameterRules) {
+	values, ok := p.values(location, name, rules)
+	if !ok {
+		return
+	}
+
+	if err := checkValueLimits(values, p.opts); err != nil {
+		p.errs = append(p.errs, newBindError(name, location, nil, err))
+		return
+	}
+
+	if p.opts.SplitSingleCSV && len(values) == 1 && strings.ContainsRune(values[0], p.opts.CSVSeparator) {
+		values = stringsSplitAndTrimRune(values[0], p.opts.CSVSeparator)
+		if p.opts.MaxSliceLen > 0 && len(values) > p.opts.MaxSliceLen {
+			p.errs = append(p.errs, newBindError(name, location, nil, tooManyValuesError(p.opts)))
+			return
+		}
+	}
+
+	out := make([]T, 0, len(values))
+	for _, s := range values {
+		v, err := parseParameter[T](s)
+		if err != nil {
+			p.errs = append(p.errs, newBindError(name, location, values, err))
+			return
+		}
+
+		out = append(out, v)
+	}
+
+	*field = out
+}
+
+func parseParameter[T Parameter](s string) (T, error) {
+	var (
+		v   T
+		err error
+	)
+
+	switch out := any(&v).(type) {
+	case *string:
+		*out = s
+	case *bool:
+		*out, err = strconv.ParseBool(s)
+	case *int:
+		*out, err = parseInt[int](s, strconv.IntSize)
+	case *int8:
+		*out, err = parseInt[int8](s, 8)
+	case *int16:
+		*out, err = parseInt[int16](s, 16)
+	case *int32:
+		*out, err = parseInt[int32](s, 32)
+	case *int64:
+		*out, err = parseInt[int64](s, 64)
+	case *uint:
+		*out, err = parseUint[uint](s, strconv.IntSize)
+	case *uint8:
+		*out, err = parseUint[uint8](s, 8)
+	case *uint16:
+		*out, err = parseUint[uint16](s, 16)
+	case *uint32:
+		*out, err = parseUint[uint32](s, 32)
+	case *uint64:
+		*out, err = parseUint[uint64](s, 64)
+	case *float32:
+		var f float64
+		f, err = strconv.ParseFloat(s, 32)
+		*out = float32(f)
+	case *float64:
+		*out, err = strconv.ParseFloat(s, 64)
+	case *time.Duration:
+		*out, err = time.ParseDuration(s)
+	}
+
+	return v, err
+}
+
+func parseInt[T int | int8 | int16 | int32 | int64](s string, bits int) (T, error) {
+	i, err := strconv.ParseInt(s, 10, bits)
+	return T(i), err
+}
+
+func parseUint[T uint | uint8 | uint16 | uint32 | uint64](s string, bits int) (T, error) {
+	u, err := strconv.ParseUint(s, 10, bits)
+	return T(u), err
+}
+
+// bindGenerated binds target with its generated binder, if it has one. Only
+// the parameters of location are bound, or all of them when it is empty.
+func bindGenerated(r *http.Request, target interface{}, opts *BindOptions, location string) (BindErrors, bool) {
+	g, ok := target.(GeneratedBinder)
+	if !ok {
+		return nil, false
+	}
+
+	p := newParameters(r, opts, location)
+	g.BindParameters(p)
+
+	return p.errs, true
+}
@@ -0,0 +1,135 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// generatedRequest has the binder mikros-bindgen generates for it, plus a
+// field only bound through reflection, which must be left untouched.
+type generatedRequest struct {
+	ID        string        `json:"id" http:"loc=path,required"`
+	Limit     int           `json:"limit" http:"loc=query,default=10"`
+	Tags      []string      `json:"tags" http:"loc=query"`
+	Timeout   time.Duration `json:"timeout" http:"loc=header"`
+	Session   string        `http:"loc=cookie"`
+	Reflected string        `json:"reflected" http:"loc=query"`
+	Name      string        `json:"name"`
+}
+
+func (t *generatedRequest) BindParameters(p *Parameters) {
+	BindParameter(p, &t.ID, "path", "id", ParameterRules{Required: true})
+	BindParameter(p, &t.Limit, "query", "limit", ParameterRules{Default: "10", HasDefault: true})
+	BindParameterSlice(p, &t.Tags, "query", "tags", ParameterRules{})
+	BindParameter(p, &t.Timeout, "header", "timeout", ParameterRules{})
+	BindParameter(p, &t.Session, "cookie", p.Name("session", "session"), ParameterRules{})
+}
+
+type envDefaultRequest struct {
+	Size int `json:"size" http:"loc=query,default_env=GENERATED_PAGE_SIZE,default=10"`
+}
+
+func (t *envDefaultRequest) BindParameters(p *Parameters) {
+	BindParameter(p, &t.Size, "query", "size", ParameterRules{DefaultEnv: "GENERATED_PAGE_SIZE", Default: "10", HasDefault: true})
+}
+
+func TestGeneratedBinder(t *testing.T) {
+	t.Run("should bind with the generated binder", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/users/42?tags=a,b&reflected=x", nil)
+		r.SetPathValue("id", "42")
+		r.Header.Set("timeout", "2s")
+		r.AddCookie(&http.Cookie{Name: "session", Value: "s1"})
+
+		var v generatedRequest
+		require.NoError(t, Bind(r, &v))
+		assert.Equal(t, "42", v.ID)
+		assert.Equal(t, 10, v.Limit)
+		assert.Equal(t, []string{"a", "b"}, v.Tags)
+		assert.Equal(t, 2*time.Second, v.Timeout)
+		assert.Equal(t, "s1", v.Session)
+		assert.Empty(t, v.Reflected)
+	})
+
+	t.Run("should prefer the environment default", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+		var v envDefaultRequest
+		require.NoError(t, Bind(r, &v))
+		assert.Equal(t, 10, v.Size)
+
+		t.Setenv("GENERATED_PAGE_SIZE", "25")
+		require.NoError(t, Bind(r, &v))
+		assert.Equal(t, 25, v.Size)
+	})
+
+	t.Run("should split single values only with the separator", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/users/42?tags=%20a%20", nil)
+		r.SetPathValue("id", "42")
+
+		var (
+			generated generatedRequest
+			reflected struct {
+				Tags []string `json:"tags" http:"loc=query"`
+			}
+		)
+		require.NoError(t, Bind(r, &generated))
+		require.NoError(t, Bind(r, &reflected))
+		assert.Equal(t, []string{" a "}, generated.Tags)
+		assert.Equal(t, reflected.Tags, generated.Tags)
+	})
+
+	t.Run("should bind only the location of the binding function", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/users/42?limit=5", nil)
+		r.SetPathValue("id", "42")
+		r.Header.Set("timeout", "2s")
+
+		var v generatedRequest
+		require.NoError(t, BindQuery(r, &v))
+		assert.Equal(t, 5, v.Limit)
+		assert.Empty(t, v.ID)
+		assert.Zero(t, v.Timeout)
+	})
+
+	t.Run("should report missing and invalid parameters", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/users?limit=abc", nil)
+
+		var v generatedRequest
+		err := Bind(r, &v)
+
+		var errs BindErrors
+		require.True(t, errors.As(err, &errs))
+		require.Len(t, errs, 2)
+		assert.Equal(t, "id", errs[0].Field)
+		assert.True(t, errors.Is(errs[0], ErrMissingParameter))
+		assert.Equal(t, "limit", errs[1].Field)
+		assert.Equal(t, "query", errs[1].Location)
+	})
+
+	t.Run("should apply value limits", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/users/42?tags=a,b,c", nil)
+		r.SetPathValue("id", "42")
+
+		var v generatedRequest
+		err := Bind(r, &v, &BindOptions{SplitSingleCSV: true, CSVSeparator: ',', MaxSliceLen: 2})
+		assert.True(t, errors.Is(err, ErrTooManyValues))
+	})
+
+	t.Run("should decode the body with BindAll", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, "/users/42", strings.NewReader(`{"name":"john"}`))
+		r.Header.Set("Content-Type", "application/json")
+		r.SetPathValue("id", "42")
+
+		var v generatedRequest
+		require.NoError(t, BindAll(r, &v))
+		assert.Equal(t, "john", v.Name)
+		assert.Equal(t, "42", v.ID)
+		assert.Equal(t, 10, v.Limit)
+	})
+}
@@ -0,0 +1,112 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+)
+
+// HandlerOptions configures the handlers created by Handler.
+type HandlerOptions struct {
+	// Bind configures how requests are bound. Nil uses the options of the
+	// request context, set by the service runtime, or the default ones.
+	Bind *BindOptions
+
+	// Success is used to write the successful responses. Its Request is
+	// always set to the request being handled.
+	Success SuccessOptions
+
+	// Problem is used to write the error responses.
+	Problem ProblemOptions
+}
+
+// Handler creates an HTTP handler from a typed function. Requests are bound
+// into Req with BindAll, fn is called with them and its result is written
+// with Success, or with ValidationProblem when it fails:
+//
+//	type GetUserRequest struct {
+//		ID string `json:"id" http:"loc=path"`
+//	}
+//
+//	mux.Handle("GET /users/{id}", Handler(func(ctx context.Context, req GetUserRequest) (*User, error) {
+//		return users.Get(ctx, req.ID)
+//	}))
+//
+// Req may be a struct or a pointer to one. Requests that cannot be bound are
+// answered with 400 Bad Request, or 415 Unsupported Media Type for bodies
+// that cannot be decoded, without calling fn. A nil Resp is answered with
+// 204 No Content.
+func Handler[Req, Resp any](fn func(ctx context.Context, req Req) (Resp, error), options ...HandlerOptions) http.HandlerFunc {
+	var handlerOpts HandlerOptions
+	if len(options) > 0 {
+		handlerOpts = options[0]
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		req, target := newHandlerRequest[Req]()
+		if err := BindAll(r, target, handlerOpts.Bind); err != nil {
+			problemOpts := handlerOpts.Problem
+			if problemOpts.HTTPStatusCode == 0 {
+				problemOpts.HTTPStatusCode = bindErrorStatusCode(err)
+			}
+
+			ValidationProblem(ctx, w, err, problemOpts)
+			return
+		}
+
+		resp, err := fn(ctx, *req)
+		if err != nil {
+			ValidationProblem(ctx, w, err, handlerOpts.Problem)
+			return
+		}
+
+		successOpts := handlerOpts.Success
+		successOpts.Request = r
+		Success(ctx, w, handlerResponse(resp), successOpts)
+	}
+}
+
+// newHandlerRequest creates the request value of a handler and the target
+// where it is bound. Pointer types are allocated so they are never nil.
+func newHandlerRequest[Req any]() (*Req, interface{}) {
+	var (
+		req Req
+		rv  = reflect.ValueOf(&req).Elem()
+	)
+
+	if rv.Kind() == reflect.Ptr {
+		rv.Set(reflect.New(rv.Type().Elem()))
+		return &req, rv.Interface()
+	}
+
+	return &req, &req
+}
+
+// handlerResponse gives back the data written by a handler, which is nil
+// for nil pointers, maps, slices and interfaces.
+func handlerResponse(resp interface{}) interface{} {
+	rv := reflect.ValueOf(resp)
+	switch rv.Kind() {
+	case reflect.Invalid:
+		return nil
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Interface:
+		if rv.IsNil() {
+			return nil
+		}
+	}
+
+	return resp
+}
+
+// bindErrorStatusCode gives back the status code of a request that could not
+// be bound.
+func bindErrorStatusCode(err error) int {
+	if errors.Is(err, ErrUnsupportedMediaType) || errors.Is(err, ErrUnsupportedContentEncoding) {
+		return http.StatusUnsupportedMediaType
+	}
+
+	return http.StatusBadRequest
+}
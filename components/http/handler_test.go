@@ -0,0 +1,150 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	merrors "github.com/mikros-dev/mikros/internal/components/errors"
+)
+
+func TestHandler(t *testing.T) {
+	type request struct {
+		ID   string `json:"id" http:"loc=path"`
+		Name string `json:"name" http:"loc=body,required"`
+	}
+
+	type response struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	}
+
+	newRequest := func(body string) *http.Request {
+		r := httptest.NewRequest(http.MethodPut, "/users/42", strings.NewReader(body))
+		r.SetPathValue("id", "42")
+		r.Header.Set("Content-Type", "application/json")
+
+		return r
+	}
+
+	t.Run("should bind the request and write the response", func(t *testing.T) {
+		var (
+			rec = httptest.NewRecorder()
+			h   = Handler(func(_ context.Context, req request) (*response, error) {
+				return &response{ID: req.ID, Name: req.Name}, nil
+			})
+		)
+
+		h(rec, newRequest(`{"name":"john"}`))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"id":"42","name":"john"}`, rec.Body.String())
+	})
+
+	t.Run("should bind with the options of the request context", func(t *testing.T) {
+		var (
+			rec = httptest.NewRecorder()
+			h   = Handler(func(_ context.Context, req struct {
+				PageSize int `http:"loc=query"`
+			}) (*response, error) {
+				return &response{ID: strconv.Itoa(req.PageSize)}, nil
+			})
+			r = httptest.NewRequest(http.MethodGet, "/users?page_size=5", nil)
+		)
+
+		h(rec, r.WithContext(NewBindOptionsContext(r.Context(), &BindOptions{FallbackSnakeCase: true})))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"id":"5","name":""}`, rec.Body.String())
+	})
+
+	t.Run("should accept pointer requests", func(t *testing.T) {
+		var (
+			rec = httptest.NewRecorder()
+			h   = Handler(func(_ context.Context, req *request) (response, error) {
+				return response{ID: req.ID}, nil
+			})
+		)
+
+		h(rec, newRequest(`{"name":"john"}`))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"id":"42","name":""}`, rec.Body.String())
+	})
+
+	t.Run("should answer binding errors without calling the function", func(t *testing.T) {
+		var (
+			called bool
+			rec    = httptest.NewRecorder()
+			h      = Handler(func(_ context.Context, _ request) (*response, error) {
+				called = true
+				return nil, nil
+			})
+		)
+
+		h(rec, newRequest(`{}`))
+		assert.False(t, called)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+		var out map[string]interface{}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &out))
+		assert.Contains(t, out["fields"], "name")
+	})
+
+	t.Run("should answer unsupported media types", func(t *testing.T) {
+		var (
+			rec = httptest.NewRecorder()
+			r   = newRequest(`name=john`)
+			h   = Handler(func(_ context.Context, _ request) (*response, error) {
+				return nil, nil
+			})
+		)
+
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		h(rec, r)
+		assert.Equal(t, http.StatusUnsupportedMediaType, rec.Code)
+	})
+
+	t.Run("should write function errors", func(t *testing.T) {
+		var (
+			rec = httptest.NewRecorder()
+			h   = Handler(func(_ context.Context, _ request) (*response, error) {
+				return nil, merrors.NewBuilder(merrors.BuilderOptions{ServiceName: "users"}).NotFound()
+			})
+		)
+
+		h(rec, newRequest(`{"name":"john"}`))
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("should write nil responses as no content", func(t *testing.T) {
+		var (
+			rec = httptest.NewRecorder()
+			h   = Handler(func(_ context.Context, _ request) (*response, error) {
+				return nil, nil
+			})
+		)
+
+		h(rec, newRequest(`{"name":"john"}`))
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+	})
+
+	t.Run("should use the handler options", func(t *testing.T) {
+		var (
+			rec = httptest.NewRecorder()
+			h   = Handler(func(_ context.Context, _ request) (*response, error) {
+				return nil, errors.New("conflict")
+			}, HandlerOptions{
+				Problem: ProblemOptions{HTTPStatusCode: http.StatusConflict},
+			})
+		)
+
+		h(rec, newRequest(`{"name":"john"}`))
+		assert.Equal(t, http.StatusConflict, rec.Code)
+	})
+}
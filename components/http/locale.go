@@ -0,0 +1,173 @@
+package http
+
+import (
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	localeType = reflect.TypeOf(Locale{})
+)
+
+// Locale is a language tag negotiated from an Accept-Language header value.
+//
+// It can be used as a struct field type with the binding functions. When
+// bound, the header value is parsed considering its quality values and the
+// best entry is matched against BindOptions.SupportedLocales:
+//
+//	type Request struct {
+//		Language Locale `json:"accept-language" http:"loc=header"`
+//	}
+type Locale struct {
+	// Tag is the language tag, for example "en-US" or "pt".
+	Tag string
+
+	// Quality is the weight (q value) the client gave to the tag.
+	Quality float64
+}
+
+// String returns the locale tag.
+func (l Locale) String() string {
+	return l.Tag
+}
+
+// IsZero reports whether no locale has been negotiated.
+func (l Locale) IsZero() bool {
+	return l.Tag == ""
+}
+
+// ParseAcceptLanguage parses an Accept-Language header value into a list of
+// locales ordered by their quality values, from the most to the least
+// preferred. Entries with the same quality keep the order in which they were
+// sent. Entries with a quality of zero or that are malformed are ignored.
+func ParseAcceptLanguage(header string) []Locale {
+	var locales []Locale
+
+	for _, entry := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(entry), ";")
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			continue
+		}
+
+		quality, ok := parseLocaleQuality(params)
+		if !ok || quality <= 0 {
+			continue
+		}
+
+		locales = append(locales, Locale{
+			Tag:     tag,
+			Quality: quality,
+		})
+	}
+
+	sort.SliceStable(locales, func(i, j int) bool {
+		return locales[i].Quality > locales[j].Quality
+	})
+
+	return locales
+}
+
+func parseLocaleQuality(params string) (float64, bool) {
+	params = strings.TrimSpace(params)
+	if params == "" {
+		return 1, true
+	}
+
+	k, v, ok := strings.Cut(params, "=")
+	if !ok || strings.TrimSpace(k) != "q" {
+		return 0, false
+	}
+
+	q, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+	if err != nil || q > 1 {
+		return 0, false
+	}
+
+	return q, true
+}
+
+// MatchLocale picks, from a list of supported locales, the one that best
+// satisfies the accepted locales (usually returned by ParseAcceptLanguage).
+//
+// Accepted locales are checked in order and, for each one, an exact
+// (case-insensitive) match is tried first, followed by a match of its primary
+// language subtag (e.g. "pt-BR" matches a supported "pt" and vice versa). The
+// wildcard "*" matches the first supported locale. The returned Locale uses
+// the tag as written in the supported list.
+//
+// If supported is empty, the most preferred accepted locale is returned.
+func MatchLocale(accepted []Locale, supported []string) (Locale, bool) {
+	if len(accepted) == 0 {
+		return Locale{}, false
+	}
+	if len(supported) == 0 {
+		return accepted[0], true
+	}
+
+	for _, a := range accepted {
+		if tag, ok := matchSupportedLocale(a.Tag, supported); ok {
+			return Locale{
+				Tag:     tag,
+				Quality: a.Quality,
+			}, true
+		}
+	}
+
+	return Locale{}, false
+}
+
+func matchSupportedLocale(tag string, supported []string) (string, bool) {
+	if tag == "*" {
+		return supported[0], true
+	}
+
+	for _, s := range supported {
+		if strings.EqualFold(tag, s) {
+			return s, true
+		}
+	}
+
+	language := primaryLanguage(tag)
+	for _, s := range supported {
+		if strings.EqualFold(language, primaryLanguage(s)) {
+			return s, true
+		}
+	}
+
+	return "", false
+}
+
+func primaryLanguage(tag string) string {
+	language, _, _ := strings.Cut(tag, "-")
+	return language
+}
+
+// NegotiateLocale parses the request Accept-Language header and returns the
+// best supported locale for it. If no supported locale is acceptable, the
+// fallback is returned.
+func NegotiateLocale(r *http.Request, supported []string, fallback string) Locale {
+	accepted := ParseAcceptLanguage(strings.Join(r.Header.Values("Accept-Language"), ","))
+	if l, ok := MatchLocale(accepted, supported); ok {
+		return l
+	}
+
+	return Locale{
+		Tag: fallback,
+	}
+}
+
+func setScalarLocaleField(field reflect.Value, value string, opt *BindOptions) error {
+	l, ok := MatchLocale(ParseAcceptLanguage(value), opt.SupportedLocales)
+	if !ok {
+		l = Locale{
+			Tag: opt.DefaultLocale,
+		}
+	}
+
+	field.Set(reflect.ValueOf(l))
+	return nil
+}
@@ -0,0 +1,139 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAcceptLanguage(t *testing.T) {
+	t.Run("should order by quality keeping the original order on ties", func(t *testing.T) {
+		locales := ParseAcceptLanguage("fr;q=0.5, en-US, pt-BR;q=0.8, en;q=0.8")
+		assert.Equal(t, []Locale{
+			{Tag: "en-US", Quality: 1},
+			{Tag: "pt-BR", Quality: 0.8},
+			{Tag: "en", Quality: 0.8},
+			{Tag: "fr", Quality: 0.5},
+		}, locales)
+	})
+
+	t.Run("should ignore zero quality and malformed entries", func(t *testing.T) {
+		locales := ParseAcceptLanguage("de;q=0, es;q=abc, it;q=2, ,ja")
+		assert.Equal(t, []Locale{{Tag: "ja", Quality: 1}}, locales)
+	})
+
+	t.Run("should handle empty header", func(t *testing.T) {
+		assert.Empty(t, ParseAcceptLanguage(""))
+	})
+}
+
+func TestMatchLocale(t *testing.T) {
+	supported := []string{"en-US", "pt-BR"}
+
+	t.Run("should match exact tags case-insensitively", func(t *testing.T) {
+		l, ok := MatchLocale(ParseAcceptLanguage("PT-br,en-US;q=0.9"), supported)
+		require.True(t, ok)
+		assert.Equal(t, "pt-BR", l.Tag)
+	})
+
+	t.Run("should match by primary language", func(t *testing.T) {
+		l, ok := MatchLocale(ParseAcceptLanguage("fr,en;q=0.7"), supported)
+		require.True(t, ok)
+		assert.Equal(t, "en-US", l.Tag)
+		assert.Equal(t, 0.7, l.Quality)
+	})
+
+	t.Run("should match wildcard to the first supported locale", func(t *testing.T) {
+		l, ok := MatchLocale(ParseAcceptLanguage("fr,*;q=0.1"), supported)
+		require.True(t, ok)
+		assert.Equal(t, "en-US", l.Tag)
+	})
+
+	t.Run("should fail when nothing matches", func(t *testing.T) {
+		_, ok := MatchLocale(ParseAcceptLanguage("fr,de"), supported)
+		assert.False(t, ok)
+	})
+
+	t.Run("should use the preferred locale without supported list", func(t *testing.T) {
+		l, ok := MatchLocale(ParseAcceptLanguage("fr;q=0.4,de"), nil)
+		require.True(t, ok)
+		assert.Equal(t, "de", l.Tag)
+	})
+}
+
+func TestNegotiateLocale(t *testing.T) {
+	t.Run("should return fallback when nothing matches", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("Accept-Language", "fr")
+
+		l := NegotiateLocale(r, []string{"en-US"}, "en-US")
+		assert.Equal(t, "en-US", l.Tag)
+	})
+
+	t.Run("should consider all header values", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Add("Accept-Language", "fr")
+		r.Header.Add("Accept-Language", "pt;q=0.9")
+
+		l := NegotiateLocale(r, []string{"en-US", "pt-BR"}, "en-US")
+		assert.Equal(t, "pt-BR", l.Tag)
+	})
+}
+
+func TestBindLocale(t *testing.T) {
+	t.Run("should bind Locale fields from headers", func(t *testing.T) {
+		var (
+			r = httptest.NewRequest(http.MethodGet, "/", nil)
+			v = struct {
+				Language Locale `json:"accept-language"`
+			}{}
+			opts = &BindOptions{
+				SupportedLocales: []string{"en-US", "pt-BR"},
+				DefaultLocale:    "en-US",
+			}
+		)
+
+		r.Header.Set("Accept-Language", "pt;q=0.9, fr")
+
+		err := BindHeader(r, &v, opts)
+		require.NoError(t, err)
+		assert.Equal(t, "pt-BR", v.Language.String())
+	})
+
+	t.Run("should use the default locale when nothing matches", func(t *testing.T) {
+		var (
+			r = httptest.NewRequest(http.MethodGet, "/", nil)
+			v = struct {
+				Language Locale `json:"accept-language"`
+			}{}
+			opts = &BindOptions{
+				SupportedLocales: []string{"en-US"},
+				DefaultLocale:    "en-US",
+			}
+		)
+
+		r.Header.Set("Accept-Language", "fr")
+
+		err := BindHeader(r, &v, opts)
+		require.NoError(t, err)
+		assert.Equal(t, "en-US", v.Language.Tag)
+	})
+
+	t.Run("should use the client preferred locale by default", func(t *testing.T) {
+		var (
+			r = httptest.NewRequest(http.MethodGet, "/", nil)
+			v = struct {
+				Language Locale `json:"accept-language" http:"loc=header"`
+			}{}
+		)
+
+		r.Header.Set("Accept-Language", "fr")
+
+		err := Bind(r, &v)
+		require.NoError(t, err)
+		assert.Equal(t, "fr", v.Language.Tag)
+	})
+}
@@ -0,0 +1,166 @@
+package http
+
+import (
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// isMapField reports whether t is a map, or a pointer to one, whose entries
+// are bound from parameters sharing its name as prefix.
+func isMapField(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	return t.Kind() == reflect.Map
+}
+
+// mapValues gathers the parameters belonging to a map field called name,
+// which can be sent either as "name[key]=value" or as "name.key=value". The
+// values of a key sent with both forms are merged.
+func mapValues(values url.Values, name string) map[string][]string {
+	var (
+		entries = make(map[string][]string)
+		keys    = make([]string, 0, len(values))
+	)
+
+	for k := range values {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	for _, k := range keys {
+		key, ok := mapKey(k, name)
+		if !ok || len(values[k]) == 0 {
+			continue
+		}
+
+		entries[key] = append(entries[key], values[k]...)
+	}
+
+	return entries
+}
+
+// headerMapValues gathers the headers belonging to a map field whose prefix
+// is name, like "X-Vendor-Region" for "x-vendor". Headers are matched without
+// regard to case and keyed by the canonical form of the rest of their names,
+// or its lower-case form when lowercase is set.
+func headerMapValues(h http.Header, name string, lowercase bool) map[string][]string {
+	entries := make(map[string][]string)
+	if name == "" {
+		return entries
+	}
+
+	prefix := name
+	if !strings.HasSuffix(prefix, "-") {
+		prefix += "-"
+	}
+
+	for k, values := range h {
+		if len(k) <= len(prefix) || !strings.EqualFold(k[:len(prefix)], prefix) || len(values) == 0 {
+			continue
+		}
+
+		key := textproto.CanonicalMIMEHeaderKey(k[len(prefix):])
+		if lowercase {
+			key = strings.ToLower(key)
+		}
+
+		entries[key] = append(entries[key], values...)
+	}
+
+	return entries
+}
+
+func mapKey(parameter, name string) (string, bool) {
+	rest, ok := strings.CutPrefix(parameter, name)
+	if !ok {
+		return "", false
+	}
+
+	if key, ok := strings.CutPrefix(rest, "."); ok && key != "" {
+		return key, true
+	}
+
+	if strings.HasPrefix(rest, "[") && strings.HasSuffix(rest, "]") && len(rest) > 2 {
+		return rest[1 : len(rest)-1], true
+	}
+
+	return "", false
+}
+
+// setMapValues sets the entries into a map field, converting its keys and
+// values like single parameters. Slice values receive all values of a key.
+func setMapValues(
+	fv reflect.Value,
+	tag *bindTag,
+	name, location string,
+	entries map[string][]string,
+	opt *BindOptions,
+) error {
+	if fv.Kind() == reflect.Ptr {
+		if fv.IsNil() {
+			fv.Set(reflect.New(fv.Type().Elem()))
+		}
+
+		return setMapValues(fv.Elem(), tag, name, location, entries, opt)
+	}
+
+	if opt.MaxSliceLen > 0 && len(entries) > opt.MaxSliceLen {
+		return newBindError(name, location, nil, tooManyValuesError(opt))
+	}
+
+	if fv.IsNil() {
+		fv.Set(reflect.MakeMapWithSize(fv.Type(), len(entries)))
+	}
+
+	keys := make([]string, 0, len(entries))
+	for k := range entries {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	var errs BindErrors
+	for _, k := range keys {
+		var (
+			values = entries[k]
+			field  = name + "[" + k + "]"
+			kv     = reflect.New(fv.Type().Key()).Elem()
+			ev     = reflect.New(fv.Type().Elem()).Elem()
+		)
+
+		// Long keys are reported by the map name, so they are not echoed
+		// back.
+		if err := checkValueLimits([]string{k}, opt); err != nil {
+			errs = append(errs, newBindError(name, location, nil, err))
+			continue
+		}
+
+		if err := checkValueLimits(values, opt); err != nil {
+			errs = append(errs, newBindError(field, location, nil, err))
+			continue
+		}
+
+		if err := setScalarValue(kv, tag, k, opt); err != nil {
+			errs = append(errs, newBindError(field, location, []string{k}, err))
+			continue
+		}
+
+		if err := setFieldValues(ev, tag, values, opt); err != nil {
+			if isLimitError(err) {
+				values = nil
+			}
+
+			errs = append(errs, newBindError(field, location, values, err))
+			continue
+		}
+
+		fv.SetMapIndex(kv, ev)
+	}
+
+	return errs.err()
+}
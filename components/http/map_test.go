@@ -0,0 +1,128 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBindMap(t *testing.T) {
+	t.Run("should bind maps from bracket and dot parameters", func(t *testing.T) {
+		var (
+			r = httptest.NewRequest(http.MethodGet, "/?meta[env]=prod&meta.team=core&labels[tier]=a&labels[tier]=b&other=1", nil)
+			v = struct {
+				Meta   map[string]string   `json:"meta" http:"loc=query"`
+				Labels map[string][]string `json:"labels" http:"loc=query"`
+			}{}
+		)
+
+		err := Bind(r, &v)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"env": "prod", "team": "core"}, v.Meta)
+		assert.Equal(t, map[string][]string{"tier": {"a", "b"}}, v.Labels)
+	})
+
+	t.Run("should bind maps with BindQuery", func(t *testing.T) {
+		var (
+			r = httptest.NewRequest(http.MethodGet, "/?limits[cpu]=2&limits[memory]=512", nil)
+			v = struct {
+				Limits *map[string]int `json:"limits"`
+			}{}
+		)
+
+		err := BindQuery(r, &v)
+		require.NoError(t, err)
+		require.NotNil(t, v.Limits)
+		assert.Equal(t, map[string]int{"cpu": 2, "memory": 512}, *v.Limits)
+	})
+
+	t.Run("should bind maps inside nested structs", func(t *testing.T) {
+		var (
+			r = httptest.NewRequest(http.MethodGet, "/?filter.labels[app]=api", nil)
+			v = struct {
+				Filter struct {
+					Labels map[string]string `json:"labels"`
+				} `json:"filter" http:"loc=query"`
+			}{}
+		)
+
+		err := Bind(r, &v)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"app": "api"}, v.Filter.Labels)
+	})
+
+	t.Run("should leave maps without parameters untouched", func(t *testing.T) {
+		var (
+			r = httptest.NewRequest(http.MethodGet, "/?meta=1&meta[]=2&metadata[a]=3", nil)
+			v = struct {
+				Meta map[string]string `json:"meta" http:"loc=query"`
+			}{}
+		)
+
+		err := Bind(r, &v)
+		require.NoError(t, err)
+		assert.Nil(t, v.Meta)
+	})
+
+	t.Run("should report required maps without parameters", func(t *testing.T) {
+		var (
+			r = httptest.NewRequest(http.MethodGet, "/", nil)
+			v = struct {
+				Meta map[string]string `json:"meta" http:"loc=query,required"`
+			}{}
+		)
+
+		err := BindQuery(r, &v)
+		assert.ErrorIs(t, err, ErrMissingParameter)
+	})
+
+	t.Run("should report invalid map values", func(t *testing.T) {
+		var (
+			r = httptest.NewRequest(http.MethodGet, "/?limits[cpu]=abc&limits[memory]=512&limits[disk]=x", nil)
+			v = struct {
+				Limits map[string]int `json:"limits" http:"loc=query"`
+			}{}
+		)
+
+		err := Bind(r, &v)
+
+		var errs BindErrors
+		require.True(t, errors.As(err, &errs))
+		require.Len(t, errs, 2)
+		assert.Equal(t, "limits[cpu]", errs[0].Field)
+		assert.Equal(t, "limits[disk]", errs[1].Field)
+		assert.Equal(t, 512, v.Limits["memory"])
+	})
+
+	t.Run("should decode maps from the body", func(t *testing.T) {
+		var (
+			r = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"meta":{"env":"prod"}}`))
+			v = struct {
+				Meta map[string]string `json:"meta" http:"loc=body"`
+			}{}
+		)
+
+		err := Bind(r, &v)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"env": "prod"}, v.Meta)
+	})
+
+	t.Run("should skip maps when binding other locations", func(t *testing.T) {
+		var (
+			r = httptest.NewRequest(http.MethodGet, "/", nil)
+			v = struct {
+				Meta map[string]string `json:"meta"`
+			}{}
+		)
+		r.Header.Set("meta", "1")
+
+		err := BindHeader(r, &v)
+		require.NoError(t, err)
+		assert.Nil(t, v.Meta)
+	})
+}
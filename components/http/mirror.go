@@ -0,0 +1,247 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	logger_api "github.com/mikros-dev/mikros/apis/features/logger"
+	"github.com/mikros-dev/mikros/components/logger"
+)
+
+const (
+	defaultMirrorTimeout       = 5 * time.Second
+	defaultMirrorMaxConcurrent = 64
+
+	// MirrorHeader is added to every mirrored request, so the secondary
+	// implementation can recognize shadow traffic.
+	MirrorHeader = "X-Shadow-Request"
+)
+
+// mirrorCredentialHeaders are removed from mirrored requests unless listed
+// by MirrorOptions.ForwardHeaders.
+var mirrorCredentialHeaders = []string{
+	"Authorization",
+	"Cookie",
+	"Proxy-Authorization",
+}
+
+// MirrorOptions configures the Mirror middleware.
+type MirrorOptions struct {
+	// Handler is a secondary handler that receives the mirrored requests.
+	Handler http.Handler
+
+	// URL is the base URL of a remote upstream that receives the mirrored
+	// requests. The original request URI is appended to it. Ignored when
+	// Handler is set.
+	URL string
+
+	// Client is the HTTP client used to send requests to URL. Defaults to
+	// a client with a 5s timeout.
+	Client *http.Client
+
+	// Timeout limits how long a mirrored request, sent either to Handler or
+	// to URL, can take. Defaults to 5s.
+	Timeout time.Duration
+
+	// MaxConcurrent is the maximum number of mirrored requests running at
+	// the same time. Requests arriving while this limit is reached are not
+	// mirrored. Defaults to 64.
+	MaxConcurrent int
+
+	// Percentage is the share of requests, from 0 to 100, that is mirrored.
+	Percentage float64
+
+	// MaxBodyBytes is the maximum request body size that is copied to be
+	// mirrored. Requests with larger bodies are not mirrored. Zero value
+	// uses the internal default (4MB).
+	MaxBodyBytes int64
+
+	// ForwardHeaders lists the credential headers (Authorization, Cookie and
+	// Proxy-Authorization) that are kept in mirrored requests. They are
+	// removed by default, so credentials don't leak to the secondary
+	// implementation.
+	ForwardHeaders []string
+
+	// Logger is used for logging mirroring failures. If nil, errors will be
+	// logged using the standard log package.
+	Logger logger_api.API
+}
+
+// Mirror returns a middleware that sends a copy of a percentage of requests
+// to a secondary handler or remote URL. Mirrored requests are fire-and-forget:
+// they run in the background, limited by MirrorOptions.MaxConcurrent and
+// MirrorOptions.Timeout, their responses are discarded and their failures
+// never affect the original request.
+func Mirror(options MirrorOptions) func(http.Handler) http.Handler {
+	m := &mirror{
+		options: options,
+		random:  rand.Float64,
+	}
+	if m.options.Client == nil {
+		m.options.Client = &http.Client{Timeout: defaultMirrorTimeout}
+	}
+	if m.options.MaxBodyBytes <= 0 {
+		m.options.MaxBodyBytes = defaultBindBodyMaxBytes
+	}
+	if m.options.Timeout <= 0 {
+		m.options.Timeout = defaultMirrorTimeout
+	}
+	if m.options.MaxConcurrent <= 0 {
+		m.options.MaxConcurrent = defaultMirrorMaxConcurrent
+	}
+	m.running = make(chan struct{}, m.options.MaxConcurrent)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if m.shouldMirror() {
+				m.mirror(r)
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+type mirror struct {
+	options MirrorOptions
+	random  func() float64
+	running chan struct{}
+}
+
+func (m *mirror) shouldMirror() bool {
+	if m.options.Handler == nil && m.options.URL == "" {
+		return false
+	}
+	if m.options.Percentage <= 0 {
+		return false
+	}
+
+	return m.random()*100 < m.options.Percentage
+}
+
+func (m *mirror) mirror(r *http.Request) {
+	// Mirrors are dropped, instead of queued, when too many are running.
+	select {
+	case m.running <- struct{}{}:
+	default:
+		return
+	}
+
+	body, ok := m.copyBody(r)
+	if !ok {
+		<-m.running
+		return
+	}
+
+	// The mirrored request must outlive the original one.
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(r.Context()), m.options.Timeout)
+	clone := r.Clone(ctx)
+	clone.Body = io.NopCloser(bytes.NewReader(body))
+	clone.Header.Set(MirrorHeader, "true")
+	m.stripCredentials(clone.Header)
+
+	go func() {
+		defer func() {
+			cancel()
+			<-m.running
+		}()
+		defer func() {
+			if p := recover(); p != nil {
+				m.logError(clone.Context(), fmt.Errorf("mirror panic: %v", p))
+			}
+		}()
+
+		if m.options.Handler != nil {
+			m.options.Handler.ServeHTTP(&discardResponseWriter{header: http.Header{}}, clone)
+			return
+		}
+
+		if err := m.send(clone, body); err != nil {
+			m.logError(clone.Context(), err)
+		}
+	}()
+}
+
+func (m *mirror) stripCredentials(header http.Header) {
+	for _, name := range mirrorCredentialHeaders {
+		if !slices.ContainsFunc(m.options.ForwardHeaders, func(h string) bool {
+			return strings.EqualFold(h, name)
+		}) {
+			header.Del(name)
+		}
+	}
+}
+
+// copyBody reads the request body so it can be replayed, restoring it into
+// the original request.
+func (m *mirror) copyBody(r *http.Request) ([]byte, bool) {
+	if r.Body == nil || r.Body == http.NoBody {
+		return nil, true
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, m.options.MaxBodyBytes+1))
+	r.Body = struct {
+		io.Reader
+		io.Closer
+	}{
+		Reader: io.MultiReader(bytes.NewReader(body), r.Body),
+		Closer: r.Body,
+	}
+	if err != nil || int64(len(body)) > m.options.MaxBodyBytes {
+		return nil, false
+	}
+
+	return body, true
+}
+
+func (m *mirror) send(r *http.Request, body []byte) error {
+	url := strings.TrimRight(m.options.URL, "/") + r.URL.RequestURI()
+
+	req, err := http.NewRequestWithContext(r.Context(), r.Method, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header = r.Header
+
+	res, err := m.options.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+
+	_, err = io.Copy(io.Discard, res.Body)
+	return err
+}
+
+func (m *mirror) logError(ctx context.Context, err error) {
+	if m.options.Logger != nil {
+		m.options.Logger.Error(ctx, "failed to mirror request", logger.Error(err))
+		return
+	}
+
+	log.Printf("failed to mirror request: %v\n", err)
+}
+
+type discardResponseWriter struct {
+	header http.Header
+}
+
+func (d *discardResponseWriter) Header() http.Header {
+	return d.header
+}
+
+func (d *discardResponseWriter) Write(b []byte) (int, error) {
+	return len(b), nil
+}
+
+func (d *discardResponseWriter) WriteHeader(_ int) {}
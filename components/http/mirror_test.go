@@ -0,0 +1,199 @@
+package http
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mirroredRequest struct {
+	path   string
+	body   string
+	header string
+}
+
+func TestMirror(t *testing.T) {
+	primary := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		_, _ = w.Write(b)
+	})
+
+	receive := func(t *testing.T, ch <-chan mirroredRequest) mirroredRequest {
+		select {
+		case m := <-ch:
+			return m
+		case <-time.After(time.Second):
+			require.FailNow(t, "request was not mirrored")
+		}
+
+		return mirroredRequest{}
+	}
+
+	t.Run("should mirror requests to a secondary handler", func(t *testing.T) {
+		var (
+			ch        = make(chan mirroredRequest, 1)
+			secondary = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				b, _ := io.ReadAll(r.Body)
+				ch <- mirroredRequest{path: r.URL.Path, body: string(b), header: r.Header.Get(MirrorHeader)}
+				w.WriteHeader(http.StatusInternalServerError)
+			})
+			rec = httptest.NewRecorder()
+			r   = httptest.NewRequest(http.MethodPost, "/items", strings.NewReader("payload"))
+		)
+
+		Mirror(MirrorOptions{Handler: secondary, Percentage: 100})(primary).ServeHTTP(rec, r)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "payload", rec.Body.String())
+		assert.Equal(t, mirroredRequest{path: "/items", body: "payload", header: "true"}, receive(t, ch))
+	})
+
+	t.Run("should mirror requests to a remote URL", func(t *testing.T) {
+		var (
+			ch       = make(chan mirroredRequest, 1)
+			upstream = httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+				b, _ := io.ReadAll(r.Body)
+				ch <- mirroredRequest{path: r.URL.RequestURI(), body: string(b), header: r.Header.Get(MirrorHeader)}
+			}))
+			rec = httptest.NewRecorder()
+			r   = httptest.NewRequest(http.MethodPost, "/items?id=1", strings.NewReader("payload"))
+		)
+		defer upstream.Close()
+
+		Mirror(MirrorOptions{URL: upstream.URL + "/", Percentage: 100})(primary).ServeHTTP(rec, r)
+
+		assert.Equal(t, "payload", rec.Body.String())
+		assert.Equal(t, mirroredRequest{path: "/items?id=1", body: "payload", header: "true"}, receive(t, ch))
+	})
+
+	t.Run("should strip credential headers unless allowed", func(t *testing.T) {
+		var (
+			ch       = make(chan http.Header, 1)
+			upstream = httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+				ch <- r.Header
+			}))
+		)
+		defer upstream.Close()
+
+		newRequest := func() *http.Request {
+			r := httptest.NewRequest(http.MethodGet, "/items", nil)
+			r.Header.Set("Authorization", "Bearer token")
+			r.Header.Set("Cookie", "session=1")
+			r.Header.Set("Proxy-Authorization", "Basic abc")
+			r.Header.Set("X-Request-Id", "42")
+			return r
+		}
+
+		r := newRequest()
+		Mirror(MirrorOptions{URL: upstream.URL, Percentage: 100})(primary).ServeHTTP(httptest.NewRecorder(), r)
+
+		var header http.Header
+		select {
+		case header = <-ch:
+		case <-time.After(time.Second):
+			require.FailNow(t, "request was not mirrored")
+		}
+		assert.Empty(t, header.Get("Authorization"))
+		assert.Empty(t, header.Get("Cookie"))
+		assert.Empty(t, header.Get("Proxy-Authorization"))
+		assert.Equal(t, "42", header.Get("X-Request-Id"))
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+
+		Mirror(MirrorOptions{
+			URL:            upstream.URL,
+			Percentage:     100,
+			ForwardHeaders: []string{"authorization"},
+		})(primary).ServeHTTP(httptest.NewRecorder(), newRequest())
+
+		select {
+		case header = <-ch:
+		case <-time.After(time.Second):
+			require.FailNow(t, "request was not mirrored")
+		}
+		assert.Equal(t, "Bearer token", header.Get("Authorization"))
+		assert.Empty(t, header.Get("Cookie"))
+	})
+
+	t.Run("should not mirror requests with large bodies", func(t *testing.T) {
+		var (
+			called    = make(chan struct{}, 1)
+			secondary = http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {
+				called <- struct{}{}
+			})
+			rec = httptest.NewRecorder()
+			r   = httptest.NewRequest(http.MethodPost, "/", strings.NewReader("large payload"))
+		)
+
+		Mirror(MirrorOptions{Handler: secondary, Percentage: 100, MaxBodyBytes: 4})(primary).ServeHTTP(rec, r)
+
+		assert.Equal(t, "large payload", rec.Body.String())
+		select {
+		case <-called:
+			assert.Fail(t, "request should not be mirrored")
+		case <-time.After(50 * time.Millisecond):
+		}
+	})
+
+	t.Run("should drop mirrors when too many are running", func(t *testing.T) {
+		var (
+			started   = make(chan struct{}, 2)
+			release   = make(chan struct{})
+			secondary = http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {
+				started <- struct{}{}
+				<-release
+			})
+			h = Mirror(MirrorOptions{Handler: secondary, Percentage: 100, MaxConcurrent: 1})(primary)
+		)
+		defer close(release)
+
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+		<-started
+
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", strings.NewReader("payload")))
+		assert.Equal(t, "payload", rec.Body.String())
+		select {
+		case <-started:
+			assert.Fail(t, "request should not be mirrored")
+		case <-time.After(50 * time.Millisecond):
+		}
+	})
+
+	t.Run("should cancel mirrors that time out", func(t *testing.T) {
+		var (
+			ch        = make(chan error, 1)
+			secondary = http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+				<-r.Context().Done()
+				ch <- r.Context().Err()
+			})
+		)
+
+		Mirror(MirrorOptions{Handler: secondary, Percentage: 100, Timeout: 10 * time.Millisecond})(primary).
+			ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+		select {
+		case err := <-ch:
+			assert.ErrorIs(t, err, context.DeadlineExceeded)
+		case <-time.After(time.Second):
+			require.FailNow(t, "mirror was not canceled")
+		}
+	})
+
+	t.Run("should respect the percentage", func(t *testing.T) {
+		m := &mirror{
+			options: MirrorOptions{Handler: primary, Percentage: 10},
+			random:  func() float64 { return 0.5 },
+		}
+		assert.False(t, m.shouldMirror())
+
+		m.random = func() float64 { return 0.05 }
+		assert.True(t, m.shouldMirror())
+	})
+}
@@ -0,0 +1,288 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+)
+
+const (
+	defaultMultipartMaxMemory int64 = 32 << 20 // 32MB as default
+)
+
+var (
+	fileHeaderType   = reflect.TypeOf((*multipart.FileHeader)(nil))
+	uploadedFileType = reflect.TypeOf(UploadedFile{})
+)
+
+// UploadedFile is a file received in a multipart/form-data request. It can
+// be used as a struct field type with BindMultipart, as well as
+// *multipart.FileHeader.
+type UploadedFile struct {
+	// Filename is the file name sent by the client.
+	Filename string
+
+	// ContentType is the media type of the file part, without parameters.
+	ContentType string
+
+	// Size is the file size in bytes.
+	Size int64
+
+	// Header gives access to the underlying multipart file header.
+	Header *multipart.FileHeader
+}
+
+// Open opens the file content.
+func (f *UploadedFile) Open() (multipart.File, error) {
+	if f.Header == nil {
+		return nil, errors.New("uploaded file has no content")
+	}
+
+	return f.Header.Open()
+}
+
+// BindMultipart parses a multipart/form-data request and binds its text
+// fields and file parts to a struct. Text fields use the same type
+// conversion of BindQuery, while file parts are bound to fields of the types
+// *multipart.FileHeader, UploadedFile, pointers to UploadedFile or slices of
+// them, when several files are sent with the same name.
+//
+// Files are checked against BindOptions.MaxFileBytes and
+// BindOptions.AllowedContentTypes.
+func BindMultipart(r *http.Request, target interface{}, opts ...*BindOptions) error {
+	o := getBindOptions(r, opts...)
+	if o.MultipartMaxMemory <= 0 {
+		o.MultipartMaxMemory = defaultMultipartMaxMemory
+	}
+
+	if r.MultipartForm == nil {
+		if err := parseMultipartForm(r, &o); err != nil {
+			return err
+		}
+	}
+
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return errors.New("target must be a pointer to a struct")
+	}
+
+	var (
+		form = r.MultipartForm
+		errs BindErrors
+	)
+
+	err := walkFields(v.Elem(), &o, "", "", nil, func(f *boundField) error {
+		if isFileField(f.sf.Type) {
+			files := form.File[f.name]
+			if len(files) == 0 {
+				if _, err := f.tag.missing("form", f.name); err != nil {
+					errs.collect(err)
+				}
+
+				return nil
+			}
+
+			if err := setFileField(f.fv, f.name, files, &o); err != nil {
+				errs.collect(err)
+			}
+
+			return nil
+		}
+
+		values, ok := form.Value[f.name]
+		if !ok || len(values) == 0 {
+			var err error
+			values, err = f.tag.missing("form", f.name)
+			if err != nil {
+				errs.collect(err)
+				return nil
+			}
+			if len(values) == 0 {
+				return nil
+			}
+		}
+
+		if err := setBoundValues(f.fv, f.tag, f.name, "form", values, &o); err != nil {
+			errs.collect(err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return finishBind(r, target, &o, nil, errs)
+}
+
+// parseMultipartForm parses the multipart form of r like
+// http.Request.ParseMultipartForm. With BindOptions.MaxFileBytes, file parts
+// are not read past the limit, so larger files are neither kept in memory
+// nor spooled to disk before being rejected.
+func parseMultipartForm(r *http.Request, opt *BindOptions) error {
+	if opt.MaxFileBytes <= 0 {
+		return r.ParseMultipartForm(opt.MultipartMaxMemory)
+	}
+
+	mr, err := r.MultipartReader()
+	if err != nil {
+		return err
+	}
+
+	var (
+		pr, pw = io.Pipe()
+		mw     = multipart.NewWriter(pw)
+		done   = make(chan struct{})
+	)
+
+	go func() {
+		defer close(done)
+		_ = pw.CloseWithError(copyLimitedParts(mw, mr, opt.MaxFileBytes))
+	}()
+
+	form, err := multipart.NewReader(pr, mw.Boundary()).ReadForm(opt.MultipartMaxMemory)
+	_ = pr.Close()
+	<-done
+	if err != nil {
+		return err
+	}
+
+	if err := r.ParseForm(); err != nil {
+		return err
+	}
+	if r.PostForm == nil {
+		r.PostForm = make(url.Values)
+	}
+	for k, v := range form.Value {
+		r.Form[k] = append(r.Form[k], v...)
+		r.PostForm[k] = append(r.PostForm[k], v...)
+	}
+
+	r.MultipartForm = form
+	return nil
+}
+
+// copyLimitedParts copies the parts of mr into mw, keeping at most one byte
+// more than maxFileBytes of each file, which is enough for them to be
+// rejected by their size. The rest of a part is discarded while moving to
+// the next one.
+func copyLimitedParts(mw *multipart.Writer, mr *multipart.Reader, maxFileBytes int64) error {
+	for {
+		part, err := mr.NextPart()
+		if errors.Is(err, io.EOF) {
+			return mw.Close()
+		}
+		if err != nil {
+			return err
+		}
+
+		w, err := mw.CreatePart(part.Header)
+		if err != nil {
+			return err
+		}
+
+		var content io.Reader = part
+		if part.FileName() != "" {
+			content = io.LimitReader(part, maxFileBytes+1)
+		}
+
+		if _, err := io.Copy(w, content); err != nil {
+			return err
+		}
+	}
+}
+
+func isFileField(t reflect.Type) bool {
+	if t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	if t == fileHeaderType {
+		return true
+	}
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	return t == uploadedFileType
+}
+
+func setFileField(field reflect.Value, name string, files []*multipart.FileHeader, opt *BindOptions) error {
+	for _, fh := range files {
+		if err := checkUploadedFile(fh, opt); err != nil {
+			return newBindError(name, "form", nil, err)
+		}
+	}
+
+	if field.Kind() != reflect.Slice {
+		field.Set(fileValue(field.Type(), files[0]))
+		return nil
+	}
+
+	out := reflect.MakeSlice(field.Type(), 0, len(files))
+	for _, fh := range files {
+		out = reflect.Append(out, fileValue(field.Type().Elem(), fh))
+	}
+
+	field.Set(out)
+	return nil
+}
+
+func fileValue(t reflect.Type, fh *multipart.FileHeader) reflect.Value {
+	if t == fileHeaderType {
+		return reflect.ValueOf(fh)
+	}
+
+	f := UploadedFile{
+		Filename:    fh.Filename,
+		ContentType: fileContentType(fh),
+		Size:        fh.Size,
+		Header:      fh,
+	}
+	if t.Kind() == reflect.Ptr {
+		return reflect.ValueOf(&f)
+	}
+
+	return reflect.ValueOf(f)
+}
+
+func fileContentType(fh *multipart.FileHeader) string {
+	mediaType, _, err := mime.ParseMediaType(fh.Header.Get("Content-Type"))
+	if err != nil || mediaType == "" {
+		return "application/octet-stream"
+	}
+
+	return mediaType
+}
+
+func checkUploadedFile(fh *multipart.FileHeader, opt *BindOptions) error {
+	if opt.MaxFileBytes > 0 && fh.Size > opt.MaxFileBytes {
+		return fmt.Errorf("%w: exceeds %d bytes", ErrFileTooLarge, opt.MaxFileBytes)
+	}
+
+	if len(opt.AllowedContentTypes) == 0 {
+		return nil
+	}
+
+	contentType := fileContentType(fh)
+	for _, allowed := range opt.AllowedContentTypes {
+		if matchContentType(allowed, contentType) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: %s", ErrContentTypeNotAllowed, contentType)
+}
+
+func matchContentType(pattern, contentType string) bool {
+	if prefix, ok := strings.CutSuffix(pattern, "/*"); ok {
+		return strings.HasPrefix(contentType, prefix+"/")
+	}
+
+	return strings.EqualFold(pattern, contentType)
+}
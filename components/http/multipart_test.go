@@ -0,0 +1,214 @@
+package http
+
+import (
+	"bytes"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type multipartFile struct {
+	field       string
+	filename    string
+	contentType string
+	content     string
+}
+
+func newMultipartRequest(t *testing.T, values map[string]string, files ...multipartFile) *http.Request {
+	var (
+		body bytes.Buffer
+		w    = multipart.NewWriter(&body)
+	)
+
+	for k, v := range values {
+		require.NoError(t, w.WriteField(k, v))
+	}
+
+	for _, f := range files {
+		h := make(textproto.MIMEHeader)
+		h.Set("Content-Disposition", `form-data; name="`+f.field+`"; filename="`+f.filename+`"`)
+		h.Set("Content-Type", f.contentType)
+
+		part, err := w.CreatePart(h)
+		require.NoError(t, err)
+		_, err = part.Write([]byte(f.content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+
+	req := httptest.NewRequest(http.MethodPost, "/upload", &body)
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	return req
+}
+
+func TestBindMultipart(t *testing.T) {
+	type uploadRequest struct {
+		Title  string                `json:"title"`
+		Count  int                   `json:"count"`
+		Avatar UploadedFile          `json:"avatar"`
+		Cover  *UploadedFile         `json:"cover"`
+		Raw    *multipart.FileHeader `json:"raw"`
+		Photos []UploadedFile        `json:"photos"`
+	}
+
+	t.Run("should bind text fields and files", func(t *testing.T) {
+		req := newMultipartRequest(t,
+			map[string]string{"title": "Holidays", "count": "2"},
+			multipartFile{"avatar", "me.png", "image/png", "png-data"},
+			multipartFile{"cover", "cover.jpg", "image/jpeg", "jpg"},
+			multipartFile{"raw", "raw.bin", "application/octet-stream", "raw"},
+			multipartFile{"photos", "a.png", "image/png", "a"},
+			multipartFile{"photos", "b.png", "image/png", "b"},
+		)
+
+		var target uploadRequest
+		require.NoError(t, BindMultipart(req, &target))
+
+		assert.Equal(t, "Holidays", target.Title)
+		assert.Equal(t, 2, target.Count)
+		assert.Equal(t, "me.png", target.Avatar.Filename)
+		assert.Equal(t, "image/png", target.Avatar.ContentType)
+		assert.Equal(t, int64(8), target.Avatar.Size)
+		require.NotNil(t, target.Cover)
+		assert.Equal(t, "cover.jpg", target.Cover.Filename)
+		require.NotNil(t, target.Raw)
+		assert.Equal(t, "raw.bin", target.Raw.Filename)
+		require.Len(t, target.Photos, 2)
+		assert.Equal(t, "b.png", target.Photos[1].Filename)
+
+		f, err := target.Avatar.Open()
+		require.NoError(t, err)
+		defer f.Close()
+		content, err := io.ReadAll(f)
+		require.NoError(t, err)
+		assert.Equal(t, "png-data", string(content))
+	})
+
+	t.Run("should reject files larger than the limit", func(t *testing.T) {
+		req := newMultipartRequest(t, nil, multipartFile{"avatar", "me.png", "image/png", "0123456789"})
+
+		var target uploadRequest
+		err := BindMultipart(req, &target, &BindOptions{MaxFileBytes: 5})
+		assert.ErrorContains(t, err, "exceeds 5 bytes")
+		assert.ErrorIs(t, err, ErrFileTooLarge)
+
+		var bindErr *BindError
+		require.ErrorAs(t, err, &bindErr)
+		assert.Equal(t, "avatar", bindErr.Field)
+		assert.Equal(t, "form", bindErr.Location)
+	})
+
+	t.Run("should collect file errors with the other ones", func(t *testing.T) {
+		req := newMultipartRequest(t,
+			map[string]string{"count": "many"},
+			multipartFile{"avatar", "me.png", "image/png", "0123456789"},
+			multipartFile{"cover", "cover.exe", "application/x-msdownload", "MZ"},
+		)
+
+		var target uploadRequest
+		err := BindMultipart(req, &target, &BindOptions{
+			MaxFileBytes:        5,
+			AllowedContentTypes: []string{"image/*"},
+			CollectErrors:       true,
+		})
+
+		var collected *CollectedErrors
+		require.ErrorAs(t, err, &collected)
+		require.Len(t, collected.Bind, 3)
+		assert.Equal(t, "count", collected.Bind[0].Field)
+		assert.ErrorIs(t, collected.Bind[1], ErrFileTooLarge)
+		assert.ErrorIs(t, collected.Bind[2], ErrContentTypeNotAllowed)
+	})
+
+	t.Run("should keep the form values available", func(t *testing.T) {
+		req := newMultipartRequest(t,
+			map[string]string{"title": "Holidays"},
+			multipartFile{"avatar", "me.png", "image/png", "png"},
+		)
+
+		var target uploadRequest
+		require.NoError(t, BindMultipart(req, &target, &BindOptions{MaxFileBytes: 5}))
+		assert.Equal(t, "Holidays", req.FormValue("title"))
+		assert.Equal(t, int64(3), target.Avatar.Size)
+	})
+
+	t.Run("should bind nested structs", func(t *testing.T) {
+		type profile struct {
+			Name   string       `json:"name"`
+			Avatar UploadedFile `json:"avatar"`
+		}
+
+		var target struct {
+			Profile profile               `json:"profile"`
+			Raw     *multipart.FileHeader `json:"raw"`
+		}
+
+		req := newMultipartRequest(t,
+			map[string]string{"profile.name": "john"},
+			multipartFile{"profile.avatar", "me.png", "image/png", "png"},
+			multipartFile{"raw", "raw.bin", "application/octet-stream", "raw"},
+		)
+		require.NoError(t, BindMultipart(req, &target))
+		assert.Equal(t, "john", target.Profile.Name)
+		assert.Equal(t, "me.png", target.Profile.Avatar.Filename)
+		require.NotNil(t, target.Raw)
+		assert.Equal(t, "raw.bin", target.Raw.Filename)
+	})
+
+	t.Run("should reject content types not allowed", func(t *testing.T) {
+		req := newMultipartRequest(t, nil, multipartFile{"avatar", "me.exe", "application/x-msdownload", "MZ"})
+
+		var target uploadRequest
+		err := BindMultipart(req, &target, &BindOptions{AllowedContentTypes: []string{"image/*"}})
+		assert.ErrorContains(t, err, "not allowed")
+
+		req = newMultipartRequest(t, nil, multipartFile{"avatar", "me.png", "image/png", "png"})
+		assert.NoError(t, BindMultipart(req, &target, &BindOptions{AllowedContentTypes: []string{"image/*"}}))
+	})
+
+	t.Run("should report missing required files", func(t *testing.T) {
+		var target struct {
+			Avatar UploadedFile `json:"avatar" http:"required"`
+		}
+
+		req := newMultipartRequest(t, map[string]string{"title": "Holidays"})
+		assert.ErrorIs(t, BindMultipart(req, &target), ErrMissingParameter)
+	})
+
+	t.Run("should return error for non multipart requests", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/upload", nil)
+
+		var target uploadRequest
+		assert.Error(t, BindMultipart(req, &target))
+	})
+}
+
+func TestCopyLimitedParts(t *testing.T) {
+	t.Run("should not copy files past the limit", func(t *testing.T) {
+		req := newMultipartRequest(t,
+			map[string]string{"title": "a long text field"},
+			multipartFile{"avatar", "me.png", "image/png", "0123456789"},
+			multipartFile{"cover", "cover.png", "image/png", "0123"},
+		)
+		mr, err := req.MultipartReader()
+		require.NoError(t, err)
+
+		var (
+			body bytes.Buffer
+			mw   = multipart.NewWriter(&body)
+		)
+		require.NoError(t, copyLimitedParts(mw, mr, 5))
+
+		form, err := multipart.NewReader(&body, mw.Boundary()).ReadForm(1 << 20)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"a long text field"}, form.Value["title"])
+		assert.Equal(t, int64(6), form.File["avatar"][0].Size)
+		assert.Equal(t, int64(4), form.File["cover"][0].Size)
+	})
+}
@@ -0,0 +1,183 @@
+package http
+
+import (
+	"reflect"
+	"slices"
+)
+
+const (
+	// maxNestedDepth is how deep the binding descends into nested structs.
+	maxNestedDepth = 16
+)
+
+// boundField is a field found while walking a binding target.
+type boundField struct {
+	sf    reflect.StructField
+	fv    reflect.Value
+	name  string
+	tag   *bindTag
+	index []int
+}
+
+// walkFields calls fn for every bindable field of rv, descending into nested
+// and embedded structs. Fields of a nested struct have their names prefixed
+// by the struct name and a dot, e.g. "filter.name", unless a `prefix` tag
+// option is given. Fields of embedded structs without a json tag are handled
+// as if they were declared in the outer struct.
+//
+// Fields without an http tag inherit the location of the struct that
+// contains them, so `http:"loc=query"` can be set only on the nested struct
+// field.
+//
+// Self-referencing types, e.g. `Parent *Node` inside Node, are not descended
+// into again, and neither are structs deeper than maxNestedDepth.
+func walkFields(
+	rv reflect.Value,
+	opt *BindOptions,
+	prefix, location string,
+	index []int,
+	fn func(f *boundField) error,
+) error {
+	return walkStruct(rv, opt, prefix, location, index, nil, fn)
+}
+
+// walkStruct walks the fields of rv, whose enclosing struct types are given
+// by parents.
+func walkStruct(
+	rv reflect.Value,
+	opt *BindOptions,
+	prefix, location string,
+	index []int,
+	parents []reflect.Type,
+	fn func(f *boundField) error,
+) error {
+	parents = append(parents[:len(parents):len(parents)], rv.Type())
+	cached := typeFields(rv.Type(), opt.FallbackSnakeCase)
+
+	for _, cf := range cached.fields {
+		var (
+			sf   = cf.sf
+			fv   = rv.Field(cf.index)
+			name = cf.name
+			tag  = cf.tag
+		)
+
+		fieldLocation := location
+		if tag != nil && tag.Location != "" {
+			fieldLocation = tag.Location
+		}
+		if tag == nil && location != "" {
+			tag = &bindTag{}
+		}
+		if tag != nil && tag.Location == "" {
+			// The cached tag is shared, so the inherited location is set
+			// into a copy of it.
+			inherited := *tag
+			inherited.Location = fieldLocation
+			tag = &inherited
+		}
+
+		fieldIndex := append(append([]int(nil), index...), cf.index)
+		if cf.nested && fieldLocation != "body" {
+			if !canDescend(sf.Type, parents) {
+				continue
+			}
+
+			if err := walkNested(sf, fv, opt, prefix+nestedPrefix(sf, name, tag), fieldLocation, fieldIndex, parents, fn); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if !fv.CanSet() {
+			continue
+		}
+
+		if err := fn(&boundField{
+			sf:    sf,
+			fv:    fv,
+			name:  prefix + name,
+			tag:   tag,
+			index: fieldIndex,
+		}); err != nil {
+			return err
+		}
+	}
+
+	return cached.err
+}
+
+// walkNested walks a nested struct field. Pointers are only allocated when
+// at least one of the nested fields is bound.
+func walkNested(
+	sf reflect.StructField,
+	fv reflect.Value,
+	opt *BindOptions,
+	prefix, location string,
+	index []int,
+	parents []reflect.Type,
+	fn func(f *boundField) error,
+) error {
+	if fv.Kind() != reflect.Ptr {
+		return walkStruct(fv, opt, prefix, location, index, parents, fn)
+	}
+
+	target := fv
+	if fv.IsNil() {
+		target = reflect.New(sf.Type.Elem())
+	}
+
+	if err := walkStruct(target.Elem(), opt, prefix, location, index, parents, fn); err != nil {
+		return err
+	}
+
+	if fv.IsNil() && !target.Elem().IsZero() {
+		fv.Set(target)
+	}
+
+	return nil
+}
+
+// canDescend reports whether a nested struct of type t can be walked inside
+// the parents structs without looping forever.
+func canDescend(t reflect.Type, parents []reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	return len(parents) < maxNestedDepth && !slices.Contains(parents, t)
+}
+
+func nestedPrefix(sf reflect.StructField, name string, tag *bindTag) string {
+	if tag != nil && tag.HasPrefix {
+		return tag.Prefix
+	}
+	if sf.Anonymous && sf.Tag.Get("json") == "" {
+		return ""
+	}
+
+	return name + "."
+}
+
+// isNestedStruct reports whether t is a struct, or a pointer to one, whose
+// fields must be bound individually, i.e., it is not a type that the binding
+// converts from a single value.
+func isNestedStruct(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+
+	switch t {
+	case timeType, localeType, uploadedFileType, fileHeaderType.Elem():
+		return false
+	}
+	if _, ok := lookupBinder(t); ok {
+		return false
+	}
+
+	return !reflect.PointerTo(t).Implements(textUnmarshalerType)
+}
@@ -0,0 +1,132 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type pagination struct {
+	Page     int `json:"page" http:"loc=query,default=1"`
+	PageSize int `json:"page_size" http:"loc=query"`
+}
+
+type listFilter struct {
+	Name   string    `json:"name"`
+	Since  time.Time `json:"since"`
+	Status []string  `json:"status"`
+}
+
+type recursiveNode struct {
+	Name   string         `json:"name" http:"loc=query"`
+	Parent *recursiveNode `json:"parent"`
+}
+
+func TestBindNested(t *testing.T) {
+	t.Run("should bind nested and embedded structs", func(t *testing.T) {
+		var (
+			r = httptest.NewRequest(http.MethodGet, "/?page_size=20&filter.name=john&filter.since=2024-01-02T00:00:00Z&filter.status=a,b", nil)
+			v = struct {
+				pagination
+				Filter listFilter `json:"filter" http:"loc=query"`
+			}{}
+		)
+
+		err := Bind(r, &v)
+		require.NoError(t, err)
+		assert.Equal(t, 1, v.Page)
+		assert.Equal(t, 20, v.PageSize)
+		assert.Equal(t, "john", v.Filter.Name)
+		assert.Equal(t, 2024, v.Filter.Since.Year())
+		assert.Equal(t, []string{"a", "b"}, v.Filter.Status)
+	})
+
+	t.Run("should use a custom prefix", func(t *testing.T) {
+		var (
+			r = httptest.NewRequest(http.MethodGet, "/?f_name=john", nil)
+			v = struct {
+				Filter listFilter `json:"filter" http:"loc=query,prefix=f_"`
+			}{}
+		)
+
+		require.NoError(t, Bind(r, &v))
+		assert.Equal(t, "john", v.Filter.Name)
+	})
+
+	t.Run("should only allocate nested pointers when bound", func(t *testing.T) {
+		var (
+			r = httptest.NewRequest(http.MethodGet, "/?filter.name=john", nil)
+			v = struct {
+				Filter *listFilter `json:"filter"`
+				Other  *listFilter `json:"other"`
+			}{}
+		)
+
+		require.NoError(t, BindQuery(r, &v))
+		require.NotNil(t, v.Filter)
+		assert.Equal(t, "john", v.Filter.Name)
+		assert.Nil(t, v.Other)
+	})
+
+	t.Run("should bind nested structs from the body as a whole", func(t *testing.T) {
+		var (
+			r = httptest.NewRequest(http.MethodPost, "/?page=3", strings.NewReader(`{"filter":{"name":"john"}}`))
+			v = struct {
+				Page   int        `json:"page" http:"loc=query"`
+				Filter listFilter `json:"filter" http:"loc=body"`
+			}{}
+		)
+
+		require.NoError(t, Bind(r, &v))
+		assert.Equal(t, 3, v.Page)
+		assert.Equal(t, "john", v.Filter.Name)
+	})
+
+	t.Run("should report nested fields by their prefixed names", func(t *testing.T) {
+		var (
+			r = httptest.NewRequest(http.MethodGet, "/?paging.page_size=many", nil)
+			v = struct {
+				Paging pagination `json:"paging"`
+			}{}
+		)
+
+		var bindErr *BindError
+		require.ErrorAs(t, Bind(r, &v), &bindErr)
+		assert.Equal(t, "paging.page_size", bindErr.Field)
+	})
+
+	t.Run("should not descend into self-referencing structs", func(t *testing.T) {
+		var (
+			r = httptest.NewRequest(http.MethodGet, "/?name=leaf&parent.name=root", nil)
+			v recursiveNode
+		)
+
+		require.NoError(t, Bind(r, &v))
+		assert.Equal(t, "leaf", v.Name)
+		assert.Nil(t, v.Parent)
+	})
+
+	t.Run("should not descend into mutually referencing structs", func(t *testing.T) {
+		type node struct {
+			Name  string         `json:"name" http:"loc=query"`
+			Child *recursiveNode `json:"child"`
+		}
+
+		var (
+			r = httptest.NewRequest(http.MethodGet, "/?name=a&child.name=b&child.parent.name=c", nil)
+			v node
+		)
+
+		require.NoError(t, Bind(r, &v))
+		assert.Equal(t, "a", v.Name)
+		require.NotNil(t, v.Child)
+		assert.Equal(t, "b", v.Child.Name)
+		assert.Nil(t, v.Child.Parent)
+	})
+
+}
@@ -0,0 +1,467 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/mail"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	uuidRegexp = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+)
+
+// OpenAPIDocument is the subset of an OpenAPI 3 document used to validate
+// inbound requests.
+type OpenAPIDocument struct {
+	routes []*openAPIRoute
+}
+
+// OpenAPIRoute is a documented operation together with its method and path
+// template.
+type OpenAPIRoute struct {
+	Method    string
+	Path      string
+	Operation *OpenAPIOperation
+}
+
+// OpenAPIOperation describes the inbound contract of a single operation.
+type OpenAPIOperation struct {
+	Parameters  []OpenAPIParameter  `json:"parameters"`
+	RequestBody *OpenAPIRequestBody `json:"requestBody"`
+
+	// Security holds the operation security requirements. When the
+	// operation does not declare them, the document ones are used.
+	Security []map[string][]string `json:"security"`
+}
+
+// RequiresAuth reports whether the operation can only be called by
+// authenticated clients.
+func (o *OpenAPIOperation) RequiresAuth() bool {
+	if len(o.Security) == 0 {
+		return false
+	}
+
+	// An empty requirement makes authentication optional.
+	return !slices.ContainsFunc(o.Security, func(s map[string][]string) bool {
+		return len(s) == 0
+	})
+}
+
+// OpenAPIParameter describes a path, query, header or cookie parameter.
+type OpenAPIParameter struct {
+	Name     string         `json:"name"`
+	In       string         `json:"in"`
+	Required bool           `json:"required"`
+	Schema   *OpenAPISchema `json:"schema"`
+}
+
+// OpenAPISchema is the subset of a parameter schema that is validated.
+type OpenAPISchema struct {
+	Type   string         `json:"type"`
+	Format string         `json:"format"`
+	Enum   []interface{}  `json:"enum"`
+	Items  *OpenAPISchema `json:"items"`
+}
+
+// OpenAPIRequestBody describes the accepted request body.
+type OpenAPIRequestBody struct {
+	Required bool                       `json:"required"`
+	Content  map[string]json.RawMessage `json:"content"`
+}
+
+type openAPIRoute struct {
+	method    string
+	path      string
+	segments  []string
+	operation *OpenAPIOperation
+}
+
+// ParseOpenAPI parses an OpenAPI 3 document in JSON format. Only the
+// information needed to validate inbound requests is kept: operations,
+// their parameters and request body content types. Parameters declared
+// in a path item apply to all its operations, unless overridden.
+func ParseOpenAPI(data []byte) (*OpenAPIDocument, error) {
+	var doc struct {
+		Paths    map[string]map[string]json.RawMessage `json:"paths"`
+		Security []map[string][]string                 `json:"security"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("could not parse OpenAPI document: %w", err)
+	}
+
+	document := &OpenAPIDocument{}
+	for path, item := range doc.Paths {
+		var shared []OpenAPIParameter
+		if raw, ok := item["parameters"]; ok {
+			if err := json.Unmarshal(raw, &shared); err != nil {
+				return nil, fmt.Errorf("could not parse parameters of '%s': %w", path, err)
+			}
+		}
+
+		for method, raw := range item {
+			if !isOpenAPIMethod(method) {
+				continue
+			}
+
+			var operation OpenAPIOperation
+			if err := json.Unmarshal(raw, &operation); err != nil {
+				return nil, fmt.Errorf("could not parse operation '%s %s': %w", strings.ToUpper(method), path, err)
+			}
+			operation.Parameters = mergeOpenAPIParameters(shared, operation.Parameters)
+			if operation.Security == nil {
+				operation.Security = doc.Security
+			}
+
+			document.routes = append(document.routes, &openAPIRoute{
+				method:    strings.ToUpper(method),
+				path:      path,
+				segments:  splitPath(path),
+				operation: &operation,
+			})
+		}
+	}
+
+	// Static segments must win over templated ones, so routes with more
+	// static segments are tried first.
+	slices.SortStableFunc(document.routes, func(a, b *openAPIRoute) int {
+		return b.staticSegments() - a.staticSegments()
+	})
+
+	return document, nil
+}
+
+func isOpenAPIMethod(method string) bool {
+	switch strings.ToLower(method) {
+	case "get", "put", "post", "delete", "options", "head", "patch", "trace":
+		return true
+	}
+
+	return false
+}
+
+func mergeOpenAPIParameters(shared, own []OpenAPIParameter) []OpenAPIParameter {
+	parameters := slices.Clone(own)
+	for _, s := range shared {
+		overridden := slices.ContainsFunc(own, func(p OpenAPIParameter) bool {
+			return p.Name == s.Name && p.In == s.In
+		})
+		if !overridden {
+			parameters = append(parameters, s)
+		}
+	}
+
+	return parameters
+}
+
+func splitPath(path string) []string {
+	return strings.Split(strings.Trim(path, "/"), "/")
+}
+
+func (r *openAPIRoute) staticSegments() int {
+	n := 0
+	for _, s := range r.segments {
+		if !isPathTemplate(s) {
+			n++
+		}
+	}
+
+	return n
+}
+
+func isPathTemplate(segment string) bool {
+	return strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}")
+}
+
+func (r *openAPIRoute) match(method string, segments []string) (map[string]string, bool) {
+	if r.method != method || len(r.segments) != len(segments) {
+		return nil, false
+	}
+
+	values := make(map[string]string)
+	for i, s := range r.segments {
+		if isPathTemplate(s) {
+			if segments[i] == "" {
+				return nil, false
+			}
+
+			values[strings.Trim(s, "{}")] = segments[i]
+			continue
+		}
+		if s != segments[i] {
+			return nil, false
+		}
+	}
+
+	return values, true
+}
+
+// Routes returns all documented operations.
+func (d *OpenAPIDocument) Routes() []OpenAPIRoute {
+	routes := make([]OpenAPIRoute, 0, len(d.routes))
+	for _, r := range d.routes {
+		routes = append(routes, OpenAPIRoute{
+			Method:    r.method,
+			Path:      r.path,
+			Operation: r.operation,
+		})
+	}
+
+	slices.SortFunc(routes, func(a, b OpenAPIRoute) int {
+		if c := strings.Compare(a.Path, b.Path); c != 0 {
+			return c
+		}
+
+		return strings.Compare(a.Method, b.Method)
+	})
+
+	return routes
+}
+
+// Operation returns the operation matching a request method and path, and the
+// values of its path parameters.
+func (d *OpenAPIDocument) Operation(method, path string) (*OpenAPIOperation, map[string]string, bool) {
+	segments := splitPath(path)
+	for _, route := range d.routes {
+		if values, ok := route.match(method, segments); ok {
+			return route.operation, values, true
+		}
+	}
+
+	return nil, nil, false
+}
+
+// RequestValidationOptions configures the ValidateRequests middleware.
+type RequestValidationOptions struct {
+	// Document is the OpenAPI document that requests are validated against.
+	Document *OpenAPIDocument
+
+	// RejectUnknownRoutes makes requests that don't match any documented
+	// operation fail with 404 Not Found. By default, they are passed through
+	// without validation.
+	RejectUnknownRoutes bool
+
+	// Problem configures how validation failures are output. Its
+	// HTTPStatusCode is always set to 400 Bad Request.
+	Problem ProblemOptions
+}
+
+// ValidateRequests returns a middleware that validates inbound requests
+// against an OpenAPI document before calling the handler. It checks that
+// required parameters are present, that parameters match their schema type,
+// format and enum, and that the request body is present when required and
+// uses one of the documented content types. Invalid requests receive a
+// 400 Bad Request response written by Problem.
+func ValidateRequests(options RequestValidationOptions) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if options.Document == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			operation, pathValues, ok := options.Document.Operation(r.Method, r.URL.Path)
+			if !ok {
+				if options.RejectUnknownRoutes {
+					http.NotFound(w, r)
+					return
+				}
+
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if err := operation.Validate(r, pathValues); err != nil {
+				problemOpts := options.Problem
+				problemOpts.HTTPStatusCode = http.StatusBadRequest
+				Problem(r.Context(), w, err, problemOpts)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// Validate checks a request against the operation contract. pathValues
+// holds the values of the path template parameters.
+func (o *OpenAPIOperation) Validate(r *http.Request, pathValues map[string]string) error {
+	for _, p := range o.Parameters {
+		values, present := parameterValues(r, p, pathValues)
+		if !present {
+			if p.Required || p.In == "path" {
+				return fmt.Errorf("missing required %s parameter '%s'", p.In, p.Name)
+			}
+
+			continue
+		}
+
+		for _, v := range values {
+			if err := p.Schema.validate(v); err != nil {
+				return fmt.Errorf("invalid %s parameter '%s': %w", p.In, p.Name, err)
+			}
+		}
+	}
+
+	return o.validateBody(r)
+}
+
+func parameterValues(r *http.Request, p OpenAPIParameter, pathValues map[string]string) ([]string, bool) {
+	var values []string
+
+	switch p.In {
+	case "path":
+		if v, ok := pathValues[p.Name]; ok {
+			values = []string{v}
+		}
+	case "query":
+		values = r.URL.Query()[p.Name]
+	case "header":
+		values = r.Header.Values(p.Name)
+	case "cookie":
+		if c, err := r.Cookie(p.Name); err == nil {
+			values = []string{c.Value}
+		}
+	}
+
+	return values, len(values) > 0
+}
+
+func (o *OpenAPIOperation) validateBody(r *http.Request) error {
+	if o.RequestBody == nil {
+		return nil
+	}
+
+	hasBody := r.ContentLength > 0 || (r.ContentLength < 0 && r.Body != nil && r.Body != http.NoBody)
+	if !hasBody {
+		if o.RequestBody.Required {
+			return errors.New("missing required request body")
+		}
+
+		return nil
+	}
+
+	if len(o.RequestBody.Content) == 0 {
+		return nil
+	}
+
+	contentType := r.Header.Get("Content-Type")
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return fmt.Errorf("invalid content type '%s'", contentType)
+	}
+
+	for accepted := range o.RequestBody.Content {
+		if mediaTypeMatches(accepted, mediaType) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unsupported content type '%s'", mediaType)
+}
+
+func mediaTypeMatches(accepted, mediaType string) bool {
+	if accepted == "*/*" || strings.EqualFold(accepted, mediaType) {
+		return true
+	}
+
+	prefix, ok := strings.CutSuffix(accepted, "/*")
+	return ok && strings.HasPrefix(strings.ToLower(mediaType), strings.ToLower(prefix)+"/")
+}
+
+func (s *OpenAPISchema) validate(value string) error {
+	if s == nil {
+		return nil
+	}
+
+	if s.Type == "array" {
+		for _, v := range strings.Split(value, ",") {
+			if err := s.Items.validate(v); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	if err := s.validateType(value); err != nil {
+		return err
+	}
+	if err := s.validateFormat(value); err != nil {
+		return err
+	}
+
+	return s.validateEnum(value)
+}
+
+func (s *OpenAPISchema) validateType(value string) error {
+	var err error
+
+	switch s.Type {
+	case "integer":
+		bitSize := 64
+		if s.Format == "int32" {
+			bitSize = 32
+		}
+		_, err = strconv.ParseInt(value, 10, bitSize)
+	case "number":
+		_, err = strconv.ParseFloat(value, 64)
+	case "boolean":
+		_, err = strconv.ParseBool(value)
+	}
+	if err != nil {
+		return fmt.Errorf("'%s' is not a valid %s", value, s.Type)
+	}
+
+	return nil
+}
+
+func (s *OpenAPISchema) validateFormat(value string) error {
+	if s.Type != "" && s.Type != "string" {
+		return nil
+	}
+
+	var ok bool
+
+	switch s.Format {
+	case "date-time":
+		_, err := time.Parse(time.RFC3339, value)
+		ok = err == nil
+	case "date":
+		_, err := time.Parse(time.DateOnly, value)
+		ok = err == nil
+	case "uuid":
+		ok = uuidRegexp.MatchString(value)
+	case "email":
+		_, err := mail.ParseAddress(value)
+		ok = err == nil
+	default:
+		return nil
+	}
+	if !ok {
+		return fmt.Errorf("'%s' is not a valid %s", value, s.Format)
+	}
+
+	return nil
+}
+
+func (s *OpenAPISchema) validateEnum(value string) error {
+	if len(s.Enum) == 0 {
+		return nil
+	}
+
+	for _, e := range s.Enum {
+		if fmt.Sprint(e) == value {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("'%s' is not one of the allowed values", value)
+}
@@ -0,0 +1,145 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testOpenAPIDocument = `{
+	"openapi": "3.0.0",
+	"paths": {
+		"/users/{id}": {
+			"parameters": [
+				{"name": "id", "in": "path", "required": true, "schema": {"type": "string", "format": "uuid"}}
+			],
+			"get": {
+				"parameters": [
+					{"name": "fields", "in": "query", "schema": {"type": "array", "items": {"type": "string", "enum": ["name", "email"]}}},
+					{"name": "X-Tenant", "in": "header", "required": true, "schema": {"type": "integer", "format": "int32"}}
+				]
+			}
+		},
+		"/users/me": {
+			"get": {}
+		},
+		"/users": {
+			"post": {
+				"requestBody": {
+					"required": true,
+					"content": {"application/json": {}}
+				}
+			}
+		}
+	}
+}`
+
+func TestParseOpenAPI(t *testing.T) {
+	t.Run("should parse operations and merge path item parameters", func(t *testing.T) {
+		doc, err := ParseOpenAPI([]byte(testOpenAPIDocument))
+		require.NoError(t, err)
+
+		op, values, ok := doc.Operation(http.MethodGet, "/users/5f1b0b5e-7a3f-4c1e-9d2a-2b7e4c8f9a10")
+		require.True(t, ok)
+		assert.Len(t, op.Parameters, 3)
+		assert.Equal(t, map[string]string{"id": "5f1b0b5e-7a3f-4c1e-9d2a-2b7e4c8f9a10"}, values)
+	})
+
+	t.Run("should prefer static path segments", func(t *testing.T) {
+		doc, err := ParseOpenAPI([]byte(testOpenAPIDocument))
+		require.NoError(t, err)
+
+		op, _, ok := doc.Operation(http.MethodGet, "/users/me")
+		require.True(t, ok)
+		assert.Empty(t, op.Parameters)
+	})
+
+	t.Run("should fail with invalid documents", func(t *testing.T) {
+		_, err := ParseOpenAPI([]byte(`{"paths": []}`))
+		assert.Error(t, err)
+	})
+}
+
+func TestValidateRequests(t *testing.T) {
+	doc, err := ParseOpenAPI([]byte(testOpenAPIDocument))
+	require.NoError(t, err)
+
+	var (
+		handler = http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		})
+		serve = func(options RequestValidationOptions, r *http.Request) *httptest.ResponseRecorder {
+			rec := httptest.NewRecorder()
+			ValidateRequests(options)(handler).ServeHTTP(rec, r)
+			return rec
+		}
+		userPath = "/users/5f1b0b5e-7a3f-4c1e-9d2a-2b7e4c8f9a10"
+	)
+
+	t.Run("should accept valid requests", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, userPath+"?fields=name,email", nil)
+		r.Header.Set("X-Tenant", "10")
+
+		rec := serve(RequestValidationOptions{Document: doc}, r)
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+	})
+
+	t.Run("should reject requests with invalid parameters", func(t *testing.T) {
+		tests := []struct {
+			name    string
+			path    string
+			tenant  string
+			message string
+		}{
+			{name: "invalid path format", path: "/users/123", tenant: "1", message: "invalid path parameter 'id'"},
+			{name: "missing header", path: userPath, message: "missing required header parameter 'X-Tenant'"},
+			{name: "invalid header type", path: userPath, tenant: "abc", message: "invalid header parameter 'X-Tenant'"},
+			{name: "invalid enum", path: userPath + "?fields=name,age", tenant: "1", message: "invalid query parameter 'fields'"},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				r := httptest.NewRequest(http.MethodGet, tt.path, nil)
+				if tt.tenant != "" {
+					r.Header.Set("X-Tenant", tt.tenant)
+				}
+
+				rec := serve(RequestValidationOptions{Document: doc}, r)
+				assert.Equal(t, http.StatusBadRequest, rec.Code)
+				assert.Contains(t, rec.Body.String(), tt.message)
+			})
+		}
+	})
+
+	t.Run("should validate the request body", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, "/users", nil)
+		rec := serve(RequestValidationOptions{Document: doc}, r)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "missing required request body")
+
+		r = httptest.NewRequest(http.MethodPost, "/users", strings.NewReader("name=a"))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		rec = serve(RequestValidationOptions{Document: doc}, r)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "unsupported content type")
+
+		r = httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`{"name":"a"}`))
+		r.Header.Set("Content-Type", "application/json; charset=utf-8")
+		rec = serve(RequestValidationOptions{Document: doc}, r)
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+	})
+
+	t.Run("should handle unknown routes", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/unknown", nil)
+
+		rec := serve(RequestValidationOptions{Document: doc}, r)
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+
+		rec = serve(RequestValidationOptions{Document: doc, RejectUnknownRoutes: true}, r)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
@@ -0,0 +1,301 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	defaultPerPage    = 20
+	defaultMaxPerPage = 100
+
+	pageParameter    = "page"
+	perPageParameter = "per_page"
+	cursorParameter  = "cursor"
+	sortParameter    = "sort"
+)
+
+const (
+	// TotalUnknown is the Page.Total of lists that don't know how many items
+	// they have, as usual with cursor based pagination.
+	TotalUnknown = -1
+)
+
+// Pagination is the pagination requested by a list request, bound from the
+// "page", "per_page", "cursor" and "sort" query parameters.
+type Pagination struct {
+	// Page is the requested page number, starting at 1.
+	Page int
+
+	// PerPage is the number of items per page.
+	PerPage int
+
+	// Cursor is the opaque position, sent by cursor based paginated lists,
+	// from where the page starts.
+	Cursor string
+
+	// Sort is the requested sort order, parsed from parameters like
+	// "sort=-created_at,name".
+	Sort []SortField
+}
+
+// SortField is a field of a sort order.
+type SortField struct {
+	Field      string
+	Descending bool
+}
+
+// Offset returns the number of items before the page.
+func (p *Pagination) Offset() int {
+	return (p.Page - 1) * p.PerPage
+}
+
+// Limit returns the maximum number of items of the page.
+func (p *Pagination) Limit() int {
+	return p.PerPage
+}
+
+// PaginationOptions configures BindPagination.
+type PaginationOptions struct {
+	// DefaultPerPage is used when "per_page" is not sent. It defaults to
+	// 20.
+	DefaultPerPage int
+
+	// MaxPerPage is the largest "per_page" accepted. It defaults to 100.
+	MaxPerPage int
+
+	// SortFields are the fields that can be used to sort the list. Any
+	// field is accepted when empty.
+	SortFields []string
+}
+
+// BindPagination binds the pagination parameters of a list request. Invalid
+// parameters are reported as BindErrors.
+func BindPagination(r *http.Request, options ...PaginationOptions) (*Pagination, error) {
+	var opts PaginationOptions
+	if len(options) > 0 {
+		opts = options[0]
+	}
+	if opts.DefaultPerPage <= 0 {
+		opts.DefaultPerPage = defaultPerPage
+	}
+	if opts.MaxPerPage <= 0 {
+		opts.MaxPerPage = defaultMaxPerPage
+	}
+
+	var (
+		q    = r.URL.Query()
+		errs BindErrors
+		p    = &Pagination{
+			Page:    1,
+			PerPage: opts.DefaultPerPage,
+			Cursor:  q.Get(cursorParameter),
+		}
+	)
+
+	if v := q.Get(pageParameter); v != "" {
+		page, err := strconv.Atoi(v)
+		if err == nil && page < 1 {
+			err = errors.New("must be greater than zero")
+		}
+		if err != nil {
+			errs = append(errs, newBindError(pageParameter, "query", []string{v}, err))
+		}
+		p.Page = page
+	}
+
+	if v := q.Get(perPageParameter); v != "" {
+		perPage, err := strconv.Atoi(v)
+		if err == nil && (perPage < 1 || perPage > opts.MaxPerPage) {
+			err = fmt.Errorf("must be between 1 and %d", opts.MaxPerPage)
+		}
+		if err != nil {
+			errs = append(errs, newBindError(perPageParameter, "query", []string{v}, err))
+		}
+		p.PerPage = perPage
+	}
+
+	if v := q.Get(sortParameter); v != "" {
+		sort, err := parseSort(v, opts.SortFields)
+		if err != nil {
+			errs = append(errs, newBindError(sortParameter, "query", []string{v}, err))
+		}
+		p.Sort = sort
+	}
+
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	return p, nil
+}
+
+func parseSort(value string, allowed []string) ([]SortField, error) {
+	var fields []SortField
+	for _, s := range stringsSplitAndTrimRune(value, ',') {
+		field := SortField{Field: s}
+		if name, ok := strings.CutPrefix(s, "-"); ok {
+			field = SortField{Field: name, Descending: true}
+		} else if name, ok := strings.CutPrefix(s, "+"); ok {
+			field.Field = name
+		}
+
+		if field.Field == "" {
+			return nil, errors.New("empty sort field")
+		}
+		if len(allowed) > 0 && !slices.Contains(allowed, field.Field) {
+			return nil, fmt.Errorf("cannot sort by '%s'", field.Field)
+		}
+
+		fields = append(fields, field)
+	}
+
+	return fields, nil
+}
+
+// Page is a page of items returned by a list request.
+type Page[T any] struct {
+	// Items are the page items.
+	Items []T
+
+	// Total is the number of items of all pages, or TotalUnknown.
+	Total int
+
+	// NextCursor is the cursor of the next page, for cursor based
+	// paginated lists. It must be empty on the last page.
+	NextCursor string
+}
+
+// PageResponse is the envelope written by SuccessPage.
+type PageResponse[T any] struct {
+	Items      []T          `json:"items"`
+	Pagination PageMetadata `json:"pagination"`
+}
+
+// PageMetadata describes the page written by SuccessPage.
+type PageMetadata struct {
+	Page       int    `json:"page,omitempty"`
+	PerPage    int    `json:"per_page"`
+	Total      *int   `json:"total,omitempty"`
+	TotalPages *int   `json:"total_pages,omitempty"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// SuccessPage outputs a page of a list as a PageResponse, with its
+// pagination metadata, and adds a Link header with the URLs of the
+// neighbouring pages. When the total is known, it is also sent in the
+// X-Total-Count header.
+//
+// Lists paginated by cursor only link to the next page. The other ones link
+// to the first, previous, next and last pages, when they exist.
+func SuccessPage[T any](
+	ctx context.Context,
+	w http.ResponseWriter,
+	r *http.Request,
+	pagination *Pagination,
+	page Page[T],
+	options ...SuccessOptions,
+) {
+	items := page.Items
+	if items == nil {
+		items = []T{}
+	}
+
+	metadata := PageMetadata{
+		PerPage:    pagination.PerPage,
+		NextCursor: page.NextCursor,
+	}
+
+	cursorBased := pagination.Cursor != "" || page.NextCursor != ""
+	if !cursorBased {
+		metadata.Page = pagination.Page
+	}
+
+	if page.Total >= 0 {
+		total := page.Total
+		metadata.Total = &total
+		w.Header().Set("X-Total-Count", strconv.Itoa(total))
+
+		if !cursorBased && pagination.PerPage > 0 {
+			totalPages := (total + pagination.PerPage - 1) / pagination.PerPage
+			metadata.TotalPages = &totalPages
+		}
+	}
+
+	if links := pageLinks(r, pagination, metadata, len(items)); len(links) > 0 {
+		w.Header().Set("Link", strings.Join(links, ", "))
+	}
+
+	var successOpts SuccessOptions
+	if len(options) > 0 {
+		successOpts = options[0]
+	}
+	if successOpts.Request == nil {
+		successOpts.Request = r
+	}
+
+	Success(ctx, w, &PageResponse[T]{
+		Items:      items,
+		Pagination: metadata,
+	}, successOpts)
+}
+
+func pageLinks(r *http.Request, pagination *Pagination, metadata PageMetadata, count int) []string {
+	var links []string
+
+	if metadata.NextCursor != "" {
+		return append(links, pageLink(r, "next", func(q url.Values) {
+			q.Del(pageParameter)
+			q.Set(cursorParameter, metadata.NextCursor)
+		}))
+	}
+	if metadata.Page == 0 {
+		// Last page of a cursor based list.
+		return nil
+	}
+
+	setPage := func(page int) func(q url.Values) {
+		return func(q url.Values) {
+			q.Set(pageParameter, strconv.Itoa(page))
+			q.Set(perPageParameter, strconv.Itoa(pagination.PerPage))
+		}
+	}
+
+	if pagination.Page > 1 {
+		links = append(links,
+			pageLink(r, "first", setPage(1)),
+			pageLink(r, "prev", setPage(pagination.Page-1)),
+		)
+	}
+
+	hasNext := count == pagination.PerPage
+	if metadata.TotalPages != nil {
+		hasNext = pagination.Page < *metadata.TotalPages
+	}
+	if hasNext {
+		links = append(links, pageLink(r, "next", setPage(pagination.Page+1)))
+	}
+	if metadata.TotalPages != nil && pagination.Page < *metadata.TotalPages {
+		links = append(links, pageLink(r, "last", setPage(*metadata.TotalPages)))
+	}
+
+	return links
+}
+
+func pageLink(r *http.Request, rel string, update func(q url.Values)) string {
+	q := r.URL.Query()
+	update(q)
+
+	u := url.URL{
+		Path:     r.URL.Path,
+		RawQuery: q.Encode(),
+	}
+
+	return fmt.Sprintf(`<%s>; rel="%s"`, u.String(), rel)
+}
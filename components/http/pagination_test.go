@@ -0,0 +1,130 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBindPagination(t *testing.T) {
+	t.Run("should use defaults without parameters", func(t *testing.T) {
+		p, err := BindPagination(httptest.NewRequest(http.MethodGet, "/users", nil))
+		require.NoError(t, err)
+		assert.Equal(t, &Pagination{Page: 1, PerPage: 20}, p)
+		assert.Equal(t, 0, p.Offset())
+		assert.Equal(t, 20, p.Limit())
+	})
+
+	t.Run("should bind the parameters", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/users?page=3&per_page=10&cursor=abc&sort=-created_at,+name", nil)
+
+		p, err := BindPagination(r, PaginationOptions{SortFields: []string{"created_at", "name"}})
+		require.NoError(t, err)
+		assert.Equal(t, 3, p.Page)
+		assert.Equal(t, 10, p.PerPage)
+		assert.Equal(t, "abc", p.Cursor)
+		assert.Equal(t, []SortField{{Field: "created_at", Descending: true}, {Field: "name"}}, p.Sort)
+		assert.Equal(t, 20, p.Offset())
+	})
+
+	t.Run("should report every invalid parameter", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/users?page=0&per_page=500&sort=password", nil)
+
+		_, err := BindPagination(r, PaginationOptions{SortFields: []string{"name"}})
+		require.Error(t, err)
+
+		var errs BindErrors
+		require.True(t, errors.As(err, &errs))
+		assert.Len(t, errs, 3)
+		assert.Contains(t, errs.FieldErrors(), "page")
+		assert.Contains(t, errs.FieldErrors(), "per_page")
+		assert.Contains(t, errs.FieldErrors(), "sort")
+	})
+
+	t.Run("should honor the per page options", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/users?per_page=150", nil)
+
+		p, err := BindPagination(r, PaginationOptions{DefaultPerPage: 50, MaxPerPage: 200})
+		require.NoError(t, err)
+		assert.Equal(t, 150, p.PerPage)
+
+		p, err = BindPagination(httptest.NewRequest(http.MethodGet, "/users", nil), PaginationOptions{DefaultPerPage: 50})
+		require.NoError(t, err)
+		assert.Equal(t, 50, p.PerPage)
+	})
+}
+
+func TestSuccessPage(t *testing.T) {
+	t.Run("should write the page with offset metadata and links", func(t *testing.T) {
+		var (
+			rec = httptest.NewRecorder()
+			r   = httptest.NewRequest(http.MethodGet, "/users?page=2&per_page=2&active=true", nil)
+		)
+
+		p, err := BindPagination(r)
+		require.NoError(t, err)
+
+		SuccessPage(ctx, rec, r, p, Page[string]{Items: []string{"c", "d"}, Total: 7})
+
+		var out PageResponse[string]
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &out))
+		assert.Equal(t, []string{"c", "d"}, out.Items)
+		assert.Equal(t, 2, out.Pagination.Page)
+		assert.Equal(t, 7, *out.Pagination.Total)
+		assert.Equal(t, 4, *out.Pagination.TotalPages)
+		assert.Equal(t, "7", rec.Header().Get("X-Total-Count"))
+		assert.Equal(t, `</users?active=true&page=1&per_page=2>; rel="first", `+
+			`</users?active=true&page=1&per_page=2>; rel="prev", `+
+			`</users?active=true&page=3&per_page=2>; rel="next", `+
+			`</users?active=true&page=4&per_page=2>; rel="last"`, rec.Header().Get("Link"))
+	})
+
+	t.Run("should not link to pages after the last one", func(t *testing.T) {
+		var (
+			rec = httptest.NewRecorder()
+			r   = httptest.NewRequest(http.MethodGet, "/users?page=4&per_page=2", nil)
+		)
+
+		p, err := BindPagination(r)
+		require.NoError(t, err)
+
+		SuccessPage(ctx, rec, r, p, Page[string]{Items: []string{"g"}, Total: 7})
+		assert.NotContains(t, rec.Header().Get("Link"), `rel="next"`)
+		assert.NotContains(t, rec.Header().Get("Link"), `rel="last"`)
+	})
+
+	t.Run("should link to the next cursor", func(t *testing.T) {
+		var (
+			rec = httptest.NewRecorder()
+			r   = httptest.NewRequest(http.MethodGet, "/events?cursor=a1", nil)
+		)
+
+		p, err := BindPagination(r)
+		require.NoError(t, err)
+
+		SuccessPage(ctx, rec, r, p, Page[int]{Items: []int{1, 2}, Total: TotalUnknown, NextCursor: "b2"})
+
+		assert.Equal(t, `</events?cursor=b2>; rel="next"`, rec.Header().Get("Link"))
+		assert.Empty(t, rec.Header().Get("X-Total-Count"))
+		assert.JSONEq(t, `{"items":[1,2],"pagination":{"per_page":20,"next_cursor":"b2"}}`, rec.Body.String())
+	})
+
+	t.Run("should write empty pages as empty lists", func(t *testing.T) {
+		var (
+			rec = httptest.NewRecorder()
+			r   = httptest.NewRequest(http.MethodGet, "/users", nil)
+		)
+
+		p, err := BindPagination(r)
+		require.NoError(t, err)
+
+		SuccessPage(ctx, rec, r, p, Page[string]{})
+		assert.JSONEq(t, `{"items":[],"pagination":{"page":1,"per_page":20,"total":0,"total_pages":0}}`, rec.Body.String())
+		assert.Empty(t, rec.Header().Get("Link"))
+	})
+}
@@ -0,0 +1,121 @@
+package http
+
+import (
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// RedirectOptions configures how Redirect responds.
+type RedirectOptions struct {
+	// HTTPStatusCode specifies the redirect status code. If zero, defaults to
+	// 303 See Other, which makes browsers follow the redirect with a GET
+	// request, as expected after submitting forms.
+	HTTPStatusCode int
+
+	// TrustedProxies are the networks, in CIDR notation, of the proxies in
+	// front of the service, e.g. "10.0.0.0/8". The X-Forwarded-Proto and
+	// X-Forwarded-Host headers are only used to build the redirect location
+	// when the request comes from one of them. Invalid networks are ignored.
+	TrustedProxies []string
+}
+
+// Redirect replies to the request with a redirect to location.
+//
+// Relative locations are turned into absolute URLs using the scheme and host
+// the client used to reach the service. When the request comes from one of
+// RedirectOptions.TrustedProxies, they are taken from the X-Forwarded-Proto
+// and X-Forwarded-Host headers, so that redirects keep working behind
+// TLS-terminating proxies.
+func Redirect(w http.ResponseWriter, r *http.Request, location string, options ...RedirectOptions) {
+	var redirectOpts RedirectOptions
+	if len(options) > 0 {
+		redirectOpts = options[0]
+	}
+	if redirectOpts.HTTPStatusCode == 0 {
+		redirectOpts.HTTPStatusCode = http.StatusSeeOther
+	}
+
+	w.Header().Set("Location", redirectLocation(r, location, redirectOpts))
+	w.WriteHeader(redirectOpts.HTTPStatusCode)
+}
+
+func redirectLocation(r *http.Request, location string, options RedirectOptions) string {
+	target, err := url.Parse(location)
+	if err != nil || target.IsAbs() || strings.HasPrefix(location, "//") {
+		return location
+	}
+
+	scheme, host := "http", r.Host
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if fromTrustedProxy(r, options.TrustedProxies) {
+		if proto := strings.ToLower(lastForwardedValue(r.Header.Values("X-Forwarded-Proto"))); proto == "http" || proto == "https" {
+			scheme = proto
+		}
+		if h := lastForwardedValue(r.Header.Values("X-Forwarded-Host")); h != "" {
+			host = h
+		}
+	}
+
+	base := &url.URL{
+		Scheme: scheme,
+		Host:   host,
+		Path:   originalURL(r).Path,
+	}
+
+	return base.ResolveReference(target).String()
+}
+
+// fromTrustedProxy reports whether the request peer is inside one of the
+// trusted networks.
+func fromTrustedProxy(r *http.Request, trustedProxies []string) bool {
+	if len(trustedProxies) == 0 {
+		return false
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+
+	for _, cidr := range trustedProxies {
+		if _, n, err := net.ParseCIDR(cidr); err == nil && n.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// lastForwardedValue returns the value appended by the proxy closest to the
+// service, which is the trusted one, when several proxies appended to a
+// forwarded header.
+func lastForwardedValue(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+
+	last := values[len(values)-1]
+	if i := strings.LastIndex(last, ","); i >= 0 {
+		last = last[i+1:]
+	}
+
+	return strings.TrimSpace(last)
+}
+
+// originalURL returns the URL requested by the client, before any prefix
+// was stripped from r.URL.Path by the runtime.
+func originalURL(r *http.Request) *url.URL {
+	if u, err := url.ParseRequestURI(r.RequestURI); err == nil && u.Path != "" {
+		return u
+	}
+
+	return r.URL
+}
@@ -0,0 +1,79 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedirect(t *testing.T) {
+	redirect := func(r *http.Request, location string, options ...RedirectOptions) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		Redirect(rec, r, location, options...)
+		return rec
+	}
+
+	t.Run("should build absolute locations from the request", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, "http://admin.local/users/new", nil)
+
+		rec := redirect(r, "/users")
+		assert.Equal(t, http.StatusSeeOther, rec.Code)
+		assert.Equal(t, "http://admin.local/users", rec.Header().Get("Location"))
+
+		rec = redirect(r, "42")
+		assert.Equal(t, "http://admin.local/users/42", rec.Header().Get("Location"))
+	})
+
+	t.Run("should respect forwarded headers from trusted proxies", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, "http://10.0.0.1/users/new", nil)
+		r.RemoteAddr = "10.1.2.3:40000"
+		r.Header.Set("X-Forwarded-Proto", "https")
+		r.Header.Set("X-Forwarded-Host", "evil.example.com, admin.example.com")
+
+		rec := redirect(r, "/users", RedirectOptions{TrustedProxies: []string{"10.0.0.0/8"}})
+		assert.Equal(t, "https://admin.example.com/users", rec.Header().Get("Location"))
+	})
+
+	t.Run("should ignore forwarded headers by default", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, "http://10.0.0.1/users/new", nil)
+		r.Header.Set("X-Forwarded-Proto", "https")
+		r.Header.Set("X-Forwarded-Host", "evil.example.com")
+
+		rec := redirect(r, "/users")
+		assert.Equal(t, "http://10.0.0.1/users", rec.Header().Get("Location"))
+
+		rec = redirect(r, "/users", RedirectOptions{TrustedProxies: []string{"10.0.0.0/8"}})
+		assert.Equal(t, "http://10.0.0.1/users", rec.Header().Get("Location"))
+	})
+
+	t.Run("should only accept http and https forwarded schemes", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, "http://10.0.0.1/users/new", nil)
+		r.RemoteAddr = "10.1.2.3:40000"
+		r.Header.Set("X-Forwarded-Proto", "javascript")
+
+		rec := redirect(r, "/users", RedirectOptions{TrustedProxies: []string{"10.0.0.0/8"}})
+		assert.Equal(t, "http://10.0.0.1/users", rec.Header().Get("Location"))
+	})
+
+	t.Run("should resolve relative locations against the original path", func(t *testing.T) {
+		var (
+			rec     = httptest.NewRecorder()
+			handler = http.StripPrefix("/api", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				Redirect(w, r, "42")
+			}))
+		)
+
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "http://admin.local/api/users/new", nil))
+		assert.Equal(t, "http://admin.local/api/users/42", rec.Header().Get("Location"))
+	})
+
+	t.Run("should keep absolute locations and custom status codes", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+		rec := redirect(r, "https://other.example.com/x", RedirectOptions{HTTPStatusCode: http.StatusFound})
+		assert.Equal(t, http.StatusFound, rec.Code)
+		assert.Equal(t, "https://other.example.com/x", rec.Header().Get("Location"))
+	})
+}
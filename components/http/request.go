@@ -1,33 +1,37 @@
 package http
 
 import (
+	"context"
 	"encoding"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
 	"reflect"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/go-playground/validator/v10"
 	"github.com/stoewer/go-strcase"
 )
 
 const (
-	defaultBindBodyMaxBytes int64 = 4 << 20 // 4MB as default
+	defaultBindBodyMaxBytes             int64 = 4 << 20  // 4MB as default
+	defaultBindBodyMaxDecompressedBytes int64 = 16 << 20 // 16MB as default
 )
 
 // Bind extracts and binds HTTP request parameters to a struct based on struct
-// field tags. It supports binding from multiple sources (path, query, headers)
-// based on the `http` struct tag.
+// field tags. It supports binding from multiple sources (path, query, headers,
+// cookies) based on the `http` struct tag.
 //
 // Struct fields must have an `http` tag with a location specifier:
 //   - `http:"loc=path"` - extract from URL path parameters
 //   - `http:"loc=query"` - extract from query string parameters
 //   - `http:"loc=header"` - extract from HTTP headers
+//   - `http:"loc=cookie"` - extract from request cookies
 //
 // Field names are resolved from the `json` tag, or fall back to the struct field name.
 //
@@ -41,21 +45,101 @@ const (
 //
 //	var params RequestParams
 //	err := Bind(r, &params)
-func Bind(r *http.Request, target interface{}) error {
-	o := getBindOptions()
+//
+// When BindOptions.EnableValidation is set, the target is validated after
+// being bound.
+//
+// Fields tagged with `http:"loc=body"` are decoded from a copy of the body and
+// converted again from their text representation. Use BindAll to decode the
+// body directly into the target.
+func Bind(r *http.Request, target interface{}, opts ...*BindOptions) error {
+	o := getBindOptions(r, opts...)
 
 	b, err := newBinder(r, target, &o)
 	if err != nil {
 		return err
 	}
 
-	for i := 0; i < b.rt.NumField(); i++ {
-		if err := b.bindField(i); err != nil {
+	if errs, ok := bindGenerated(r, target, &o, ""); ok {
+		return finishBind(r, target, &o, nil, errs)
+	}
+
+	var (
+		errs       BindErrors
+		bodyErr    error
+		bodyFailed bool
+	)
+
+	err = walkFields(b.rv, &o, "", "", nil, func(f *boundField) error {
+		if bodyFailed && f.tag != nil && f.tag.Location == "body" {
+			return nil
+		}
+
+		if err := b.bindField(f); err != nil && !errs.collect(err) {
+			if !o.CollectErrors || f.tag.Location != "body" {
+				return err
+			}
+
+			// Like BindAll, requests without body are not a failure.
+			bodyFailed = true
+			if !errors.Is(err, io.EOF) {
+				bodyErr = err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return finishBind(r, target, &o, bodyErr, errs)
+}
+
+// finishBind gives back the result of a binding call that found errs,
+// validating its target when everything was bound. With
+// BindOptions.CollectErrors, the target is validated anyway and all errors,
+// including bodyErr, are returned together.
+func finishBind(r *http.Request, target interface{}, opt *BindOptions, bodyErr error, errs BindErrors) error {
+	opt.FailureStats.observe(r, errs)
+
+	if !opt.CollectErrors {
+		if err := errs.err(); err != nil {
 			return err
 		}
+
+		return validateTarget(target, opt)
 	}
 
-	return nil
+	collected := &CollectedErrors{
+		Body:       bodyErr,
+		Bind:       errs,
+		Validation: withoutBindErrors(validateTarget(target, opt), errs),
+	}
+
+	return collected.err()
+}
+
+// withoutBindErrors removes from a validation error the fields that could
+// not be bound, which would be reported twice, e.g., as invalid and as
+// required.
+func withoutBindErrors(err error, errs BindErrors) error {
+	var fieldErrors FieldErrors
+	if len(errs) == 0 || !errors.As(err, &fieldErrors) {
+		return err
+	}
+
+	out := make(FieldErrors)
+	for field, messages := range fieldErrors {
+		if !slices.ContainsFunc(errs, func(e *BindError) bool { return e.Field == field }) {
+			out[field] = messages
+		}
+	}
+	if len(out) == 0 {
+		return nil
+	}
+
+	return out
 }
 
 type binder struct {
@@ -92,46 +176,67 @@ func validateBindTarget(target interface{}) (reflect.Value, reflect.Type, error)
 	return rv, rv.Type(), nil
 }
 
-func (b *binder) bindField(index int) error {
-	sf := b.rt.Field(index)
-	fv := b.rv.Field(index)
-
-	if !fv.CanSet() {
+func (b *binder) bindField(f *boundField) error {
+	if f.tag == nil {
+		// No tag, skip field
 		return nil
 	}
 
-	name, ok := resolveFieldName(sf, b.opt.FallbackSnakeCase)
-	if !ok {
-		return nil
-	}
-
-	tag, err := parseBindTag(sf.Tag)
-	if err != nil || tag == nil {
-		return err
+	if f.tag.Location == "body" {
+		return b.bindFromBody(f)
 	}
 
-	if tag.Location == "body" {
-		return b.bindFromBody(index, sf, fv)
+	if isMapField(f.sf.Type) {
+		return b.bindMap(f)
 	}
 
-	return b.bindFromExtractor(name, tag.Location, sf, fv)
+	return b.bindFromExtractor(f.name, f.tag, f.fv)
 }
 
-func (b *binder) bindFromBody(index int, sf reflect.StructField, fv reflect.Value) error {
+func (b *binder) bindFromBody(f *boundField) error {
 	if err := b.ensureBodyParsed(); err != nil {
 		return err
 	}
 
-	bf := reflect.ValueOf(b.bodyParsed).Elem().Field(index)
-	if isZeroValue(bf) {
+	bf, err := reflect.ValueOf(b.bodyParsed).Elem().FieldByIndexErr(f.index)
+	if err != nil || isZeroValue(bf) {
+		values, err := f.tag.missing(f.tag.Location, f.name)
+		if err != nil || len(values) == 0 {
+			return err
+		}
+
+		return setBoundValues(f.fv, f.tag, f.name, f.tag.Location, values, b.opt)
+	}
+
+	// Nested structs and maps are decoded from the body as a whole.
+	if isNestedStruct(f.sf.Type) || isMapField(f.sf.Type) {
+		f.fv.Set(bf)
 		return nil
 	}
 
-	return setFieldValues(fv, sf, []string{
+	return setBoundValues(f.fv, f.tag, f.name, f.tag.Location, []string{
 		fmt.Sprintf("%v", bf.Interface()),
 	}, b.opt)
 }
 
+// bindMap binds a map field from the query parameters or headers using its
+// name as prefix. Maps are not bound from other locations.
+func (b *binder) bindMap(f *boundField) error {
+	switch f.tag.Location {
+	case "query":
+		q := b.r.URL.Query()
+		return bindMapParameters(f, b.opt, f.tag.Location, func(name string) map[string][]string {
+			return mapValues(q, name)
+		})
+	case "header":
+		return bindMapParameters(f, b.opt, f.tag.Location, func(name string) map[string][]string {
+			return headerMapValues(b.r.Header, b.opt.HeaderPrefix+name, b.opt.LowercaseHeaderKeys)
+		})
+	}
+
+	return nil
+}
+
 func (b *binder) ensureBodyParsed() error {
 	if b.bodyParsed != nil {
 		return nil
@@ -147,16 +252,78 @@ func (b *binder) ensureBodyParsed() error {
 }
 
 func (b *binder) bindFromExtractor(
-	name, location string,
-	sf reflect.StructField,
+	name string,
+	tag *bindTag,
 	fv reflect.Value,
 ) error {
-	val := extractor(location, name, b.r)
+	val := extractor(tag.Location, parameterName(tag.Location, name, b.opt), b.r)
 	if val == "" {
-		return nil
+		values, err := tag.missing(tag.Location, name)
+		if err != nil || len(values) == 0 {
+			return err
+		}
+
+		return setBoundValues(fv, tag, name, tag.Location, values, b.opt)
+	}
+
+	return setBoundValues(fv, tag, name, tag.Location, []string{val}, b.opt)
+}
+
+// parameterName gives back the name a parameter is looked up by, which, for
+// headers, carries the BindOptions.HeaderPrefix.
+func parameterName(location, name string, opt *BindOptions) string {
+	if location == "header" {
+		return opt.HeaderPrefix + name
 	}
 
-	return setFieldValues(fv, sf, []string{val}, b.opt)
+	return name
+}
+
+// setBoundValues sets values into a field, reporting conversion errors as a
+// BindError.
+func setBoundValues(
+	fv reflect.Value,
+	tag *bindTag,
+	name, location string,
+	values []string,
+	opt *BindOptions,
+) error {
+	if err := checkValueLimits(values, opt); err != nil {
+		return newBindError(name, location, nil, err)
+	}
+
+	if err := setFieldValues(fv, tag, values, opt); err != nil {
+		if isLimitError(err) {
+			values = nil
+		}
+
+		return newBindError(name, location, values, err)
+	}
+
+	return nil
+}
+
+// checkValueLimits checks values against BindOptions.MaxSliceLen and
+// BindOptions.MaxValueLen, so abusive parameters are rejected before being
+// converted.
+func checkValueLimits(values []string, opt *BindOptions) error {
+	if opt.MaxSliceLen > 0 && len(values) > opt.MaxSliceLen {
+		return tooManyValuesError(opt)
+	}
+
+	if opt.MaxValueLen > 0 {
+		for _, v := range values {
+			if len(v) > opt.MaxValueLen {
+				return fmt.Errorf("%w, at most %d bytes are accepted", ErrValueTooLong, opt.MaxValueLen)
+			}
+		}
+	}
+
+	return nil
+}
+
+func tooManyValuesError(opt *BindOptions) error {
+	return fmt.Errorf("%w, at most %d are accepted", ErrTooManyValues, opt.MaxSliceLen)
 }
 
 func extractor(location, name string, r *http.Request) string {
@@ -167,6 +334,11 @@ func extractor(location, name string, r *http.Request) string {
 		return r.URL.Query().Get(name)
 	case "header":
 		return r.Header.Get(name)
+	case "cookie":
+		if c, err := r.Cookie(name); err == nil {
+			return c.Value
+		}
+		return ""
 	default:
 		return ""
 	}
@@ -182,13 +354,23 @@ type BindBodyOptions struct {
 	// default.
 	MaxBytes int64
 
+	// MaxDecompressedBytes limits the size of compressed request bodies once
+	// decompressed, guarding against decompression bombs. Zero value uses the
+	// internal default or MaxBytes, whichever is larger.
+	MaxDecompressedBytes int64
+
 	// DisallowUnknownFields reject JSON with fields not present in the target
 	// struct.
 	DisallowUnknownFields bool
 }
 
-// BindBody decodes a JSON request body into a target struct. It supports
-// optional limits on body size and strict field validation.
+// BindBody decodes a request body into a target struct, using the decoder
+// registered for the request Content-Type. JSON, XML and protobuf bodies are
+// supported by default, requests without Content-Type are decoded as JSON and
+// custom media types can be added with RegisterBodyDecoder. Bodies sent with
+// a gzip, deflate or br Content-Encoding are decompressed before being
+// decoded. It supports optional limits on body size and strict field
+// validation.
 func BindBody(r *http.Request, target interface{}, options ...BindBodyOptions) error {
 	var bindOpts BindBodyOptions
 	if len(options) > 0 {
@@ -197,44 +379,68 @@ func BindBody(r *http.Request, target interface{}, options ...BindBodyOptions) e
 	if bindOpts.MaxBytes <= 0 {
 		bindOpts.MaxBytes = defaultBindBodyMaxBytes
 	}
+	if bindOpts.MaxDecompressedBytes <= 0 {
+		bindOpts.MaxDecompressedBytes = max(defaultBindBodyMaxDecompressedBytes, bindOpts.MaxBytes)
+	}
+
+	decode, err := lookupBodyDecoder(r.Header.Get("Content-Type"))
+	if err != nil {
+		return err
+	}
 
 	// Reject immediately if the body is too large
 	if r.ContentLength > bindOpts.MaxBytes && r.ContentLength != -1 {
 		return fmt.Errorf("request body exceeds %d bytes", bindOpts.MaxBytes)
 	}
 
-	var (
-		limitReader = &io.LimitedReader{
-			R: r.Body,
-			N: bindOpts.MaxBytes + 1,
-		}
+	limitReader := &io.LimitedReader{
+		R: r.Body,
+		N: bindOpts.MaxBytes + 1,
+	}
 
-		body = io.Reader(limitReader)
-	)
+	var body io.Reader = limitReader
+	if encoding := r.Header.Get("Content-Encoding"); encoding != "" {
+		decompressed, closeFn, err := decodeContent(limitReader, encoding)
+		if err != nil {
+			if limitReader.N == 0 {
+				return fmt.Errorf("request body exceeds %d bytes", bindOpts.MaxBytes)
+			}
 
-	dec := json.NewDecoder(body)
-	if bindOpts.DisallowUnknownFields {
-		dec.DisallowUnknownFields()
+			return err
+		}
+		defer closeFn()
+
+		body = decompressed
 	}
 
-	if err := dec.Decode(target); err != nil {
-		if limitReader.N == 0 {
-			return fmt.Errorf("request body exceeds %d bytes", bindOpts.MaxBytes)
-		}
+	decompressedReader := &io.LimitedReader{
+		R: body,
+		N: bindOpts.MaxDecompressedBytes + 1,
+	}
 
-		return err
+	if err := decode(decompressedReader, target, bindOpts); err != nil {
+		return bodyLimitError(limitReader, decompressedReader, &bindOpts, err)
 	}
 
-	// Ensure we're dealing with a single JSON
-	if err := dec.Decode(&struct{}{}); err != io.EOF {
-		return errors.New("expected only one JSON object in request body")
+	return bodyLimitError(limitReader, decompressedReader, &bindOpts, nil)
+}
+
+// bodyLimitError gives back the error of a request body that exceeded one
+// of its limits or, otherwise, err.
+func bodyLimitError(body, decompressed *io.LimitedReader, options *BindBodyOptions, err error) error {
+	if body.N == 0 {
+		return fmt.Errorf("request body exceeds %d bytes", options.MaxBytes)
+	}
+	if decompressed.N == 0 {
+		return fmt.Errorf("decompressed request body exceeds %d bytes", options.MaxDecompressedBytes)
 	}
 
-	return nil
+	return err
 }
 
 type (
 	parameterExtractor func(name string) ([]string, bool)
+	mapExtractor       func(name string) map[string][]string
 
 	// PathGetter defines a function type for extracting path parameters from
 	// HTTP requests. Implementations should return the parameter value and a
@@ -272,16 +478,121 @@ type BindOptions struct {
 
 	// DefaultTimeLayout specifies the time format for parsing time.Time fields.
 	// Can be overridden per-field using `http:"time_format=..."` struct tags.
-	// It defaults to time.RFC3339.
+	// It accepts the same values as the tag option and defaults to
+	// time.RFC3339.
 	DefaultTimeLayout string
 
 	// EnableTextUnmarshaler enables support for types implementing
 	// encoding.TextUnmarshaler. Default is true.
 	EnableTextUnmarshaler bool
+
+	// SupportedLocales lists the locales that Locale fields can be negotiated
+	// to. When empty, the most preferred locale sent by the client is used.
+	SupportedLocales []string
+
+	// DefaultLocale is the locale assigned to Locale fields when none of the
+	// client locales is supported.
+	DefaultLocale string
+
+	// MultipartMaxMemory is the number of bytes of a multipart form kept in
+	// memory by BindMultipart, the remainder is stored in temporary files.
+	// Defaults to 32MB.
+	MultipartMaxMemory int64
+
+	// MaxFileBytes limits the size of each file bound by BindMultipart. File
+	// parts are not read past it, so larger files are rejected without being
+	// stored. Zero means no limit.
+	MaxFileBytes int64
+
+	// AllowedContentTypes restricts the content types of files bound by
+	// BindMultipart. Entries can use a wildcard subtype, e.g. "image/*".
+	// When empty, any content type is accepted.
+	AllowedContentTypes []string
+
+	// EnableValidation validates the target after it is bound, checking its
+	// `validate` tags with go-playground/validator and calling its Validate
+	// method, if it implements Validator. Tag errors are returned as
+	// FieldErrors.
+	EnableValidation bool
+
+	// Validator replaces the validator instance used when EnableValidation is
+	// set. The default one reports fields by their binding names.
+	Validator *validator.Validate
+
+	// Body configures how BindAll decodes the request body.
+	Body BindBodyOptions
+
+	// HeaderPrefix is prepended to the names of header fields when looking
+	// them up, e.g., with "X-App-", a `user-id` field is bound from the
+	// X-App-User-Id header. Header map fields also use it in their prefix.
+	HeaderPrefix string
+
+	// LowercaseHeaderKeys makes header map fields use lower-case keys instead
+	// of the canonical form of the header names, e.g., "trace-id" instead of
+	// "Trace-Id".
+	LowercaseHeaderKeys bool
+
+	// MaxSliceLen limits how many values a parameter can have, including the
+	// ones split from a CSV value and the entries of map fields. Zero means
+	// no limit.
+	MaxSliceLen int
+
+	// MaxValueLen limits the length, in bytes, of every parameter value and
+	// map key. Zero means no limit.
+	MaxValueLen int
+
+	// CollectErrors makes binding go on after failures that would stop it,
+	// like a body that BindAll cannot decode, and validate targets even when
+	// some of their parameters could not be bound. All problems are then
+	// returned together as a *CollectedErrors, while the target keeps
+	// everything that was bound.
+	CollectErrors bool
+
+	// FailureStats, if set, counts the parameters that could not be bound,
+	// per route and field.
+	FailureStats *BindFailureStats
 }
 
-func getBindOptions(opts ...*BindOptions) BindOptions {
-	if len(opts) == 0 || opts[0] == nil {
+type bindOptionsContextKey struct{}
+
+// BindOptionsContextKey is the key under which the default BindOptions of a
+// service are stored inside request contexts. Runtimes using contexts that
+// don't support context.WithValue, such as fasthttp.RequestCtx, can store
+// them directly as a user value with it.
+var BindOptionsContextKey = bindOptionsContextKey{}
+
+// NewBindOptionsContext returns a copy of ctx carrying opts, which are used
+// by the binding functions called without options for requests with ctx.
+// Service runtimes store the DefaultBindOptions of their service options
+// this way, so services sharing a process don't share their options.
+func NewBindOptionsContext(ctx context.Context, opts *BindOptions) context.Context {
+	return context.WithValue(ctx, BindOptionsContextKey, opts)
+}
+
+// BindOptionsFromContext retrieves the BindOptions stored inside ctx.
+func BindOptionsFromContext(ctx context.Context) (*BindOptions, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+
+	opts, ok := ctx.Value(BindOptionsContextKey).(*BindOptions)
+	return opts, ok && opts != nil
+}
+
+// getBindOptions gives back the options of a binding call, which are the
+// ones given to it or the ones of the request context, in this order.
+func getBindOptions(r *http.Request, opts ...*BindOptions) BindOptions {
+	var base *BindOptions
+	if r != nil {
+		if o, ok := BindOptionsFromContext(r.Context()); ok {
+			base = o
+		}
+	}
+	if len(opts) > 0 && opts[0] != nil {
+		base = opts[0]
+	}
+
+	if base == nil {
 		return BindOptions{
 			FallbackSnakeCase:     false,
 			SplitSingleCSV:        true,
@@ -292,7 +603,7 @@ func getBindOptions(opts ...*BindOptions) BindOptions {
 		}
 	}
 
-	o := *opts[0]
+	o := *base
 
 	// Set some default options if not set by the caller.
 	if o.CSVSeparator == 0 {
@@ -310,16 +621,19 @@ func getBindOptions(opts ...*BindOptions) BindOptions {
 
 // BindQuery extracts query string parameters and binds them to a struct. It
 // supports multiple values for the same parameter name, which will be bound
-// to slice fields.
+// to slice fields, and parameters like "name[key]" or "name.key", which will
+// be bound to map fields.
 func BindQuery(r *http.Request, target interface{}, opts ...*BindOptions) error {
 	var (
-		o = getBindOptions(opts...)
+		o = getBindOptions(r, opts...)
 		q = r.URL.Query()
 	)
 
-	return bindParameters(target, &o, func(name string) ([]string, bool) {
+	return bindParameters(r, target, &o, "query", func(name string) ([]string, bool) {
 		v, ok := valuesLookup(q, name)
 		return v, ok
+	}, func(name string) map[string][]string {
+		return mapValues(q, name)
 	})
 }
 
@@ -337,26 +651,52 @@ func valuesLookup(q url.Values, name string) ([]string, bool) {
 
 // BindHeader extracts HTTP headers and binds them to a struct. Header names are
 // case-insensitive as per HTTP specification.
+// Map fields receive every header starting with their name, e.g., an
+// `x-vendor` map is bound from X-Vendor-Trace-Id and X-Vendor-Region, keyed by
+// "Trace-Id" and "Region".
 func BindHeader(r *http.Request, target interface{}, opts ...*BindOptions) error {
 	var (
-		o = getBindOptions(opts...)
+		o = getBindOptions(r, opts...)
 		h = r.Header
 	)
 
-	return bindParameters(target, &o, func(name string) ([]string, bool) {
-		if v := h.Values(name); len(v) > 0 {
+	return bindParameters(r, target, &o, "header", func(name string) ([]string, bool) {
+		if v := h.Values(o.HeaderPrefix + name); len(v) > 0 {
 			return v, true
 		}
 
 		return nil, false
+	}, func(name string) map[string][]string {
+		return headerMapValues(h, o.HeaderPrefix+name, o.LowercaseHeaderKeys)
+	})
+}
+
+// BindCookie extracts request cookies and binds them to a struct. When the
+// request carries more than one cookie with the same name, all of their values
+// are bound to slice fields.
+func BindCookie(r *http.Request, target interface{}, opts ...*BindOptions) error {
+	o := getBindOptions(r, opts...)
+
+	return bindParameters(r, target, &o, "cookie", func(name string) ([]string, bool) {
+		cookies := r.CookiesNamed(name)
+		if len(cookies) == 0 {
+			return nil, false
+		}
+
+		values := make([]string, 0, len(cookies))
+		for _, c := range cookies {
+			values = append(values, c.Value)
+		}
+
+		return values, true
 	})
 }
 
 // BindPath extracts URL path parameters and binds them to a struct.
 func BindPath(r *http.Request, target interface{}, opts ...*BindOptions) error {
-	o := getBindOptions(opts...)
+	o := getBindOptions(r, opts...)
 
-	return bindParameters(target, &o, func(name string) ([]string, bool) {
+	return bindParameters(r, target, &o, "path", func(name string) ([]string, bool) {
 		if v, ok := o.PathGetter(r, name); ok {
 			return []string{v}, true
 		}
@@ -365,43 +705,73 @@ func BindPath(r *http.Request, target interface{}, opts ...*BindOptions) error {
 	})
 }
 
-func bindParameters(target interface{}, opt *BindOptions, extractor parameterExtractor) error {
+// bindParameters binds the parameters of a location into target. Map fields
+// are only bound when mapExtractor is given.
+func bindParameters(
+	r *http.Request,
+	target interface{},
+	opt *BindOptions,
+	location string,
+	extractor parameterExtractor,
+	mapExtractor ...mapExtractor,
+) error {
 	v := reflect.ValueOf(target)
 	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
 		return errors.New("target must be a pointer to a struct")
 	}
 
-	var (
-		rv = v.Elem()
-		rt = rv.Type()
-	)
+	if errs, ok := bindGenerated(r, target, opt, location); ok {
+		return finishBind(r, target, opt, nil, errs)
+	}
 
-	for i := 0; i < rt.NumField(); i++ {
-		var (
-			sf = rt.Field(i)
-			fv = rv.Field(i)
-		)
+	var errs BindErrors
+	err := walkFields(v.Elem(), opt, "", "", nil, func(f *boundField) error {
+		if isMapField(f.sf.Type) {
+			if len(mapExtractor) == 0 {
+				return nil
+			}
 
-		if !fv.CanSet() {
-			continue // unexported
-		}
+			if err := bindMapParameters(f, opt, location, mapExtractor[0]); err != nil {
+				errs.collect(err)
+			}
 
-		name, ok := resolveFieldName(sf, opt.FallbackSnakeCase)
-		if !ok {
-			continue // e.g. json:"-"
+			return nil
 		}
 
-		values, ok := extractor(name)
+		values, ok := extractor(f.name)
 		if !ok || len(values) == 0 {
-			continue
+			var err error
+			values, err = f.tag.missing(location, f.name)
+			if err != nil {
+				errs.collect(err)
+				return nil
+			}
+			if len(values) == 0 {
+				return nil
+			}
 		}
 
-		if err := setFieldValues(fv, sf, values, opt); err != nil {
-			return err
+		if err := setBoundValues(f.fv, f.tag, f.name, location, values, opt); err != nil {
+			errs.collect(err)
 		}
+
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
-	return nil
+	return finishBind(r, target, opt, nil, errs)
+}
+
+func bindMapParameters(f *boundField, opt *BindOptions, location string, extractor mapExtractor) error {
+	entries := extractor(f.name)
+	if len(entries) == 0 {
+		_, err := f.tag.missing(location, f.name)
+		return err
+	}
+
+	return setMapValues(f.fv, f.tag, f.name, location, entries, opt)
 }
 
 func resolveFieldName(sf reflect.StructField, useSnakeCase bool) (string, bool) {
@@ -422,7 +792,17 @@ func resolveFieldName(sf reflect.StructField, useSnakeCase bool) (string, bool)
 	return strings.ToLower(sf.Name), true
 }
 
-func setFieldValues(field reflect.Value, sf reflect.StructField, values []string, opt *BindOptions) error {
+func setFieldValues(field reflect.Value, tag *bindTag, values []string, opt *BindOptions) error {
+	// Registered binders, which can also be registered for pointer and
+	// slice types.
+	if fn, ok := lookupBinder(field.Type()); ok {
+		if len(values) == 0 {
+			return nil
+		}
+
+		return setBinderValue(field, fn, values[0])
+	}
+
 	// pointers
 	if field.Kind() == reflect.Ptr {
 		if field.IsNil() {
@@ -430,7 +810,7 @@ func setFieldValues(field reflect.Value, sf reflect.StructField, values []string
 			field.Set(reflect.New(field.Type().Elem()))
 		}
 
-		return setFieldValues(field.Elem(), sf, values, opt)
+		return setFieldValues(field.Elem(), tag, values, opt)
 	}
 
 	// slices
@@ -438,6 +818,9 @@ func setFieldValues(field reflect.Value, sf reflect.StructField, values []string
 		if opt.SplitSingleCSV && len(values) == 1 && strings.ContainsRune(values[0], opt.CSVSeparator) {
 			values = stringsSplitAndTrimRune(values[0], opt.CSVSeparator)
 		}
+		if opt.MaxSliceLen > 0 && len(values) > opt.MaxSliceLen {
+			return tooManyValuesError(opt)
+		}
 
 		var (
 			elem = field.Type().Elem()
@@ -446,7 +829,7 @@ func setFieldValues(field reflect.Value, sf reflect.StructField, values []string
 
 		for _, s := range values {
 			ev := reflect.New(elem).Elem()
-			if err := setScalarValue(ev, sf, s, opt); err != nil {
+			if err := setScalarValue(ev, tag, s, opt); err != nil {
 				return err
 			}
 			out = reflect.Append(out, ev)
@@ -458,7 +841,7 @@ func setFieldValues(field reflect.Value, sf reflect.StructField, values []string
 
 	// scalar
 	if len(values) > 0 {
-		return setScalarValue(field, sf, values[0], opt)
+		return setScalarValue(field, tag, values[0], opt)
 	}
 
 	return nil
@@ -479,7 +862,18 @@ func stringsSplitAndTrimRune(s string, sep rune) []string {
 	return result
 }
 
-func setScalarValue(field reflect.Value, sf reflect.StructField, value string, opt *BindOptions) error {
+func setScalarValue(field reflect.Value, tag *bindTag, value string, opt *BindOptions) error {
+	// Registered binders
+	if fn, ok := lookupBinder(field.Type()); ok {
+		return setBinderValue(field, fn, value)
+	}
+
+	// time.Time, which is handled before encoding.TextUnmarshaler so its
+	// layouts can be chosen.
+	if field.Type() == timeType {
+		return setScalarTimeField(field, tag, value, opt)
+	}
+
 	// encoding.TextUnmarshaler
 	if opt.EnableTextUnmarshaler && field.CanAddr() && field.Addr().Type().Implements(textUnmarshalerType) {
 		return field.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(value))
@@ -490,9 +884,9 @@ func setScalarValue(field reflect.Value, sf reflect.StructField, value string, o
 		return setScalarDurationField(field, value)
 	}
 
-	// time.Time
-	if field.Type() == timeType {
-		return setScalarTimeField(field, sf, value, opt)
+	// Locale
+	if field.Type() == localeType {
+		return setScalarLocaleField(field, value, opt)
 	}
 
 	return setScalarField(field, value)
@@ -508,17 +902,15 @@ func setScalarDurationField(field reflect.Value, value string) error {
 	return nil
 }
 
-func setScalarTimeField(field reflect.Value, sf reflect.StructField, value string, opt *BindOptions) error {
-	tag, err := parseBindTag(sf.Tag)
-	if err != nil {
-		return err
-	}
-	layout := opt.DefaultTimeLayout
-	if tag != nil && tag.TimeFormat != "" {
-		layout = tag.TimeFormat
+// setScalarTimeField parses value with the layouts of the field tag, which
+// comes from the field cache, or with the default one.
+func setScalarTimeField(field reflect.Value, tag *bindTag, value string, opt *BindOptions) error {
+	layouts := []string{opt.DefaultTimeLayout}
+	if tag != nil && len(tag.TimeFormats) > 0 {
+		layouts = tag.TimeFormats
 	}
 
-	t, err := time.Parse(layout, value)
+	t, err := parseTime(value, layouts)
 	if err != nil {
 		return err
 	}
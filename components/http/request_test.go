@@ -1,6 +1,7 @@
 package http
 
 import (
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -107,6 +108,129 @@ func TestBind(t *testing.T) {
 		assert.Equal(t, "123", v.ID)
 		assert.Equal(t, "", v.internal)
 	})
+
+	t.Run("should bind prefixed headers", func(t *testing.T) {
+		var (
+			r = httptest.NewRequest(http.MethodGet, "/users", nil)
+			v = struct {
+				UserID string            `json:"user-id" http:"loc=header"`
+				Vendor map[string]string `json:"vendor" http:"loc=header"`
+			}{}
+		)
+
+		r.Header.Set("X-App-User-Id", "42")
+		r.Header.Set("X-App-Vendor-Region", "eu")
+
+		err := Bind(r, &v, &BindOptions{HeaderPrefix: "X-App-"})
+		require.NoError(t, err)
+		assert.Equal(t, "42", v.UserID)
+		assert.Equal(t, map[string]string{"Region": "eu"}, v.Vendor)
+	})
+}
+
+func TestBindRequiredAndDefault(t *testing.T) {
+	t.Run("should fail when a required parameter is missing", func(t *testing.T) {
+		var (
+			r = httptest.NewRequest(http.MethodGet, "/", nil)
+			v = struct {
+				Limit int `json:"limit" http:"loc=query,required"`
+			}{}
+		)
+
+		err := Bind(r, &v)
+		assert.EqualError(t, err, "missing required query parameter 'limit'")
+	})
+
+	t.Run("should fill defaults for absent parameters", func(t *testing.T) {
+		var (
+			r = httptest.NewRequest(http.MethodGet, "/?limit=5", nil)
+			v = struct {
+				Limit  int      `json:"limit" http:"loc=query,default=10"`
+				Offset int      `json:"offset" http:"loc=query,default=20"`
+				Sort   []string `json:"sort" http:"loc=query,default=name"`
+				Lang   string   `json:"x-lang" http:"loc=header,default=en"`
+			}{}
+		)
+
+		err := Bind(r, &v)
+		require.NoError(t, err)
+		assert.Equal(t, 5, v.Limit)
+		assert.Equal(t, 20, v.Offset)
+		assert.Equal(t, []string{"name"}, v.Sort)
+		assert.Equal(t, "en", v.Lang)
+	})
+
+	t.Run("should honor tag options in specialized binders", func(t *testing.T) {
+		var (
+			r = httptest.NewRequest(http.MethodGet, "/", nil)
+			v = struct {
+				Page  int    `json:"page" http:"default=1"`
+				Token string `json:"token" http:"loc=header,required"`
+			}{}
+		)
+
+		err := BindQuery(r, &v)
+		require.NoError(t, err)
+		assert.Equal(t, 1, v.Page)
+
+		err = BindHeader(r, &v)
+		assert.EqualError(t, err, "missing required header parameter 'token'")
+	})
+
+	t.Run("should reject invalid default values", func(t *testing.T) {
+		var (
+			r = httptest.NewRequest(http.MethodGet, "/", nil)
+			v = struct {
+				Limit int `json:"limit" http:"loc=query,default=ten"`
+			}{}
+		)
+
+		err := Bind(r, &v)
+		assert.Error(t, err)
+	})
+
+	t.Run("should reject required and default together", func(t *testing.T) {
+		var (
+			r = httptest.NewRequest(http.MethodGet, "/", nil)
+			v = struct {
+				Limit int `json:"limit" http:"loc=query,required,default=1"`
+			}{}
+		)
+
+		err := Bind(r, &v)
+		assert.Error(t, err)
+	})
+
+	t.Run("should use defaults from the environment", func(t *testing.T) {
+		t.Setenv("MIKROS_TEST_PAGE_SIZE", "50")
+
+		var (
+			r = httptest.NewRequest(http.MethodGet, "/?env_sort=date", nil)
+			v = struct {
+				EnvPageSize int    `json:"env_page_size" http:"loc=query,default_env=MIKROS_TEST_PAGE_SIZE,default=10"`
+				EnvMode     string `json:"env_mode" http:"loc=query,default_env=MIKROS_TEST_UNSET_MODE,default=fast"`
+				EnvSort     string `json:"env_sort" http:"loc=query,default_env=MIKROS_TEST_PAGE_SIZE"`
+			}{}
+		)
+
+		err := Bind(r, &v)
+		require.NoError(t, err)
+		assert.Equal(t, 50, v.EnvPageSize)
+		assert.Equal(t, "fast", v.EnvMode)
+		assert.Equal(t, "date", v.EnvSort)
+	})
+
+	t.Run("should reject required and default_env together", func(t *testing.T) {
+		var (
+			r = httptest.NewRequest(http.MethodGet, "/", nil)
+			v = struct {
+				Limit int `json:"limit" http:"loc=query,required,default_env=LIMIT"`
+			}{}
+		)
+
+		err := Bind(r, &v)
+		assert.Error(t, err)
+	})
 }
 
 func TestBindBody(t *testing.T) {
@@ -484,6 +608,123 @@ func TestBindHeader(t *testing.T) {
 		require.NoError(t, err)
 		assert.Equal(t, []string{"application/json", "text/html"}, v.Accept)
 	})
+
+	t.Run("should strip the header prefix", func(t *testing.T) {
+		var (
+			r = httptest.NewRequest(http.MethodGet, "/", nil)
+			v = struct {
+				UserID string `json:"user-id"`
+				Tenant string `json:"tenant"`
+			}{}
+		)
+
+		r.Header.Set("X-App-User-Id", "42")
+		r.Header.Set("Tenant", "ignored")
+
+		err := BindHeader(r, &v, &BindOptions{HeaderPrefix: "X-App-"})
+		require.NoError(t, err)
+		assert.Equal(t, "42", v.UserID)
+		assert.Empty(t, v.Tenant)
+	})
+
+	t.Run("should bind prefixed headers into maps", func(t *testing.T) {
+		var (
+			r = httptest.NewRequest(http.MethodGet, "/", nil)
+			v = struct {
+				Vendor map[string]string   `json:"x-vendor"`
+				Tags   map[string][]string `json:"tag"`
+			}{}
+		)
+
+		r.Header.Set("X-Vendor-Trace-Id", "abc")
+		r.Header["x-vendor-region"] = []string{"eu"}
+		r.Header.Set("X-Vendor", "ignored")
+		r.Header.Add("X-App-Tag-Env", "prod")
+		r.Header.Add("X-App-Tag-Env", "blue")
+
+		err := BindHeader(r, &v)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"Trace-Id": "abc", "Region": "eu"}, v.Vendor)
+		assert.Nil(t, v.Tags)
+
+		v.Vendor, v.Tags = nil, nil
+		err = BindHeader(r, &v, &BindOptions{HeaderPrefix: "X-App-", LowercaseHeaderKeys: true})
+		require.NoError(t, err)
+		assert.Equal(t, map[string][]string{"env": {"prod", "blue"}}, v.Tags)
+		assert.Nil(t, v.Vendor)
+	})
+}
+
+func TestBindCookie(t *testing.T) {
+	t.Run("should bind cookies with type conversion", func(t *testing.T) {
+		var (
+			r = httptest.NewRequest(http.MethodGet, "/", nil)
+			v = struct {
+				Session string        `json:"session"`
+				Visits  int           `json:"visits"`
+				TTL     time.Duration `json:"ttl"`
+				Missing *string       `json:"missing"`
+			}{}
+		)
+
+		r.AddCookie(&http.Cookie{Name: "session", Value: "abc123"})
+		r.AddCookie(&http.Cookie{Name: "visits", Value: "7"})
+		r.AddCookie(&http.Cookie{Name: "ttl", Value: "5m"})
+
+		err := BindCookie(r, &v)
+		require.NoError(t, err)
+		assert.Equal(t, "abc123", v.Session)
+		assert.Equal(t, 7, v.Visits)
+		assert.Equal(t, 5*time.Minute, v.TTL)
+		assert.Nil(t, v.Missing)
+	})
+
+	t.Run("should bind repeated cookies to slices", func(t *testing.T) {
+		var (
+			r = httptest.NewRequest(http.MethodGet, "/", nil)
+			v = struct {
+				Prefs []string `json:"pref"`
+			}{}
+		)
+
+		r.AddCookie(&http.Cookie{Name: "pref", Value: "dark"})
+		r.AddCookie(&http.Cookie{Name: "pref", Value: "compact"})
+
+		err := BindCookie(r, &v)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"dark", "compact"}, v.Prefs)
+	})
+
+	t.Run("should bind cookies with Bind", func(t *testing.T) {
+		var (
+			r = httptest.NewRequest(http.MethodGet, "/?limit=10", nil)
+			v = struct {
+				Session string `json:"session" http:"loc=cookie"`
+				Limit   int    `json:"limit" http:"loc=query"`
+			}{}
+		)
+
+		r.AddCookie(&http.Cookie{Name: "session", Value: "abc123"})
+
+		err := Bind(r, &v)
+		require.NoError(t, err)
+		assert.Equal(t, "abc123", v.Session)
+		assert.Equal(t, 10, v.Limit)
+	})
+
+	t.Run("should return error for invalid cookie value", func(t *testing.T) {
+		var (
+			r = httptest.NewRequest(http.MethodGet, "/", nil)
+			v = struct {
+				Visits int `json:"visits" http:"loc=cookie"`
+			}{}
+		)
+
+		r.AddCookie(&http.Cookie{Name: "visits", Value: "many"})
+
+		err := Bind(r, &v)
+		assert.Error(t, err)
+	})
 }
 
 func TestBindPath(t *testing.T) {
@@ -533,3 +774,112 @@ func TestBindPath(t *testing.T) {
 		assert.Equal(t, "", v.ID)
 	})
 }
+
+func TestBindLimits(t *testing.T) {
+	opts := &BindOptions{MaxSliceLen: 2, MaxValueLen: 8}
+
+	t.Run("should reject too many repeated parameters", func(t *testing.T) {
+		var (
+			r = httptest.NewRequest(http.MethodGet, "/?tags=a&tags=b&tags=c", nil)
+			v = struct {
+				Tags []string `json:"tags"`
+			}{}
+		)
+
+		err := BindQuery(r, &v, opts)
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, ErrTooManyValues))
+		assert.Nil(t, v.Tags)
+	})
+
+	t.Run("should reject too many CSV values", func(t *testing.T) {
+		var (
+			r = httptest.NewRequest(http.MethodGet, "/?tags=a,b,c", nil)
+			v = struct {
+				Tags []string `json:"tags" http:"loc=query"`
+			}{}
+		)
+
+		err := Bind(r, &v, &BindOptions{MaxSliceLen: 2, SplitSingleCSV: true})
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, ErrTooManyValues))
+	})
+
+	t.Run("should reject long values without echoing them", func(t *testing.T) {
+		var (
+			r = httptest.NewRequest(http.MethodGet, "/", nil)
+			v = struct {
+				Token string `json:"token"`
+			}{}
+		)
+
+		r.Header.Set("token", strings.Repeat("x", 64))
+
+		err := BindHeader(r, &v, opts)
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, ErrValueTooLong))
+		assert.NotContains(t, err.Error(), "xxxxxxxxx")
+
+		var errs BindErrors
+		require.True(t, errors.As(err, &errs))
+		assert.Equal(t, FieldErrors{"token": {"is too long"}}, errs.FieldErrors())
+	})
+
+	t.Run("should limit map entries and keys", func(t *testing.T) {
+		var (
+			r = httptest.NewRequest(http.MethodGet, "/?labels[a]=1&labels[b]=2&labels[c]=3", nil)
+			v = struct {
+				Labels map[string]string `json:"labels"`
+			}{}
+		)
+
+		err := BindQuery(r, &v, opts)
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, ErrTooManyValues))
+
+		r = httptest.NewRequest(http.MethodGet, "/?labels[averyverylongkey]=1", nil)
+		err = BindQuery(r, &v, opts)
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, ErrValueTooLong))
+	})
+
+	t.Run("should accept values within the limits", func(t *testing.T) {
+		var (
+			r = httptest.NewRequest(http.MethodGet, "/?tags=a&tags=b", nil)
+			v = struct {
+				Tags []string `json:"tags"`
+			}{}
+		)
+
+		require.NoError(t, BindQuery(r, &v, opts))
+		assert.Equal(t, []string{"a", "b"}, v.Tags)
+	})
+}
+
+func TestBindOptionsContext(t *testing.T) {
+	type request struct {
+		PageSize int `http:"loc=query"`
+	}
+
+	t.Run("should use the options of the request context", func(t *testing.T) {
+		var (
+			r = httptest.NewRequest(http.MethodGet, "/?page_size=5", nil)
+			v request
+		)
+		r = r.WithContext(NewBindOptionsContext(r.Context(), &BindOptions{FallbackSnakeCase: true}))
+
+		require.NoError(t, BindQuery(r, &v))
+		assert.Equal(t, 5, v.PageSize)
+	})
+
+	t.Run("should prefer the options of the call", func(t *testing.T) {
+		var (
+			r = httptest.NewRequest(http.MethodGet, "/?page_size=5", nil)
+			v request
+		)
+		r = r.WithContext(NewBindOptionsContext(r.Context(), &BindOptions{FallbackSnakeCase: true}))
+
+		require.NoError(t, BindQuery(r, &v, &BindOptions{}))
+		assert.Zero(t, v.PageSize)
+	})
+}
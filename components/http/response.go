@@ -3,9 +3,9 @@ package http
 import (
 	"bytes"
 	"context"
-	"encoding/json"
 	"log"
 	"net/http"
+	"time"
 
 	logger_api "github.com/mikros-dev/mikros/apis/features/logger"
 	merrors "github.com/mikros-dev/mikros/components/errors"
@@ -102,6 +102,32 @@ type SuccessOptions struct {
 	// Headers contains additional HTTP headers to include in the response.
 	Headers map[string]string
 
+	// MaxBytes caps the size of the encoded response body. Zero value means
+	// no limit.
+	MaxBytes int
+
+	// ResponseSizePolicy defines what happens when the encoded response body
+	// exceeds MaxBytes. It defaults to ResponseSizePolicyError.
+	ResponseSizePolicy ResponseSizePolicy
+
+	// Request is the request being answered. When set, its Accept header
+	// chooses the encoder of the response body among the ones registered
+	// with RegisterResponseEncoder. Without it, responses are JSON-encoded.
+	// It is also required to answer conditional requests.
+	Request *http.Request
+
+	// ETag is the entity tag of the response, set in the ETag header. Values
+	// without quotes are quoted.
+	ETag string
+
+	// GenerateETag computes the ETag from the encoded response body when
+	// ETag is not set.
+	GenerateETag bool
+
+	// LastModified, if set, is the time the response data last changed,
+	// set in the Last-Modified header.
+	LastModified time.Time
+
 	// Output is a custom function for handling success output. If provided, this
 	// function will be called instead of the default success handling.
 	Output func(ctx context.Context, w http.ResponseWriter, data interface{}, code int)
@@ -112,8 +138,13 @@ type SuccessOptions struct {
 // and headers, and manages different scenarios for nil vs. non-nil data.
 //
 // When data is nil, it returns a 204 No Content response with an empty body.
-// When data is provided, it JSON-encodes the data and returns it with a
-// 200 OK status.
+// When data is provided, it encodes the data and returns it with a 200 OK
+// status. The data is JSON-encoded unless SuccessOptions.Request accepts
+// another registered media type.
+//
+// Responses with an ETag or a LastModified time answer GET and HEAD requests
+// whose If-None-Match or If-Modified-Since headers match them with a 304 Not
+// Modified status and an empty body.
 func Success(ctx context.Context, w http.ResponseWriter, data interface{}, options ...SuccessOptions) {
 	var successOpts SuccessOptions
 	if len(options) > 0 {
@@ -148,8 +179,12 @@ func writeSuccess(ctx context.Context, w http.ResponseWriter, data interface{},
 		options.HTTPStatusCode = http.StatusOK
 	}
 
-	var buf bytes.Buffer
-	if err := json.NewEncoder(&buf).Encode(data); err != nil {
+	var (
+		buf bytes.Buffer
+		enc = negotiateResponseEncoder(options.Request)
+	)
+
+	if err := enc.encode(&buf, data); err != nil {
 		if options.Logger != nil {
 			options.Logger.Error(ctx, "failed to encode response", logger.Error(err))
 			return
@@ -159,15 +194,60 @@ func writeSuccess(ctx context.Context, w http.ResponseWriter, data interface{},
 		return
 	}
 
+	body := buf.Bytes()
+	if options.MaxBytes > 0 && len(body) > options.MaxBytes {
+		if enc.mediaType != jsonMediaType {
+			// Only JSON lists can be truncated.
+			options.ResponseSizePolicy = ResponseSizePolicyError
+		}
+
+		limited, headers, err := limitResponseBody(data, body, options)
+		if err != nil {
+			writeProblem(ctx, w, err, ProblemOptions{
+				HTTPStatusCode: http.StatusInternalServerError,
+				Logger:         options.Logger,
+			})
+			return
+		}
+
+		body = limited
+		for k, v := range headers {
+			w.Header().Set(k, v)
+		}
+	}
+
+	etag := entityTag(body, options)
+	if etag != "" {
+		w.Header().Set("ETag", etag)
+	}
+	if !options.LastModified.IsZero() {
+		w.Header().Set("Last-Modified", options.LastModified.UTC().Format(http.TimeFormat))
+	}
+
+	// The representation depends on the negotiated encoding, which caches
+	// must know about for 304 responses as well.
+	if options.Request != nil {
+		w.Header().Add("Vary", "Accept")
+	}
+
+	if options.HTTPStatusCode == http.StatusOK && notModified(options.Request, etag, options.LastModified) {
+		for k, v := range options.Headers {
+			w.Header().Set(k, v)
+		}
+		w.WriteHeader(http.StatusNotModified)
+
+		return
+	}
+
 	// Set headers and status code
-	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Content-Type", enc.contentType)
 	for k, v := range options.Headers {
 		w.Header().Set(k, v)
 	}
 	w.WriteHeader(options.HTTPStatusCode)
 
 	// Set body
-	if _, err := w.Write(buf.Bytes()); err != nil {
+	if _, err := w.Write(body); err != nil {
 		if options.Logger != nil {
 			options.Logger.Error(ctx, "failed to write response", logger.Error(err))
 			return
@@ -0,0 +1,205 @@
+package http
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"mime"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	jsonMediaType = "application/json"
+)
+
+// ResponseEncoder encodes the data of a success response into w.
+type ResponseEncoder func(w io.Writer, data interface{}) error
+
+// responseEncoder is a registered ResponseEncoder and the Content-Type its
+// responses are sent with.
+type responseEncoder struct {
+	mediaType   string
+	contentType string
+	encode      ResponseEncoder
+}
+
+var (
+	responseEncodersMu sync.RWMutex
+	responseEncoders   = map[string]*responseEncoder{
+		jsonMediaType: {
+			mediaType:   jsonMediaType,
+			contentType: "application/json; charset=utf-8",
+			encode:      encodeJSONResponse,
+		},
+		"application/xml": {
+			mediaType:   "application/xml",
+			contentType: "application/xml; charset=utf-8",
+			encode:      encodeXMLResponse,
+		},
+		"text/xml": {
+			mediaType:   "text/xml",
+			contentType: "text/xml; charset=utf-8",
+			encode:      encodeXMLResponse,
+		},
+	}
+)
+
+// RegisterResponseEncoder registers the encoder Success uses for requests
+// accepting the mediaType media type, replacing the one previously registered
+// for it, if any. Responses encoded by it have mediaType as Content-Type, so
+// it may carry parameters, e.g. "text/csv; charset=utf-8".
+//
+// JSON and XML encoders are registered by default. Other formats, such as
+// msgpack, can be added with:
+//
+//	http.RegisterResponseEncoder("application/msgpack", func(w io.Writer, data interface{}) error {
+//		return msgpack.NewEncoder(w).Encode(data)
+//	})
+func RegisterResponseEncoder(mediaType string, encoder ResponseEncoder) {
+	t, _, err := mime.ParseMediaType(mediaType)
+	if err != nil {
+		t = mediaType
+	}
+
+	responseEncodersMu.Lock()
+	defer responseEncodersMu.Unlock()
+
+	t = strings.ToLower(t)
+	responseEncoders[t] = &responseEncoder{
+		mediaType:   t,
+		contentType: mediaType,
+		encode:      encoder,
+	}
+}
+
+// acceptedMediaType is an entry of an Accept header.
+type acceptedMediaType struct {
+	mediaType string
+	quality   float64
+}
+
+// negotiateResponseEncoder chooses the encoder of a response from the Accept
+// header of its request, following the client preference order. Requests
+// without the header, or accepting no registered media type, are answered
+// with JSON. Media types with a "+json" or "+xml" suffix are served by the
+// JSON or XML encoders, unless an encoder was registered for them.
+func negotiateResponseEncoder(r *http.Request) *responseEncoder {
+	responseEncodersMu.RLock()
+	defer responseEncodersMu.RUnlock()
+
+	if r == nil {
+		return responseEncoders[jsonMediaType]
+	}
+
+	for _, accepted := range parseAccept(r.Header.Values("Accept")) {
+		if enc, ok := matchResponseEncoder(accepted.mediaType); ok {
+			return enc
+		}
+	}
+
+	return responseEncoders[jsonMediaType]
+}
+
+// matchResponseEncoder returns the encoder of an accepted media type. It must
+// be called with responseEncodersMu held.
+func matchResponseEncoder(mediaType string) (*responseEncoder, bool) {
+	if enc, ok := responseEncoders[mediaType]; ok {
+		return enc, true
+	}
+
+	switch {
+	case mediaType == "*/*":
+		return responseEncoders[jsonMediaType], true
+
+	case strings.HasSuffix(mediaType, "/*"):
+		if strings.HasPrefix(jsonMediaType, strings.TrimSuffix(mediaType, "*")) {
+			return responseEncoders[jsonMediaType], true
+		}
+
+		// Sort to choose the same encoder every time.
+		keys := make([]string, 0, len(responseEncoders))
+		for k := range responseEncoders {
+			keys = append(keys, k)
+		}
+		slices.Sort(keys)
+
+		for _, k := range keys {
+			if strings.HasPrefix(k, strings.TrimSuffix(mediaType, "*")) {
+				return responseEncoders[k], true
+			}
+		}
+
+	case strings.HasSuffix(mediaType, "+json"):
+		return &responseEncoder{
+			mediaType:   mediaType,
+			contentType: mediaType + "; charset=utf-8",
+			encode:      responseEncoders[jsonMediaType].encode,
+		}, true
+
+	case strings.HasSuffix(mediaType, "+xml"):
+		return &responseEncoder{
+			mediaType:   mediaType,
+			contentType: mediaType + "; charset=utf-8",
+			encode:      responseEncoders["application/xml"].encode,
+		}, true
+	}
+
+	return nil, false
+}
+
+// parseAccept parses Accept header values into the media types they list,
+// sorted by their quality value. Media types with the same quality keep the
+// order they were sent in, and the ones with quality 0 are dropped.
+func parseAccept(values []string) []acceptedMediaType {
+	var accepted []acceptedMediaType
+	for _, value := range values {
+		for _, entry := range strings.Split(value, ",") {
+			mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(entry))
+			if err != nil {
+				continue
+			}
+
+			quality := 1.0
+			if q, ok := params["q"]; ok {
+				v, err := strconv.ParseFloat(q, 64)
+				if err != nil {
+					continue
+				}
+				quality = v
+			}
+			if quality <= 0 {
+				continue
+			}
+
+			accepted = append(accepted, acceptedMediaType{
+				mediaType: mediaType,
+				quality:   quality,
+			})
+		}
+	}
+
+	slices.SortStableFunc(accepted, func(a, b acceptedMediaType) int {
+		switch {
+		case a.quality > b.quality:
+			return -1
+		case a.quality < b.quality:
+			return 1
+		}
+
+		return 0
+	})
+
+	return accepted
+}
+
+func encodeJSONResponse(w io.Writer, data interface{}) error {
+	return json.NewEncoder(w).Encode(data)
+}
+
+func encodeXMLResponse(w io.Writer, data interface{}) error {
+	return xml.NewEncoder(w).Encode(data)
+}
@@ -0,0 +1,121 @@
+package http
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type encodedUser struct {
+	Name string `json:"name" xml:"name"`
+}
+
+func newAcceptRequest(accept string) *http.Request {
+	r := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+	if accept != "" {
+		r.Header.Set("Accept", accept)
+	}
+
+	return r
+}
+
+func TestSuccessContentNegotiation(t *testing.T) {
+	t.Run("should encode JSON without a request", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		Success(ctx, rec, encodedUser{Name: "john"})
+
+		assert.Equal(t, "application/json; charset=utf-8", rec.Header().Get("Content-Type"))
+		assert.Equal(t, "{\"name\":\"john\"}\n", rec.Body.String())
+		assert.Empty(t, rec.Header().Get("Vary"))
+	})
+
+	t.Run("should encode JSON without an Accept header", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		Success(ctx, rec, encodedUser{Name: "john"}, SuccessOptions{Request: newAcceptRequest("")})
+
+		assert.Equal(t, "application/json; charset=utf-8", rec.Header().Get("Content-Type"))
+		assert.Equal(t, "Accept", rec.Header().Get("Vary"))
+	})
+
+	t.Run("should encode XML when accepted", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		Success(ctx, rec, encodedUser{Name: "john"}, SuccessOptions{Request: newAcceptRequest("application/xml")})
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/xml; charset=utf-8", rec.Header().Get("Content-Type"))
+		assert.Equal(t, "<encodedUser><name>john</name></encodedUser>", rec.Body.String())
+	})
+
+	t.Run("should follow quality values", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		Success(ctx, rec, encodedUser{Name: "john"}, SuccessOptions{
+			Request: newAcceptRequest("application/json;q=0.5, application/xml;q=0.9"),
+		})
+
+		assert.Equal(t, "application/xml; charset=utf-8", rec.Header().Get("Content-Type"))
+	})
+
+	t.Run("should use a registered encoder", func(t *testing.T) {
+		RegisterResponseEncoder("text/plain; charset=utf-8", func(w io.Writer, data interface{}) error {
+			_, err := fmt.Fprintf(w, "%v", data)
+			return err
+		})
+		t.Cleanup(func() {
+			responseEncodersMu.Lock()
+			delete(responseEncoders, "text/plain")
+			responseEncodersMu.Unlock()
+		})
+
+		rec := httptest.NewRecorder()
+		Success(ctx, rec, encodedUser{Name: "john"}, SuccessOptions{Request: newAcceptRequest("text/plain")})
+
+		assert.Equal(t, "text/plain; charset=utf-8", rec.Header().Get("Content-Type"))
+		assert.Equal(t, "{john}", rec.Body.String())
+	})
+
+	t.Run("should fall back to JSON when nothing matches", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		Success(ctx, rec, encodedUser{Name: "john"}, SuccessOptions{Request: newAcceptRequest("image/png")})
+
+		assert.Equal(t, "application/json; charset=utf-8", rec.Header().Get("Content-Type"))
+	})
+
+	t.Run("should not truncate non JSON responses", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		Success(ctx, rec, []encodedUser{{Name: "john"}, {Name: "jane"}}, SuccessOptions{
+			Request:            newAcceptRequest("application/xml"),
+			MaxBytes:           10,
+			ResponseSizePolicy: ResponseSizePolicyTruncate,
+		})
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	})
+}
+
+func TestNegotiateResponseEncoder(t *testing.T) {
+	tests := []struct {
+		accept      string
+		contentType string
+	}{
+		{accept: "*/*", contentType: "application/json; charset=utf-8"},
+		{accept: "application/*", contentType: "application/json; charset=utf-8"},
+		{accept: "text/*", contentType: "text/xml; charset=utf-8"},
+		{accept: "application/problem+json", contentType: "application/problem+json; charset=utf-8"},
+		{accept: "application/atom+xml", contentType: "application/atom+xml; charset=utf-8"},
+		{accept: "application/xml;q=0, */*", contentType: "application/json; charset=utf-8"},
+		{accept: "text/html, application/xml;q=0.9, */*;q=0.8", contentType: "application/xml; charset=utf-8"},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("should negotiate %s", tt.accept), func(t *testing.T) {
+			enc := negotiateResponseEncoder(newAcceptRequest(tt.accept))
+			require.NotNil(t, enc)
+			assert.Equal(t, tt.contentType, enc.contentType)
+		})
+	}
+}
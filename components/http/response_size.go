@@ -0,0 +1,117 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// ResponseSizePolicy defines what Success does when an encoded response body
+// is larger than SuccessOptions.MaxBytes.
+type ResponseSizePolicy int
+
+const (
+	// ResponseSizePolicyError replaces the response with an internal server
+	// error. It is the default policy.
+	ResponseSizePolicyError ResponseSizePolicy = iota
+
+	// ResponseSizePolicyTruncate drops trailing elements of slice or array
+	// responses until they fit the limit and adds a Warning header telling
+	// that the response was truncated.
+	ResponseSizePolicyTruncate
+
+	// ResponseSizePolicyPaginationHint drops trailing elements of slice or
+	// array responses until they fit the limit and adds headers telling the
+	// client the total number of elements (X-Total-Count) and the page size
+	// that fits the limit (X-Pagination-Limit), so it can paginate the next
+	// requests.
+	ResponseSizePolicyPaginationHint
+)
+
+const (
+	truncatedWarningHeaderValue = `199 - "response truncated"`
+)
+
+// ErrResponseTooLarge is returned, through the error response, when an encoded
+// response body exceeds the configured limit and cannot be reduced.
+type ErrResponseTooLarge struct {
+	Size     int
+	MaxBytes int
+}
+
+func (e *ErrResponseTooLarge) Error() string {
+	return fmt.Sprintf("response body has %d bytes and exceeds the limit of %d bytes", e.Size, e.MaxBytes)
+}
+
+// limitResponseBody applies the configured size policy to an already encoded
+// response body. It returns the body that should be written and the headers
+// that must be added to the response.
+func limitResponseBody(data interface{}, body []byte, options SuccessOptions) ([]byte, map[string]string, error) {
+	tooLarge := &ErrResponseTooLarge{
+		Size:     len(body),
+		MaxBytes: options.MaxBytes,
+	}
+
+	if options.ResponseSizePolicy == ResponseSizePolicyError {
+		return nil, nil, tooLarge
+	}
+
+	v := reflect.ValueOf(data)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		// Only lists can be reduced without breaking the response format.
+		return nil, nil, tooLarge
+	}
+
+	truncated, count, err := truncateJSONList(v, options.MaxBytes)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	headers := map[string]string{}
+	switch options.ResponseSizePolicy {
+	case ResponseSizePolicyTruncate:
+		headers["Warning"] = truncatedWarningHeaderValue
+	case ResponseSizePolicyPaginationHint:
+		headers["X-Total-Count"] = strconv.Itoa(v.Len())
+		headers["X-Pagination-Limit"] = strconv.Itoa(count)
+	}
+
+	return truncated, headers, nil
+}
+
+// truncateJSONList encodes as many leading elements of v as possible without
+// exceeding maxBytes, using the same output format as json.Encoder.
+func truncateJSONList(v reflect.Value, maxBytes int) ([]byte, int, error) {
+	var (
+		buf   bytes.Buffer
+		count int
+	)
+
+	// Reserve space for the enclosing brackets and the trailing newline.
+	buf.WriteByte('[')
+	for i := 0; i < v.Len(); i++ {
+		b, err := json.Marshal(v.Index(i).Interface())
+		if err != nil {
+			return nil, 0, err
+		}
+
+		size := len(b) + 2
+		if i > 0 {
+			size++
+		}
+		if buf.Len()+size > maxBytes {
+			break
+		}
+
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.Write(b)
+		count++
+	}
+	buf.WriteString("]\n")
+
+	return buf.Bytes(), count, nil
+}
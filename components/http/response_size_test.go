@@ -0,0 +1,82 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSuccessMaxBytes(t *testing.T) {
+	data := []string{"aaaa", "bbbb", "cccc", "dddd"}
+
+	t.Run("should write responses within the limit", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+
+		Success(ctx, rec, data, SuccessOptions{MaxBytes: 1024})
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `["aaaa","bbbb","cccc","dddd"]`, rec.Body.String())
+	})
+
+	t.Run("should fail by default when exceeding the limit", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+
+		Success(ctx, rec, data, SuccessOptions{MaxBytes: 10})
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		assert.Contains(t, rec.Body.String(), "exceeds the limit of 10 bytes")
+	})
+
+	t.Run("should truncate lists with a warning header", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+
+		Success(ctx, rec, data, SuccessOptions{
+			MaxBytes:           20,
+			ResponseSizePolicy: ResponseSizePolicyTruncate,
+		})
+
+		var out []string
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &out))
+		assert.Equal(t, []string{"aaaa", "bbbb"}, out)
+		assert.LessOrEqual(t, rec.Body.Len(), 20)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, truncatedWarningHeaderValue, rec.Header().Get("Warning"))
+	})
+
+	t.Run("should add pagination hints", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+
+		Success(ctx, rec, data, SuccessOptions{
+			MaxBytes:           25,
+			ResponseSizePolicy: ResponseSizePolicyPaginationHint,
+		})
+
+		var out []string
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &out))
+		assert.Equal(t, []string{"aaaa", "bbbb", "cccc"}, out)
+		assert.Equal(t, "4", rec.Header().Get("X-Total-Count"))
+		assert.Equal(t, "3", rec.Header().Get("X-Pagination-Limit"))
+	})
+
+	t.Run("should fail for non list responses that cannot be reduced", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+
+		Success(ctx, rec, map[string]string{"key": "a long enough value"}, SuccessOptions{
+			MaxBytes:           10,
+			ResponseSizePolicy: ResponseSizePolicyTruncate,
+		})
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	})
+}
+
+func TestErrResponseTooLarge(t *testing.T) {
+	_, _, err := limitResponseBody(nil, make([]byte, 20), SuccessOptions{MaxBytes: 10})
+
+	var tooLarge *ErrResponseTooLarge
+	require.True(t, errors.As(err, &tooLarge))
+	assert.Equal(t, 20, tooLarge.Size)
+	assert.Equal(t, 10, tooLarge.MaxBytes)
+}
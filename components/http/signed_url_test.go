@@ -0,0 +1,119 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestURLSigner(t *testing.T) {
+	now := time.Now()
+	signer, err := NewURLSigner([]byte("secret"))
+	require.NoError(t, err)
+	signer.now = func() time.Time { return now }
+
+	sign := func(options SignURLOptions) *url.URL {
+		raw, err := signer.Sign("https://api.example.com/files/report.pdf?inline=1", options)
+		require.NoError(t, err)
+
+		u, err := url.Parse(raw)
+		require.NoError(t, err)
+		return u
+	}
+
+	t.Run("should verify signed urls and return their claims", func(t *testing.T) {
+		u := sign(SignURLOptions{
+			ExpiresIn: time.Minute,
+			Claims:    map[string]string{"user": "42"},
+		})
+
+		claims, err := signer.VerifyURL(http.MethodGet, u)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"user": "42"}, claims)
+	})
+
+	t.Run("should reject tampered urls", func(t *testing.T) {
+		u := sign(SignURLOptions{ExpiresIn: time.Minute})
+
+		q := u.Query()
+		q.Set("inline", "0")
+		u.RawQuery = q.Encode()
+		_, err := signer.VerifyURL(http.MethodGet, u)
+		assert.ErrorIs(t, err, ErrSignedURLInvalid)
+
+		u = sign(SignURLOptions{ExpiresIn: time.Minute})
+		u.Path = "/files/other.pdf"
+		_, err = signer.VerifyURL(http.MethodGet, u)
+		assert.ErrorIs(t, err, ErrSignedURLInvalid)
+
+		u = sign(SignURLOptions{ExpiresIn: time.Minute})
+		other, err := NewURLSigner([]byte("other"))
+		require.NoError(t, err)
+		_, err = other.VerifyURL(http.MethodGet, u)
+		assert.ErrorIs(t, err, ErrSignedURLInvalid)
+	})
+
+	t.Run("should reject expired urls", func(t *testing.T) {
+		u := sign(SignURLOptions{ExpiresIn: time.Minute})
+
+		later, err := NewURLSigner([]byte("secret"))
+		require.NoError(t, err)
+		later.now = func() time.Time { return now.Add(2 * time.Minute) }
+
+		_, err = later.VerifyURL(http.MethodGet, u)
+		assert.ErrorIs(t, err, ErrSignedURLExpired)
+	})
+
+	t.Run("should restrict the method", func(t *testing.T) {
+		u := sign(SignURLOptions{ExpiresIn: time.Minute, Method: http.MethodPut})
+
+		_, err := signer.VerifyURL(http.MethodGet, u)
+		assert.ErrorIs(t, err, ErrSignedURLInvalid)
+
+		_, err = signer.VerifyURL(http.MethodPut, u)
+		assert.NoError(t, err)
+	})
+
+	t.Run("should protect handlers with the middleware", func(t *testing.T) {
+		var claims map[string]string
+		h := signer.Middleware()(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+			claims, _ = SignedURLClaims(r.Context())
+		}))
+
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/files/report.pdf", nil))
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+
+		u := sign(SignURLOptions{ExpiresIn: time.Minute, Claims: map[string]string{"user": "1"}})
+		rec = httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, u.RequestURI(), nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "1", claims["user"])
+	})
+
+	t.Run("should verify the path requested before the base path is stripped", func(t *testing.T) {
+		var (
+			rec = httptest.NewRecorder()
+			h   = http.StripPrefix("/files", signer.Middleware()(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {})))
+			u   = sign(SignURLOptions{ExpiresIn: time.Minute})
+		)
+
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, u.RequestURI(), nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+
+	t.Run("should reject empty keys", func(t *testing.T) {
+		_, err := NewURLSigner(nil)
+		assert.Error(t, err)
+	})
+
+	t.Run("should require an expiration", func(t *testing.T) {
+		_, err := signer.Sign("/files", SignURLOptions{})
+		assert.Error(t, err)
+	})
+}
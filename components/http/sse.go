@@ -0,0 +1,292 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mikros-dev/mikros/components/clock"
+	mcontext "github.com/mikros-dev/mikros/components/context"
+)
+
+var (
+	// ErrStreamingUnsupported is returned by NewEventStream when the
+	// response writer cannot flush partial responses to the client.
+	ErrStreamingUnsupported = errors.New("response writer does not support streaming")
+
+	// ErrStreamClosed is returned when sending to a closed EventStream.
+	ErrStreamClosed = errors.New("stream is closed")
+)
+
+// Event is a Server-Sent Event.
+type Event struct {
+	// ID is sent as the event id, which clients send back in the
+	// Last-Event-ID header when reconnecting.
+	ID string
+
+	// Event is the event type. Clients receive events without it as
+	// "message" events.
+	Event string
+
+	// Data is the event payload. Strings and byte slices are sent as they
+	// are, while other values are JSON-encoded.
+	Data interface{}
+
+	// Retry tells the client how long to wait before reconnecting.
+	Retry time.Duration
+}
+
+// EventStreamOptions configures an EventStream.
+type EventStreamOptions struct {
+	// Heartbeat is the interval of the comments sent to keep idle
+	// connections open. Zero value disables heartbeats.
+	Heartbeat time.Duration
+
+	// Retry, if set, is sent to the client before any event, telling how
+	// long it must wait before reconnecting.
+	Retry time.Duration
+
+	// Headers contains additional HTTP headers to include in the response.
+	Headers map[string]string
+
+	// Context, usually the request one, lets the stream end with the
+	// request and when the service starts stopping, as told by
+	// mcontext.ShutdownNotice. In the latter case, a "shutdown" event, with
+	// the Retry value, is sent before closing the stream, so clients
+	// reconnect to another instance instead of seeing a connection reset.
+	Context context.Context
+}
+
+// EventStream writes Server-Sent Events (text/event-stream) responses,
+// flushing every event to the client as it is sent. It is safe for
+// concurrent use.
+type EventStream struct {
+	mu         sync.Mutex
+	w          http.ResponseWriter
+	controller *http.ResponseController
+	closed     bool
+	done       chan struct{}
+	stopped    chan struct{}
+}
+
+// NewEventStream starts a Server-Sent Events response. Handlers must call
+// Close when they are done streaming.
+func NewEventStream(w http.ResponseWriter, options ...EventStreamOptions) (*EventStream, error) {
+	var opts EventStreamOptions
+	if len(options) > 0 {
+		opts = options[0]
+	}
+
+	controller, err := startEventStream(w, opts.Headers)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &EventStream{
+		w:          w,
+		controller: controller,
+		done:       make(chan struct{}),
+		stopped:    make(chan struct{}),
+	}
+
+	if opts.Retry > 0 {
+		if err := s.write(fmt.Sprintf("retry: %d\n\n", opts.Retry.Milliseconds())); err != nil {
+			return nil, err
+		}
+	}
+
+	if opts.Heartbeat > 0 || opts.Context != nil {
+		go s.watch(opts)
+	} else {
+		close(s.stopped)
+	}
+
+	return s, nil
+}
+
+// Send sends an event to the client.
+func (s *EventStream) Send(event Event) error {
+	data, err := eventData(event.Data)
+	if err != nil {
+		return err
+	}
+
+	var b strings.Builder
+	if event.ID != "" {
+		b.WriteString("id: " + singleLine(event.ID) + "\n")
+	}
+	if event.Event != "" {
+		b.WriteString("event: " + singleLine(event.Event) + "\n")
+	}
+	if event.Retry > 0 {
+		b.WriteString("retry: " + strconv.FormatInt(event.Retry.Milliseconds(), 10) + "\n")
+	}
+	for _, line := range strings.Split(data, "\n") {
+		b.WriteString("data: " + line + "\n")
+	}
+	b.WriteString("\n")
+
+	return s.write(b.String())
+}
+
+// Comment sends a comment, which clients ignore.
+func (s *EventStream) Comment(text string) error {
+	var b strings.Builder
+	for _, line := range strings.Split(text, "\n") {
+		b.WriteString(": " + line + "\n")
+	}
+	b.WriteString("\n")
+
+	return s.write(b.String())
+}
+
+// Close stops the heartbeats. Events cannot be sent after it.
+func (s *EventStream) Close() {
+	s.close()
+	<-s.stopped
+}
+
+// Done returns a channel closed when the stream is closed, either by Close
+// or because its Context ended or the service started stopping.
+func (s *EventStream) Done() <-chan struct{} {
+	return s.done
+}
+
+func (s *EventStream) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.closed {
+		s.closed = true
+		close(s.done)
+	}
+}
+
+// watch sends the heartbeats and closes the stream when its context ends.
+func (s *EventStream) watch(opts EventStreamOptions) {
+	defer close(s.stopped)
+
+	var (
+		tick    <-chan time.Time
+		ctxDone <-chan struct{}
+		notice  <-chan struct{}
+	)
+	if opts.Heartbeat > 0 {
+		ticker := clock.NewTicker(opts.Heartbeat)
+		defer ticker.Stop()
+		tick = ticker.C()
+	}
+	if opts.Context != nil {
+		ctxDone = opts.Context.Done()
+		notice = mcontext.ShutdownNotice(opts.Context)
+	}
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ctxDone:
+			s.close()
+			return
+		case <-notice:
+			_ = s.Send(Event{Event: "shutdown", Retry: opts.Retry})
+			s.close()
+			return
+		case <-tick:
+			if err := s.write(":\n\n"); err != nil {
+				// The client is gone.
+				return
+			}
+		}
+	}
+}
+
+func (s *EventStream) write(data string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return ErrStreamClosed
+	}
+
+	if _, err := s.w.Write([]byte(data)); err != nil {
+		return err
+	}
+
+	return s.controller.Flush()
+}
+
+// LastEventID returns the ID of the last event received by a reconnecting
+// Server-Sent Events client, so the handler can resume the stream after it.
+func LastEventID(r *http.Request) string {
+	return r.Header.Get("Last-Event-ID")
+}
+
+func eventData(data interface{}) (string, error) {
+	switch v := data.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return strings.ReplaceAll(v, "\r\n", "\n"), nil
+	case []byte:
+		return strings.ReplaceAll(string(v), "\r\n", "\n"), nil
+	}
+
+	b, err := json.Marshal(data)
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
+
+func singleLine(s string) string {
+	return strings.NewReplacer("\r", "", "\n", "").Replace(s)
+}
+
+// startEventStream writes the headers of an event stream response. Proxies
+// are told not to buffer it.
+func startEventStream(w http.ResponseWriter, headers map[string]string) (*http.ResponseController, error) {
+	// Checks before writing anything, so handlers can still respond with
+	// an error.
+	if !canFlush(w) {
+		return nil, ErrStreamingUnsupported
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	for k, v := range headers {
+		w.Header().Set(k, v)
+	}
+	w.WriteHeader(http.StatusOK)
+
+	controller := http.NewResponseController(w)
+	if err := controller.Flush(); err != nil {
+		return nil, err
+	}
+
+	return controller, nil
+}
+
+// canFlush reports whether w, or a writer wrapped by it, implements
+// http.Flusher, following the same rules of http.ResponseController.
+func canFlush(w http.ResponseWriter) bool {
+	for {
+		if _, ok := w.(http.Flusher); ok {
+			return true
+		}
+
+		u, ok := w.(interface{ Unwrap() http.ResponseWriter })
+		if !ok {
+			return false
+		}
+		w = u.Unwrap()
+	}
+}
@@ -0,0 +1,167 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mikros-dev/mikros/components/clock"
+	mcontext "github.com/mikros-dev/mikros/components/context"
+)
+
+// syncRecorder is a flushable http.ResponseWriter safe to be read while
+// written by the heartbeat goroutine.
+type syncRecorder struct {
+	mu     sync.Mutex
+	header http.Header
+	body   bytes.Buffer
+}
+
+func (r *syncRecorder) Header() http.Header {
+	return r.header
+}
+
+func (r *syncRecorder) Write(b []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.body.Write(b)
+}
+
+func (r *syncRecorder) WriteHeader(int) {}
+
+func (r *syncRecorder) Flush() {}
+
+func (r *syncRecorder) String() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.body.String()
+}
+
+// plainWriter is an http.ResponseWriter that cannot flush.
+type plainWriter struct {
+	http.ResponseWriter
+}
+
+func TestEventStream(t *testing.T) {
+	t.Run("should write events", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+
+		s, err := NewEventStream(rec, EventStreamOptions{
+			Retry:   3 * time.Second,
+			Headers: map[string]string{"X-Custom": "1"},
+		})
+		require.NoError(t, err)
+
+		require.NoError(t, s.Send(Event{ID: "1", Event: "created", Data: map[string]string{"name": "john"}}))
+		require.NoError(t, s.Send(Event{Data: "line 1\nline 2"}))
+		require.NoError(t, s.Comment("keep going"))
+		s.Close()
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "text/event-stream", rec.Header().Get("Content-Type"))
+		assert.Equal(t, "no-cache", rec.Header().Get("Cache-Control"))
+		assert.Equal(t, "1", rec.Header().Get("X-Custom"))
+		assert.True(t, rec.Flushed)
+		assert.Equal(t, "retry: 3000\n\n"+
+			"id: 1\nevent: created\ndata: {\"name\":\"john\"}\n\n"+
+			"data: line 1\ndata: line 2\n\n"+
+			": keep going\n\n", rec.Body.String())
+	})
+
+	t.Run("should not allow line breaks in ids", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+
+		s, err := NewEventStream(rec)
+		require.NoError(t, err)
+		require.NoError(t, s.Send(Event{ID: "1\nevent: x", Data: []byte("ok")}))
+		s.Close()
+
+		assert.Equal(t, "id: 1event: x\ndata: ok\n\n", rec.Body.String())
+	})
+
+	t.Run("should fail sending after closed", func(t *testing.T) {
+		s, err := NewEventStream(httptest.NewRecorder())
+		require.NoError(t, err)
+
+		s.Close()
+		s.Close()
+		assert.True(t, errors.Is(s.Send(Event{Data: "late"}), ErrStreamClosed))
+	})
+
+	t.Run("should fail when the writer cannot flush", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+
+		_, err := NewEventStream(plainWriter{rec})
+		assert.True(t, errors.Is(err, ErrStreamingUnsupported))
+		assert.Empty(t, rec.Header().Get("Content-Type"))
+	})
+
+	t.Run("should send heartbeats", func(t *testing.T) {
+		fake := clock.NewFake(time.Now())
+		defer clock.Set(fake)()
+
+		rec := &syncRecorder{header: http.Header{}}
+		s, err := NewEventStream(rec, EventStreamOptions{Heartbeat: 15 * time.Second})
+		require.NoError(t, err)
+		defer s.Close()
+
+		// Waits for the heartbeat goroutine to create its ticker.
+		assert.Eventually(t, func() bool {
+			fake.Advance(15 * time.Second)
+			return rec.String() != ""
+		}, time.Second, 10*time.Millisecond)
+		assert.Contains(t, rec.String(), ":\n\n")
+	})
+
+	t.Run("should send a shutdown event when the service stops", func(t *testing.T) {
+		var (
+			notice = make(chan struct{})
+			ctx    = mcontext.WithShutdownNotice(context.Background(), notice)
+			rec    = &syncRecorder{header: http.Header{}}
+		)
+
+		s, err := NewEventStream(rec, EventStreamOptions{Context: ctx, Retry: time.Second})
+		require.NoError(t, err)
+		defer s.Close()
+
+		close(notice)
+		<-s.Done()
+
+		assert.True(t, errors.Is(s.Send(Event{Data: "late"}), ErrStreamClosed))
+		assert.Equal(t, "retry: 1000\n\nevent: shutdown\nretry: 1000\ndata: \n\n", rec.String())
+	})
+
+	t.Run("should close when its context ends", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		rec := &syncRecorder{header: http.Header{}}
+
+		s, err := NewEventStream(rec, EventStreamOptions{Context: ctx})
+		require.NoError(t, err)
+		defer s.Close()
+
+		cancel()
+		<-s.Done()
+
+		assert.Empty(t, rec.String())
+	})
+}
+
+func TestLastEventID(t *testing.T) {
+	t.Run("should return the id sent by reconnecting clients", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/events", nil)
+		assert.Empty(t, LastEventID(r))
+
+		r.Header.Set("Last-Event-ID", "42")
+		assert.Equal(t, "42", LastEventID(r))
+	})
+}
@@ -0,0 +1,284 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"iter"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	logger_api "github.com/mikros-dev/mikros/apis/features/logger"
+	"github.com/mikros-dev/mikros/components/clock"
+	"github.com/mikros-dev/mikros/components/logger"
+)
+
+const (
+	defaultStreamFlushEvery    = 100
+	defaultStreamFlushInterval = time.Second
+)
+
+// StreamOptions configures how streamed success responses are handled and
+// output.
+type StreamOptions struct {
+	// HTTPStatusCode specifies the HTTP status code to return. If zero,
+	// defaults to 200 OK.
+	HTTPStatusCode int
+
+	// Logger is used for logging errors that occur during response writing. If
+	// nil, errors will be logged using the standard log package.
+	Logger logger_api.API
+
+	// Headers contains additional HTTP headers to include in the response.
+	Headers map[string]string
+
+	// FlushEvery flushes the response after this number of items is written.
+	// Zero value uses the internal default (100).
+	FlushEvery int
+
+	// FlushInterval flushes, in the background, the items written since the
+	// last flush at every interval, even if FlushEvery items were not written
+	// yet, so slow producers don't hold data back. Zero value uses the
+	// internal default (1s).
+	FlushInterval time.Duration
+}
+
+// SuccessStream outputs an HTTP success response writing items, one by one, as
+// elements of a JSON array. The response is flushed periodically, so large
+// result sets don't need to be held in memory before responding.
+//
+// Since the status code is sent before the first item, errors while encoding
+// items or a context cancellation can only interrupt the stream, leaving the
+// array unterminated so that clients can detect the failure. These errors
+// are logged.
+func SuccessStream[T any](ctx context.Context, w http.ResponseWriter, items iter.Seq[T], options ...StreamOptions) {
+	var streamOpts StreamOptions
+	if len(options) > 0 {
+		streamOpts = options[0]
+	}
+
+	streamItems(ctx, w, items, streamOpts, false)
+}
+
+// SuccessStreamChan is a SuccessStream variant that reads items from a channel
+// until it is closed or ctx is done.
+func SuccessStreamChan[T any](ctx context.Context, w http.ResponseWriter, items <-chan T, options ...StreamOptions) {
+	SuccessStream(ctx, w, chanSeq(ctx, items), options...)
+}
+
+// SuccessNDJSON outputs an HTTP success response writing items, one by one, as
+// newline-delimited JSON (application/x-ndjson), flushed like SuccessStream.
+// Clients can process each line as it arrives.
+//
+// Errors while encoding items or a context cancellation interrupt the stream
+// and are logged.
+func SuccessNDJSON[T any](ctx context.Context, w http.ResponseWriter, items iter.Seq[T], options ...StreamOptions) {
+	var streamOpts StreamOptions
+	if len(options) > 0 {
+		streamOpts = options[0]
+	}
+
+	streamItems(ctx, w, items, streamOpts, true)
+}
+
+// SuccessNDJSONChan is a SuccessNDJSON variant that reads items from a channel
+// until it is closed or ctx is done.
+func SuccessNDJSONChan[T any](ctx context.Context, w http.ResponseWriter, items <-chan T, options ...StreamOptions) {
+	SuccessNDJSON(ctx, w, chanSeq(ctx, items), options...)
+}
+
+func streamItems[T any](
+	ctx context.Context,
+	w http.ResponseWriter,
+	items iter.Seq[T],
+	streamOpts StreamOptions,
+	ndjson bool,
+) {
+	if streamOpts.HTTPStatusCode == 0 {
+		streamOpts.HTTPStatusCode = http.StatusOK
+	}
+	if streamOpts.FlushEvery <= 0 {
+		streamOpts.FlushEvery = defaultStreamFlushEvery
+	}
+	if streamOpts.FlushInterval <= 0 {
+		streamOpts.FlushInterval = defaultStreamFlushInterval
+	}
+
+	s := &jsonStreamWriter{
+		ctx:        ctx,
+		w:          w,
+		controller: http.NewResponseController(w),
+		options:    streamOpts,
+		ndjson:     ndjson,
+		done:       make(chan struct{}),
+		stopped:    make(chan struct{}),
+	}
+
+	s.begin()
+	go s.flushPeriodically()
+	defer s.stopFlushing()
+
+	for item := range items {
+		if err := s.writeItem(item); err != nil {
+			s.logError("failed to stream response", err)
+			return
+		}
+	}
+
+	s.stopFlushing()
+	if err := s.end(); err != nil {
+		s.logError("failed to stream response", err)
+	}
+}
+
+func chanSeq[T any](ctx context.Context, ch <-chan T) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case item, ok := <-ch:
+				if !ok || !yield(item) {
+					return
+				}
+			}
+		}
+	}
+}
+
+type jsonStreamWriter struct {
+	ctx        context.Context
+	w          http.ResponseWriter
+	controller *http.ResponseController
+	options    StreamOptions
+	count      int
+	ndjson     bool
+
+	// mu serializes the writes of the handler with the periodic flushes.
+	mu       sync.Mutex
+	pending  bool
+	done     chan struct{}
+	stopped  chan struct{}
+	stopOnce sync.Once
+}
+
+func (s *jsonStreamWriter) begin() {
+	contentType := "application/json; charset=utf-8"
+	if s.ndjson {
+		contentType = "application/x-ndjson"
+	}
+
+	s.w.Header().Set("Content-Type", contentType)
+	for k, v := range s.options.Headers {
+		s.w.Header().Set(k, v)
+	}
+	s.w.WriteHeader(s.options.HTTPStatusCode)
+}
+
+func (s *jsonStreamWriter) writeItem(item interface{}) error {
+	if err := s.ctx.Err(); err != nil {
+		return err
+	}
+
+	b, err := json.Marshal(item)
+	if err != nil {
+		return err
+	}
+
+	if s.ndjson {
+		b = append(b, '\n')
+	} else {
+		separator := ","
+		if s.count == 0 {
+			separator = "["
+		}
+		b = append([]byte(separator), b...)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.w.Write(b); err != nil {
+		return err
+	}
+	s.count++
+	s.pending = true
+
+	if s.count%s.options.FlushEvery == 0 {
+		return s.flush()
+	}
+
+	return nil
+}
+
+// flushPeriodically flushes the pending items at every FlushInterval until
+// the stream ends.
+func (s *jsonStreamWriter) flushPeriodically() {
+	defer close(s.stopped)
+
+	ticker := clock.NewTicker(s.options.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C():
+			s.mu.Lock()
+			if s.pending {
+				// A failed flush means the client is gone, which the next
+				// write reports.
+				_ = s.flush()
+			}
+			s.mu.Unlock()
+		}
+	}
+}
+
+// stopFlushing stops the periodic flushes, waiting for an ongoing one, so
+// the stream can be ended without concurrent writes.
+func (s *jsonStreamWriter) stopFlushing() {
+	s.stopOnce.Do(func() {
+		close(s.done)
+	})
+	<-s.stopped
+}
+
+func (s *jsonStreamWriter) end() error {
+	if err := s.ctx.Err(); err != nil {
+		return err
+	}
+
+	if !s.ndjson {
+		closing := "]\n"
+		if s.count == 0 {
+			closing = "[]\n"
+		}
+		if _, err := s.w.Write([]byte(closing)); err != nil {
+			return err
+		}
+	}
+
+	return s.flush()
+}
+
+func (s *jsonStreamWriter) flush() error {
+	s.pending = false
+
+	if err := s.controller.Flush(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return err
+	}
+
+	return nil
+}
+
+func (s *jsonStreamWriter) logError(msg string, err error) {
+	if s.options.Logger != nil {
+		s.options.Logger.Error(s.ctx, msg, logger.Error(err))
+		return
+	}
+
+	log.Printf("%s: %v\n", msg, err)
+}
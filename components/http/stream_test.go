@@ -0,0 +1,184 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSuccessStream(t *testing.T) {
+	t.Run("should write items as a JSON array", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+
+		SuccessStream(ctx, rec, slices.Values([]int{1, 2, 3}), StreamOptions{
+			Headers:    map[string]string{"X-Custom": "1"},
+			FlushEvery: 2,
+		})
+
+		var out []int
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &out))
+		assert.Equal(t, []int{1, 2, 3}, out)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/json; charset=utf-8", rec.Header().Get("Content-Type"))
+		assert.Equal(t, "1", rec.Header().Get("X-Custom"))
+		assert.True(t, rec.Flushed)
+	})
+
+	t.Run("should write an empty array without items", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+
+		SuccessStream(ctx, rec, slices.Values([]string{}))
+		assert.Equal(t, "[]\n", rec.Body.String())
+	})
+
+	t.Run("should leave the array unterminated on encoding errors", func(t *testing.T) {
+		var (
+			rec   = httptest.NewRecorder()
+			items = slices.Values([]interface{}{1, make(chan int), 3})
+		)
+
+		SuccessStream(ctx, rec, items)
+		assert.Equal(t, "[1", rec.Body.String())
+	})
+
+	t.Run("should stop when the context is canceled", func(t *testing.T) {
+		var (
+			rec        = httptest.NewRecorder()
+			cctx, stop = context.WithCancel(ctx)
+			items      = func(yield func(int) bool) {
+				for i := 0; ; i++ {
+					if i == 2 {
+						stop()
+					}
+					if !yield(i) {
+						return
+					}
+				}
+			}
+		)
+
+		SuccessStream(cctx, rec, items)
+		assert.Equal(t, "[0,1", rec.Body.String())
+	})
+}
+
+func TestSuccessStreamChan(t *testing.T) {
+	t.Run("should write items until the channel is closed", func(t *testing.T) {
+		var (
+			rec = httptest.NewRecorder()
+			ch  = make(chan string, 2)
+		)
+
+		ch <- "a"
+		ch <- "b"
+		close(ch)
+
+		SuccessStreamChan(ctx, rec, ch)
+		assert.Equal(t, "[\"a\",\"b\"]\n", rec.Body.String())
+	})
+
+	t.Run("should stop when the context is done", func(t *testing.T) {
+		var (
+			rec        = httptest.NewRecorder()
+			ch         = make(chan string)
+			cctx, stop = context.WithCancel(ctx)
+		)
+
+		stop()
+		SuccessStreamChan(cctx, rec, ch)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Empty(t, rec.Body.String())
+	})
+}
+
+func TestSuccessNDJSON(t *testing.T) {
+	t.Run("should write one JSON value per line", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+
+		SuccessNDJSON(ctx, rec, slices.Values([]map[string]int{{"a": 1}, {"b": 2}}))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/x-ndjson", rec.Header().Get("Content-Type"))
+		assert.Equal(t, "{\"a\":1}\n{\"b\":2}\n", rec.Body.String())
+		assert.True(t, rec.Flushed)
+	})
+
+	t.Run("should write nothing without items", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+
+		SuccessNDJSON(ctx, rec, slices.Values([]int{}))
+		assert.Empty(t, rec.Body.String())
+	})
+
+	t.Run("should stop on encoding errors", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+
+		SuccessNDJSON(ctx, rec, slices.Values([]interface{}{1, make(chan int), 3}))
+		assert.Equal(t, "1\n", rec.Body.String())
+	})
+}
+
+func TestSuccessNDJSONChan(t *testing.T) {
+	t.Run("should write items until the channel is closed", func(t *testing.T) {
+		var (
+			rec = httptest.NewRecorder()
+			ch  = make(chan int, 2)
+		)
+
+		ch <- 1
+		ch <- 2
+		close(ch)
+
+		SuccessNDJSONChan(ctx, rec, ch)
+		assert.Equal(t, "1\n2\n", rec.Body.String())
+	})
+}
+
+type flushSignalWriter struct {
+	*httptest.ResponseRecorder
+	flushed chan struct{}
+}
+
+func (w *flushSignalWriter) Flush() {
+	w.ResponseRecorder.Flush()
+	w.flushed <- struct{}{}
+}
+
+func TestStreamFlushInterval(t *testing.T) {
+	t.Run("should flush pending items while the producer is idle", func(t *testing.T) {
+		var (
+			w = &flushSignalWriter{
+				ResponseRecorder: httptest.NewRecorder(),
+				flushed:          make(chan struct{}, 1),
+			}
+			items = make(chan int)
+			done  = make(chan struct{})
+		)
+
+		go func() {
+			defer close(done)
+			SuccessNDJSONChan(ctx, w, items, StreamOptions{
+				FlushEvery:    100,
+				FlushInterval: 10 * time.Millisecond,
+			})
+		}()
+
+		items <- 1
+		select {
+		case <-w.flushed:
+		case <-time.After(5 * time.Second):
+			require.FailNow(t, "pending item was not flushed")
+		}
+
+		close(items)
+		<-w.flushed
+		<-done
+		assert.Equal(t, "1\n", w.Body.String())
+	})
+}
@@ -2,16 +2,26 @@ package http
 
 import (
 	"errors"
+	"os"
 	"reflect"
 	"slices"
 	"strings"
 )
 
 type bindTag struct {
-	Location   string
-	TimeFormat string
+	Location    string
+	TimeFormats []string
+	Required    bool
+	Default     string
+	HasDefault  bool
+	DefaultEnv  string
+	Prefix      string
+	HasPrefix   bool
 }
 
+// bindTagOptions are the options accepted by the http tag.
+var bindTagOptions = []string{"loc", "time_format", "required", "default", "default_env", "prefix"}
+
 func parseBindTag(tag reflect.StructTag) (*bindTag, error) {
 	raw, ok := tag.Lookup("http")
 	if !ok {
@@ -27,16 +37,27 @@ func parseBindTag(tag reflect.StructTag) (*bindTag, error) {
 		return nil, errors.New("http tag cannot be empty")
 	}
 
+	var lastOption string
 	for _, entry := range entries {
 		k, v, ok := strings.Cut(strings.TrimSpace(entry), "=")
 		k = strings.TrimSpace(k)
 
+		// Entries following time_format that are not options are its
+		// fallback layouts.
+		if lastOption == "time_format" && !slices.Contains(bindTagOptions, k) {
+			if layout := strings.TrimSpace(entry); layout != "" {
+				t.TimeFormats = append(t.TimeFormats, layout)
+			}
+			continue
+		}
+		lastOption = k
+
 		switch k {
 		case "loc":
 			if !ok {
 				return nil, errors.New("http: missing member location")
 			}
-			if !slices.Contains([]string{"query", "header", "path", "body"}, v) {
+			if !slices.Contains([]string{"query", "header", "path", "cookie", "body"}, v) {
 				return nil, errors.New("http: invalid location")
 			}
 			t.Location = strings.TrimSpace(v)
@@ -45,9 +66,69 @@ func parseBindTag(tag reflect.StructTag) (*bindTag, error) {
 			if !ok {
 				return nil, errors.New("http: missing member time_format")
 			}
-			t.TimeFormat = strings.TrimSpace(v)
+			t.TimeFormats = append(t.TimeFormats, strings.TrimSpace(v))
+
+		case "required":
+			t.Required = true
+
+		case "default":
+			if !ok {
+				return nil, errors.New("http: missing member default")
+			}
+			t.Default = strings.TrimSpace(v)
+			t.HasDefault = true
+
+		case "default_env":
+			if !ok || strings.TrimSpace(v) == "" {
+				return nil, errors.New("http: missing member default_env")
+			}
+			t.DefaultEnv = strings.TrimSpace(v)
+
+		case "prefix":
+			if !ok {
+				return nil, errors.New("http: missing member prefix")
+			}
+			t.Prefix = strings.TrimSpace(v)
+			t.HasPrefix = true
+		}
+	}
+
+	if t.Required && (t.HasDefault || t.DefaultEnv != "") {
+		return nil, errors.New("http: required and default cannot be used together")
+	}
+
+	// The environment default is resolved only once, since tags are parsed
+	// when the metadata of their struct is cached, and takes precedence over
+	// the tag default, which is kept when the variable is not set.
+	if t.DefaultEnv != "" {
+		if v, ok := os.LookupEnv(t.DefaultEnv); ok && v != "" {
+			t.Default = v
+			t.HasDefault = true
 		}
 	}
 
 	return t, nil
 }
+
+// appliesTo reports whether the tag options should be considered when binding
+// from location. Tags without a location apply to any of them.
+func (t *bindTag) appliesTo(location string) bool {
+	return t != nil && (t.Location == "" || t.Location == location)
+}
+
+// missing resolves the values of a field that was not found in the request,
+// returning its default value, when available, or an error if the field is
+// required.
+func (t *bindTag) missing(location, name string) ([]string, error) {
+	if !t.appliesTo(location) {
+		return nil, nil
+	}
+	if t.Required {
+		return nil, newBindError(name, location, nil, ErrMissingParameter)
+	}
+	if t.HasDefault {
+		return []string{t.Default}, nil
+	}
+
+	return nil, nil
+}
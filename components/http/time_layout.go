@@ -0,0 +1,82 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	unixLayout      = "unix"
+	unixMilliLayout = "unixmilli"
+)
+
+// namedTimeLayouts are the layouts that can be referenced by the name of
+// their time package constant, which is the only way to use layouts
+// containing commas, like RFC1123, in the `time_format` tag option.
+var namedTimeLayouts = map[string]string{
+	"ANSIC":       time.ANSIC,
+	"UnixDate":    time.UnixDate,
+	"RubyDate":    time.RubyDate,
+	"RFC822":      time.RFC822,
+	"RFC822Z":     time.RFC822Z,
+	"RFC850":      time.RFC850,
+	"RFC1123":     time.RFC1123,
+	"RFC1123Z":    time.RFC1123Z,
+	"RFC3339":     time.RFC3339,
+	"RFC3339Nano": time.RFC3339Nano,
+	"Kitchen":     time.Kitchen,
+	"DateTime":    time.DateTime,
+	"DateOnly":    time.DateOnly,
+	"TimeOnly":    time.TimeOnly,
+}
+
+// parseTime parses value with the first of layouts that accepts it. Besides
+// time package layouts, "unix" and "unixmilli" parse seconds or milliseconds
+// since the Unix epoch.
+func parseTime(value string, layouts []string) (time.Time, error) {
+	if len(layouts) == 0 {
+		return time.Time{}, errors.New("no time layout to parse value")
+	}
+
+	var firstErr error
+	for _, layout := range layouts {
+		t, err := parseTimeLayout(value, layout)
+		if err == nil {
+			return t, nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	if len(layouts) == 1 {
+		return time.Time{}, firstErr
+	}
+
+	return time.Time{}, fmt.Errorf("value does not match any of the layouts %s", strings.Join(layouts, ", "))
+}
+
+func parseTimeLayout(value, layout string) (time.Time, error) {
+	switch layout {
+	case unixLayout, unixMilliLayout:
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return time.Time{}, err
+		}
+
+		if layout == unixLayout {
+			return time.Unix(n, 0).UTC(), nil
+		}
+
+		return time.UnixMilli(n).UTC(), nil
+	}
+
+	if named, ok := namedTimeLayouts[layout]; ok {
+		layout = named
+	}
+
+	return time.Parse(layout, value)
+}
@@ -0,0 +1,96 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBindTimeLayouts(t *testing.T) {
+	type request struct {
+		Since time.Time  `json:"since" http:"loc=query,time_format=unix"`
+		Until time.Time  `json:"until" http:"loc=query,time_format=unixmilli"`
+		Day   time.Time  `json:"day" http:"loc=query,time_format=DateOnly,02/01/2006,unix,required"`
+		Seen  *time.Time `json:"seen" http:"loc=query,time_format=RFC1123"`
+	}
+
+	t.Run("should parse unix timestamps", func(t *testing.T) {
+		var (
+			r = httptest.NewRequest(http.MethodGet, "/?since=1700000000&until=1700000000123&day=2024-03-01", nil)
+			v request
+		)
+
+		err := Bind(r, &v)
+		require.NoError(t, err)
+		assert.Equal(t, time.Unix(1700000000, 0).UTC(), v.Since)
+		assert.Equal(t, time.UnixMilli(1700000000123).UTC(), v.Until)
+	})
+
+	t.Run("should try every fallback layout", func(t *testing.T) {
+		expected := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+		for _, day := range []string{"2024-03-01", "01/03/2024", "1709251200"} {
+			var (
+				r = httptest.NewRequest(http.MethodGet, "/?day="+day, nil)
+				v request
+			)
+
+			err := Bind(r, &v)
+			require.NoError(t, err, day)
+			assert.True(t, expected.Equal(v.Day), day)
+		}
+	})
+
+	t.Run("should accept named layouts containing commas", func(t *testing.T) {
+		var (
+			r = httptest.NewRequest(http.MethodGet, "/?day=2024-03-01&seen=Fri,+01+Mar+2024+10:00:00+UTC", nil)
+			v request
+		)
+
+		err := Bind(r, &v)
+		require.NoError(t, err)
+		require.NotNil(t, v.Seen)
+		assert.Equal(t, 10, v.Seen.Hour())
+	})
+
+	t.Run("should keep the options following the layouts", func(t *testing.T) {
+		var (
+			r = httptest.NewRequest(http.MethodGet, "/", nil)
+			v request
+		)
+
+		err := Bind(r, &v)
+		assert.ErrorIs(t, err, ErrMissingParameter)
+	})
+
+	t.Run("should report values not matching any layout", func(t *testing.T) {
+		var (
+			r = httptest.NewRequest(http.MethodGet, "/?day=yesterday", nil)
+			v request
+		)
+
+		err := Bind(r, &v)
+
+		var errs BindErrors
+		require.True(t, errors.As(err, &errs))
+		assert.ErrorContains(t, errs[0], "DateOnly, 02/01/2006, unix")
+	})
+
+	t.Run("should use unix as default layout", func(t *testing.T) {
+		var (
+			r = httptest.NewRequest(http.MethodGet, "/?at=60", nil)
+			v = struct {
+				At time.Time `json:"at"`
+			}{}
+		)
+
+		err := BindQuery(r, &v, &BindOptions{DefaultTimeLayout: "unix"})
+		require.NoError(t, err)
+		assert.Equal(t, int64(60), v.At.Unix())
+	})
+}
@@ -0,0 +1,62 @@
+package http
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// Validator is implemented by binding targets that validate themselves. When
+// BindOptions.EnableValidation is set, Validate is called after the target
+// is bound and its `validate` tags are checked.
+type Validator interface {
+	Validate() error
+}
+
+var defaultValidator = sync.OnceValue(func() *validator.Validate {
+	v := validator.New(validator.WithRequiredStructEnabled())
+
+	// Fields are reported using the same names used to bind them.
+	v.RegisterTagNameFunc(func(sf reflect.StructField) string {
+		name, ok := resolveFieldName(sf, false)
+		if !ok {
+			return ""
+		}
+
+		return name
+	})
+
+	return v
+})
+
+// validateTarget validates a bound target when validation is enabled. Errors
+// of `validate` tags are returned as FieldErrors, while errors of the target
+// Validate method are returned as they are.
+func validateTarget(target interface{}, opt *BindOptions) error {
+	if !opt.EnableValidation {
+		return nil
+	}
+
+	v := opt.Validator
+	if v == nil {
+		v = defaultValidator()
+	}
+
+	if err := v.Struct(target); err != nil {
+		var validationErrors validator.ValidationErrors
+		if !errors.As(err, &validationErrors) {
+			return err
+		}
+
+		fieldErrors, _ := NewFieldErrors(validationErrors)
+		return fieldErrors
+	}
+
+	if t, ok := target.(Validator); ok {
+		return t.Validate()
+	}
+
+	return nil
+}
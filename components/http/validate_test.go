@@ -0,0 +1,79 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type validatedRequest struct {
+	From int `json:"from" http:"loc=query"`
+	To   int `json:"to" http:"loc=query"`
+}
+
+func (v *validatedRequest) Validate() error {
+	if v.From > v.To {
+		return errors.New("from must not be greater than to")
+	}
+
+	return nil
+}
+
+func TestBindValidation(t *testing.T) {
+	t.Run("should not validate unless enabled", func(t *testing.T) {
+		var (
+			r = httptest.NewRequest(http.MethodGet, "/?limit=0", nil)
+			v = struct {
+				Limit int `json:"limit" http:"loc=query" validate:"min=1"`
+			}{}
+		)
+
+		require.NoError(t, Bind(r, &v))
+	})
+
+	t.Run("should return field errors for validate tags", func(t *testing.T) {
+		var (
+			r = httptest.NewRequest(http.MethodGet, "/?limit=0&email=invalid", nil)
+			v = struct {
+				Limit int    `json:"limit" http:"loc=query" validate:"min=1"`
+				Email string `json:"email" http:"loc=query" validate:"email"`
+				Name  string `json:"name" http:"loc=query" validate:"required"`
+			}{}
+		)
+
+		err := Bind(r, &v, &BindOptions{EnableValidation: true})
+		require.Error(t, err)
+
+		fieldErrors, ok := NewFieldErrors(err)
+		require.True(t, ok)
+		assert.Equal(t, FieldErrors{
+			"limit": {"must be at least 1"},
+			"email": {"must be a valid email address"},
+			"name":  {"is required"},
+		}, fieldErrors)
+	})
+
+	t.Run("should call the target Validate method", func(t *testing.T) {
+		var (
+			r = httptest.NewRequest(http.MethodGet, "/?from=10&to=5", nil)
+			v validatedRequest
+		)
+
+		err := BindQuery(r, &v, &BindOptions{EnableValidation: true})
+		assert.EqualError(t, err, "from must not be greater than to")
+	})
+
+	t.Run("should pass when the target is valid", func(t *testing.T) {
+		var (
+			r = httptest.NewRequest(http.MethodGet, "/?from=1&to=5", nil)
+			v validatedRequest
+		)
+
+		require.NoError(t, Bind(r, &v, &BindOptions{EnableValidation: true}))
+		assert.Equal(t, 5, v.To)
+	})
+}
@@ -0,0 +1,190 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	defaultVersionHeader = "Accept-Version"
+)
+
+type versionContextKey struct{}
+
+// VersionRouterOptions configures the handler returned by VersionRouter.
+type VersionRouterOptions struct {
+	// Versions maps each API version (e.g. "v1") to the handler that serves
+	// it. The same handler can be used for several versions and can check
+	// the selected one with VersionFromContext.
+	Versions map[string]http.Handler
+
+	// Default is the version used when the request does not select one. If
+	// empty, these requests are rejected with 404 Not Found.
+	Default string
+
+	// Header is the request header used to select a version when the path
+	// does not have a version prefix. Defaults to "Accept-Version".
+	Header string
+}
+
+// VersionRouter returns a handler that dispatches requests between API
+// versions. A version is selected, in order, by:
+//
+//  1. A path prefix with its name, e.g. /v2/users, which is removed before
+//     calling the version handler;
+//  2. The version header (Accept-Version by default);
+//  3. The default version.
+//
+// The selected version is available to handlers through VersionFromContext.
+// Requests selecting an unknown version through the header receive a
+// 400 Bad Request.
+func VersionRouter(options VersionRouterOptions) http.Handler {
+	header := options.Header
+	if header == "" {
+		header = defaultVersionHeader
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if version, rest, ok := versionFromPath(r.URL.Path, options.Versions); ok {
+			serveVersion(w, stripVersionPrefix(r, rest), version, options.Versions[version])
+			return
+		}
+
+		if version := strings.TrimSpace(r.Header.Get(header)); version != "" {
+			h, ok := options.Versions[version]
+			if !ok {
+				http.Error(w, fmt.Sprintf("unsupported API version '%s'", version), http.StatusBadRequest)
+				return
+			}
+
+			serveVersion(w, r, version, h)
+			return
+		}
+
+		if h, ok := options.Versions[options.Default]; ok {
+			serveVersion(w, r, options.Default, h)
+			return
+		}
+
+		http.NotFound(w, r)
+	})
+}
+
+func versionFromPath(path string, versions map[string]http.Handler) (string, string, bool) {
+	segment, rest, _ := strings.Cut(strings.TrimPrefix(path, "/"), "/")
+	if _, ok := versions[segment]; !ok {
+		return "", "", false
+	}
+
+	return segment, "/" + rest, true
+}
+
+func stripVersionPrefix(r *http.Request, path string) *http.Request {
+	r2 := new(http.Request)
+	*r2 = *r
+	r2.URL = new(url.URL)
+	*r2.URL = *r.URL
+	r2.URL.Path = path
+	r2.URL.RawPath = ""
+
+	return r2
+}
+
+func serveVersion(w http.ResponseWriter, r *http.Request, version string, h http.Handler) {
+	h.ServeHTTP(w, r.WithContext(WithVersion(r.Context(), version)))
+}
+
+// WithVersion returns a copy of ctx carrying the API version.
+func WithVersion(ctx context.Context, version string) context.Context {
+	return context.WithValue(ctx, versionContextKey{}, version)
+}
+
+// VersionFromContext retrieves the API version selected for the request.
+func VersionFromContext(ctx context.Context) (string, bool) {
+	v, ok := ctx.Value(versionContextKey{}).(string)
+	return v, ok
+}
+
+// SplitOpenAPIByVersion splits an OpenAPI 3 document in JSON format into one
+// document per API version, so every version exposes only its own contract.
+// Paths starting with a version prefix, e.g. /v2/users, go to the document
+// of that version without the prefix, like VersionRouter presents them to
+// the version handlers. Paths without a version prefix are shared by all
+// versions. Everything else in the document is kept as is.
+func SplitOpenAPIByVersion(data []byte, versions []string) (map[string][]byte, error) {
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("could not parse OpenAPI document: %w", err)
+	}
+
+	var paths map[string]json.RawMessage
+	if raw, ok := doc["paths"]; ok {
+		if err := json.Unmarshal(raw, &paths); err != nil {
+			return nil, fmt.Errorf("could not parse OpenAPI document paths: %w", err)
+		}
+	}
+
+	split := make(map[string]map[string]json.RawMessage, len(versions))
+	for _, version := range versions {
+		split[version] = make(map[string]json.RawMessage)
+	}
+
+	for path, item := range paths {
+		segment, rest, _ := strings.Cut(strings.TrimPrefix(path, "/"), "/")
+		if versionPaths, ok := split[segment]; ok {
+			versionPaths["/"+rest] = item
+			continue
+		}
+
+		for _, versionPaths := range split {
+			if _, ok := versionPaths[path]; !ok {
+				versionPaths[path] = item
+			}
+		}
+	}
+
+	documents := make(map[string][]byte, len(versions))
+	for version, versionPaths := range split {
+		versionDoc := make(map[string]json.RawMessage, len(doc))
+		for k, v := range doc {
+			versionDoc[k] = v
+		}
+
+		raw, err := json.Marshal(versionPaths)
+		if err != nil {
+			return nil, err
+		}
+		versionDoc["paths"] = raw
+
+		out, err := json.Marshal(versionDoc)
+		if err != nil {
+			return nil, err
+		}
+		documents[version] = out
+	}
+
+	return documents, nil
+}
+
+// OpenAPIVersionHandler returns a handler writing the OpenAPI document of
+// the version selected for the request, like the ones returned by
+// SplitOpenAPIByVersion. It is meant to be served by the version handlers
+// of a VersionRouter, e.g. at /v1/openapi.json. Requests without a known
+// version receive a 404 Not Found.
+func OpenAPIVersionHandler(documents map[string][]byte) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		version, _ := VersionFromContext(r.Context())
+		doc, ok := documents[version]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(doc)
+	})
+}
@@ -0,0 +1,169 @@
+package http
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVersionRouter(t *testing.T) {
+	echo := func(name string) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			version, _ := VersionFromContext(r.Context())
+			_, _ = io.WriteString(w, name+":"+version+":"+r.URL.Path)
+		})
+	}
+
+	var (
+		shared = echo("shared")
+		router = VersionRouter(VersionRouterOptions{
+			Versions: map[string]http.Handler{
+				"v1": shared,
+				"v2": shared,
+				"v3": echo("new"),
+			},
+			Default: "v1",
+		})
+	)
+
+	serve := func(r *http.Request) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, r)
+		return rec
+	}
+
+	t.Run("should select version by path prefix", func(t *testing.T) {
+		rec := serve(httptest.NewRequest(http.MethodGet, "/v2/users", nil))
+		assert.Equal(t, "shared:v2:/users", rec.Body.String())
+
+		rec = serve(httptest.NewRequest(http.MethodGet, "/v3/users", nil))
+		assert.Equal(t, "new:v3:/users", rec.Body.String())
+	})
+
+	t.Run("should select version by header", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/users", nil)
+		r.Header.Set("Accept-Version", "v3")
+
+		rec := serve(r)
+		assert.Equal(t, "new:v3:/users", rec.Body.String())
+	})
+
+	t.Run("should prefer the path over the header", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/v2/users", nil)
+		r.Header.Set("Accept-Version", "v3")
+
+		rec := serve(r)
+		assert.Equal(t, "shared:v2:/users", rec.Body.String())
+	})
+
+	t.Run("should use the default version", func(t *testing.T) {
+		rec := serve(httptest.NewRequest(http.MethodGet, "/users", nil))
+		assert.Equal(t, "shared:v1:/users", rec.Body.String())
+	})
+
+	t.Run("should reject unknown header versions", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/users", nil)
+		r.Header.Set("Accept-Version", "v9")
+
+		rec := serve(r)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("should return not found without default version", func(t *testing.T) {
+		var (
+			rec = httptest.NewRecorder()
+			r   = httptest.NewRequest(http.MethodGet, "/users", nil)
+		)
+
+		VersionRouter(VersionRouterOptions{
+			Versions: map[string]http.Handler{"v1": shared},
+			Header:   "X-API-Version",
+		}).ServeHTTP(rec, r)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
+
+func TestSplitOpenAPIByVersion(t *testing.T) {
+	doc := []byte(`{
+		"openapi": "3.0.0",
+		"info": {"title": "users"},
+		"paths": {
+			"/v1/users": {"get": {"parameters": [{"name": "page", "in": "query"}]}},
+			"/v2/users": {"get": {"parameters": [{"name": "cursor", "in": "query"}]}},
+			"/v2/users/{id}": {"get": {}},
+			"/health": {"get": {}}
+		}
+	}`)
+
+	t.Run("should split paths per version", func(t *testing.T) {
+		docs, err := SplitOpenAPIByVersion(doc, []string{"v1", "v2"})
+		require.NoError(t, err)
+		require.Len(t, docs, 2)
+
+		paths := func(t *testing.T, doc []byte) map[string][]string {
+			parsed, err := ParseOpenAPI(doc)
+			require.NoError(t, err)
+
+			out := make(map[string][]string)
+			for _, route := range parsed.Routes() {
+				var names []string
+				for _, p := range route.Operation.Parameters {
+					names = append(names, p.Name)
+				}
+				out[route.Path] = names
+			}
+
+			return out
+		}
+
+		assert.Equal(t, map[string][]string{
+			"/health": nil,
+			"/users":  {"page"},
+		}, paths(t, docs["v1"]))
+		assert.Equal(t, map[string][]string{
+			"/health":     nil,
+			"/users":      {"cursor"},
+			"/users/{id}": nil,
+		}, paths(t, docs["v2"]))
+
+		var meta struct {
+			Info struct {
+				Title string `json:"title"`
+			} `json:"info"`
+		}
+		require.NoError(t, json.Unmarshal(docs["v2"], &meta))
+		assert.Equal(t, "users", meta.Info.Title)
+	})
+
+	t.Run("should fail with invalid documents", func(t *testing.T) {
+		_, err := SplitOpenAPIByVersion([]byte("{"), []string{"v1"})
+		assert.Error(t, err)
+	})
+
+	t.Run("should serve the document of the selected version", func(t *testing.T) {
+		docs, err := SplitOpenAPIByVersion(doc, []string{"v1", "v2"})
+		require.NoError(t, err)
+
+		var (
+			spec   = OpenAPIVersionHandler(docs)
+			router = VersionRouter(VersionRouterOptions{
+				Versions: map[string]http.Handler{"v1": spec, "v2": spec},
+			})
+			rec = httptest.NewRecorder()
+		)
+
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v2/openapi.json", nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+		assert.Equal(t, string(docs["v2"]), rec.Body.String())
+
+		rec = httptest.NewRecorder()
+		spec.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
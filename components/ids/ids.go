@@ -0,0 +1,125 @@
+// Package ids provides the generation of the IDs created by the framework,
+// such as request tracker IDs, lock owners and session IDs, allowing tests
+// to replace random IDs with deterministic ones.
+package ids
+
+import (
+	"crypto/rand"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/mikros-dev/mikros/apis/integrations"
+)
+
+// Generator creates unique IDs.
+type Generator interface {
+	Generate() string
+}
+
+// GeneratorFunc adapts a function into a Generator.
+type GeneratorFunc func() string
+
+// Generate calls f.
+func (f GeneratorFunc) Generate() string {
+	return f()
+}
+
+// Random returns a Generator of random UUIDv4 strings.
+func Random() Generator {
+	return GeneratorFunc(func() string {
+		b := make([]byte, 16)
+		_, _ = rand.Read(b)
+
+		b[6] = (b[6] & 0x0f) | 0x40 // version 4
+		b[8] = (b[8] & 0x3f) | 0x80 // variant 10
+
+		return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+	})
+}
+
+// Sequential is a deterministic Generator, creating IDs like "prefix-000001",
+// "prefix-000002", and so on. It is safe for concurrent use.
+type Sequential struct {
+	prefix string
+	next   atomic.Uint64
+}
+
+// NewSequential creates a new Sequential generator.
+func NewSequential(prefix string) *Sequential {
+	return &Sequential{
+		prefix: prefix,
+	}
+}
+
+// Generate returns the next ID of the sequence.
+func (s *Sequential) Generate() string {
+	return fmt.Sprintf("%s-%06d", s.prefix, s.next.Add(1))
+}
+
+// Reset restarts the sequence.
+func (s *Sequential) Reset() {
+	s.next.Store(0)
+}
+
+var (
+	mu       sync.RWMutex
+	override Generator
+	random   = Random()
+)
+
+// New creates a new ID with the generator set with Set or, by default, a
+// random one.
+func New() string {
+	if g, ok := Override(); ok {
+		return g.Generate()
+	}
+
+	return random.Generate()
+}
+
+// Set replaces the generator used by the framework until the returned
+// function is called, which restores the previous one. Since it is global,
+// tests setting it must not run in parallel.
+func Set(g Generator) func() {
+	mu.Lock()
+	defer mu.Unlock()
+
+	previous := override
+	override = g
+
+	return func() {
+		mu.Lock()
+		defer mu.Unlock()
+
+		override = previous
+	}
+}
+
+// Override returns the generator set with Set, if any.
+func Override() (Generator, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	return override, override != nil
+}
+
+// Tracker wraps a tracker so that, while a generator is set with Set, its
+// IDs are created by it instead of by the tracker.
+func Tracker(t integrations.Tracker) integrations.Tracker {
+	return &tracker{
+		Tracker: t,
+	}
+}
+
+type tracker struct {
+	integrations.Tracker
+}
+
+func (t *tracker) Generate() string {
+	if g, ok := Override(); ok {
+		return g.Generate()
+	}
+
+	return t.Tracker.Generate()
+}
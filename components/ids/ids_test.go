@@ -0,0 +1,55 @@
+package ids
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeTracker struct{}
+
+func (fakeTracker) Generate() string { return "tracker-id" }
+
+func (fakeTracker) Add(ctx context.Context, _ string) context.Context { return ctx }
+
+func (fakeTracker) Retrieve(_ context.Context) (string, bool) { return "", false }
+
+func TestIDs(t *testing.T) {
+	t.Run("should generate random UUIDs by default", func(t *testing.T) {
+		var (
+			a = New()
+			b = New()
+		)
+
+		assert.Regexp(t, regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`), a)
+		assert.NotEqual(t, a, b)
+	})
+
+	t.Run("should generate sequential IDs while a generator is set", func(t *testing.T) {
+		seq := NewSequential("test")
+		restore := Set(seq)
+
+		assert.Equal(t, "test-000001", New())
+		assert.Equal(t, "test-000002", New())
+
+		seq.Reset()
+		assert.Equal(t, "test-000001", New())
+
+		restore()
+		_, ok := Override()
+		assert.False(t, ok)
+		assert.NotContains(t, New(), "test-")
+	})
+
+	t.Run("should replace tracker IDs while a generator is set", func(t *testing.T) {
+		tracker := Tracker(fakeTracker{})
+		assert.Equal(t, "tracker-id", tracker.Generate())
+
+		restore := Set(NewSequential("trk"))
+		defer restore()
+
+		assert.Equal(t, "trk-000001", tracker.Generate())
+	})
+}
@@ -0,0 +1,108 @@
+package logger
+
+import (
+	"context"
+
+	logger_api "github.com/mikros-dev/mikros/apis/features/logger"
+)
+
+type contextKey struct{}
+
+// ContextKey is the key under which the request-scoped logger is stored
+// inside a context. Runtimes using contexts that don't support
+// context.WithValue, such as fasthttp.RequestCtx, can store the logger
+// directly as a user value with it.
+var ContextKey = contextKey{}
+
+// NewContext returns a copy of ctx carrying log.
+func NewContext(ctx context.Context, log logger_api.API) context.Context {
+	return context.WithValue(ctx, ContextKey, log)
+}
+
+// FromContext retrieves the request-scoped logger that service runtimes store
+// inside the context of every request, which adds the request information,
+// like its method and tracker ID, into all messages:
+//
+//	func (s *Service) GetUser(ctx context.Context, req *GetUserRequest) (*GetUserResponse, error) {
+//		logger.FromContext(ctx).Info(ctx, "retrieving user", logger.String("user.id", req.ID))
+//		...
+//	}
+//
+// When ctx has no logger, a logger discarding all messages is returned.
+func FromContext(ctx context.Context) logger_api.API {
+	if ctx != nil {
+		if l, ok := ctx.Value(ContextKey).(logger_api.API); ok && l != nil {
+			return l
+		}
+	}
+
+	return nopLogger{}
+}
+
+// With returns a logger that adds attrs into every message written through
+// log.
+func With(log logger_api.API, attrs ...logger_api.Attribute) logger_api.API {
+	if len(attrs) == 0 {
+		return log
+	}
+
+	if s, ok := log.(*scopedLogger); ok {
+		return &scopedLogger{
+			API:   s.API,
+			attrs: append(append([]logger_api.Attribute{}, s.attrs...), attrs...),
+		}
+	}
+
+	return &scopedLogger{
+		API:   log,
+		attrs: attrs,
+	}
+}
+
+// scopedLogger adds its attributes into the messages of the logger it wraps.
+type scopedLogger struct {
+	logger_api.API
+	attrs []logger_api.Attribute
+}
+
+func (s *scopedLogger) Debug(ctx context.Context, msg string, attrs ...logger_api.Attribute) {
+	s.API.Debug(ctx, msg, s.merge(attrs)...)
+}
+
+func (s *scopedLogger) Internal(ctx context.Context, msg string, attrs ...logger_api.Attribute) {
+	s.API.Internal(ctx, msg, s.merge(attrs)...)
+}
+
+func (s *scopedLogger) Info(ctx context.Context, msg string, attrs ...logger_api.Attribute) {
+	s.API.Info(ctx, msg, s.merge(attrs)...)
+}
+
+func (s *scopedLogger) Warn(ctx context.Context, msg string, attrs ...logger_api.Attribute) {
+	s.API.Warn(ctx, msg, s.merge(attrs)...)
+}
+
+func (s *scopedLogger) Error(ctx context.Context, msg string, attrs ...logger_api.Attribute) {
+	s.API.Error(ctx, msg, s.merge(attrs)...)
+}
+
+func (s *scopedLogger) Fatal(ctx context.Context, msg string, attrs ...logger_api.Attribute) {
+	s.API.Fatal(ctx, msg, s.merge(attrs)...)
+}
+
+func (s *scopedLogger) merge(attrs []logger_api.Attribute) []logger_api.Attribute {
+	merged := make([]logger_api.Attribute, 0, len(s.attrs)+len(attrs))
+	merged = append(merged, s.attrs...)
+	return append(merged, attrs...)
+}
+
+// nopLogger discards all messages.
+type nopLogger struct{}
+
+func (nopLogger) Debug(context.Context, string, ...logger_api.Attribute)    {}
+func (nopLogger) Internal(context.Context, string, ...logger_api.Attribute) {}
+func (nopLogger) Info(context.Context, string, ...logger_api.Attribute)     {}
+func (nopLogger) Warn(context.Context, string, ...logger_api.Attribute)     {}
+func (nopLogger) Error(context.Context, string, ...logger_api.Attribute)    {}
+func (nopLogger) Fatal(context.Context, string, ...logger_api.Attribute)    {}
+func (nopLogger) SetLogLevel(string) (string, error)                        { return "", nil }
+func (nopLogger) Level() string                                             { return "" }
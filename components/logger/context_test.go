@@ -0,0 +1,66 @@
+package logger
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	logger_api "github.com/mikros-dev/mikros/apis/features/logger"
+)
+
+type fakeLogger struct {
+	logger_api.API
+	attrs map[string]interface{}
+}
+
+func (f *fakeLogger) Info(_ context.Context, _ string, attrs ...logger_api.Attribute) {
+	f.attrs = make(map[string]interface{})
+	for _, a := range attrs {
+		f.attrs[a.Key()] = a.Value()
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	t.Run("should retrieve the logger stored in the context", func(t *testing.T) {
+		var (
+			l   = &fakeLogger{}
+			ctx = NewContext(context.Background(), With(l, String("http.method", "GET")))
+		)
+
+		FromContext(ctx).Info(ctx, "message", String("user.id", "42"))
+		assert.Equal(t, map[string]interface{}{
+			"http.method": "GET",
+			"user.id":     "42",
+		}, l.attrs)
+	})
+
+	t.Run("should discard messages without a logger", func(t *testing.T) {
+		l := FromContext(context.Background())
+		assert.NotNil(t, l)
+		assert.NotPanics(t, func() {
+			l.Info(context.Background(), "message")
+		})
+	})
+}
+
+func TestWith(t *testing.T) {
+	t.Run("should accumulate attributes", func(t *testing.T) {
+		var (
+			l      = &fakeLogger{}
+			parent = With(l, String("a", "1"))
+			child  = With(parent, String("b", "2"))
+		)
+
+		child.Info(context.Background(), "message")
+		assert.Equal(t, map[string]interface{}{"a": "1", "b": "2"}, l.attrs)
+
+		parent.Info(context.Background(), "message")
+		assert.Equal(t, map[string]interface{}{"a": "1"}, l.attrs)
+	})
+
+	t.Run("should return the logger without attributes", func(t *testing.T) {
+		l := &fakeLogger{}
+		assert.Same(t, l, With(l))
+	})
+}
@@ -0,0 +1,111 @@
+package options
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/mikros-dev/mikros/components/definition"
+)
+
+// EnvResolver is implemented by options that have different values for
+// each deployment environment. They are replaced by the value of the
+// current environment when the service is created.
+type EnvResolver interface {
+	ResolveEnv(env definition.DeploymentEnv) interface{}
+}
+
+// ByEnv holds a value for each deployment environment, allowing services to
+// vary settings by environment without branching around NewServiceOptions
+// construction:
+//
+//	FeatureInputs: map[string]interface{}{
+//		"cache": options.ByEnv[*CacheOptions]{
+//			Production: &CacheOptions{Size: 1024},
+//			Default:    &CacheOptions{Size: 16},
+//		},
+//	}
+//
+// An environment without a value (i.e., with its zero value) uses Default.
+type ByEnv[T any] struct {
+	Production  T
+	Test        T
+	Development T
+	Local       T
+	Default     T
+}
+
+// Resolve returns the value for the deployment environment.
+func (b ByEnv[T]) Resolve(env definition.DeploymentEnv) T {
+	var value T
+
+	switch env {
+	case definition.DeploymentEnvProduction:
+		value = b.Production
+	case definition.DeploymentEnvTest:
+		value = b.Test
+	case definition.DeploymentEnvDevelopment:
+		value = b.Development
+	case definition.DeploymentEnvLocal:
+		value = b.Local
+	default:
+		return b.Default
+	}
+
+	if reflect.ValueOf(&value).Elem().IsZero() {
+		return b.Default
+	}
+
+	return value
+}
+
+// ResolveEnv implements the EnvResolver interface.
+func (b ByEnv[T]) ResolveEnv(env definition.DeploymentEnv) interface{} {
+	return b.Resolve(env)
+}
+
+// ServiceByEnv holds service options for each deployment environment. It
+// can be used as a NewServiceOptions.Service entry. All values must be of
+// the same runtime kind.
+type ServiceByEnv ByEnv[ServiceOptions]
+
+// Kind returns the runtime type of the options.
+func (s ServiceByEnv) Kind() definition.RuntimeType {
+	for _, o := range []ServiceOptions{s.Default, s.Production, s.Test, s.Development, s.Local} {
+		if o != nil {
+			return o.Kind()
+		}
+	}
+
+	return definition.RuntimeType{}
+}
+
+// ResolveEnv implements the EnvResolver interface.
+func (s ServiceByEnv) ResolveEnv(env definition.DeploymentEnv) interface{} {
+	return ByEnv[ServiceOptions](s).Resolve(env)
+}
+
+// ResolveEnv replaces all service options and feature inputs implementing
+// EnvResolver with their values for the deployment environment.
+func (o *NewServiceOptions) ResolveEnv(env definition.DeploymentEnv) error {
+	for name, opt := range o.Service {
+		resolver, ok := opt.(EnvResolver)
+		if !ok {
+			continue
+		}
+
+		resolved, ok := resolver.ResolveEnv(env).(ServiceOptions)
+		if !ok || resolved == nil {
+			return fmt.Errorf("service '%s' has no options for the '%s' environment", name, env.String())
+		}
+
+		o.Service[name] = resolved
+	}
+
+	for name, input := range o.FeatureInputs {
+		if resolver, ok := input.(EnvResolver); ok {
+			o.FeatureInputs[name] = resolver.ResolveEnv(env)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,169 @@
+package options
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mikros-dev/mikros/components/definition"
+)
+
+func TestByEnvResolve(t *testing.T) {
+	values := ByEnv[int]{
+		Production:  1,
+		Test:        2,
+		Development: 3,
+		Default:     10,
+	}
+
+	tests := []struct {
+		name     string
+		env      definition.DeploymentEnv
+		expected int
+	}{
+		{name: "should resolve production", env: definition.DeploymentEnvProduction, expected: 1},
+		{name: "should resolve test", env: definition.DeploymentEnvTest, expected: 2},
+		{name: "should resolve development", env: definition.DeploymentEnvDevelopment, expected: 3},
+		{name: "should fall back to default without a value", env: definition.DeploymentEnvLocal, expected: 10},
+		{name: "should fall back to default for unknown environments", env: definition.DeploymentEnvUnknown, expected: 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, values.Resolve(tt.env))
+			assert.Equal(t, tt.expected, values.ResolveEnv(tt.env))
+		})
+	}
+
+	t.Run("should fall back to default for nil pointers", func(t *testing.T) {
+		var (
+			def    = &HTTPServiceOptions{BasePath: "/default"}
+			prod   = &HTTPServiceOptions{BasePath: "/prod"}
+			values = ByEnv[*HTTPServiceOptions]{Production: prod, Default: def}
+		)
+
+		assert.Same(t, prod, values.Resolve(definition.DeploymentEnvProduction))
+		assert.Same(t, def, values.Resolve(definition.DeploymentEnvLocal))
+	})
+}
+
+func TestServiceByEnv(t *testing.T) {
+	var (
+		prod = &HTTPServiceOptions{BasePath: "/prod"}
+		def  = &HTTPServiceOptions{BasePath: "/default"}
+	)
+
+	tests := []struct {
+		name         string
+		options      ServiceByEnv
+		env          definition.DeploymentEnv
+		expected     interface{}
+		expectedKind definition.RuntimeType
+	}{
+		{
+			name:         "should resolve the environment options",
+			options:      ServiceByEnv{Production: prod, Default: def},
+			env:          definition.DeploymentEnvProduction,
+			expected:     prod,
+			expectedKind: definition.RuntimeTypeHTTP,
+		},
+		{
+			name:         "should fall back to the default options",
+			options:      ServiceByEnv{Production: prod, Default: def},
+			env:          definition.DeploymentEnvTest,
+			expected:     def,
+			expectedKind: definition.RuntimeTypeHTTP,
+		},
+		{
+			name:         "should take the kind from any environment",
+			options:      ServiceByEnv{Local: &GrpcServiceOptions{}},
+			env:          definition.DeploymentEnvProduction,
+			expected:     nil,
+			expectedKind: definition.RuntimeTypeGRPC,
+		},
+		{
+			name:         "should have no kind without options",
+			env:          definition.DeploymentEnvProduction,
+			expected:     nil,
+			expectedKind: definition.RuntimeType{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolved := tt.options.ResolveEnv(tt.env)
+			if tt.expected == nil {
+				assert.Nil(t, resolved)
+			} else {
+				assert.Same(t, tt.expected, resolved)
+			}
+			assert.Equal(t, tt.expectedKind, tt.options.Kind())
+		})
+	}
+}
+
+func TestNewServiceOptionsResolveEnv(t *testing.T) {
+	var (
+		prod = &HTTPServiceOptions{BasePath: "/prod"}
+		def  = &HTTPServiceOptions{BasePath: "/default"}
+		grpc = &GrpcServiceOptions{}
+	)
+
+	tests := []struct {
+		name           string
+		env            definition.DeploymentEnv
+		expectedHTTP   ServiceOptions
+		expectedInputs map[string]interface{}
+	}{
+		{
+			name:         "should resolve the environment entries",
+			env:          definition.DeploymentEnvProduction,
+			expectedHTTP: prod,
+			expectedInputs: map[string]interface{}{
+				"cache": 1024,
+				"plain": "value",
+			},
+		},
+		{
+			name:         "should fall back to the default entries",
+			env:          definition.DeploymentEnvDevelopment,
+			expectedHTTP: def,
+			expectedInputs: map[string]interface{}{
+				"cache": 16,
+				"plain": "value",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := &NewServiceOptions{
+				Service: map[string]ServiceOptions{
+					"http": ServiceByEnv{Production: prod, Default: def},
+					"grpc": grpc,
+				},
+				FeatureInputs: map[string]interface{}{
+					"cache": ByEnv[int]{Production: 1024, Default: 16},
+					"plain": "value",
+				},
+			}
+
+			require.NoError(t, opts.ResolveEnv(tt.env))
+			assert.Same(t, tt.expectedHTTP, opts.Service["http"])
+			assert.Same(t, grpc, opts.Service["grpc"])
+			assert.Equal(t, tt.expectedInputs, opts.FeatureInputs)
+		})
+	}
+
+	t.Run("should fail when an environment has no service options", func(t *testing.T) {
+		opts := &NewServiceOptions{
+			Service: map[string]ServiceOptions{
+				"http": ServiceByEnv{Production: prod},
+			},
+		}
+
+		err := opts.ResolveEnv(definition.DeploymentEnvLocal)
+		assert.EqualError(t, err, "service 'http' has no options for the 'local' environment")
+	})
+}
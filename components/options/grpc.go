@@ -3,8 +3,10 @@ package options
 import (
 	"fmt"
 	"reflect"
+	"time"
 
 	"google.golang.org/grpc"
+	"google.golang.org/protobuf/reflect/protoreflect"
 
 	"github.com/mikros-dev/mikros/components/definition"
 	"github.com/mikros-dev/mikros/components/service"
@@ -13,6 +15,20 @@ import (
 // GrpcServiceOptions gathers options to initialize a gRPC runtime
 type GrpcServiceOptions struct {
 	ProtoServiceDescription *grpc.ServiceDesc
+
+	// AuthScopesOption is the custom proto method option holding the scopes
+	// required to call each method of the service. Its value can be a string,
+	// a repeated string or a message with a repeated string field named
+	// "scopes". Methods declaring scopes are enforced by the runtime auth
+	// interceptor.
+	AuthScopesOption protoreflect.ExtensionType
+
+	// StreamGracePeriod is how long calls, mainly streaming ones, have to
+	// end after the service starts stopping, when clients receive GOAWAY
+	// and handlers are notified through mcontext.ShutdownNotice, before
+	// their contexts are canceled. A zero value uses the Mikros default
+	// (10 s).
+	StreamGracePeriod time.Duration
 }
 
 // Kind returns the runtime type as definition.RuntimeTypeGRPC.
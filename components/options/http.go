@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/mikros-dev/mikros/components/definition"
+	mhttp "github.com/mikros-dev/mikros/components/http"
 )
 
 // HTTPServiceOptions defines runtime options for an HTTP service.
@@ -33,6 +34,17 @@ type HTTPServiceOptions struct {
 	// is enabled. A zero value uses the Mikros default (60 s).
 	IdleTimeout time.Duration
 
+	// ShutdownTimeout is the maximum time to wait for in-flight requests when
+	// the service stops. Connections still open after it are closed. A zero
+	// value uses the Mikros default (30 s).
+	ShutdownTimeout time.Duration
+
+	// StreamGracePeriod is how long requests, mainly long-lived ones such as
+	// event streams, have to end after being notified that the service is
+	// stopping, through mcontext.ShutdownNotice, before their contexts are
+	// canceled. A zero value uses the Mikros default (10 s).
+	StreamGracePeriod time.Duration
+
 	// MaxHeaderBytes controls the maximum number of bytes the server will
 	// read parsing request headers. A zero value uses the Go standard
 	// library default (1 MiB).
@@ -43,6 +55,11 @@ type HTTPServiceOptions struct {
 	// (such as CORS and authentication). The first element in the slice becomes
 	// the outermost wrapper.
 	Middlewares []func(handler http.Handler) http.Handler
+
+	// DefaultBindOptions, if set, are stored inside the context of every
+	// request, where the binding functions of the http component (Bind,
+	// BindQuery, Handler, ...) called without options pick them up.
+	DefaultBindOptions *mhttp.BindOptions
 }
 
 // Kind returns the runtime type, which is always definition.RuntimeTypeHTTP
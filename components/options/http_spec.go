@@ -3,11 +3,17 @@ package options
 import (
 	"github.com/mikros-dev/mikros/apis/runtimes/http_spec"
 	"github.com/mikros-dev/mikros/components/definition"
+	mhttp "github.com/mikros-dev/mikros/components/http"
 )
 
 // HTTPSpecServiceOptions gathers options to initialize a service as an HTTP service.
 type HTTPSpecServiceOptions struct {
 	ProtoHTTPServer http_spec.API
+
+	// DefaultBindOptions, if set, are stored inside the context of every
+	// request, where the binding functions of the http component called
+	// without options pick them up.
+	DefaultBindOptions *mhttp.BindOptions
 }
 
 // Kind returns the type of service implemented by HTTPSpecServiceOptions as
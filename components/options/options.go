@@ -24,6 +24,10 @@ type NewServiceOptions struct {
 	// GrpcClients should have every gRPC dependency that the service
 	// may have.
 	GrpcClients map[string]*GrpcClient
+
+	// Listen sets the addresses that runtime servers listen to. Settings
+	// of the 'service.toml' file listen section take precedence over it.
+	Listen *definition.Listen
 }
 
 // ServiceOptions is an interface that all services options structures must
@@ -12,6 +12,11 @@ const (
 	ErrorsFeatureName     = PluginNamePrefix + "errors"
 	DefinitionFeatureName = PluginNamePrefix + "definition"
 	EnvFeatureName        = PluginNamePrefix + "env"
+	WatchdogFeatureName   = PluginNamePrefix + "watchdog"
+	OAuth2FeatureName     = PluginNamePrefix + "oauth2"
+	LockFeatureName       = PluginNamePrefix + "lock"
+	CacheFeatureName      = PluginNamePrefix + "cache"
+	ClockFeatureName      = PluginNamePrefix + "clock"
 )
 
 // These HTTP features plugins don't exist here, but to be supported by
@@ -20,6 +25,7 @@ const (
 	HTTPCorsIntegrationName        = PluginNamePrefix + "http_cors"
 	HTTPSpecAuthIntegrationName    = PluginNamePrefix + "http_spec_auth"
 	HTTPAuthIntegrationName        = PluginNamePrefix + "http_auth"
+	GRPCAuthIntegrationName        = PluginNamePrefix + "grpc_auth"
 	TracingIntegrationName         = PluginNamePrefix + "tracing"
 	TrackerIntegrationName         = PluginNamePrefix + "tracker"
 	LoggerExtractorIntegrationName = PluginNamePrefix + "logger_extractor"
@@ -0,0 +1,69 @@
+package plugin
+
+import (
+	"context"
+	"time"
+)
+
+// HealthState is the health state of a feature.
+type HealthState string
+
+// Supported health states.
+const (
+	HealthStateHealthy   HealthState = "healthy"
+	HealthStateDegraded  HealthState = "degraded"
+	HealthStateUnhealthy HealthState = "unhealthy"
+)
+
+// FeatureHealth is the health reported by a feature.
+type FeatureHealth struct {
+	State HealthState
+
+	// Reason explains why the feature is not healthy.
+	Reason string
+}
+
+// FeatureHealthChecker is an optional behavior that a feature may have to
+// report its health, such as the state of its connection to an external
+// resource. Health must return quickly since it is called by readiness
+// checks.
+type FeatureHealthChecker interface {
+	Health(ctx context.Context) FeatureHealth
+}
+
+// ServiceHealth gives runtimes the service readiness, so their own health
+// checks follow the health of the features.
+type ServiceHealth interface {
+	// Check checks the health of the features and aggregates it.
+	Check(ctx context.Context) HealthReport
+
+	// Subscribe registers fn to receive the report of every check, including
+	// the periodic ones.
+	Subscribe(fn func(report HealthReport))
+}
+
+// HealthReport gathers the health of the service features.
+type HealthReport struct {
+	// State is the worst state among the features. Unhealthy non-critical
+	// features only degrade the service.
+	State HealthState `json:"state"`
+
+	// Ready tells if the service can receive requests, which is false only
+	// when a critical feature is unhealthy.
+	Ready    bool                  `json:"ready"`
+	Features []FeatureHealthReport `json:"features"`
+}
+
+// FeatureHealthReport is the health of a single feature.
+type FeatureHealthReport struct {
+	Name     string      `json:"name"`
+	State    HealthState `json:"state"`
+	Reason   string      `json:"reason,omitempty"`
+	Critical bool        `json:"critical"`
+
+	// Since is when the feature entered its current state.
+	Since time.Time `json:"since"`
+
+	// Transitions counts how many times the feature changed its state.
+	Transitions int64 `json:"transitions"`
+}
@@ -3,6 +3,7 @@ package plugin
 import (
 	"context"
 	"fmt"
+	"sync/atomic"
 )
 
 // FeatureSet gathers all features that a service can use during its execution.
@@ -22,6 +23,14 @@ type registeredFeature struct {
 	name         string
 	feature      Feature
 	dependencies []string
+	usage        atomic.Int64
+}
+
+// FeatureUsage holds how many times a feature was requested by the service.
+type FeatureUsage struct {
+	Name    string
+	Enabled bool
+	Calls   int64
 }
 
 // NewFeatureSet creates a new FeatureSet.
@@ -130,6 +139,28 @@ func (s *FeatureSet) Feature(name string) (Feature, error) {
 	return feature.feature, nil
 }
 
+// TrackUsage records that the feature was requested by the service.
+func (s *FeatureSet) TrackUsage(name string) {
+	if feature, ok := s.features[name]; ok {
+		feature.usage.Add(1)
+	}
+}
+
+// Usage returns how many times each registered feature was requested, in the
+// order they were registered.
+func (s *FeatureSet) Usage() []FeatureUsage {
+	usage := make([]FeatureUsage, 0, len(s.orderedFeatures))
+	for _, feature := range s.orderedFeatures {
+		usage = append(usage, FeatureUsage{
+			Name:    feature.name,
+			Enabled: feature.feature.IsEnabled(),
+			Calls:   feature.usage.Load(),
+		})
+	}
+
+	return usage
+}
+
 // Iterator returns a new FeatureSetIterator for iterating over the ordered
 // features in the FeatureSet.
 func (s *FeatureSet) Iterator() *FeatureSetIterator {
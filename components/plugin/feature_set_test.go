@@ -116,6 +116,29 @@ func TestFeatureSetRegisterAndFeature(t *testing.T) {
 	assert.Equal(t, "test_feature", feat.Name())
 }
 
+func TestFeatureSetUsage(t *testing.T) {
+	var (
+		set     = NewFeatureSet()
+		used    = &fakeFeature{}
+		unused  = &fakeFeature{}
+		enabled = UpdateInfoEntry{Enabled: true}
+	)
+
+	set.Register("used", used)
+	set.Register("unused", unused)
+	used.UpdateInfo(enabled)
+	unused.UpdateInfo(enabled)
+
+	set.TrackUsage("used")
+	set.TrackUsage("used")
+	set.TrackUsage("unknown")
+
+	assert.Equal(t, []FeatureUsage{
+		{Name: "used", Enabled: true, Calls: 2},
+		{Name: "unused", Enabled: true, Calls: 0},
+	}, set.Usage())
+}
+
 func TestFeatureSetRegisterReplacesFeatureInPlace(t *testing.T) {
 	var (
 		set   = NewFeatureSet()
@@ -2,14 +2,18 @@ package plugin
 
 import (
 	"context"
+	"net"
+	"strconv"
 
 	env_api "github.com/mikros-dev/mikros/apis/features/env"
 	errors_api "github.com/mikros-dev/mikros/apis/features/errors"
 	logger_api "github.com/mikros-dev/mikros/apis/features/logger"
 	mcontext "github.com/mikros-dev/mikros/components/context"
 	"github.com/mikros-dev/mikros/components/definition"
+	"github.com/mikros-dev/mikros/components/handoff"
 	"github.com/mikros-dev/mikros/components/options"
 	"github.com/mikros-dev/mikros/components/service"
+	"github.com/mikros-dev/mikros/internal/components/proxyproto"
 )
 
 // Runtime is an internal package behavior that all supported runtime types must
@@ -50,9 +54,46 @@ type RuntimeSettings interface {
 	Definitions(path string) (definition.ExternalRuntimeEntry, error)
 }
 
+// RuntimeConfig is an optional behavior that a plugin may have to report the
+// effective settings it is executing with, after merging its defaults,
+// service options and definitions.
+type RuntimeConfig interface {
+	// EffectiveConfig must return the runtime settings with their sources.
+	EffectiveConfig() []ConfigEntry
+}
+
+// RuntimeListener is an optional behavior that a plugin may have to report
+// the address its server is listening to, which is only known after it is
+// initialized when an ephemeral port is used.
+type RuntimeListener interface {
+	// Addr must return the address of the runtime server listener.
+	Addr() net.Addr
+}
+
+// ConfigSource tells where the effective value of a setting came from.
+type ConfigSource string
+
+// Supported configuration sources.
+const (
+	ConfigSourceDefault     ConfigSource = "default"
+	ConfigSourceDefinitions ConfigSource = "definitions"
+	ConfigSourceOptions     ConfigSource = "options"
+	ConfigSourceEnv         ConfigSource = "env"
+)
+
+// ConfigEntry is a single effective setting.
+type ConfigEntry struct {
+	Key    string       `json:"key"`
+	Value  interface{}  `json:"value"`
+	Source ConfigSource `json:"source"`
+}
+
 // RuntimeOptions gathers all available options to create a runtime object.
 type RuntimeOptions struct {
 	Port           service.ServerPort
+	Host           string
+	Network        string
+	ProxyProtocol  definition.ProxyProtocol
 	Type           definition.RuntimeType
 	Name           service.Name
 	Product        string
@@ -66,4 +107,43 @@ type RuntimeOptions struct {
 	Integrations   *IntegrationSet
 	ServiceHandler interface{}
 	Env            env_api.API
+
+	// Health is the service readiness, nil when it isn't available.
+	Health ServiceHealth
+}
+
+// Listen announces on the address of the runtime server, made of its Host
+// and Port, over its Network. Listeners are created with handoff, so they
+// can be passed to an upgraded process, and parse the PROXY protocol header
+// of their connections when ProxyProtocol is enabled.
+func (o *RuntimeOptions) Listen() (net.Listener, error) {
+	network := o.Network
+	if network == "" {
+		network = "tcp"
+	}
+
+	l, err := handoff.ListenNetwork(network, o.Address())
+	if err != nil {
+		return nil, err
+	}
+	if !o.ProxyProtocol.Enabled {
+		return l, nil
+	}
+
+	pl, err := proxyproto.NewListener(l, proxyproto.Options{
+		TrustedProxies: o.ProxyProtocol.TrustedProxies,
+		HeaderTimeout:  o.ProxyProtocol.HeaderTimeout,
+	})
+	if err != nil {
+		_ = l.Close()
+		return nil, err
+	}
+
+	return pl, nil
+}
+
+// Address returns the address of the runtime server, e.g. ":8080" or
+// "127.0.0.1:8080".
+func (o *RuntimeOptions) Address() string {
+	return net.JoinHostPort(o.Host, strconv.Itoa(o.Port.Int()))
 }
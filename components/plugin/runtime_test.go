@@ -0,0 +1,76 @@
+package plugin
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mikros-dev/mikros/components/definition"
+)
+
+func TestRuntimeOptionsListen(t *testing.T) {
+	t.Run("should listen to all interfaces by default", func(t *testing.T) {
+		opt := &RuntimeOptions{Port: 8080}
+		assert.Equal(t, ":8080", opt.Address())
+	})
+
+	t.Run("should listen to the configured host and network", func(t *testing.T) {
+		opt := &RuntimeOptions{Host: "127.0.0.1", Network: "tcp4"}
+		assert.Equal(t, "127.0.0.1:0", opt.Address())
+
+		l, err := opt.Listen()
+		require.NoError(t, err)
+		defer func() { _ = l.Close() }()
+
+		assert.True(t, l.Addr().(*net.TCPAddr).IP.IsLoopback())
+	})
+
+	t.Run("should parse the PROXY protocol header when enabled", func(t *testing.T) {
+		opt := &RuntimeOptions{
+			Host: "127.0.0.1",
+			ProxyProtocol: definition.ProxyProtocol{
+				Enabled:        true,
+				TrustedProxies: []string{"127.0.0.0/8"},
+			},
+		}
+
+		l, err := opt.Listen()
+		require.NoError(t, err)
+		defer func() { _ = l.Close() }()
+
+		go func() {
+			c, err := net.Dial("tcp", l.Addr().String())
+			if err != nil {
+				return
+			}
+			defer func() { _ = c.Close() }()
+			_, _ = c.Write([]byte("PROXY TCP4 192.0.2.10 192.0.2.1 51000 443\r\n"))
+		}()
+
+		c, err := l.Accept()
+		require.NoError(t, err)
+		defer func() { _ = c.Close() }()
+
+		assert.Equal(t, "192.0.2.10:51000", c.RemoteAddr().String())
+	})
+
+	t.Run("should fail with invalid PROXY protocol settings", func(t *testing.T) {
+		opt := &RuntimeOptions{
+			Host: "127.0.0.1",
+			ProxyProtocol: definition.ProxyProtocol{
+				Enabled:        true,
+				TrustedProxies: []string{"invalid"},
+			},
+		}
+
+		_, err := opt.Listen()
+		assert.Error(t, err)
+	})
+
+	t.Run("should format IPv6 hosts", func(t *testing.T) {
+		opt := &RuntimeOptions{Host: "::1", Port: 9090}
+		assert.Equal(t, "[::1]:9090", opt.Address())
+	})
+}
@@ -0,0 +1,171 @@
+// Package scaffold generates the boilerplate of new external features and
+// runtimes (service plugins), so they start following the framework
+// conventions: an Entry embedding client, its 'service.toml' definitions
+// section and tests.
+//
+// It can be used directly or through go:generate with the mikros-scaffold
+// command:
+//
+//	//go:generate go run github.com/mikros-dev/mikros/cmd/mikros-scaffold -kind=feature -name=audit_log
+package scaffold
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+// Kind is the kind of plugin to generate.
+type Kind string
+
+// Supported plugin kinds.
+const (
+	KindFeature Kind = "feature"
+	KindRuntime Kind = "runtime"
+)
+
+var (
+	namePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+)
+
+// Options gathers the settings of the generated plugin.
+type Options struct {
+	// Kind is the kind of the plugin. It defaults to KindFeature.
+	Kind Kind
+
+	// Name is the plugin name, in snake_case, e.g. "audit_log". It is the
+	// name used to register the plugin and its 'service.toml' section.
+	Name string
+
+	// Package is the Go package name of the generated files. It defaults
+	// to Name without underscores.
+	Package string
+
+	// Directory is where Write creates the files. It defaults to the
+	// current directory.
+	Directory string
+}
+
+// File is a generated source file.
+type File struct {
+	Name    string
+	Content []byte
+}
+
+// templateData is what the templates receive.
+type templateData struct {
+	Name     string
+	Package  string
+	TypeName string
+}
+
+// Generate returns the formatted source files of the plugin described by
+// options.
+func Generate(options *Options) ([]File, error) {
+	if options == nil {
+		return nil, errors.New("scaffold options cannot be nil")
+	}
+	if !namePattern.MatchString(options.Name) {
+		return nil, fmt.Errorf("invalid plugin name '%s', it must be in snake_case", options.Name)
+	}
+
+	kind := options.Kind
+	if kind == "" {
+		kind = KindFeature
+	}
+
+	templates, ok := kindTemplates[kind]
+	if !ok {
+		return nil, fmt.Errorf("unsupported plugin kind '%s'", kind)
+	}
+
+	data := templateData{
+		Name:     options.Name,
+		Package:  options.Package,
+		TypeName: typeName(options.Name),
+	}
+	if data.Package == "" {
+		data.Package = strings.ReplaceAll(options.Name, "_", "")
+	}
+
+	files := make([]File, 0, len(templates))
+	for _, t := range templates {
+		content, err := render(t, data)
+		if err != nil {
+			return nil, fmt.Errorf("could not generate '%s': %w", t.name, err)
+		}
+
+		files = append(files, File{
+			Name:    t.name,
+			Content: content,
+		})
+	}
+
+	return files, nil
+}
+
+// Write generates the plugin files inside options.Directory. It fails
+// without writing anything if any of the files already exist.
+func Write(options *Options) error {
+	files, err := Generate(options)
+	if err != nil {
+		return err
+	}
+
+	dir := options.Directory
+	if dir == "" {
+		dir = "."
+	}
+
+	for _, f := range files {
+		if _, err := os.Stat(filepath.Join(dir, f.Name)); err == nil {
+			return fmt.Errorf("file '%s' already exists", filepath.Join(dir, f.Name))
+		}
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	for _, f := range files {
+		if err := os.WriteFile(filepath.Join(dir, f.Name), f.Content, 0o644); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func render(t fileTemplate, data templateData) ([]byte, error) {
+	tpl, err := template.New(t.name).Parse(t.content)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := tpl.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+
+	return format.Source(buf.Bytes())
+}
+
+// typeName converts a snake_case name into CamelCase.
+func typeName(name string) string {
+	var b strings.Builder
+	for _, part := range strings.Split(name, "_") {
+		if part == "" {
+			continue
+		}
+
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+
+	return b.String()
+}
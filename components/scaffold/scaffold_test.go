@@ -0,0 +1,98 @@
+package scaffold
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func fileNames(files []File) []string {
+	names := make([]string, 0, len(files))
+	for _, f := range files {
+		names = append(names, f.Name)
+	}
+
+	return names
+}
+
+func TestGenerate(t *testing.T) {
+	t.Run("should generate a feature by default", func(t *testing.T) {
+		files, err := Generate(&Options{Name: "audit_log"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"definitions.go", "feature.go", "feature_test.go"}, fileNames(files))
+
+		for _, f := range files {
+			parsed, err := parser.ParseFile(token.NewFileSet(), f.Name, f.Content, parser.PackageClauseOnly)
+			require.NoError(t, err)
+			assert.Equal(t, "auditlog", parsed.Name.Name)
+		}
+
+		assert.Contains(t, string(files[0].Content), "AuditLog Definitions `toml:\"audit_log\"`")
+		assert.Contains(t, string(files[1].Content), `const FeatureName = "audit_log"`)
+	})
+
+	t.Run("should generate a runtime", func(t *testing.T) {
+		files, err := Generate(&Options{
+			Kind:    KindRuntime,
+			Name:    "queue",
+			Package: "consumer",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"definitions.go", "runtime.go", "runtime_test.go"}, fileNames(files))
+
+		for _, f := range files {
+			parsed, err := parser.ParseFile(token.NewFileSet(), f.Name, f.Content, parser.AllErrors)
+			require.NoError(t, err)
+			assert.Equal(t, "consumer", parsed.Name.Name)
+		}
+	})
+
+	t.Run("should fail with invalid names", func(t *testing.T) {
+		for _, name := range []string{"", "AuditLog", "audit-log", "1audit"} {
+			_, err := Generate(&Options{Name: name})
+			assert.Error(t, err, name)
+		}
+	})
+
+	t.Run("should fail with unsupported kinds", func(t *testing.T) {
+		_, err := Generate(&Options{Kind: "integration", Name: "audit"})
+		assert.Error(t, err)
+	})
+}
+
+func TestWrite(t *testing.T) {
+	t.Run("should write the files into the directory", func(t *testing.T) {
+		dir := filepath.Join(t.TempDir(), "audit")
+		require.NoError(t, Write(&Options{Name: "audit", Directory: dir}))
+
+		for _, name := range []string{"definitions.go", "feature.go", "feature_test.go"} {
+			assert.FileExists(t, filepath.Join(dir, name))
+		}
+	})
+
+	t.Run("should not overwrite existing files", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "feature.go"), []byte("package audit\n"), 0o600))
+
+		err := Write(&Options{Name: "audit", Directory: dir})
+		assert.Error(t, err)
+
+		b, err := os.ReadFile(filepath.Join(dir, "feature.go"))
+		require.NoError(t, err)
+		assert.Equal(t, "package audit\n", string(b))
+		assert.NoFileExists(t, filepath.Join(dir, "definitions.go"))
+	})
+}
+
+func TestTypeName(t *testing.T) {
+	t.Run("should convert snake_case names", func(t *testing.T) {
+		assert.Equal(t, "AuditLog", typeName("audit_log"))
+		assert.Equal(t, "Cache", typeName("cache"))
+		assert.Equal(t, "V2Queue", typeName("v2__queue"))
+	})
+}
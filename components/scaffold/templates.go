@@ -0,0 +1,353 @@
+package scaffold
+
+// fileTemplate is the template of a generated file.
+type fileTemplate struct {
+	name    string
+	content string
+}
+
+var kindTemplates = map[Kind][]fileTemplate{
+	KindFeature: {
+		{name: "definitions.go", content: featureDefinitionsTemplate},
+		{name: "feature.go", content: featureTemplate},
+		{name: "feature_test.go", content: featureTestTemplate},
+	},
+	KindRuntime: {
+		{name: "definitions.go", content: runtimeDefinitionsTemplate},
+		{name: "runtime.go", content: runtimeTemplate},
+		{name: "runtime_test.go", content: runtimeTestTemplate},
+	},
+}
+
+const featureDefinitionsTemplate = `package {{.Package}}
+
+import (
+	"github.com/mikros-dev/mikros/components/definition"
+)
+
+// Definitions holds the {{.Name}} feature settings loaded from the
+// 'service.toml' file:
+//
+//	[features.{{.Name}}]
+//	enabled = true
+type Definitions struct {
+	Enable bool ` + "`toml:\"enabled\"`" + `
+}
+
+func loadDefinitions(path string) (*Definitions, error) {
+	var file struct {
+		Features struct {
+			{{.TypeName}} Definitions ` + "`toml:\"{{.Name}}\"`" + `
+		} ` + "`toml:\"features\"`" + `
+	}
+
+	if err := definition.ParseExternalDefinitions(path, &file); err != nil {
+		return nil, err
+	}
+
+	return &file.Features.{{.TypeName}}, nil
+}
+
+// Enabled returns if the feature was enabled.
+func (d *Definitions) Enabled() bool {
+	return d.Enable
+}
+
+// Validate validates the feature settings.
+func (d *Definitions) Validate() error {
+	return nil
+}
+`
+
+const featureTemplate = `package {{.Package}}
+
+import (
+	"context"
+	"fmt"
+
+	logger_api "github.com/mikros-dev/mikros/apis/features/logger"
+	"github.com/mikros-dev/mikros/components/definition"
+	"github.com/mikros-dev/mikros/components/plugin"
+)
+
+// FeatureName is the name the feature is registered with.
+const FeatureName = "{{.Name}}"
+
+// Client is the {{.Name}} feature client.
+type Client struct {
+	plugin.Entry
+	defs *Definitions
+}
+
+// New creates the {{.Name}} feature.
+func New() *Client {
+	return &Client{}
+}
+
+// Features returns a FeatureSet with the feature registered, to be added to
+// services with WithExternalFeatures.
+func Features() *plugin.FeatureSet {
+	features := plugin.NewFeatureSet()
+	features.Register(FeatureName, New())
+
+	return features
+}
+
+// Definitions loads the feature settings from the 'service.toml' file.
+func (c *Client) Definitions(path string) (definition.ExternalFeatureEntry, error) {
+	return loadDefinitions(path)
+}
+
+// CanBeInitialized checks if the feature can be initialized.
+func (c *Client) CanBeInitialized(options *plugin.CanBeInitializedOptions) bool {
+	defs, err := options.Definitions.ExternalFeatureDefinitions(c.Name())
+	if err != nil {
+		return false
+	}
+
+	return defs.Enabled()
+}
+
+// Initialize initializes the feature.
+func (c *Client) Initialize(_ context.Context, options *plugin.InitializeOptions) error {
+	entry, err := options.Definitions.ExternalFeatureDefinitions(c.Name())
+	if err != nil {
+		return err
+	}
+
+	defs, ok := entry.(*Definitions)
+	if !ok {
+		return fmt.Errorf("invalid {{.Name}} definitions type %T", entry)
+	}
+
+	c.defs = defs
+	return nil
+}
+
+// Fields returns feature fields to be logged.
+func (c *Client) Fields() []logger_api.Attribute {
+	return []logger_api.Attribute{}
+}
+
+// Start starts the feature background tasks, if any.
+func (c *Client) Start(_ context.Context, _ interface{}) error {
+	return nil
+}
+
+// Cleanup releases the feature resources.
+func (c *Client) Cleanup(_ context.Context) error {
+	return nil
+}
+
+// ServiceAPI returns the API that services use through struct tags.
+func (c *Client) ServiceAPI() interface{} {
+	return c
+}
+`
+
+const featureTestTemplate = `package {{.Package}}
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mikros-dev/mikros/components/definition"
+	"github.com/mikros-dev/mikros/components/plugin"
+)
+
+func TestDefinitions(t *testing.T) {
+	t.Run("should load the feature settings", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "service.toml")
+		require.NoError(t, os.WriteFile(path, []byte("[features.{{.Name}}]\nenabled = true\n"), 0o600))
+
+		defs, err := New().Definitions(path)
+		require.NoError(t, err)
+		assert.True(t, defs.Enabled())
+		assert.NoError(t, defs.Validate())
+	})
+}
+
+func TestClient(t *testing.T) {
+	t.Run("should be initialized only when enabled", func(t *testing.T) {
+		c := New()
+		c.UpdateInfo(plugin.UpdateInfoEntry{Name: FeatureName})
+
+		defs := &definition.Definitions{}
+		options := &plugin.CanBeInitializedOptions{Definitions: defs}
+		assert.False(t, c.CanBeInitialized(options))
+
+		defs.AddExternalFeatureDefinitions(FeatureName, &Definitions{Enable: true})
+		assert.True(t, c.CanBeInitialized(options))
+	})
+}
+`
+
+const runtimeDefinitionsTemplate = `package {{.Package}}
+
+import (
+	"github.com/mikros-dev/mikros/components/definition"
+)
+
+// Definitions holds the {{.Name}} runtime settings loaded from the
+// 'service.toml' file:
+//
+//	[runtime.{{.Name}}]
+type Definitions struct{}
+
+func loadDefinitions(path string) (*Definitions, error) {
+	var file struct {
+		Runtime struct {
+			{{.TypeName}} Definitions ` + "`toml:\"{{.Name}}\"`" + `
+		} ` + "`toml:\"runtime\"`" + `
+	}
+
+	if err := definition.ParseExternalDefinitions(path, &file); err != nil {
+		return nil, err
+	}
+
+	return &file.Runtime.{{.TypeName}}, nil
+}
+
+// Name returns the runtime name.
+func (d *Definitions) Name() string {
+	return RuntimeName
+}
+
+// Validate validates the runtime settings.
+func (d *Definitions) Validate() error {
+	return nil
+}
+`
+
+const runtimeTemplate = `package {{.Package}}
+
+import (
+	"context"
+	"fmt"
+
+	logger_api "github.com/mikros-dev/mikros/apis/features/logger"
+	"github.com/mikros-dev/mikros/components/definition"
+	"github.com/mikros-dev/mikros/components/plugin"
+)
+
+// RuntimeName is the runtime name used in the service 'types'.
+const RuntimeName = "{{.Name}}"
+
+// Server is the {{.Name}} runtime server.
+type Server struct {
+	defs   *Definitions
+	logger logger_api.API
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// New creates the {{.Name}} runtime.
+func New() *Server {
+	return &Server{}
+}
+
+// Runtimes returns a RuntimeSet with the runtime registered, to be added to
+// services with WithExternalRuntimes.
+func Runtimes() *plugin.RuntimeSet {
+	runtimes := plugin.NewRuntimeSet()
+	runtimes.Register(New())
+
+	return runtimes
+}
+
+// Name gives the implementation runtime name.
+func (s *Server) Name() string {
+	return RuntimeName
+}
+
+// Info returns runtime fields to be logged.
+func (s *Server) Info() []logger_api.Attribute {
+	return nil
+}
+
+// Definitions loads the runtime settings from the 'service.toml' file.
+func (s *Server) Definitions(path string) (definition.ExternalRuntimeEntry, error) {
+	return loadDefinitions(path)
+}
+
+// Initialize initializes the runtime internals.
+func (s *Server) Initialize(ctx context.Context, opt *plugin.RuntimeOptions) error {
+	entry, err := opt.Definitions.ExternalRuntimeDefinitions(RuntimeName)
+	if err != nil {
+		return err
+	}
+
+	defs, ok := entry.(*Definitions)
+	if !ok {
+		return fmt.Errorf("invalid {{.Name}} definitions type %T", entry)
+	}
+
+	s.defs = defs
+	s.logger = opt.Logger
+	s.ctx, s.cancel = context.WithCancel(ctx)
+
+	return nil
+}
+
+// Run puts the runtime in execution until it is stopped.
+func (s *Server) Run(_ context.Context, _ interface{}) error {
+	<-s.ctx.Done()
+	return nil
+}
+
+// Stop stops the runtime.
+func (s *Server) Stop(_ context.Context) error {
+	s.cancel()
+	return nil
+}
+`
+
+const runtimeTestTemplate = `package {{.Package}}
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mikros-dev/mikros/components/definition"
+	"github.com/mikros-dev/mikros/components/plugin"
+)
+
+func TestDefinitions(t *testing.T) {
+	t.Run("should load the runtime settings", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "service.toml")
+		require.NoError(t, os.WriteFile(path, []byte("[runtime.{{.Name}}]\n"), 0o600))
+
+		defs, err := New().Definitions(path)
+		require.NoError(t, err)
+		assert.Equal(t, RuntimeName, defs.Name())
+		assert.NoError(t, defs.Validate())
+	})
+}
+
+func TestServer(t *testing.T) {
+	t.Run("should run until stopped", func(t *testing.T) {
+		defs := &definition.Definitions{}
+		defs.AddExternalRuntimeDefinitions(RuntimeName, &Definitions{})
+
+		s := New()
+		require.NoError(t, s.Initialize(context.Background(), &plugin.RuntimeOptions{Definitions: defs}))
+
+		done := make(chan error)
+		go func() {
+			done <- s.Run(context.Background(), nil)
+		}()
+
+		require.NoError(t, s.Stop(context.Background()))
+		assert.NoError(t, <-done)
+	})
+}
+`
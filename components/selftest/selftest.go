@@ -0,0 +1,177 @@
+// Package selftest runs smoke checks against a bootstrapped service and
+// reports their results, in TAP or JSON, for post-deploy verification jobs.
+//
+// Services declare their own checks by implementing Provider in their main
+// struct. Features may also implement it to check their dependencies.
+package selftest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+const (
+	defaultTimeout = 10 * time.Second
+)
+
+// Format is the output format of a Report.
+type Format string
+
+const (
+	FormatTAP  Format = "tap"
+	FormatJSON Format = "json"
+)
+
+// ParseFormat converts a string into a Format.
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(strings.ToLower(s)); f {
+	case FormatTAP, FormatJSON:
+		return f, nil
+	default:
+		return "", fmt.Errorf("selftest: unsupported report format '%s'", s)
+	}
+}
+
+// Check is a single smoke check.
+type Check struct {
+	// Name identifies the check in the report.
+	Name string
+
+	// Run executes the check, which fails when it returns an error.
+	Run func(ctx context.Context) error
+}
+
+// Provider is an optional interface that service structs and features
+// implement to declare their own checks.
+type Provider interface {
+	SelfTestChecks() []Check
+}
+
+// Options configures how checks are executed.
+type Options struct {
+	// Timeout limits how long every check has to finish. Defaults to 10
+	// seconds.
+	Timeout time.Duration
+}
+
+// Result is the outcome of a check.
+type Result struct {
+	Name     string        `json:"name"`
+	Passed   bool          `json:"passed"`
+	Error    string        `json:"error,omitempty"`
+	Duration time.Duration `json:"duration_ns"`
+}
+
+// Report holds the results of all executed checks.
+type Report struct {
+	Passed  bool     `json:"passed"`
+	Results []Result `json:"results"`
+}
+
+// Run executes checks, in the order they are given, and reports their results.
+// A check that panics or doesn't finish in time fails.
+func Run(ctx context.Context, checks []Check, options Options) Report {
+	if options.Timeout <= 0 {
+		options.Timeout = defaultTimeout
+	}
+
+	report := Report{
+		Passed:  true,
+		Results: make([]Result, 0, len(checks)),
+	}
+
+	for _, c := range checks {
+		start := time.Now()
+		err := runCheck(ctx, c, options.Timeout)
+
+		result := Result{
+			Name:     c.Name,
+			Passed:   err == nil,
+			Duration: time.Since(start),
+		}
+		if err != nil {
+			result.Error = err.Error()
+			report.Passed = false
+		}
+
+		report.Results = append(report.Results, result)
+	}
+
+	return report
+}
+
+func runCheck(ctx context.Context, c Check, timeout time.Duration) error {
+	if c.Run == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	result := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				result <- fmt.Errorf("check panicked: %v", r)
+			}
+		}()
+
+		result <- c.Run(ctx)
+	}()
+
+	select {
+	case err := <-result:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("check timed out after %s", timeout)
+	}
+}
+
+// Write outputs the report to w using the given format.
+func (r Report) Write(w io.Writer, format Format) error {
+	switch format {
+	case FormatJSON:
+		return r.WriteJSON(w)
+	case FormatTAP:
+		return r.WriteTAP(w)
+	default:
+		return fmt.Errorf("selftest: unsupported report format '%s'", format)
+	}
+}
+
+// WriteJSON outputs the report as a JSON document.
+func (r Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// WriteTAP outputs the report using the Test Anything Protocol, version 13.
+func (r Report) WriteTAP(w io.Writer) error {
+	var b strings.Builder
+
+	b.WriteString("TAP version 13\n")
+	fmt.Fprintf(&b, "1..%d\n", len(r.Results))
+
+	for i, res := range r.Results {
+		status := "ok"
+		if !res.Passed {
+			status = "not ok"
+		}
+
+		fmt.Fprintf(&b, "%s %d - %s\n", status, i+1, res.Name)
+		if !res.Passed {
+			b.WriteString("  ---\n")
+			fmt.Fprintf(&b, "  message: %q\n", res.Error)
+			fmt.Fprintf(&b, "  duration_ms: %d\n", res.Duration.Milliseconds())
+			b.WriteString("  ...\n")
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
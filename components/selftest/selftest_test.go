@@ -0,0 +1,110 @@
+package selftest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun(t *testing.T) {
+	t.Run("should pass when every check passes", func(t *testing.T) {
+		report := Run(context.Background(), []Check{
+			{Name: "first", Run: func(context.Context) error { return nil }},
+			{Name: "second", Run: func(context.Context) error { return nil }},
+		}, Options{})
+
+		assert.True(t, report.Passed)
+		require.Len(t, report.Results, 2)
+		assert.Equal(t, "first", report.Results[0].Name)
+		assert.Equal(t, "second", report.Results[1].Name)
+	})
+
+	t.Run("should fail when a check fails", func(t *testing.T) {
+		report := Run(context.Background(), []Check{
+			{Name: "ok", Run: func(context.Context) error { return nil }},
+			{Name: "broken", Run: func(context.Context) error { return errors.New("connection refused") }},
+		}, Options{})
+
+		assert.False(t, report.Passed)
+		assert.True(t, report.Results[0].Passed)
+		assert.False(t, report.Results[1].Passed)
+		assert.Equal(t, "connection refused", report.Results[1].Error)
+	})
+
+	t.Run("should fail checks that panic", func(t *testing.T) {
+		report := Run(context.Background(), []Check{
+			{Name: "panic", Run: func(context.Context) error { panic("boom") }},
+		}, Options{})
+
+		assert.False(t, report.Passed)
+		assert.Contains(t, report.Results[0].Error, "boom")
+	})
+
+	t.Run("should fail checks that don't finish in time", func(t *testing.T) {
+		report := Run(context.Background(), []Check{
+			{Name: "slow", Run: func(context.Context) error {
+				time.Sleep(time.Second)
+				return nil
+			}},
+		}, Options{Timeout: 10 * time.Millisecond})
+
+		assert.False(t, report.Passed)
+		assert.Contains(t, report.Results[0].Error, "timed out")
+	})
+}
+
+func TestReportWrite(t *testing.T) {
+	report := Report{
+		Passed: false,
+		Results: []Result{
+			{Name: "config", Passed: true},
+			{Name: "feature/database", Passed: false, Error: "connection refused"},
+		},
+	}
+
+	t.Run("should write TAP reports", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, report.Write(&buf, FormatTAP))
+
+		assert.Equal(t, "TAP version 13\n"+
+			"1..2\n"+
+			"ok 1 - config\n"+
+			"not ok 2 - feature/database\n"+
+			"  ---\n"+
+			"  message: \"connection refused\"\n"+
+			"  duration_ms: 0\n"+
+			"  ...\n", buf.String())
+	})
+
+	t.Run("should write JSON reports", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, report.Write(&buf, FormatJSON))
+
+		var decoded Report
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+		assert.Equal(t, report, decoded)
+	})
+
+	t.Run("should fail with unsupported formats", func(t *testing.T) {
+		assert.Error(t, report.Write(&bytes.Buffer{}, "xml"))
+	})
+}
+
+func TestParseFormat(t *testing.T) {
+	t.Run("should parse supported formats", func(t *testing.T) {
+		f, err := ParseFormat("JSON")
+		require.NoError(t, err)
+		assert.Equal(t, FormatJSON, f)
+	})
+
+	t.Run("should fail with unsupported formats", func(t *testing.T) {
+		_, err := ParseFormat("junit")
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,77 @@
+package session
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	mhttp "github.com/mikros-dev/mikros/components/http"
+)
+
+const (
+	flashKey = "_flash"
+)
+
+// Flash is a one-time message kept in the session until the next request
+// reads it, usually to show the result of a form submission after a
+// redirect.
+type Flash struct {
+	Kind    string `json:"k"`
+	Message string `json:"m"`
+}
+
+// AddFlash adds a flash message into the session.
+func (s *Session) AddFlash(flash Flash) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	flashes := s.flashes()
+	flashes = append(flashes, flash)
+
+	b, _ := json.Marshal(flashes)
+	s.values[flashKey] = string(b)
+	s.modified = true
+}
+
+// Flashes returns the flash messages of the session and removes them.
+func (s *Session) Flashes() []Flash {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	flashes := s.flashes()
+	if _, ok := s.values[flashKey]; ok {
+		delete(s.values, flashKey)
+		s.modified = true
+	}
+
+	return flashes
+}
+
+func (s *Session) flashes() []Flash {
+	var flashes []Flash
+	if v, ok := s.values[flashKey]; ok {
+		_ = json.Unmarshal([]byte(v), &flashes)
+	}
+
+	return flashes
+}
+
+// RedirectWithFlash adds a flash message into the request session and
+// redirects the client to location using mhttp.Redirect. The request must
+// have passed through Manager.Middleware.
+func RedirectWithFlash(
+	w http.ResponseWriter,
+	r *http.Request,
+	location string,
+	flash Flash,
+	options ...mhttp.RedirectOptions,
+) error {
+	s, ok := FromContext(r.Context())
+	if !ok {
+		return errors.New("request has no session")
+	}
+
+	s.AddFlash(flash)
+	mhttp.Redirect(w, r, location, options...)
+	return nil
+}
@@ -0,0 +1,406 @@
+package session
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"maps"
+	"net/http"
+	"sync"
+	"time"
+
+	logger_api "github.com/mikros-dev/mikros/apis/features/logger"
+	"github.com/mikros-dev/mikros/components/ids"
+	"github.com/mikros-dev/mikros/components/logger"
+)
+
+const (
+	defaultCookieName = "mikros_session"
+	defaultTTL        = 24 * time.Hour
+	sessionIDBytes    = 32
+)
+
+// Options configures a Manager.
+type Options struct {
+	// Key encrypts and authenticates the session cookie with AES-GCM. It must
+	// have 16, 24 or 32 bytes.
+	Key []byte
+
+	// CookieName is the name of the session cookie. Defaults to
+	// "mikros_session".
+	CookieName string
+
+	// TTL is for how long a session is kept since it was last saved.
+	// Defaults to 24 hours.
+	TTL time.Duration
+
+	// Cookie attributes. HttpOnly is always set and SameSite defaults to Lax.
+	Path     string
+	Domain   string
+	Secure   bool
+	SameSite http.SameSite
+
+	// Store, when set, keeps the session values on the server side and the
+	// cookie only carries the session ID. Otherwise, values are carried by
+	// the cookie itself, which limits their size to about 4KB.
+	Store Store
+
+	// Logger is used for logging errors while loading or saving sessions.
+	// If nil, errors will be logged using the standard log package.
+	Logger logger_api.API
+}
+
+// Manager loads and saves sessions of browser-facing services, keeping them
+// in encrypted and authenticated cookies.
+type Manager struct {
+	options Options
+	aead    cipher.AEAD
+	now     func() time.Time
+}
+
+// New creates a new Manager.
+func New(options Options) (*Manager, error) {
+	block, err := aes.NewCipher(options.Key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid session key: %w", err)
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	if options.CookieName == "" {
+		options.CookieName = defaultCookieName
+	}
+	if options.TTL <= 0 {
+		options.TTL = defaultTTL
+	}
+	if options.Path == "" {
+		options.Path = "/"
+	}
+	if options.SameSite == 0 {
+		options.SameSite = http.SameSiteLaxMode
+	}
+
+	return &Manager{
+		options: options,
+		aead:    aead,
+		now:     time.Now,
+	}, nil
+}
+
+// Session holds the values of a client session. It is safe for concurrent
+// use.
+type Session struct {
+	mu        sync.Mutex
+	id        string
+	values    map[string]string
+	modified  bool
+	destroyed bool
+	renewed   bool
+}
+
+// Get returns a session value.
+func (s *Session) Get(key string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	v, ok := s.values[key]
+	return v, ok
+}
+
+// Set changes a session value.
+func (s *Session) Set(key, value string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.values[key] = value
+	s.modified = true
+}
+
+// Delete removes a session value.
+func (s *Session) Delete(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.values, key)
+	s.modified = true
+}
+
+// Renew gives the session a new ID, keeping its values, when it is saved.
+// It must be called after the user logs in or has its privileges changed,
+// so that an ID obtained before, e.g. planted by an attacker, can't be used
+// to access the session afterward.
+func (s *Session) Renew() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.renewed = true
+	s.modified = true
+}
+
+// Destroy removes all session values and expires the session cookie.
+func (s *Session) Destroy() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.values = make(map[string]string)
+	s.destroyed = true
+	s.modified = true
+}
+
+type contextKey struct{}
+
+// FromContext returns the session loaded by Manager.Middleware.
+func FromContext(ctx context.Context) (*Session, bool) {
+	s, ok := ctx.Value(contextKey{}).(*Session)
+	return s, ok
+}
+
+type cookiePayload struct {
+	ID        string            `json:"i,omitempty"`
+	Values    map[string]string `json:"v,omitempty"`
+	ExpiresAt int64             `json:"e"`
+}
+
+// Load returns the session of a request. A new, empty session is returned
+// when the request has no valid session cookie.
+func (m *Manager) Load(r *http.Request) (*Session, error) {
+	s := &Session{
+		values: make(map[string]string),
+	}
+
+	cookie, err := r.Cookie(m.options.CookieName)
+	if err != nil {
+		return s, nil
+	}
+
+	payload, err := m.decode(cookie.Value)
+	if err != nil || !m.now().Before(time.Unix(payload.ExpiresAt, 0)) {
+		// Invalid or expired cookies start a new session.
+		return s, nil
+	}
+
+	if m.options.Store == nil {
+		if payload.Values != nil {
+			s.values = payload.Values
+		}
+
+		return s, nil
+	}
+
+	values, err := m.options.Store.Load(r.Context(), payload.ID)
+	if errors.Is(err, ErrNotFound) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s.id = payload.ID
+	s.values = values
+	return s, nil
+}
+
+// Save writes the session cookie into the response and, when a Store is
+// used, saves the session values. It must be called before the response
+// status is written.
+func (m *Manager) Save(ctx context.Context, w http.ResponseWriter, s *Session) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.destroyed {
+		if m.options.Store != nil && s.id != "" {
+			if err := m.options.Store.Delete(ctx, s.id); err != nil {
+				return err
+			}
+		}
+
+		http.SetCookie(w, m.cookie("", -1))
+		return nil
+	}
+
+	payload := cookiePayload{
+		ExpiresAt: m.now().Add(m.options.TTL).Unix(),
+	}
+
+	if m.options.Store != nil {
+		if s.renewed && s.id != "" {
+			if err := m.options.Store.Delete(ctx, s.id); err != nil {
+				return err
+			}
+			s.id = ""
+		}
+		if s.id == "" {
+			id, err := newSessionID()
+			if err != nil {
+				return err
+			}
+			s.id = id
+		}
+
+		if err := m.options.Store.Save(ctx, s.id, maps.Clone(s.values), m.options.TTL); err != nil {
+			return err
+		}
+
+		payload.ID = s.id
+	} else {
+		payload.Values = s.values
+	}
+
+	value, err := m.encode(payload)
+	if err != nil {
+		return err
+	}
+
+	s.renewed = false
+	http.SetCookie(w, m.cookie(value, int(m.options.TTL.Seconds())))
+	return nil
+}
+
+func (m *Manager) cookie(value string, maxAge int) *http.Cookie {
+	return &http.Cookie{
+		Name:     m.options.CookieName,
+		Value:    value,
+		Path:     m.options.Path,
+		Domain:   m.options.Domain,
+		MaxAge:   maxAge,
+		Secure:   m.options.Secure,
+		HttpOnly: true,
+		SameSite: m.options.SameSite,
+	}
+}
+
+func (m *Manager) encode(payload cookiePayload) (string, error) {
+	plaintext, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, m.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+
+	// The cookie name is authenticated, so a value can't be moved into
+	// another cookie.
+	sealed := m.aead.Seal(nonce, nonce, plaintext, []byte(m.options.CookieName))
+	return base64.RawURLEncoding.EncodeToString(sealed), nil
+}
+
+func (m *Manager) decode(value string) (*cookiePayload, error) {
+	sealed, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+
+	size := m.aead.NonceSize()
+	if len(sealed) < size {
+		return nil, errors.New("invalid session cookie")
+	}
+
+	plaintext, err := m.aead.Open(nil, sealed[:size], sealed[size:], []byte(m.options.CookieName))
+	if err != nil {
+		return nil, err
+	}
+
+	var payload cookiePayload
+	if err := json.Unmarshal(plaintext, &payload); err != nil {
+		return nil, err
+	}
+
+	return &payload, nil
+}
+
+// newSessionID creates a random session ID, unless the framework IDs are
+// being replaced by a generator set with ids.Set.
+func newSessionID() (string, error) {
+	if g, ok := ids.Override(); ok {
+		return g.Generate(), nil
+	}
+
+	b := make([]byte, sessionIDBytes)
+	if _, err := io.ReadFull(rand.Reader, b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// Middleware returns a middleware that loads the request session, makes it
+// available to the handler through FromContext, and saves it, if it was
+// modified, before the response is written.
+func (m *Manager) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s, err := m.Load(r)
+		if err != nil {
+			m.logError(r.Context(), "could not load session", err)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+
+		sw := &sessionWriter{
+			ResponseWriter: w,
+			save: func() {
+				s.mu.Lock()
+				modified := s.modified
+				s.mu.Unlock()
+
+				if modified {
+					if err := m.Save(r.Context(), w, s); err != nil {
+						m.logError(r.Context(), "could not save session", err)
+					}
+				}
+			},
+		}
+
+		next.ServeHTTP(sw, r.WithContext(context.WithValue(r.Context(), contextKey{}, s)))
+		sw.saveOnce()
+	})
+}
+
+func (m *Manager) logError(ctx context.Context, msg string, err error) {
+	if m.options.Logger != nil {
+		m.options.Logger.Error(ctx, msg, logger.Error(err))
+		return
+	}
+
+	log.Printf("%s: %v\n", msg, err)
+}
+
+// sessionWriter saves the session right before the response status is
+// written, since cookies are headers.
+type sessionWriter struct {
+	http.ResponseWriter
+	save  func()
+	saved bool
+}
+
+func (w *sessionWriter) saveOnce() {
+	if !w.saved {
+		w.saved = true
+		w.save()
+	}
+}
+
+func (w *sessionWriter) WriteHeader(code int) {
+	w.saveOnce()
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *sessionWriter) Write(b []byte) (int, error) {
+	w.saveOnce()
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *sessionWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
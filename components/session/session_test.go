@@ -0,0 +1,207 @@
+package session
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mikros-dev/mikros/components/ids"
+)
+
+var testKey = []byte("0123456789abcdef0123456789abcdef")
+
+func newTestHandler(t *testing.T, options Options) (http.Handler, *Manager) {
+	options.Key = testKey
+	m, err := New(options)
+	require.NoError(t, err)
+
+	h := m.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s, ok := FromContext(r.Context())
+		require.True(t, ok)
+
+		switch r.URL.Path {
+		case "/visit":
+			s.Set("visited", "true")
+		case "/login":
+			s.Set("user", "42")
+			s.Renew()
+		case "/logout":
+			s.Destroy()
+		}
+
+		user, _ := s.Get("user")
+		_, _ = w.Write([]byte(user))
+	}))
+
+	return h, m
+}
+
+func serve(h http.Handler, path string, cookies ...*http.Cookie) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	for _, c := range cookies {
+		req.AddCookie(c)
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func sessionCookie(t *testing.T, rec *httptest.ResponseRecorder) *http.Cookie {
+	cookies := rec.Result().Cookies()
+	require.Len(t, cookies, 1)
+	return cookies[0]
+}
+
+func TestManager(t *testing.T) {
+	t.Run("should keep values in encrypted cookies", func(t *testing.T) {
+		h, _ := newTestHandler(t, Options{})
+
+		rec := serve(h, "/login")
+		cookie := sessionCookie(t, rec)
+		assert.Equal(t, defaultCookieName, cookie.Name)
+		assert.True(t, cookie.HttpOnly)
+
+		rec = serve(h, "/", cookie)
+		assert.Equal(t, "42", rec.Body.String())
+		assert.Empty(t, rec.Result().Cookies())
+	})
+
+	t.Run("should ignore tampered cookies", func(t *testing.T) {
+		h, _ := newTestHandler(t, Options{})
+
+		cookie := sessionCookie(t, serve(h, "/login"))
+		cookie.Value = cookie.Value[:len(cookie.Value)-2] + "xx"
+
+		rec := serve(h, "/", cookie)
+		assert.Empty(t, rec.Body.String())
+	})
+
+	t.Run("should ignore expired cookies", func(t *testing.T) {
+		h, m := newTestHandler(t, Options{TTL: time.Minute})
+
+		cookie := sessionCookie(t, serve(h, "/login"))
+		m.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+
+		rec := serve(h, "/", cookie)
+		assert.Empty(t, rec.Body.String())
+	})
+
+	t.Run("should keep values in the store", func(t *testing.T) {
+		store := NewMemoryStore()
+		h, _ := newTestHandler(t, Options{Store: store})
+
+		cookie := sessionCookie(t, serve(h, "/login"))
+		assert.Len(t, store.sessions, 1)
+
+		rec := serve(h, "/", cookie)
+		assert.Equal(t, "42", rec.Body.String())
+
+		rec = serve(h, "/logout", cookie)
+		assert.Equal(t, -1, sessionCookie(t, rec).MaxAge)
+		assert.Empty(t, store.sessions)
+
+		rec = serve(h, "/", cookie)
+		assert.Empty(t, rec.Body.String())
+	})
+
+	t.Run("should renew the session ID on login", func(t *testing.T) {
+		store := NewMemoryStore()
+		h, m := newTestHandler(t, Options{Store: store})
+
+		before := sessionCookie(t, serve(h, "/visit"))
+		after := sessionCookie(t, serve(h, "/login", before))
+
+		oldPayload, err := m.decode(before.Value)
+		require.NoError(t, err)
+		newPayload, err := m.decode(after.Value)
+		require.NoError(t, err)
+		assert.NotEqual(t, oldPayload.ID, newPayload.ID)
+		assert.Len(t, store.sessions, 1)
+
+		values, err := store.Load(context.Background(), newPayload.ID)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"visited": "true", "user": "42"}, values)
+
+		_, err = store.Load(context.Background(), oldPayload.ID)
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("should create session IDs with the framework ID generator", func(t *testing.T) {
+		reset := ids.Set(ids.NewSequential("session"))
+		defer reset()
+
+		store := NewMemoryStore()
+		h, _ := newTestHandler(t, Options{Store: store})
+
+		serve(h, "/login")
+		assert.Contains(t, store.sessions, "session-000001")
+	})
+
+	t.Run("should reject invalid keys", func(t *testing.T) {
+		_, err := New(Options{Key: []byte("short")})
+		assert.Error(t, err)
+	})
+}
+
+func TestMemoryStore(t *testing.T) {
+	var (
+		now   = time.Now()
+		store = NewMemoryStore()
+		ctx   = context.Background()
+	)
+	store.now = func() time.Time { return now }
+
+	require.NoError(t, store.Save(ctx, "id", map[string]string{"a": "b"}, time.Minute))
+
+	values, err := store.Load(ctx, "id")
+	require.NoError(t, err)
+	assert.Equal(t, "b", values["a"])
+
+	now = now.Add(time.Hour)
+	_, err = store.Load(ctx, "id")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	t.Run("should evict expired sessions when saving", func(t *testing.T) {
+		require.NoError(t, store.Save(ctx, "old", map[string]string{"a": "b"}, time.Minute))
+
+		now = now.Add(time.Hour)
+		require.NoError(t, store.Save(ctx, "new", map[string]string{"a": "b"}, time.Minute))
+		assert.NotContains(t, store.sessions, "old")
+		assert.Contains(t, store.sessions, "new")
+	})
+}
+
+func TestFlash(t *testing.T) {
+	m, err := New(Options{Key: testKey})
+	require.NoError(t, err)
+
+	h := m.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/save" {
+			assert.NoError(t, RedirectWithFlash(w, r, "/list", Flash{Kind: "success", Message: "saved"}))
+			return
+		}
+
+		s, _ := FromContext(r.Context())
+		for _, f := range s.Flashes() {
+			_, _ = w.Write([]byte(f.Kind + ":" + f.Message))
+		}
+	}))
+
+	rec := serve(h, "/save")
+	assert.Equal(t, http.StatusSeeOther, rec.Code)
+	assert.Equal(t, "http://example.com/list", rec.Header().Get("Location"))
+	cookie := sessionCookie(t, rec)
+
+	rec = serve(h, "/list", cookie)
+	assert.Equal(t, "success:saved", rec.Body.String())
+
+	// Flashes are only shown once.
+	rec = serve(h, "/list", sessionCookie(t, rec))
+	assert.Empty(t, rec.Body.String())
+}
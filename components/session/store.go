@@ -0,0 +1,108 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"sync"
+	"time"
+)
+
+// ErrNotFound is returned by a Store when a session does not exist or has
+// expired.
+var ErrNotFound = errors.New("session not found")
+
+// Store keeps session values on the server side, so that the cookie only
+// carries the session ID.
+type Store interface {
+	// Load returns the values of a session or ErrNotFound.
+	Load(ctx context.Context, id string) (map[string]string, error)
+
+	// Save stores the values of a session, replacing previous ones. The
+	// session must expire after ttl.
+	Save(ctx context.Context, id string, values map[string]string, ttl time.Duration) error
+
+	// Delete removes a session.
+	Delete(ctx context.Context, id string) error
+}
+
+const (
+	// memoryStoreSweepInterval is how often, at most, MemoryStore removes
+	// its expired sessions.
+	memoryStoreSweepInterval = time.Minute
+)
+
+// MemoryStore is a Store that keeps sessions in memory. It is meant for
+// tests and single instance services. Expired sessions are removed when
+// loaded or, periodically, when other sessions are saved.
+type MemoryStore struct {
+	mu        sync.Mutex
+	now       func() time.Time
+	sessions  map[string]memorySession
+	lastSweep time.Time
+}
+
+type memorySession struct {
+	values    map[string]string
+	expiresAt time.Time
+}
+
+// NewMemoryStore creates a new MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		now:      time.Now,
+		sessions: make(map[string]memorySession),
+	}
+}
+
+// Load returns the values of a session.
+func (m *MemoryStore) Load(_ context.Context, id string) (map[string]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s, ok := m.sessions[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	if !m.now().Before(s.expiresAt) {
+		delete(m.sessions, id)
+		return nil, ErrNotFound
+	}
+
+	return maps.Clone(s.values), nil
+}
+
+// Save stores the values of a session.
+func (m *MemoryStore) Save(_ context.Context, id string, values map[string]string, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	if now.Sub(m.lastSweep) >= memoryStoreSweepInterval {
+		m.sweep(now)
+	}
+
+	m.sessions[id] = memorySession{
+		values:    maps.Clone(values),
+		expiresAt: now.Add(ttl),
+	}
+
+	return nil
+}
+
+// sweep removes the expired sessions.
+func (m *MemoryStore) sweep(now time.Time) {
+	maps.DeleteFunc(m.sessions, func(_ string, s memorySession) bool {
+		return !now.Before(s.expiresAt)
+	})
+	m.lastSweep = now
+}
+
+// Delete removes a session.
+func (m *MemoryStore) Delete(_ context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.sessions, id)
+	return nil
+}
@@ -0,0 +1,123 @@
+package testing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	defaultFixtureReadyTimeout  = 30 * time.Second
+	defaultFixtureReadyInterval = 500 * time.Millisecond
+)
+
+// Fixture is an external resource, such as a database or a message broker,
+// that tests need running. Fixtures declared in Options.Fixtures are started
+// by SetupTest, before the features are set up, and stopped by Teardown, in
+// the reverse order, which is where containers can be managed:
+//
+//	db := &testing.Fixture{
+//		Name: "postgres",
+//		Start: func(ctx context.Context) error {
+//			c, err := postgres.Run(ctx, "postgres:16")
+//			...
+//		},
+//		Ready: func(ctx context.Context) error {
+//			return pool.Ping(ctx)
+//		},
+//	}
+type Fixture struct {
+	// Name identifies the fixture in errors.
+	Name string
+
+	// Start starts the resource.
+	Start func(ctx context.Context) error
+
+	// Stop, if set, stops the resource.
+	Stop func(ctx context.Context) error
+
+	// Ready, if set, is called until it succeeds, to wait for the resource
+	// to accept connections after being started.
+	Ready func(ctx context.Context) error
+
+	// ReadyTimeout is how long to wait for the resource to become ready.
+	// Defaults to 30 seconds.
+	ReadyTimeout time.Duration
+
+	// ReadyInterval is the interval between Ready calls. Defaults to 500
+	// milliseconds.
+	ReadyInterval time.Duration
+}
+
+// StartFixtures starts fixtures in order, waiting for each one to become
+// ready before starting the next. It returns the function that stops all of
+// them, in the reverse order. If a fixture fails to start, the ones already
+// started are stopped.
+func StartFixtures(ctx context.Context, fixtures []*Fixture) (func(ctx context.Context) error, error) {
+	var started []*Fixture
+
+	stop := func(ctx context.Context) error {
+		var errs []error
+		for i := len(started) - 1; i >= 0; i-- {
+			f := started[i]
+			if f.Stop == nil {
+				continue
+			}
+
+			if err := f.Stop(ctx); err != nil {
+				errs = append(errs, fmt.Errorf("could not stop fixture '%s': %w", f.Name, err))
+			}
+		}
+
+		return errors.Join(errs...)
+	}
+
+	for _, f := range fixtures {
+		if f.Start != nil {
+			if err := f.Start(ctx); err != nil {
+				return nil, errors.Join(fmt.Errorf("could not start fixture '%s': %w", f.Name, err), stop(ctx))
+			}
+		}
+		started = append(started, f)
+
+		if err := f.waitReady(ctx); err != nil {
+			return nil, errors.Join(err, stop(ctx))
+		}
+	}
+
+	return stop, nil
+}
+
+func (f *Fixture) waitReady(ctx context.Context) error {
+	if f.Ready == nil {
+		return nil
+	}
+
+	var (
+		timeout  = f.ReadyTimeout
+		interval = f.ReadyInterval
+	)
+	if timeout <= 0 {
+		timeout = defaultFixtureReadyTimeout
+	}
+	if interval <= 0 {
+		interval = defaultFixtureReadyInterval
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	for {
+		err := f.Ready(ctx)
+		if err == nil {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("fixture '%s' is not ready after %s: %w", f.Name, timeout, err)
+		case <-time.After(interval):
+		}
+	}
+}
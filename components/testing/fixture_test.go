@@ -0,0 +1,86 @@
+package testing
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestFixture(name string, events *[]string) *Fixture {
+	return &Fixture{
+		Name: name,
+		Start: func(_ context.Context) error {
+			*events = append(*events, "start "+name)
+			return nil
+		},
+		Stop: func(_ context.Context) error {
+			*events = append(*events, "stop "+name)
+			return nil
+		},
+	}
+}
+
+func TestStartFixtures(t *testing.T) {
+	t.Run("should start fixtures in order and stop them in reverse", func(t *testing.T) {
+		var events []string
+
+		stop, err := StartFixtures(context.Background(), []*Fixture{
+			newTestFixture("db", &events),
+			newTestFixture("broker", &events),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"start db", "start broker"}, events)
+
+		require.NoError(t, stop(context.Background()))
+		assert.Equal(t, []string{"start db", "start broker", "stop broker", "stop db"}, events)
+	})
+
+	t.Run("should wait for fixtures to become ready", func(t *testing.T) {
+		var (
+			events   []string
+			attempts int
+			fixture  = newTestFixture("db", &events)
+		)
+		fixture.ReadyInterval = time.Millisecond
+		fixture.Ready = func(_ context.Context) error {
+			attempts++
+			if attempts < 3 {
+				return errors.New("not ready")
+			}
+			return nil
+		}
+
+		_, err := StartFixtures(context.Background(), []*Fixture{fixture})
+		require.NoError(t, err)
+		assert.Equal(t, 3, attempts)
+	})
+
+	t.Run("should stop started fixtures when one fails", func(t *testing.T) {
+		var (
+			events  []string
+			broken  = newTestFixture("broker", &events)
+			waiting = newTestFixture("cache", &events)
+		)
+		broken.Start = func(_ context.Context) error {
+			return errors.New("no docker")
+		}
+		waiting.ReadyTimeout = 5 * time.Millisecond
+		waiting.ReadyInterval = time.Millisecond
+		waiting.Ready = func(_ context.Context) error {
+			return errors.New("not ready")
+		}
+
+		_, err := StartFixtures(context.Background(), []*Fixture{newTestFixture("db", &events), broken})
+		assert.ErrorContains(t, err, "could not start fixture 'broker': no docker")
+		assert.Equal(t, []string{"start db", "stop db"}, events)
+
+		events = nil
+		_, err = StartFixtures(context.Background(), []*Fixture{waiting})
+		assert.ErrorContains(t, err, "fixture 'cache' is not ready")
+		assert.Equal(t, []string{"start cache", "stop cache"}, events)
+	})
+}
@@ -0,0 +1,179 @@
+package testing
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// Call kinds.
+const (
+	GRPCCall = "grpc"
+	HTTPCall = "http"
+)
+
+// Call is an outgoing call captured by a Recorder.
+type Call struct {
+	// Kind tells if it is a GRPCCall or an HTTPCall.
+	Kind string
+
+	// Service is the name of the called service for gRPC calls, or the
+	// request host for HTTP calls.
+	Service string
+
+	// Method is the gRPC full method name, e.g. "/users.Users/Get", or the
+	// HTTP method and path, e.g. "GET /users/1".
+	Method string
+
+	// Payload is the request message of gRPC calls, or the body bytes of
+	// HTTP calls.
+	Payload interface{}
+
+	// Metadata is the outgoing gRPC metadata, or the HTTP request headers.
+	Metadata map[string][]string
+
+	// Err is the error returned by the call, if any.
+	Err error
+}
+
+// Recorder captures the outgoing calls that a service makes to other
+// services while it is recording, so tests can assert on them instead of
+// setting mock expectations.
+type Recorder struct {
+	mu        sync.Mutex
+	calls     []Call
+	recording atomic.Bool
+}
+
+// NewRecorder creates a new Recorder. It starts stopped.
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+// Start makes the recorder capture calls.
+func (r *Recorder) Start() {
+	r.recording.Store(true)
+}
+
+// Stop stops capturing calls. Captured calls are kept.
+func (r *Recorder) Stop() {
+	r.recording.Store(false)
+}
+
+// Reset discards all captured calls.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.calls = nil
+}
+
+// Calls returns all captured calls, in the order they were made.
+func (r *Recorder) Calls() []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]Call(nil), r.calls...)
+}
+
+// CallsTo returns the captured calls of a single method.
+func (r *Recorder) CallsTo(method string) []Call {
+	var calls []Call
+	for _, c := range r.Calls() {
+		if c.Method == method {
+			calls = append(calls, c)
+		}
+	}
+
+	return calls
+}
+
+func (r *Recorder) record(c Call) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.calls = append(r.calls, c)
+}
+
+// UnaryClientInterceptor returns a gRPC client interceptor that captures
+// the calls made to service.
+func (r *Recorder) UnaryClientInterceptor(service string) grpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context,
+		method string,
+		req, reply interface{},
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		if r.recording.Load() {
+			md, _ := metadata.FromOutgoingContext(ctx)
+			r.record(Call{
+				Kind:     GRPCCall,
+				Service:  service,
+				Method:   method,
+				Payload:  req,
+				Metadata: md.Copy(),
+				Err:      err,
+			})
+		}
+
+		return err
+	}
+}
+
+// Transport returns an http.RoundTripper that captures the requests sent
+// through base. It uses http.DefaultTransport if base is nil.
+func (r *Recorder) Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	return &recorderTransport{
+		recorder: r,
+		base:     base,
+	}
+}
+
+type recorderTransport struct {
+	recorder *Recorder
+	base     http.RoundTripper
+}
+
+func (t *recorderTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !t.recorder.recording.Load() {
+		return t.base.RoundTrip(req)
+	}
+
+	var body []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		b, err := io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		body = b
+		req = req.Clone(req.Context())
+		req.Body = io.NopCloser(bytes.NewReader(b))
+	}
+
+	res, err := t.base.RoundTrip(req)
+	t.recorder.record(Call{
+		Kind:     HTTPCall,
+		Service:  req.URL.Host,
+		Method:   req.Method + " " + req.URL.Path,
+		Payload:  body,
+		Metadata: req.Header.Clone(),
+		Err:      err,
+	})
+
+	return res, err
+}
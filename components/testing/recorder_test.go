@@ -0,0 +1,86 @@
+package testing
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestRecorder(t *testing.T) {
+	t.Run("should capture gRPC calls while recording", func(t *testing.T) {
+		var (
+			r           = NewRecorder()
+			interceptor = r.UnaryClientInterceptor("users")
+			failure     = errors.New("unavailable")
+			invoker     = func(_ context.Context, method string, _, _ interface{}, _ *grpc.ClientConn, _ ...grpc.CallOption) error {
+				if method == "/users.Users/Delete" {
+					return failure
+				}
+				return nil
+			}
+			ctx = metadata.AppendToOutgoingContext(context.Background(), "x-request-id", "abc")
+		)
+
+		require.NoError(t, interceptor(ctx, "/users.Users/Get", "ignored", nil, nil, invoker))
+
+		r.Start()
+		require.NoError(t, interceptor(ctx, "/users.Users/Get", "req", nil, nil, invoker))
+		assert.ErrorIs(t, interceptor(ctx, "/users.Users/Delete", "req", nil, nil, invoker), failure)
+		r.Stop()
+
+		require.NoError(t, interceptor(ctx, "/users.Users/Get", "ignored", nil, nil, invoker))
+
+		calls := r.Calls()
+		require.Len(t, calls, 2)
+		assert.Equal(t, GRPCCall, calls[0].Kind)
+		assert.Equal(t, "users", calls[0].Service)
+		assert.Equal(t, "req", calls[0].Payload)
+		assert.Equal(t, []string{"abc"}, calls[0].Metadata["x-request-id"])
+		assert.ErrorIs(t, calls[1].Err, failure)
+
+		assert.Len(t, r.CallsTo("/users.Users/Delete"), 1)
+
+		r.Reset()
+		assert.Empty(t, r.Calls())
+	})
+
+	t.Run("should capture HTTP calls while recording", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			b, _ := io.ReadAll(r.Body)
+			_, _ = w.Write(b)
+		}))
+		defer server.Close()
+
+		var (
+			r      = NewRecorder()
+			client = &http.Client{Transport: r.Transport(nil)}
+		)
+		r.Start()
+
+		req, err := http.NewRequest(http.MethodPost, server.URL+"/users", strings.NewReader(`{"name":"john"}`))
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer token")
+
+		res, err := client.Do(req)
+		require.NoError(t, err)
+		body, err := io.ReadAll(res.Body)
+		require.NoError(t, err)
+		_ = res.Body.Close()
+		assert.Equal(t, `{"name":"john"}`, string(body))
+
+		calls := r.CallsTo("POST /users")
+		require.Len(t, calls, 1)
+		assert.Equal(t, HTTPCall, calls[0].Kind)
+		assert.Equal(t, []byte(`{"name":"john"}`), calls[0].Payload)
+		assert.Equal(t, []string{"Bearer token"}, calls[0].Metadata["Authorization"])
+	})
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/valyala/fasthttp"
 	"github.com/valyala/fasthttp/fasthttputil"
 	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc"
 )
 
 // Supported content types.
@@ -83,6 +84,28 @@ type Options struct {
 	// FeatureOptions is the mechanism that a test can pass specific features
 	// options to be used by it.
 	FeatureOptions map[string]interface{}
+
+	// Fixtures are external resources started by SetupTest and stopped by
+	// Teardown.
+	Fixtures []*Fixture
+
+	// CoupledServers are in-process implementations of the services coupled
+	// as gRPC clients, keyed by the service name. Clients of these services
+	// are connected to them by SetupTest instead of being left uninitialized,
+	// which requires the service to be started before SetupTest is called.
+	CoupledServers map[string]*CoupledServer
+}
+
+// CoupledServer is a gRPC service implementation that coupled clients can
+// call inside a test.
+type CoupledServer struct {
+	// Description is the gRPC service description, usually the
+	// <Service>_ServiceDesc variable of its generated code.
+	Description *grpc.ServiceDesc
+
+	// Server is the service implementation, e.g. the service struct of
+	// another mikros service started inside the test.
+	Server interface{}
 }
 
 // New creates a new Testing object to help building service unit tests. It can
@@ -0,0 +1,234 @@
+package mikros
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+
+	"github.com/mikros-dev/mikros/components/logger"
+	"github.com/mikros-dev/mikros/components/plugin"
+)
+
+const (
+	redactedConfigValue = "[REDACTED]"
+)
+
+var (
+	// secretConfigKeys are key name fragments of settings whose values must
+	// not be exposed. They are matched against every segment of the keys
+	// without separators, so "api_key", "API-KEY" and "ApiKey" are all
+	// matched by "apikey".
+	secretConfigKeys = []string{
+		"password",
+		"passwd",
+		"secret",
+		"token",
+		"apikey",
+		"privatekey",
+		"credential",
+		"authorization",
+		"cookie",
+	}
+
+	secretKeySeparators = strings.NewReplacer("_", "", "-", "")
+)
+
+// EffectiveConfig returns the service configuration gathered from its
+// definitions, options, environment variables and the effective settings
+// reported by its runtimes. Every entry is annotated with its source and
+// values of secret-like keys, as well as the values of every environment
+// variable declared by the service, are redacted.
+func (s *Service) EffectiveConfig() []plugin.ConfigEntry {
+	var entries []plugin.ConfigEntry
+
+	entries = append(entries, s.definitionsConfig()...)
+	entries = append(entries, s.optionsConfig()...)
+	entries = append(entries, s.envsConfig()...)
+	for _, r := range s.runtimes {
+		if c, ok := r.(plugin.RuntimeConfig); ok {
+			entries = append(entries, c.EffectiveConfig()...)
+		}
+	}
+
+	for i := range entries {
+		if isSecretConfigKey(entries[i].Key) {
+			entries[i].Value = redactedConfigValue
+		}
+	}
+
+	// Runtime entries override the definitions ones with the same key since
+	// they hold the value actually used.
+	slices.SortStableFunc(entries, func(a, b plugin.ConfigEntry) int {
+		return strings.Compare(a.Key, b.Key)
+	})
+
+	var effective []plugin.ConfigEntry
+	for i, e := range entries {
+		if i+1 < len(entries) && entries[i+1].Key == e.Key {
+			continue
+		}
+
+		effective = append(effective, e)
+	}
+
+	return effective
+}
+
+// EffectiveConfigHandler returns an HTTP handler that outputs the
+// service EffectiveConfig as JSON. It is intended to be mounted in an
+// administrative (non-public) route.
+func (s *Service) EffectiveConfigHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		if err := json.NewEncoder(w).Encode(s.EffectiveConfig()); err != nil {
+			s.logger.Error(r.Context(), "failed to write effective config", logger.Error(err))
+		}
+	})
+}
+
+func (s *Service) definitionsConfig() []plugin.ConfigEntry {
+	var (
+		entries []plugin.ConfigEntry
+		add     = func(key string, value interface{}) {
+			entries = append(entries, plugin.ConfigEntry{
+				Key:    key,
+				Value:  value,
+				Source: plugin.ConfigSourceDefinitions,
+			})
+		}
+	)
+
+	if values, ok := configValues(s.definitions); ok {
+		flattenConfig("", values, add)
+	}
+
+	// External features keep their settings out of the encoded definitions.
+	for _, name := range s.definitions.ExternalFeatureNames() {
+		defs, err := s.definitions.ExternalFeatureDefinitions(name)
+		if err != nil {
+			continue
+		}
+
+		if values, ok := configValues(defs); ok {
+			flattenConfig("features."+name, values, add)
+		}
+	}
+
+	return entries
+}
+
+// configValues converts settings into a map using their TOML keys.
+func configValues(settings interface{}) (map[string]interface{}, bool) {
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(settings); err != nil {
+		return nil, false
+	}
+
+	var values map[string]interface{}
+	if _, err := toml.Decode(buf.String(), &values); err != nil {
+		return nil, false
+	}
+
+	return values, true
+}
+
+func flattenConfig(prefix string, value interface{}, add func(key string, value interface{})) {
+	m, ok := value.(map[string]interface{})
+	if !ok {
+		add(prefix, value)
+		return
+	}
+
+	for k, v := range m {
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+
+		flattenConfig(key, v, add)
+	}
+}
+
+func (s *Service) optionsConfig() []plugin.ConfigEntry {
+	var entries []plugin.ConfigEntry
+
+	for name, opt := range s.serviceOptions {
+		v := reflect.Indirect(reflect.ValueOf(opt))
+		if v.Kind() != reflect.Struct {
+			continue
+		}
+
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if !field.IsExported() || !isPlainConfigValue(field.Type) {
+				continue
+			}
+
+			entries = append(entries, plugin.ConfigEntry{
+				Key:    fmt.Sprintf("options.%s.%s", name, field.Name),
+				Value:  v.Field(i).Interface(),
+				Source: plugin.ConfigSourceOptions,
+			})
+		}
+	}
+
+	return entries
+}
+
+// isPlainConfigValue tells if a type holds data that can be reported, which
+// excludes functions, such as middlewares, and channels.
+func isPlainConfigValue(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Func, reflect.Chan, reflect.Interface, reflect.UnsafePointer:
+		return false
+	case reflect.Slice, reflect.Array, reflect.Ptr, reflect.Map:
+		return isPlainConfigValue(t.Elem())
+	default:
+		return true
+	}
+}
+
+func (s *Service) envsConfig() []plugin.ConfigEntry {
+	var entries []plugin.ConfigEntry
+
+	for _, setting := range s.envs.Settings() {
+		source := plugin.ConfigSourceDefault
+		if setting.FromEnv {
+			source = plugin.ConfigSourceEnv
+		}
+
+		// Service variables, e.g. DATABASE_URL, may carry credentials
+		// whatever their names are, so only the framework ones are shown.
+		var value interface{} = redactedConfigValue
+		if setting.Framework {
+			value = setting.Value
+		}
+
+		entries = append(entries, plugin.ConfigEntry{
+			Key:    "env." + setting.Name,
+			Value:  value,
+			Source: source,
+		})
+	}
+
+	return entries
+}
+
+func isSecretConfigKey(key string) bool {
+	for _, segment := range strings.Split(strings.ToLower(key), ".") {
+		segment = secretKeySeparators.Replace(segment)
+		for _, s := range secretConfigKeys {
+			if strings.Contains(segment, s) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,96 @@
+package mikros
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/mikros-dev/mikros/components/definition"
+	"github.com/mikros-dev/mikros/components/logger"
+	"github.com/mikros-dev/mikros/internal/components/crash"
+)
+
+const (
+	// panicExitCode is the exit code of Go programs terminated by a panic.
+	panicExitCode = 2
+)
+
+// recentLogRecords returns how many log records must be kept in memory to
+// be tailed, flushed on crashes and added to crash reports.
+func recentLogRecords(defs *definition.Definitions) int {
+	records := defs.Log.RecentRecords
+	if defs.Crash.Path != "" {
+		records = max(records, defs.Crash.LogRecords)
+	}
+
+	return records
+}
+
+// reportPanic writes a crash report of an unrecovered panic before letting
+// it continue. It must be deferred.
+func (s *Service) reportPanic(ctx context.Context) {
+	if r := recover(); r != nil {
+		s.flushRecentLogs(ctx)
+		s.writeCrashReport(ctx, "panic", panicExitCode, nil, r)
+		panic(r)
+	}
+}
+
+// flushRecentLogs outputs the kept log records that were suppressed by the
+// current log level, giving context to the crash.
+func (s *Service) flushRecentLogs(ctx context.Context) {
+	count, err := s.logger.FlushRecentRecords(os.Stderr)
+	if err != nil {
+		s.logger.Error(ctx, "could not flush recent log records", logger.Error(err))
+		return
+	}
+
+	if count > 0 {
+		s.logger.Info(ctx, "recent log records flushed", logger.Int32("log.flushed_records", int32(count)))
+	}
+}
+
+// writeCrashReport writes the structured crash report of the service, if
+// enabled by its definitions. Failures are only logged since the service is
+// already going down.
+func (s *Service) writeCrashReport(ctx context.Context, reason string, exitCode int, err error, panicValue interface{}) {
+	if s.definitions.Crash.Path == "" {
+		return
+	}
+
+	report := crash.NewReport(s.definitions.ServiceName().String(), s.definitions.Version, reason)
+	report.ExitCode = exitCode
+	report.ConfigHash = crash.ConfigHash(s.definitions.Path())
+	report.Logs = lastRecords(s.logger.RecentRecords(), s.definitions.Crash.LogRecords)
+
+	if err != nil {
+		report.Error = err.Error()
+	}
+	if panicValue != nil {
+		report.Panic = fmt.Sprint(panicValue)
+	}
+
+	for _, u := range s.registeredFeatures.Usage() {
+		if u.Enabled {
+			report.Features = append(report.Features, u.Name)
+		}
+	}
+
+	path, werr := crash.Write(s.definitions.Crash.Path, report)
+	if werr != nil {
+		s.logger.Error(ctx, "could not write crash report", logger.Error(werr))
+		return
+	}
+
+	s.logger.Info(ctx, "crash report written", logger.String("crash.path", path))
+}
+
+// lastRecords returns the last n records.
+func lastRecords(records []json.RawMessage, n int) []json.RawMessage {
+	if len(records) > n {
+		return records[len(records)-n:]
+	}
+
+	return records
+}
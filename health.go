@@ -0,0 +1,76 @@
+package mikros
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/mikros-dev/mikros/components/logger"
+	"github.com/mikros-dev/mikros/components/plugin"
+)
+
+// HealthReport checks the health of the service features implementing
+// plugin.FeatureHealthChecker and aggregates it into the service readiness.
+// Degraded features, and unhealthy ones listed as non-critical by the
+// service definitions, don't make the service not ready.
+func (s *Service) HealthReport(ctx context.Context) plugin.HealthReport {
+	if s.health == nil {
+		// Features are not initialized yet.
+		return plugin.HealthReport{
+			State: plugin.HealthStateUnhealthy,
+		}
+	}
+
+	return s.health.Check(ctx)
+}
+
+// HealthHandler returns an HTTP handler that outputs the service HealthReport
+// as JSON, responding with 503 when the service is not ready. It is intended
+// to be used as the service readiness probe.
+func (s *Service) HealthHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		report := s.HealthReport(r.Context())
+
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		if !report.Ready {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+
+		if err := json.NewEncoder(w).Encode(report); err != nil {
+			s.logger.Error(r.Context(), "failed to write health report", logger.Error(err))
+		}
+	})
+}
+
+// runtimeHealth returns the service readiness given to the runtimes, so
+// their own health checks follow it.
+func (s *Service) runtimeHealth() plugin.ServiceHealth {
+	if s.health == nil {
+		return nil
+	}
+
+	return s.health
+}
+
+// monitorHealth periodically checks the features health, so their state
+// transitions are logged and the runtimes health follows them, if enabled
+// by the service definitions.
+func (s *Service) monitorHealth(ctx context.Context) {
+	interval := s.definitions.Health.CheckInterval
+	if interval <= 0 || s.health == nil {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.health.Check(ctx)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+// Package crash writes the structured reports of services that abort or
+// panic, gathering what is needed for postmortems in a single JSON file.
+package crash
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"time"
+)
+
+const (
+	maxStackSize = 8 << 20
+)
+
+// Report is a crash report.
+type Report struct {
+	Time       time.Time         `json:"time"`
+	Service    string            `json:"service"`
+	Version    string            `json:"version"`
+	PID        int               `json:"pid"`
+	GoVersion  string            `json:"go_version"`
+	Reason     string            `json:"reason"`
+	ExitCode   int               `json:"exit_code,omitempty"`
+	Error      string            `json:"error,omitempty"`
+	Panic      string            `json:"panic,omitempty"`
+	ConfigHash string            `json:"config_hash,omitempty"`
+	Features   []string          `json:"features"`
+	Stack      string            `json:"stack"`
+	Logs       []json.RawMessage `json:"logs"`
+}
+
+// NewReport creates a report of the current process with the stack traces
+// of all its goroutines.
+func NewReport(service, version, reason string) *Report {
+	return &Report{
+		Time:      time.Now().UTC(),
+		Service:   service,
+		Version:   version,
+		PID:       os.Getpid(),
+		GoVersion: runtime.Version(),
+		Reason:    reason,
+		Stack:     Stacks(),
+	}
+}
+
+// Write writes the report as a JSON file inside dir and returns its path.
+func Write(dir string, report *Report) (string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+
+	b, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	name := fmt.Sprintf("crash-%s-%s-%d.json", report.Service, report.Time.Format("20060102T150405Z"), report.PID)
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, b, 0o600); err != nil {
+		return "", err
+	}
+
+	return path, nil
+}
+
+// ConfigHash returns the SHA-256 of the file at path, so reports tell which
+// configuration the service was running with. It returns an empty string if
+// the file cannot be read.
+func ConfigHash(path string) string {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+// Stacks returns the stack traces of all goroutines.
+func Stacks() string {
+	buf := make([]byte, 64<<10)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) || len(buf) >= maxStackSize {
+			return string(buf[:n])
+		}
+
+		buf = make([]byte, len(buf)*2)
+	}
+}
@@ -0,0 +1,56 @@
+package crash
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrite(t *testing.T) {
+	t.Run("should write the report as JSON", func(t *testing.T) {
+		var (
+			dir    = filepath.Join(t.TempDir(), "crashes")
+			report = NewReport("users", "v1.2.0", "runtime")
+		)
+
+		report.Error = "listener closed"
+		report.Features = []string{"cache", "tracing"}
+		report.Logs = []json.RawMessage{json.RawMessage(`{"msg":"started"}`)}
+
+		path, err := Write(dir, report)
+		require.NoError(t, err)
+		assert.Equal(t, dir, filepath.Dir(path))
+		assert.Contains(t, filepath.Base(path), "crash-users-")
+
+		b, err := os.ReadFile(path)
+		require.NoError(t, err)
+
+		var out map[string]interface{}
+		require.NoError(t, json.Unmarshal(b, &out))
+		assert.Equal(t, "users", out["service"])
+		assert.Equal(t, "runtime", out["reason"])
+		assert.Equal(t, "listener closed", out["error"])
+		assert.Equal(t, []interface{}{"cache", "tracing"}, out["features"])
+		assert.Equal(t, []interface{}{map[string]interface{}{"msg": "started"}}, out["logs"])
+		assert.Contains(t, out["stack"], "goroutine")
+	})
+}
+
+func TestConfigHash(t *testing.T) {
+	t.Run("should hash the file contents", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "service.toml")
+		require.NoError(t, os.WriteFile(path, []byte(`name = "users"`), 0o600))
+
+		hash := ConfigHash(path)
+		assert.Len(t, hash, 64)
+		assert.Equal(t, hash, ConfigHash(path))
+	})
+
+	t.Run("should return empty for missing files", func(t *testing.T) {
+		assert.Empty(t, ConfigHash(filepath.Join(t.TempDir(), "missing.toml")))
+	})
+}
@@ -38,6 +38,11 @@ func (e *GlobalEnvs) postLoad() {
 // isRunningTest returns if the current session is being executed in test mode.
 func (e *GlobalEnvs) isRunningTest() bool {
 	for _, arg := range os.Args {
+		// The self-test mode runs the service as it is deployed.
+		if strings.HasPrefix(strings.TrimLeft(arg, "-"), "selftest") {
+			continue
+		}
+
 		if strings.HasSuffix(arg, ".test") || strings.Contains(arg, "-test") {
 			return true
 		}
@@ -3,6 +3,8 @@ package env
 import (
 	"fmt"
 	"os"
+	"reflect"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -17,7 +19,8 @@ const (
 // ServiceEnvs is the object that will allow all internal (and external) mikros
 // features to access the environment variables loaded.
 type ServiceEnvs struct {
-	envs *GlobalEnvs
+	envs        *GlobalEnvs
+	serviceName string
 
 	// definedEnvs holds all variables pointed directly into the 'service.toml'
 	// file.
@@ -42,6 +45,7 @@ func NewServiceEnvs(defs *definition.Definitions) (*ServiceEnvs, error) {
 
 	return &ServiceEnvs{
 		envs:        &envs,
+		serviceName: defs.ServiceName().String(),
 		definedEnvs: definedEnvs,
 	}, nil
 }
@@ -142,3 +146,77 @@ func (s *ServiceEnvs) GetBool(name string) (bool, error) {
 	b, err := strconv.ParseBool(s.Get(name))
 	return b, err
 }
+
+// Setting is an environment-backed setting and its current value.
+type Setting struct {
+	// Name is the environment variable name.
+	Name string
+
+	// Value is the current setting value.
+	Value string
+
+	// FromEnv tells if the value was loaded from the environment or if it
+	// is a default value.
+	FromEnv bool
+
+	// Framework tells if the setting is one of the framework variables,
+	// whose values are known to hold no secrets, instead of one declared
+	// by the service.
+	Framework bool
+}
+
+// Settings returns all framework and service-defined environment settings
+// with their current values.
+func (s *ServiceEnvs) Settings() []Setting {
+	var (
+		settings []Setting
+		v        = reflect.ValueOf(s.envs).Elem()
+		t        = v.Type()
+	)
+
+	for i := 0; i < t.NumField(); i++ {
+		tag, ok := t.Field(i).Tag.Lookup("env")
+		if !ok {
+			continue
+		}
+
+		name, _, _ := strings.Cut(tag, ",")
+		settings = append(settings, Setting{
+			Name:      name,
+			Value:     settingValue(v.Field(i)),
+			FromEnv:   s.isSet(name),
+			Framework: true,
+		})
+	}
+
+	for name, value := range s.definedEnvs {
+		settings = append(settings, Setting{
+			Name:    name,
+			Value:   value,
+			FromEnv: true,
+		})
+	}
+
+	slices.SortFunc(settings, func(a, b Setting) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return settings
+}
+
+func (s *ServiceEnvs) isSet(name string) bool {
+	if _, ok := os.LookupEnv(s.serviceName + "__" + name); ok {
+		return true
+	}
+
+	_, ok := os.LookupEnv(name)
+	return ok
+}
+
+func settingValue(v reflect.Value) string {
+	if stringer, ok := v.Addr().Interface().(fmt.Stringer); ok {
+		return stringer.String()
+	}
+
+	return fmt.Sprintf("%v", v.Interface())
+}
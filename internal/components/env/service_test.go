@@ -0,0 +1,54 @@
+package env
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mikros-dev/mikros/components/definition"
+)
+
+func TestSettings(t *testing.T) {
+	t.Setenv("MIKROS_HTTP_PORT", "9090")
+	t.Setenv("orders__MIKROS_GRPC_PORT", "7071")
+	t.Setenv("DB_HOST", "db.local")
+
+	defs, err := definition.New()
+	require.NoError(t, err)
+	defs.Name = "orders"
+	defs.Envs = []string{"DB_HOST"}
+
+	envs, err := NewServiceEnvs(defs)
+	require.NoError(t, err)
+
+	settings := make(map[string]Setting)
+	var names []string
+	for _, s := range envs.Settings() {
+		settings[s.Name] = s
+		names = append(names, s.Name)
+	}
+
+	tests := []struct {
+		name    string
+		value   string
+		fromEnv bool
+	}{
+		{name: "MIKROS_HTTP_PORT", value: "9090", fromEnv: true},
+		{name: "MIKROS_GRPC_PORT", value: "7071", fromEnv: true},
+		{name: "MIKROS_COUPLED_PORT", value: "7070", fromEnv: false},
+		{name: "MIKROS_TRACKER_HEADER_NAME", value: "X-Request-ID", fromEnv: false},
+		{name: "DB_HOST", value: "db.local", fromEnv: true},
+	}
+
+	for _, tt := range tests {
+		s, ok := settings[tt.name]
+		require.True(t, ok, tt.name)
+		assert.Equal(t, tt.value, s.Value, tt.name)
+		assert.Equal(t, tt.fromEnv, s.FromEnv, tt.name)
+	}
+
+	assert.True(t, settings["MIKROS_HTTP_PORT"].Framework)
+	assert.False(t, settings["DB_HOST"].Framework)
+	assert.IsIncreasing(t, names)
+}
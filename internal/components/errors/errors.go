@@ -1,6 +1,7 @@
 package errors
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 
@@ -71,17 +72,8 @@ func (v *value) Unwrap() error {
 	return v.cause
 }
 
-type grpcErrorMessage struct {
-	Kind        merrors.Kind `json:"kind"`
-	Message     string       `json:"message,omitempty"`
-	Cause       string       `json:"cause,omitempty"`
-	Code        int32        `json:"code,omitempty"`
-	ServiceName string       `json:"service_name,omitempty"`
-	Destination string       `json:"destination,omitempty"`
-}
-
-func (v *value) grpcMessage() grpcErrorMessage {
-	msg := grpcErrorMessage{
+func (v *value) grpcMessage() merrors.Payload {
+	msg := merrors.Payload{
 		Kind:        v.kind,
 		Message:     v.message,
 		Code:        v.code,
@@ -95,18 +87,33 @@ func (v *value) grpcMessage() grpcErrorMessage {
 	return msg
 }
 
+// ToGRPCStatus converts a service error into a gRPC status using the JSON
+// serializer, understood by every client. It returns false if err is not a
+// service error.
 func ToGRPCStatus(err error) (*status.Status, bool, error) {
+	return ToGRPCStatusWith(err, merrors.JSONSerializer())
+}
+
+// ToGRPCStatusFor converts a service error into a gRPC status using the
+// serializer negotiated with the client of the incoming call of ctx.
+func ToGRPCStatusFor(ctx context.Context, err error) (*status.Status, bool, error) {
+	return ToGRPCStatusWith(err, merrors.NegotiateSerializer(merrors.AcceptedSerializers(ctx)))
+}
+
+// ToGRPCStatusWith converts a service error into a gRPC status using a
+// specific serializer, usually the one negotiated with the client.
+func ToGRPCStatusWith(err error, serializer merrors.Serializer) (*status.Status, bool, error) {
 	var v *value
 	if !errors.As(err, &v) {
 		return nil, false, nil
 	}
 
-	b, err := json.Marshal(v.grpcMessage())
+	st, err := serializer.Encode(grpcCode(v.kind), v.grpcMessage())
 	if err != nil {
 		return nil, false, err
 	}
 
-	return status.New(grpcCode(v.kind), string(b)), true, nil
+	return st, true, nil
 }
 
 func grpcCode(kind merrors.Kind) codes.Code {
@@ -135,8 +142,8 @@ func FromGRPCStatus(st *status.Status, from, to service.Name) error {
 		return internalRemoteError(from, to, "nil gRPC status")
 	}
 
-	var msg grpcErrorMessage
-	if err := json.Unmarshal([]byte(st.Message()), &msg); err != nil {
+	msg, ok := merrors.DecodeStatus(st)
+	if !ok {
 		return internalRemoteError(from, to, st.Message())
 	}
 
@@ -164,3 +171,9 @@ func internalRemoteError(from, to service.Name, msg string) error {
 		message:     "got an internal error",
 	}
 }
+
+// AppendAcceptedSerializers lists, in the metadata of an outgoing gRPC call,
+// the serializers this client decodes.
+func AppendAcceptedSerializers(ctx context.Context) context.Context {
+	return merrors.AppendAcceptedSerializers(ctx)
+}
@@ -0,0 +1,107 @@
+// Package expand replaces ${VAR} references inside values with variables,
+// as done for environment variables and service definitions.
+package expand
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Value replaces the ${VAR} references inside value with the variables
+// given by lookup. A ${VAR:-fallback} reference uses fallback, which can
+// also have references, when VAR is not set or is empty. $${ is kept as a
+// literal ${. Values of referenced variables are not expanded again, so
+// references cannot loop.
+//
+// When set, notFound is called for every variable that is not set and has
+// no fallback, which is replaced by an empty string.
+func Value(
+	value string,
+	lookup func(name string) (string, bool),
+	notFound func(name string),
+) (string, error) {
+	if !strings.Contains(value, "${") {
+		return value, nil
+	}
+
+	var b strings.Builder
+	for {
+		i := strings.Index(value, "${")
+		if i < 0 {
+			b.WriteString(value)
+			break
+		}
+
+		if i > 0 && value[i-1] == '$' {
+			b.WriteString(value[:i-1])
+			b.WriteString("${")
+			value = value[i+2:]
+			continue
+		}
+
+		b.WriteString(value[:i])
+		end := closingBrace(value[i+2:])
+		if end < 0 {
+			return "", fmt.Errorf("unterminated reference in %q", value[i:])
+		}
+
+		expanded, err := expandReference(value[i+2:i+2+end], lookup, notFound)
+		if err != nil {
+			return "", err
+		}
+
+		b.WriteString(expanded)
+		value = value[i+2+end+1:]
+	}
+
+	return b.String(), nil
+}
+
+// closingBrace gives back the index of the brace closing a reference,
+// skipping the ones of references nested in its fallback.
+func closingBrace(s string) int {
+	depth := 0
+	for i := 0; i < len(s); i++ {
+		switch {
+		case strings.HasPrefix(s[i:], "${"):
+			depth++
+			i++
+		case s[i] == '}':
+			if depth == 0 {
+				return i
+			}
+			depth--
+		}
+	}
+
+	return -1
+}
+
+func expandReference(
+	ref string,
+	lookup func(name string) (string, bool),
+	notFound func(name string),
+) (string, error) {
+	name, fallback, hasFallback := strings.Cut(ref, ":-")
+	if name == "" {
+		return "", errors.New("empty variable reference")
+	}
+	if strings.ContainsAny(name, " \t${}") {
+		return "", fmt.Errorf("invalid variable reference %q", name)
+	}
+
+	value, ok := lookup(name)
+	if !hasFallback {
+		if !ok && notFound != nil {
+			notFound(name)
+		}
+
+		return value, nil
+	}
+	if value != "" {
+		return value, nil
+	}
+
+	return Value(fallback, lookup, notFound)
+}
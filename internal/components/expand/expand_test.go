@@ -0,0 +1,71 @@
+package expand
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValue(t *testing.T) {
+	lookup := func(name string) (string, bool) {
+		values := map[string]string{
+			"USER":  "admin",
+			"HOST":  "db",
+			"EMPTY": "",
+			"RAW":   "${USER}",
+		}
+		value, ok := values[name]
+		return value, ok
+	}
+
+	t.Run("should expand references", func(t *testing.T) {
+		tests := map[string]string{
+			"plain":                        "plain",
+			"${USER}@${HOST}":              "admin@db",
+			"${MISSING:-fallback}":         "fallback",
+			"${EMPTY:-fallback}":           "fallback",
+			"${EMPTY}":                     "",
+			"${HOST:-fallback}":            "db",
+			"${MISSING:-${HOST}:5432}":     "db:5432",
+			"${MISSING:-}":                 "",
+			"$${USER} is ${USER}":          "${USER} is admin",
+			"cost$5":                       "cost$5",
+			"${RAW}":                       "${USER}",
+			"${MISSING:-${OTHER:-nested}}": "nested",
+		}
+
+		for value, expected := range tests {
+			var notFound []string
+			out, err := Value(value, lookup, func(name string) {
+				notFound = append(notFound, name)
+			})
+			require.NoError(t, err, value)
+			assert.Equal(t, expected, out, value)
+			assert.Empty(t, notFound, value)
+		}
+	})
+
+	t.Run("should report variables not set", func(t *testing.T) {
+		var notFound []string
+		out, err := Value("${MISSING}/${OTHER:-x}/${LAST}", lookup, func(name string) {
+			notFound = append(notFound, name)
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "/x/", out)
+		assert.Equal(t, []string{"MISSING", "LAST"}, notFound)
+	})
+
+	t.Run("should accept no missing variable hook", func(t *testing.T) {
+		out, err := Value("${MISSING}", lookup, nil)
+		require.NoError(t, err)
+		assert.Empty(t, out)
+	})
+
+	t.Run("should fail with invalid references", func(t *testing.T) {
+		for _, value := range []string{"${USER", "${}", "${:-x}", "${BAD NAME}", "${MISSING:-${HOST}"} {
+			_, err := Value(value, lookup, nil)
+			assert.Error(t, err, value)
+		}
+	})
+}
@@ -0,0 +1,231 @@
+// Package health aggregates the health reported by the service features into
+// its readiness, logging and counting the state transitions of every one of
+// them.
+package health
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	logger_api "github.com/mikros-dev/mikros/apis/features/logger"
+	"github.com/mikros-dev/mikros/components/concurrent"
+	"github.com/mikros-dev/mikros/components/logger"
+	"github.com/mikros-dev/mikros/components/plugin"
+)
+
+const (
+	defaultTimeout = 5 * time.Second
+)
+
+// Options configures a Monitor.
+type Options struct {
+	// Timeout limits how long a feature has to report its health. Defaults
+	// to 5 seconds.
+	Timeout time.Duration
+
+	// NonCritical lists features that don't affect the service readiness
+	// when they are unhealthy.
+	NonCritical []string
+
+	// Now is replaced by tests.
+	Now func() time.Time
+}
+
+// Monitor checks the health of the enabled features implementing
+// plugin.FeatureHealthChecker.
+type Monitor struct {
+	features *plugin.FeatureSet
+	logger   logger_api.API
+	options  Options
+	mu       sync.Mutex
+	states   map[string]*featureState
+	subs     []func(report plugin.HealthReport)
+}
+
+type featureState struct {
+	state       plugin.HealthState
+	since       time.Time
+	transitions int64
+}
+
+// NewMonitor creates a new Monitor.
+func NewMonitor(features *plugin.FeatureSet, log logger_api.API, options Options) *Monitor {
+	if options.Timeout <= 0 {
+		options.Timeout = defaultTimeout
+	}
+	if options.Now == nil {
+		options.Now = time.Now
+	}
+
+	return &Monitor{
+		features: features,
+		logger:   log,
+		options:  options,
+		states:   make(map[string]*featureState),
+	}
+}
+
+// Check checks the health of every feature, in parallel, and aggregates it.
+// Features changing their state since the last check are logged and the
+// report is sent to the subscribers.
+func (m *Monitor) Check(ctx context.Context) plugin.HealthReport {
+	checkers := m.checkers()
+
+	// Every feature has its own worker, so none of them waits for another
+	// one to be checked, and a failure here is never reported since
+	// checkFeature doesn't return errors.
+	results, _ := concurrent.Map(ctx, checkers, func(ctx context.Context, c namedChecker) (plugin.FeatureHealth, error) {
+		return m.checkFeature(ctx, c.checker), nil
+	}, concurrent.PoolOptions{Workers: max(len(checkers), 1)})
+
+	report := plugin.HealthReport{
+		State:    plugin.HealthStateHealthy,
+		Ready:    true,
+		Features: make([]plugin.FeatureHealthReport, 0, len(checkers)),
+	}
+
+	for i, c := range checkers {
+		feature := m.update(ctx, c.name, results[i])
+		report.Features = append(report.Features, feature)
+
+		switch {
+		case feature.State == plugin.HealthStateUnhealthy && feature.Critical:
+			report.State = plugin.HealthStateUnhealthy
+			report.Ready = false
+		case feature.State != plugin.HealthStateHealthy && report.State == plugin.HealthStateHealthy:
+			report.State = plugin.HealthStateDegraded
+		}
+	}
+
+	m.mu.Lock()
+	subs := slices.Clone(m.subs)
+	m.mu.Unlock()
+
+	for _, fn := range subs {
+		fn(report)
+	}
+
+	return report
+}
+
+// Subscribe registers fn to receive the report of every check.
+func (m *Monitor) Subscribe(fn func(report plugin.HealthReport)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.subs = append(m.subs, fn)
+}
+
+type namedChecker struct {
+	name    string
+	checker plugin.FeatureHealthChecker
+}
+
+func (m *Monitor) checkers() []namedChecker {
+	var (
+		checkers []namedChecker
+		it       = m.features.Iterator()
+	)
+
+	for f, next := it.Next(); next; f, next = it.Next() {
+		c, ok := f.(plugin.FeatureHealthChecker)
+		if !ok || !f.IsEnabled() {
+			continue
+		}
+
+		checkers = append(checkers, namedChecker{name: f.Name(), checker: c})
+	}
+
+	return checkers
+}
+
+// checkFeature calls the feature health check, considering it unhealthy when
+// it doesn't answer in time.
+func (m *Monitor) checkFeature(ctx context.Context, checker plugin.FeatureHealthChecker) plugin.FeatureHealth {
+	ctx, cancel := context.WithTimeout(ctx, m.options.Timeout)
+	defer cancel()
+
+	result := make(chan plugin.FeatureHealth, 1)
+	go func() {
+		result <- checker.Health(ctx)
+	}()
+
+	select {
+	case h := <-result:
+		switch h.State {
+		case plugin.HealthStateHealthy, plugin.HealthStateDegraded, plugin.HealthStateUnhealthy:
+			return h
+		default:
+			return plugin.FeatureHealth{
+				State:  plugin.HealthStateUnhealthy,
+				Reason: "unknown health state '" + string(h.State) + "'",
+			}
+		}
+	case <-ctx.Done():
+		return plugin.FeatureHealth{
+			State:  plugin.HealthStateUnhealthy,
+			Reason: "health check timed out",
+		}
+	}
+}
+
+// update records the current health of a feature, logging it when its state
+// changed.
+func (m *Monitor) update(ctx context.Context, name string, h plugin.FeatureHealth) plugin.FeatureHealthReport {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.options.Now()
+	s, ok := m.states[name]
+	if !ok {
+		// Features are assumed healthy until their first check.
+		s = &featureState{
+			state: plugin.HealthStateHealthy,
+			since: now,
+		}
+		m.states[name] = s
+	}
+
+	previous := s.state
+	if h.State != previous {
+		s.state = h.State
+		s.since = now
+		s.transitions++
+		m.logTransition(ctx, name, previous, h)
+	}
+
+	return plugin.FeatureHealthReport{
+		Name:        name,
+		State:       s.state,
+		Reason:      h.Reason,
+		Critical:    !slices.Contains(m.options.NonCritical, name),
+		Since:       s.since,
+		Transitions: s.transitions,
+	}
+}
+
+func (m *Monitor) logTransition(ctx context.Context, name string, previous plugin.HealthState, h plugin.FeatureHealth) {
+	if m.logger == nil {
+		return
+	}
+
+	attrs := []logger_api.Attribute{
+		logger.String("feature.name", name),
+		logger.String("health.previous_state", string(previous)),
+		logger.String("health.state", string(h.State)),
+	}
+	if h.Reason != "" {
+		attrs = append(attrs, logger.String("health.reason", h.Reason))
+	}
+
+	switch h.State {
+	case plugin.HealthStateHealthy:
+		m.logger.Info(ctx, "feature is healthy again", attrs...)
+	case plugin.HealthStateDegraded:
+		m.logger.Warn(ctx, "feature is degraded", attrs...)
+	default:
+		m.logger.Error(ctx, "feature is unhealthy", attrs...)
+	}
+}
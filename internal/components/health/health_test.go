@@ -0,0 +1,255 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	logger_api "github.com/mikros-dev/mikros/apis/features/logger"
+	"github.com/mikros-dev/mikros/components/plugin"
+)
+
+type fakeFeature struct {
+	plugin.Entry
+	mu     sync.Mutex
+	health plugin.FeatureHealth
+	delay  time.Duration
+}
+
+func (f *fakeFeature) CanBeInitialized(*plugin.CanBeInitializedOptions) bool {
+	return true
+}
+
+func (f *fakeFeature) Initialize(context.Context, *plugin.InitializeOptions) error {
+	return nil
+}
+
+func (f *fakeFeature) Fields() []logger_api.Attribute {
+	return nil
+}
+
+func (f *fakeFeature) Health(ctx context.Context) plugin.FeatureHealth {
+	if f.delay > 0 {
+		select {
+		case <-time.After(f.delay):
+		case <-ctx.Done():
+		}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.health
+}
+
+func (f *fakeFeature) set(state plugin.HealthState, reason string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.health = plugin.FeatureHealth{State: state, Reason: reason}
+}
+
+// plainFeature doesn't report its health.
+type plainFeature struct {
+	plugin.Entry
+}
+
+func (f *plainFeature) CanBeInitialized(*plugin.CanBeInitializedOptions) bool {
+	return true
+}
+
+func (f *plainFeature) Initialize(context.Context, *plugin.InitializeOptions) error {
+	return nil
+}
+
+func (f *plainFeature) Fields() []logger_api.Attribute {
+	return nil
+}
+
+type fakeLogger struct {
+	logger_api.API
+	mu       sync.Mutex
+	messages []string
+}
+
+func (l *fakeLogger) Info(_ context.Context, msg string, _ ...logger_api.Attribute) {
+	l.add("info: " + msg)
+}
+
+func (l *fakeLogger) Warn(_ context.Context, msg string, _ ...logger_api.Attribute) {
+	l.add("warn: " + msg)
+}
+
+func (l *fakeLogger) Error(_ context.Context, msg string, _ ...logger_api.Attribute) {
+	l.add("error: " + msg)
+}
+
+func (l *fakeLogger) add(msg string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.messages = append(l.messages, msg)
+}
+
+func newFeatureSet(features map[string]plugin.Feature, disabled ...string) *plugin.FeatureSet {
+	set := plugin.NewFeatureSet()
+	for _, name := range []string{"cache", "database", "plain", "queue"} {
+		f, ok := features[name]
+		if !ok {
+			continue
+		}
+
+		set.Register(name, f)
+		enabled := true
+		for _, d := range disabled {
+			if d == name {
+				enabled = false
+			}
+		}
+		f.UpdateInfo(plugin.UpdateInfoEntry{Name: name, Enabled: enabled})
+	}
+
+	return set
+}
+
+func TestMonitorCheck(t *testing.T) {
+	t.Run("should be ready with healthy features", func(t *testing.T) {
+		var (
+			cache = &fakeFeature{health: plugin.FeatureHealth{State: plugin.HealthStateHealthy}}
+			m     = NewMonitor(newFeatureSet(map[string]plugin.Feature{
+				"cache": cache,
+				"plain": &plainFeature{},
+			}), nil, Options{})
+		)
+
+		report := m.Check(context.Background())
+		assert.True(t, report.Ready)
+		assert.Equal(t, plugin.HealthStateHealthy, report.State)
+		assert.Len(t, report.Features, 1)
+		assert.Equal(t, "cache", report.Features[0].Name)
+		assert.True(t, report.Features[0].Critical)
+	})
+
+	t.Run("should aggregate states by criticality", func(t *testing.T) {
+		var (
+			cache    = &fakeFeature{}
+			database = &fakeFeature{}
+			m        = NewMonitor(newFeatureSet(map[string]plugin.Feature{
+				"cache":    cache,
+				"database": database,
+			}), nil, Options{NonCritical: []string{"cache"}})
+		)
+
+		cache.set(plugin.HealthStateUnhealthy, "connection refused")
+		database.set(plugin.HealthStateDegraded, "slow queries")
+		report := m.Check(context.Background())
+		assert.True(t, report.Ready)
+		assert.Equal(t, plugin.HealthStateDegraded, report.State)
+		assert.False(t, report.Features[0].Critical)
+		assert.Equal(t, "connection refused", report.Features[0].Reason)
+
+		database.set(plugin.HealthStateUnhealthy, "connection lost")
+		report = m.Check(context.Background())
+		assert.False(t, report.Ready)
+		assert.Equal(t, plugin.HealthStateUnhealthy, report.State)
+	})
+
+	t.Run("should ignore disabled features", func(t *testing.T) {
+		var (
+			database = &fakeFeature{health: plugin.FeatureHealth{State: plugin.HealthStateUnhealthy}}
+			m        = NewMonitor(newFeatureSet(map[string]plugin.Feature{
+				"database": database,
+			}, "database"), nil, Options{})
+		)
+
+		report := m.Check(context.Background())
+		assert.True(t, report.Ready)
+		assert.Empty(t, report.Features)
+	})
+
+	t.Run("should log and count state transitions", func(t *testing.T) {
+		var (
+			now      = time.Now()
+			log      = &fakeLogger{}
+			database = &fakeFeature{}
+			m        = NewMonitor(newFeatureSet(map[string]plugin.Feature{
+				"database": database,
+			}), log, Options{Now: func() time.Time { return now }})
+		)
+
+		database.set(plugin.HealthStateHealthy, "")
+		m.Check(context.Background())
+
+		now = now.Add(time.Minute)
+		database.set(plugin.HealthStateDegraded, "slow")
+		m.Check(context.Background())
+		m.Check(context.Background())
+
+		now = now.Add(time.Minute)
+		database.set(plugin.HealthStateUnhealthy, "down")
+		m.Check(context.Background())
+
+		now = now.Add(time.Minute)
+		database.set(plugin.HealthStateHealthy, "")
+		report := m.Check(context.Background())
+
+		assert.Equal(t, []string{
+			"warn: feature is degraded",
+			"error: feature is unhealthy",
+			"info: feature is healthy again",
+		}, log.messages)
+		assert.Equal(t, int64(3), report.Features[0].Transitions)
+		assert.Equal(t, now, report.Features[0].Since)
+	})
+
+	t.Run("should consider unhealthy features that don't answer in time", func(t *testing.T) {
+		var (
+			database = &fakeFeature{
+				health: plugin.FeatureHealth{State: plugin.HealthStateHealthy},
+				delay:  time.Second,
+			}
+			m = NewMonitor(newFeatureSet(map[string]plugin.Feature{
+				"database": database,
+			}), nil, Options{Timeout: 10 * time.Millisecond})
+		)
+
+		report := m.Check(context.Background())
+		assert.False(t, report.Ready)
+		assert.Equal(t, "health check timed out", report.Features[0].Reason)
+	})
+
+	t.Run("should consider unhealthy unknown states", func(t *testing.T) {
+		var (
+			database = &fakeFeature{health: plugin.FeatureHealth{State: "broken"}}
+			m        = NewMonitor(newFeatureSet(map[string]plugin.Feature{
+				"database": database,
+			}), nil, Options{})
+		)
+
+		report := m.Check(context.Background())
+		assert.Equal(t, plugin.HealthStateUnhealthy, report.Features[0].State)
+	})
+}
+
+func TestMonitorSubscribe(t *testing.T) {
+	t.Run("should send the report of every check to the subscribers", func(t *testing.T) {
+		var (
+			cache   = &fakeFeature{health: plugin.FeatureHealth{State: plugin.HealthStateUnhealthy}}
+			m       = NewMonitor(newFeatureSet(map[string]plugin.Feature{"cache": cache}), nil, Options{})
+			reports []plugin.HealthReport
+		)
+
+		m.Subscribe(func(report plugin.HealthReport) {
+			reports = append(reports, report)
+		})
+
+		m.Check(context.Background())
+		m.Check(context.Background())
+
+		assert.Len(t, reports, 2)
+		assert.False(t, reports[0].Ready)
+	})
+}
@@ -0,0 +1,176 @@
+package limits
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"strconv"
+	"strings"
+)
+
+const (
+	defaultCgroupRoot       = "/sys/fs/cgroup"
+	defaultMemoryLimitRatio = 0.9
+)
+
+// Sources of the effective limits.
+const (
+	SourceDefault     = "default"
+	SourceEnv         = "env"
+	SourceCgroup      = "cgroup"
+	SourceDefinitions = "definitions"
+)
+
+// Options configures how the Go runtime limits are adjusted.
+type Options struct {
+	// MaxProcs overrides the detected GOMAXPROCS value.
+	MaxProcs int
+
+	// MemoryLimit overrides the detected GOMEMLIMIT value, in bytes.
+	MemoryLimit int64
+
+	// MemoryLimitRatio is the share of the container memory limit used as
+	// GOMEMLIMIT, leaving room for non-heap memory. Defaults to 0.9.
+	MemoryLimitRatio float64
+
+	// CgroupRoot is where the cgroup filesystem is mounted. Defaults to
+	// /sys/fs/cgroup.
+	CgroupRoot string
+}
+
+// Result holds the effective limits and where they came from.
+type Result struct {
+	MaxProcs          int
+	MaxProcsSource    string
+	MemoryLimit       int64
+	MemoryLimitSource string
+}
+
+// Apply sets GOMAXPROCS and GOMEMLIMIT according to the container CPU and
+// memory limits. Explicit options have precedence, followed by the
+// GOMAXPROCS and GOMEMLIMIT environment variables, which are already
+// handled by the Go runtime and are kept untouched.
+func Apply(options Options) Result {
+	if options.CgroupRoot == "" {
+		options.CgroupRoot = defaultCgroupRoot
+	}
+	if options.MemoryLimitRatio <= 0 || options.MemoryLimitRatio > 1 {
+		options.MemoryLimitRatio = defaultMemoryLimitRatio
+	}
+
+	var result Result
+	result.MaxProcs, result.MaxProcsSource = maxProcs(options)
+	runtime.GOMAXPROCS(result.MaxProcs)
+
+	result.MemoryLimit, result.MemoryLimitSource = memoryLimit(options)
+	debug.SetMemoryLimit(result.MemoryLimit)
+
+	return result
+}
+
+func maxProcs(options Options) (int, string) {
+	if options.MaxProcs > 0 {
+		return options.MaxProcs, SourceDefinitions
+	}
+
+	if _, ok := os.LookupEnv("GOMAXPROCS"); ok {
+		return runtime.GOMAXPROCS(0), SourceEnv
+	}
+
+	if quota, ok := CPUQuota(options.CgroupRoot); ok {
+		return max(1, int(math.Ceil(quota))), SourceCgroup
+	}
+
+	return runtime.GOMAXPROCS(0), SourceDefault
+}
+
+func memoryLimit(options Options) (int64, string) {
+	if options.MemoryLimit > 0 {
+		return options.MemoryLimit, SourceDefinitions
+	}
+
+	// A negative input only queries the current limit.
+	current := debug.SetMemoryLimit(-1)
+	if _, ok := os.LookupEnv("GOMEMLIMIT"); ok {
+		return current, SourceEnv
+	}
+
+	if limit, ok := MemoryMax(options.CgroupRoot); ok {
+		return int64(float64(limit) * options.MemoryLimitRatio), SourceCgroup
+	}
+
+	return current, SourceDefault
+}
+
+// CPUQuota returns the number of CPUs available to the container, according
+// to its cgroup (v2 or v1) CPU quota.
+func CPUQuota(root string) (float64, bool) {
+	// cgroup v2: "<quota> <period>" or "max <period>"
+	if b, err := os.ReadFile(filepath.Join(root, "cpu.max")); err == nil {
+		fields := strings.Fields(string(b))
+		if len(fields) != 2 || fields[0] == "max" {
+			return 0, false
+		}
+
+		return quotaRatio(fields[0], fields[1])
+	}
+
+	// cgroup v1
+	quota, err := os.ReadFile(filepath.Join(root, "cpu", "cpu.cfs_quota_us"))
+	if err != nil {
+		return 0, false
+	}
+	period, err := os.ReadFile(filepath.Join(root, "cpu", "cpu.cfs_period_us"))
+	if err != nil {
+		return 0, false
+	}
+
+	return quotaRatio(strings.TrimSpace(string(quota)), strings.TrimSpace(string(period)))
+}
+
+func quotaRatio(quota, period string) (float64, bool) {
+	q, err := strconv.ParseFloat(quota, 64)
+	if err != nil || q <= 0 {
+		return 0, false
+	}
+
+	p, err := strconv.ParseFloat(period, 64)
+	if err != nil || p <= 0 {
+		return 0, false
+	}
+
+	return q / p, true
+}
+
+// MemoryMax returns the container memory limit in bytes, according to its
+// cgroup (v2 or v1).
+func MemoryMax(root string) (int64, bool) {
+	paths := []string{
+		filepath.Join(root, "memory.max"),
+		filepath.Join(root, "memory", "memory.limit_in_bytes"),
+	}
+
+	for _, path := range paths {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+
+		limit, err := strconv.ParseInt(strings.TrimSpace(string(b)), 10, 64)
+		if err != nil || limit <= 0 {
+			// "max" or invalid values mean no limit.
+			return 0, false
+		}
+
+		// cgroup v1 uses a huge value (page counter max) to express no limit.
+		if limit >= math.MaxInt64/2 {
+			return 0, false
+		}
+
+		return limit, true
+	}
+
+	return 0, false
+}
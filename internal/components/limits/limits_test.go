@@ -0,0 +1,95 @@
+package limits
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeCgroupFile(t *testing.T, root, name, content string) {
+	path := filepath.Join(root, name)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+}
+
+func TestCPUQuota(t *testing.T) {
+	t.Run("should read cgroup v2 quota", func(t *testing.T) {
+		root := t.TempDir()
+		writeCgroupFile(t, root, "cpu.max", "150000 100000\n")
+
+		quota, ok := CPUQuota(root)
+		assert.True(t, ok)
+		assert.Equal(t, 1.5, quota)
+	})
+
+	t.Run("should not have quota when unlimited", func(t *testing.T) {
+		root := t.TempDir()
+		writeCgroupFile(t, root, "cpu.max", "max 100000\n")
+
+		_, ok := CPUQuota(root)
+		assert.False(t, ok)
+	})
+
+	t.Run("should read cgroup v1 quota", func(t *testing.T) {
+		root := t.TempDir()
+		writeCgroupFile(t, root, "cpu/cpu.cfs_quota_us", "200000\n")
+		writeCgroupFile(t, root, "cpu/cpu.cfs_period_us", "100000\n")
+
+		quota, ok := CPUQuota(root)
+		assert.True(t, ok)
+		assert.Equal(t, 2.0, quota)
+	})
+
+	t.Run("should not have quota without cgroup files", func(t *testing.T) {
+		_, ok := CPUQuota(t.TempDir())
+		assert.False(t, ok)
+	})
+}
+
+func TestMemoryMax(t *testing.T) {
+	t.Run("should read cgroup v2 limit", func(t *testing.T) {
+		root := t.TempDir()
+		writeCgroupFile(t, root, "memory.max", "536870912\n")
+
+		limit, ok := MemoryMax(root)
+		assert.True(t, ok)
+		assert.Equal(t, int64(536870912), limit)
+	})
+
+	t.Run("should not have limit when unlimited", func(t *testing.T) {
+		root := t.TempDir()
+		writeCgroupFile(t, root, "memory.max", "max\n")
+
+		_, ok := MemoryMax(root)
+		assert.False(t, ok)
+
+		root = t.TempDir()
+		writeCgroupFile(t, root, "memory/memory.limit_in_bytes", "9223372036854771712\n")
+
+		_, ok = MemoryMax(root)
+		assert.False(t, ok)
+	})
+}
+
+func TestApply(t *testing.T) {
+	t.Run("should use definitions overrides", func(t *testing.T) {
+		root := t.TempDir()
+		writeCgroupFile(t, root, "cpu.max", "400000 100000\n")
+
+		result := Apply(Options{
+			MaxProcs:    1,
+			MemoryLimit: 1 << 30,
+			CgroupRoot:  root,
+		})
+
+		assert.Equal(t, Result{
+			MaxProcs:          1,
+			MaxProcsSource:    SourceDefinitions,
+			MemoryLimit:       1 << 30,
+			MemoryLimitSource: SourceDefinitions,
+		}, result)
+	})
+}
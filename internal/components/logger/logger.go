@@ -2,6 +2,7 @@ package logger
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"log/slog"
@@ -12,13 +13,16 @@ import (
 	"time"
 
 	logger_api "github.com/mikros-dev/mikros/apis/features/logger"
+	mcontext "github.com/mikros-dev/mikros/components/context"
+	"github.com/mikros-dev/mikros/components/logger"
 )
 
 const (
-	levelFatal    = slog.Level(12)
-	levelInternal = slog.Level(-2)
-	fatalExitCode = 1
-	loggerPkgHint = "/internal/components/logger"
+	levelFatal           = slog.Level(12)
+	levelInternal        = slog.Level(-2)
+	fatalExitCode        = 1
+	fatalShutdownTimeout = 2 * time.Second
+	loggerPkgHint        = "/internal/components/logger"
 )
 
 var (
@@ -65,6 +69,10 @@ type Logger struct {
 	errorLogger     *slog.Logger
 	level           *logLeveler
 	fieldExtractor  ContextFieldExtractor
+	requestID       RequestIDExtractor
+	requests        *requestBuffer
+	ring            *recordRing
+	otlp            *otlpExporter
 }
 
 // Options represents customizable settings for configuring logger behaviors
@@ -74,6 +82,13 @@ type Options struct {
 	DiscardMessages   bool
 	ErrorStackTrace   string
 	FixedAttributes   map[string]string
+	RecentRecords     int
+	OTLP              *OTLPOptions
+	RequestBuffer     int
+
+	// Output is where non-error messages are written. Defaults to the
+	// standard output.
+	Output io.Writer
 }
 
 // New creates a new Logger interface for applications.
@@ -106,27 +121,64 @@ func New(options Options) *Logger {
 				return a
 			},
 		}
-		l, e = createLoggers(options, opts)
+		ring  *recordRing
+		otlp  *otlpExporter
+		sinks []slog.Handler
 	)
 
+	if options.RecentRecords > 0 {
+		ring = newRecordRing(options.RecentRecords)
+		sinks = append(sinks, newRingHandler(ring, opts).WithAttrs(fixedAttributes(options)))
+	}
+	if options.OTLP != nil && !options.DiscardMessages {
+		otlp = newOTLPExporter(options.OTLP, options.FixedAttributes)
+		sinks = append(sinks, newOTLPHandler(otlp, level))
+	}
+	l, e := createLoggers(options, opts, sinks)
+
+	var requests *requestBuffer
+	if options.RequestBuffer > 0 {
+		requests = newRequestBuffer(options.RequestBuffer)
+	}
+
 	return &Logger{
 		errorStackTrace: ErrorStackTraceMode(options.ErrorStackTrace),
 		logger:          l,
 		errorLogger:     e,
 		level:           level,
+		ring:            ring,
+		otlp:            otlp,
+		requests:        requests,
 	}
 }
 
-func createLoggers(options Options, opts *slog.HandlerOptions) (*slog.Logger, *slog.Logger) {
-	// Adds custom fixed attributes into every log message.
+// fixedAttributes gives back the custom fixed attributes added into every
+// log message.
+func fixedAttributes(options Options) []slog.Attr {
 	var attrs []slog.Attr
 	for k, v := range options.FixedAttributes {
 		attrs = append(attrs, slog.String(k, v))
 	}
 
-	logHandler := slog.NewJSONHandler(os.Stdout, opts).WithAttrs(attrs)
+	return attrs
+}
+
+// createLoggers creates the service loggers. Records are also sent to every
+// sink handler.
+func createLoggers(options Options, opts *slog.HandlerOptions, sinks []slog.Handler) (*slog.Logger, *slog.Logger) {
+	attrs := fixedAttributes(options)
+
+	output := options.Output
+	if output == nil {
+		output = os.Stdout
+	}
+
+	logHandler := slog.NewJSONHandler(output, opts).WithAttrs(attrs)
 	if options.TextOutput {
-		logHandler = slog.NewTextHandler(os.Stdout, opts).WithAttrs(attrs)
+		logHandler = slog.NewTextHandler(output, opts).WithAttrs(attrs)
+	}
+	if len(sinks) > 0 {
+		logHandler = append(teeHandler{logHandler}, sinks...)
 	}
 
 	// Creates a specific log handler so every error message can have its source
@@ -136,6 +188,9 @@ func createLoggers(options Options, opts *slog.HandlerOptions) (*slog.Logger, *s
 	if options.TextOutput {
 		errHandler = slog.NewTextHandler(os.Stderr, opts).WithAttrs(attrs)
 	}
+	if len(sinks) > 0 {
+		errHandler = append(teeHandler{errHandler}, sinks...)
+	}
 
 	// Create our handlers
 	l := slog.New(logHandler)
@@ -152,18 +207,21 @@ func createLoggers(options Options, opts *slog.HandlerOptions) (*slog.Logger, *s
 // Debug outputs messages using debug level.
 func (l *Logger) Debug(ctx context.Context, msg string, attrs ...logger_api.Attribute) {
 	mFields := l.mergeFieldsWithCtx(ctx, attrs)
+	l.bufferRequestRecord(ctx, slog.LevelDebug, msg, mFields)
 	l.logger.Debug(msg, mFields...)
 }
 
 // Info outputs messages using the info level.
 func (l *Logger) Info(ctx context.Context, msg string, attrs ...logger_api.Attribute) {
 	mFields := l.mergeFieldsWithCtx(ctx, attrs)
+	l.bufferRequestRecord(ctx, slog.LevelInfo, msg, mFields)
 	l.logger.Info(msg, mFields...)
 }
 
 // Warn outputs messages using warning level.
 func (l *Logger) Warn(ctx context.Context, msg string, attrs ...logger_api.Attribute) {
 	mFields := l.mergeFieldsWithCtx(ctx, attrs)
+	l.bufferRequestRecord(ctx, slog.LevelWarn, msg, mFields)
 	l.logger.Warn(msg, mFields...)
 }
 
@@ -179,6 +237,7 @@ func (l *Logger) Error(ctx context.Context, msg string, attrs ...logger_api.Attr
 // Internal outputs messages using the custom internal level.
 func (l *Logger) Internal(ctx context.Context, msg string, attrs ...logger_api.Attribute) {
 	mFields := l.mergeFieldsWithCtx(ctx, attrs)
+	l.bufferRequestRecord(ctx, levelInternal, msg, mFields)
 	l.logger.Log(ctx, levelInternal, msg, mFields...)
 }
 
@@ -294,9 +353,21 @@ func lastSegment(fn string) string {
 
 // Fatal outputs message using fatal level.
 func (l *Logger) Fatal(ctx context.Context, msg string, attrs ...logger_api.Attribute) {
+	l.FatalWithExitCode(ctx, fatalExitCode, msg, attrs...)
+}
+
+// FatalWithExitCode outputs message using fatal level and exits the process
+// with code.
+func (l *Logger) FatalWithExitCode(ctx context.Context, code int, msg string, attrs ...logger_api.Attribute) {
 	mFields := l.mergeFieldsWithCtx(ctx, attrs)
 	l.logger.Log(ctx, levelFatal, msg, mFields...)
-	os.Exit(fatalExitCode)
+
+	// Gives exporters a chance to send the last records before exiting.
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), fatalShutdownTimeout)
+	_ = l.Shutdown(shutdownCtx)
+	cancel()
+
+	os.Exit(code)
 }
 
 func (l *Logger) mergeFieldsWithCtx(ctx context.Context, attrs []logger_api.Attribute) []any {
@@ -317,9 +388,19 @@ func (l *Logger) DisableDebugMessages() {
 	l.level.setLevel(slog.LevelInfo)
 }
 
-// appendServiceContext executes a custom field extractor from the current
-// context to add more fields into the message.
+// appendServiceContext adds the current handler information and executes a
+// custom field extractor from the current context to add more fields into the
+// message.
 func (l *Logger) appendServiceContext(ctx context.Context, attrs []logger_api.Attribute) []logger_api.Attribute {
+	if info, ok := mcontext.HandlerInfoFromContext(ctx); ok {
+		if info.Name != "" {
+			attrs = append(attrs, logger.String("handler", info.Name))
+		}
+		if info.Route != "" {
+			attrs = append(attrs, logger.String("route", info.Route))
+		}
+	}
+
 	if l.fieldExtractor != nil {
 		attrs = append(attrs, l.fieldExtractor(ctx)...)
 	}
@@ -329,27 +410,32 @@ func (l *Logger) appendServiceContext(ctx context.Context, attrs []logger_api.At
 
 // SetLogLevel changes the current messages log level.
 func (l *Logger) SetLogLevel(level string) (string, error) {
-	var newLevel slog.Level
+	newLevel, err := parseLevel(level)
+	if err != nil {
+		return "", err
+	}
+
+	l.level.setLevel(newLevel)
+	return level, nil
+}
 
+func parseLevel(level string) (slog.Level, error) {
 	switch strings.ToLower(level) {
 	case "debug":
-		newLevel = slog.LevelDebug
+		return slog.LevelDebug, nil
 	case "info":
-		newLevel = slog.LevelInfo
+		return slog.LevelInfo, nil
 	case "warn":
-		newLevel = slog.LevelWarn
+		return slog.LevelWarn, nil
 	case "error":
-		newLevel = slog.LevelError
+		return slog.LevelError, nil
 	case "fatal":
-		newLevel = levelFatal
+		return levelFatal, nil
 	case "internal":
-		newLevel = levelInternal
-	default:
-		return "", fmt.Errorf("unknown log level '%v'", level)
+		return levelInternal, nil
 	}
 
-	l.level.setLevel(newLevel)
-	return level, nil
+	return 0, fmt.Errorf("unknown log level '%v'", level)
 }
 
 // Level gets the current log level.
@@ -372,6 +458,134 @@ func (l *Logger) Level() string {
 	return "unknown"
 }
 
+// RecentRecords returns the last log records, as JSON objects, from the
+// oldest to the newest one. Records are kept regardless of the current log
+// level. It returns nil when Options.RecentRecords is not set.
+func (l *Logger) RecentRecords() []json.RawMessage {
+	if l.ring == nil {
+		return nil
+	}
+
+	return l.ring.Records()
+}
+
+// TailRecords returns the last n log records with at least the level, from
+// the oldest to the newest one. A non-positive n returns all records and an
+// empty level does not filter them.
+func (l *Logger) TailRecords(n int, level string) ([]json.RawMessage, error) {
+	minLevel := levelAll
+	if level != "" {
+		lvl, err := parseLevel(level)
+		if err != nil {
+			return nil, err
+		}
+
+		minLevel = lvl
+	}
+
+	var records []json.RawMessage
+	for _, record := range l.RecentRecords() {
+		if recordLevel(record) >= minLevel {
+			records = append(records, record)
+		}
+	}
+
+	if n > 0 && len(records) > n {
+		records = records[len(records)-n:]
+	}
+
+	return records, nil
+}
+
+// FlushRecentRecords writes into w, one per line, the kept log records that
+// were not output because of the current log level, so their context is not
+// lost when the service crashes. It returns how many records were written.
+func (l *Logger) FlushRecentRecords(w io.Writer) (int, error) {
+	var (
+		current = l.level.Level()
+		count   int
+	)
+
+	for _, record := range l.RecentRecords() {
+		if recordLevel(record) >= current {
+			continue
+		}
+
+		if _, err := w.Write(append(record, '\n')); err != nil {
+			return count, err
+		}
+
+		count++
+	}
+
+	return count, nil
+}
+
+// Shutdown sends the log records pending to be exported, if any, and stops
+// exporting new ones.
+func (l *Logger) Shutdown(ctx context.Context) error {
+	if l.otlp == nil {
+		return nil
+	}
+
+	return l.otlp.Shutdown(ctx)
+}
+
+// bufferRequestRecord keeps a message suppressed by the current log level
+// while its request is being handled, so it can be output if the request
+// fails.
+func (l *Logger) bufferRequestRecord(ctx context.Context, level slog.Level, msg string, fields []any) {
+	if l.requests == nil || l.requestID == nil || level >= l.level.Level() {
+		return
+	}
+
+	id, ok := l.requestID(ctx)
+	if !ok || id == "" {
+		return
+	}
+
+	r := slog.NewRecord(time.Now(), level, msg, 0)
+	r.Add(fields...)
+	l.requests.add(id, r)
+}
+
+// EndRequest finishes the request being handled with ctx. Its messages that
+// were suppressed by the log level are output when elevate is true, with the
+// "log.elevated" attribute, and discarded otherwise.
+func (l *Logger) EndRequest(ctx context.Context, elevate bool) {
+	if l.requests == nil || l.requestID == nil {
+		return
+	}
+
+	id, ok := l.requestID(ctx)
+	if !ok {
+		return
+	}
+
+	records := l.requests.remove(id)
+	if !elevate {
+		return
+	}
+
+	var (
+		handler     = l.logger.Handler()
+		elevatedCtx = withElevated(ctx)
+	)
+
+	for _, r := range records {
+		r.AddAttrs(slog.Bool("log.elevated", true))
+		if err := handler.Handle(elevatedCtx, r); err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "error logging elevated message: %v\n", err)
+		}
+	}
+}
+
+// SetRequestIDExtractor sets how the ID of the request being handled is
+// retrieved from contexts, enabling messages to be kept by request.
+func (l *Logger) SetRequestIDExtractor(extractor RequestIDExtractor) {
+	l.requestID = extractor
+}
+
 // SetContextFieldExtractor adds a custom function to extract values from the
 // context and add them into the log messages.
 func (l *Logger) SetContextFieldExtractor(extractor ContextFieldExtractor) {
@@ -0,0 +1,460 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultOTLPBatchSize     = 512
+	defaultOTLPFlushInterval = time.Second
+	defaultOTLPTimeout       = 10 * time.Second
+	defaultOTLPQueueSize     = 4096
+	otlpLogsPath             = "/v1/logs"
+	otlpScopeName            = "github.com/mikros-dev/mikros"
+	otlpTraceIDKey           = "trace_id"
+	otlpSpanIDKey            = "span_id"
+)
+
+// OTLPOptions configures the export of log records to an OpenTelemetry
+// collector, using the OTLP/HTTP protocol with JSON encoding.
+type OTLPOptions struct {
+	// Endpoint is the collector base URL, e.g. "http://collector:4318". The
+	// logs path "/v1/logs" is added when not present.
+	Endpoint string
+
+	// Headers are added into every export request, e.g. for authentication.
+	Headers map[string]string
+
+	// BatchSize is the maximum number of records sent by export request.
+	BatchSize int
+
+	// FlushInterval is the maximum time records wait to be exported.
+	FlushInterval time.Duration
+
+	// Timeout limits every export request.
+	Timeout time.Duration
+}
+
+// otlpExporter sends log records to an OTLP collector in batches. Records
+// are dropped when the collector cannot keep up with them, so logging never
+// blocks the service.
+type otlpExporter struct {
+	endpoint      string
+	headers       map[string]string
+	batchSize     int
+	flushInterval time.Duration
+	client        *http.Client
+	resource      []otlpKeyValue
+	queue         chan otlpLogRecord
+	stop          chan struct{}
+	done          chan struct{}
+	stopOnce      sync.Once
+	mu            sync.Mutex
+	dropped       int
+	lastErr       error
+}
+
+func newOTLPExporter(options *OTLPOptions, resource map[string]string) *otlpExporter {
+	e := &otlpExporter{
+		endpoint:      otlpLogsEndpoint(options.Endpoint),
+		headers:       options.Headers,
+		batchSize:     options.BatchSize,
+		flushInterval: options.FlushInterval,
+		client:        &http.Client{Timeout: options.Timeout},
+		queue:         make(chan otlpLogRecord, defaultOTLPQueueSize),
+		stop:          make(chan struct{}),
+		done:          make(chan struct{}),
+	}
+
+	if e.batchSize <= 0 {
+		e.batchSize = defaultOTLPBatchSize
+	}
+	if e.flushInterval <= 0 {
+		e.flushInterval = defaultOTLPFlushInterval
+	}
+	if e.client.Timeout <= 0 {
+		e.client.Timeout = defaultOTLPTimeout
+	}
+
+	for k, v := range resource {
+		e.resource = append(e.resource, otlpKeyValue{Key: k, Value: otlpAnyValue{StringValue: &v}})
+	}
+
+	go e.run()
+	return e
+}
+
+func otlpLogsEndpoint(endpoint string) string {
+	endpoint = strings.TrimSuffix(endpoint, "/")
+	if strings.HasSuffix(endpoint, otlpLogsPath) {
+		return endpoint
+	}
+
+	return endpoint + otlpLogsPath
+}
+
+// enqueue adds a record to be exported.
+func (e *otlpExporter) enqueue(record otlpLogRecord) {
+	select {
+	case <-e.stop:
+		return
+	default:
+	}
+
+	select {
+	case e.queue <- record:
+	default:
+		e.mu.Lock()
+		e.dropped++
+		e.mu.Unlock()
+	}
+}
+
+func (e *otlpExporter) run() {
+	defer close(e.done)
+
+	var (
+		ticker = time.NewTicker(e.flushInterval)
+		batch  []otlpLogRecord
+	)
+	defer ticker.Stop()
+
+	send := func() {
+		if len(batch) > 0 {
+			e.export(batch)
+			batch = nil
+		}
+	}
+
+	drain := func() {
+		for {
+			select {
+			case record := <-e.queue:
+				batch = append(batch, record)
+				if len(batch) >= e.batchSize {
+					send()
+				}
+			default:
+				send()
+				return
+			}
+		}
+	}
+
+	for {
+		select {
+		case record := <-e.queue:
+			batch = append(batch, record)
+			if len(batch) >= e.batchSize {
+				send()
+			}
+
+		case <-ticker.C:
+			send()
+
+		case <-e.stop:
+			drain()
+			return
+		}
+	}
+}
+
+// Shutdown exports the queued records and stops the exporter.
+func (e *otlpExporter) Shutdown(ctx context.Context) error {
+	e.stopOnce.Do(func() {
+		close(e.stop)
+	})
+
+	select {
+	case <-e.done:
+		return e.err()
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (e *otlpExporter) err() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	err := e.lastErr
+	if e.dropped > 0 {
+		err = errors.Join(err, fmt.Errorf("%d log records were dropped", e.dropped))
+	}
+
+	return err
+}
+
+func (e *otlpExporter) export(records []otlpLogRecord) {
+	b, err := json.Marshal(otlpLogsRequest{
+		ResourceLogs: []otlpResourceLogs{
+			{
+				Resource: otlpResource{Attributes: e.resource},
+				ScopeLogs: []otlpScopeLogs{
+					{
+						Scope:      otlpScope{Name: otlpScopeName},
+						LogRecords: records,
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		e.setErr(err)
+		return
+	}
+
+	req, err := http.NewRequest(http.MethodPost, e.endpoint, bytes.NewReader(b))
+	if err != nil {
+		e.setErr(err)
+		return
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range e.headers {
+		req.Header.Set(k, v)
+	}
+
+	res, err := e.client.Do(req)
+	if err != nil {
+		e.setErr(err)
+		return
+	}
+	_ = res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		e.setErr(fmt.Errorf("OTLP collector answered with status %d", res.StatusCode))
+	}
+}
+
+func (e *otlpExporter) setErr(err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.lastErr = err
+}
+
+// otlpHandler is a slog.Handler that converts records into OTLP log records.
+// Attributes named trace_id and span_id, usually added by a LoggerExtractor
+// integration, correlate records with their traces. Attributes inside groups
+// have their keys prefixed with the group names, e.g. "group.key".
+type otlpHandler struct {
+	exporter *otlpExporter
+	level    slog.Leveler
+	attrs    []otlpKeyValue
+	prefix   string
+	traceID  string
+	spanID   string
+}
+
+func newOTLPHandler(exporter *otlpExporter, level slog.Leveler) slog.Handler {
+	return &otlpHandler{
+		exporter: exporter,
+		level:    level,
+	}
+}
+
+func (h *otlpHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level.Level()
+}
+
+func (h *otlpHandler) Handle(_ context.Context, record slog.Record) error {
+	var (
+		message = record.Message
+		out     = otlpLogRecord{
+			TimeUnixNano:         strconv.FormatInt(record.Time.UnixNano(), 10),
+			ObservedTimeUnixNano: strconv.FormatInt(time.Now().UnixNano(), 10),
+			SeverityNumber:       otlpSeverityNumber(record.Level),
+			SeverityText:         otlpSeverityText(record.Level),
+			Body:                 otlpAnyValue{StringValue: &message},
+			Attributes:           append([]otlpKeyValue(nil), h.attrs...),
+			TraceID:              h.traceID,
+			SpanID:               h.spanID,
+		}
+	)
+
+	record.Attrs(func(a slog.Attr) bool {
+		out.Attributes = h.appendAttr(out.Attributes, &out.TraceID, &out.SpanID, a)
+		return true
+	})
+
+	h.exporter.enqueue(out)
+	return nil
+}
+
+// appendAttr appends a to attrs unless it is the trace or span ID of the
+// record.
+func (h *otlpHandler) appendAttr(attrs []otlpKeyValue, traceID, spanID *string, a slog.Attr) []otlpKeyValue {
+	if a.Equal(slog.Attr{}) {
+		return attrs
+	}
+
+	if h.prefix == "" {
+		switch a.Key {
+		case otlpTraceIDKey:
+			if id := otlpID(a.Value, 16); id != "" {
+				*traceID = id
+				return attrs
+			}
+		case otlpSpanIDKey:
+			if id := otlpID(a.Value, 8); id != "" {
+				*spanID = id
+				return attrs
+			}
+		}
+	}
+
+	return append(attrs, otlpKeyValue{Key: h.prefix + a.Key, Value: otlpValue(a.Value)})
+}
+
+func (h *otlpHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	n := *h
+	n.attrs = append([]otlpKeyValue(nil), h.attrs...)
+	for _, a := range attrs {
+		n.attrs = n.appendAttr(n.attrs, &n.traceID, &n.spanID, a)
+	}
+
+	return &n
+}
+
+func (h *otlpHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	n := *h
+	n.prefix = h.prefix + name + "."
+	return &n
+}
+
+// otlpSeverityNumber maps slog levels into OTLP severity numbers, which
+// have the same distance between levels, i.e., INFO is 9 and DEBUG is 5.
+func otlpSeverityNumber(level slog.Level) int {
+	return min(max(int(level)+9, 1), 24)
+}
+
+func otlpSeverityText(level slog.Level) string {
+	if name, ok := levelNames[level]; ok {
+		return name
+	}
+
+	return level.String()
+}
+
+// otlpID gives back an attribute value as a trace or span ID, which must be
+// hexadecimal strings of size bytes.
+func otlpID(v slog.Value, size int) string {
+	id := strings.ToLower(v.Resolve().String())
+	if b, err := hex.DecodeString(id); err != nil || len(b) != size {
+		return ""
+	}
+
+	return id
+}
+
+func otlpValue(v slog.Value) otlpAnyValue {
+	v = v.Resolve()
+
+	switch v.Kind() {
+	case slog.KindString:
+		s := v.String()
+		return otlpAnyValue{StringValue: &s}
+	case slog.KindInt64:
+		s := strconv.FormatInt(v.Int64(), 10)
+		return otlpAnyValue{IntValue: &s}
+	case slog.KindUint64:
+		s := strconv.FormatUint(v.Uint64(), 10)
+		return otlpAnyValue{IntValue: &s}
+	case slog.KindFloat64:
+		f := v.Float64()
+		return otlpAnyValue{DoubleValue: &f}
+	case slog.KindBool:
+		b := v.Bool()
+		return otlpAnyValue{BoolValue: &b}
+	case slog.KindTime:
+		s := v.Time().Format(time.RFC3339Nano)
+		return otlpAnyValue{StringValue: &s}
+	case slog.KindGroup:
+		var values []otlpKeyValue
+		for _, a := range v.Group() {
+			if !a.Equal(slog.Attr{}) {
+				values = append(values, otlpKeyValue{Key: a.Key, Value: otlpValue(a.Value)})
+			}
+		}
+
+		return otlpAnyValue{KvlistValue: &otlpKeyValueList{Values: values}}
+	}
+
+	var s string
+	if err, ok := v.Any().(error); ok {
+		s = err.Error()
+	} else {
+		s = v.String()
+	}
+
+	return otlpAnyValue{StringValue: &s}
+}
+
+// OTLP/HTTP JSON payload types.
+type (
+	otlpLogsRequest struct {
+		ResourceLogs []otlpResourceLogs `json:"resourceLogs"`
+	}
+
+	otlpResourceLogs struct {
+		Resource  otlpResource    `json:"resource"`
+		ScopeLogs []otlpScopeLogs `json:"scopeLogs"`
+	}
+
+	otlpResource struct {
+		Attributes []otlpKeyValue `json:"attributes,omitempty"`
+	}
+
+	otlpScopeLogs struct {
+		Scope      otlpScope       `json:"scope"`
+		LogRecords []otlpLogRecord `json:"logRecords"`
+	}
+
+	otlpScope struct {
+		Name string `json:"name"`
+	}
+
+	otlpLogRecord struct {
+		TimeUnixNano         string         `json:"timeUnixNano"`
+		ObservedTimeUnixNano string         `json:"observedTimeUnixNano"`
+		SeverityNumber       int            `json:"severityNumber"`
+		SeverityText         string         `json:"severityText"`
+		Body                 otlpAnyValue   `json:"body"`
+		Attributes           []otlpKeyValue `json:"attributes,omitempty"`
+		TraceID              string         `json:"traceId,omitempty"`
+		SpanID               string         `json:"spanId,omitempty"`
+	}
+
+	otlpKeyValue struct {
+		Key   string       `json:"key"`
+		Value otlpAnyValue `json:"value"`
+	}
+
+	otlpKeyValueList struct {
+		Values []otlpKeyValue `json:"values"`
+	}
+
+	otlpAnyValue struct {
+		StringValue *string           `json:"stringValue,omitempty"`
+		BoolValue   *bool             `json:"boolValue,omitempty"`
+		IntValue    *string           `json:"intValue,omitempty"`
+		DoubleValue *float64          `json:"doubleValue,omitempty"`
+		KvlistValue *otlpKeyValueList `json:"kvlistValue,omitempty"`
+	}
+)
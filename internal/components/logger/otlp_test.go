@@ -0,0 +1,125 @@
+package logger
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mikros-dev/mikros/components/logger"
+)
+
+type otlpCollector struct {
+	mu       sync.Mutex
+	requests []otlpLogsRequest
+	headers  []http.Header
+}
+
+func (c *otlpCollector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	b, _ := io.ReadAll(r.Body)
+
+	var req otlpLogsRequest
+	_ = json.Unmarshal(b, &req)
+
+	c.mu.Lock()
+	c.requests = append(c.requests, req)
+	c.headers = append(c.headers, r.Header.Clone())
+	c.mu.Unlock()
+}
+
+func (c *otlpCollector) records() []otlpLogRecord {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var records []otlpLogRecord
+	for _, r := range c.requests {
+		for _, rl := range r.ResourceLogs {
+			for _, sl := range rl.ScopeLogs {
+				records = append(records, sl.LogRecords...)
+			}
+		}
+	}
+
+	return records
+}
+
+func TestOTLPExport(t *testing.T) {
+	t.Run("should export log records to the collector", func(t *testing.T) {
+		var (
+			collector = &otlpCollector{}
+			server    = httptest.NewServer(collector)
+			ctx       = context.Background()
+		)
+		defer server.Close()
+
+		l := New(Options{
+			FixedAttributes: map[string]string{"service.name": "users"},
+			OTLP: &OTLPOptions{
+				Endpoint: server.URL,
+				Headers:  map[string]string{"Authorization": "Bearer token"},
+			},
+		})
+
+		l.Info(ctx, "user created",
+			logger.String("trace_id", "4BF92F3577B34DA6A3CE929D0E0E4736"),
+			logger.String("span_id", "00f067aa0ba902b7"),
+			logger.Int32("user.age", 42),
+		)
+		l.Debug(ctx, "suppressed")
+		l.Error(ctx, "user not found", logger.String("trace_id", "invalid"))
+		require.NoError(t, l.Shutdown(ctx))
+
+		records := collector.records()
+		require.Len(t, records, 2)
+
+		assert.Equal(t, "user created", *records[0].Body.StringValue)
+		assert.Equal(t, 9, records[0].SeverityNumber)
+		assert.Equal(t, "INFO", records[0].SeverityText)
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", records[0].TraceID)
+		assert.Equal(t, "00f067aa0ba902b7", records[0].SpanID)
+		age := otlpAttributeValue(records[0].Attributes, "user.age")
+		require.NotNil(t, age)
+		assert.Equal(t, "42", *age.IntValue)
+
+		assert.Equal(t, 17, records[1].SeverityNumber)
+		assert.Empty(t, records[1].TraceID)
+		assert.Equal(t, "invalid", *otlpAttributeValue(records[1].Attributes, "trace_id").StringValue)
+
+		assert.Equal(t, "Bearer token", collector.headers[0].Get("Authorization"))
+		assert.Equal(t, "users", *otlpAttributeValue(collector.requests[0].ResourceLogs[0].Resource.Attributes, "service.name").StringValue)
+	})
+}
+
+func otlpAttributeValue(attrs []otlpKeyValue, key string) *otlpAnyValue {
+	for _, a := range attrs {
+		if a.Key == key {
+			return &a.Value
+		}
+	}
+
+	return nil
+}
+
+func TestOTLPHandler(t *testing.T) {
+	t.Run("should prefix attributes inside groups", func(t *testing.T) {
+		exporter := &otlpExporter{queue: make(chan otlpLogRecord, 1), stop: make(chan struct{})}
+		h := newOTLPHandler(exporter, slog.LevelInfo).WithAttrs([]slog.Attr{slog.String("a", "1")}).WithGroup("req")
+
+		slog.New(h).With("b", "2").Warn("done", "c", 3)
+
+		record := <-exporter.queue
+		assert.Equal(t, 13, record.SeverityNumber)
+		assert.Equal(t, []string{"a", "req.b", "req.c"}, []string{
+			record.Attributes[0].Key,
+			record.Attributes[1].Key,
+			record.Attributes[2].Key,
+		})
+	})
+}
@@ -0,0 +1,96 @@
+package logger
+
+import (
+	"container/list"
+	"context"
+	"log/slog"
+	"sync"
+)
+
+const (
+	defaultMaxBufferedRequests = 1024
+)
+
+// RequestIDExtractor retrieves the ID of the request being handled, usually
+// its tracker ID, from a context.
+type RequestIDExtractor func(ctx context.Context) (string, bool)
+
+// requestBuffer keeps, for every request, the records suppressed by the
+// current log level. The oldest requests are evicted when there are too many
+// of them, so requests that never end do not leak.
+type requestBuffer struct {
+	mu          sync.Mutex
+	maxRecords  int
+	maxRequests int
+	requests    map[string]*list.Element
+	order       *list.List
+}
+
+type bufferedRequest struct {
+	id      string
+	records []slog.Record
+}
+
+func newRequestBuffer(maxRecords int) *requestBuffer {
+	return &requestBuffer{
+		maxRecords:  maxRecords,
+		maxRequests: defaultMaxBufferedRequests,
+		requests:    make(map[string]*list.Element),
+		order:       list.New(),
+	}
+}
+
+// add keeps a record of the id request. Once the request has the maximum
+// number of records, the oldest ones are discarded.
+func (b *requestBuffer) add(id string, record slog.Record) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	el, ok := b.requests[id]
+	if !ok {
+		if b.order.Len() >= b.maxRequests {
+			oldest := b.order.Front()
+			b.order.Remove(oldest)
+			delete(b.requests, oldest.Value.(*bufferedRequest).id)
+		}
+
+		el = b.order.PushBack(&bufferedRequest{id: id})
+		b.requests[id] = el
+	}
+
+	req := el.Value.(*bufferedRequest)
+	if len(req.records) >= b.maxRecords {
+		req.records = req.records[1:]
+	}
+
+	req.records = append(req.records, record)
+}
+
+// remove gives back the records of the id request and stops keeping them.
+func (b *requestBuffer) remove(id string) []slog.Record {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	el, ok := b.requests[id]
+	if !ok {
+		return nil
+	}
+
+	b.order.Remove(el)
+	delete(b.requests, id)
+
+	return el.Value.(*bufferedRequest).records
+}
+
+// elevatedKey marks contexts used to output records that were suppressed by
+// the log level when they were created.
+type elevatedKey struct{}
+
+func withElevated(ctx context.Context) context.Context {
+	return context.WithValue(ctx, elevatedKey{}, true)
+}
+
+func isElevated(ctx context.Context) bool {
+	elevated, _ := ctx.Value(elevatedKey{}).(bool)
+	return elevated
+}
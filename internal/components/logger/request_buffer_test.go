@@ -0,0 +1,96 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingHandler struct {
+	level   slog.Level
+	records []slog.Record
+}
+
+func (h *recordingHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level
+}
+
+func (h *recordingHandler) Handle(_ context.Context, r slog.Record) error {
+	h.records = append(h.records, r)
+	return nil
+}
+
+func (h *recordingHandler) WithAttrs(_ []slog.Attr) slog.Handler { return h }
+func (h *recordingHandler) WithGroup(_ string) slog.Handler      { return h }
+
+func TestRequestBuffer(t *testing.T) {
+	newRecord := func(msg string) slog.Record {
+		return slog.NewRecord(time.Now(), slog.LevelDebug, msg, 0)
+	}
+
+	t.Run("should keep the last records of every request", func(t *testing.T) {
+		b := newRequestBuffer(2)
+		b.add("a", newRecord("1"))
+		b.add("a", newRecord("2"))
+		b.add("a", newRecord("3"))
+		b.add("b", newRecord("4"))
+
+		records := b.remove("a")
+		assert.Len(t, records, 2)
+		assert.Equal(t, "2", records[0].Message)
+		assert.Equal(t, "3", records[1].Message)
+		assert.Nil(t, b.remove("a"))
+		assert.Len(t, b.remove("b"), 1)
+	})
+
+	t.Run("should evict the oldest requests", func(t *testing.T) {
+		b := newRequestBuffer(1)
+		b.maxRequests = 2
+		b.add("a", newRecord("1"))
+		b.add("b", newRecord("2"))
+		b.add("c", newRecord("3"))
+
+		assert.Nil(t, b.remove("a"))
+		assert.Len(t, b.remove("b"), 1)
+		assert.Len(t, b.remove("c"), 1)
+	})
+}
+
+func TestLoggerEndRequest(t *testing.T) {
+	var (
+		ctx    = context.Background()
+		output = &recordingHandler{level: slog.LevelInfo}
+		ring   = &recordingHandler{level: levelAll}
+		level  = newLogLeveler(slog.LevelInfo)
+		l      = &Logger{
+			logger:   slog.New(teeHandler{output, ring}),
+			level:    level,
+			requests: newRequestBuffer(10),
+			requestID: func(_ context.Context) (string, bool) {
+				return "req-1", true
+			},
+		}
+	)
+
+	t.Run("should discard the suppressed records of successful requests", func(t *testing.T) {
+		l.Debug(ctx, "debug")
+		l.EndRequest(ctx, false)
+
+		assert.Empty(t, output.records)
+		assert.Len(t, ring.records, 1)
+	})
+
+	t.Run("should output the suppressed records of failed requests", func(t *testing.T) {
+		l.Debug(ctx, "debug")
+		l.Info(ctx, "info")
+		l.EndRequest(ctx, true)
+
+		assert.Len(t, output.records, 2)
+		assert.Equal(t, "info", output.records[0].Message)
+		assert.Equal(t, "debug", output.records[1].Message)
+		assert.Len(t, ring.records, 3)
+	})
+}
@@ -0,0 +1,150 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"math"
+	"strings"
+	"sync"
+)
+
+const (
+	// levelAll is below every log level, so the ring keeps all records.
+	levelAll = slog.Level(math.MinInt)
+)
+
+// recordRing keeps the last log records written to it. The JSON handler
+// writes each record with a single call, so every write is a record.
+type recordRing struct {
+	mu      sync.Mutex
+	records [][]byte
+	next    int
+	full    bool
+}
+
+func newRecordRing(size int) *recordRing {
+	return &recordRing{
+		records: make([][]byte, size),
+	}
+}
+
+func (r *recordRing) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.records[r.next] = bytes.TrimSpace(append([]byte(nil), p...))
+	r.next = (r.next + 1) % len(r.records)
+	if r.next == 0 {
+		r.full = true
+	}
+
+	return len(p), nil
+}
+
+// Records returns the kept records, from the oldest to the newest one.
+func (r *recordRing) Records() []json.RawMessage {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var (
+		records []json.RawMessage
+		start   = 0
+		count   = r.next
+	)
+
+	if r.full {
+		start = r.next
+		count = len(r.records)
+	}
+
+	for i := 0; i < count; i++ {
+		records = append(records, r.records[(start+i)%len(r.records)])
+	}
+
+	return records
+}
+
+// newRingHandler creates the handler that writes records into the ring,
+// ignoring the current log level.
+func newRingHandler(ring *recordRing, opts *slog.HandlerOptions) slog.Handler {
+	ringOpts := *opts
+	ringOpts.Level = levelAll
+
+	return slog.NewJSONHandler(ring, &ringOpts)
+}
+
+// recordLevel gives back the level of a record written into the ring.
+func recordLevel(record json.RawMessage) slog.Level {
+	var r struct {
+		Level string `json:"level"`
+	}
+	if err := json.Unmarshal(record, &r); err != nil {
+		return levelAll
+	}
+
+	for leveler, name := range levelNames {
+		if name == r.Level {
+			return leveler.Level()
+		}
+	}
+
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(strings.ToUpper(r.Level))); err != nil {
+		return levelAll
+	}
+
+	return level
+}
+
+// teeHandler sends records to all its handlers.
+type teeHandler []slog.Handler
+
+func (t teeHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, h := range t {
+		if h.Enabled(ctx, level) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (t teeHandler) Handle(ctx context.Context, record slog.Record) error {
+	var (
+		errs     []error
+		elevated = isElevated(ctx)
+	)
+
+	for _, h := range t {
+		// Elevated records were already sent to the handlers enabled for
+		// their level, so only the remaining ones receive them.
+		if h.Enabled(ctx, record.Level) != elevated {
+			if err := h.Handle(ctx, record.Clone()); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (t teeHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	handlers := make(teeHandler, len(t))
+	for i, h := range t {
+		handlers[i] = h.WithAttrs(attrs)
+	}
+
+	return handlers
+}
+
+func (t teeHandler) WithGroup(name string) slog.Handler {
+	handlers := make(teeHandler, len(t))
+	for i, h := range t {
+		handlers[i] = h.WithGroup(name)
+	}
+
+	return handlers
+}
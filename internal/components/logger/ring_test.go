@@ -0,0 +1,117 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func recordMessages(t *testing.T, records []json.RawMessage) []string {
+	t.Helper()
+
+	var messages []string
+	for _, record := range records {
+		var r struct {
+			Msg string `json:"msg"`
+		}
+		require.NoError(t, json.Unmarshal(record, &r))
+		messages = append(messages, r.Msg)
+	}
+
+	return messages
+}
+
+func TestRecordRing(t *testing.T) {
+	t.Run("should keep records from the oldest to the newest one", func(t *testing.T) {
+		r := newRecordRing(3)
+		_, _ = r.Write([]byte(`{"msg":"1"}` + "\n"))
+		_, _ = r.Write([]byte(`{"msg":"2"}` + "\n"))
+
+		assert.Equal(t, []string{"1", "2"}, recordMessages(t, r.Records()))
+	})
+
+	t.Run("should drop the oldest records when full", func(t *testing.T) {
+		r := newRecordRing(3)
+		for _, msg := range []string{"1", "2", "3", "4", "5"} {
+			_, _ = r.Write([]byte(`{"msg":"` + msg + `"}` + "\n"))
+		}
+
+		assert.Equal(t, []string{"3", "4", "5"}, recordMessages(t, r.Records()))
+	})
+
+	t.Run("should give back the record levels", func(t *testing.T) {
+		assert.Equal(t, slog.LevelDebug, recordLevel(json.RawMessage(`{"level":"DEBUG"}`)))
+		assert.Equal(t, slog.LevelWarn, recordLevel(json.RawMessage(`{"level":"warn"}`)))
+		assert.Equal(t, levelAll, recordLevel(json.RawMessage(`{"level":"unknown"}`)))
+		assert.Equal(t, levelAll, recordLevel(json.RawMessage(`invalid`)))
+	})
+}
+
+func TestLoggerRecentRecords(t *testing.T) {
+	var (
+		ctx = context.Background()
+		l   = New(Options{RecentRecords: 4, Output: io.Discard})
+	)
+
+	l.Debug(ctx, "debug")
+	l.Info(ctx, "info")
+	l.Warn(ctx, "warn")
+	l.Debug(ctx, "debug again")
+	l.Info(ctx, "info again")
+
+	t.Run("should keep records suppressed by the log level", func(t *testing.T) {
+		assert.Equal(t, []string{"info", "warn", "debug again", "info again"}, recordMessages(t, l.RecentRecords()))
+	})
+
+	t.Run("should filter records by their level", func(t *testing.T) {
+		records, err := l.TailRecords(0, "info")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"info", "warn", "info again"}, recordMessages(t, records))
+
+		records, err = l.TailRecords(0, "warn")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"warn"}, recordMessages(t, records))
+	})
+
+	t.Run("should limit the number of records", func(t *testing.T) {
+		records, err := l.TailRecords(2, "")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"debug again", "info again"}, recordMessages(t, records))
+
+		records, err = l.TailRecords(2, "info")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"warn", "info again"}, recordMessages(t, records))
+	})
+
+	t.Run("should fail with unknown levels", func(t *testing.T) {
+		_, err := l.TailRecords(0, "verbose")
+		assert.Error(t, err)
+	})
+
+	t.Run("should flush only the suppressed records", func(t *testing.T) {
+		var buf bytes.Buffer
+		count, err := l.FlushRecentRecords(&buf)
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		require.Len(t, lines, 1)
+		assert.Contains(t, lines[0], `"msg":"debug again"`)
+	})
+
+	t.Run("should return nothing without kept records", func(t *testing.T) {
+		l := New(Options{Output: io.Discard})
+		assert.Nil(t, l.RecentRecords())
+
+		count, err := l.FlushRecentRecords(io.Discard)
+		require.NoError(t, err)
+		assert.Zero(t, count)
+	})
+}
@@ -0,0 +1,214 @@
+// Package ports validates the ports that runtimes are about to listen to,
+// reporting conflicts and permission problems with actionable errors before
+// any of them is bound.
+package ports
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/mikros-dev/mikros/components/abort"
+)
+
+const (
+	defaultProcRoot       = "/proc"
+	defaultPrivilegedPort = 1024
+	maxPort               = 65535
+
+	// capNetBindService is the capability bit that allows binding
+	// privileged ports.
+	capNetBindService = 10
+)
+
+// geteuid is replaced by tests.
+var geteuid = os.Geteuid
+
+// Port is a port that a runtime is configured to listen to, on the
+// interface of Host, over Network. Empty Host and Network mean all
+// interfaces, over both IPv4 and IPv6.
+type Port struct {
+	Runtime string
+	Port    int32
+	Host    string
+	Network string
+}
+
+// Options configures the checks.
+type Options struct {
+	// SkipAvailability disables checking if the ports are free, which must
+	// be done when their listeners are inherited from another process.
+	SkipAvailability bool
+
+	// ProcRoot is where the proc filesystem is mounted, used to check if
+	// the process can bind privileged ports. Defaults to /proc. The check
+	// is skipped when it is not available.
+	ProcRoot string
+}
+
+// Check validates ports, returning all problems found. Problems with the
+// configuration are annotated with abort.ReasonConfig, while ports used by
+// other processes keep the abort.ReasonPortBusy of their listen errors.
+// Zero ports, chosen by the system, are ignored.
+func Check(ports []Port, options Options) error {
+	if options.ProcRoot == "" {
+		options.ProcRoot = defaultProcRoot
+	}
+
+	var (
+		errs []error
+		seen []Port
+	)
+
+	for _, p := range ports {
+		if p.Port == 0 {
+			continue
+		}
+		if p.Port < 0 || p.Port > maxPort {
+			errs = append(errs, abort.Wrap(abort.ReasonConfig, fmt.Errorf(
+				"runtime '%s' port %d is out of range, it must be between 1 and %d", p.Runtime, p.Port, maxPort,
+			)))
+			continue
+		}
+
+		if other, ok := findConflict(seen, p); ok {
+			errs = append(errs, abort.Wrap(abort.ReasonConfig, fmt.Errorf(
+				"runtimes '%s' and '%s' are both configured to listen to port %d, each one must use its own port "+
+					"or interface",
+				other.Runtime, p.Runtime, p.Port,
+			)))
+			continue
+		}
+		seen = append(seen, p)
+
+		if isPrivileged(p.Port, options) && !canBindPrivileged(options) {
+			errs = append(errs, abort.Wrap(abort.ReasonConfig, fmt.Errorf(
+				"runtime '%s' port %d is privileged and the service is neither running as root nor has the "+
+					"CAP_NET_BIND_SERVICE capability, use a port from %d or grant the capability",
+				p.Runtime, p.Port, privilegedPortEnd(options),
+			)))
+			continue
+		}
+
+		if !options.SkipAvailability {
+			if err := checkAvailable(p); err != nil {
+				errs = append(errs, fmt.Errorf(
+					"runtime '%s' port %d is already in use by another process: %w", p.Runtime, p.Port, err,
+				))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// findConflict returns the port of ports that p can't be bound with, which
+// is one with the same number, over an overlapping network and on the same
+// interface, all of them being the wildcard one.
+func findConflict(ports []Port, p Port) (Port, bool) {
+	for _, other := range ports {
+		if other.Port == p.Port && networksOverlap(other.Network, p.Network) && hostsOverlap(other.Host, p.Host) {
+			return other, true
+		}
+	}
+
+	return Port{}, false
+}
+
+func networksOverlap(a, b string) bool {
+	a, b = tcpNetwork(a), tcpNetwork(b)
+	return a == b || a == "tcp" || b == "tcp"
+}
+
+func tcpNetwork(network string) string {
+	if network == "" {
+		return "tcp"
+	}
+
+	return network
+}
+
+func hostsOverlap(a, b string) bool {
+	return isWildcardHost(a) || isWildcardHost(b) || a == b
+}
+
+func isWildcardHost(host string) bool {
+	if host == "" {
+		return true
+	}
+
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsUnspecified()
+}
+
+func checkAvailable(p Port) error {
+	network := p.Network
+	if network == "" {
+		network = "tcp"
+	}
+
+	l, err := net.Listen(network, net.JoinHostPort(p.Host, strconv.Itoa(int(p.Port))))
+	if err != nil {
+		return err
+	}
+
+	return l.Close()
+}
+
+// isPrivileged tells if port can only be bound by privileged processes. It
+// is always false when the proc filesystem is not available, e.g. on
+// systems other than Linux.
+func isPrivileged(port int32, options Options) bool {
+	if _, err := os.Stat(filepath.Join(options.ProcRoot, "self", "status")); err != nil {
+		return false
+	}
+
+	return int(port) < privilegedPortEnd(options)
+}
+
+// privilegedPortEnd gives back the first unprivileged port, which can be
+// changed by the net.ipv4.ip_unprivileged_port_start kernel setting.
+func privilegedPortEnd(options Options) int {
+	b, err := os.ReadFile(filepath.Join(options.ProcRoot, "sys", "net", "ipv4", "ip_unprivileged_port_start"))
+	if err != nil {
+		return defaultPrivilegedPort
+	}
+
+	n, err := strconv.Atoi(strings.TrimSpace(string(b)))
+	if err != nil {
+		return defaultPrivilegedPort
+	}
+
+	return n
+}
+
+func canBindPrivileged(options Options) bool {
+	if geteuid() == 0 {
+		return true
+	}
+
+	b, err := os.ReadFile(filepath.Join(options.ProcRoot, "self", "status"))
+	if err != nil {
+		return true
+	}
+
+	for _, line := range strings.Split(string(b), "\n") {
+		value, ok := strings.CutPrefix(line, "CapEff:")
+		if !ok {
+			continue
+		}
+
+		caps, err := strconv.ParseUint(strings.TrimSpace(value), 16, 64)
+		if err != nil {
+			return true
+		}
+
+		return caps&(1<<capNetBindService) != 0
+	}
+
+	return true
+}
@@ -0,0 +1,143 @@
+package ports
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mikros-dev/mikros/components/abort"
+)
+
+func writeProcFile(t *testing.T, root, name, content string) {
+	path := filepath.Join(root, name)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+}
+
+func TestCheck(t *testing.T) {
+	noProc := Options{ProcRoot: filepath.Join(t.TempDir(), "missing")}
+
+	t.Run("should accept free and system chosen ports", func(t *testing.T) {
+		err := Check([]Port{{Runtime: "grpc", Port: 0}, {Runtime: "http", Port: 0}}, noProc)
+		assert.NoError(t, err)
+	})
+
+	t.Run("should reject ports out of range", func(t *testing.T) {
+		err := Check([]Port{{Runtime: "http", Port: 70000}}, noProc)
+		assert.ErrorContains(t, err, "runtime 'http' port 70000 is out of range")
+		assert.Equal(t, abort.ReasonConfig, abort.ReasonOf(err))
+	})
+
+	t.Run("should reject ports shared by runtimes", func(t *testing.T) {
+		err := Check([]Port{{Runtime: "grpc", Port: 8080}, {Runtime: "http", Port: 8080}}, Options{
+			ProcRoot:         noProc.ProcRoot,
+			SkipAvailability: true,
+		})
+		assert.EqualError(t, err, "runtimes 'grpc' and 'http' are both configured to listen to port 8080, each one must use its own port or interface")
+	})
+
+	t.Run("should only reject ports shared on the same interface", func(t *testing.T) {
+		options := Options{ProcRoot: noProc.ProcRoot, SkipAvailability: true}
+
+		assert.NoError(t, Check([]Port{
+			{Runtime: "grpc", Port: 8080, Host: "127.0.0.1"},
+			{Runtime: "http", Port: 8080, Host: "10.0.0.1"},
+		}, options))
+		assert.NoError(t, Check([]Port{
+			{Runtime: "grpc", Port: 8080, Network: "tcp4"},
+			{Runtime: "http", Port: 8080, Network: "tcp6"},
+		}, options))
+
+		tests := []struct {
+			name  string
+			ports []Port
+		}{
+			{
+				name: "all interfaces",
+				ports: []Port{
+					{Runtime: "grpc", Port: 8080},
+					{Runtime: "http", Port: 8080, Host: "127.0.0.1"},
+				},
+			},
+			{
+				name: "unspecified address",
+				ports: []Port{
+					{Runtime: "grpc", Port: 8080, Host: "127.0.0.1"},
+					{Runtime: "http", Port: 8080, Host: "0.0.0.0"},
+				},
+			},
+			{
+				name: "overlapping network",
+				ports: []Port{
+					{Runtime: "grpc", Port: 8080, Host: "127.0.0.1", Network: "tcp4"},
+					{Runtime: "http", Port: 8080, Host: "127.0.0.1"},
+				},
+			},
+		}
+
+		for _, tt := range tests {
+			assert.ErrorContains(t, Check(tt.ports, options), "both configured to listen to port 8080", tt.name)
+		}
+	})
+
+	t.Run("should reject ports already in use", func(t *testing.T) {
+		l, err := net.Listen("tcp", ":0")
+		require.NoError(t, err)
+		defer l.Close()
+
+		port := int32(l.Addr().(*net.TCPAddr).Port)
+		err = Check([]Port{{Runtime: "http", Port: port}}, noProc)
+		assert.ErrorContains(t, err, "is already in use by another process")
+		assert.Equal(t, abort.ReasonPortBusy, abort.ReasonOf(err))
+
+		assert.NoError(t, Check([]Port{{Runtime: "http", Port: port}}, Options{
+			ProcRoot:         noProc.ProcRoot,
+			SkipAvailability: true,
+		}))
+	})
+
+	t.Run("should check the availability on the configured interface", func(t *testing.T) {
+		l, err := net.Listen("tcp4", "127.0.0.1:0")
+		require.NoError(t, err)
+		defer l.Close()
+
+		port := int32(l.Addr().(*net.TCPAddr).Port)
+		err = Check([]Port{{Runtime: "http", Port: port, Host: "127.0.0.1", Network: "tcp4"}}, noProc)
+		assert.ErrorContains(t, err, "is already in use by another process")
+	})
+
+	t.Run("should reject privileged ports without permission", func(t *testing.T) {
+		geteuid = func() int { return 1000 }
+		defer func() { geteuid = os.Geteuid }()
+
+		root := t.TempDir()
+		writeProcFile(t, root, "self/status", "Name:\tservice\nCapEff:\t0000000000000000\n")
+		writeProcFile(t, root, "sys/net/ipv4/ip_unprivileged_port_start", "1024\n")
+		options := Options{ProcRoot: root, SkipAvailability: true}
+
+		err := Check([]Port{{Runtime: "http", Port: 80}}, options)
+		assert.ErrorContains(t, err, "runtime 'http' port 80 is privileged")
+		assert.ErrorContains(t, err, "use a port from 1024")
+
+		writeProcFile(t, root, "self/status", "Name:\tservice\nCapEff:\t0000000000000400\n")
+		assert.NoError(t, Check([]Port{{Runtime: "http", Port: 80}}, options))
+
+		writeProcFile(t, root, "self/status", "Name:\tservice\nCapEff:\t0000000000000000\n")
+		writeProcFile(t, root, "sys/net/ipv4/ip_unprivileged_port_start", "0\n")
+		assert.NoError(t, Check([]Port{{Runtime: "http", Port: 80}}, options))
+	})
+
+	t.Run("should report every problem", func(t *testing.T) {
+		err := Check([]Port{
+			{Runtime: "grpc", Port: -1},
+			{Runtime: "http", Port: 9000},
+			{Runtime: "http_spec", Port: 9000},
+		}, Options{ProcRoot: noProc.ProcRoot, SkipAvailability: true})
+		assert.ErrorContains(t, err, "runtime 'grpc' port -1 is out of range")
+		assert.ErrorContains(t, err, "runtimes 'http' and 'http_spec'")
+	})
+}
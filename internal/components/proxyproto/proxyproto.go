@@ -0,0 +1,300 @@
+// Package proxyproto wraps listeners to parse the PROXY protocol header, in
+// versions 1 and 2, that L4 load balancers send at the beginning of their
+// connections, so servers see the address of the real clients instead of
+// the one of the load balancer.
+package proxyproto
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultHeaderTimeout = 5 * time.Second
+
+	// v1MaxLength is the maximum length of a version 1 header, including
+	// its CRLF.
+	v1MaxLength = 107
+
+	v2HeaderLength = 16
+	v2CommandLocal = 0x0
+	v2CommandProxy = 0x1
+	v2FamilyTCP4   = 0x11
+	v2FamilyTCP6   = 0x21
+)
+
+var (
+	v1Signature = []byte("PROXY ")
+	v2Signature = []byte("\r\n\r\n\x00\r\nQUIT\n")
+
+	// ErrNoHeader is returned when reading connections that did not start
+	// with a PROXY protocol header.
+	ErrNoHeader = errors.New("proxyproto: connection did not send a PROXY protocol header")
+)
+
+// Options configures which connections have their header parsed.
+type Options struct {
+	// TrustedProxies are the networks, in CIDR notation, of the proxies
+	// allowed to send headers. Connections from other peers are used as they
+	// are, so their addresses cannot be spoofed. When empty, no peer is
+	// trusted.
+	TrustedProxies []string
+
+	// HeaderTimeout is the maximum time to wait for the header. Defaults to
+	// 5 seconds.
+	HeaderTimeout time.Duration
+}
+
+// Listener is a net.Listener whose connections have their PROXY protocol
+// header parsed on their first use.
+type Listener struct {
+	net.Listener
+	trusted []*net.IPNet
+	timeout time.Duration
+}
+
+// NewListener wraps l to parse the PROXY protocol header of its connections.
+func NewListener(l net.Listener, options Options) (*Listener, error) {
+	trusted := make([]*net.IPNet, 0, len(options.TrustedProxies))
+	for _, cidr := range options.TrustedProxies {
+		_, n, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("proxyproto: invalid trusted proxy network: %w", err)
+		}
+
+		trusted = append(trusted, n)
+	}
+
+	timeout := options.HeaderTimeout
+	if timeout <= 0 {
+		timeout = defaultHeaderTimeout
+	}
+
+	return &Listener{
+		Listener: l,
+		trusted:  trusted,
+		timeout:  timeout,
+	}, nil
+}
+
+// Accept waits for the next connection. Its header is only read when it is
+// first used, so slow clients don't hold other connections.
+func (l *Listener) Accept() (net.Conn, error) {
+	c, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+
+	if !l.isTrusted(c.RemoteAddr()) {
+		return c, nil
+	}
+
+	return &Conn{
+		Conn:    c,
+		reader:  bufio.NewReader(c),
+		timeout: l.timeout,
+	}, nil
+}
+
+func (l *Listener) isTrusted(addr net.Addr) bool {
+	tcp, ok := addr.(*net.TCPAddr)
+	if !ok {
+		return false
+	}
+
+	for _, n := range l.trusted {
+		if n.Contains(tcp.IP) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Conn is a connection that started with a PROXY protocol header.
+type Conn struct {
+	net.Conn
+	reader  *bufio.Reader
+	timeout time.Duration
+	once    sync.Once
+	err     error
+	remote  net.Addr
+	local   net.Addr
+}
+
+// Read reads data after the header. It fails if the header is invalid.
+func (c *Conn) Read(b []byte) (int, error) {
+	c.once.Do(c.readHeader)
+	if c.err != nil {
+		return 0, c.err
+	}
+
+	return c.reader.Read(b)
+}
+
+// RemoteAddr returns the address of the client given by the header, or the
+// one of the peer when the header has none.
+func (c *Conn) RemoteAddr() net.Addr {
+	c.once.Do(c.readHeader)
+	if c.remote != nil {
+		return c.remote
+	}
+
+	return c.Conn.RemoteAddr()
+}
+
+// LocalAddr returns the destination address given by the header, or the
+// local one when the header has none.
+func (c *Conn) LocalAddr() net.Addr {
+	c.once.Do(c.readHeader)
+	if c.local != nil {
+		return c.local
+	}
+
+	return c.Conn.LocalAddr()
+}
+
+func (c *Conn) readHeader() {
+	if err := c.Conn.SetReadDeadline(time.Now().Add(c.timeout)); err != nil {
+		c.err = err
+		return
+	}
+
+	c.remote, c.local, c.err = parseHeader(c.reader)
+	if c.err != nil {
+		return
+	}
+
+	c.err = c.Conn.SetReadDeadline(time.Time{})
+}
+
+// parseHeader reads the header from r, returning the source and destination
+// addresses. They are nil when the header carries no addresses, like the
+// ones of health checks sent by the proxy itself.
+func parseHeader(r *bufio.Reader) (net.Addr, net.Addr, error) {
+	prefix, err := r.Peek(len(v1Signature))
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", ErrNoHeader, err)
+	}
+	if bytes.Equal(prefix, v1Signature) {
+		return parseV1(r)
+	}
+
+	prefix, err = r.Peek(len(v2Signature))
+	if err == nil && bytes.Equal(prefix, v2Signature) {
+		return parseV2(r)
+	}
+
+	return nil, nil, ErrNoHeader
+}
+
+func parseV1(r *bufio.Reader) (net.Addr, net.Addr, error) {
+	var line []byte
+	for len(line) < v1MaxLength {
+		b, err := r.ReadByte()
+		if err != nil {
+			return nil, nil, fmt.Errorf("proxyproto: incomplete v1 header: %w", err)
+		}
+
+		line = append(line, b)
+		if b == '\n' {
+			break
+		}
+	}
+	if !bytes.HasSuffix(line, []byte("\r\n")) {
+		return nil, nil, errors.New("proxyproto: v1 header is too long or missing CRLF")
+	}
+
+	fields := strings.Fields(string(line[:len(line)-2]))
+	if len(fields) >= 2 && fields[1] == "UNKNOWN" {
+		return nil, nil, nil
+	}
+	if len(fields) != 6 || (fields[1] != "TCP4" && fields[1] != "TCP6") {
+		return nil, nil, fmt.Errorf("proxyproto: invalid v1 header %q", line)
+	}
+
+	src, err := parseV1Address(fields[2], fields[4], fields[1])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	dst, err := parseV1Address(fields[3], fields[5], fields[1])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return src, dst, nil
+}
+
+func parseV1Address(ip, port, protocol string) (*net.TCPAddr, error) {
+	addr := net.ParseIP(ip)
+	if addr == nil || (protocol == "TCP4") != (addr.To4() != nil) {
+		return nil, fmt.Errorf("proxyproto: invalid v1 address %q", ip)
+	}
+
+	p, err := strconv.ParseUint(port, 10, 16)
+	if err != nil {
+		return nil, fmt.Errorf("proxyproto: invalid v1 port %q", port)
+	}
+
+	return &net.TCPAddr{IP: addr, Port: int(p)}, nil
+}
+
+func parseV2(r *bufio.Reader) (net.Addr, net.Addr, error) {
+	header := make([]byte, v2HeaderLength)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, nil, fmt.Errorf("proxyproto: incomplete v2 header: %w", err)
+	}
+
+	if version := header[12] >> 4; version != 2 {
+		return nil, nil, fmt.Errorf("proxyproto: unsupported v2 header version %d", version)
+	}
+
+	payload := make([]byte, binary.BigEndian.Uint16(header[14:16]))
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return nil, nil, fmt.Errorf("proxyproto: incomplete v2 header: %w", err)
+	}
+
+	switch command := header[12] & 0x0f; command {
+	case v2CommandLocal:
+		return nil, nil, nil
+	case v2CommandProxy:
+	default:
+		return nil, nil, fmt.Errorf("proxyproto: unsupported v2 command %d", command)
+	}
+
+	// Only TCP addresses are used, the remaining ones and the TLVs after
+	// them are skipped.
+	var size int
+	switch header[13] {
+	case v2FamilyTCP4:
+		size = net.IPv4len
+	case v2FamilyTCP6:
+		size = net.IPv6len
+	default:
+		return nil, nil, nil
+	}
+	if len(payload) < 2*size+4 {
+		return nil, nil, errors.New("proxyproto: v2 header is too short for its addresses")
+	}
+
+	src := &net.TCPAddr{
+		IP:   net.IP(payload[:size]),
+		Port: int(binary.BigEndian.Uint16(payload[2*size:])),
+	}
+	dst := &net.TCPAddr{
+		IP:   net.IP(payload[size : 2*size]),
+		Port: int(binary.BigEndian.Uint16(payload[2*size+2:])),
+	}
+
+	return src, dst, nil
+}
@@ -0,0 +1,197 @@
+package proxyproto
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func v2Header(command, family byte, addresses []byte) []byte {
+	header := append([]byte{}, v2Signature...)
+	header = append(header, 0x20|command, family)
+	header = binary.BigEndian.AppendUint16(header, uint16(len(addresses)))
+
+	return append(header, addresses...)
+}
+
+func tcp4Addresses() []byte {
+	addresses := []byte{192, 0, 2, 10, 192, 0, 2, 1}
+	addresses = binary.BigEndian.AppendUint16(addresses, 51000)
+	return binary.BigEndian.AppendUint16(addresses, 443)
+}
+
+func TestParseHeader(t *testing.T) {
+	t.Run("should parse v1 headers", func(t *testing.T) {
+		r := bufio.NewReader(strings.NewReader("PROXY TCP4 192.0.2.10 192.0.2.1 51000 443\r\nGET / HTTP/1.1"))
+
+		src, dst, err := parseHeader(r)
+		require.NoError(t, err)
+		assert.Equal(t, "192.0.2.10:51000", src.String())
+		assert.Equal(t, "192.0.2.1:443", dst.String())
+
+		rest, _ := io.ReadAll(r)
+		assert.Equal(t, "GET / HTTP/1.1", string(rest))
+	})
+
+	t.Run("should parse v1 IPv6 headers", func(t *testing.T) {
+		r := bufio.NewReader(strings.NewReader("PROXY TCP6 2001:db8::1 2001:db8::2 51000 443\r\n"))
+
+		src, _, err := parseHeader(r)
+		require.NoError(t, err)
+		assert.Equal(t, "[2001:db8::1]:51000", src.String())
+	})
+
+	t.Run("should keep addresses of v1 UNKNOWN headers", func(t *testing.T) {
+		r := bufio.NewReader(strings.NewReader("PROXY UNKNOWN\r\n"))
+
+		src, dst, err := parseHeader(r)
+		require.NoError(t, err)
+		assert.Nil(t, src)
+		assert.Nil(t, dst)
+	})
+
+	t.Run("should parse v2 headers skipping TLVs", func(t *testing.T) {
+		header := v2Header(v2CommandProxy, v2FamilyTCP4, append(tcp4Addresses(), 0x04, 0x00, 0x01, 0xff))
+		r := bufio.NewReader(bytes.NewReader(append(header, "data"...)))
+
+		src, dst, err := parseHeader(r)
+		require.NoError(t, err)
+		assert.Equal(t, "192.0.2.10:51000", src.String())
+		assert.Equal(t, "192.0.2.1:443", dst.String())
+
+		rest, _ := io.ReadAll(r)
+		assert.Equal(t, "data", string(rest))
+	})
+
+	t.Run("should keep addresses of v2 LOCAL headers", func(t *testing.T) {
+		r := bufio.NewReader(bytes.NewReader(v2Header(v2CommandLocal, 0x00, nil)))
+
+		src, _, err := parseHeader(r)
+		require.NoError(t, err)
+		assert.Nil(t, src)
+	})
+
+	t.Run("should fail without header", func(t *testing.T) {
+		_, _, err := parseHeader(bufio.NewReader(strings.NewReader("GET / HTTP/1.1\r\n\r\n")))
+		assert.ErrorIs(t, err, ErrNoHeader)
+	})
+
+	t.Run("should fail with invalid headers", func(t *testing.T) {
+		for _, header := range [][]byte{
+			[]byte("PROXY TCP4 192.0.2.10\r\n"),
+			[]byte("PROXY TCP4 2001:db8::1 192.0.2.1 51000 443\r\n"),
+			[]byte("PROXY TCP4 192.0.2.10 192.0.2.1 70000 443\r\n"),
+			[]byte("PROXY " + strings.Repeat("x", v1MaxLength)),
+			v2Header(v2CommandProxy, v2FamilyTCP4, []byte{1, 2, 3}),
+			v2Header(0x3, v2FamilyTCP4, tcp4Addresses()),
+		} {
+			_, _, err := parseHeader(bufio.NewReader(bytes.NewReader(header)))
+			assert.Error(t, err, string(header))
+		}
+	})
+}
+
+func TestListener(t *testing.T) {
+	dial := func(t *testing.T, l net.Listener, data string) net.Conn {
+		t.Helper()
+
+		go func() {
+			c, err := net.Dial("tcp", l.Addr().String())
+			if err != nil {
+				return
+			}
+			defer func() { _ = c.Close() }()
+			_, _ = c.Write([]byte(data))
+			time.Sleep(100 * time.Millisecond)
+		}()
+
+		c, err := l.Accept()
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = c.Close() })
+
+		return c
+	}
+
+	loopback := []string{"127.0.0.0/8"}
+
+	listen := func(t *testing.T, options Options) net.Listener {
+		t.Helper()
+
+		raw, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+
+		l, err := NewListener(raw, options)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = l.Close() })
+
+		return l
+	}
+
+	t.Run("should use addresses of the header", func(t *testing.T) {
+		l := listen(t, Options{TrustedProxies: loopback})
+		c := dial(t, l, "PROXY TCP4 192.0.2.10 192.0.2.1 51000 443\r\nping")
+
+		assert.Equal(t, "192.0.2.10:51000", c.RemoteAddr().String())
+		assert.Equal(t, "192.0.2.1:443", c.LocalAddr().String())
+
+		buf := make([]byte, 4)
+		_, err := io.ReadFull(c, buf)
+		require.NoError(t, err)
+		assert.Equal(t, "ping", string(buf))
+	})
+
+	t.Run("should reject connections without header", func(t *testing.T) {
+		l := listen(t, Options{TrustedProxies: loopback})
+		c := dial(t, l, "GET / HTTP/1.1\r\n\r\n")
+
+		_, err := c.Read(make([]byte, 4))
+		assert.ErrorIs(t, err, ErrNoHeader)
+		assert.Contains(t, c.RemoteAddr().String(), "127.0.0.1")
+	})
+
+	t.Run("should time out waiting for the header", func(t *testing.T) {
+		l := listen(t, Options{TrustedProxies: loopback, HeaderTimeout: 10 * time.Millisecond})
+		c := dial(t, l, "")
+
+		_, err := c.Read(make([]byte, 4))
+		assert.ErrorIs(t, err, ErrNoHeader)
+	})
+
+	t.Run("should not parse connections from untrusted peers", func(t *testing.T) {
+		l := listen(t, Options{TrustedProxies: []string{"10.0.0.0/8"}})
+		c := dial(t, l, "PROXY TCP4 192.0.2.10 192.0.2.1 51000 443\r\n")
+
+		_, ok := c.(*Conn)
+		assert.False(t, ok)
+		assert.Contains(t, c.RemoteAddr().String(), "127.0.0.1")
+	})
+
+	t.Run("should not trust any peer without trusted proxies", func(t *testing.T) {
+		l := listen(t, Options{})
+		c := dial(t, l, "PROXY TCP4 192.0.2.10 192.0.2.1 51000 443\r\n")
+
+		_, ok := c.(*Conn)
+		assert.False(t, ok)
+		assert.Contains(t, c.RemoteAddr().String(), "127.0.0.1")
+	})
+
+	t.Run("should parse connections from trusted peers", func(t *testing.T) {
+		l := listen(t, Options{TrustedProxies: loopback})
+		c := dial(t, l, "PROXY TCP4 192.0.2.10 192.0.2.1 51000 443\r\n")
+
+		assert.Equal(t, "192.0.2.10:51000", c.RemoteAddr().String())
+	})
+
+	t.Run("should fail with invalid trusted proxies", func(t *testing.T) {
+		_, err := NewListener(nil, Options{TrustedProxies: []string{"10.0.0.1"}})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,60 @@
+// Package shutdown implements the termination policy of long-lived requests,
+// such as streams, when their runtime server stops: they are notified first,
+// so they can tell their clients to reconnect elsewhere, and canceled when a
+// grace window expires.
+package shutdown
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	mcontext "github.com/mikros-dev/mikros/components/context"
+)
+
+// Notifier notifies and cancels the requests of a runtime server.
+type Notifier struct {
+	notice chan struct{}
+	force  context.Context
+	cancel context.CancelFunc
+	once   sync.Once
+}
+
+// NewNotifier creates a new Notifier.
+func NewNotifier() *Notifier {
+	force, cancel := context.WithCancel(context.Background())
+
+	return &Notifier{
+		notice: make(chan struct{}),
+		force:  force,
+		cancel: cancel,
+	}
+}
+
+// Context returns a copy of ctx carrying the shutdown notice, retrieved with
+// mcontext.ShutdownNotice, which is canceled when the grace window expires.
+// The returned function must be called when the request ends.
+func (n *Notifier) Context(ctx context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(mcontext.WithShutdownNotice(ctx, n.notice))
+	stop := context.AfterFunc(n.force, cancel)
+
+	return ctx, func() {
+		stop()
+		cancel()
+	}
+}
+
+// Shutdown notifies the requests that the server is shutting down and
+// cancels the ones still running after grace. It doesn't wait for them.
+func (n *Notifier) Shutdown(grace time.Duration) {
+	n.once.Do(func() {
+		close(n.notice)
+
+		if grace <= 0 {
+			n.cancel()
+			return
+		}
+
+		time.AfterFunc(grace, n.cancel)
+	})
+}
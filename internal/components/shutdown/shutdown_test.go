@@ -0,0 +1,72 @@
+package shutdown
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	mcontext "github.com/mikros-dev/mikros/components/context"
+)
+
+func TestNotifier(t *testing.T) {
+	t.Run("should notify requests and cancel them after the grace period", func(t *testing.T) {
+		var (
+			n           = NewNotifier()
+			ctx, cancel = n.Context(context.Background())
+		)
+		defer cancel()
+
+		notice := mcontext.ShutdownNotice(ctx)
+		assert.NotNil(t, notice)
+
+		start := time.Now()
+		n.Shutdown(50 * time.Millisecond)
+
+		select {
+		case <-notice:
+		default:
+			t.Fatal("request was not notified")
+		}
+		assert.NoError(t, ctx.Err())
+
+		<-ctx.Done()
+		assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+	})
+
+	t.Run("should cancel requests right away without grace period", func(t *testing.T) {
+		var (
+			n           = NewNotifier()
+			ctx, cancel = n.Context(context.Background())
+		)
+		defer cancel()
+
+		n.Shutdown(0)
+		n.Shutdown(time.Second)
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(time.Second):
+			t.Fatal("request was not canceled")
+		}
+	})
+
+	t.Run("should not cancel finished requests", func(t *testing.T) {
+		var (
+			n           = NewNotifier()
+			parent      = context.Background()
+			ctx, cancel = n.Context(parent)
+		)
+
+		cancel()
+		n.Shutdown(0)
+
+		assert.ErrorIs(t, ctx.Err(), context.Canceled)
+		assert.NoError(t, parent.Err())
+	})
+
+	t.Run("should not have notice outside requests", func(t *testing.T) {
+		assert.Nil(t, mcontext.ShutdownNotice(context.Background()))
+	})
+}
@@ -0,0 +1,75 @@
+package cache
+
+import (
+	"errors"
+	"time"
+
+	"github.com/creasty/defaults"
+
+	"github.com/mikros-dev/mikros/components/definition"
+)
+
+// Definitions holds the cache settings loaded from the 'service.toml' file:
+//
+//	[features.cache]
+//	enabled = true
+//	backend = "file"
+//	dir = "/var/cache/service"
+//	default_ttl = "5m"
+//
+// Backend selects where entries are kept: "memory", "file", which keeps
+// them inside dir, or the name of a backend registered by a plugin with
+// cache.RegisterBackend, which receives the settings of the
+// '[features.cache.options]' section.
+type Definitions struct {
+	Enable     bool                   `toml:"enabled"`
+	Backend    string                 `toml:"backend" default:"memory"`
+	Dir        string                 `toml:"dir"`
+	DefaultTTL time.Duration          `toml:"default_ttl"`
+	MaxEntries int                    `toml:"max_entries" default:"10000"`
+	Options    map[string]interface{} `toml:"options"`
+}
+
+func loadDefinitions(path string) (*Definitions, error) {
+	var file struct {
+		Features struct {
+			Cache Definitions `toml:"cache"`
+		} `toml:"features"`
+	}
+
+	if err := defaults.Set(&file.Features.Cache); err != nil {
+		return nil, err
+	}
+
+	if err := definition.ParseExternalDefinitions(path, &file); err != nil {
+		return nil, err
+	}
+
+	return &file.Features.Cache, nil
+}
+
+// Enabled returns if the cache feature was enabled.
+func (d *Definitions) Enabled() bool {
+	return d.Enable
+}
+
+// Validate validates the cache settings.
+func (d *Definitions) Validate() error {
+	if !d.Enable {
+		return nil
+	}
+
+	if d.Backend == "" {
+		return errors.New("cache backend must be set")
+	}
+
+	if d.Backend == "file" && d.Dir == "" {
+		return errors.New("cache dir must be set when using the file backend")
+	}
+
+	if d.DefaultTTL < 0 || d.MaxEntries < 0 {
+		return errors.New("cache default_ttl and max_entries cannot be negative")
+	}
+
+	return nil
+}
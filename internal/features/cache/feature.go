@@ -0,0 +1,125 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	cache_api "github.com/mikros-dev/mikros/apis/features/cache"
+	logger_api "github.com/mikros-dev/mikros/apis/features/logger"
+	"github.com/mikros-dev/mikros/components/definition"
+	"github.com/mikros-dev/mikros/components/logger"
+	"github.com/mikros-dev/mikros/components/plugin"
+)
+
+// Client is the cache feature client. It keeps entries in the backend
+// selected in the feature settings.
+type Client struct {
+	plugin.Entry
+	defs    *Definitions
+	backend cache_api.Backend
+}
+
+// New creates the cache feature.
+func New() *Client {
+	return &Client{}
+}
+
+// Definitions loads the feature settings from the 'service.toml' file.
+func (c *Client) Definitions(path string) (definition.ExternalFeatureEntry, error) {
+	return loadDefinitions(path)
+}
+
+// CanBeInitialized checks if the feature can be initialized.
+func (c *Client) CanBeInitialized(options *plugin.CanBeInitializedOptions) bool {
+	defs, err := options.Definitions.ExternalFeatureDefinitions(c.Name())
+	if err != nil {
+		return false
+	}
+
+	return defs.Enabled()
+}
+
+// Initialize initializes the feature.
+func (c *Client) Initialize(_ context.Context, options *plugin.InitializeOptions) error {
+	entry, err := options.Definitions.ExternalFeatureDefinitions(c.Name())
+	if err != nil {
+		return err
+	}
+
+	defs, ok := entry.(*Definitions)
+	if !ok {
+		return fmt.Errorf("invalid cache definitions type %T", entry)
+	}
+
+	backend, err := newBackend(defs)
+	if err != nil {
+		return err
+	}
+
+	c.defs = defs
+	c.backend = backend
+	return nil
+}
+
+func newBackend(defs *Definitions) (cache_api.Backend, error) {
+	switch defs.Backend {
+	case "memory":
+		return newMemoryBackend(defs.MaxEntries), nil
+	case "file":
+		return newFileBackend(defs.Dir)
+	}
+
+	factory, ok := cache_api.LookupBackend(defs.Backend)
+	if !ok {
+		return nil, fmt.Errorf("unknown cache backend '%s'", defs.Backend)
+	}
+
+	return factory(defs.Options)
+}
+
+// Fields returns feature fields to be logged.
+func (c *Client) Fields() []logger_api.Attribute {
+	if !c.IsEnabled() {
+		return []logger_api.Attribute{}
+	}
+
+	return []logger_api.Attribute{
+		logger.String("cache.backend", c.defs.Backend),
+	}
+}
+
+// Start does nothing, the backend is ready once the feature is initialized.
+func (c *Client) Start(_ context.Context, _ interface{}) error {
+	return nil
+}
+
+// Cleanup closes the backend, if it needs to.
+func (c *Client) Cleanup(_ context.Context) error {
+	if closer, ok := c.backend.(io.Closer); ok {
+		return closer.Close()
+	}
+
+	return nil
+}
+
+// Get returns the value of key, and false if it is not cached or has
+// expired.
+func (c *Client) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	return c.backend.Get(ctx, key)
+}
+
+// Set caches value for key during ttl, or the default TTL if ttl is zero.
+func (c *Client) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if ttl <= 0 {
+		ttl = c.defs.DefaultTTL
+	}
+
+	return c.backend.Set(ctx, key, value, ttl)
+}
+
+// Delete removes key from the cache.
+func (c *Client) Delete(ctx context.Context, key string) error {
+	return c.backend.Delete(ctx, key)
+}
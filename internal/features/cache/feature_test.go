@@ -0,0 +1,111 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	cache_api "github.com/mikros-dev/mikros/apis/features/cache"
+	"github.com/mikros-dev/mikros/components/clock"
+)
+
+func TestBackends(t *testing.T) {
+	file, err := newFileBackend(t.TempDir())
+	require.NoError(t, err)
+
+	backends := map[string]cache_api.Backend{
+		"memory": newMemoryBackend(10),
+		"file":   file,
+	}
+
+	for name, backend := range backends {
+		t.Run("should store, expire and delete entries with "+name+" backend", func(t *testing.T) {
+			ctx := context.Background()
+
+			require.NoError(t, backend.Set(ctx, "a", []byte("1"), 0))
+			require.NoError(t, backend.Set(ctx, "b", []byte("2"), 10*time.Millisecond))
+
+			v, ok, err := backend.Get(ctx, "a")
+			require.NoError(t, err)
+			assert.True(t, ok)
+			assert.Equal(t, []byte("1"), v)
+
+			time.Sleep(20 * time.Millisecond)
+			_, ok, err = backend.Get(ctx, "b")
+			require.NoError(t, err)
+			assert.False(t, ok)
+
+			require.NoError(t, backend.Delete(ctx, "a"))
+			_, ok, err = backend.Get(ctx, "a")
+			require.NoError(t, err)
+			assert.False(t, ok)
+		})
+	}
+
+	t.Run("should expire entries when the clock moves", func(t *testing.T) {
+		var (
+			ctx     = context.Background()
+			fake    = clock.NewFake(time.Now())
+			restore = clock.Set(fake)
+			backend = newMemoryBackend(10)
+		)
+		defer restore()
+
+		require.NoError(t, backend.Set(ctx, "a", []byte("1"), time.Hour))
+
+		fake.Advance(59 * time.Minute)
+		_, ok, _ := backend.Get(ctx, "a")
+		assert.True(t, ok)
+
+		fake.Advance(time.Minute)
+		_, ok, _ = backend.Get(ctx, "a")
+		assert.False(t, ok)
+	})
+
+	t.Run("should evict entries when the memory backend is full", func(t *testing.T) {
+		var (
+			ctx     = context.Background()
+			backend = newMemoryBackend(2)
+		)
+
+		require.NoError(t, backend.Set(ctx, "a", []byte("1"), time.Minute))
+		require.NoError(t, backend.Set(ctx, "b", []byte("2"), time.Hour))
+		require.NoError(t, backend.Set(ctx, "c", []byte("3"), time.Hour))
+
+		_, ok, _ := backend.Get(ctx, "a")
+		assert.False(t, ok)
+		_, ok, _ = backend.Get(ctx, "c")
+		assert.True(t, ok)
+	})
+}
+
+type fakeBackend struct {
+	cache_api.Backend
+	options map[string]interface{}
+}
+
+func TestNewBackend(t *testing.T) {
+	t.Run("should use backends registered by plugins", func(t *testing.T) {
+		cache_api.RegisterBackend("fake", func(options map[string]interface{}) (cache_api.Backend, error) {
+			return &fakeBackend{options: options}, nil
+		})
+
+		backend, err := newBackend(&Definitions{
+			Backend: "fake",
+			Options: map[string]interface{}{"address": "localhost:6379"},
+		})
+		require.NoError(t, err)
+
+		fake, ok := backend.(*fakeBackend)
+		require.True(t, ok)
+		assert.Equal(t, "localhost:6379", fake.options["address"])
+	})
+
+	t.Run("should fail with unknown backends", func(t *testing.T) {
+		_, err := newBackend(&Definitions{Backend: "unknown"})
+		assert.EqualError(t, err, "unknown cache backend 'unknown'")
+	})
+}
@@ -0,0 +1,106 @@
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/mikros-dev/mikros/components/clock"
+)
+
+const (
+	fileEntryHeaderSize = 8
+)
+
+// fileBackend keeps every entry in a file inside a directory, named after
+// the hash of its key. Files start with the entry expiration time, as Unix
+// nanoseconds (zero for no expiration), followed by its value.
+type fileBackend struct {
+	dir string
+	now func() time.Time
+}
+
+func newFileBackend(dir string) (*fileBackend, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	return &fileBackend{
+		dir: dir,
+		now: clock.Now,
+	}, nil
+}
+
+func (f *fileBackend) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(f.dir, hex.EncodeToString(sum[:]))
+}
+
+func (f *fileBackend) Get(_ context.Context, key string) ([]byte, bool, error) {
+	path := f.path(key)
+
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	if len(b) < fileEntryHeaderSize {
+		// A truncated entry is discarded.
+		_ = os.Remove(path)
+		return nil, false, nil
+	}
+
+	expiresAt := int64(binary.BigEndian.Uint64(b[:fileEntryHeaderSize]))
+	if expiresAt != 0 && f.now().UnixNano() >= expiresAt {
+		_ = os.Remove(path)
+		return nil, false, nil
+	}
+
+	return b[fileEntryHeaderSize:], true, nil
+}
+
+func (f *fileBackend) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	var expiresAt int64
+	if ttl > 0 {
+		expiresAt = f.now().Add(ttl).UnixNano()
+	}
+
+	b := make([]byte, fileEntryHeaderSize+len(value))
+	binary.BigEndian.PutUint64(b, uint64(expiresAt))
+	copy(b[fileEntryHeaderSize:], value)
+
+	// Entries are written into a temporary file and renamed so that readers
+	// never see a partial entry.
+	tmp, err := os.CreateTemp(f.dir, ".entry-*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = os.Remove(tmp.Name())
+	}()
+
+	if _, err := tmp.Write(b); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), f.path(key))
+}
+
+func (f *fileBackend) Delete(_ context.Context, key string) error {
+	if err := os.Remove(f.path(key)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
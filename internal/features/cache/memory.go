@@ -0,0 +1,108 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/mikros-dev/mikros/components/clock"
+)
+
+// memoryBackend keeps entries in memory. When maxEntries is reached, expired
+// entries are removed and, if there is still no room, the entry closest to
+// expire is evicted.
+type memoryBackend struct {
+	mu         sync.Mutex
+	entries    map[string]memoryEntry
+	maxEntries int
+	now        func() time.Time
+}
+
+type memoryEntry struct {
+	value     []byte
+	expiresAt time.Time
+}
+
+func (e memoryEntry) expired(now time.Time) bool {
+	return !e.expiresAt.IsZero() && !now.Before(e.expiresAt)
+}
+
+func newMemoryBackend(maxEntries int) *memoryBackend {
+	return &memoryBackend{
+		entries:    make(map[string]memoryEntry),
+		maxEntries: maxEntries,
+		now:        clock.Now,
+	}
+}
+
+func (m *memoryBackend) Get(_ context.Context, key string) ([]byte, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e, ok := m.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+	if e.expired(m.now()) {
+		delete(m.entries, key)
+		return nil, false, nil
+	}
+
+	return e.value, true, nil
+}
+
+func (m *memoryBackend) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	if _, ok := m.entries[key]; !ok && m.maxEntries > 0 && len(m.entries) >= m.maxEntries {
+		m.evict(now)
+	}
+
+	e := memoryEntry{
+		value: append([]byte(nil), value...),
+	}
+	if ttl > 0 {
+		e.expiresAt = now.Add(ttl)
+	}
+	m.entries[key] = e
+
+	return nil
+}
+
+// evict must be called with mu held.
+func (m *memoryBackend) evict(now time.Time) {
+	var (
+		victim    string
+		victimExp time.Time
+	)
+
+	for key, e := range m.entries {
+		if e.expired(now) {
+			delete(m.entries, key)
+			continue
+		}
+
+		// Entries without expiration are evicted last.
+		exp := e.expiresAt
+		if exp.IsZero() {
+			exp = time.Unix(1<<62, 0)
+		}
+		if victim == "" || exp.Before(victimExp) {
+			victim, victimExp = key, exp
+		}
+	}
+
+	if len(m.entries) >= m.maxEntries && victim != "" {
+		delete(m.entries, victim)
+	}
+}
+
+func (m *memoryBackend) Delete(_ context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.entries, key)
+	return nil
+}
@@ -0,0 +1,69 @@
+package clock
+
+import (
+	"context"
+	"time"
+
+	logger_api "github.com/mikros-dev/mikros/apis/features/logger"
+	"github.com/mikros-dev/mikros/components/clock"
+	"github.com/mikros-dev/mikros/components/plugin"
+)
+
+// Client is the clock feature client. It always uses the framework default
+// clock, so services see the same time as the framework, even when tests
+// replace it.
+type Client struct {
+	plugin.Entry
+}
+
+// New creates the clock feature.
+func New() *Client {
+	return &Client{}
+}
+
+// CanBeInitialized checks if the feature can be initialized.
+func (c *Client) CanBeInitialized(_ *plugin.CanBeInitializedOptions) bool {
+	// Always enabled
+	return true
+}
+
+// Initialize initializes the feature.
+func (c *Client) Initialize(_ context.Context, _ *plugin.InitializeOptions) error {
+	return nil
+}
+
+// Fields returns feature fields to be logged.
+func (c *Client) Fields() []logger_api.Attribute {
+	return []logger_api.Attribute{}
+}
+
+// ServiceAPI returns the clock API that services can use.
+func (c *Client) ServiceAPI() interface{} {
+	return c
+}
+
+// Now returns the current time.
+func (c *Client) Now() time.Time {
+	return clock.Now()
+}
+
+// Since returns the time elapsed since t.
+func (c *Client) Since(t time.Time) time.Duration {
+	return clock.Since(t)
+}
+
+// After waits for d to elapse and then sends the current time on the
+// returned channel.
+func (c *Client) After(d time.Duration) <-chan time.Time {
+	return clock.After(d)
+}
+
+// NewTimer creates a timer that fires once d elapses.
+func (c *Client) NewTimer(d time.Duration) clock.Timer {
+	return clock.NewTimer(d)
+}
+
+// NewTicker creates a ticker that ticks every d.
+func (c *Client) NewTicker(d time.Duration) clock.Ticker {
+	return clock.NewTicker(d)
+}
@@ -3,11 +3,16 @@ package features
 import (
 	"github.com/mikros-dev/mikros/components/options"
 	"github.com/mikros-dev/mikros/components/plugin"
+	"github.com/mikros-dev/mikros/internal/features/cache"
+	"github.com/mikros-dev/mikros/internal/features/clock"
 	"github.com/mikros-dev/mikros/internal/features/definition"
 	"github.com/mikros-dev/mikros/internal/features/env"
 	"github.com/mikros-dev/mikros/internal/features/errors"
 	"github.com/mikros-dev/mikros/internal/features/http"
+	"github.com/mikros-dev/mikros/internal/features/lock"
 	"github.com/mikros-dev/mikros/internal/features/logger"
+	"github.com/mikros-dev/mikros/internal/features/oauth2"
+	"github.com/mikros-dev/mikros/internal/features/watchdog"
 )
 
 // Features returns the set of features that are available in mikros.
@@ -19,6 +24,11 @@ func Features() *plugin.FeatureSet {
 	features.Register(options.ErrorsFeatureName, errors.New())
 	features.Register(options.DefinitionFeatureName, definition.New())
 	features.Register(options.EnvFeatureName, env.New())
+	features.Register(options.WatchdogFeatureName, watchdog.New())
+	features.Register(options.OAuth2FeatureName, oauth2.New())
+	features.Register(options.LockFeatureName, lock.New())
+	features.Register(options.CacheFeatureName, cache.New())
+	features.Register(options.ClockFeatureName, clock.New())
 
 	return features
 }
@@ -0,0 +1,80 @@
+package lock
+
+import (
+	"errors"
+	"time"
+
+	"github.com/creasty/defaults"
+
+	"github.com/mikros-dev/mikros/components/definition"
+)
+
+// Definitions holds the lock settings loaded from the 'service.toml' file:
+//
+//	[features.lock]
+//	enabled = true
+//	backend = "file"
+//	dir = "/var/run/service/locks"
+//	ttl = "15s"
+//	retry_interval = "5s"
+//
+// Backend selects where locks are kept: "memory", which only shares them
+// inside the process, "file", which keeps them inside dir, or the name of a
+// backend registered by a plugin with lock.RegisterBackend, which receives
+// the settings of the '[features.lock.options]' section.
+//
+// TTL is the lease of the locks held by RunIfLeader, which are refreshed at
+// a third of it. RetryInterval is how often replicas standing by try to take
+// the leadership over.
+type Definitions struct {
+	Enable        bool                   `toml:"enabled"`
+	Backend       string                 `toml:"backend" default:"memory"`
+	Dir           string                 `toml:"dir"`
+	TTL           time.Duration          `toml:"ttl" default:"15s"`
+	RetryInterval time.Duration          `toml:"retry_interval" default:"5s"`
+	Options       map[string]interface{} `toml:"options"`
+}
+
+func loadDefinitions(path string) (*Definitions, error) {
+	var file struct {
+		Features struct {
+			Lock Definitions `toml:"lock"`
+		} `toml:"features"`
+	}
+
+	if err := defaults.Set(&file.Features.Lock); err != nil {
+		return nil, err
+	}
+
+	if err := definition.ParseExternalDefinitions(path, &file); err != nil {
+		return nil, err
+	}
+
+	return &file.Features.Lock, nil
+}
+
+// Enabled returns if the lock feature was enabled.
+func (d *Definitions) Enabled() bool {
+	return d.Enable
+}
+
+// Validate validates the lock settings.
+func (d *Definitions) Validate() error {
+	if !d.Enable {
+		return nil
+	}
+
+	if d.Backend == "" {
+		return errors.New("lock backend must be set")
+	}
+
+	if d.Backend == "file" && d.Dir == "" {
+		return errors.New("lock dir must be set when using the file backend")
+	}
+
+	if d.TTL <= 0 || d.RetryInterval <= 0 {
+		return errors.New("lock ttl and retry_interval must be greater than zero")
+	}
+
+	return nil
+}
@@ -0,0 +1,295 @@
+package lock
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	lock_api "github.com/mikros-dev/mikros/apis/features/lock"
+	logger_api "github.com/mikros-dev/mikros/apis/features/logger"
+	"github.com/mikros-dev/mikros/components/clock"
+	"github.com/mikros-dev/mikros/components/definition"
+	"github.com/mikros-dev/mikros/components/ids"
+	"github.com/mikros-dev/mikros/components/logger"
+	"github.com/mikros-dev/mikros/components/plugin"
+)
+
+// Client is the lock feature client. It provides TTL based locks, kept in
+// the configured backend, and leader-only task execution on top of them.
+type Client struct {
+	plugin.Entry
+	defs    *Definitions
+	backend lock_api.Backend
+	owner   string
+	logger  logger_api.API
+	stop    chan struct{}
+	stopped sync.Once
+	wg      sync.WaitGroup
+
+	mu      sync.Mutex
+	leaders map[string]bool
+}
+
+// New creates the lock feature.
+func New() *Client {
+	return &Client{
+		owner:   newOwnerID(),
+		stop:    make(chan struct{}),
+		leaders: make(map[string]bool),
+	}
+}
+
+func newOwnerID() string {
+	hostname, _ := os.Hostname()
+	return fmt.Sprintf("%s-%d-%s", hostname, os.Getpid(), ids.New())
+}
+
+// newOwnerToken identifies a single acquisition, so concurrent ones of the
+// same replica exclude each other and can only release their own locks.
+func (c *Client) newOwnerToken() string {
+	return c.owner + "/" + ids.New()
+}
+
+// Definitions loads the feature settings from the 'service.toml' file.
+func (c *Client) Definitions(path string) (definition.ExternalFeatureEntry, error) {
+	return loadDefinitions(path)
+}
+
+// CanBeInitialized checks if the feature can be initialized.
+func (c *Client) CanBeInitialized(options *plugin.CanBeInitializedOptions) bool {
+	defs, err := options.Definitions.ExternalFeatureDefinitions(c.Name())
+	if err != nil {
+		return false
+	}
+
+	return defs.Enabled()
+}
+
+// Initialize initializes the feature.
+func (c *Client) Initialize(_ context.Context, options *plugin.InitializeOptions) error {
+	entry, err := options.Definitions.ExternalFeatureDefinitions(c.Name())
+	if err != nil {
+		return err
+	}
+
+	defs, ok := entry.(*Definitions)
+	if !ok {
+		return fmt.Errorf("invalid lock definitions type %T", entry)
+	}
+
+	backend, err := newBackend(defs)
+	if err != nil {
+		return err
+	}
+
+	c.defs = defs
+	c.backend = backend
+	c.logger = options.Logger
+	return nil
+}
+
+func newBackend(defs *Definitions) (lock_api.Backend, error) {
+	switch defs.Backend {
+	case "memory":
+		return newMemoryBackend(), nil
+	case "file":
+		return newFileBackend(defs.Dir)
+	}
+
+	factory, ok := lock_api.LookupBackend(defs.Backend)
+	if !ok {
+		return nil, fmt.Errorf("unknown lock backend '%s'", defs.Backend)
+	}
+
+	return factory(defs.Options)
+}
+
+// Fields returns feature fields to be logged.
+func (c *Client) Fields() []logger_api.Attribute {
+	if !c.IsEnabled() {
+		return []logger_api.Attribute{}
+	}
+
+	return []logger_api.Attribute{
+		logger.String("lock.backend", c.defs.Backend),
+		logger.String("lock.owner", c.owner),
+	}
+}
+
+// Start does nothing, since leaderships are only disputed when RunIfLeader is
+// called.
+func (c *Client) Start(_ context.Context, _ interface{}) error {
+	return nil
+}
+
+// Cleanup stops all RunIfLeader calls, releasing their locks.
+func (c *Client) Cleanup(_ context.Context) error {
+	c.stopped.Do(func() {
+		close(c.stop)
+	})
+
+	c.wg.Wait()
+	return nil
+}
+
+// TryLock tries to acquire the lock name for ttl without waiting.
+func (c *Client) TryLock(ctx context.Context, name string, ttl time.Duration) (lock_api.Lock, bool, error) {
+	owner := c.newOwnerToken()
+	ok, err := c.backend.Acquire(ctx, name, owner, ttl)
+	if err != nil || !ok {
+		return nil, false, err
+	}
+
+	return &heldLock{
+		client: c,
+		name:   name,
+		owner:  owner,
+		ttl:    ttl,
+	}, true, nil
+}
+
+// RunIfLeader executes fn only while this replica is the leader of name.
+func (c *Client) RunIfLeader(ctx context.Context, name string, fn func(ctx context.Context) error) error {
+	c.wg.Add(1)
+	defer c.wg.Done()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	go func() {
+		select {
+		case <-c.stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	var (
+		owner = c.newOwnerToken()
+		retry = clock.NewTimer(0)
+	)
+	defer retry.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-retry.C():
+		}
+
+		ok, err := c.backend.Acquire(ctx, name, owner, c.defs.TTL)
+		if err != nil {
+			c.logger.Warn(ctx, "lock: could not acquire leadership", logger.String("lock.name", name), logger.Error(err))
+		}
+		if ok {
+			if done, err := c.lead(ctx, name, owner, fn); done {
+				return err
+			}
+		}
+
+		retry.Reset(c.defs.RetryInterval)
+	}
+}
+
+// lead executes fn while the leadership of name, acquired with the owner
+// token, is kept. It returns true when fn has finished, or false if the
+// leadership was lost.
+func (c *Client) lead(
+	ctx context.Context,
+	name, owner string,
+	fn func(ctx context.Context) error,
+) (bool, error) {
+	leaderCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	c.setLeader(name, true)
+	defer c.setLeader(name, false)
+
+	c.logger.Info(ctx, "lock: leadership acquired", logger.String("lock.name", name))
+
+	result := make(chan error, 1)
+	go func() {
+		result <- fn(leaderCtx)
+	}()
+
+	var (
+		refresh     = clock.NewTicker(c.defs.TTL / 3)
+		lastRefresh = clock.Now()
+	)
+	defer refresh.Stop()
+
+	for {
+		select {
+		case err := <-result:
+			if rerr := c.backend.Release(context.WithoutCancel(ctx), name, owner); rerr != nil {
+				c.logger.Warn(ctx, "lock: could not release leadership", logger.String("lock.name", name), logger.Error(rerr))
+			}
+
+			return true, err
+
+		case now := <-refresh.C():
+			ok, err := c.backend.Acquire(ctx, name, owner, c.defs.TTL)
+			if err == nil && ok {
+				lastRefresh = now
+				continue
+			}
+
+			// Errors are tolerated while the current lease is still valid,
+			// since the backend may recover before it expires.
+			if err != nil && now.Sub(lastRefresh) < c.defs.TTL {
+				c.logger.Warn(ctx, "lock: could not refresh leadership", logger.String("lock.name", name), logger.Error(err))
+				continue
+			}
+
+			c.logger.Warn(ctx, "lock: leadership lost", logger.String("lock.name", name))
+			cancel()
+			<-result
+			return false, nil
+		}
+	}
+}
+
+func (c *Client) setLeader(name string, leader bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if leader {
+		c.leaders[name] = true
+		return
+	}
+
+	delete(c.leaders, name)
+}
+
+// IsLeader returns true if this replica currently is the leader of name.
+func (c *Client) IsLeader(name string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.leaders[name]
+}
+
+type heldLock struct {
+	client *Client
+	name   string
+	owner  string
+	ttl    time.Duration
+}
+
+func (l *heldLock) Refresh(ctx context.Context) error {
+	ok, err := l.client.backend.Acquire(ctx, l.name, l.owner, l.ttl)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return lock_api.ErrNotHeld
+	}
+
+	return nil
+}
+
+func (l *heldLock) Release(ctx context.Context) error {
+	return l.client.backend.Release(ctx, l.name, l.owner)
+}
@@ -0,0 +1,241 @@
+package lock
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	lock_api "github.com/mikros-dev/mikros/apis/features/lock"
+	"github.com/mikros-dev/mikros/components/ids"
+	ilogger "github.com/mikros-dev/mikros/internal/components/logger"
+)
+
+func newTestClient(backend lock_api.Backend) *Client {
+	c := New()
+	c.defs = &Definitions{
+		Enable:        true,
+		Backend:       "memory",
+		TTL:           30 * time.Millisecond,
+		RetryInterval: 5 * time.Millisecond,
+	}
+	c.backend = backend
+	c.logger = ilogger.New(ilogger.Options{DiscardMessages: true})
+
+	return c
+}
+
+func TestClientTryLock(t *testing.T) {
+	t.Run("should not acquire a lock held by another owner", func(t *testing.T) {
+		var (
+			backend = newMemoryBackend()
+			a       = newTestClient(backend)
+			b       = newTestClient(backend)
+		)
+
+		l, ok, err := a.TryLock(context.Background(), "job", time.Minute)
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		_, ok, err = b.TryLock(context.Background(), "job", time.Minute)
+		require.NoError(t, err)
+		assert.False(t, ok)
+
+		require.NoError(t, l.Refresh(context.Background()))
+		require.NoError(t, l.Release(context.Background()))
+		assert.ErrorIs(t, l.Release(context.Background()), lock_api.ErrNotHeld)
+
+		_, ok, err = b.TryLock(context.Background(), "job", time.Minute)
+		require.NoError(t, err)
+		assert.True(t, ok)
+	})
+
+	t.Run("should not share locks among concurrent callers", func(t *testing.T) {
+		c := newTestClient(newMemoryBackend())
+
+		first, ok, err := c.TryLock(context.Background(), "job", time.Minute)
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		_, ok, err = c.TryLock(context.Background(), "job", time.Minute)
+		require.NoError(t, err)
+		assert.False(t, ok)
+
+		require.NoError(t, first.Release(context.Background()))
+
+		second, ok, err := c.TryLock(context.Background(), "job", time.Minute)
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		// The first holder cannot release the lock again.
+		assert.ErrorIs(t, first.Release(context.Background()), lock_api.ErrNotHeld)
+		require.NoError(t, second.Refresh(context.Background()))
+	})
+}
+
+func TestClientOwner(t *testing.T) {
+	t.Run("should create owners with the framework ID generator", func(t *testing.T) {
+		reset := ids.Set(ids.NewSequential("test"))
+		defer reset()
+
+		c := newTestClient(newMemoryBackend())
+		assert.True(t, strings.HasSuffix(c.owner, "-test-000001"), c.owner)
+		assert.Equal(t, c.owner+"/test-000002", c.newOwnerToken())
+	})
+}
+
+func TestClientRunIfLeader(t *testing.T) {
+	t.Run("should run on a single replica and take over when the leader stops", func(t *testing.T) {
+		var (
+			backend = newMemoryBackend()
+			a       = newTestClient(backend)
+			b       = newTestClient(backend)
+			running atomic.Int32
+			runs    atomic.Int32
+		)
+
+		task := func(ctx context.Context) error {
+			running.Add(1)
+			runs.Add(1)
+			defer running.Add(-1)
+
+			<-ctx.Done()
+			return ctx.Err()
+		}
+
+		ctxA, cancelA := context.WithCancel(context.Background())
+		errA := make(chan error, 1)
+		go func() { errA <- a.RunIfLeader(ctxA, "job", task) }()
+		require.Eventually(t, func() bool { return a.IsLeader("job") }, time.Second, time.Millisecond)
+
+		ctxB, cancelB := context.WithCancel(context.Background())
+		defer cancelB()
+		go func() { _ = b.RunIfLeader(ctxB, "job", task) }()
+
+		time.Sleep(50 * time.Millisecond)
+		assert.False(t, b.IsLeader("job"))
+		assert.Equal(t, int32(1), running.Load())
+
+		cancelA()
+		assert.ErrorIs(t, <-errA, context.Canceled)
+
+		require.Eventually(t, func() bool { return b.IsLeader("job") }, time.Second, time.Millisecond)
+		assert.Equal(t, int32(2), runs.Load())
+		require.NoError(t, b.Cleanup(context.Background()))
+	})
+
+	t.Run("should run on a single goroutine of the same replica", func(t *testing.T) {
+		var (
+			c       = newTestClient(newMemoryBackend())
+			running atomic.Int32
+			maxRun  atomic.Int32
+		)
+
+		task := func(context.Context) error {
+			n := running.Add(1)
+			defer running.Add(-1)
+			if n > maxRun.Load() {
+				maxRun.Store(n)
+			}
+
+			time.Sleep(20 * time.Millisecond)
+			return nil
+		}
+
+		errs := make(chan error, 2)
+		for i := 0; i < 2; i++ {
+			go func() { errs <- c.RunIfLeader(context.Background(), "job", task) }()
+		}
+
+		require.NoError(t, <-errs)
+		require.NoError(t, <-errs)
+		assert.Equal(t, int32(1), maxRun.Load())
+	})
+
+	t.Run("should cancel the task when the leadership is lost", func(t *testing.T) {
+		var (
+			backend = newMemoryBackend()
+			a       = newTestClient(backend)
+			lost    = make(chan struct{})
+		)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		go func() {
+			_ = a.RunIfLeader(ctx, "job", func(ctx context.Context) error {
+				<-ctx.Done()
+				close(lost)
+				return nil
+			})
+		}()
+		require.Eventually(t, func() bool { return a.IsLeader("job") }, time.Second, time.Millisecond)
+
+		// Another replica takes the lock over, e.g. after a network partition.
+		backend.mu.Lock()
+		backend.locks["job"] = memoryLock{owner: "other", expiresAt: time.Now().Add(time.Hour)}
+		backend.mu.Unlock()
+
+		select {
+		case <-lost:
+		case <-time.After(time.Second):
+			t.Fatal("task was not canceled")
+		}
+		require.Eventually(t, func() bool { return !a.IsLeader("job") }, time.Second, time.Millisecond)
+	})
+
+	t.Run("should return the task error and release the lock", func(t *testing.T) {
+		var (
+			backend = newMemoryBackend()
+			a       = newTestClient(backend)
+		)
+
+		err := a.RunIfLeader(context.Background(), "job", func(_ context.Context) error {
+			return errors.New("failed")
+		})
+		assert.EqualError(t, err, "failed")
+
+		ok, err := backend.Acquire(context.Background(), "job", "other", time.Minute)
+		require.NoError(t, err)
+		assert.True(t, ok)
+	})
+}
+
+func TestFileBackend(t *testing.T) {
+	t.Run("should share locks among backends using the same directory", func(t *testing.T) {
+		var (
+			ctx = context.Background()
+			dir = t.TempDir()
+		)
+
+		a, err := newFileBackend(dir)
+		require.NoError(t, err)
+		b, err := newFileBackend(dir)
+		require.NoError(t, err)
+
+		ok, err := a.Acquire(ctx, "jobs/daily", "a", time.Minute)
+		require.NoError(t, err)
+		assert.True(t, ok)
+
+		ok, err = b.Acquire(ctx, "jobs/daily", "b", time.Minute)
+		require.NoError(t, err)
+		assert.False(t, ok)
+
+		assert.ErrorIs(t, b.Release(ctx, "jobs/daily", "b"), lock_api.ErrNotHeld)
+		require.NoError(t, a.Release(ctx, "jobs/daily", "a"))
+
+		ok, err = b.Acquire(ctx, "jobs/daily", "b", time.Millisecond)
+		require.NoError(t, err)
+		assert.True(t, ok)
+
+		time.Sleep(5 * time.Millisecond)
+		ok, err = a.Acquire(ctx, "jobs/daily", "a", time.Minute)
+		require.NoError(t, err)
+		assert.True(t, ok, "expired locks can be taken over")
+	})
+}
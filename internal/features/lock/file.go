@@ -0,0 +1,154 @@
+package lock
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	lock_api "github.com/mikros-dev/mikros/apis/features/lock"
+	"github.com/mikros-dev/mikros/components/clock"
+)
+
+const (
+	fileGuardRetryInterval = 5 * time.Millisecond
+	fileGuardStaleAfter    = 10 * time.Second
+)
+
+// fileBackend keeps every lock in a file inside a directory, so locks are
+// shared among processes of the same host or that share the directory
+// through a filesystem.
+type fileBackend struct {
+	dir string
+	now func() time.Time
+}
+
+type fileLock struct {
+	Owner     string    `json:"owner"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func newFileBackend(dir string) (*fileBackend, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	return &fileBackend{
+		dir: dir,
+		now: clock.Now,
+	}, nil
+}
+
+func (f *fileBackend) path(name string) string {
+	return filepath.Join(f.dir, url.PathEscape(name)+".lock")
+}
+
+func (f *fileBackend) Acquire(ctx context.Context, name, owner string, ttl time.Duration) (bool, error) {
+	acquired := false
+	err := f.withGuard(ctx, name, func() error {
+		path := f.path(name)
+
+		current, err := readFileLock(path)
+		if err != nil {
+			return err
+		}
+
+		now := f.now()
+		if current != nil && current.Owner != owner && now.Before(current.ExpiresAt) {
+			return nil
+		}
+
+		if err := writeFileLock(path, &fileLock{Owner: owner, ExpiresAt: now.Add(ttl)}); err != nil {
+			return err
+		}
+
+		acquired = true
+		return nil
+	})
+
+	return acquired, err
+}
+
+func (f *fileBackend) Release(ctx context.Context, name, owner string) error {
+	return f.withGuard(ctx, name, func() error {
+		path := f.path(name)
+
+		current, err := readFileLock(path)
+		if err != nil {
+			return err
+		}
+		if current == nil || current.Owner != owner || !f.now().Before(current.ExpiresAt) {
+			return lock_api.ErrNotHeld
+		}
+
+		return os.Remove(path)
+	})
+}
+
+// withGuard executes fn while holding a guard file for the lock, making the
+// read-modify-write of the lock file atomic among processes. Guards left
+// behind by crashed processes are removed after a while.
+func (f *fileBackend) withGuard(ctx context.Context, name string, fn func() error) error {
+	guard := f.path(name) + ".guard"
+
+	for {
+		file, err := os.OpenFile(guard, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+		if err == nil {
+			_ = file.Close()
+			defer func() {
+				_ = os.Remove(guard)
+			}()
+
+			return fn()
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return err
+		}
+
+		if info, err := os.Stat(guard); err == nil && f.now().Sub(info.ModTime()) > fileGuardStaleAfter {
+			_ = os.Remove(guard)
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(fileGuardRetryInterval):
+		}
+	}
+}
+
+func readFileLock(path string) (*fileLock, error) {
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var l fileLock
+	if err := json.Unmarshal(b, &l); err != nil {
+		// A corrupted lock file is considered released.
+		return nil, nil
+	}
+
+	return &l, nil
+}
+
+func writeFileLock(path string, l *fileLock) error {
+	b, err := json.Marshal(l)
+	if err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, b, 0o600); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,60 @@
+package lock
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	lock_api "github.com/mikros-dev/mikros/apis/features/lock"
+	"github.com/mikros-dev/mikros/components/clock"
+)
+
+// memoryBackend keeps locks in memory, so they are only shared inside the
+// same process. It is useful for tests and services with a single replica.
+type memoryBackend struct {
+	mu    sync.Mutex
+	locks map[string]memoryLock
+	now   func() time.Time
+}
+
+type memoryLock struct {
+	owner     string
+	expiresAt time.Time
+}
+
+func newMemoryBackend() *memoryBackend {
+	return &memoryBackend{
+		locks: make(map[string]memoryLock),
+		now:   clock.Now,
+	}
+}
+
+func (m *memoryBackend) Acquire(_ context.Context, name, owner string, ttl time.Duration) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	if l, ok := m.locks[name]; ok && l.owner != owner && now.Before(l.expiresAt) {
+		return false, nil
+	}
+
+	m.locks[name] = memoryLock{
+		owner:     owner,
+		expiresAt: now.Add(ttl),
+	}
+
+	return true, nil
+}
+
+func (m *memoryBackend) Release(_ context.Context, name, owner string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	l, ok := m.locks[name]
+	if !ok || l.owner != owner || !m.now().Before(l.expiresAt) {
+		return lock_api.ErrNotHeld
+	}
+
+	delete(m.locks, name)
+	return nil
+}
@@ -0,0 +1,86 @@
+package oauth2
+
+import (
+	"errors"
+	"time"
+
+	"github.com/creasty/defaults"
+
+	"github.com/mikros-dev/mikros/components/definition"
+)
+
+// Definitions holds the oauth2 settings loaded from the 'service.toml' file:
+//
+//	[features.oauth2]
+//	enabled = true
+//	token_url = "https://auth.example.com/oauth/token"
+//	scopes = ["read"]
+//
+//	[features.oauth2.clients]
+//	user = "https://user.example.com"
+//
+// The client ID and secret are read from the environment variables named by
+// client_id_env and client_secret_env. Clients maps coupled gRPC clients to
+// the audience of the tokens attached to their calls. Tokens are refreshed
+// refresh_before their expiration, and the ones received without expires_in
+// are considered valid for default_expires_in.
+type Definitions struct {
+	Enable           bool              `toml:"enabled"`
+	TokenURL         string            `toml:"token_url"`
+	ClientIDEnv      string            `toml:"client_id_env" default:"OAUTH2_CLIENT_ID"`
+	ClientSecretEnv  string            `toml:"client_secret_env" default:"OAUTH2_CLIENT_SECRET"`
+	Scopes           []string          `toml:"scopes"`
+	AudienceParam    string            `toml:"audience_param" default:"audience"`
+	RefreshBefore    time.Duration     `toml:"refresh_before" default:"1m"`
+	DefaultExpiresIn time.Duration     `toml:"default_expires_in" default:"5m"`
+	Timeout          time.Duration     `toml:"timeout" default:"10s"`
+	Clients          map[string]string `toml:"clients"`
+}
+
+func loadDefinitions(path string) (*Definitions, error) {
+	var file struct {
+		Features struct {
+			OAuth2 Definitions `toml:"oauth2"`
+		} `toml:"features"`
+	}
+
+	if err := defaults.Set(&file.Features.OAuth2); err != nil {
+		return nil, err
+	}
+
+	if err := definition.ParseExternalDefinitions(path, &file); err != nil {
+		return nil, err
+	}
+
+	return &file.Features.OAuth2, nil
+}
+
+// Enabled returns if the oauth2 feature was enabled.
+func (d *Definitions) Enabled() bool {
+	return d.Enable
+}
+
+// Validate validates the oauth2 settings.
+func (d *Definitions) Validate() error {
+	if !d.Enable {
+		return nil
+	}
+
+	if d.TokenURL == "" {
+		return errors.New("oauth2 token_url must be set")
+	}
+
+	if d.ClientIDEnv == "" || d.ClientSecretEnv == "" {
+		return errors.New("oauth2 client_id_env and client_secret_env must be set")
+	}
+
+	if d.RefreshBefore < 0 || d.Timeout <= 0 {
+		return errors.New("oauth2 refresh_before cannot be negative and timeout must be greater than zero")
+	}
+
+	if d.DefaultExpiresIn <= 0 {
+		return errors.New("oauth2 default_expires_in must be greater than zero")
+	}
+
+	return nil
+}
@@ -0,0 +1,321 @@
+package oauth2
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/credentials"
+
+	logger_api "github.com/mikros-dev/mikros/apis/features/logger"
+	oauth2_api "github.com/mikros-dev/mikros/apis/features/oauth2"
+	"github.com/mikros-dev/mikros/components/clock"
+	"github.com/mikros-dev/mikros/components/definition"
+	"github.com/mikros-dev/mikros/components/logger"
+	"github.com/mikros-dev/mikros/components/plugin"
+)
+
+const (
+	maxTokenResponseBytes = 1 << 20
+)
+
+// Client is the oauth2 feature client. It fetches access tokens using the
+// client credentials grant and caches them per audience.
+type Client struct {
+	plugin.Entry
+	defs         *Definitions
+	clientID     string
+	clientSecret string
+	httpClient   *http.Client
+	now          func() time.Time
+
+	mu     sync.Mutex
+	tokens map[string]*cachedToken
+	stats  oauth2_api.Stats
+}
+
+type cachedToken struct {
+	mu        sync.Mutex
+	value     string
+	expiresAt time.Time
+	refreshAt time.Time
+}
+
+// New creates the oauth2 feature.
+func New() *Client {
+	return &Client{
+		now:    clock.Now,
+		tokens: make(map[string]*cachedToken),
+	}
+}
+
+// Definitions loads the feature settings from the 'service.toml' file.
+func (c *Client) Definitions(path string) (definition.ExternalFeatureEntry, error) {
+	return loadDefinitions(path)
+}
+
+// CanBeInitialized checks if the feature can be initialized.
+func (c *Client) CanBeInitialized(options *plugin.CanBeInitializedOptions) bool {
+	defs, err := options.Definitions.ExternalFeatureDefinitions(c.Name())
+	if err != nil {
+		return false
+	}
+
+	return defs.Enabled()
+}
+
+// Initialize initializes the feature.
+func (c *Client) Initialize(_ context.Context, options *plugin.InitializeOptions) error {
+	entry, err := options.Definitions.ExternalFeatureDefinitions(c.Name())
+	if err != nil {
+		return err
+	}
+
+	defs, ok := entry.(*Definitions)
+	if !ok {
+		return fmt.Errorf("invalid oauth2 definitions type %T", entry)
+	}
+
+	c.clientID = os.Getenv(defs.ClientIDEnv)
+	c.clientSecret = os.Getenv(defs.ClientSecretEnv)
+	if c.clientID == "" || c.clientSecret == "" {
+		return fmt.Errorf("oauth2 credentials not found in '%s' and '%s' environment variables", defs.ClientIDEnv, defs.ClientSecretEnv)
+	}
+
+	c.defs = defs
+	c.httpClient = &http.Client{
+		Timeout: defs.Timeout,
+	}
+
+	return nil
+}
+
+// Fields returns feature fields to be logged.
+func (c *Client) Fields() []logger_api.Attribute {
+	if !c.IsEnabled() {
+		return []logger_api.Attribute{}
+	}
+
+	return []logger_api.Attribute{
+		logger.String("oauth2.token_url", c.defs.TokenURL),
+	}
+}
+
+// Token returns a valid access token for the audience, fetching a new one
+// when the cached token is about to expire. If refreshing fails while the
+// cached token is still valid, the cached token is returned.
+func (c *Client) Token(ctx context.Context, audience string) (string, error) {
+	if !c.IsEnabled() {
+		return "", errors.New("oauth2 feature is not enabled")
+	}
+
+	token := c.cachedToken(audience)
+	token.mu.Lock()
+	defer token.mu.Unlock()
+
+	now := c.now()
+	if token.value != "" && now.Before(token.refreshAt) {
+		return token.value, nil
+	}
+
+	value, expiresIn, err := c.fetch(ctx, audience)
+	if err != nil {
+		c.recordFailure(now, err)
+		if token.value != "" && now.Before(token.expiresAt) {
+			c.Logger().Warn(ctx, "could not refresh oauth2 token, using cached one",
+				logger.String("oauth2.audience", audience),
+				logger.Error(err),
+			)
+
+			return token.value, nil
+		}
+
+		return "", err
+	}
+
+	if expiresIn <= 0 {
+		expiresIn = c.defs.DefaultExpiresIn
+	}
+
+	token.value = value
+	token.expiresAt = now.Add(expiresIn)
+	token.refreshAt = token.expiresAt.Add(-refreshMargin(expiresIn, c.defs.RefreshBefore))
+	return token.value, nil
+}
+
+// refreshMargin returns how long before expiring a token is refreshed. It
+// is limited to half the token lifetime, so short-lived tokens are not
+// fetched again on every call.
+func refreshMargin(lifetime, refreshBefore time.Duration) time.Duration {
+	return min(refreshBefore, lifetime/2)
+}
+
+func (c *Client) cachedToken(audience string) *cachedToken {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	token, ok := c.tokens[audience]
+	if !ok {
+		token = &cachedToken{}
+		c.tokens[audience] = token
+	}
+
+	return token
+}
+
+func (c *Client) recordFailure(now time.Time, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.stats.Failures++
+	c.stats.LastFailure = now
+	c.stats.LastError = err.Error()
+}
+
+func (c *Client) fetch(ctx context.Context, audience string) (string, time.Duration, error) {
+	c.mu.Lock()
+	c.stats.Fetches++
+	c.mu.Unlock()
+
+	form := url.Values{
+		"grant_type": {"client_credentials"},
+	}
+	if audience != "" {
+		form.Set(c.defs.AudienceParam, audience)
+	}
+	if len(c.defs.Scopes) > 0 {
+		form.Set("scope", strings.Join(c.defs.Scopes, " "))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.defs.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", 0, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(c.clientID), url.QueryEscape(c.clientSecret))
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", 0, fmt.Errorf("could not request oauth2 token: %w", err)
+	}
+	defer func() {
+		_ = res.Body.Close()
+	}()
+
+	body, err := io.ReadAll(io.LimitReader(res.Body, maxTokenResponseBytes))
+	if err != nil {
+		return "", 0, fmt.Errorf("could not read oauth2 token response: %w", err)
+	}
+	if res.StatusCode != http.StatusOK {
+		return "", 0, fmt.Errorf("oauth2 token request failed with status %d: %s", res.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var out struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := json.Unmarshal(body, &out); err != nil {
+		return "", 0, fmt.Errorf("could not decode oauth2 token response: %w", err)
+	}
+	if out.AccessToken == "" {
+		return "", 0, errors.New("oauth2 token response has no access_token")
+	}
+
+	return out.AccessToken, time.Duration(out.ExpiresIn) * time.Second, nil
+}
+
+// Transport wraps base with a round tripper adding access tokens for the
+// audience into requests.
+func (c *Client) Transport(audience string, base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	return &transport{
+		client:   c,
+		audience: audience,
+		base:     base,
+	}
+}
+
+// HTTPClient returns an http.Client adding access tokens for the audience
+// into requests.
+func (c *Client) HTTPClient(audience string) *http.Client {
+	return &http.Client{
+		Transport: c.Transport(audience, nil),
+	}
+}
+
+// Stats returns counters of the token requests made so far.
+func (c *Client) Stats() oauth2_api.Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.stats
+}
+
+// PerRPCCredentials returns the gRPC credentials that must be used by the
+// coupled client, if it was mapped to an audience in the definitions.
+func (c *Client) PerRPCCredentials(client string) (credentials.PerRPCCredentials, bool) {
+	if !c.IsEnabled() {
+		return nil, false
+	}
+
+	audience, ok := c.defs.Clients[client]
+	if !ok {
+		return nil, false
+	}
+
+	return &rpcCredentials{
+		client:   c,
+		audience: audience,
+	}, true
+}
+
+type transport struct {
+	client   *Client
+	audience string
+	base     http.RoundTripper
+}
+
+func (t *transport) RoundTrip(r *http.Request) (*http.Response, error) {
+	token, err := t.client.Token(r.Context(), t.audience)
+	if err != nil {
+		return nil, err
+	}
+
+	req := r.Clone(r.Context())
+	req.Header.Set("Authorization", "Bearer "+token)
+	return t.base.RoundTrip(req)
+}
+
+type rpcCredentials struct {
+	client   *Client
+	audience string
+}
+
+func (r *rpcCredentials) GetRequestMetadata(ctx context.Context, _ ...string) (map[string]string, error) {
+	token, err := r.client.Token(ctx, r.audience)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]string{
+		"authorization": "Bearer " + token,
+	}, nil
+}
+
+// RequireTransportSecurity allows tokens over the insecure connections used
+// between coupled services.
+func (r *rpcCredentials) RequireTransportSecurity() bool {
+	return false
+}
@@ -0,0 +1,178 @@
+package oauth2
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mikros-dev/mikros/components/plugin"
+)
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	c := New()
+	c.UpdateInfo(plugin.UpdateInfoEntry{Enabled: true})
+	c.defs = &Definitions{
+		Enable:           true,
+		TokenURL:         server.URL,
+		AudienceParam:    "audience",
+		RefreshBefore:    time.Minute,
+		DefaultExpiresIn: 5 * time.Minute,
+		Clients: map[string]string{
+			"user": "https://user.example.com",
+		},
+	}
+	c.clientID = "id"
+	c.clientSecret = "secret"
+	c.httpClient = server.Client()
+
+	return c
+}
+
+func TestClientToken(t *testing.T) {
+	t.Run("should fetch and cache tokens per audience", func(t *testing.T) {
+		var calls atomic.Int32
+		c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			id, secret, ok := r.BasicAuth()
+			assert.True(t, ok)
+			assert.Equal(t, "id", id)
+			assert.Equal(t, "secret", secret)
+			assert.NoError(t, r.ParseForm())
+			assert.Equal(t, "client_credentials", r.PostForm.Get("grant_type"))
+
+			n := calls.Add(1)
+			_, _ = fmt.Fprintf(w, `{"access_token":"%s-%d","expires_in":3600}`, r.PostForm.Get("audience"), n)
+		})
+
+		token, err := c.Token(context.Background(), "a")
+		require.NoError(t, err)
+		assert.Equal(t, "a-1", token)
+
+		token, err = c.Token(context.Background(), "a")
+		require.NoError(t, err)
+		assert.Equal(t, "a-1", token)
+
+		token, err = c.Token(context.Background(), "b")
+		require.NoError(t, err)
+		assert.Equal(t, "b-2", token)
+		assert.Equal(t, uint64(2), c.Stats().Fetches)
+	})
+
+	t.Run("should refresh tokens before they expire", func(t *testing.T) {
+		var calls atomic.Int32
+		c := newTestClient(t, func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = fmt.Fprintf(w, `{"access_token":"t%d","expires_in":120}`, calls.Add(1))
+		})
+
+		now := time.Now()
+		c.now = func() time.Time { return now }
+
+		token, err := c.Token(context.Background(), "a")
+		require.NoError(t, err)
+		assert.Equal(t, "t1", token)
+
+		now = now.Add(90 * time.Second)
+		token, err = c.Token(context.Background(), "a")
+		require.NoError(t, err)
+		assert.Equal(t, "t2", token)
+	})
+
+	t.Run("should use the default lifetime without expires_in", func(t *testing.T) {
+		var calls atomic.Int32
+		c := newTestClient(t, func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = fmt.Fprintf(w, `{"access_token":"t%d"}`, calls.Add(1))
+		})
+
+		now := time.Now()
+		c.now = func() time.Time { return now }
+
+		token, err := c.Token(context.Background(), "a")
+		require.NoError(t, err)
+		assert.Equal(t, "t1", token)
+
+		now = now.Add(3 * time.Minute)
+		token, err = c.Token(context.Background(), "a")
+		require.NoError(t, err)
+		assert.Equal(t, "t1", token)
+
+		now = now.Add(90 * time.Second)
+		token, err = c.Token(context.Background(), "a")
+		require.NoError(t, err)
+		assert.Equal(t, "t2", token)
+	})
+
+	t.Run("should not refresh short-lived tokens on every call", func(t *testing.T) {
+		var calls atomic.Int32
+		c := newTestClient(t, func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = fmt.Fprintf(w, `{"access_token":"t%d","expires_in":30}`, calls.Add(1))
+		})
+
+		now := time.Now()
+		c.now = func() time.Time { return now }
+
+		_, err := c.Token(context.Background(), "a")
+		require.NoError(t, err)
+
+		now = now.Add(10 * time.Second)
+		token, err := c.Token(context.Background(), "a")
+		require.NoError(t, err)
+		assert.Equal(t, "t1", token)
+
+		now = now.Add(10 * time.Second)
+		token, err = c.Token(context.Background(), "a")
+		require.NoError(t, err)
+		assert.Equal(t, "t2", token)
+	})
+
+	t.Run("should count failures", func(t *testing.T) {
+		c := newTestClient(t, func(w http.ResponseWriter, _ *http.Request) {
+			http.Error(w, "invalid_client", http.StatusUnauthorized)
+		})
+
+		_, err := c.Token(context.Background(), "a")
+		assert.Error(t, err)
+		assert.Equal(t, uint64(1), c.Stats().Failures)
+		assert.Contains(t, c.Stats().LastError, "status 401")
+	})
+}
+
+func TestClientOutbound(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"access_token":"abc","expires_in":3600}`))
+	})
+
+	t.Run("should add tokens into HTTP requests", func(t *testing.T) {
+		var authorization string
+		api := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+			authorization = r.Header.Get("Authorization")
+		}))
+		defer api.Close()
+
+		res, err := c.HTTPClient("a").Get(api.URL)
+		require.NoError(t, err)
+		_ = res.Body.Close()
+		assert.Equal(t, "Bearer abc", authorization)
+	})
+
+	t.Run("should add tokens into coupled gRPC clients metadata", func(t *testing.T) {
+		_, ok := c.PerRPCCredentials("unknown")
+		assert.False(t, ok)
+
+		creds, ok := c.PerRPCCredentials("user")
+		require.True(t, ok)
+		assert.False(t, creds.RequireTransportSecurity())
+
+		md, err := creds.GetRequestMetadata(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, "Bearer abc", md["authorization"])
+	})
+}
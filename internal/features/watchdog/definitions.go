@@ -0,0 +1,83 @@
+package watchdog
+
+import (
+	"errors"
+	"time"
+
+	"github.com/creasty/defaults"
+
+	"github.com/mikros-dev/mikros/components/definition"
+)
+
+// Definitions holds the watchdog settings loaded from the 'service.toml'
+// file:
+//
+//	[features.watchdog]
+//	enabled = true
+//	max_goroutines = 10000
+//	max_heap_bytes = 1073741824
+//	max_gc_pause = "100ms"
+//	max_scheduler_lag = "50ms"
+//	profile_dir = "/var/tmp/profiles"
+type Definitions struct {
+	Enable          bool          `toml:"enabled"`
+	Interval        time.Duration `toml:"interval" default:"30s"`
+	MaxGoroutines   int           `toml:"max_goroutines"`
+	MaxHeapBytes    uint64        `toml:"max_heap_bytes"`
+	MaxGCPause      time.Duration `toml:"max_gc_pause"`
+	ProfileDir      string        `toml:"profile_dir"`
+	ProfileCooldown time.Duration `toml:"profile_cooldown" default:"10m"`
+
+	// LagProbeInterval is the interval of the timer used to measure the
+	// scheduler lag.
+	LagProbeInterval time.Duration `toml:"lag_probe_interval" default:"100ms"`
+	MaxSchedulerLag  time.Duration `toml:"max_scheduler_lag"`
+}
+
+func loadDefinitions(path string) (*Definitions, error) {
+	var file struct {
+		Features struct {
+			Watchdog Definitions `toml:"watchdog"`
+		} `toml:"features"`
+	}
+
+	if err := defaults.Set(&file.Features.Watchdog); err != nil {
+		return nil, err
+	}
+
+	if err := definition.ParseExternalDefinitions(path, &file); err != nil {
+		return nil, err
+	}
+
+	return &file.Features.Watchdog, nil
+}
+
+// Enabled returns if the watchdog was enabled.
+func (d *Definitions) Enabled() bool {
+	return d.Enable
+}
+
+// Validate validates the watchdog settings.
+func (d *Definitions) Validate() error {
+	if !d.Enable {
+		return nil
+	}
+
+	if d.Interval <= 0 {
+		return errors.New("watchdog interval must be greater than zero")
+	}
+
+	if d.MaxGoroutines < 0 {
+		return errors.New("watchdog max_goroutines cannot be negative")
+	}
+
+	if d.LagProbeInterval <= 0 {
+		return errors.New("watchdog lag_probe_interval must be greater than zero")
+	}
+
+	if d.MaxGCPause < 0 || d.ProfileCooldown < 0 || d.MaxSchedulerLag < 0 {
+		return errors.New("watchdog durations cannot be negative")
+	}
+
+	return nil
+}
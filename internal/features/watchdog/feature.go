@@ -0,0 +1,298 @@
+package watchdog
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	logger_api "github.com/mikros-dev/mikros/apis/features/logger"
+	"github.com/mikros-dev/mikros/components/clock"
+	"github.com/mikros-dev/mikros/components/definition"
+	"github.com/mikros-dev/mikros/components/logger"
+	"github.com/mikros-dev/mikros/components/plugin"
+)
+
+// Client is the watchdog feature client. When enabled, it periodically
+// samples the number of goroutines, the heap usage, GC pause times and the
+// scheduler lag, logging warnings when they exceed the configured thresholds.
+type Client struct {
+	plugin.Entry
+	defs            *Definitions
+	logger          logger_api.API
+	cancel          context.CancelFunc
+	wg              sync.WaitGroup
+	lastNumGC       uint32
+	lastProfile     time.Time
+	schedulerLag    atomic.Int64
+	maxSchedulerLag atomic.Int64
+}
+
+// New creates the watchdog feature.
+func New() *Client {
+	return &Client{}
+}
+
+// Definitions loads the feature settings from the 'service.toml' file.
+func (c *Client) Definitions(path string) (definition.ExternalFeatureEntry, error) {
+	return loadDefinitions(path)
+}
+
+// CanBeInitialized checks if the feature can be initialized.
+func (c *Client) CanBeInitialized(options *plugin.CanBeInitializedOptions) bool {
+	if options.DeploymentEnv == definition.DeploymentEnvTest {
+		return false
+	}
+
+	defs, err := options.Definitions.ExternalFeatureDefinitions(c.Name())
+	if err != nil {
+		return false
+	}
+
+	return defs.Enabled()
+}
+
+// Initialize initializes the feature.
+func (c *Client) Initialize(_ context.Context, options *plugin.InitializeOptions) error {
+	entry, err := options.Definitions.ExternalFeatureDefinitions(c.Name())
+	if err != nil {
+		return err
+	}
+
+	defs, ok := entry.(*Definitions)
+	if !ok {
+		return fmt.Errorf("invalid watchdog definitions type %T", entry)
+	}
+
+	c.defs = defs
+	c.logger = options.Logger
+	return nil
+}
+
+// Fields returns feature fields to be logged.
+func (c *Client) Fields() []logger_api.Attribute {
+	if !c.IsEnabled() {
+		return []logger_api.Attribute{}
+	}
+
+	return []logger_api.Attribute{
+		logger.String("watchdog.interval", c.defs.Interval.String()),
+	}
+}
+
+// Start starts sampling the runtime in background.
+func (c *Client) Start(ctx context.Context, _ interface{}) error {
+	if !c.IsEnabled() {
+		return nil
+	}
+
+	ctx, c.cancel = context.WithCancel(context.WithoutCancel(ctx))
+	var (
+		ticker = clock.NewTicker(c.defs.Interval)
+		probe  = newLagProbe(c.defs.LagProbeInterval)
+	)
+
+	c.wg.Add(2)
+	go c.watch(ctx, ticker)
+	go c.probeSchedulerLag(ctx, probe)
+	return nil
+}
+
+// Cleanup stops the runtime sampling.
+func (c *Client) Cleanup(_ context.Context) error {
+	if c.cancel == nil {
+		return nil
+	}
+
+	c.cancel()
+	c.wg.Wait()
+	return nil
+}
+
+func (c *Client) watch(ctx context.Context, ticker clock.Ticker) {
+	defer c.wg.Done()
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C():
+			c.check(ctx, c.sample())
+		}
+	}
+}
+
+// lagProbe is a timer that knows when it is expected to fire.
+type lagProbe struct {
+	timer    clock.Timer
+	interval time.Duration
+	expected time.Time
+}
+
+func newLagProbe(interval time.Duration) *lagProbe {
+	return &lagProbe{
+		timer:    clock.NewTimer(interval),
+		interval: interval,
+		expected: clock.Now().Add(interval),
+	}
+}
+
+// measure returns how late the timer fired, after it was received, and
+// restarts it.
+func (p *lagProbe) measure() time.Duration {
+	// The time is taken after receiving from the timer, instead of using
+	// the value received, so the time spent in the run queue is included.
+	now := clock.Now()
+	lag := max(now.Sub(p.expected), 0)
+
+	p.expected = now.Add(p.interval)
+	p.timer.Reset(p.interval)
+
+	return lag
+}
+
+// probeSchedulerLag measures how late a timer fires compared to its
+// interval. When all Ps are busy, runnable goroutines wait in the run
+// queue and the timer goroutine is scheduled late.
+func (c *Client) probeSchedulerLag(ctx context.Context, probe *lagProbe) {
+	defer c.wg.Done()
+	defer probe.timer.Stop()
+
+	var lastLog time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-probe.timer.C():
+		}
+
+		lag := probe.measure()
+		c.recordSchedulerLag(lag)
+
+		// Warnings are limited to one per sampling interval.
+		now := clock.Now()
+		if c.defs.MaxSchedulerLag > 0 && lag > c.defs.MaxSchedulerLag && now.Sub(lastLog) >= c.defs.Interval {
+			lastLog = now
+			c.logger.Warn(ctx, "watchdog: scheduler lag above threshold",
+				logger.String("watchdog.scheduler_lag", lag.String()),
+				logger.String("watchdog.max_scheduler_lag", c.defs.MaxSchedulerLag.String()),
+			)
+		}
+	}
+}
+
+func (c *Client) recordSchedulerLag(lag time.Duration) {
+	c.schedulerLag.Store(int64(lag))
+	for {
+		current := c.maxSchedulerLag.Load()
+		if int64(lag) <= current || c.maxSchedulerLag.CompareAndSwap(current, int64(lag)) {
+			return
+		}
+	}
+}
+
+// SchedulerLag returns the most recent scheduler lag measured.
+func (c *Client) SchedulerLag() time.Duration {
+	return time.Duration(c.schedulerLag.Load())
+}
+
+// MaxSchedulerLag returns the highest scheduler lag measured.
+func (c *Client) MaxSchedulerLag() time.Duration {
+	return time.Duration(c.maxSchedulerLag.Load())
+}
+
+type sample struct {
+	goroutines int
+	heapBytes  uint64
+	maxGCPause time.Duration
+}
+
+func (c *Client) sample() sample {
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+
+	// Only pauses of collections that happened since the last sample are
+	// considered. PauseNs is a circular buffer of the 256 most recent ones.
+	var maxPause time.Duration
+	for n := stats.NumGC; n > c.lastNumGC && stats.NumGC-n < 256; n-- {
+		if p := time.Duration(stats.PauseNs[(n+255)%256]); p > maxPause {
+			maxPause = p
+		}
+	}
+	c.lastNumGC = stats.NumGC
+
+	return sample{
+		goroutines: runtime.NumGoroutine(),
+		heapBytes:  stats.HeapAlloc,
+		maxGCPause: maxPause,
+	}
+}
+
+func (c *Client) check(ctx context.Context, s sample) {
+	if c.defs.MaxGoroutines > 0 && s.goroutines > c.defs.MaxGoroutines {
+		c.logger.Warn(ctx, "watchdog: goroutines count above threshold",
+			logger.Any("watchdog.goroutines", s.goroutines),
+			logger.Any("watchdog.max_goroutines", c.defs.MaxGoroutines),
+		)
+	}
+
+	if c.defs.MaxGCPause > 0 && s.maxGCPause > c.defs.MaxGCPause {
+		c.logger.Warn(ctx, "watchdog: GC pause above threshold",
+			logger.String("watchdog.gc_pause", s.maxGCPause.String()),
+			logger.String("watchdog.max_gc_pause", c.defs.MaxGCPause.String()),
+		)
+	}
+
+	if c.defs.MaxHeapBytes > 0 && s.heapBytes > c.defs.MaxHeapBytes {
+		c.logger.Warn(ctx, "watchdog: heap usage above threshold",
+			logger.Any("watchdog.heap_bytes", s.heapBytes),
+			logger.Any("watchdog.max_heap_bytes", c.defs.MaxHeapBytes),
+		)
+
+		c.dumpHeapProfile(ctx)
+	}
+}
+
+// dumpHeapProfile writes a heap profile into the configured directory, at
+// most once per cooldown period.
+func (c *Client) dumpHeapProfile(ctx context.Context) {
+	if c.defs.ProfileDir == "" || clock.Since(c.lastProfile) < c.defs.ProfileCooldown {
+		return
+	}
+	c.lastProfile = clock.Now()
+
+	path, err := writeHeapProfile(c.defs.ProfileDir, c.lastProfile)
+	if err != nil {
+		c.logger.Error(ctx, "watchdog: could not write heap profile", logger.Error(err))
+		return
+	}
+
+	c.logger.Info(ctx, "watchdog: heap profile written", logger.String("watchdog.profile", path))
+}
+
+func writeHeapProfile(dir string, now time.Time) (string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(dir, fmt.Sprintf("heap-%d.pprof", now.Unix()))
+	file, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	if err := pprof.WriteHeapProfile(file); err != nil {
+		return "", err
+	}
+
+	return path, nil
+}
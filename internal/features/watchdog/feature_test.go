@@ -0,0 +1,211 @@
+package watchdog
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	logger_api "github.com/mikros-dev/mikros/apis/features/logger"
+	"github.com/mikros-dev/mikros/components/clock"
+	"github.com/mikros-dev/mikros/components/plugin"
+)
+
+type watchdogLogger struct {
+	logger_api.API
+	mu       sync.Mutex
+	messages []string
+}
+
+func (l *watchdogLogger) Info(_ context.Context, msg string, _ ...logger_api.Attribute) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.messages = append(l.messages, msg)
+}
+
+func (l *watchdogLogger) Warn(ctx context.Context, msg string, attrs ...logger_api.Attribute) {
+	l.Info(ctx, msg, attrs...)
+}
+
+func (l *watchdogLogger) Error(ctx context.Context, msg string, attrs ...logger_api.Attribute) {
+	l.Info(ctx, msg, attrs...)
+}
+
+func (l *watchdogLogger) Messages() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]string{}, l.messages...)
+}
+
+func newTestClient(t *testing.T, defs *Definitions) (*Client, *watchdogLogger) {
+	t.Helper()
+
+	defs.Enable = true
+	if defs.Interval == 0 {
+		defs.Interval = time.Hour
+	}
+	if defs.LagProbeInterval == 0 {
+		defs.LagProbeInterval = time.Hour
+	}
+
+	var (
+		log = &watchdogLogger{}
+		c   = New()
+	)
+	c.UpdateInfo(plugin.UpdateInfoEntry{Name: "watchdog", Enabled: true})
+	c.defs = defs
+	c.logger = log
+
+	return c, log
+}
+
+func TestCheck(t *testing.T) {
+	fake := clock.NewFake(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	defer clock.Set(fake)()
+
+	t.Run("should warn about values above the thresholds", func(t *testing.T) {
+		c, log := newTestClient(t, &Definitions{
+			MaxGoroutines: 100,
+			MaxHeapBytes:  1 << 20,
+			MaxGCPause:    10 * time.Millisecond,
+		})
+
+		c.check(context.Background(), sample{goroutines: 100, heapBytes: 1 << 20, maxGCPause: 10 * time.Millisecond})
+		assert.Empty(t, log.Messages())
+
+		c.check(context.Background(), sample{goroutines: 101, heapBytes: 2 << 20, maxGCPause: 20 * time.Millisecond})
+		assert.Equal(t, []string{
+			"watchdog: goroutines count above threshold",
+			"watchdog: GC pause above threshold",
+			"watchdog: heap usage above threshold",
+		}, log.Messages())
+	})
+
+	t.Run("should ignore disabled thresholds", func(t *testing.T) {
+		c, log := newTestClient(t, &Definitions{})
+
+		c.check(context.Background(), sample{goroutines: 1 << 20, heapBytes: 1 << 40, maxGCPause: time.Hour})
+		assert.Empty(t, log.Messages())
+	})
+
+	t.Run("should dump heap profiles respecting the cooldown", func(t *testing.T) {
+		var (
+			dir    = filepath.Join(t.TempDir(), "profiles")
+			c, log = newTestClient(t, &Definitions{
+				MaxHeapBytes:    1,
+				ProfileDir:      dir,
+				ProfileCooldown: 10 * time.Minute,
+			})
+			heavy = sample{heapBytes: 2}
+		)
+
+		c.check(context.Background(), heavy)
+		c.check(context.Background(), heavy)
+
+		files, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		require.Len(t, files, 1)
+		assert.Equal(t, "heap-1767225600.pprof", files[0].Name())
+		assert.Contains(t, log.Messages(), "watchdog: heap profile written")
+
+		fake.Advance(10 * time.Minute)
+		c.check(context.Background(), heavy)
+
+		files, err = os.ReadDir(dir)
+		require.NoError(t, err)
+		assert.Len(t, files, 2)
+	})
+
+	t.Run("should report profiles that could not be written", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "file")
+		require.NoError(t, os.WriteFile(file, nil, 0o600))
+
+		c, log := newTestClient(t, &Definitions{MaxHeapBytes: 1, ProfileDir: file})
+		c.check(context.Background(), sample{heapBytes: 2})
+		assert.Contains(t, log.Messages(), "watchdog: could not write heap profile")
+	})
+}
+
+func TestWatch(t *testing.T) {
+	fake := clock.NewFake(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	defer clock.Set(fake)()
+
+	c, log := newTestClient(t, &Definitions{
+		Interval:      time.Second,
+		MaxGoroutines: 1,
+	})
+	require.NoError(t, c.Start(context.Background(), nil))
+
+	assert.Empty(t, log.Messages())
+	fake.Advance(time.Second)
+	require.Eventually(t, func() bool {
+		return len(log.Messages()) == 1
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, "watchdog: goroutines count above threshold", log.Messages()[0])
+
+	require.NoError(t, c.Cleanup(context.Background()))
+}
+
+func TestSchedulerLag(t *testing.T) {
+	fake := clock.NewFake(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	defer clock.Set(fake)()
+
+	c, log := newTestClient(t, &Definitions{
+		Interval:         time.Second,
+		LagProbeInterval: 100 * time.Millisecond,
+		MaxSchedulerLag:  50 * time.Millisecond,
+	})
+	require.NoError(t, c.Start(context.Background(), nil))
+
+	// The probe timer is received 30ms after it was due.
+	fake.Advance(130 * time.Millisecond)
+	require.Eventually(t, func() bool {
+		return c.SchedulerLag() == 30*time.Millisecond
+	}, time.Second, time.Millisecond)
+	assert.Empty(t, log.Messages())
+
+	fake.Advance(180 * time.Millisecond)
+	require.Eventually(t, func() bool {
+		return len(log.Messages()) == 1
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, 80*time.Millisecond, c.SchedulerLag())
+	assert.Equal(t, []string{"watchdog: scheduler lag above threshold"}, log.Messages())
+
+	// Measures restart from the time the timer was received.
+	fake.Advance(100 * time.Millisecond)
+	require.Eventually(t, func() bool {
+		return c.SchedulerLag() == 0
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, 80*time.Millisecond, c.MaxSchedulerLag())
+
+	require.NoError(t, c.Cleanup(context.Background()))
+}
+
+func TestCleanup(t *testing.T) {
+	t.Run("should wait for the background goroutines", func(t *testing.T) {
+		c, _ := newTestClient(t, &Definitions{LagProbeInterval: time.Millisecond})
+		require.NoError(t, c.Start(context.Background(), nil))
+
+		done := make(chan struct{})
+		go func() {
+			_ = c.Cleanup(context.Background())
+			close(done)
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("cleanup did not return")
+		}
+	})
+
+	t.Run("should do nothing when not started", func(t *testing.T) {
+		c, _ := newTestClient(t, &Definitions{})
+		assert.NoError(t, c.Cleanup(context.Background()))
+	})
+}
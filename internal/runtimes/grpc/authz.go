@@ -0,0 +1,190 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+
+	"github.com/mikros-dev/mikros/apis/integrations"
+	"github.com/mikros-dev/mikros/components/definition"
+	"github.com/mikros-dev/mikros/components/options"
+	"github.com/mikros-dev/mikros/components/plugin"
+)
+
+// methodScopes loads, from the service proto descriptor, the scopes required
+// by each of its methods, keyed by the method full name. Methods without the
+// option are not included.
+func methodScopes(
+	desc *grpc.ServiceDesc,
+	option protoreflect.ExtensionType,
+	files *protoregistry.Files,
+) (map[string][]string, error) {
+	d, err := files.FindDescriptorByName(protoreflect.FullName(desc.ServiceName))
+	if err != nil {
+		return nil, fmt.Errorf("could not find proto descriptor of service '%s': %w", desc.ServiceName, err)
+	}
+
+	sd, ok := d.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("'%s' is not a proto service", desc.ServiceName)
+	}
+
+	var (
+		scopes  = make(map[string][]string)
+		methods = sd.Methods()
+	)
+
+	for i := 0; i < methods.Len(); i++ {
+		m := methods.Get(i)
+
+		opts := m.Options()
+		if opts == nil || !proto.HasExtension(opts, option) {
+			continue
+		}
+
+		s, err := scopesFromOption(proto.GetExtension(opts, option))
+		if err != nil {
+			return nil, fmt.Errorf("invalid auth scopes of method '%s': %w", m.FullName(), err)
+		}
+		if len(s) > 0 {
+			scopes[fmt.Sprintf("/%s/%s", desc.ServiceName, m.Name())] = s
+		}
+	}
+
+	return scopes, nil
+}
+
+func scopesFromOption(value interface{}) ([]string, error) {
+	switch v := value.(type) {
+	case string:
+		if v == "" {
+			return nil, nil
+		}
+		return []string{v}, nil
+
+	case []string:
+		return v, nil
+
+	case protoreflect.List:
+		return scopesFromList(v)
+
+	case proto.Message:
+		m := v.ProtoReflect()
+		fd := m.Descriptor().Fields().ByName("scopes")
+		if fd == nil || !fd.IsList() || fd.Kind() != protoreflect.StringKind {
+			return nil, errors.New("option message must have a repeated string field named 'scopes'")
+		}
+
+		return scopesFromList(m.Get(fd).List())
+	}
+
+	return nil, fmt.Errorf("unsupported option type %T", value)
+}
+
+func scopesFromList(list protoreflect.List) ([]string, error) {
+	scopes := make([]string, 0, list.Len())
+	for i := 0; i < list.Len(); i++ {
+		s, ok := list.Get(i).Interface().(string)
+		if !ok {
+			return nil, errors.New("option list must hold strings")
+		}
+		scopes = append(scopes, s)
+	}
+
+	return scopes, nil
+}
+
+// initializeAuthz loads the methods required scopes and the integration that
+// enforces them. When no method declares scopes, the integration is not
+// required.
+func (s *Server) initializeAuthz(svc *options.GrpcServiceOptions, opt *plugin.RuntimeOptions) error {
+	if svc.AuthScopesOption == nil || s.defs.DisableAuth {
+		return nil
+	}
+
+	// If we're running tests, we won't have authenticated methods
+	if opt.Env != nil && opt.Env.DeploymentEnv() == definition.DeploymentEnvTest {
+		return nil
+	}
+
+	scopes, err := methodScopes(svc.ProtoServiceDescription, svc.AuthScopesOption, protoregistry.GlobalFiles)
+	if err != nil {
+		return err
+	}
+	if len(scopes) == 0 {
+		return nil
+	}
+
+	auth, err := requireGRPCAuth(opt)
+	if err != nil {
+		return err
+	}
+
+	s.scopes = scopes
+	s.auth = auth
+	return nil
+}
+
+func requireGRPCAuth(opt *plugin.RuntimeOptions) (integrations.GRPCAuthenticator, error) {
+	if opt.Integrations == nil {
+		return nil, errors.New("grpc methods require auth scopes but integration is not available")
+	}
+
+	i, err := opt.Integrations.Integration(options.GRPCAuthIntegrationName)
+	if err != nil {
+		return nil, errors.New("grpc methods require auth scopes but integration is not available")
+	}
+
+	auth, ok := i.API().(integrations.GRPCAuthenticator)
+	if !ok {
+		return nil, errors.New("grpc methods require auth scopes but integration does not implement GRPCAuthenticator")
+	}
+
+	return auth, nil
+}
+
+// authorize is the auth interceptor. It lets the auth integration check that
+// callers of methods declaring required scopes were granted them.
+func (s *Server) authorize(
+	ctx context.Context,
+	req interface{},
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (interface{}, error) {
+	scopes, ok := s.scopes[info.FullMethod]
+	if !ok || s.auth == nil {
+		return handler(ctx, req)
+	}
+
+	ctx, err := s.auth.Authorize(ctx, info.FullMethod, scopes)
+	if err != nil {
+		return nil, err
+	}
+
+	return handler(ctx, req)
+}
+
+// streamAuthorize does the same as authorize for streaming calls.
+func (s *Server) streamAuthorize(
+	srv interface{},
+	ss grpc.ServerStream,
+	info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler,
+) error {
+	scopes, ok := s.scopes[info.FullMethod]
+	if !ok || s.auth == nil {
+		return handler(srv, ss)
+	}
+
+	ctx, err := s.auth.Authorize(ss.Context(), info.FullMethod, scopes)
+	if err != nil {
+		return err
+	}
+
+	return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+}
@@ -0,0 +1,223 @@
+package grpc
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+	_ "google.golang.org/protobuf/types/known/emptypb"
+)
+
+// newAuthzFiles builds a registry with a service whose "Update" method
+// declares the scopes in a custom method option, as protoc would do for:
+//
+//	extend google.protobuf.MethodOptions { repeated string scopes = 50001; }
+//	service Users {
+//	  rpc Get(google.protobuf.Empty) returns (google.protobuf.Empty);
+//	  rpc Update(google.protobuf.Empty) returns (google.protobuf.Empty) {
+//	    option (scopes) = "users:write";
+//	  }
+//	}
+func newAuthzFiles(t *testing.T) (*protoregistry.Files, protoreflect.ExtensionType) {
+	optionFile, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+		Name:       proto.String("authz/options.proto"),
+		Package:    proto.String("authz"),
+		Dependency: []string{"google/protobuf/descriptor.proto"},
+		Extension: []*descriptorpb.FieldDescriptorProto{{
+			Name:     proto.String("scopes"),
+			Number:   proto.Int32(50001),
+			Label:    descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum(),
+			Type:     descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+			Extendee: proto.String(".google.protobuf.MethodOptions"),
+			JsonName: proto.String("scopes"),
+		}},
+		Syntax: proto.String("proto3"),
+	}, protoregistry.GlobalFiles)
+	require.NoError(t, err)
+
+	option := dynamicpb.NewExtensionType(optionFile.Extensions().Get(0))
+	scopes := option.New()
+	scopes.List().Append(protoreflect.ValueOfString("users:write"))
+
+	updateOptions := &descriptorpb.MethodOptions{}
+	proto.SetExtension(updateOptions, option, option.InterfaceOf(scopes))
+
+	serviceFile, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+		Name:       proto.String("users/users.proto"),
+		Package:    proto.String("users"),
+		Dependency: []string{"google/protobuf/empty.proto"},
+		Service: []*descriptorpb.ServiceDescriptorProto{{
+			Name: proto.String("Users"),
+			Method: []*descriptorpb.MethodDescriptorProto{
+				{
+					Name:       proto.String("Get"),
+					InputType:  proto.String(".google.protobuf.Empty"),
+					OutputType: proto.String(".google.protobuf.Empty"),
+				},
+				{
+					Name:       proto.String("Update"),
+					InputType:  proto.String(".google.protobuf.Empty"),
+					OutputType: proto.String(".google.protobuf.Empty"),
+					Options:    updateOptions,
+				},
+			},
+		}},
+		Syntax: proto.String("proto3"),
+	}, protoregistry.GlobalFiles)
+	require.NoError(t, err)
+
+	files := &protoregistry.Files{}
+	require.NoError(t, files.RegisterFile(serviceFile))
+
+	return files, option
+}
+
+// authorizedKey holds, in the contexts returned by fakeAuthenticator, the
+// authorized method.
+type authorizedKey struct{}
+
+type fakeAuthenticator struct {
+	granted []string
+	calls   []string
+}
+
+func (f *fakeAuthenticator) Authorize(ctx context.Context, method string, scopes []string) (context.Context, error) {
+	f.calls = append(f.calls, method)
+	if !slices.Equal(f.granted, scopes) {
+		return nil, status.Error(codes.PermissionDenied, "missing scopes")
+	}
+
+	return context.WithValue(ctx, authorizedKey{}, method), nil
+}
+
+func TestMethodScopes(t *testing.T) {
+	t.Run("should load scopes from method options", func(t *testing.T) {
+		files, option := newAuthzFiles(t)
+
+		scopes, err := methodScopes(&grpc.ServiceDesc{ServiceName: "users.Users"}, option, files)
+		require.NoError(t, err)
+		assert.Equal(t, map[string][]string{
+			"/users.Users/Update": {"users:write"},
+		}, scopes)
+	})
+
+	t.Run("should fail for unknown services", func(t *testing.T) {
+		files, option := newAuthzFiles(t)
+
+		_, err := methodScopes(&grpc.ServiceDesc{ServiceName: "users.Unknown"}, option, files)
+		assert.Error(t, err)
+	})
+}
+
+func TestAuthorize(t *testing.T) {
+	handler := func(_ context.Context, _ interface{}) (interface{}, error) {
+		return "ok", nil
+	}
+
+	t.Run("should only authorize methods declaring scopes", func(t *testing.T) {
+		var (
+			auth = &fakeAuthenticator{}
+			s    = &Server{
+				auth:   auth,
+				scopes: map[string][]string{"/users.Users/Update": {"users:write"}},
+			}
+		)
+
+		resp, err := s.authorize(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/users.Users/Get"}, handler)
+		require.NoError(t, err)
+		assert.Equal(t, "ok", resp)
+		assert.Empty(t, auth.calls)
+
+		_, err = s.authorize(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/users.Users/Update"}, handler)
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+		assert.Equal(t, []string{"/users.Users/Update"}, auth.calls)
+	})
+
+	t.Run("should call the handler when granted", func(t *testing.T) {
+		s := &Server{
+			auth:   &fakeAuthenticator{granted: []string{"users:write"}},
+			scopes: map[string][]string{"/users.Users/Update": {"users:write"}},
+		}
+
+		resp, err := s.authorize(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/users.Users/Update"}, handler)
+		require.NoError(t, err)
+		assert.Equal(t, "ok", resp)
+	})
+}
+
+func TestStreamAuthorize(t *testing.T) {
+	handler := func(_ interface{}, ss grpc.ServerStream) error {
+		return ss.Context().Err()
+	}
+
+	t.Run("should only authorize methods declaring scopes", func(t *testing.T) {
+		var (
+			auth = &fakeAuthenticator{granted: []string{"users:read"}}
+			s    = &Server{
+				auth:   auth,
+				scopes: map[string][]string{"/users.Users/Watch": {"users:write"}},
+			}
+			stream = &fakeServerStream{ctx: context.Background()}
+		)
+
+		err := s.streamAuthorize(nil, stream, &grpc.StreamServerInfo{FullMethod: "/users.Users/List"}, handler)
+		require.NoError(t, err)
+		assert.Empty(t, auth.calls)
+
+		err = s.streamAuthorize(nil, stream, &grpc.StreamServerInfo{FullMethod: "/users.Users/Watch"}, handler)
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+		assert.Equal(t, []string{"/users.Users/Watch"}, auth.calls)
+	})
+
+	t.Run("should pass the authorized context to the handler", func(t *testing.T) {
+		var (
+			auth = &fakeAuthenticator{granted: []string{"users:write"}}
+			s    = &Server{
+				auth:   auth,
+				scopes: map[string][]string{"/users.Users/Watch": {"users:write"}},
+			}
+			stream = &fakeServerStream{ctx: context.Background()}
+		)
+		err := s.streamAuthorize(nil, stream, &grpc.StreamServerInfo{FullMethod: "/users.Users/Watch"}, func(_ interface{}, ss grpc.ServerStream) error {
+			assert.Equal(t, "/users.Users/Watch", ss.Context().Value(authorizedKey{}))
+			return nil
+		})
+		require.NoError(t, err)
+	})
+}
+
+func TestScopesFromOption(t *testing.T) {
+	t.Run("should accept a single scope", func(t *testing.T) {
+		scopes, err := scopesFromOption("users:read")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"users:read"}, scopes)
+	})
+
+	t.Run("should ignore an empty scope", func(t *testing.T) {
+		scopes, err := scopesFromOption("")
+		require.NoError(t, err)
+		assert.Empty(t, scopes)
+	})
+
+	t.Run("should accept a list of scopes", func(t *testing.T) {
+		scopes, err := scopesFromOption([]string{"users:read", "users:write"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"users:read", "users:write"}, scopes)
+	})
+
+	t.Run("should fail for unsupported types", func(t *testing.T) {
+		_, err := scopesFromOption(int32(1))
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,123 @@
+package grpc
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"time"
+
+	"github.com/mikros-dev/mikros/components/definition"
+)
+
+// Definitions represents configuration options for a gRPC server.
+type Definitions struct {
+	// DisableAuth disables the enforcement of the scopes declared by the
+	// service methods proto options.
+	DisableAuth bool               `toml:"disable_auth" json:"disable_auth"`
+	GRPCWeb     GRPCWebDefinitions `toml:"grpc_web" json:"grpc_web"`
+
+	// MaxRequestBytes and MaxResponseBytes limit the size of the messages
+	// of every method. MaxRequestBytes defaults to 4MB and MaxResponseBytes
+	// to no limit.
+	MaxRequestBytes  int `toml:"max_request_bytes" json:"max_request_bytes"`
+	MaxResponseBytes int `toml:"max_response_bytes" json:"max_response_bytes"`
+
+	// Methods overrides the message size limits of specific methods, keyed
+	// by the method name:
+	//
+	//	[runtime.grpc.methods.UploadDocument]
+	//	max_request_bytes = 33554432
+	Methods map[string]MethodDefinitions `toml:"methods" json:"methods"`
+
+	// StreamGracePeriod is how long calls, mainly streaming ones, have to
+	// end after the server sends GOAWAY to its clients when stopping,
+	// before their contexts are canceled. Defaults to 10 seconds.
+	StreamGracePeriod time.Duration `toml:"stream_grace_period" json:"stream_grace_period"`
+}
+
+// MethodDefinitions holds the message size limits of a single method. Zero
+// values use the server limits.
+type MethodDefinitions struct {
+	MaxRequestBytes  int `toml:"max_request_bytes" json:"max_request_bytes"`
+	MaxResponseBytes int `toml:"max_response_bytes" json:"max_response_bytes"`
+}
+
+// GRPCWebDefinitions configures an HTTP/1.1 server that lets browser clients
+// call the service using the gRPC-Web protocol.
+type GRPCWebDefinitions struct {
+	Enabled bool  `toml:"enabled" json:"enabled"`
+	Port    int32 `toml:"port" json:"port"`
+
+	// AllowedOrigins restricts which origins can call the service. An empty
+	// list or "*" accepts any origin.
+	AllowedOrigins []string `toml:"allowed_origins" json:"allowed_origins"`
+}
+
+func newDefinitions(definitions *definition.Definitions) (*Definitions, error) {
+	out := &Definitions{
+		MaxRequestBytes: defaultMaxRequestBytes,
+	}
+	if definitions == nil {
+		return out, nil
+	}
+
+	if currentDefs, ok := definitions.LoadRuntime(definition.RuntimeTypeGRPC); ok {
+		currentDefs, err := parseDurations(currentDefs, "stream_grace_period")
+		if err != nil {
+			return nil, err
+		}
+
+		b, err := json.Marshal(currentDefs)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(b, out); err != nil {
+			return nil, err
+		}
+	}
+
+	if out.GRPCWeb.Enabled && out.GRPCWeb.Port <= 0 {
+		return nil, errors.New("grpc_web port must be set when it is enabled")
+	}
+
+	if out.MaxRequestBytes < 0 || out.MaxResponseBytes < 0 {
+		return nil, errors.New("max_request_bytes and max_response_bytes cannot be negative")
+	}
+	if out.MaxRequestBytes == 0 {
+		out.MaxRequestBytes = defaultMaxRequestBytes
+	}
+
+	for name, m := range out.Methods {
+		if m.MaxRequestBytes < 0 || m.MaxResponseBytes < 0 {
+			return nil, fmt.Errorf("method '%s' max_request_bytes and max_response_bytes cannot be negative", name)
+		}
+	}
+
+	if out.StreamGracePeriod < 0 {
+		return nil, errors.New("stream_grace_period cannot be negative")
+	}
+
+	return out, nil
+}
+
+// parseDurations converts duration settings written as strings, e.g. "10s",
+// into values that can be decoded into time.Duration fields.
+func parseDurations(defs map[string]interface{}, keys ...string) (map[string]interface{}, error) {
+	out := maps.Clone(defs)
+	for _, key := range keys {
+		s, ok := out[key].(string)
+		if !ok {
+			continue
+		}
+
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", key, err)
+		}
+
+		out[key] = int64(d)
+	}
+
+	return out, nil
+}
@@ -0,0 +1,212 @@
+package grpc
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+
+	"google.golang.org/grpc"
+)
+
+const (
+	grpcWebContentType     = "application/grpc-web"
+	grpcWebTextContentType = "application/grpc-web-text"
+	grpcTrailerPrefix      = "Trailer:"
+	grpcWebTrailerFlag     = 0x80
+)
+
+// grpcWebHandler translates gRPC-Web requests, sent by browsers over
+// HTTP/1.1, into regular gRPC calls handled by the service gRPC server.
+//
+// Both the binary (application/grpc-web) and the base64 text
+// (application/grpc-web-text) modes are supported, as well as CORS
+// preflights for them.
+type grpcWebHandler struct {
+	server         *grpc.Server
+	allowedOrigins []string
+}
+
+func (h *grpcWebHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	origin := r.Header.Get("Origin")
+	if origin != "" {
+		if !h.isOriginAllowed(origin) {
+			http.Error(w, "origin not allowed", http.StatusForbidden)
+			return
+		}
+
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+		w.Header().Add("Vary", "Origin")
+	}
+
+	if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+		h.preflight(w, r)
+		return
+	}
+
+	contentType := r.Header.Get("Content-Type")
+	if r.Method != http.MethodPost || !isGRPCWebContentType(contentType) {
+		http.Error(w, "not a gRPC-Web request", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	w.Header().Set("Access-Control-Expose-Headers", "Grpc-Status, Grpc-Message, Grpc-Status-Details-Bin")
+
+	var (
+		text    = strings.HasPrefix(contentType, grpcWebTextContentType)
+		subtype = strings.TrimPrefix(strings.TrimPrefix(contentType, grpcWebTextContentType), grpcWebContentType)
+		req     = r.Clone(r.Context())
+	)
+
+	// The gRPC server only handles HTTP/2 requests, so the request is
+	// presented as one.
+	req.ProtoMajor = 2
+	req.ProtoMinor = 0
+	req.Header.Set("Content-Type", "application/grpc"+subtype)
+	req.Header.Del("Content-Length")
+	req.ContentLength = -1
+	if text {
+		req.Body = io.NopCloser(base64.NewDecoder(base64.StdEncoding, r.Body))
+	}
+
+	rw := &grpcWebResponseWriter{
+		w:           w,
+		header:      make(http.Header),
+		contentType: contentType,
+		text:        text,
+	}
+
+	h.server.ServeHTTP(rw, req)
+	rw.finish()
+}
+
+func (h *grpcWebHandler) isOriginAllowed(origin string) bool {
+	if len(h.allowedOrigins) == 0 || slices.Contains(h.allowedOrigins, "*") {
+		return true
+	}
+
+	return slices.Contains(h.allowedOrigins, origin)
+}
+
+func (h *grpcWebHandler) preflight(w http.ResponseWriter, r *http.Request) {
+	headers := r.Header.Get("Access-Control-Request-Headers")
+	if headers == "" {
+		headers = "Content-Type, X-Grpc-Web, X-User-Agent, Grpc-Timeout"
+	}
+
+	w.Header().Set("Access-Control-Allow-Methods", http.MethodPost)
+	w.Header().Set("Access-Control-Allow-Headers", headers)
+	w.Header().Set("Access-Control-Max-Age", "600")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func isGRPCWebContentType(contentType string) bool {
+	return strings.HasPrefix(contentType, grpcWebContentType)
+}
+
+// grpcWebResponseWriter is the http.ResponseWriter handed to the gRPC server.
+// Headers are forwarded when the response starts, and everything the server
+// sets as trailers afterward is sent as a final gRPC-Web trailer frame, since
+// HTTP/1.1 clients can't read real trailers.
+type grpcWebResponseWriter struct {
+	w           http.ResponseWriter
+	header      http.Header
+	contentType string
+	text        bool
+	wroteHeader bool
+	sent        map[string]bool
+
+	// encoder encodes the whole body of text responses as a single base64
+	// stream, keeping the bytes that don't fill a base64 group between
+	// writes.
+	encoder io.WriteCloser
+}
+
+func (g *grpcWebResponseWriter) Header() http.Header {
+	return g.header
+}
+
+func (g *grpcWebResponseWriter) WriteHeader(code int) {
+	if g.wroteHeader {
+		return
+	}
+	g.wroteHeader = true
+
+	g.sent = make(map[string]bool)
+	for k, v := range g.header {
+		g.sent[k] = true
+		if k == "Trailer" || strings.HasPrefix(k, grpcTrailerPrefix) {
+			continue
+		}
+
+		g.w.Header()[k] = v
+	}
+
+	g.w.Header().Set("Content-Type", g.contentType)
+	g.w.WriteHeader(code)
+}
+
+func (g *grpcWebResponseWriter) Write(b []byte) (int, error) {
+	g.WriteHeader(http.StatusOK)
+
+	return g.body().Write(b)
+}
+
+func (g *grpcWebResponseWriter) Flush() {
+	g.WriteHeader(http.StatusOK)
+	if f, ok := g.w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// body returns where the response body is written, which, for text
+// responses, is the base64 encoder.
+func (g *grpcWebResponseWriter) body() io.Writer {
+	if !g.text {
+		return g.w
+	}
+	if g.encoder == nil {
+		g.encoder = base64.NewEncoder(base64.StdEncoding, g.w)
+	}
+
+	return g.encoder
+}
+
+// finish writes the trailer frame with the headers set after the response
+// was started.
+func (g *grpcWebResponseWriter) finish() {
+	if !g.wroteHeader {
+		// The request was rejected before any response was written, which
+		// means the gRPC server also wrote no status.
+		g.WriteHeader(http.StatusOK)
+	}
+
+	var trailers bytes.Buffer
+	for k, v := range g.header {
+		name, isTrailer := strings.CutPrefix(k, grpcTrailerPrefix)
+		if !isTrailer && (g.sent[k] || k == "Trailer") {
+			continue
+		}
+
+		for _, value := range v {
+			trailers.WriteString(strings.ToLower(name) + ": " + value + "\r\n")
+		}
+	}
+
+	frame := make([]byte, 5, 5+trailers.Len())
+	frame[0] = grpcWebTrailerFlag
+	binary.BigEndian.PutUint32(frame[1:], uint32(trailers.Len()))
+	frame = append(frame, trailers.Bytes()...)
+
+	_, _ = g.body().Write(frame)
+	if g.encoder != nil {
+		// Writes the remaining bytes with the base64 padding.
+		_ = g.encoder.Close()
+	}
+	if f, ok := g.w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
@@ -0,0 +1,165 @@
+package grpc
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/protobuf/proto"
+)
+
+func newGRPCWebTestHandler(origins ...string) *grpcWebHandler {
+	server := grpc.NewServer()
+	healthpb.RegisterHealthServer(server, health.NewServer())
+
+	return &grpcWebHandler{
+		server:         server,
+		allowedOrigins: origins,
+	}
+}
+
+func grpcWebFrame(t *testing.T, flag byte, msg proto.Message) []byte {
+	b, err := proto.Marshal(msg)
+	require.NoError(t, err)
+
+	frame := make([]byte, 5)
+	frame[0] = flag
+	binary.BigEndian.PutUint32(frame[1:], uint32(len(b)))
+
+	return append(frame, b...)
+}
+
+func readGRPCWebFrames(t *testing.T, body []byte) (message, trailers []byte) {
+	for len(body) > 0 {
+		require.GreaterOrEqual(t, len(body), 5)
+		size := binary.BigEndian.Uint32(body[1:5])
+		payload := body[5 : 5+size]
+
+		if body[0]&grpcWebTrailerFlag != 0 {
+			trailers = payload
+		} else {
+			message = payload
+		}
+
+		body = body[5+size:]
+	}
+
+	return message, trailers
+}
+
+func TestGRPCWebHandler(t *testing.T) {
+	t.Run("should answer CORS preflights", func(t *testing.T) {
+		h := newGRPCWebTestHandler("https://app.example.com")
+
+		req := httptest.NewRequest(http.MethodOptions, "/grpc.health.v1.Health/Check", nil)
+		req.Header.Set("Origin", "https://app.example.com")
+		req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+		req.Header.Set("Access-Control-Request-Headers", "content-type,x-grpc-web")
+
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+		assert.Equal(t, "https://app.example.com", rec.Header().Get("Access-Control-Allow-Origin"))
+		assert.Equal(t, "content-type,x-grpc-web", rec.Header().Get("Access-Control-Allow-Headers"))
+	})
+
+	t.Run("should reject unknown origins", func(t *testing.T) {
+		h := newGRPCWebTestHandler("https://app.example.com")
+
+		req := httptest.NewRequest(http.MethodOptions, "/grpc.health.v1.Health/Check", nil)
+		req.Header.Set("Origin", "https://evil.example.com")
+		req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+
+	t.Run("should handle binary requests", func(t *testing.T) {
+		h := newGRPCWebTestHandler()
+		body := grpcWebFrame(t, 0, &healthpb.HealthCheckRequest{})
+
+		req := httptest.NewRequest(http.MethodPost, "/grpc.health.v1.Health/Check", strings.NewReader(string(body)))
+		req.Header.Set("Content-Type", "application/grpc-web+proto")
+
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/grpc-web+proto", rec.Header().Get("Content-Type"))
+
+		message, trailers := readGRPCWebFrames(t, rec.Body.Bytes())
+		var resp healthpb.HealthCheckResponse
+		require.NoError(t, proto.Unmarshal(message, &resp))
+		assert.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.GetStatus())
+		assert.Contains(t, string(trailers), "grpc-status: 0\r\n")
+	})
+
+	t.Run("should handle text requests", func(t *testing.T) {
+		h := newGRPCWebTestHandler()
+		body := base64.StdEncoding.EncodeToString(grpcWebFrame(t, 0, &healthpb.HealthCheckRequest{
+			Service: "unknown",
+		}))
+
+		req := httptest.NewRequest(http.MethodPost, "/grpc.health.v1.Health/Check", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/grpc-web-text")
+
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/grpc-web-text", rec.Header().Get("Content-Type"))
+
+		decoded, err := base64.StdEncoding.DecodeString(rec.Body.String())
+		require.NoError(t, err)
+
+		_, trailers := readGRPCWebFrames(t, decoded)
+		assert.Contains(t, string(trailers), "grpc-status: 5\r\n")
+	})
+
+	t.Run("should encode text responses as a single base64 stream", func(t *testing.T) {
+		h := newGRPCWebTestHandler()
+		body := base64.StdEncoding.EncodeToString(grpcWebFrame(t, 0, &healthpb.HealthCheckRequest{}))
+
+		req := httptest.NewRequest(http.MethodPost, "/grpc.health.v1.Health/Check", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/grpc-web-text")
+
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.NotContains(t, strings.TrimRight(rec.Body.String(), "="), "=")
+
+		decoded, err := base64.StdEncoding.DecodeString(rec.Body.String())
+		require.NoError(t, err)
+
+		message, trailers := readGRPCWebFrames(t, decoded)
+		var resp healthpb.HealthCheckResponse
+		require.NoError(t, proto.Unmarshal(message, &resp))
+		assert.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.GetStatus())
+		assert.Contains(t, string(trailers), "grpc-status: 0\r\n")
+	})
+
+	t.Run("should reject non gRPC-Web requests", func(t *testing.T) {
+		h := newGRPCWebTestHandler()
+
+		req := httptest.NewRequest(http.MethodPost, "/grpc.health.v1.Health/Check", io.NopCloser(strings.NewReader("{}")))
+		req.Header.Set("Content-Type", "application/json")
+
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusUnsupportedMediaType, rec.Code)
+	})
+}
@@ -0,0 +1,56 @@
+package grpc
+
+import (
+	"context"
+
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/mikros-dev/mikros/components/plugin"
+)
+
+// healthServer is the gRPC health server whose serving status follows the
+// service readiness. Every service health check, including the periodic
+// ones, updates it, so watchers are notified of readiness changes.
+type healthServer struct {
+	*health.Server
+	readiness plugin.ServiceHealth
+}
+
+func newHealthServer(readiness plugin.ServiceHealth) *healthServer {
+	h := &healthServer{
+		Server:    health.NewServer(),
+		readiness: readiness,
+	}
+
+	h.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	if readiness != nil {
+		readiness.Subscribe(h.update)
+	}
+
+	return h
+}
+
+// Check checks the service readiness before answering, so the status
+// returned is always the current one.
+func (h *healthServer) Check(ctx context.Context, in *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	h.refresh(ctx)
+	return h.Server.Check(ctx, in)
+}
+
+// refresh checks the service readiness, which updates the serving status
+// through the subscription.
+func (h *healthServer) refresh(ctx context.Context) {
+	if h.readiness != nil {
+		h.readiness.Check(ctx)
+	}
+}
+
+func (h *healthServer) update(report plugin.HealthReport) {
+	status := healthpb.HealthCheckResponse_SERVING
+	if !report.Ready {
+		status = healthpb.HealthCheckResponse_NOT_SERVING
+	}
+
+	h.SetServingStatus("", status)
+}
@@ -0,0 +1,87 @@
+package grpc
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/mikros-dev/mikros/components/plugin"
+)
+
+type fakeReadiness struct {
+	mu    sync.Mutex
+	ready bool
+	subs  []func(report plugin.HealthReport)
+}
+
+func (f *fakeReadiness) Check(context.Context) plugin.HealthReport {
+	f.mu.Lock()
+	report := plugin.HealthReport{Ready: f.ready}
+	subs := f.subs
+	f.mu.Unlock()
+
+	for _, fn := range subs {
+		fn(report)
+	}
+
+	return report
+}
+
+func (f *fakeReadiness) Subscribe(fn func(report plugin.HealthReport)) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.subs = append(f.subs, fn)
+}
+
+func (f *fakeReadiness) setReady(ready bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.ready = ready
+}
+
+func checkStatus(t *testing.T, h *healthServer) healthpb.HealthCheckResponse_ServingStatus {
+	t.Helper()
+
+	res, err := h.Check(context.Background(), &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+	return res.GetStatus()
+}
+
+func TestHealthServer(t *testing.T) {
+	t.Run("should serve without a service readiness", func(t *testing.T) {
+		h := newHealthServer(nil)
+		assert.Equal(t, healthpb.HealthCheckResponse_SERVING, checkStatus(t, h))
+	})
+
+	t.Run("should follow the service readiness on checks", func(t *testing.T) {
+		var (
+			readiness = &fakeReadiness{}
+			h         = newHealthServer(readiness)
+		)
+
+		assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, checkStatus(t, h))
+
+		readiness.setReady(true)
+		assert.Equal(t, healthpb.HealthCheckResponse_SERVING, checkStatus(t, h))
+	})
+
+	t.Run("should be updated by checks made by the service", func(t *testing.T) {
+		var (
+			readiness = &fakeReadiness{ready: true}
+			h         = newHealthServer(readiness)
+		)
+
+		readiness.setReady(false)
+		readiness.Check(context.Background())
+
+		res, err := h.Server.Check(context.Background(), &healthpb.HealthCheckRequest{})
+		require.NoError(t, err)
+		assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, res.GetStatus())
+	})
+}
@@ -0,0 +1,114 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	defaultMaxRequestBytes = 4 << 20 // the same default as grpc-go
+)
+
+// messageLimits holds the message size limits of a method. Zero means no
+// limit.
+type messageLimits struct {
+	request  int
+	response int
+}
+
+// initializeLimits resolves the message size limits of every method of the
+// service and returns the server options required to enforce them. Since the
+// transport rejects messages above its own limit before any interceptor is
+// called, it is raised up to the largest method limit, while the server
+// limit is enforced by the sizeLimits interceptor.
+func (s *Server) initializeLimits(desc *grpc.ServiceDesc) ([]grpc.ServerOption, error) {
+	var (
+		defaults = messageLimits{
+			request:  s.defs.MaxRequestBytes,
+			response: s.defs.MaxResponseBytes,
+		}
+
+		limits     = make(map[string]messageLimits)
+		maxRequest = defaults.request
+	)
+
+	for name, m := range s.defs.Methods {
+		if !slices.ContainsFunc(desc.Methods, func(md grpc.MethodDesc) bool { return md.MethodName == name }) {
+			return nil, fmt.Errorf("unknown method '%s' in gRPC runtime methods definitions", name)
+		}
+
+		l := defaults
+		if m.MaxRequestBytes > 0 {
+			l.request = m.MaxRequestBytes
+		}
+		if m.MaxResponseBytes > 0 {
+			l.response = m.MaxResponseBytes
+		}
+
+		limits[fmt.Sprintf("/%s/%s", desc.ServiceName, name)] = l
+		maxRequest = max(maxRequest, l.request)
+	}
+
+	s.defaultLimits = defaults
+	s.limits = limits
+
+	return []grpc.ServerOption{
+		grpc.MaxRecvMsgSize(maxRequest),
+	}, nil
+}
+
+func (s *Server) methodLimits(method string) messageLimits {
+	if l, ok := s.limits[method]; ok {
+		return l
+	}
+
+	return s.defaultLimits
+}
+
+// sizeLimits is the interceptor that rejects requests and responses larger
+// than the limits of their methods with a ResourceExhausted error.
+func (s *Server) sizeLimits(
+	ctx context.Context,
+	req interface{},
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (interface{}, error) {
+	limits := s.methodLimits(info.FullMethod)
+	if err := checkMessageSize(info.FullMethod, "request", req, limits.request); err != nil {
+		return nil, err
+	}
+
+	resp, err := handler(ctx, req)
+	if err != nil {
+		return resp, err
+	}
+
+	if err := checkMessageSize(info.FullMethod, "response", resp, limits.response); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func checkMessageSize(method, kind string, msg interface{}, limit int) error {
+	m, ok := msg.(proto.Message)
+	if !ok || limit <= 0 {
+		return nil
+	}
+
+	if size := proto.Size(m); size > limit {
+		return status.Errorf(
+			codes.ResourceExhausted,
+			"%s message of method '%s' is larger than the limit (%d vs. %d bytes)",
+			kind, method, size, limit,
+		)
+	}
+
+	return nil
+}
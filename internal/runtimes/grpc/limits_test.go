@@ -0,0 +1,81 @@
+package grpc
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func newLimitsTestServer(t *testing.T, defs *Definitions) (*Server, []grpc.ServerOption) {
+	s := &Server{defs: defs}
+	opts, err := s.initializeLimits(&grpc.ServiceDesc{
+		ServiceName: "docs.Documents",
+		Methods: []grpc.MethodDesc{
+			{MethodName: "Get"},
+			{MethodName: "Upload"},
+		},
+	})
+	require.NoError(t, err)
+
+	return s, opts
+}
+
+func TestSizeLimits(t *testing.T) {
+	var (
+		small = wrapperspb.String(strings.Repeat("a", 10))
+		large = wrapperspb.String(strings.Repeat("a", 100))
+		echo  = func(_ context.Context, req interface{}) (interface{}, error) {
+			return req, nil
+		}
+	)
+
+	t.Run("should apply method limits over the server ones", func(t *testing.T) {
+		s, opts := newLimitsTestServer(t, &Definitions{
+			MaxRequestBytes: 50,
+			Methods: map[string]MethodDefinitions{
+				"Upload": {MaxRequestBytes: 200, MaxResponseBytes: 20},
+			},
+		})
+		assert.Len(t, opts, 1)
+
+		_, err := s.sizeLimits(context.Background(), large, &grpc.UnaryServerInfo{FullMethod: "/docs.Documents/Get"}, echo)
+		assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+		assert.Contains(t, err.Error(), "request message of method '/docs.Documents/Get'")
+
+		resp, err := s.sizeLimits(context.Background(), small, &grpc.UnaryServerInfo{FullMethod: "/docs.Documents/Get"}, echo)
+		require.NoError(t, err)
+		assert.Equal(t, small, resp)
+
+		_, err = s.sizeLimits(context.Background(), large, &grpc.UnaryServerInfo{FullMethod: "/docs.Documents/Upload"}, echo)
+		assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+		assert.Contains(t, err.Error(), "response message of method '/docs.Documents/Upload'")
+
+		resp, err = s.sizeLimits(context.Background(), small, &grpc.UnaryServerInfo{FullMethod: "/docs.Documents/Upload"}, echo)
+		require.NoError(t, err)
+		assert.Equal(t, small, resp)
+	})
+
+	t.Run("should not limit responses by default", func(t *testing.T) {
+		s, _ := newLimitsTestServer(t, &Definitions{MaxRequestBytes: defaultMaxRequestBytes})
+
+		resp, err := s.sizeLimits(context.Background(), large, &grpc.UnaryServerInfo{FullMethod: "/docs.Documents/Get"}, echo)
+		require.NoError(t, err)
+		assert.Equal(t, large, resp)
+	})
+
+	t.Run("should fail for unknown methods", func(t *testing.T) {
+		s := &Server{defs: &Definitions{
+			Methods: map[string]MethodDefinitions{"Delete": {MaxRequestBytes: 10}},
+		}}
+
+		_, err := s.initializeLimits(&grpc.ServiceDesc{ServiceName: "docs.Documents"})
+		assert.ErrorContains(t, err, "unknown method 'Delete'")
+	})
+}
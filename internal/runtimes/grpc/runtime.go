@@ -5,41 +5,61 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"net/http"
+	"strings"
+	"time"
 
 	"github.com/go-playground/validator/v10"
 	grpc_recovery "github.com/grpc-ecosystem/go-grpc-middleware/recovery"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/health"
 	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
 	"google.golang.org/grpc/status"
 
 	errors_api "github.com/mikros-dev/mikros/apis/features/errors"
 	logger_api "github.com/mikros-dev/mikros/apis/features/logger"
+	"github.com/mikros-dev/mikros/apis/integrations"
+	mcontext "github.com/mikros-dev/mikros/components/context"
 	"github.com/mikros-dev/mikros/components/definition"
+	"github.com/mikros-dev/mikros/components/downstream"
 	merrors "github.com/mikros-dev/mikros/components/errors"
 	mierrors "github.com/mikros-dev/mikros/internal/components/errors"
 	"github.com/mikros-dev/mikros/components/logger"
 	"github.com/mikros-dev/mikros/components/options"
 	"github.com/mikros-dev/mikros/components/plugin"
 	"github.com/mikros-dev/mikros/components/service"
+	"github.com/mikros-dev/mikros/internal/components/shutdown"
 )
 
 // Server represents the gRPC runtime server.
 type Server struct {
 	port             service.ServerPort
+	address          string
 	server           *grpc.Server
 	listener         net.Listener
-	health           *health.Server
+	health           *healthServer
 	errors           errors_api.Errors
 	logger           logger_api.API
 	protoServiceDesc *grpc.ServiceDesc
+	budget           downstream.Budget
+	defs             *Definitions
+	webServer        *http.Server
+	webListener      net.Listener
+	scopes           map[string][]string
+	auth             integrations.GRPCAuthenticator
+	tracker          integrations.Tracker
+	limits           map[string]messageLimits
+	defaultLimits    messageLimits
+	notifier         *shutdown.Notifier
+	gracePeriod      time.Duration
 }
 
 // New creates a new Server struct.
 func New() *Server {
-	return &Server{}
+	return &Server{
+		notifier: shutdown.NewNotifier(),
+	}
 }
 
 // Name gives the implementation runtime name.
@@ -49,15 +69,37 @@ func (s *Server) Name() string {
 
 // Info returns runtime fields to be logged.
 func (s *Server) Info() []logger_api.Attribute {
-	return []logger_api.Attribute{
-		logger.String("grpc.listening_address", fmt.Sprintf(":%v", s.port.Int32())),
+	fields := []logger_api.Attribute{
+		logger.String("grpc.listening_address", s.address),
+	}
+	if s.webServer != nil {
+		fields = append(fields, logger.String("grpc_web.listening_address", s.webServer.Addr))
 	}
+
+	return fields
+}
+
+// Addr returns the address of the runtime server listener.
+func (s *Server) Addr() net.Addr {
+	return s.listener.Addr()
 }
 
 // Run starts the gRPC server.
-func (s *Server) Run(_ context.Context, srv interface{}) error {
+func (s *Server) Run(ctx context.Context, srv interface{}) error {
 	s.server.RegisterService(s.protoServiceDesc, srv)
 	reflection.Register(s.server)
+
+	// Starts serving with the current service readiness.
+	s.health.refresh(ctx)
+
+	if s.webServer != nil {
+		go func() {
+			if err := s.webServer.Serve(s.webListener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				s.logger.Error(context.Background(), "gRPC-Web server failed", logger.Error(err))
+			}
+		}()
+	}
+
 	return s.server.Serve(s.listener)
 }
 
@@ -72,7 +114,12 @@ func (s *Server) Initialize(_ context.Context, opt *plugin.RuntimeOptions) error
 		return errors.New("unsupported RuntimeOptions received on initialization")
 	}
 
-	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", opt.Port))
+	defs, err := newDefinitions(opt.Definitions)
+	if err != nil {
+		return fmt.Errorf("invalid gRPC runtime definitions: %w", err)
+	}
+
+	listener, err := opt.Listen()
 	if err != nil {
 		return fmt.Errorf("could not listen to service port: %w", err)
 	}
@@ -82,21 +129,83 @@ func (s *Server) Initialize(_ context.Context, opt *plugin.RuntimeOptions) error
 	s.listener = listener
 	s.protoServiceDesc = svc.ProtoServiceDescription
 	s.port = opt.Port
+	s.address = opt.Address()
+	s.defs = defs
+	s.gracePeriod = streamGracePeriod(defs, svc)
+	if opt.Definitions != nil {
+		s.budget = downstream.Budget{
+			MaxCalls:    opt.Definitions.Budget.MaxCalls,
+			MaxDuration: opt.Definitions.Budget.MaxDuration,
+			Enforce:     opt.Definitions.Budget.Enforce,
+		}
+	}
+
+	if err := s.initializeAuthz(svc, opt); err != nil {
+		return err
+	}
+
+	tracker, err := getTracker(opt)
+	if err != nil {
+		return err
+	}
+	s.tracker = tracker
+
+	limitOptions, err := s.initializeLimits(svc.ProtoServiceDescription)
+	if err != nil {
+		return err
+	}
 
 	// Starts the gRPC server
-	s.server = grpc.NewServer(
+	s.server = grpc.NewServer(append(limitOptions,
+		grpc.ChainStreamInterceptor(
+			s.streamShutdownNotice,
+			s.streamHandlerInfo,
+			s.streamAuthorize,
+		),
 		grpc.ChainUnaryInterceptor(
+			s.shutdownNotice,
+			s.handlerInfo,
+			s.requestLogger,
+			s.requestLogs,
 			s.handleGRPCError,
+			s.sizeLimits,
+			s.authorize,
 			grpc_recovery.UnaryServerInterceptor(
 				grpc_recovery.WithRecoveryHandler(s.recoverFromGrpcPanic),
 			),
 		),
-	)
+	)...)
+
+	s.health = newHealthServer(opt.Health)
+	healthpb.RegisterHealthServer(s.server, s.health)
+
+	if defs.GRPCWeb.Enabled {
+		return s.initializeGRPCWeb(opt)
+	}
+
+	return nil
+}
+
+func (s *Server) initializeGRPCWeb(opt *plugin.RuntimeOptions) error {
+	// gRPC-Web listens to the same interface of the gRPC server.
+	webOpt := *opt
+	webOpt.Port = service.ServerPort(s.defs.GRPCWeb.Port)
+
+	addr := webOpt.Address()
+	listener, err := webOpt.Listen()
+	if err != nil {
+		return fmt.Errorf("could not listen to gRPC-Web port: %w", err)
+	}
 
-	healthSrv := health.NewServer()
-	healthSrv.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
-	healthpb.RegisterHealthServer(s.server, healthSrv)
-	s.health = healthSrv
+	s.webListener = listener
+	s.webServer = &http.Server{
+		Addr: addr,
+		Handler: &grpcWebHandler{
+			server:         s.server,
+			allowedOrigins: s.defs.GRPCWeb.AllowedOrigins,
+		},
+		ReadHeaderTimeout: 15 * time.Second,
+	}
 
 	return nil
 }
@@ -108,7 +217,6 @@ func (s *Server) validate(opt *plugin.RuntimeOptions) error {
 			opt.ServiceOptions,
 			opt.Logger,
 			opt.Errors,
-			opt.Port,
 		}
 	)
 
@@ -125,6 +233,111 @@ func (s *Server) recoverFromGrpcPanic(p interface{}) error {
 	return s.errors.Internal(fmt.Errorf("%v", p))
 }
 
+// handlerInfo stores the RPC method being executed and the downstream call
+// budget inside the handler context.
+func (s *Server) handlerInfo(
+	ctx context.Context,
+	req interface{},
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (interface{}, error) {
+	ctx = mcontext.WithHandlerInfo(ctx, mcontext.HandlerInfo{
+		Name:  methodName(info.FullMethod),
+		Route: info.FullMethod,
+	})
+
+	return handler(downstream.WithBudget(ctx, s.budget), req)
+}
+
+// streamHandlerInfo does the same as handlerInfo for streaming calls.
+func (s *Server) streamHandlerInfo(
+	srv interface{},
+	ss grpc.ServerStream,
+	info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler,
+) error {
+	ctx := mcontext.WithHandlerInfo(ss.Context(), mcontext.HandlerInfo{
+		Name:  methodName(info.FullMethod),
+		Route: info.FullMethod,
+	})
+
+	return handler(srv, &serverStream{ServerStream: ss, ctx: downstream.WithBudget(ctx, s.budget)})
+}
+
+// methodName gives the RPC name of a full gRPC method name.
+func methodName(fullMethod string) string {
+	if i := strings.LastIndex(fullMethod, "/"); i >= 0 {
+		return fullMethod[i+1:]
+	}
+
+	return fullMethod
+}
+
+// requestLogger stores inside the handler context a logger adding the RPC
+// method and the request tracker ID into every message, retrieved with
+// logger.FromContext.
+func (s *Server) requestLogger(
+	ctx context.Context,
+	req interface{},
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (interface{}, error) {
+	attrs := []logger_api.Attribute{
+		logger.String("grpc.method", info.FullMethod),
+	}
+	if s.tracker != nil {
+		if id, ok := s.tracker.Retrieve(ctx); ok {
+			attrs = append(attrs, logger.String("tracker.id", id))
+		}
+	}
+
+	return handler(logger.NewContext(ctx, logger.With(s.logger, attrs...)), req)
+}
+
+func getTracker(opt *plugin.RuntimeOptions) (integrations.Tracker, error) {
+	if opt.Integrations == nil {
+		return nil, nil
+	}
+
+	i, err := opt.Integrations.Integration(options.TrackerIntegrationName)
+	if err != nil {
+		return nil, nil
+	}
+
+	t, ok := i.API().(integrations.Tracker)
+	if !ok {
+		return nil, errors.New("tracker integration exists but does not implement Tracker")
+	}
+
+	return t, nil
+}
+
+// requestLogs outputs the log messages of RPCs that failed with a server
+// error, even the ones suppressed by the log level.
+func (s *Server) requestLogs(
+	ctx context.Context,
+	req interface{},
+	_ *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (interface{}, error) {
+	resp, err := handler(ctx, req)
+
+	if l, ok := s.logger.(logger_api.RequestLogs); ok {
+		l.EndRequest(ctx, isServerError(status.Code(err)))
+	}
+
+	return resp, err
+}
+
+func isServerError(code codes.Code) bool {
+	switch code {
+	case codes.Unknown, codes.Internal, codes.Unavailable, codes.DataLoss, codes.DeadlineExceeded:
+		return true
+	}
+
+	return false
+}
+
 func (s *Server) handleGRPCError(
 	ctx context.Context,
 	req interface{},
@@ -150,7 +363,7 @@ func (s *Server) handleGRPCError(
 	}
 
 	// Try to convert the error to a gRPC status.
-	st, ok, err := mierrors.ToGRPCStatus(err)
+	st, ok, err := mierrors.ToGRPCStatusFor(ctx, err)
 	if ok {
 		if err == nil {
 			return resp, st.Err()
@@ -173,8 +386,31 @@ func (s *Server) handleGRPCError(
 	return resp, status.Error(codes.Internal, "internal server error")
 }
 
-// Stop stops the gRPC server.
-func (s *Server) Stop(_ context.Context) error {
-	// Nothing to do here
-	return nil
+// Stop stops the gRPC server, waiting for pending calls until ctx is done,
+// and the gRPC-Web server, when it is enabled. Clients receive GOAWAY and
+// handlers are notified right away, while calls still running after the
+// stream grace period are canceled.
+func (s *Server) Stop(ctx context.Context) error {
+	s.notifier.Shutdown(s.gracePeriod)
+
+	var err error
+	if s.webServer != nil {
+		err = s.webServer.Shutdown(ctx)
+	}
+
+	if s.server != nil {
+		stopped := make(chan struct{})
+		go func() {
+			s.server.GracefulStop()
+			close(stopped)
+		}()
+
+		select {
+		case <-stopped:
+		case <-ctx.Done():
+			s.server.Stop()
+		}
+	}
+
+	return err
 }
@@ -0,0 +1,35 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	mcontext "github.com/mikros-dev/mikros/components/context"
+	"github.com/mikros-dev/mikros/components/downstream"
+)
+
+func TestStreamHandlerInfo(t *testing.T) {
+	t.Run("should store the handler info and the downstream budget in stream contexts", func(t *testing.T) {
+		var (
+			s      = &Server{budget: downstream.Budget{MaxCalls: 1, Enforce: true}}
+			stream = &fakeServerStream{ctx: context.Background()}
+			info   = &grpc.StreamServerInfo{FullMethod: "/users.Users/Watch"}
+		)
+
+		err := s.streamHandlerInfo(nil, stream, info, func(_ interface{}, ss grpc.ServerStream) error {
+			handler, ok := mcontext.HandlerInfoFromContext(ss.Context())
+			require.True(t, ok)
+			assert.Equal(t, "Watch", handler.Name)
+			assert.Equal(t, "/users.Users/Watch", handler.Route)
+
+			_, ok = downstream.BudgetUsageFromContext(ss.Context())
+			assert.True(t, ok)
+			return nil
+		})
+		assert.NoError(t, err)
+	})
+}
@@ -0,0 +1,67 @@
+package grpc
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"github.com/mikros-dev/mikros/components/options"
+)
+
+const (
+	defaultStreamGracePeriod = 10 * time.Second
+)
+
+// streamGracePeriod gives the grace period of the definitions file, falling
+// back to the one of the service options.
+func streamGracePeriod(defs *Definitions, svc *options.GrpcServiceOptions) time.Duration {
+	if defs.StreamGracePeriod > 0 {
+		return defs.StreamGracePeriod
+	}
+	if svc.StreamGracePeriod > 0 {
+		return svc.StreamGracePeriod
+	}
+
+	return defaultStreamGracePeriod
+}
+
+// shutdownNotice lets unary calls know when the server starts stopping,
+// through mcontext.ShutdownNotice, and cancels the ones still running after
+// the stream grace period.
+func (s *Server) shutdownNotice(
+	ctx context.Context,
+	req interface{},
+	_ *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (interface{}, error) {
+	ctx, cancel := s.notifier.Context(ctx)
+	defer cancel()
+
+	return handler(ctx, req)
+}
+
+// streamShutdownNotice does the same as shutdownNotice for streaming calls,
+// which usually last longer.
+func (s *Server) streamShutdownNotice(
+	srv interface{},
+	ss grpc.ServerStream,
+	_ *grpc.StreamServerInfo,
+	handler grpc.StreamHandler,
+) error {
+	ctx, cancel := s.notifier.Context(ss.Context())
+	defer cancel()
+
+	return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+}
+
+// serverStream replaces the context of a grpc.ServerStream.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context returns the stream context.
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,78 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	mcontext "github.com/mikros-dev/mikros/components/context"
+	"github.com/mikros-dev/mikros/components/definition"
+	"github.com/mikros-dev/mikros/components/options"
+	"github.com/mikros-dev/mikros/internal/components/shutdown"
+)
+
+type fakeServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (f *fakeServerStream) Context() context.Context {
+	return f.ctx
+}
+
+func TestStreamGracePeriod(t *testing.T) {
+	t.Run("should prefer the definitions over the service options", func(t *testing.T) {
+		defs := &Definitions{StreamGracePeriod: time.Minute}
+		assert.Equal(t, time.Minute, streamGracePeriod(defs, &options.GrpcServiceOptions{StreamGracePeriod: time.Second}))
+	})
+
+	t.Run("should use the service options and the default", func(t *testing.T) {
+		assert.Equal(t, time.Second, streamGracePeriod(&Definitions{}, &options.GrpcServiceOptions{StreamGracePeriod: time.Second}))
+		assert.Equal(t, defaultStreamGracePeriod, streamGracePeriod(&Definitions{}, &options.GrpcServiceOptions{}))
+	})
+
+	t.Run("should parse durations of the definitions file", func(t *testing.T) {
+		defs, err := newDefinitions(&definition.Definitions{
+			Runtime: map[string]map[string]interface{}{
+				"grpc": {"stream_grace_period": "30s"},
+			},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 30*time.Second, defs.StreamGracePeriod)
+
+		_, err = newDefinitions(&definition.Definitions{
+			Runtime: map[string]map[string]interface{}{
+				"grpc": {"stream_grace_period": "soon"},
+			},
+		})
+		assert.ErrorContains(t, err, "invalid stream_grace_period")
+	})
+}
+
+func TestStreamShutdownNotice(t *testing.T) {
+	t.Run("should notify streams and cancel them after the grace period", func(t *testing.T) {
+		var (
+			s        = &Server{notifier: shutdown.NewNotifier()}
+			notified = make(chan struct{})
+			stream   = &fakeServerStream{ctx: context.Background()}
+		)
+
+		go func() {
+			<-notified
+			s.notifier.Shutdown(10 * time.Millisecond)
+		}()
+
+		err := s.streamShutdownNotice(nil, stream, nil, func(_ interface{}, ss grpc.ServerStream) error {
+			close(notified)
+			<-mcontext.ShutdownNotice(ss.Context())
+			<-ss.Context().Done()
+			return ss.Context().Err()
+		})
+
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
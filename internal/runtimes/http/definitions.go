@@ -2,6 +2,8 @@ package http
 
 import (
 	"encoding/json"
+	"fmt"
+	"maps"
 	"reflect"
 	"strings"
 	"time"
@@ -10,6 +12,7 @@ import (
 
 	"github.com/mikros-dev/mikros/components/definition"
 	"github.com/mikros-dev/mikros/components/options"
+	"github.com/mikros-dev/mikros/components/plugin"
 )
 
 // Definitions represents configuration options for an HTTP server.
@@ -21,9 +24,26 @@ type Definitions struct {
 	WriteTimeout   time.Duration `toml:"write_timeout" json:"write_timeout" default:"15s"`
 	IdleTimeout    time.Duration `toml:"idle_timeout" json:"idle_timeout" default:"60s"`
 	MaxHeaderBytes int           `toml:"max_header_bytes" json:"max_header_bytes" default:"1048576"`
+
+	// ShutdownTimeout limits how long the server waits for in-flight
+	// requests when stopping, while their number is logged at every
+	// DrainLogInterval.
+	ShutdownTimeout  time.Duration `toml:"shutdown_timeout" json:"shutdown_timeout" default:"30s"`
+	DrainLogInterval time.Duration `toml:"drain_log_interval" json:"drain_log_interval" default:"5s"`
+
+	// StreamGracePeriod is how long requests have to end, after being
+	// notified that the server is shutting down, before they are canceled.
+	// It lets long-lived ones, like event streams, tell their clients to
+	// reconnect elsewhere instead of having their connections reset.
+	StreamGracePeriod time.Duration `toml:"stream_grace_period" json:"stream_grace_period" default:"10s"`
+
+	// Middlewares enables built-in middlewares, in the order they must be
+	// composed.
+	Middlewares []string             `toml:"middlewares" json:"middlewares"`
+	RateLimit   RateLimitDefinitions `toml:"rate_limit" json:"rate_limit"`
 }
 
-func newDefinitions(definitions *definition.Definitions, opt *options.HTTPServiceOptions) *Definitions {
+func newDefinitions(definitions *definition.Definitions, opt *options.HTTPServiceOptions) (*Definitions, error) {
 	out := &Definitions{}
 	_ = defaults.Set(out)
 
@@ -39,28 +59,156 @@ func newDefinitions(definitions *definition.Definitions, opt *options.HTTPServic
 
 	// Apply file definitions
 	if currentDefs, ok := definitions.LoadRuntime(definition.RuntimeTypeHTTP); ok {
-		handleFileDefinitions(currentDefs, out)
+		if err := handleFileDefinitions(currentDefs, out); err != nil {
+			return nil, err
+		}
 	}
 
-	return out
+	return out, nil
 }
 
-func handleFileDefinitions(currentDefs map[string]interface{}, out *Definitions) {
-	if b, err := json.Marshal(currentDefs); err == nil {
-		var defs Definitions
-		if json.Unmarshal(b, &defs) == nil {
-			// File version of the following settings always wins
-			out.DisableAuth = defs.DisableAuth
-			out.CORSStrict = defs.CORSStrict
-
-			// Only use the file version if it's not empty'
-			if defs.BasePath != "" {
-				out.BasePath = normalizeBasePath(defs.BasePath)
-			}
+func handleFileDefinitions(currentDefs map[string]interface{}, out *Definitions) error {
+	defs, err := decodeFileDefinitions(currentDefs)
+	if err != nil {
+		return err
+	}
+
+	// File version of the following settings always wins
+	out.DisableAuth = defs.DisableAuth
+	out.CORSStrict = defs.CORSStrict
 
-			mergeNonZero(out, &defs)
+	// Only use the file version if it's not empty'
+	if defs.BasePath != "" {
+		out.BasePath = normalizeBasePath(defs.BasePath)
+	}
+	if len(defs.Middlewares) > 0 {
+		out.Middlewares = defs.Middlewares
+	}
+	if defs.RateLimit != (RateLimitDefinitions{}) {
+		out.RateLimit = defs.RateLimit
+	}
+
+	mergeNonZero(out, defs)
+	return nil
+}
+
+func decodeFileDefinitions(currentDefs map[string]interface{}) (*Definitions, error) {
+	currentDefs, err := parseDurations(currentDefs)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := json.Marshal(currentDefs)
+	if err != nil {
+		return nil, err
+	}
+
+	var defs Definitions
+	if err := json.Unmarshal(b, &defs); err != nil {
+		return nil, fmt.Errorf("invalid runtime.http definitions: %w", err)
+	}
+
+	return &defs, nil
+}
+
+// parseDurations converts the duration settings written as strings, e.g.
+// "10s", into values that can be decoded into the time.Duration fields of
+// Definitions.
+func parseDurations(defs map[string]interface{}) (map[string]interface{}, error) {
+	var (
+		out = maps.Clone(defs)
+		t   = reflect.TypeOf(Definitions{})
+	)
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Type != reflect.TypeOf(time.Duration(0)) {
+			continue
 		}
+
+		key, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		s, ok := out[key].(string)
+		if !ok {
+			continue
+		}
+
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid runtime.http.%s: %w", key, err)
+		}
+
+		out[key] = int64(d)
+	}
+
+	return out, nil
+}
+
+// effectiveConfig reports the merged definitions annotating each setting with
+// the source whose value was used, following the same precedence used by
+// newDefinitions.
+func effectiveConfig(
+	defs *Definitions,
+	opt *options.HTTPServiceOptions,
+	fileDefs map[string]interface{},
+) []plugin.ConfigEntry {
+	var (
+		entries []plugin.ConfigEntry
+		dv      = reflect.ValueOf(defs).Elem()
+		dt      = dv.Type()
+		fv      reflect.Value
+	)
+
+	if fileDefs != nil {
+		if decoded, err := decodeFileDefinitions(fileDefs); err == nil {
+			fv = reflect.ValueOf(decoded).Elem()
+		}
+	}
+
+	for i := 0; i < dt.NumField(); i++ {
+		var (
+			field  = dt.Field(i)
+			key, _ = strings.CutSuffix(field.Tag.Get("json"), ",omitempty")
+			source = plugin.ConfigSourceDefault
+		)
+
+		if fv.IsValid() && (isFileOnlySetting(field.Name) || isMergedValue(fv.Field(i))) {
+			source = plugin.ConfigSourceDefinitions
+		} else if opt != nil {
+			if of := reflect.ValueOf(opt).Elem().FieldByName(field.Name); of.IsValid() && of.Type() == field.Type && isMergedValue(of) {
+				source = plugin.ConfigSourceOptions
+			}
+		}
+
+		entries = append(entries, plugin.ConfigEntry{
+			Key:    "runtime.http." + key,
+			Value:  dv.Field(i).Interface(),
+			Source: source,
+		})
 	}
+
+	return entries
+}
+
+// isMergedValue tells if a setting value replaces the one already set when
+// merging the definitions: numbers must be positive, and other values must
+// not be empty.
+func isMergedValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		return v.Int() > 0
+	case reflect.Slice, reflect.Map:
+		return v.Len() > 0
+	case reflect.Bool:
+		return false
+	default:
+		return !v.IsZero()
+	}
+}
+
+// isFileOnlySetting tells if a setting always uses the file value when the
+// runtime has definitions in the 'service.toml' file.
+func isFileOnlySetting(name string) bool {
+	return name == "DisableAuth" || name == "CORSStrict"
 }
 
 // normalizeBasePath ensures a leading "/" and trims trailing "/".
@@ -0,0 +1,127 @@
+package http
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mikros-dev/mikros/components/definition"
+	"github.com/mikros-dev/mikros/components/options"
+	"github.com/mikros-dev/mikros/components/plugin"
+)
+
+func TestEffectiveConfig(t *testing.T) {
+	defs := &Definitions{
+		BasePath:    "/api",
+		ReadTimeout: 5 * time.Second,
+		IdleTimeout: time.Minute,
+	}
+
+	tests := []struct {
+		name     string
+		opt      *options.HTTPServiceOptions
+		fileDefs map[string]interface{}
+		sources  map[string]plugin.ConfigSource
+	}{
+		{
+			name: "without file definitions nor options",
+			sources: map[string]plugin.ConfigSource{
+				"runtime.http.base_path":    plugin.ConfigSourceDefault,
+				"runtime.http.read_timeout": plugin.ConfigSourceDefault,
+				"runtime.http.cors_strict":  plugin.ConfigSourceDefault,
+			},
+		},
+		{
+			name: "with options",
+			opt:  &options.HTTPServiceOptions{BasePath: "/api"},
+			sources: map[string]plugin.ConfigSource{
+				"runtime.http.base_path":    plugin.ConfigSourceOptions,
+				"runtime.http.read_timeout": plugin.ConfigSourceDefault,
+			},
+		},
+		{
+			name:     "with file definitions overriding options",
+			opt:      &options.HTTPServiceOptions{BasePath: "/api"},
+			fileDefs: map[string]interface{}{"base_path": "/api", "read_timeout": "5s"},
+			sources: map[string]plugin.ConfigSource{
+				"runtime.http.base_path":    plugin.ConfigSourceDefinitions,
+				"runtime.http.read_timeout": plugin.ConfigSourceDefinitions,
+				"runtime.http.idle_timeout": plugin.ConfigSourceDefault,
+				"runtime.http.cors_strict":  plugin.ConfigSourceDefinitions,
+				"runtime.http.disable_auth": plugin.ConfigSourceDefinitions,
+			},
+		},
+		{
+			name:     "with file definitions ignored by the merge",
+			opt:      &options.HTTPServiceOptions{BasePath: "/api"},
+			fileDefs: map[string]interface{}{"base_path": "", "read_timeout": -1, "idle_timeout": 0},
+			sources: map[string]plugin.ConfigSource{
+				"runtime.http.base_path":    plugin.ConfigSourceOptions,
+				"runtime.http.read_timeout": plugin.ConfigSourceDefault,
+				"runtime.http.idle_timeout": plugin.ConfigSourceDefault,
+				"runtime.http.cors_strict":  plugin.ConfigSourceDefinitions,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries := make(map[string]plugin.ConfigEntry)
+			for _, e := range effectiveConfig(defs, tt.opt, tt.fileDefs) {
+				entries[e.Key] = e
+			}
+
+			for key, source := range tt.sources {
+				e, ok := entries[key]
+				require.True(t, ok, key)
+				assert.Equal(t, source, e.Source, key)
+			}
+
+			assert.Equal(t, "/api", entries["runtime.http.base_path"].Value)
+			assert.Equal(t, 5*time.Second, entries["runtime.http.read_timeout"].Value)
+		})
+	}
+}
+
+func TestNewDefinitions(t *testing.T) {
+	t.Run("should parse durations written as strings", func(t *testing.T) {
+		defs, err := newDefinitions(&definition.Definitions{
+			Types: []string{"http"},
+			Runtime: map[string]map[string]interface{}{
+				"http": {
+					"read_timeout": "5s",
+					"idle_timeout": int64(2 * time.Minute),
+					"base_path":    "api",
+				},
+			},
+		}, nil)
+		require.NoError(t, err)
+		assert.Equal(t, 5*time.Second, defs.ReadTimeout)
+		assert.Equal(t, 2*time.Minute, defs.IdleTimeout)
+		assert.Equal(t, 15*time.Second, defs.WriteTimeout)
+		assert.Equal(t, "/api", defs.BasePath)
+	})
+
+	t.Run("should keep the defaults of non-positive durations", func(t *testing.T) {
+		defs, err := newDefinitions(&definition.Definitions{
+			Types: []string{"http"},
+			Runtime: map[string]map[string]interface{}{
+				"http": {"read_timeout": "-1s"},
+			},
+		}, nil)
+		require.NoError(t, err)
+		assert.Equal(t, 15*time.Second, defs.ReadTimeout)
+	})
+
+	t.Run("should fail with invalid durations", func(t *testing.T) {
+		_, err := newDefinitions(&definition.Definitions{
+			Types: []string{"http"},
+			Runtime: map[string]map[string]interface{}{
+				"http": {"read_timeout": "5 seconds"},
+			},
+		}, nil)
+		assert.ErrorContains(t, err, "runtime.http.read_timeout")
+	})
+}
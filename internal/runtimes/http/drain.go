@@ -0,0 +1,106 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	logger_api "github.com/mikros-dev/mikros/apis/features/logger"
+	"github.com/mikros-dev/mikros/components/logger"
+	"github.com/mikros-dev/mikros/internal/components/shutdown"
+)
+
+// inFlightRequests counts the requests being handled by the server, so its
+// shutdown can report how many are still being drained. It must be the
+// outermost middleware.
+func inFlightRequests(counter *atomic.Int64) middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			counter.Add(1)
+			defer counter.Add(-1)
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// shutdownNotice lets requests know when the server starts shutting down,
+// through mcontext.ShutdownNotice, and cancels the ones still running after
+// the stream grace period.
+func shutdownNotice(notifier *shutdown.Notifier) middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := notifier.Context(r.Context())
+			defer cancel()
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// InFlight returns the number of requests currently being handled.
+func (s *Server) InFlight() int64 {
+	return s.inFlight.Load()
+}
+
+// shutdown gracefully stops the server, waiting for in-flight requests up to
+// the shutdown timeout and logging the drain progress meanwhile. Long-lived
+// requests, such as event streams, are notified right away and canceled
+// after the stream grace period. Connections still open after the timeout
+// are closed.
+func (s *Server) shutdown(ctx context.Context) error {
+	var (
+		start = time.Now()
+		done  = make(chan struct{})
+		log   = s.logger
+	)
+	if log == nil {
+		log = logger.FromContext(ctx)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.defs.ShutdownTimeout)
+	defer cancel()
+
+	s.notifier.Shutdown(s.defs.StreamGracePeriod)
+	go s.logDrainProgress(ctx, log, start, done)
+	err := s.server.Shutdown(ctx)
+	close(done)
+
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+		log.Warn(ctx, "http server shutdown timed out, closing remaining connections",
+			logger.Any("http.in_flight", s.InFlight()),
+			logger.String("http.drain_duration", time.Since(start).String()),
+		)
+
+		_ = s.server.Close()
+		return err
+	}
+
+	log.Info(ctx, "http server drained", logger.String("http.drain_duration", time.Since(start).String()))
+	return err
+}
+
+func (s *Server) logDrainProgress(ctx context.Context, log logger_api.API, start time.Time, done <-chan struct{}) {
+	if s.defs.DrainLogInterval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(s.defs.DrainLogInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			log.Info(ctx, "draining http requests",
+				logger.Any("http.in_flight", s.InFlight()),
+				logger.String("http.drain_duration", time.Since(start).String()),
+			)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package http
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	logger_api "github.com/mikros-dev/mikros/apis/features/logger"
+	mcontext "github.com/mikros-dev/mikros/components/context"
+	"github.com/mikros-dev/mikros/internal/components/shutdown"
+)
+
+type drainLogger struct {
+	logger_api.API
+	mu       sync.Mutex
+	messages []string
+}
+
+func (d *drainLogger) Info(_ context.Context, msg string, _ ...logger_api.Attribute) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.messages = append(d.messages, msg)
+}
+
+func (d *drainLogger) Warn(ctx context.Context, msg string, attrs ...logger_api.Attribute) {
+	d.Info(ctx, msg, attrs...)
+}
+
+func (d *drainLogger) Messages() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]string{}, d.messages...)
+}
+
+func TestInFlightRequests(t *testing.T) {
+	t.Run("should count the requests being handled", func(t *testing.T) {
+		var (
+			counter atomic.Int64
+			during  int64
+			handler = inFlightRequests(&counter)(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+				during = counter.Load()
+			}))
+		)
+
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+		assert.Equal(t, int64(1), during)
+		assert.Zero(t, counter.Load())
+	})
+}
+
+func TestShutdown(t *testing.T) {
+	newServer := func(t *testing.T, handler http.Handler, defs *Definitions) (*Server, *drainLogger) {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+
+		var (
+			log = &drainLogger{}
+			s   = &Server{
+				listener: listener,
+				defs:     defs,
+				logger:   log,
+				notifier: shutdown.NewNotifier(),
+			}
+		)
+
+		s.server = &http.Server{Handler: inFlightRequests(&s.inFlight)(shutdownNotice(s.notifier)(handler))}
+		go func() { _ = s.Run(context.Background(), nil) }()
+
+		return s, log
+	}
+
+	t.Run("should wait for in-flight requests and log the drain progress", func(t *testing.T) {
+		var (
+			started = make(chan struct{})
+			s, log  = newServer(t, http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+				close(started)
+				time.Sleep(100 * time.Millisecond)
+			}), &Definitions{ShutdownTimeout: time.Second, DrainLogInterval: 20 * time.Millisecond})
+		)
+
+		go func() { _, _ = http.Get("http://" + s.listener.Addr().String()) }()
+		<-started
+		assert.Equal(t, int64(1), s.InFlight())
+
+		require.NoError(t, s.Stop(context.Background()))
+		assert.Zero(t, s.InFlight())
+		assert.Contains(t, log.Messages(), "draining http requests")
+		assert.Contains(t, log.Messages(), "http server drained")
+	})
+
+	t.Run("should close the connections after the shutdown timeout", func(t *testing.T) {
+		var (
+			started = make(chan struct{})
+			release = make(chan struct{})
+			s, log  = newServer(t, http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+				close(started)
+				<-release
+			}), &Definitions{ShutdownTimeout: 50 * time.Millisecond})
+		)
+		defer close(release)
+
+		go func() { _, _ = http.Get("http://" + s.listener.Addr().String()) }()
+		<-started
+
+		err := s.Stop(context.Background())
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Equal(t, []string{"http server shutdown timed out, closing remaining connections"}, log.Messages())
+	})
+
+	t.Run("should notify long-lived requests and cancel them after the grace period", func(t *testing.T) {
+		var (
+			started  = make(chan struct{})
+			notified = make(chan time.Time, 1)
+			canceled = make(chan time.Time, 1)
+			s, _     = newServer(t, http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+				close(started)
+				<-mcontext.ShutdownNotice(r.Context())
+				notified <- time.Now()
+				<-r.Context().Done()
+				canceled <- time.Now()
+			}), &Definitions{ShutdownTimeout: time.Second, StreamGracePeriod: 100 * time.Millisecond})
+		)
+
+		go func() { _, _ = http.Get("http://" + s.listener.Addr().String()) }()
+		<-started
+
+		require.NoError(t, s.Stop(context.Background()))
+		assert.GreaterOrEqual(t, (<-canceled).Sub(<-notified), 100*time.Millisecond)
+	})
+}
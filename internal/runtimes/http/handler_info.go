@@ -0,0 +1,101 @@
+package http
+
+import (
+	"net/http"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync"
+
+	mcontext "github.com/mikros-dev/mikros/components/context"
+	"github.com/mikros-dev/mikros/components/definition"
+	"github.com/mikros-dev/mikros/components/downstream"
+)
+
+// handlerInfo wraps the service handler so that the context of every request
+// carries the route template that matched it and, when the service handler
+// is an http.ServeMux, the name of the function registered for it.
+func handlerInfo(next http.Handler) http.Handler {
+	mux, _ := next.(*http.ServeMux)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var (
+			mu   sync.Mutex
+			info mcontext.HandlerInfo
+			req  *http.Request
+		)
+
+		// The request is routed by the mux after being stored here, so the
+		// information is only resolved when someone asks for it, and kept
+		// once the route is known.
+		req = r.WithContext(mcontext.WithHandlerInfoFunc(r.Context(), func() mcontext.HandlerInfo {
+			mu.Lock()
+			defer mu.Unlock()
+
+			if info.Route != "" {
+				return info
+			}
+
+			info.Route = req.Pattern
+			if mux != nil {
+				h, pattern := mux.Handler(req)
+				info.Name = handlerName(h)
+				if info.Route == "" {
+					info.Route = pattern
+				}
+			}
+
+			return info
+		}))
+
+		next.ServeHTTP(w, req)
+	})
+}
+
+// handlerName returns the name of the function behind h, without its package
+// path.
+func handlerName(h http.Handler) string {
+	if h == nil {
+		return ""
+	}
+
+	v := reflect.ValueOf(h)
+	if v.Kind() != reflect.Func {
+		return reflect.Indirect(v).Type().Name()
+	}
+
+	fn := runtime.FuncForPC(v.Pointer())
+	if fn == nil {
+		return ""
+	}
+
+	name := strings.TrimSuffix(fn.Name(), "-fm")
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	if _, after, ok := strings.Cut(name, "."); ok {
+		name = after
+	}
+	if i := strings.LastIndex(name, ")."); i >= 0 {
+		name = name[i+2:]
+	}
+
+	return name
+}
+
+// downstreamBudget limits the downstream calls of every request according to
+// the service definitions.
+func downstreamBudget(defs definition.DownstreamBudget, next http.Handler) http.Handler {
+	budget := downstream.Budget{
+		MaxCalls:    defs.MaxCalls,
+		MaxDuration: defs.MaxDuration,
+		Enforce:     defs.Enforce,
+	}
+	if budget.IsZero() {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(downstream.WithBudget(r.Context(), budget)))
+	})
+}
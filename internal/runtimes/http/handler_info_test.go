@@ -0,0 +1,70 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	mcontext "github.com/mikros-dev/mikros/components/context"
+)
+
+type userHandlers struct {
+	info mcontext.HandlerInfo
+	ok   bool
+}
+
+func (u *userHandlers) GetUser(_ http.ResponseWriter, r *http.Request) {
+	u.info, u.ok = mcontext.HandlerInfoFromContext(r.Context())
+}
+
+func TestHandlerInfo(t *testing.T) {
+	t.Run("should store the matched route and handler name", func(t *testing.T) {
+		var (
+			u   = &userHandlers{}
+			mux = http.NewServeMux()
+		)
+		mux.HandleFunc("GET /users/{id}", u.GetUser)
+
+		rec := httptest.NewRecorder()
+		handlerInfo(mux).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users/42", nil))
+
+		assert.True(t, u.ok)
+		assert.Equal(t, "GET /users/{id}", u.info.Route)
+		assert.Equal(t, "GetUser", u.info.Name)
+	})
+
+	t.Run("should store the matched route for other handlers", func(t *testing.T) {
+		var (
+			u   = &userHandlers{}
+			mux = http.NewServeMux()
+		)
+		mux.HandleFunc("/users/", u.GetUser)
+
+		rec := httptest.NewRecorder()
+		h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mux.ServeHTTP(w, r)
+		})
+		handlerInfo(h).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users/42", nil))
+
+		assert.True(t, u.ok)
+		assert.Equal(t, "/users/", u.info.Route)
+		assert.Empty(t, u.info.Name)
+	})
+
+	t.Run("should not report information before routing", func(t *testing.T) {
+		var (
+			info mcontext.HandlerInfo
+			ok   bool
+		)
+
+		h := http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+			info, ok = mcontext.HandlerInfoFromContext(r.Context())
+		})
+		handlerInfo(h).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+		assert.False(t, ok)
+		assert.Empty(t, info)
+	})
+}
@@ -0,0 +1,370 @@
+package http
+
+import (
+	"compress/gzip"
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	logger_api "github.com/mikros-dev/mikros/apis/features/logger"
+	"github.com/mikros-dev/mikros/apis/integrations"
+	mhttp "github.com/mikros-dev/mikros/components/http"
+	"github.com/mikros-dev/mikros/components/logger"
+)
+
+// Built-in middlewares that can be enabled through the 'service.toml' file.
+const (
+	securityHeadersMiddleware = "security_headers"
+	accessLogMiddleware       = "access_log"
+	compressionMiddleware     = "compression"
+	rateLimitMiddleware       = "rate_limit"
+)
+
+// RateLimitDefinitions configures the rate_limit built-in middleware.
+type RateLimitDefinitions struct {
+	RequestsPerSecond float64 `toml:"requests_per_second" json:"requests_per_second"`
+	Burst             int     `toml:"burst" json:"burst"`
+}
+
+// buildMiddlewares creates the built-in middlewares enabled in the
+// definitions, in the same order they were declared. The first one becomes
+// the outermost wrapper.
+func buildMiddlewares(log logger_api.API, defs *Definitions) ([]middleware, error) {
+	var (
+		chain []middleware
+		seen  = make(map[string]bool)
+	)
+
+	for _, name := range defs.Middlewares {
+		if seen[name] {
+			return nil, fmt.Errorf("middleware '%s' is enabled more than once", name)
+		}
+		seen[name] = true
+
+		switch name {
+		case securityHeadersMiddleware:
+			chain = append(chain, securityHeaders)
+		case accessLogMiddleware:
+			chain = append(chain, accessLog(log))
+		case compressionMiddleware:
+			chain = append(chain, compression)
+		case rateLimitMiddleware:
+			if defs.RateLimit.RequestsPerSecond <= 0 {
+				return nil, fmt.Errorf("middleware '%s' requires requests_per_second to be greater than zero", name)
+			}
+
+			chain = append(chain, rateLimit(defs.RateLimit))
+		default:
+			return nil, fmt.Errorf("unknown middleware '%s'", name)
+		}
+	}
+
+	return chain, nil
+}
+
+func securityHeaders(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h := w.Header()
+		h.Set("X-Content-Type-Options", "nosniff")
+		h.Set("X-Frame-Options", "DENY")
+		h.Set("Referrer-Policy", "no-referrer")
+
+		if r.TLS != nil || strings.EqualFold(r.Header.Get("X-Forwarded-Proto"), "https") {
+			h.Set("Strict-Transport-Security", "max-age=63072000; includeSubDomains")
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// statusRecorder captures the status code and the size of a response.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (s *statusRecorder) WriteHeader(code int) {
+	if s.status == 0 {
+		s.status = code
+	}
+
+	s.ResponseWriter.WriteHeader(code)
+}
+
+func (s *statusRecorder) Write(b []byte) (int, error) {
+	if s.status == 0 {
+		s.status = http.StatusOK
+	}
+
+	n, err := s.ResponseWriter.Write(b)
+	s.bytes += n
+	return n, err
+}
+
+func (s *statusRecorder) Unwrap() http.ResponseWriter {
+	return s.ResponseWriter
+}
+
+// accessLogContext holds the request context seen by the inner middlewares,
+// which the access log, being an outer one, can't see otherwise.
+type accessLogContext struct {
+	ctx context.Context
+}
+
+type accessLogContextKey struct{}
+
+// accessLog outputs one message for every request using its context, as
+// completed by requestLogger, so the message carries the request-scoped
+// attributes, like its tracker ID.
+func accessLog(log logger_api.API) middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var (
+				start = time.Now()
+				rec   = &statusRecorder{ResponseWriter: w}
+				slot  = &accessLogContext{ctx: r.Context()}
+			)
+
+			next.ServeHTTP(rec, r.WithContext(context.WithValue(r.Context(), accessLogContextKey{}, slot)))
+
+			if rec.status == 0 {
+				rec.status = http.StatusOK
+			}
+
+			log.Info(slot.ctx, "http request",
+				logger.String("http.method", r.Method),
+				logger.String("http.path", r.URL.Path),
+				logger.Any("http.status", rec.status),
+				logger.Any("http.response_bytes", rec.bytes),
+				logger.String("http.duration", time.Since(start).String()),
+				logger.String("http.remote_addr", r.RemoteAddr),
+			)
+		})
+	}
+}
+
+// requestLogs outputs the log messages of requests that failed with a server
+// error, even the ones suppressed by the log level. It wraps the service
+// handler directly, so the request context has everything added by the other
+// middlewares, like its tracker ID.
+func requestLogs(l logger_api.RequestLogs) middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rec := &statusRecorder{ResponseWriter: w}
+			defer func() {
+				l.EndRequest(r.Context(), rec.status >= http.StatusInternalServerError)
+			}()
+
+			next.ServeHTTP(rec, r)
+		})
+	}
+}
+
+// requestLogger stores inside the request context a logger adding the request
+// method, path and tracker ID into every message, retrieved with
+// logger.FromContext. Like requestLogs, it wraps the service handler
+// directly.
+func requestLogger(log logger_api.API, tracker integrations.Tracker) middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attrs := []logger_api.Attribute{
+				logger.String("http.method", r.Method),
+				logger.String("http.path", r.URL.Path),
+			}
+			if tracker != nil {
+				if id, ok := tracker.Retrieve(r.Context()); ok {
+					attrs = append(attrs, logger.String("tracker.id", id))
+				}
+			}
+
+			ctx := logger.NewContext(r.Context(), logger.With(log, attrs...))
+			if slot, ok := r.Context().Value(accessLogContextKey{}).(*accessLogContext); ok {
+				slot.ctx = ctx
+			}
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// bindOptions stores the default BindOptions of the service inside the
+// request context, where the binding functions of the http component pick
+// them up.
+func bindOptions(opts *mhttp.BindOptions) middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(mhttp.NewBindOptionsContext(r.Context(), opts)))
+		})
+	}
+}
+
+// gzipResponseWriter compresses everything written through it, deciding
+// whether to do it when the response status is written.
+type gzipResponseWriter struct {
+	http.ResponseWriter
+	writer      *gzip.Writer
+	wroteHeader bool
+}
+
+func (g *gzipResponseWriter) WriteHeader(code int) {
+	// Informational responses don't carry the final headers.
+	if g.wroteHeader || code < http.StatusOK {
+		g.ResponseWriter.WriteHeader(code)
+		return
+	}
+
+	g.wroteHeader = true
+	if shouldCompress(code, g.Header()) {
+		g.Header().Set("Content-Encoding", "gzip")
+		g.Header().Del("Content-Length")
+
+		g.writer = gzipWriterPool.Get().(*gzip.Writer)
+		g.writer.Reset(g.ResponseWriter)
+	}
+
+	g.ResponseWriter.WriteHeader(code)
+}
+
+func (g *gzipResponseWriter) Write(b []byte) (int, error) {
+	if !g.wroteHeader {
+		g.WriteHeader(http.StatusOK)
+	}
+	if g.writer == nil {
+		return g.ResponseWriter.Write(b)
+	}
+
+	return g.writer.Write(b)
+}
+
+func (g *gzipResponseWriter) Flush() {
+	if g.writer != nil {
+		_ = g.writer.Flush()
+	}
+	_ = http.NewResponseController(g.ResponseWriter).Flush()
+}
+
+func (g *gzipResponseWriter) Unwrap() http.ResponseWriter {
+	return g.ResponseWriter
+}
+
+// close finishes the compressed stream, if any.
+func (g *gzipResponseWriter) close() {
+	if g.writer == nil {
+		return
+	}
+
+	_ = g.writer.Close()
+	gzipWriterPool.Put(g.writer)
+	g.writer = nil
+}
+
+// shouldCompress tells if a response can be compressed, which is not the
+// case when it has no body or the handler already encoded it.
+func shouldCompress(code int, header http.Header) bool {
+	if code == http.StatusNoContent || code == http.StatusNotModified {
+		return false
+	}
+
+	return header.Get("Content-Encoding") == ""
+}
+
+var gzipWriterPool = sync.Pool{
+	New: func() interface{} {
+		return gzip.NewWriter(nil)
+	},
+}
+
+func compression(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept-Encoding")
+
+		if !acceptsGzip(r) || r.Method == http.MethodHead {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		gw := &gzipResponseWriter{ResponseWriter: w}
+		defer gw.close()
+
+		next.ServeHTTP(gw, r)
+	})
+}
+
+func acceptsGzip(r *http.Request) bool {
+	for _, e := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(e), ";")
+		if !strings.EqualFold(strings.TrimSpace(name), "gzip") {
+			continue
+		}
+
+		return strings.ReplaceAll(strings.TrimSpace(params), " ", "") != "q=0"
+	}
+
+	return false
+}
+
+// tokenBucket is a process-wide rate limiter.
+type tokenBucket struct {
+	mu       sync.Mutex
+	rate     float64
+	capacity float64
+	tokens   float64
+	last     time.Time
+	now      func() time.Time
+}
+
+func newTokenBucket(defs RateLimitDefinitions) *tokenBucket {
+	capacity := float64(defs.Burst)
+	if capacity < 1 {
+		capacity = math.Max(1, math.Ceil(defs.RequestsPerSecond))
+	}
+
+	return &tokenBucket{
+		rate:     defs.RequestsPerSecond,
+		capacity: capacity,
+		tokens:   capacity,
+		last:     time.Now(),
+		now:      time.Now,
+	}
+}
+
+// take consumes a token, returning how long to wait for the next one when
+// none is available.
+func (b *tokenBucket) take() (time.Duration, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	elapsed := max(now.Sub(b.last), 0)
+	b.tokens = math.Min(b.capacity, b.tokens+elapsed.Seconds()*b.rate)
+	b.last = now
+
+	if b.tokens < 1 {
+		return time.Duration((1 - b.tokens) / b.rate * float64(time.Second)), false
+	}
+
+	b.tokens--
+	return 0, true
+}
+
+func rateLimit(defs RateLimitDefinitions) middleware {
+	bucket := newTokenBucket(defs)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if wait, ok := bucket.take(); !ok {
+				w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+				http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,283 @@
+package http
+
+import (
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	logger_api "github.com/mikros-dev/mikros/apis/features/logger"
+	mhttp "github.com/mikros-dev/mikros/components/http"
+	"github.com/mikros-dev/mikros/components/logger"
+)
+
+func TestBuildMiddlewares(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("hello"))
+	})
+
+	t.Run("should fail with unknown middlewares", func(t *testing.T) {
+		_, err := buildMiddlewares(nil, &Definitions{Middlewares: []string{"unknown"}})
+		assert.ErrorContains(t, err, "unknown middleware 'unknown'")
+	})
+
+	t.Run("should fail with duplicated middlewares", func(t *testing.T) {
+		_, err := buildMiddlewares(nil, &Definitions{
+			Middlewares: []string{"compression", "compression"},
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("should fail with rate limit without settings", func(t *testing.T) {
+		_, err := buildMiddlewares(nil, &Definitions{Middlewares: []string{"rate_limit"}})
+		assert.Error(t, err)
+	})
+
+	t.Run("should add security headers", func(t *testing.T) {
+		var (
+			rec = httptest.NewRecorder()
+			r   = httptest.NewRequest(http.MethodGet, "/", nil)
+		)
+		r.Header.Set("X-Forwarded-Proto", "https")
+
+		securityHeaders(handler).ServeHTTP(rec, r)
+		assert.Equal(t, "nosniff", rec.Header().Get("X-Content-Type-Options"))
+		assert.Equal(t, "DENY", rec.Header().Get("X-Frame-Options"))
+		assert.NotEmpty(t, rec.Header().Get("Strict-Transport-Security"))
+	})
+
+	t.Run("should compress responses", func(t *testing.T) {
+		var (
+			rec = httptest.NewRecorder()
+			r   = httptest.NewRequest(http.MethodGet, "/", nil)
+		)
+		r.Header.Set("Accept-Encoding", "br, gzip")
+
+		compression(handler).ServeHTTP(rec, r)
+		require.Equal(t, "gzip", rec.Header().Get("Content-Encoding"))
+		assert.Equal(t, "Accept-Encoding", rec.Header().Get("Vary"))
+
+		gz, err := gzip.NewReader(rec.Body)
+		require.NoError(t, err)
+		body, err := io.ReadAll(gz)
+		require.NoError(t, err)
+		assert.Equal(t, "hello", string(body))
+	})
+
+	t.Run("should not compress when not accepted", func(t *testing.T) {
+		var (
+			rec = httptest.NewRecorder()
+			r   = httptest.NewRequest(http.MethodGet, "/", nil)
+		)
+		r.Header.Set("Accept-Encoding", "gzip;q=0")
+
+		compression(handler).ServeHTTP(rec, r)
+		assert.Empty(t, rec.Header().Get("Content-Encoding"))
+		assert.Equal(t, "hello", rec.Body.String())
+	})
+
+	t.Run("should not compress responses without body or already encoded", func(t *testing.T) {
+		tests := []struct {
+			name    string
+			method  string
+			handler http.HandlerFunc
+		}{
+			{
+				name:   "no content",
+				method: http.MethodGet,
+				handler: func(w http.ResponseWriter, _ *http.Request) {
+					w.WriteHeader(http.StatusNoContent)
+				},
+			},
+			{
+				name:   "not modified",
+				method: http.MethodGet,
+				handler: func(w http.ResponseWriter, _ *http.Request) {
+					w.WriteHeader(http.StatusNotModified)
+				},
+			},
+			{
+				name:   "head",
+				method: http.MethodHead,
+				handler: func(w http.ResponseWriter, _ *http.Request) {
+					w.WriteHeader(http.StatusOK)
+				},
+			},
+			{
+				name:   "encoded",
+				method: http.MethodGet,
+				handler: func(w http.ResponseWriter, _ *http.Request) {
+					w.Header().Set("Content-Encoding", "br")
+					_, _ = w.Write([]byte("brotli"))
+				},
+			},
+		}
+
+		for _, tt := range tests {
+			var (
+				rec = httptest.NewRecorder()
+				r   = httptest.NewRequest(tt.method, "/", nil)
+			)
+			r.Header.Set("Accept-Encoding", "gzip")
+
+			compression(tt.handler).ServeHTTP(rec, r)
+			assert.NotEqual(t, "gzip", rec.Header().Get("Content-Encoding"), tt.name)
+			assert.Equal(t, "Accept-Encoding", rec.Header().Get("Vary"), tt.name)
+			if tt.name == "encoded" {
+				assert.Equal(t, "brotli", rec.Body.String())
+			} else {
+				assert.Empty(t, rec.Body.String(), tt.name)
+			}
+		}
+	})
+
+	t.Run("should limit the request rate", func(t *testing.T) {
+		var (
+			now    = time.Now()
+			bucket = newTokenBucket(RateLimitDefinitions{RequestsPerSecond: 1, Burst: 2})
+		)
+		bucket.now = func() time.Time { return now }
+		bucket.last = now
+
+		_, ok := bucket.take()
+		assert.True(t, ok)
+		_, ok = bucket.take()
+		assert.True(t, ok)
+
+		wait, ok := bucket.take()
+		assert.False(t, ok)
+		assert.Equal(t, time.Second, wait)
+
+		now = now.Add(time.Second)
+		_, ok = bucket.take()
+		assert.True(t, ok)
+	})
+}
+
+type fakeRequestLogs struct {
+	elevated []bool
+}
+
+func (f *fakeRequestLogs) EndRequest(_ context.Context, elevate bool) {
+	f.elevated = append(f.elevated, elevate)
+}
+
+func TestRequestLogs(t *testing.T) {
+	t.Run("should elevate the logs of server errors only", func(t *testing.T) {
+		var (
+			logs    = &fakeRequestLogs{}
+			handler = requestLogs(logs)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/fail" {
+					w.WriteHeader(http.StatusInternalServerError)
+					return
+				}
+
+				w.WriteHeader(http.StatusNotFound)
+			}))
+		)
+
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/missing", nil))
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/fail", nil))
+		assert.Equal(t, []bool{false, true}, logs.elevated)
+	})
+}
+
+type fakeLogger struct {
+	logger_api.API
+	ctx   context.Context
+	attrs map[string]interface{}
+}
+
+func (f *fakeLogger) Info(ctx context.Context, _ string, attrs ...logger_api.Attribute) {
+	f.ctx = ctx
+	f.attrs = make(map[string]interface{})
+	for _, a := range attrs {
+		f.attrs[a.Key()] = a.Value()
+	}
+}
+
+type fakeTracker struct{}
+
+func (fakeTracker) Generate() string { return "id" }
+
+func (fakeTracker) Add(ctx context.Context, _ string) context.Context { return ctx }
+
+func (fakeTracker) Retrieve(_ context.Context) (string, bool) { return "abc", true }
+
+func TestRequestLogger(t *testing.T) {
+	t.Run("should store a request-scoped logger in the context", func(t *testing.T) {
+		var (
+			l       = &fakeLogger{}
+			handler = requestLogger(l, fakeTracker{})(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+				logger.FromContext(r.Context()).Info(r.Context(), "handling")
+			}))
+		)
+
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/users", nil))
+		assert.Equal(t, map[string]interface{}{
+			"http.method": http.MethodPost,
+			"http.path":   "/users",
+			"tracker.id":  "abc",
+		}, l.attrs)
+	})
+}
+
+type accessLogKey struct{}
+
+func TestAccessLog(t *testing.T) {
+	t.Run("should log with the request context", func(t *testing.T) {
+		var (
+			l       = &fakeLogger{}
+			handler = accessLog(l)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusCreated)
+			}))
+			r = httptest.NewRequest(http.MethodPost, "/users", nil)
+		)
+		r = r.WithContext(context.WithValue(r.Context(), accessLogKey{}, "request"))
+
+		handler.ServeHTTP(httptest.NewRecorder(), r)
+		require.NotNil(t, l.ctx)
+		assert.Equal(t, "request", l.ctx.Value(accessLogKey{}))
+		assert.Equal(t, http.StatusCreated, l.attrs["http.status"])
+		assert.Equal(t, "/users", l.attrs["http.path"])
+	})
+
+	t.Run("should log with the context completed by the request logger", func(t *testing.T) {
+		var (
+			l       = &fakeLogger{}
+			handler = accessLog(l)(requestLogger(&fakeLogger{}, fakeTracker{})(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})))
+		)
+
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users", nil))
+		require.NotNil(t, l.ctx)
+		assert.NotNil(t, l.ctx.Value(logger.ContextKey))
+	})
+}
+
+func TestBindOptions(t *testing.T) {
+	t.Run("should store the default bind options in the context", func(t *testing.T) {
+		var (
+			opts = &mhttp.BindOptions{FallbackSnakeCase: true}
+			req  struct {
+				PageSize int `http:"loc=query"`
+			}
+			handler = bindOptions(opts)(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+				stored, ok := mhttp.BindOptionsFromContext(r.Context())
+				assert.True(t, ok)
+				assert.Same(t, opts, stored)
+				assert.NoError(t, mhttp.BindQuery(r, &req))
+			}))
+		)
+
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/?page_size=5", nil))
+		assert.Equal(t, 5, req.PageSize)
+	})
+}
@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"slices"
 	"strings"
+	"sync/atomic"
 
 	"github.com/lab259/cors"
 
@@ -19,6 +20,7 @@ import (
 	"github.com/mikros-dev/mikros/components/options"
 	"github.com/mikros-dev/mikros/components/plugin"
 	"github.com/mikros-dev/mikros/components/service"
+	"github.com/mikros-dev/mikros/internal/components/shutdown"
 )
 
 type middleware = func(http.Handler) http.Handler
@@ -26,14 +28,22 @@ type middleware = func(http.Handler) http.Handler
 // Server represents the HTTP runtime server.
 type Server struct {
 	port     service.ServerPort
+	address  string
 	listener net.Listener
 	server   *http.Server
 	defs     *Definitions
+	options  *options.HTTPServiceOptions
+	fileDefs map[string]interface{}
+	logger   logger_api.API
+	inFlight atomic.Int64
+	notifier *shutdown.Notifier
 }
 
 // New creates a new Server struct.
 func New() *Server {
-	return &Server{}
+	return &Server{
+		notifier: shutdown.NewNotifier(),
+	}
 }
 
 // Name gives the implementation runtime name.
@@ -44,11 +54,16 @@ func (s *Server) Name() string {
 // Info returns runtime fields to be logged.
 func (s *Server) Info() []logger_api.Attribute {
 	return []logger_api.Attribute{
-		logger.String("http.listenin_address", fmt.Sprintf(":%v", s.port.Int32())),
+		logger.String("http.listenin_address", s.address),
 		logger.String("http.auth_enabled", fmt.Sprintf("%t", !s.defs.DisableAuth)),
 	}
 }
 
+// EffectiveConfig returns the runtime settings and where each one came from.
+func (s *Server) EffectiveConfig() []plugin.ConfigEntry {
+	return effectiveConfig(s.defs, s.options, s.fileDefs)
+}
+
 // Initialize initializes the runtime internals.
 func (s *Server) Initialize(ctx context.Context, opt *plugin.RuntimeOptions) error {
 	provider, ok := opt.ServiceHandler.(http_api.API)
@@ -69,21 +84,49 @@ func (s *Server) Initialize(ctx context.Context, opt *plugin.RuntimeOptions) err
 		return errors.New("invalid RuntimeOptions received on initialization")
 	}
 
-	var (
-		h    = baseHandler
-		defs = newDefinitions(opt.Definitions, svcOptions)
-	)
+	defs, err := newDefinitions(opt.Definitions, svcOptions)
+	if err != nil {
+		return err
+	}
+
+	h := handlerInfo(baseHandler)
+
+	if opt.Definitions != nil {
+		h = downstreamBudget(opt.Definitions.Budget, h)
+	}
 
 	if defs.BasePath != "" {
 		h = http.StripPrefix(defs.BasePath, h)
 	}
 
-	// Add user-supplied middlewares after core ones.
+	// Built-in middlewares enabled by definitions wrap the core ones, and
+	// user-supplied middlewares come after them.
+	chain, err := buildMiddlewares(opt.Logger, defs)
+	if err != nil {
+		return err
+	}
+
 	core, err := buildCoreMiddlewares(ctx, opt, defs)
 	if err != nil {
 		return err
 	}
-	chain := append(core, svcOptions.Middlewares...)
+	chain = append(chain, core...)
+	if svcOptions.DefaultBindOptions != nil {
+		chain = append(chain, bindOptions(svcOptions.DefaultBindOptions))
+	}
+	chain = append(chain, svcOptions.Middlewares...)
+	if opt.Logger != nil {
+		tracker, err := getTracker(opt)
+		if err != nil {
+			return err
+		}
+
+		chain = append(chain, requestLogger(opt.Logger, tracker))
+	}
+	if l, ok := opt.Logger.(logger_api.RequestLogs); ok && opt.Definitions != nil && opt.Definitions.Log.RequestBuffer > 0 {
+		chain = append(chain, requestLogs(l))
+	}
+	chain = append([]middleware{inFlightRequests(&s.inFlight), shutdownNotice(s.notifier)}, chain...)
 
 	// Compose the handlers
 	for i := len(chain) - 1; i >= 0; i-- {
@@ -91,14 +134,18 @@ func (s *Server) Initialize(ctx context.Context, opt *plugin.RuntimeOptions) err
 	}
 
 	// Create the listener for the runtime server.
-	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", opt.Port))
+	listener, err := opt.Listen()
 	if err != nil {
 		return fmt.Errorf("could not listen to service port: %w", err)
 	}
 
 	// Initialize the runtime
 	s.defs = defs
+	s.options = svcOptions
+	s.fileDefs, _ = opt.Definitions.LoadRuntime(definition.RuntimeTypeHTTP)
 	s.port = opt.Port
+	s.address = opt.Address()
+	s.logger = opt.Logger
 	s.listener = listener
 	s.server = &http.Server{
 		Handler:        h,
@@ -143,6 +190,24 @@ func buildCoreMiddlewares(ctx context.Context, opt *plugin.RuntimeOptions, defs
 	}), nil
 }
 
+func getTracker(opt *plugin.RuntimeOptions) (integrations.Tracker, error) {
+	if opt.Integrations == nil {
+		return nil, nil
+	}
+
+	i, err := opt.Integrations.Integration(options.TrackerIntegrationName)
+	if err != nil {
+		return nil, nil
+	}
+
+	t, ok := i.API().(integrations.Tracker)
+	if !ok {
+		return nil, errors.New("tracker integration exists but does not implement Tracker")
+	}
+
+	return t, nil
+}
+
 func validateCORS(cors integrations.CorsHandler) error {
 	cfg := cors.Cors()
 
@@ -283,6 +348,11 @@ func getCors(opt *plugin.RuntimeOptions) integrations.CorsHandler {
 	return c
 }
 
+// Addr returns the address of the runtime server listener.
+func (s *Server) Addr() net.Addr {
+	return s.listener.Addr()
+}
+
 // Run runs the runtime.
 func (s *Server) Run(_ context.Context, _ interface{}) error {
 	if err := s.server.Serve(s.listener); err != nil {
@@ -302,5 +372,5 @@ func (s *Server) Stop(ctx context.Context) error {
 		_ = listener.Close()
 	}(s.listener)
 
-	return s.server.Shutdown(ctx)
+	return s.shutdown(ctx)
 }
@@ -17,7 +17,9 @@ import (
 
 	logger_api "github.com/mikros-dev/mikros/apis/features/logger"
 	"github.com/mikros-dev/mikros/apis/integrations"
+	mcontext "github.com/mikros-dev/mikros/components/context"
 	"github.com/mikros-dev/mikros/components/definition"
+	mhttp "github.com/mikros-dev/mikros/components/http"
 	"github.com/mikros-dev/mikros/components/logger"
 	"github.com/mikros-dev/mikros/components/options"
 	"github.com/mikros-dev/mikros/components/plugin"
@@ -27,6 +29,7 @@ import (
 // Server represents the HTTP (spec) runtime server.
 type Server struct {
 	port              service.ServerPort
+	address           string
 	trackerHeaderName string
 	defs              *Definitions
 	server            *fasthttp.Server
@@ -35,6 +38,8 @@ type Server struct {
 	tracing           integrations.Tracer
 	tracker           integrations.Tracker
 	panicRecovery     integrations.HTTPSpecRecovery
+	bindOptions       *mhttp.BindOptions
+	readiness         plugin.ServiceHealth
 }
 
 // New creates a new Server struct.
@@ -50,11 +55,16 @@ func (s *Server) Name() string {
 // Info returns runtime fields to be logged.
 func (s *Server) Info() []logger_api.Attribute {
 	return []logger_api.Attribute{
-		logger.String("http_spec.listening_address", fmt.Sprintf(":%v", s.port.Int32())),
+		logger.String("http_spec.listening_address", s.address),
 		logger.String("http_spec.auth_enabled", fmt.Sprintf("%t", !s.defs.DisableAuth)),
 	}
 }
 
+// Addr returns the address of the runtime server listener.
+func (s *Server) Addr() net.Addr {
+	return s.listener.Addr()
+}
+
 // Run starts the HTTP (spec) server.
 func (s *Server) Run(_ context.Context, _ interface{}) error {
 	return s.server.Serve(s.listener)
@@ -79,8 +89,10 @@ func (s *Server) Initialize(ctx context.Context, opt *plugin.RuntimeOptions) err
 	}
 
 	s.port = opt.Port
+	s.address = opt.Address()
 	s.logger = opt.Logger
 	s.trackerHeaderName = opt.Env.TrackerHeaderName()
+	s.readiness = opt.Health
 
 	tr, err := s.getTracker(opt)
 	if err != nil {
@@ -102,7 +114,7 @@ func (s *Server) Initialize(ctx context.Context, opt *plugin.RuntimeOptions) err
 
 	// Starts the listener last so we don't need to worry about closing it in
 	// other error paths.
-	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", opt.Port))
+	listener, err := opt.Listen()
 	if err != nil {
 		return fmt.Errorf("could not listen to service port: %w", err)
 	}
@@ -117,7 +129,6 @@ func (s *Server) validate(opt *plugin.RuntimeOptions) error {
 		fields   = []interface{}{
 			opt.Name,
 			opt.Logger,
-			opt.Port,
 			opt.Env.DeploymentEnv(),
 			opt.ServiceOptions,
 			opt.Integrations,
@@ -141,6 +152,7 @@ func (s *Server) initializeHTTPServerInternals(ctx context.Context, opt *plugin.
 	// 404 when some client uses a wrong endpoint.
 	httpRouter := router.New()
 	httpRouter.RedirectFixedPath = false
+	httpRouter.SaveMatchedRoutePath = true
 
 	svc, ok := opt.ServiceOptions.(*options.HTTPSpecServiceOptions)
 	if !ok {
@@ -152,6 +164,8 @@ func (s *Server) initializeHTTPServerInternals(ctx context.Context, opt *plugin.
 		return err
 	}
 
+	s.bindOptions = svc.DefaultBindOptions
+
 	if err = svc.ProtoHTTPServer.SetupServer(
 		opt.Definitions.ServiceName().String(),
 		opt.Logger,
@@ -268,6 +282,17 @@ func (s *Server) getCors(opt *plugin.RuntimeOptions) (integrations.CorsHandler,
 	return c, nil
 }
 
+// serveHealth answers the /health route following the service readiness,
+// responding with 503 when the service is not ready.
+func (s *Server) serveHealth(ctx *fasthttp.RequestCtx) {
+	if s.readiness != nil && !s.readiness.Check(ctx).Ready {
+		ctx.SetStatusCode(fasthttp.StatusServiceUnavailable)
+		return
+	}
+
+	ctx.SetStatusCode(fasthttp.StatusOK)
+}
+
 func (s *Server) serverRequestHandler(h fasthttp.RequestHandler) fasthttp.RequestHandler {
 	return func(ctx *fasthttp.RequestCtx) {
 		if s.tracker != nil {
@@ -275,10 +300,17 @@ func (s *Server) serverRequestHandler(h fasthttp.RequestHandler) fasthttp.Reques
 		}
 
 		if ctx.IsGet() && string(ctx.Path()) == "/health" {
-			ctx.SetStatusCode(fasthttp.StatusOK)
+			s.serveHealth(ctx)
 			return
 		}
 
+		s.setHandlerInfo(ctx)
+		s.setRequestLogger(ctx)
+		if s.bindOptions != nil {
+			ctx.SetUserValue(mhttp.BindOptionsContextKey, s.bindOptions)
+		}
+		defer s.endRequestLogs(ctx)
+
 		data := s.startTracing(ctx)
 		if s.panicRecovery != nil {
 			defer s.panicRecovery.Recover(ctx)
@@ -290,6 +322,39 @@ func (s *Server) serverRequestHandler(h fasthttp.RequestHandler) fasthttp.Reques
 	}
 }
 
+// setHandlerInfo stores inside the request context a function that gives the
+// route template matched by the router, which is only known after the request
+// is routed.
+func (s *Server) setHandlerInfo(ctx *fasthttp.RequestCtx) {
+	ctx.SetUserValue(mcontext.HandlerInfoKey, func() mcontext.HandlerInfo {
+		route, _ := ctx.UserValue(router.MatchedRoutePathParam).(string)
+		if route == "" {
+			return mcontext.HandlerInfo{}
+		}
+
+		return mcontext.HandlerInfo{
+			Route: string(ctx.Method()) + " " + route,
+		}
+	})
+}
+
+// setRequestLogger stores inside the request context a logger adding the
+// request method, path and tracker ID into every message, retrieved with
+// logger.FromContext.
+func (s *Server) setRequestLogger(ctx *fasthttp.RequestCtx) {
+	attrs := []logger_api.Attribute{
+		logger.String("http.method", string(ctx.Method())),
+		logger.String("http.path", string(ctx.Path())),
+	}
+	if s.tracker != nil {
+		if id, ok := s.tracker.Retrieve(ctx); ok {
+			attrs = append(attrs, logger.String("tracker.id", id))
+		}
+	}
+
+	ctx.SetUserValue(logger.ContextKey, logger.With(s.logger, attrs...))
+}
+
 func (s *Server) injectTrackerID(ctx *fasthttp.RequestCtx) {
 	trackID := s.tracker.Generate()
 
@@ -322,6 +387,23 @@ func (s *Server) stopTracing(ctx *fasthttp.RequestCtx, data interface{}) {
 	}
 }
 
+// endRequestLogs outputs the log messages of requests that failed or were
+// sampled by the tracing integration, even the ones suppressed by the log
+// level.
+func (s *Server) endRequestLogs(ctx *fasthttp.RequestCtx) {
+	l, ok := s.logger.(logger_api.RequestLogs)
+	if !ok {
+		return
+	}
+
+	elevate := ctx.Response.StatusCode() >= fasthttp.StatusInternalServerError
+	if sampler, ok := s.tracing.(integrations.TraceSampler); ok && sampler.Sampled(ctx) {
+		elevate = true
+	}
+
+	l.EndRequest(ctx, elevate)
+}
+
 func (s *Server) handleHTTPError(ctx *fasthttp.RequestCtx, err error) {
 	s.logger.Error(ctx, "http error", logger.Error(err))
 }
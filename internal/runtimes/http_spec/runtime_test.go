@@ -0,0 +1,48 @@
+//revive:disable:var-naming
+package http_spec
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fasthttp"
+
+	"github.com/mikros-dev/mikros/components/plugin"
+)
+
+type fakeReadiness struct {
+	ready bool
+}
+
+func (f *fakeReadiness) Check(context.Context) plugin.HealthReport {
+	return plugin.HealthReport{Ready: f.ready}
+}
+
+func (f *fakeReadiness) Subscribe(func(report plugin.HealthReport)) {}
+
+func TestHealthRoute(t *testing.T) {
+	serve := func(s *Server) int {
+		called := false
+		h := s.serverRequestHandler(func(*fasthttp.RequestCtx) {
+			called = true
+		})
+
+		ctx := &fasthttp.RequestCtx{}
+		ctx.Request.Header.SetMethod(fasthttp.MethodGet)
+		ctx.Request.SetRequestURI("/health")
+		h(ctx)
+
+		assert.False(t, called)
+		return ctx.Response.StatusCode()
+	}
+
+	t.Run("should answer ok without a service readiness", func(t *testing.T) {
+		assert.Equal(t, fasthttp.StatusOK, serve(&Server{}))
+	})
+
+	t.Run("should follow the service readiness", func(t *testing.T) {
+		assert.Equal(t, fasthttp.StatusOK, serve(&Server{readiness: &fakeReadiness{ready: true}}))
+		assert.Equal(t, fasthttp.StatusServiceUnavailable, serve(&Server{readiness: &fakeReadiness{}}))
+	})
+}
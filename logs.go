@@ -0,0 +1,37 @@
+package mikros
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/mikros-dev/mikros/components/logger"
+)
+
+// LogTailHandler returns an HTTP handler that outputs, as JSON, the last log
+// records kept in memory by the service, including the ones suppressed by
+// its log level. The "n" query parameter limits how many records are
+// returned and "level" filters them by their minimum level. Records are
+// only kept when the service definitions set log.recent_records. It is
+// intended to be mounted in an administrative (non-public) route.
+func (s *Service) LogTailHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+
+		n, _ := strconv.Atoi(r.URL.Query().Get("n"))
+		records, err := s.logger.TailRecords(n, r.URL.Query().Get("level"))
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			return
+		}
+
+		if records == nil {
+			records = []json.RawMessage{}
+		}
+
+		if err := json.NewEncoder(w).Encode(records); err != nil {
+			s.logger.Error(r.Context(), "failed to write log records", logger.Error(err))
+		}
+	})
+}
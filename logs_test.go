@@ -0,0 +1,76 @@
+package mikros
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	mlogger "github.com/mikros-dev/mikros/internal/components/logger"
+)
+
+func TestLogTailHandler(t *testing.T) {
+	var (
+		ctx = context.Background()
+		s   = &Service{
+			logger: mlogger.New(mlogger.Options{RecentRecords: 10, Output: io.Discard}),
+		}
+		h = s.LogTailHandler()
+	)
+
+	s.logger.Debug(ctx, "debug")
+	s.logger.Info(ctx, "info")
+	s.logger.Warn(ctx, "warn")
+
+	tail := func(t *testing.T, query string) (*httptest.ResponseRecorder, []json.RawMessage) {
+		t.Helper()
+
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/logs"+query, nil))
+
+		var records []json.RawMessage
+		if rec.Code == http.StatusOK {
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &records))
+		}
+
+		return rec, records
+	}
+
+	t.Run("should output the kept records", func(t *testing.T) {
+		rec, records := tail(t, "")
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/json; charset=utf-8", rec.Header().Get("Content-Type"))
+		assert.Len(t, records, 3)
+	})
+
+	t.Run("should limit and filter the records", func(t *testing.T) {
+		_, records := tail(t, "?n=1")
+		require.Len(t, records, 1)
+		assert.Contains(t, string(records[0]), `"msg":"warn"`)
+
+		_, records = tail(t, "?level=info")
+		assert.Len(t, records, 2)
+	})
+
+	t.Run("should reject unknown levels", func(t *testing.T) {
+		rec, _ := tail(t, "?level=verbose")
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "error")
+	})
+
+	t.Run("should output an empty list without kept records", func(t *testing.T) {
+		var (
+			rec = httptest.NewRecorder()
+			s   = &Service{logger: mlogger.New(mlogger.Options{Output: io.Discard})}
+		)
+
+		s.LogTailHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/logs", nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, "[]", rec.Body.String())
+	})
+}
@@ -0,0 +1,154 @@
+package mikros
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/mikros-dev/mikros/components/logger"
+	"github.com/mikros-dev/mikros/components/plugin"
+	"github.com/mikros-dev/mikros/internal/components/lifecycle"
+)
+
+const (
+	// readyPollInterval is how often RunningService checks if its servers
+	// accept connections.
+	readyPollInterval = 10 * time.Millisecond
+)
+
+// RunningService is a service started by StartForTest, running its servers
+// inside the test process.
+type RunningService struct {
+	svc      *Service
+	srv      interface{}
+	addrs    map[string]net.Addr
+	ready    chan struct{}
+	errs     chan error
+	stopped  chan struct{}
+	stopOnce sync.Once
+}
+
+// StartForTest bootstraps the service, like Start, and puts its servers in
+// execution listening on ephemeral ports, so tests can exercise the service
+// through the network as its clients do. Unlike Start, it does not block and
+// returns errors instead of aborting.
+//
+// The service is stopped when ctx is done or when RunningService.Stop is
+// called.
+func (s *Service) StartForTest(ctx context.Context, srv interface{}) (*RunningService, error) {
+	s.ephemeralPorts = true
+
+	if err := s.bootstrap(ctx, srv); err != nil {
+		return nil, fmt.Errorf("could not bootstrap service: %w", err)
+	}
+
+	r := &RunningService{
+		svc:     s,
+		srv:     srv,
+		addrs:   make(map[string]net.Addr),
+		ready:   make(chan struct{}),
+		errs:    make(chan error, len(s.runtimes)),
+		stopped: make(chan struct{}),
+	}
+
+	for _, runtime := range s.runtimes {
+		if l, ok := runtime.(plugin.RuntimeListener); ok {
+			r.addrs[runtime.Name()] = l.Addr()
+		}
+
+		go func(runtime plugin.Runtime) {
+			defer s.reportPanic(ctx)
+
+			attrs := append(runtime.Info(), logger.String("runtime.mode", runtime.Name()))
+			s.logger.Info(ctx, "runtime is running", attrs...)
+			if err := runtime.Run(ctx, srv); err != nil {
+				r.errs <- fmt.Errorf("runtime '%s' failed: %w", runtime.Name(), err)
+			}
+		}(runtime)
+	}
+
+	go r.waitReady(ctx)
+	go func() {
+		select {
+		case <-ctx.Done():
+			r.Stop(context.Background())
+		case <-r.stopped:
+		}
+	}()
+
+	return r, nil
+}
+
+// waitReady closes the ready channel once every server accepts connections.
+func (r *RunningService) waitReady(ctx context.Context) {
+	for _, addr := range r.addrs {
+		for {
+			conn, err := net.DialTimeout(addr.Network(), dialableAddress(addr), time.Second)
+			if err == nil {
+				_ = conn.Close()
+				break
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-r.stopped:
+				return
+			case <-time.After(readyPollInterval):
+			}
+		}
+	}
+
+	close(r.ready)
+}
+
+// Ready returns a channel that is closed when the service servers are
+// accepting connections.
+func (r *RunningService) Ready() <-chan struct{} {
+	return r.ready
+}
+
+// Err returns a channel that receives the errors of servers that stopped
+// unexpectedly.
+func (r *RunningService) Err() <-chan error {
+	return r.errs
+}
+
+// Addr returns the address, e.g. "127.0.0.1:38451", that the server of a
+// runtime, e.g. "grpc" or "http", is listening to. It returns an empty
+// string if the service has no such server.
+func (r *RunningService) Addr(runtime string) string {
+	addr, ok := r.addrs[runtime]
+	if !ok {
+		return ""
+	}
+
+	return dialableAddress(addr)
+}
+
+// Stop executes the service lifecycle.OnFinish and stops its servers,
+// features and integrations. It can be called more than once.
+func (r *RunningService) Stop(ctx context.Context) {
+	r.stopOnce.Do(func() {
+		close(r.stopped)
+		lifecycle.OnFinish(ctx, r.srv, &lifecycle.Options{
+			Env:            r.svc.envs.DeploymentEnv(),
+			ExecuteOnTests: r.svc.definitions.Tests.ExecuteLifecycle,
+		})
+		r.svc.stopService(ctx)
+	})
+}
+
+// dialableAddress replaces the unspecified host of a listener address, e.g.
+// "[::]:38451", with the loopback one.
+func dialableAddress(addr net.Addr) string {
+	tcp, ok := addr.(*net.TCPAddr)
+	if !ok || !tcp.IP.IsUnspecified() {
+		return addr.String()
+	}
+
+	return net.JoinHostPort("127.0.0.1", strconv.Itoa(tcp.Port))
+}
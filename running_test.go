@@ -0,0 +1,81 @@
+package mikros
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mikros-dev/mikros/components/definition"
+	"github.com/mikros-dev/mikros/components/options"
+)
+
+type runningTestService struct{}
+
+func (s *runningTestService) ping(w http.ResponseWriter, _ *http.Request) {
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *runningTestService) HTTPHandler(_ context.Context) (http.Handler, error) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /ping", s.ping)
+	return mux, nil
+}
+
+func TestStartForTest(t *testing.T) {
+	t.Run("should run an HTTP service on an ephemeral port until stopped", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "service.toml")
+		require.NoError(t, os.WriteFile(path, []byte(`
+name = "running"
+types = ["http"]
+version = "v0.1.0"
+language = "go"
+product = "TEST"
+
+[runtime.http]
+disable_auth = true
+`), 0o600))
+
+		defs, err := definition.ParseFromFile(path)
+		require.NoError(t, err)
+
+		svc, err := newService(&options.NewServiceOptions{
+			Service: map[string]options.ServiceOptions{
+				"http": &options.HTTPServiceOptions{},
+			},
+		}, defs)
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		running, err := svc.StartForTest(ctx, &runningTestService{})
+		require.NoError(t, err)
+
+		select {
+		case <-running.Ready():
+		case err := <-running.Err():
+			require.NoError(t, err)
+		case <-ctx.Done():
+			require.FailNow(t, "service was not ready in time")
+		}
+
+		addr := running.Addr("http")
+		require.NotEmpty(t, addr)
+		assert.NotEqual(t, "127.0.0.1:8080", addr)
+		assert.Empty(t, running.Addr("grpc"))
+
+		res, err := http.Get("http://" + addr + "/ping")
+		require.NoError(t, err)
+		_ = res.Body.Close()
+		assert.Equal(t, http.StatusNoContent, res.StatusCode)
+
+		running.Stop(ctx)
+		assert.NotPanics(t, func() { running.Stop(ctx) })
+	})
+}
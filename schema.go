@@ -0,0 +1,75 @@
+package mikros
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"os"
+	"reflect"
+
+	"github.com/mikros-dev/mikros/components/definition"
+	"github.com/mikros-dev/mikros/internal/components/tags"
+)
+
+var (
+	definitionsSchemaFlag = flag.Bool("definitions-schema", false, "Prints the JSON Schema of the service definitions and exits.")
+)
+
+// definitionsSchemaEnabled returns if the service was executed only to print
+// its definitions schema.
+func definitionsSchemaEnabled() bool {
+	return flag.Parsed() && *definitionsSchemaFlag
+}
+
+// DefinitionsSchema returns the JSON Schema of the service definitions file,
+// including the sections of its features and runtimes with their own
+// settings and the '[service]' section of srv, when it has a member tagged
+// as "definitions".
+func (s *Service) DefinitionsSchema(srv interface{}) (definition.Schema, error) {
+	if err := s.loadExternalDefinitions(); err != nil {
+		return nil, err
+	}
+
+	return s.definitions.JSONSchema(serviceDefinitionsOf(srv)), nil
+}
+
+// serviceDefinitionsOf returns a new value of the srv member tagged as
+// "definitions".
+func serviceDefinitionsOf(srv interface{}) interface{} {
+	t := reflect.TypeOf(srv)
+	if t == nil {
+		return nil
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if tag := tags.ParseTag(field.Tag); tag != nil && tag.IsDefinitions {
+			return reflect.New(field.Type).Elem().Interface()
+		}
+	}
+
+	return nil
+}
+
+// printDefinitionsSchema writes the service DefinitionsSchema to the standard
+// output and finishes the process.
+func (s *Service) printDefinitionsSchema(ctx context.Context, srv interface{}) {
+	schema, err := s.DefinitionsSchema(srv)
+	if err != nil {
+		s.fatalAbort(ctx, "could not load service definitions", err)
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(schema); err != nil {
+		s.fatalAbort(ctx, "could not write definitions schema", err)
+	}
+
+	os.Exit(0)
+}
@@ -0,0 +1,114 @@
+package mikros
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/mikros-dev/mikros/components/logger"
+	"github.com/mikros-dev/mikros/components/plugin"
+	"github.com/mikros-dev/mikros/components/selftest"
+)
+
+var (
+	selfTestFlag       = flag.Bool("selftest", false, "Runs the service smoke checks, prints their report and exits.")
+	selfTestFormatFlag = flag.String("selftest-format", string(selftest.FormatTAP), "Sets the self-test report format: tap or json.")
+)
+
+// selfTestEnabled returns if the service was executed in self-test mode.
+func selfTestEnabled() bool {
+	return flag.Parsed() && *selfTestFlag
+}
+
+// runSelfTest executes the service smoke checks, writing their report to the
+// standard output, and finishes the process. It exits with a non-zero code
+// if any check fails, including the service bootstrap.
+func (s *Service) runSelfTest(ctx context.Context, srv interface{}, bootstrapErr error) {
+	format, err := selftest.ParseFormat(*selfTestFormatFlag)
+	if err != nil {
+		s.fatalAbort(ctx, "invalid self-test options", err)
+	}
+
+	checks := []selftest.Check{
+		{
+			Name: "bootstrap",
+			Run: func(context.Context) error {
+				return bootstrapErr
+			},
+		},
+	}
+	if bootstrapErr == nil {
+		checks = append(checks, s.selfTestChecks(ctx, srv)...)
+	}
+
+	report := selftest.Run(ctx, checks, selftest.Options{
+		Timeout: s.definitions.Health.Timeout,
+	})
+	if err := report.Write(os.Stdout, format); err != nil {
+		s.fatalAbort(ctx, "could not write self-test report", err)
+	}
+
+	if err := s.stopDependencies(ctx); err != nil {
+		s.logger.Error(ctx, "could not stop service dependencies", logger.Error(err))
+	}
+
+	if !report.Passed {
+		os.Exit(1)
+	}
+
+	os.Exit(0)
+}
+
+// selfTestChecks gathers the checks of the service: its definitions sanity,
+// the health of its dependencies and the ones declared by the service struct
+// and by its features.
+func (s *Service) selfTestChecks(ctx context.Context, srv interface{}) []selftest.Check {
+	checks := []selftest.Check{
+		{
+			Name: "config",
+			Run: func(context.Context) error {
+				return s.definitions.Validate()
+			},
+		},
+	}
+
+	report := s.HealthReport(ctx)
+	for _, f := range report.Features {
+		checks = append(checks, selftest.Check{
+			Name: "dependency/" + f.Name,
+			Run: func(context.Context) error {
+				if f.State != plugin.HealthStateUnhealthy {
+					return nil
+				}
+				if f.Reason == "" {
+					return errors.New("dependency is unhealthy")
+				}
+
+				return errors.New(f.Reason)
+			},
+		})
+	}
+
+	iter := s.registeredFeatures.Iterator()
+	for f, next := iter.Next(); next; f, next = iter.Next() {
+		if p, ok := f.(selftest.Provider); ok && f.IsEnabled() {
+			checks = append(checks, prefixChecks("feature/"+f.Name(), p.SelfTestChecks())...)
+		}
+	}
+
+	if p, ok := srv.(selftest.Provider); ok {
+		checks = append(checks, prefixChecks("service", p.SelfTestChecks())...)
+	}
+
+	return checks
+}
+
+func prefixChecks(prefix string, checks []selftest.Check) []selftest.Check {
+	for i := range checks {
+		checks[i].Name = fmt.Sprintf("%s/%s", prefix, checks[i].Name)
+	}
+
+	return checks
+}
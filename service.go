@@ -4,21 +4,30 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"log"
+	"maps"
 	"os"
 	"os/signal"
 	"reflect"
+	"sort"
 	"strings"
 	"syscall"
+	"time"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 
 	errors_api "github.com/mikros-dev/mikros/apis/features/errors"
 	logger_api "github.com/mikros-dev/mikros/apis/features/logger"
 	integrations_api "github.com/mikros-dev/mikros/apis/integrations"
+	"github.com/mikros-dev/mikros/components/abort"
 	mcontext "github.com/mikros-dev/mikros/components/context"
 	"github.com/mikros-dev/mikros/components/definition"
+	"github.com/mikros-dev/mikros/components/downstream"
 	mgrpc "github.com/mikros-dev/mikros/components/grpc"
+	"github.com/mikros-dev/mikros/components/handoff"
+	"github.com/mikros-dev/mikros/components/ids"
 	"github.com/mikros-dev/mikros/components/logger"
 	"github.com/mikros-dev/mikros/components/options"
 	"github.com/mikros-dev/mikros/components/plugin"
@@ -26,15 +35,25 @@ import (
 	"github.com/mikros-dev/mikros/components/testing"
 	"github.com/mikros-dev/mikros/internal/components/env"
 	merrors "github.com/mikros-dev/mikros/internal/components/errors"
+	"github.com/mikros-dev/mikros/internal/components/health"
 	"github.com/mikros-dev/mikros/internal/components/lifecycle"
+	"github.com/mikros-dev/mikros/internal/components/limits"
 	mlogger "github.com/mikros-dev/mikros/internal/components/logger"
+	"github.com/mikros-dev/mikros/internal/components/ports"
 	"github.com/mikros-dev/mikros/internal/components/tags"
 	"github.com/mikros-dev/mikros/internal/components/validations"
 	"github.com/mikros-dev/mikros/internal/features"
+	"github.com/mikros-dev/mikros/internal/features/oauth2"
 	"github.com/mikros-dev/mikros/internal/integrations"
 	"github.com/mikros-dev/mikros/internal/runtimes"
 )
 
+const (
+	// upgradeTimeout is how long a new process has to become ready during
+	// an upgrade.
+	upgradeTimeout = time.Minute
+)
+
 // Service is the object that represents a service application.
 type Service struct {
 	serviceOptions         map[string]options.ServiceOptions
@@ -51,6 +70,10 @@ type Service struct {
 	registeredIntegrations *plugin.IntegrationSet
 	tracker                integrations_api.Tracker
 	grpcConns              []*grpc.ClientConn
+	srv                    interface{}
+	ephemeralPorts         bool
+	listen                 definition.Listen
+	health                 *health.Monitor
 }
 
 // ServiceName is the way to retrieve a service name from a string.
@@ -65,17 +88,24 @@ func ServiceName(name string) service.Name {
 // something wrong happens.
 func NewService(opt *options.NewServiceOptions) *Service {
 	if err := opt.Validate(); err != nil {
-		log.Fatal(err)
+		exitWithConfigError(err)
 	}
 
 	svc, err := initService(opt)
 	if err != nil {
-		log.Fatal(err)
+		exitWithConfigError(err)
 	}
 
 	return svc
 }
 
+// exitWithConfigError finishes the process when the service cannot be
+// created, which happens before its logger is available.
+func exitWithConfigError(err error) {
+	log.Println(err)
+	os.Exit(abort.ReasonConfig.ExitCode())
+}
+
 // initService parses the service.toml file and creates the Service object
 // initializing its main fields.
 func initService(opt *options.NewServiceOptions) (*Service, error) {
@@ -84,18 +114,32 @@ func initService(opt *options.NewServiceOptions) (*Service, error) {
 		return nil, err
 	}
 
+	return newService(opt, defs)
+}
+
+// newService creates the Service object from already loaded definitions.
+func newService(opt *options.NewServiceOptions, defs *definition.Definitions) (*Service, error) {
 	// Loads environment variables
 	envs, err := env.NewServiceEnvs(defs)
 	if err != nil {
 		return nil, err
 	}
 
+	// Replaces options that vary by deployment environment with their
+	// current values.
+	if err := opt.ResolveEnv(envs.DeploymentEnv()); err != nil {
+		return nil, err
+	}
+
 	// Initialize the service logger system.
 	serviceLogger, err := initLogger(defs, envs)
 	if err != nil {
 		return nil, err
 	}
 
+	// Adjusts the Go runtime to the container limits.
+	applyRuntimeLimits(defs, envs, serviceLogger)
+
 	// Context initialization
 	ctx, err := mcontext.New(&mcontext.Options{
 		Name: defs.ServiceName(),
@@ -112,6 +156,7 @@ func initService(opt *options.NewServiceOptions) (*Service, error) {
 		ctx:                    ctx,
 		clients:                opt.GrpcClients,
 		definitions:            defs,
+		listen:                 listenSettings(opt.Listen, defs.Listen),
 		envs:                   envs,
 		registeredFeatures:     features.Features(),
 		registeredRuntimes:     runtimes.Runtimes(),
@@ -144,6 +189,10 @@ func initLogger(defs *definition.Definitions, envs *env.ServiceEnvs) (*mlogger.L
 		DiscardMessages: discardMessages,
 		ErrorStackTrace: defs.Log.ErrorStackTrace,
 		FixedAttributes: attributes,
+		RecentRecords:   recentLogRecords(defs),
+		OTLP:            otlpLogOptions(defs),
+		RequestBuffer:   defs.Log.RequestBuffer,
+		Output:          logOutput(),
 	})
 
 	if defs.Log.Level != "" {
@@ -155,6 +204,50 @@ func initLogger(defs *definition.Definitions, envs *env.ServiceEnvs) (*mlogger.L
 	return serviceLogger, nil
 }
 
+// logOutput returns where the service log messages are written, keeping the
+// standard output free for the self-test report, the definitions schema and
+// the validation result.
+func logOutput() io.Writer {
+	if selfTestEnabled() || definitionsSchemaEnabled() || validateEnabled() {
+		return os.Stderr
+	}
+
+	return os.Stdout
+}
+
+func otlpLogOptions(defs *definition.Definitions) *mlogger.OTLPOptions {
+	if defs.Log.OTLP == nil {
+		return nil
+	}
+
+	return &mlogger.OTLPOptions{
+		Endpoint:      defs.Log.OTLP.Endpoint,
+		Headers:       defs.Log.OTLP.Headers,
+		BatchSize:     defs.Log.OTLP.BatchSize,
+		FlushInterval: defs.Log.OTLP.FlushInterval,
+		Timeout:       defs.Log.OTLP.Timeout,
+	}
+}
+
+func applyRuntimeLimits(defs *definition.Definitions, envs *env.ServiceEnvs, serviceLogger *mlogger.Logger) {
+	if defs.Limits.Disabled || envs.DeploymentEnv() == definition.DeploymentEnvTest {
+		return
+	}
+
+	result := limits.Apply(limits.Options{
+		MaxProcs:         defs.Limits.MaxProcs,
+		MemoryLimit:      defs.Limits.MemoryLimit,
+		MemoryLimitRatio: defs.Limits.MemoryLimitRatio,
+	})
+
+	serviceLogger.Info(context.Background(), "runtime limits",
+		logger.Any("runtime.gomaxprocs", result.MaxProcs),
+		logger.String("runtime.gomaxprocs_source", result.MaxProcsSource),
+		logger.Any("runtime.gomemlimit", result.MemoryLimit),
+		logger.String("runtime.gomemlimit_source", result.MemoryLimitSource),
+	)
+}
+
 func initServiceErrors(defs *definition.Definitions) errors_api.Errors {
 	return merrors.NewBuilder(merrors.BuilderOptions{
 		ServiceName: defs.ServiceName().String(),
@@ -179,6 +272,13 @@ func (s *Service) WithExternalFeatures(features *plugin.FeatureSet) *Service {
 	return s
 }
 
+// WithNamingPolicy adds a naming policy that the service definitions must
+// follow, failing the service startup otherwise.
+func (s *Service) WithNamingPolicy(policy definition.NamingPolicy) *Service {
+	s.definitions.AddNamingPolicy(policy)
+	return s
+}
+
 // WithExternalIntegrations allows a service to add external Integrations into it.
 func (s *Service) WithExternalIntegrations(integrations *plugin.IntegrationSet) *Service {
 	s.registeredIntegrations.Append(integrations)
@@ -190,8 +290,23 @@ func (s *Service) WithExternalIntegrations(integrations *plugin.IntegrationSet)
 //
 // We don't return an error here so that the service does not need to handle it
 // inside its code. We abort in case of an error.
+//
+// Tests that need the service servers running must use StartForTest instead.
 func (s *Service) Start(srv interface{}) {
 	ctx := context.Background()
+	defer s.reportPanic(ctx)
+
+	if definitionsSchemaEnabled() {
+		s.printDefinitionsSchema(ctx, srv)
+	}
+
+	if validateEnabled() {
+		s.runValidate(srv)
+	}
+
+	if selfTestEnabled() {
+		s.runSelfTest(ctx, srv, s.bootstrap(ctx, srv))
+	}
 
 	if err := s.bootstrap(ctx, srv); err != nil {
 		s.fatalAbort(ctx, "could not bootstrap service", err)
@@ -208,19 +323,24 @@ func (s *Service) Start(srv interface{}) {
 
 func (s *Service) bootstrap(ctx context.Context, srv interface{}) error {
 	s.logger.Info(ctx, "starting service")
+	s.srv = srv
 
 	if err := s.postProcessDefinitions(srv); err != nil {
-		return fmt.Errorf("service definitions error: %w", err)
+		return abort.Wrap(abort.ReasonConfig, fmt.Errorf("service definitions error: %w", err))
 	}
 
 	if err := s.startFeatures(ctx, srv); err != nil {
-		return err
+		return abort.Wrap(abort.ReasonDependencyUnavailable, err)
 	}
 
 	if err := s.startIntegrations(ctx, srv); err != nil {
-		return err
+		return abort.Wrap(abort.ReasonDependencyUnavailable, err)
 	}
 
+	// Linted after the integrations start, so their settings are checked
+	// as well.
+	s.lintDefinitions(ctx)
+
 	if err := s.initializeServiceInternals(ctx, srv); err != nil {
 		return err
 	}
@@ -233,6 +353,22 @@ func (s *Service) bootstrap(ctx context.Context, srv interface{}) error {
 // the service. Also, here is where we initialize the service structure member
 // tagged as "definitions".
 func (s *Service) postProcessDefinitions(srv interface{}) error {
+	if err := s.loadExternalDefinitions(); err != nil {
+		return err
+	}
+
+	// Load custom service definitions
+	if err := s.definitions.LoadCustomServiceDefinitions(srv); err != nil {
+		return err
+	}
+
+	// Ensure that everything is right
+	return s.definitions.Validate()
+}
+
+// loadExternalDefinitions loads the definitions of the features and runtimes
+// that have their own settings.
+func (s *Service) loadExternalDefinitions() error {
 	// Load all feature definitions.
 	iter := s.registeredFeatures.Iterator()
 	for p, next := iter.Next(); next; p, next = iter.Next() {
@@ -258,13 +394,47 @@ func (s *Service) postProcessDefinitions(srv interface{}) error {
 		}
 	}
 
-	// Load custom service definitions
-	if err := s.definitions.LoadCustomServiceDefinitions(srv); err != nil {
-		return err
+	return nil
+}
+
+// lintDefinitions logs operational best-practice warnings found in the
+// service definitions.
+func (s *Service) lintDefinitions(ctx context.Context) {
+	for _, w := range s.lintWarnings(s.corsSettings()) {
+		s.logger.Warn(ctx, "service definitions warning",
+			logger.String("lint.rule", w.Rule),
+			logger.String("lint.message", w.Message),
+		)
 	}
+}
 
-	// Ensure that everything is right
-	return s.definitions.Validate()
+// lintWarnings lints the service definitions for its deployment environment
+// and the CORS settings, when the service has them.
+func (s *Service) lintWarnings(cors *definition.CORSSettings) []definition.LintWarning {
+	return definition.Lint(s.definitions, definition.LintOptions{
+		Env:  s.envs.DeploymentEnv(),
+		CORS: cors,
+	})
+}
+
+// corsSettings returns the CORS settings of the service CORS integration, if
+// it has one.
+func (s *Service) corsSettings() *definition.CORSSettings {
+	i, err := s.registeredIntegrations.Integration(options.HTTPCorsIntegrationName)
+	if err != nil {
+		return nil
+	}
+
+	c, ok := i.API().(integrations_api.CorsHandler)
+	if !ok {
+		return nil
+	}
+
+	cfg := c.Cors()
+	return &definition.CORSSettings{
+		AllowedOrigins:   cfg.AllowedOrigins,
+		AllowCredentials: cfg.AllowCredentials,
+	}
 }
 
 // startFeatures starts all registered Features and everything that are related
@@ -301,6 +471,11 @@ func (s *Service) initializeFeatures(ctx context.Context, srv interface{}) error
 		return err
 	}
 
+	s.health = health.NewMonitor(s.registeredFeatures, s.logger, health.Options{
+		Timeout:     s.definitions.Health.Timeout,
+		NonCritical: s.definitions.Health.NonCritical,
+	})
+
 	// Load tagged Features into the service struct
 	return s.loadTaggedFeatures(ctx, srv)
 }
@@ -385,7 +560,9 @@ func (s *Service) startTracker() error {
 		return errors.New("tracker integration exists but does not implement Tracker")
 	}
 
-	s.tracker = t
+	// Lets tests replace the tracker IDs by deterministic ones.
+	s.tracker = ids.Tracker(t)
+	s.logger.SetRequestIDExtractor(s.tracker.Retrieve)
 	return nil
 }
 
@@ -415,7 +592,7 @@ func (s *Service) initializeServiceInternals(ctx context.Context, srv interface{
 
 	// Establishes connection with all gRPC clients.
 	if err := s.coupleClients(srv); err != nil {
-		return fmt.Errorf("could not establish connection with clients: %w", err)
+		return abort.Wrap(abort.ReasonDependencyUnavailable, fmt.Errorf("could not establish connection with clients: %w", err))
 	}
 
 	// Call lifecycle.OnStart before validating the service structure to
@@ -431,7 +608,7 @@ func (s *Service) initializeServiceInternals(ctx context.Context, srv interface{
 
 	if s.envs.DeploymentEnv() != definition.DeploymentEnvTest {
 		if err := validations.EnsureValuesAreInitialized(srv); err != nil {
-			return fmt.Errorf("service server object is not properly initialized: %w", err)
+			return abort.Wrap(abort.ReasonConfig, fmt.Errorf("service server object is not properly initialized: %w", err))
 		}
 	}
 
@@ -445,6 +622,10 @@ func (s *Service) initializeServiceInternals(ctx context.Context, srv interface{
 }
 
 func (s *Service) initializeRegisteredRuntimes(ctx context.Context, srv interface{}) error {
+	if err := s.checkRuntimePorts(); err != nil {
+		return err
+	}
+
 	// Creates the service
 	for runtimeType, port := range s.definitions.RuntimeTypes() {
 		runtime, ok := s.registeredRuntimes.Runtimes()[runtimeType.String()]
@@ -459,6 +640,9 @@ func (s *Service) initializeRegisteredRuntimes(ctx context.Context, srv interfac
 
 		if err := runtime.Initialize(ctx, &plugin.RuntimeOptions{
 			Port:           s.getRuntimePort(port, runtimeType.String()),
+			Host:           s.listen.RuntimeHost(runtimeType),
+			Network:        s.listen.Network,
+			ProxyProtocol:  s.listen.ProxyProtocol,
 			Type:           runtimeType,
 			Name:           s.definitions.ServiceName(),
 			Product:        s.definitions.Product,
@@ -472,6 +656,7 @@ func (s *Service) initializeRegisteredRuntimes(ctx context.Context, srv interfac
 			Integrations:   s.registeredIntegrations,
 			ServiceHandler: srv,
 			Env:            s.envs,
+			Health:         s.runtimeHealth(),
 		}); err != nil {
 			return err
 		}
@@ -483,7 +668,66 @@ func (s *Service) initializeRegisteredRuntimes(ctx context.Context, srv interfac
 	return nil
 }
 
+// checkRuntimePorts validates the ports of all runtimes before any of them
+// is initialized, so conflicts and permission problems are reported with
+// actionable errors instead of failing inside a runtime.
+func (s *Service) checkRuntimePorts() error {
+	if s.ephemeralPorts {
+		return nil
+	}
+
+	var runtimePorts []ports.Port
+	for runtimeType, port := range s.definitions.RuntimeTypes() {
+		runtimePorts = append(runtimePorts, ports.Port{
+			Runtime: runtimeType.String(),
+			Port:    s.getRuntimePort(port, runtimeType.String()).Int32(),
+			Host:    s.listen.RuntimeHost(runtimeType),
+			Network: s.listen.Network,
+		})
+	}
+	sort.Slice(runtimePorts, func(i, j int) bool {
+		return runtimePorts[i].Runtime < runtimePorts[j].Runtime
+	})
+
+	return ports.Check(runtimePorts, ports.Options{
+		SkipAvailability: handoff.Inherited(),
+	})
+}
+
+// listenSettings merges the listen settings of the service options with the
+// ones of its definitions, which take precedence.
+func listenSettings(opt *definition.Listen, defs definition.Listen) definition.Listen {
+	var out definition.Listen
+	if opt != nil {
+		out = *opt
+	}
+	if defs.Host != "" {
+		out.Host = defs.Host
+	}
+	if defs.Network != "" {
+		out.Network = defs.Network
+	}
+	if len(defs.Hosts) > 0 {
+		hosts := maps.Clone(out.Hosts)
+		if hosts == nil {
+			hosts = make(map[string]string)
+		}
+		maps.Copy(hosts, defs.Hosts)
+		out.Hosts = hosts
+	}
+	if defs.ProxyProtocol.Enabled {
+		out.ProxyProtocol = defs.ProxyProtocol
+	}
+
+	return out
+}
+
 func (s *Service) getRuntimePort(port service.ServerPort, runtimeType string) service.ServerPort {
+	// Lets the system choose free ports for services started by tests.
+	if s.ephemeralPorts {
+		return 0
+	}
+
 	// Use default port values in case no port was set in the service.toml
 	if port == 0 {
 		if runtimeType == definition.RuntimeTypeGRPC.String() {
@@ -559,7 +803,23 @@ func (s *Service) coupleClients(srv interface{}) error {
 		return nil
 	}
 
+	conns, err := s.couple(srv, func(client *options.GrpcClient) (*mgrpc.ClientConnectionOptions, error) {
+		return s.createGrpcCoupledClientOptions(client), nil
+	})
+	s.grpcConns = append(s.grpcConns, conns...)
+
+	return err
+}
+
+// couple initializes every client member of the service structure with a
+// connection created with the options returned by connOptions. Clients for
+// which it returns nil options are skipped.
+func (s *Service) couple(
+	srv interface{},
+	connOptions func(client *options.GrpcClient) (*mgrpc.ClientConnectionOptions, error),
+) ([]*grpc.ClientConn, error) {
 	var (
+		conns   []*grpc.ClientConn
 		typeOf  = reflect.TypeOf(srv)
 		valueOf = reflect.ValueOf(srv)
 	)
@@ -573,18 +833,25 @@ func (s *Service) coupleClients(srv interface{}) error {
 
 		client, ok := s.clients[tag.GrpcClientName]
 		if !ok {
-			return fmt.Errorf("could not find gRPC client '%s' inside service options", tag.GrpcClientName)
+			return conns, fmt.Errorf("could not find gRPC client '%s' inside service options", tag.GrpcClientName)
 		}
 		if err := client.Validate(); err != nil {
-			return err
+			return conns, err
+		}
+
+		cOpts, err := connOptions(client)
+		if err != nil {
+			return conns, err
+		}
+		if cOpts == nil {
+			continue
 		}
 
-		cOpts := s.createGrpcCoupledClientOptions(client)
 		conn, err := mgrpc.ClientConnection(cOpts)
 		if err != nil {
-			return err
+			return conns, err
 		}
-		s.grpcConns = append(s.grpcConns, conn)
+		conns = append(conns, conn)
 
 		call := reflect.ValueOf(client.NewClientFunction)
 		out := call.Call([]reflect.Value{reflect.ValueOf(conn)})
@@ -594,7 +861,7 @@ func (s *Service) coupleClients(srv interface{}) error {
 		valueOf.Elem().Field(i).Set(ptr.Elem())
 	}
 
-	return nil
+	return conns, nil
 }
 
 func (s *Service) createGrpcCoupledClientOptions(client *options.GrpcClient) *mgrpc.ClientConnectionOptions {
@@ -611,6 +878,13 @@ func (s *Service) createGrpcCoupledClientOptions(client *options.GrpcClient) *mg
 			Port:      s.envs.CoupledPort(),
 		},
 		Tracker: s.tracker,
+		SlowCalls: downstream.NewSlowCallLogger(downstream.SlowCallOptions{
+			Threshold: s.definitions.Log.SlowCallThreshold,
+			Logger:    s.logger,
+			Tracker:   s.tracker,
+		}),
+		Logger:      s.logger,
+		Credentials: s.coupledClientCredentials(client),
 	}
 
 	if s.definitions.Clients != nil {
@@ -625,6 +899,27 @@ func (s *Service) createGrpcCoupledClientOptions(client *options.GrpcClient) *mg
 	return opts
 }
 
+// coupledClientCredentials returns the credentials attached to the calls of
+// a coupled client by the oauth2 feature, if any.
+func (s *Service) coupledClientCredentials(client *options.GrpcClient) credentials.PerRPCCredentials {
+	f, err := s.registeredFeatures.Feature(options.OAuth2FeatureName)
+	if err != nil {
+		return nil
+	}
+
+	c, ok := f.(*oauth2.Client)
+	if !ok {
+		return nil
+	}
+
+	creds, ok := c.PerRPCCredentials(client.ServiceName.String())
+	if !ok {
+		return nil
+	}
+
+	return creds
+}
+
 func (s *Service) printServiceResources(ctx context.Context) {
 	var (
 		fields []logger_api.Attribute
@@ -638,6 +933,57 @@ func (s *Service) printServiceResources(ctx context.Context) {
 	s.logger.Info(ctx, "service resources", fields...)
 }
 
+// logFeatureUsage logs which enabled features were requested by the service
+// and which were not. Since features are only requested by handlers, it is
+// called after the service has been running, periodically or when it stops.
+func (s *Service) logFeatureUsage(ctx context.Context) {
+	var used, unused []string
+	for _, u := range s.registeredFeatures.Usage() {
+		if !u.Enabled {
+			continue
+		}
+
+		if u.Calls > 0 {
+			used = append(used, u.Name)
+			continue
+		}
+
+		unused = append(unused, u.Name)
+	}
+
+	s.logger.Info(ctx, "service features usage",
+		logger.String("features.used", strings.Join(used, ",")),
+		logger.String("features.unused", strings.Join(unused, ",")),
+	)
+
+	if len(unused) > 0 {
+		s.logger.Warn(ctx, "service has enabled features that are not being used",
+			logger.String("features.unused", strings.Join(unused, ",")),
+		)
+	}
+}
+
+// reportFeatureUsage periodically logs the features usage, if enabled by the
+// service definitions.
+func (s *Service) reportFeatureUsage(ctx context.Context) {
+	interval := s.definitions.Log.FeatureUsageInterval
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.logFeatureUsage(ctx)
+		}
+	}
+}
+
 func (s *Service) run(ctx context.Context, srv interface{}) {
 	defer s.stopService(ctx)
 	defer lifecycle.OnFinish(ctx, srv, &lifecycle.Options{
@@ -653,7 +999,7 @@ func (s *Service) run(ctx context.Context, srv interface{}) {
 		s.logger.Info(ctx, "runtime is running", attrs...)
 
 		if err := svc.Run(ctx, srv); err != nil {
-			s.fatalAbort(ctx, "could not execute runtime", err)
+			s.fatalAbort(ctx, "could not execute runtime", abort.Wrap(abort.ReasonRuntime, err))
 		}
 
 		return
@@ -661,14 +1007,27 @@ func (s *Service) run(ctx context.Context, srv interface{}) {
 
 	// Otherwise, initialize all runtime types and put them to run.
 
+	// Periodically reports features usage while running.
+	reportCtx, cancelReport := context.WithCancel(ctx)
+	defer cancelReport()
+	go s.reportFeatureUsage(reportCtx)
+	go s.monitorHealth(reportCtx)
+
 	// Create channels for finishing the service and bind the signal that
 	// finishes it.
 	errChan := make(chan error)
 	stopChan := make(chan os.Signal, 1)
 	signal.Notify(stopChan, syscall.SIGTERM, syscall.SIGINT)
 
+	upgradeChan := make(chan os.Signal, 1)
+	if signals := handoff.Signals(); len(signals) > 0 {
+		signal.Notify(upgradeChan, signals...)
+	}
+
 	for _, svc := range s.runtimes {
 		go func(service plugin.Runtime) {
+			defer s.reportPanic(ctx)
+
 			attrs := append(svc.Info(), logger.String("runtime.mode", svc.Name()))
 			s.logger.Info(ctx, "runtime is running", attrs...)
 			if err := service.Run(ctx, srv); err != nil {
@@ -677,17 +1036,50 @@ func (s *Service) run(ctx context.Context, srv interface{}) {
 		}(svc)
 	}
 
+	// If we were started by an upgrade, lets the old process know that it
+	// can stop.
+	if err := handoff.Ready(); err != nil {
+		s.logger.Error(ctx, "could not notify upgrade readiness", logger.Error(err))
+	}
+
 	// Blocks the call
-	select {
-	case err := <-errChan:
-		s.fatalAbort(ctx, "could not execute runtime", err)
+	for {
+		select {
+		case err := <-errChan:
+			s.fatalAbort(ctx, "could not execute runtime", abort.Wrap(abort.ReasonRuntime, err))
 
-	case <-stopChan:
+		case <-stopChan:
+			return
+
+		case <-upgradeChan:
+			if s.upgrade(ctx) {
+				return
+			}
+		}
+	}
+}
+
+// upgrade hands the service listeners off to a new process and reports if
+// the current one can stop.
+func (s *Service) upgrade(ctx context.Context) bool {
+	s.logger.Info(ctx, "upgrading service")
+
+	upgradeCtx, cancel := context.WithTimeout(ctx, upgradeTimeout)
+	defer cancel()
+
+	process, err := handoff.Upgrade(upgradeCtx)
+	if err != nil {
+		s.logger.Error(ctx, "could not upgrade service", logger.Error(err))
+		return false
 	}
+
+	s.logger.Info(ctx, "service upgraded, draining old process", logger.Any("upgrade.pid", process.Pid))
+	return true
 }
 
 func (s *Service) stopService(ctx context.Context) {
 	s.logger.Info(ctx, "stopping service")
+	s.logFeatureUsage(ctx)
 
 	for _, conn := range s.grpcConns {
 		if err := conn.Close(); err != nil {
@@ -707,6 +1099,10 @@ func (s *Service) stopService(ctx context.Context) {
 	}
 
 	s.logger.Info(ctx, "service stopped")
+
+	if err := s.logger.Shutdown(ctx); err != nil {
+		log.Printf("could not export service logs: %v", err)
+	}
 }
 
 // stopDependencies cleans up features and integrations that the service is using.
@@ -742,23 +1138,30 @@ func (s *Service) Errors() errors_api.Errors {
 	return s.errors
 }
 
-// Abort is a helper method to abort the service.
+// Abort is a helper method to abort the service. The process exit code is
+// chosen by the abort reason of err, which can be set with abort.Wrap, e.g.
+// abort.Wrap(abort.ReasonConfig, err).
 func (s *Service) Abort(message string, err error) {
 	s.fatalAbort(context.TODO(), message, err)
 }
 
-// abort is an internal helper method to finish the service execution with an
-// error message.
+// fatalAbort is an internal helper method to finish the service execution
+// with an error message and the exit code of the err abort reason.
 func (s *Service) fatalAbort(ctx context.Context, reason string, err error) {
+	abortReason := abort.ReasonOf(err)
 	attrs := []logger_api.Attribute{
 		logger.String("reason", reason),
+		logger.String("abort.reason", abortReason.String()),
+		logger.Int32("abort.exit_code", int32(abortReason.ExitCode())),
 	}
 
 	if err != nil {
 		attrs = append(attrs, logger.Error(err))
 	}
 
-	s.logger.Fatal(ctx, "aborting service execution", attrs...)
+	s.flushRecentLogs(ctx)
+	s.writeCrashReport(ctx, abortReason.String(), abortReason.ExitCode(), err, nil)
+	s.logger.FatalWithExitCode(ctx, abortReason.ExitCode(), "aborting service execution", attrs...)
 }
 
 // ServiceName gives back the service name.
@@ -827,6 +1230,7 @@ func (s *Service) Feature(_ context.Context, target interface{}) error {
 
 		if im := featureType.Implements(api); im {
 			reflect.ValueOf(target).Elem().Set(f)
+			s.registeredFeatures.TrackUsage(feature.Name())
 			return nil
 		}
 	}
@@ -851,7 +1255,8 @@ func (s *Service) Env(name string) string {
 }
 
 // SetupTest is an api that should start the testing environment for a unit
-// test.
+// test. When the test options have CoupledServers, the service must already
+// be started, e.g. with StartForTest, so its gRPC clients can be coupled.
 func (s *Service) SetupTest(ctx context.Context, t *testing.Testing) *ServiceTesting {
 	return setupServiceTesting(ctx, s, t)
 }
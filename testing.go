@@ -2,8 +2,16 @@ package mikros
 
 import (
 	"context"
+	"errors"
+	"time"
 
+	"google.golang.org/grpc"
+
+	"github.com/mikros-dev/mikros/components/clock"
 	"github.com/mikros-dev/mikros/components/definition"
+	mgrpc "github.com/mikros-dev/mikros/components/grpc"
+	"github.com/mikros-dev/mikros/components/ids"
+	"github.com/mikros-dev/mikros/components/options"
 	"github.com/mikros-dev/mikros/components/plugin"
 	"github.com/mikros-dev/mikros/components/testing"
 )
@@ -13,8 +21,16 @@ import (
 // It should be used when creating unit tests that need to use registeredFeatures,
 // internal or external, and require some kind of setup/teardown mechanism.
 type ServiceTesting struct {
-	svc  *Service
-	test *testing.Testing
+	svc        *Service
+	test       *testing.Testing
+	servers    []*mgrpc.InMemoryServer
+	conns      []*grpc.ClientConn
+	recorder   *testing.Recorder
+	ids        *ids.Sequential
+	resetIDs   func()
+	clock      *clock.Fake
+	resetClock func()
+	fixtures   func(ctx context.Context) error
 }
 
 func setupServiceTesting(ctx context.Context, svc *Service, t *testing.Testing) *ServiceTesting {
@@ -23,10 +39,25 @@ func setupServiceTesting(ctx context.Context, svc *Service, t *testing.Testing)
 	}
 
 	svcTest := &ServiceTesting{
-		svc:  svc,
-		test: t,
+		svc:      svc,
+		test:     t,
+		recorder: testing.NewRecorder(),
+		ids:      ids.NewSequential("test"),
 	}
 
+	// External resources must be available before features are set up.
+	if opts := t.Options(); opts != nil && len(opts.Fixtures) > 0 {
+		stop, err := testing.StartFixtures(ctx, opts.Fixtures)
+		if err != nil {
+			t.T().Fatalf("could not start test fixtures: %v", err)
+		}
+		svcTest.fixtures = stop
+	}
+
+	// Framework generated IDs, such as tracker IDs, become deterministic
+	// while testing.
+	svcTest.resetIDs = ids.Set(svcTest.ids)
+
 	// Sets up every plugin that needs.
 	iter := svc.registeredFeatures.Iterator()
 	for p, next := iter.Next(); next; p, next = iter.Next() {
@@ -35,9 +66,53 @@ func setupServiceTesting(ctx context.Context, svc *Service, t *testing.Testing)
 		}
 	}
 
+	if err := svcTest.coupleInMemoryClients(); err != nil {
+		// Fatalf does not return, so everything already started, fixtures
+		// included, must be released here.
+		svcTest.Teardown(ctx)
+		t.T().Fatalf("could not couple in-memory gRPC clients: %v", err)
+	}
+
 	return svcTest
 }
 
+// coupleInMemoryClients connects the service gRPC clients to the in-process
+// implementations given by the test options. Clients without one are kept
+// uninitialized. The service structure is only known after the service is
+// bootstrapped, so it must have been started before.
+func (s *ServiceTesting) coupleInMemoryClients() error {
+	opts := s.test.Options()
+	if opts == nil || len(opts.CoupledServers) == 0 {
+		return nil
+	}
+	if s.svc.srv == nil {
+		return errors.New("coupled servers require the service to be started, e.g. with StartForTest, before SetupTest")
+	}
+
+	conns, err := s.svc.couple(s.svc.srv, func(client *options.GrpcClient) (*mgrpc.ClientConnectionOptions, error) {
+		coupled, ok := opts.CoupledServers[client.ServiceName.String()]
+		if !ok {
+			return nil, nil
+		}
+
+		server, err := mgrpc.NewInMemoryServer(coupled.Description, coupled.Server)
+		if err != nil {
+			return nil, err
+		}
+		s.servers = append(s.servers, server)
+
+		cOpts := s.svc.createGrpcCoupledClientOptions(client)
+		cOpts.AlternativeConnection = nil
+		cOpts.Dialer = server.Dialer()
+		cOpts.Interceptors = append(cOpts.Interceptors, s.recorder.UnaryClientInterceptor(client.ServiceName.String()))
+
+		return cOpts, nil
+	})
+	s.conns = append(s.conns, conns...)
+
+	return err
+}
+
 // Teardown releases every resource allocated by the SetupTest call.
 func (s *ServiceTesting) Teardown(ctx context.Context) {
 	iter := s.svc.registeredFeatures.Iterator()
@@ -46,6 +121,65 @@ func (s *ServiceTesting) Teardown(ctx context.Context) {
 			featureTester.Teardown(ctx, s.test)
 		}
 	}
+
+	for _, conn := range s.conns {
+		_ = conn.Close()
+	}
+	for _, server := range s.servers {
+		server.Stop()
+	}
+
+	s.resetIDs()
+	if s.resetClock != nil {
+		s.resetClock()
+	}
+
+	if s.fixtures != nil {
+		if err := s.fixtures(ctx); err != nil {
+			s.test.T().Errorf("could not stop test fixtures: %v", err)
+		}
+	}
+}
+
+// Clock replaces, on its first call, the clock used by the framework timers,
+// and by the clock feature, with a fake one whose time only moves with its
+// Advance and SetNow methods. It starts at the current time. Timers created
+// before the first call keep using the real time.
+func (s *ServiceTesting) Clock() *clock.Fake {
+	if s.clock == nil {
+		s.clock = clock.NewFake(time.Now())
+		s.resetClock = clock.Set(s.clock)
+	}
+
+	return s.clock
+}
+
+// IDs gives access to the deterministic generator of the IDs created by the
+// framework while testing, e.g. to restart its sequence between subtests.
+func (s *ServiceTesting) IDs() *ids.Sequential {
+	return s.ids
+}
+
+// StartRecording starts capturing the outgoing calls made by the service,
+// discarding the ones previously captured. gRPC calls are captured for the
+// clients coupled to Options.CoupledServers and HTTP calls for the clients
+// using the recorder Transport.
+func (s *ServiceTesting) StartRecording() *testing.Recorder {
+	s.recorder.Reset()
+	s.recorder.Start()
+
+	return s.recorder
+}
+
+// StopRecording stops capturing the outgoing calls made by the service.
+// Captured calls are still available through the Recorder.
+func (s *ServiceTesting) StopRecording() {
+	s.recorder.Stop()
+}
+
+// Recorder gives access to the outgoing calls captured while recording.
+func (s *ServiceTesting) Recorder() *testing.Recorder {
+	return s.recorder
 }
 
 // Do is a function that executes tests from inside all registered registeredFeatures.
@@ -0,0 +1,117 @@
+package mikros
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/mikros-dev/mikros/components/definition"
+	"github.com/mikros-dev/mikros/components/options"
+	"github.com/mikros-dev/mikros/components/service"
+	mtesting "github.com/mikros-dev/mikros/components/testing"
+)
+
+type coupledTestService struct {
+	Users healthpb.HealthClient `mikros:"grpc_client=users"`
+}
+
+func (s *coupledTestService) HTTPHandler(_ context.Context) (http.Handler, error) {
+	return http.NewServeMux(), nil
+}
+
+func newCoupledTestService(t *testing.T) *Service {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "service.toml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+name = "orders"
+types = ["http"]
+version = "v0.1.0"
+language = "go"
+product = "TEST"
+
+[runtime.http]
+disable_auth = true
+`), 0o600))
+
+	defs, err := definition.ParseFromFile(path)
+	require.NoError(t, err)
+
+	svc, err := newService(&options.NewServiceOptions{
+		Service: map[string]options.ServiceOptions{
+			"http": &options.HTTPServiceOptions{},
+		},
+		GrpcClients: map[string]*options.GrpcClient{
+			"users": {
+				ServiceName:       service.FromString("users"),
+				NewClientFunction: healthpb.NewHealthClient,
+			},
+		},
+	}, defs)
+	require.NoError(t, err)
+
+	return svc
+}
+
+func TestSetupTestCoupledServers(t *testing.T) {
+	t.Run("should couple clients to in-memory servers and record their calls", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		svc := newCoupledTestService(t)
+		srv := &coupledTestService{}
+		running, err := svc.StartForTest(ctx, srv)
+		require.NoError(t, err)
+		defer running.Stop(ctx)
+
+		users := health.NewServer()
+		users.SetServingStatus("users", healthpb.HealthCheckResponse_SERVING)
+
+		svcTest := svc.SetupTest(ctx, mtesting.New(t, &mtesting.Options{
+			CoupledServers: map[string]*mtesting.CoupledServer{
+				"users": {
+					Description: &healthpb.Health_ServiceDesc,
+					Server:      users,
+				},
+			},
+		}))
+		defer svcTest.Teardown(ctx)
+		require.NotNil(t, srv.Users)
+
+		recorder := svcTest.StartRecording()
+		res, err := srv.Users.Check(ctx, &healthpb.HealthCheckRequest{Service: "users"})
+		require.NoError(t, err)
+		assert.Equal(t, healthpb.HealthCheckResponse_SERVING, res.GetStatus())
+
+		calls := recorder.CallsTo("/grpc.health.v1.Health/Check")
+		require.Len(t, calls, 1)
+		assert.Equal(t, "users", calls[0].Service)
+		assert.NoError(t, calls[0].Err)
+	})
+
+	t.Run("should fail when the service was not started", func(t *testing.T) {
+		svc := newCoupledTestService(t)
+		svcTest := &ServiceTesting{
+			svc: svc,
+			test: mtesting.New(t, &mtesting.Options{
+				CoupledServers: map[string]*mtesting.CoupledServer{
+					"users": {
+						Description: &healthpb.Health_ServiceDesc,
+						Server:      health.NewServer(),
+					},
+				},
+			}),
+			recorder: mtesting.NewRecorder(),
+		}
+
+		assert.ErrorContains(t, svcTest.coupleInMemoryClients(), "require the service to be started")
+	})
+}
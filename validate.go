@@ -0,0 +1,46 @@
+package mikros
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+var (
+	validateFlag = flag.Bool("validate", false, "Validates the service definitions, prints their warnings and exits.")
+)
+
+// validateEnabled returns if the service was executed only to validate its
+// definitions.
+func validateEnabled() bool {
+	return flag.Parsed() && *validateFlag
+}
+
+// runValidate validates and lints the service definitions, without starting
+// anything, writing the result to the standard output, and finishes the
+// process. It exits with a non-zero code if the definitions are invalid.
+func (s *Service) runValidate(srv interface{}) {
+	if err := s.validateDefinitions(os.Stdout, srv); err != nil {
+		os.Exit(1)
+	}
+
+	os.Exit(0)
+}
+
+// validateDefinitions validates the service definitions and writes the
+// lint warnings found in them to w. The settings of integrations are not
+// checked, since they are not initialized.
+func (s *Service) validateDefinitions(w io.Writer, srv interface{}) error {
+	if err := s.postProcessDefinitions(srv); err != nil {
+		_, _ = fmt.Fprintf(w, "error: %v\n", err)
+		return err
+	}
+
+	for _, warning := range s.lintWarnings(nil) {
+		_, _ = fmt.Fprintf(w, "warning: %s\n", warning)
+	}
+
+	_, _ = fmt.Fprintln(w, "service definitions are valid")
+	return nil
+}
@@ -0,0 +1,78 @@
+package mikros
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/mikros-dev/mikros/components/definition"
+	"github.com/mikros-dev/mikros/components/plugin"
+	"github.com/mikros-dev/mikros/internal/components/env"
+)
+
+func newValidateService(t *testing.T, toml string) *Service {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "service.toml")
+	require.NoError(t, os.WriteFile(path, []byte(toml), 0o600))
+
+	defs, err := definition.ParseFromFile(path)
+	require.NoError(t, err)
+
+	envs, err := env.NewServiceEnvs(defs)
+	require.NoError(t, err)
+
+	return &Service{
+		definitions:            defs,
+		envs:                   envs,
+		registeredFeatures:     plugin.NewFeatureSet(),
+		registeredRuntimes:     plugin.NewRuntimeSet(),
+		registeredIntegrations: plugin.NewIntegrationSet(),
+	}
+}
+
+func TestValidateDefinitions(t *testing.T) {
+	t.Run("should write the lint warnings of valid definitions", func(t *testing.T) {
+		var (
+			out bytes.Buffer
+			s   = newValidateService(t, `
+name = "example"
+types = ["http"]
+version = "v1.0.0"
+language = "go"
+product = "SDS"
+
+[runtime.http]
+read_timeout = "-1s"
+`)
+		)
+
+		require.NoError(t, s.validateDefinitions(&out, &struct{}{}))
+		assert.Contains(t, out.String(), "warning: http-timeouts: runtime.http.read_timeout is not positive")
+		assert.Contains(t, out.String(), "service definitions are valid")
+	})
+
+	t.Run("should fail with invalid definitions", func(t *testing.T) {
+		var (
+			out bytes.Buffer
+			s   = newValidateService(t, `
+name = "example"
+types = ["http"]
+version = "v1.0.0"
+language = "go"
+product = "SDS"
+
+[listen.proxy_protocol]
+enabled = true
+`)
+		)
+
+		assert.Error(t, s.validateDefinitions(&out, &struct{}{}))
+		assert.Contains(t, out.String(), "error: ")
+		assert.NotContains(t, out.String(), "are valid")
+	})
+}